@@ -3,14 +3,33 @@ package main_test // Or a package name like 'adoptionservicetest'
 import (
 	"context"
 	"errors"
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
 	// Adjust these import paths to match your project's module path and structure
+	"github.com/zhandarbeks/petstore-final-project/adoption-service/internal/client"
+	"github.com/zhandarbeks/petstore-final-project/adoption-service/internal/config"
 	"github.com/zhandarbeks/petstore-final-project/adoption-service/internal/domain"
+	"github.com/zhandarbeks/petstore-final-project/adoption-service/internal/handler"
 	"github.com/zhandarbeks/petstore-final-project/adoption-service/internal/publisher" // For mock publisher
 	"github.com/zhandarbeks/petstore-final-project/adoption-service/internal/repository" // For mock repository
+	"github.com/zhandarbeks/petstore-final-project/adoption-service/internal/selftest"
+	"github.com/zhandarbeks/petstore-final-project/adoption-service/internal/server"
 	"github.com/zhandarbeks/petstore-final-project/adoption-service/internal/usecase"
+	pb "github.com/zhandarbeks/petstore-final-project/genprotos/adoption"
+	pbPet "github.com/zhandarbeks/petstore-final-project/genprotos/pet"
+	"github.com/zhandarbeks/petstore-final-project/internal/features"
+	"github.com/zhandarbeks/petstore-final-project/internal/settings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	// Optional: for assertions, e.g., "github.com/stretchr/testify/assert"
 	// Optional: for mocking, e.g., "github.com/stretchr/testify/mock"
@@ -22,8 +41,18 @@ import (
 type MockAdoptionRepository struct {
 	CreateAdoptionApplicationFunc       func(ctx context.Context, app *domain.AdoptionApplication) (*domain.AdoptionApplication, error)
 	GetAdoptionApplicationByIDFunc      func(ctx context.Context, id string) (*domain.AdoptionApplication, error)
-	UpdateAdoptionApplicationStatusFunc func(ctx context.Context, id string, newStatus domain.ApplicationStatus, reviewNotes string) (*domain.AdoptionApplication, error)
+	UpdateAdoptionApplicationStatusFunc func(ctx context.Context, id string, newStatus domain.ApplicationStatus, reviewNotes *string) (*domain.AdoptionApplication, error)
 	ListAdoptionApplicationsByUserIDFunc func(ctx context.Context, userID string, page, limit int, statusFilter *domain.ApplicationStatus) ([]*domain.AdoptionApplication, int64, error)
+	CountOtherPetsAppliedForSinceFunc    func(ctx context.Context, userID, excludePetID string, since time.Time) (int, error)
+	GetLatestApplicationForPetFunc       func(ctx context.Context, userID, petID string) (*domain.AdoptionApplication, error)
+	GetPetApplicationStatsFunc           func(ctx context.Context, petID string) (*domain.PetApplicationStats, error)
+	CountApplicationsByPetIDsFunc        func(ctx context.Context, petIDs []string) (map[string]int64, error)
+	ReopenAdoptionApplicationFunc        func(ctx context.Context, id string, change domain.ApplicationStatusChange) (*domain.AdoptionApplication, error)
+	AddApplicationAttachmentFunc         func(ctx context.Context, id string, attachment domain.Attachment, maxAttachments int) (*domain.AdoptionApplication, error)
+	RemoveApplicationAttachmentFunc      func(ctx context.Context, id, url string) (*domain.AdoptionApplication, error)
+	ListAdoptionApplicationsByPetIDFunc  func(ctx context.Context, petID string, page, limit int, statusFilter *domain.ApplicationStatus) ([]*domain.AdoptionApplication, int64, error)
+	ListStalePendingApplicationsFunc     func(ctx context.Context, pendingSince time.Time, limit int) ([]*domain.AdoptionApplication, error)
+	ClaimPendingReminderFunc             func(ctx context.Context, id string, pendingSince, at time.Time) (bool, error)
 }
 
 var _ repository.AdoptionRepository = (*MockAdoptionRepository)(nil)
@@ -40,7 +69,7 @@ func (m *MockAdoptionRepository) GetAdoptionApplicationByID(ctx context.Context,
 	}
 	return nil, errors.New("GetAdoptionApplicationByIDFunc not implemented")
 }
-func (m *MockAdoptionRepository) UpdateAdoptionApplicationStatus(ctx context.Context, id string, newStatus domain.ApplicationStatus, reviewNotes string) (*domain.AdoptionApplication, error) {
+func (m *MockAdoptionRepository) UpdateAdoptionApplicationStatus(ctx context.Context, id string, newStatus domain.ApplicationStatus, reviewNotes *string) (*domain.AdoptionApplication, error) {
 	if m.UpdateAdoptionApplicationStatusFunc != nil {
 		return m.UpdateAdoptionApplicationStatusFunc(ctx, id, newStatus, reviewNotes)
 	}
@@ -52,6 +81,67 @@ func (m *MockAdoptionRepository) ListAdoptionApplicationsByUserID(ctx context.Co
 	}
 	return nil, 0, errors.New("ListAdoptionApplicationsByUserIDFunc not implemented")
 }
+func (m *MockAdoptionRepository) ListAdoptionApplicationsByPetID(ctx context.Context, petID string, page, limit int, statusFilter *domain.ApplicationStatus) ([]*domain.AdoptionApplication, int64, error) {
+	if m.ListAdoptionApplicationsByPetIDFunc != nil {
+		return m.ListAdoptionApplicationsByPetIDFunc(ctx, petID, page, limit, statusFilter)
+	}
+	return nil, 0, errors.New("ListAdoptionApplicationsByPetIDFunc not implemented")
+}
+func (m *MockAdoptionRepository) CountOtherPetsAppliedForSince(ctx context.Context, userID, excludePetID string, since time.Time) (int, error) {
+	if m.CountOtherPetsAppliedForSinceFunc != nil {
+		return m.CountOtherPetsAppliedForSinceFunc(ctx, userID, excludePetID, since)
+	}
+	return 0, errors.New("CountOtherPetsAppliedForSinceFunc not implemented")
+}
+func (m *MockAdoptionRepository) GetLatestApplicationForPet(ctx context.Context, userID, petID string) (*domain.AdoptionApplication, error) {
+	if m.GetLatestApplicationForPetFunc != nil {
+		return m.GetLatestApplicationForPetFunc(ctx, userID, petID)
+	}
+	return nil, nil // No previous application
+}
+func (m *MockAdoptionRepository) CountApplicationsByPetIDs(ctx context.Context, petIDs []string) (map[string]int64, error) {
+	if m.CountApplicationsByPetIDsFunc != nil {
+		return m.CountApplicationsByPetIDsFunc(ctx, petIDs)
+	}
+	return nil, errors.New("CountApplicationsByPetIDsFunc not implemented")
+}
+
+func (m *MockAdoptionRepository) GetPetApplicationStats(ctx context.Context, petID string) (*domain.PetApplicationStats, error) {
+	if m.GetPetApplicationStatsFunc != nil {
+		return m.GetPetApplicationStatsFunc(ctx, petID)
+	}
+	return nil, errors.New("GetPetApplicationStatsFunc not implemented")
+}
+func (m *MockAdoptionRepository) ReopenAdoptionApplication(ctx context.Context, id string, change domain.ApplicationStatusChange) (*domain.AdoptionApplication, error) {
+	if m.ReopenAdoptionApplicationFunc != nil {
+		return m.ReopenAdoptionApplicationFunc(ctx, id, change)
+	}
+	return nil, errors.New("ReopenAdoptionApplicationFunc not implemented")
+}
+func (m *MockAdoptionRepository) AddApplicationAttachment(ctx context.Context, id string, attachment domain.Attachment, maxAttachments int) (*domain.AdoptionApplication, error) {
+	if m.AddApplicationAttachmentFunc != nil {
+		return m.AddApplicationAttachmentFunc(ctx, id, attachment, maxAttachments)
+	}
+	return nil, errors.New("AddApplicationAttachmentFunc not implemented")
+}
+func (m *MockAdoptionRepository) RemoveApplicationAttachment(ctx context.Context, id, url string) (*domain.AdoptionApplication, error) {
+	if m.RemoveApplicationAttachmentFunc != nil {
+		return m.RemoveApplicationAttachmentFunc(ctx, id, url)
+	}
+	return nil, errors.New("RemoveApplicationAttachmentFunc not implemented")
+}
+func (m *MockAdoptionRepository) ListStalePendingApplications(ctx context.Context, pendingSince time.Time, limit int) ([]*domain.AdoptionApplication, error) {
+	if m.ListStalePendingApplicationsFunc != nil {
+		return m.ListStalePendingApplicationsFunc(ctx, pendingSince, limit)
+	}
+	return nil, errors.New("ListStalePendingApplicationsFunc not implemented")
+}
+func (m *MockAdoptionRepository) ClaimPendingReminder(ctx context.Context, id string, pendingSince, at time.Time) (bool, error) {
+	if m.ClaimPendingReminderFunc != nil {
+		return m.ClaimPendingReminderFunc(ctx, id, pendingSince, at)
+	}
+	return false, errors.New("ClaimPendingReminderFunc not implemented")
+}
 
 // MockAdoptionCache is a mock for AdoptionCache
 type MockAdoptionCache struct {
@@ -85,6 +175,8 @@ func (m *MockAdoptionCache) DeleteAdoptionApplication(ctx context.Context, id st
 type MockAdoptionEventPublisher struct {
 	PublishAdoptionApplicationCreatedFunc       func(ctx context.Context, app *domain.AdoptionApplication) error
 	PublishAdoptionApplicationStatusUpdatedFunc func(ctx context.Context, app *domain.AdoptionApplication) error
+	PublishApplicantFlaggedFunc                 func(ctx context.Context, app *domain.AdoptionApplication, recentPetCount int) error
+	PublishApplicationPendingReminderFunc       func(ctx context.Context, app *domain.AdoptionApplication) error
 	CloseFunc                                   func()
 }
 
@@ -102,12 +194,60 @@ func (m *MockAdoptionEventPublisher) PublishAdoptionApplicationStatusUpdated(ctx
 	}
 	return errors.New("PublishAdoptionApplicationStatusUpdatedFunc not implemented")
 }
+func (m *MockAdoptionEventPublisher) PublishApplicantFlagged(ctx context.Context, app *domain.AdoptionApplication, recentPetCount int) error {
+	if m.PublishApplicantFlaggedFunc != nil {
+		return m.PublishApplicantFlaggedFunc(ctx, app, recentPetCount)
+	}
+	return errors.New("PublishApplicantFlaggedFunc not implemented")
+}
+func (m *MockAdoptionEventPublisher) PublishApplicationPendingReminder(ctx context.Context, app *domain.AdoptionApplication) error {
+	if m.PublishApplicationPendingReminderFunc != nil {
+		return m.PublishApplicationPendingReminderFunc(ctx, app)
+	}
+	return errors.New("PublishApplicationPendingReminderFunc not implemented")
+}
 func (m *MockAdoptionEventPublisher) Close() {
 	if m.CloseFunc != nil {
 		m.CloseFunc()
 	}
 }
 
+// MockPetServiceClient is a mock for the pet-service client
+type MockPetServiceClient struct {
+	IsPetAvailableForAdoptionFunc func(ctx context.Context, petID string) (bool, error)
+	GetPetAdoptionStatusFunc      func(ctx context.Context, petID string) (pbPet.AdoptionStatus, error)
+	MarkPetAsAdoptedFunc          func(ctx context.Context, petID, adopterUserID, reason string) error
+	MarkPetAsAvailableFunc        func(ctx context.Context, petID, reason string) error
+}
+
+var _ client.PetServiceClient = (*MockPetServiceClient)(nil)
+
+func (m *MockPetServiceClient) IsPetAvailableForAdoption(ctx context.Context, petID string) (bool, error) {
+	if m.IsPetAvailableForAdoptionFunc != nil {
+		return m.IsPetAvailableForAdoptionFunc(ctx, petID)
+	}
+	return false, errors.New("IsPetAvailableForAdoptionFunc not implemented")
+}
+func (m *MockPetServiceClient) GetPetAdoptionStatus(ctx context.Context, petID string) (pbPet.AdoptionStatus, error) {
+	if m.GetPetAdoptionStatusFunc != nil {
+		return m.GetPetAdoptionStatusFunc(ctx, petID)
+	}
+	return pbPet.AdoptionStatus_ADOPTION_STATUS_UNSPECIFIED, errors.New("GetPetAdoptionStatusFunc not implemented")
+}
+func (m *MockPetServiceClient) MarkPetAsAdopted(ctx context.Context, petID, adopterUserID, reason string) error {
+	if m.MarkPetAsAdoptedFunc != nil {
+		return m.MarkPetAsAdoptedFunc(ctx, petID, adopterUserID, reason)
+	}
+	return errors.New("MarkPetAsAdoptedFunc not implemented")
+}
+func (m *MockPetServiceClient) MarkPetAsAvailable(ctx context.Context, petID, reason string) error {
+	if m.MarkPetAsAvailableFunc != nil {
+		return m.MarkPetAsAvailableFunc(ctx, petID, reason)
+	}
+	return errors.New("MarkPetAsAvailableFunc not implemented")
+}
+func (m *MockPetServiceClient) Close() error { return nil }
+
 // --- Test Functions ---
 
 func TestAdoptionUsecase_CreateAdoptionApplication_Success(t *testing.T) {
@@ -136,7 +276,7 @@ func TestAdoptionUsecase_CreateAdoptionApplication_Success(t *testing.T) {
 		return nil
 	}
 
-	uc := usecase.NewAdoptionUsecase(mockRepo, mockCache, mockPub)
+	uc := usecase.NewAdoptionUsecase(mockRepo, mockCache, mockPub, usecase.AdoptionPolicy{}, nil)
 	ctx := context.Background()
 
 	createdApp, err := uc.CreateAdoptionApplication(ctx, reqData)
@@ -170,6 +310,126 @@ func TestAdoptionUsecase_CreateAdoptionApplication_Success(t *testing.T) {
 	}
 }
 
+func TestAdoptionUsecase_CreateAdoptionApplication_SeedsCache(t *testing.T) {
+	repoReads := 0
+	mockRepo := &MockAdoptionRepository{
+		CreateAdoptionApplicationFunc: func(ctx context.Context, app *domain.AdoptionApplication) (*domain.AdoptionApplication, error) {
+			app.ID = "app1"
+			app.PrepareForCreate()
+			return app, nil
+		},
+		GetAdoptionApplicationByIDFunc: func(ctx context.Context, id string) (*domain.AdoptionApplication, error) {
+			repoReads++
+			return nil, errors.New("adoption application not found")
+		},
+	}
+	cached := map[string]*domain.AdoptionApplication{}
+	mockCache := &MockAdoptionCache{
+		GetAdoptionApplicationFunc: func(ctx context.Context, id string) (*domain.AdoptionApplication, error) {
+			if app, ok := cached[id]; ok {
+				return app, nil
+			}
+			return nil, errors.New("adoption application not found in cache")
+		},
+		SetAdoptionApplicationFunc: func(ctx context.Context, id string, app *domain.AdoptionApplication, expiration time.Duration) error {
+			cached[id] = app
+			return nil
+		},
+	}
+	uc := usecase.NewAdoptionUsecase(mockRepo, mockCache, &MockAdoptionEventPublisher{}, usecase.AdoptionPolicy{SeedCacheOnCreate: true}, nil)
+	ctx := context.Background()
+
+	if _, err := uc.CreateAdoptionApplication(ctx, usecase.CreateAdoptionApplicationRequestData{UserID: "user1", PetID: "pet1"}); err != nil {
+		t.Fatalf("CreateAdoptionApplication() error = %v", err)
+	}
+	if _, ok := cached["app1"]; !ok {
+		t.Fatalf("CreateAdoptionApplication() did not seed the cache with the new application")
+	}
+	app, err := uc.GetAdoptionApplicationByID(ctx, "app1")
+	if err != nil {
+		t.Fatalf("GetAdoptionApplicationByID() error = %v", err)
+	}
+	if app.UserID != "user1" || app.PetID != "pet1" {
+		t.Errorf("GetAdoptionApplicationByID() = %+v, want the created application", app)
+	}
+	if repoReads != 0 {
+		t.Errorf("repository GetAdoptionApplicationByID called %d times, want 0 (read served from cache)", repoReads)
+	}
+}
+
+func TestAdoptionUsecase_NormalizesNotes(t *testing.T) {
+	var storedNotes, storedReviewNotes string
+	mockRepo := &MockAdoptionRepository{
+		GetLatestApplicationForPetFunc: func(ctx context.Context, userID, petID string) (*domain.AdoptionApplication, error) {
+			return nil, nil
+		},
+		CountOtherPetsAppliedForSinceFunc: func(ctx context.Context, userID, excludePetID string, since time.Time) (int, error) {
+			return 0, nil
+		},
+		CreateAdoptionApplicationFunc: func(ctx context.Context, app *domain.AdoptionApplication) (*domain.AdoptionApplication, error) {
+			storedNotes = app.ApplicationNotes
+			app.ID = "app1"
+			return app, nil
+		},
+		UpdateAdoptionApplicationStatusFunc: func(ctx context.Context, id string, newStatus domain.ApplicationStatus, reviewNotes *string) (*domain.AdoptionApplication, error) {
+			storedReviewNotes = *reviewNotes
+			return &domain.AdoptionApplication{ID: id, Status: newStatus, ReviewNotes: *reviewNotes}, nil
+		},
+	}
+	mockCache := &MockAdoptionCache{
+		DeleteAdoptionApplicationFunc: func(ctx context.Context, id string) error { return nil },
+	}
+	mockPub := &MockAdoptionEventPublisher{
+		PublishAdoptionApplicationCreatedFunc:       func(ctx context.Context, app *domain.AdoptionApplication) error { return nil },
+		PublishAdoptionApplicationStatusUpdatedFunc: func(ctx context.Context, app *domain.AdoptionApplication) error { return nil },
+	}
+	uc := usecase.NewAdoptionUsecase(mockRepo, mockCache, mockPub, usecase.AdoptionPolicy{MaxNotesLength: 20, RequireReviewNotesOnRejection: true}, nil)
+	ctx := context.Background()
+
+	_, err := uc.CreateAdoptionApplication(ctx, usecase.CreateAdoptionApplicationRequestData{
+		UserID:           "user1",
+		PetID:            "pet1",
+		ApplicationNotes: "\t I have\x07 a   big\x00 garden  ",
+	})
+	if err != nil {
+		t.Fatalf("CreateAdoptionApplication() error = %v", err)
+	}
+	if want := "I have a big garden"; storedNotes != want {
+		t.Errorf("stored application notes = %q, want %q", storedNotes, want)
+	}
+
+	// Application notes still too long once normalized are rejected rather than cut.
+	_, err = uc.CreateAdoptionApplication(ctx, usecase.CreateAdoptionApplicationRequestData{
+		UserID:           "user1",
+		PetID:            "pet2",
+		ApplicationNotes: "I have a big garden and a lot of free time",
+	})
+	var validationErr *usecase.ValidationError
+	if !errors.As(err, &validationErr) || len(validationErr.Violations) != 1 || validationErr.Violations[0].Field != "application_notes" {
+		t.Errorf("CreateAdoptionApplication() with long notes error = %v, want an application_notes violation", err)
+	}
+
+	// Notes that are empty once normalized do not count as an explanation for a rejection.
+	_, err = uc.UpdateAdoptionApplicationStatus(ctx, "app1", usecase.UpdateAdoptionApplicationStatusRequestData{
+		NewStatus:   domain.StatusAppRejected,
+		ReviewNotes: stringPtr(" \x00\x1b \n "),
+	})
+	if !errors.Is(err, usecase.ErrReviewNotesRequired) {
+		t.Errorf("UpdateAdoptionApplicationStatus() with blank notes error = %v, want %v", err, usecase.ErrReviewNotesRequired)
+	}
+
+	_, err = uc.UpdateAdoptionApplicationStatus(ctx, "app1", usecase.UpdateAdoptionApplicationStatusRequestData{
+		NewStatus:   domain.StatusAppRejected,
+		ReviewNotes: stringPtr("Landlord   does not\tallow pets"),
+	})
+	if err != nil {
+		t.Fatalf("UpdateAdoptionApplicationStatus() error = %v", err)
+	}
+	if want := "Landlord does not al"; storedReviewNotes != want {
+		t.Errorf("stored review notes = %q, want %q", storedReviewNotes, want)
+	}
+}
+
 func TestAdoptionUsecase_CreateAdoptionApplication_MissingUserID(t *testing.T) {
 	mockRepo := &MockAdoptionRepository{} // Not expected to be called
 	mockCache := &MockAdoptionCache{}
@@ -181,7 +441,7 @@ func TestAdoptionUsecase_CreateAdoptionApplication_MissingUserID(t *testing.T) {
 		ApplicationNotes: "Test notes",
 	}
 
-	uc := usecase.NewAdoptionUsecase(mockRepo, mockCache, mockPub)
+	uc := usecase.NewAdoptionUsecase(mockRepo, mockCache, mockPub, usecase.AdoptionPolicy{}, nil)
 	ctx := context.Background()
 
 	_, err := uc.CreateAdoptionApplication(ctx, reqData)
@@ -190,10 +450,1008 @@ func TestAdoptionUsecase_CreateAdoptionApplication_MissingUserID(t *testing.T) {
 		t.Errorf("Expected an error when UserID is missing, but got nil")
 		return
 	}
-	expectedErrorMsg := "user ID and pet ID are required" // Or similar based on your usecase validation
+	expectedErrorMsg := "user ID is required" // Or similar based on your usecase validation
 	if err.Error() != expectedErrorMsg {
 		t.Errorf("Expected error message '%s', but got '%s'", expectedErrorMsg, err.Error())
 	}
+	var validationErr *usecase.ValidationError
+	if !errors.As(err, &validationErr) || len(validationErr.Violations) != 1 || validationErr.Violations[0].Field != "user_id" {
+		t.Errorf("Expected a user_id field violation, but got %v", err)
+	}
+}
+
+func TestAdoptionHandler_CreateAdoptionApplication_ReportsFieldViolations(t *testing.T) {
+	uc := usecase.NewAdoptionUsecase(&MockAdoptionRepository{}, &MockAdoptionCache{}, &MockAdoptionEventPublisher{}, usecase.AdoptionPolicy{MaxNotesLength: 10}, nil)
+	h := handler.NewAdoptionHandler(uc)
+
+	_, err := h.CreateAdoptionApplication(context.Background(), &pb.CreateAdoptionApplicationRequest{ApplicationNotes: "Far more than ten characters"})
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.InvalidArgument {
+		t.Fatalf("CreateAdoptionApplication() error = %v, want InvalidArgument", err)
+	}
+	var got []string
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			for _, v := range badRequest.GetFieldViolations() {
+				if v.GetDescription() == "" {
+					t.Errorf("violation of %s has no description", v.GetField())
+				}
+				got = append(got, v.GetField())
+			}
+		}
+	}
+	if want := []string{"user_id", "pet_id", "application_notes"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("field violations = %v, want %v", got, want)
+	}
+}
+
+func TestAdoptionHandler_CreateAdoptionApplication_ChecksPetAvailability(t *testing.T) {
+	// The pet-service reports a pet as available only while its status is AVAILABLE.
+	tests := []struct {
+		name      string
+		petStatus string
+		petErr    error
+		wantCode  codes.Code
+	}{
+		{name: "available", petStatus: "AVAILABLE", wantCode: codes.OK},
+		{name: "pending adoption", petStatus: "PENDING_ADOPTION", wantCode: codes.FailedPrecondition},
+		{name: "adopted", petStatus: "ADOPTED", wantCode: codes.FailedPrecondition},
+		{name: "unknown pet", petErr: client.ErrPetNotFound, wantCode: codes.NotFound},
+		{name: "pet-service down", petErr: status.Error(codes.Unavailable, "connection refused"), wantCode: codes.Internal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created := false
+			mockRepo := &MockAdoptionRepository{
+				CreateAdoptionApplicationFunc: func(ctx context.Context, app *domain.AdoptionApplication) (*domain.AdoptionApplication, error) {
+					created = true
+					app.ID = "app1"
+					app.PrepareForCreate()
+					return app, nil
+				},
+				CountOtherPetsAppliedForSinceFunc: func(ctx context.Context, userID, excludePetID string, since time.Time) (int, error) {
+					return 0, nil
+				},
+			}
+			var checkedPetID string
+			petClient := &MockPetServiceClient{
+				IsPetAvailableForAdoptionFunc: func(ctx context.Context, petID string) (bool, error) {
+					checkedPetID = petID
+					return tt.petStatus == "AVAILABLE", tt.petErr
+				},
+			}
+			uc := usecase.NewAdoptionUsecase(mockRepo, &MockAdoptionCache{}, &MockAdoptionEventPublisher{}, usecase.AdoptionPolicy{}, petClient)
+			h := handler.NewAdoptionHandler(uc)
+
+			_, err := h.CreateAdoptionApplication(context.Background(), &pb.CreateAdoptionApplicationRequest{UserId: "user1", PetId: "pet1"})
+			if got := status.Code(err); got != tt.wantCode {
+				t.Fatalf("CreateAdoptionApplication() code = %v, want %v (err: %v)", got, tt.wantCode, err)
+			}
+			if checkedPetID != "pet1" {
+				t.Errorf("availability checked for pet %q, want pet1", checkedPetID)
+			}
+			if created != (tt.wantCode == codes.OK) {
+				t.Errorf("application created = %v, want %v", created, tt.wantCode == codes.OK)
+			}
+		})
+	}
+}
+
+func TestAdoptionUsecase_CreateAdoptionApplication_AutoApprovesTrustedUser(t *testing.T) {
+	tests := []struct {
+		name         string
+		roles        []string
+		wantStatus   domain.ApplicationStatus
+		wantApproved bool
+	}{
+		{name: "trusted user", roles: []string{"user", usecase.RoleTrusted}, wantStatus: domain.StatusAppApproved, wantApproved: true},
+		{name: "untrusted user", roles: []string{"user"}, wantStatus: domain.StatusAppPendingReview, wantApproved: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &MockAdoptionRepository{
+				CreateAdoptionApplicationFunc: func(ctx context.Context, app *domain.AdoptionApplication) (*domain.AdoptionApplication, error) {
+					app.ID = "app1"
+					app.PrepareForCreate()
+					return app, nil
+				},
+			}
+			approvedEventSent := false
+			mockPub := &MockAdoptionEventPublisher{
+				PublishAdoptionApplicationCreatedFunc: func(ctx context.Context, app *domain.AdoptionApplication) error { return nil },
+				PublishAdoptionApplicationStatusUpdatedFunc: func(ctx context.Context, app *domain.AdoptionApplication) error {
+					approvedEventSent = app.Status == domain.StatusAppApproved
+					return nil
+				},
+			}
+			uc := usecase.NewAdoptionUsecase(mockRepo, &MockAdoptionCache{}, mockPub, usecase.AdoptionPolicy{AutoApproveTrustedUsers: true}, nil)
+
+			app, err := uc.CreateAdoptionApplication(context.Background(), usecase.CreateAdoptionApplicationRequestData{
+				UserID:      "user1",
+				PetID:       "pet1",
+				CallerRoles: tt.roles,
+			})
+			if err != nil {
+				t.Fatalf("CreateAdoptionApplication() error = %v", err)
+			}
+			if app.Status != tt.wantStatus {
+				t.Errorf("CreateAdoptionApplication() Status = %s, want %s", app.Status, tt.wantStatus)
+			}
+			if approvedEventSent != tt.wantApproved {
+				t.Errorf("approved event sent = %v, want %v", approvedEventSent, tt.wantApproved)
+			}
+		})
+	}
+}
+
+func TestAdoptionHandler_CreateAdoptionApplication_AutoApprovesTrustedCaller(t *testing.T) {
+	mockRepo := &MockAdoptionRepository{
+		CreateAdoptionApplicationFunc: func(ctx context.Context, app *domain.AdoptionApplication) (*domain.AdoptionApplication, error) {
+			app.ID = "app1"
+			app.PrepareForCreate()
+			return app, nil
+		},
+	}
+	mockPub := &MockAdoptionEventPublisher{
+		PublishAdoptionApplicationCreatedFunc:       func(ctx context.Context, app *domain.AdoptionApplication) error { return nil },
+		PublishAdoptionApplicationStatusUpdatedFunc: func(ctx context.Context, app *domain.AdoptionApplication) error { return nil },
+	}
+	uc := usecase.NewAdoptionUsecase(mockRepo, &MockAdoptionCache{}, mockPub, usecase.AdoptionPolicy{AutoApproveTrustedUsers: true}, nil)
+	h := handler.NewAdoptionHandler(uc)
+	// The metadata the gateway forwards for a bearer token of user trusted-1 with the "trusted" role.
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-user-id", "trusted-1", "x-user-roles", usecase.RoleTrusted))
+
+	resp, err := h.CreateAdoptionApplication(ctx, &pb.CreateAdoptionApplicationRequest{UserId: "trusted-1", PetId: "pet1"})
+	if err != nil {
+		t.Fatalf("CreateAdoptionApplication() error = %v", err)
+	}
+	if got := resp.GetApplication().GetStatus(); got != pb.ApplicationStatus_APPROVED {
+		t.Errorf("status of the trusted caller's application = %v, want APPROVED", got)
+	}
+
+	// The caller's roles say nothing about another applicant.
+	resp, err = h.CreateAdoptionApplication(ctx, &pb.CreateAdoptionApplicationRequest{UserId: "user-2", PetId: "pet1"})
+	if err != nil {
+		t.Fatalf("CreateAdoptionApplication() for another user error = %v", err)
+	}
+	if got := resp.GetApplication().GetStatus(); got != pb.ApplicationStatus_PENDING_REVIEW {
+		t.Errorf("status of an application filed for another user = %v, want PENDING_REVIEW", got)
+	}
+}
+
+func TestFeatures_IsFeatureEnabledForUser(t *testing.T) {
+	tests := []struct {
+		name      string
+		enabled   bool
+		allowlist []string
+		want      map[string]bool // User ID -> expected result
+	}{
+		{name: "global on", enabled: true, want: map[string]bool{"user1": true, "user2": true, "": true}},
+		{name: "allowlist only", allowlist: []string{" user1 ", ""}, want: map[string]bool{"user1": true, "user2": false, "": false}},
+		{name: "disabled", want: map[string]bool{"user1": false, "user2": false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags := features.NewFlags()
+			flags.Set(features.AutoApproval, tt.enabled, tt.allowlist)
+			for userID, want := range tt.want {
+				if got := flags.IsFeatureEnabledForUser(features.AutoApproval, userID); got != want {
+					t.Errorf("IsFeatureEnabledForUser(%q) = %v, want %v", userID, got, want)
+				}
+			}
+			if flags.IsFeatureEnabledForUser(features.Digest, "user1") {
+				t.Errorf("IsFeatureEnabledForUser() of an unconfigured feature = true, want false")
+			}
+		})
+	}
+
+	var unset *features.Flags
+	if unset.IsFeatureEnabledForUser(features.AutoApproval, "user1") {
+		t.Errorf("IsFeatureEnabledForUser() on nil Flags = true, want false")
+	}
+}
+
+func TestAdoptionUsecase_CreateAdoptionApplication_AutoApprovalSoftLaunch(t *testing.T) {
+	mockRepo := &MockAdoptionRepository{
+		CreateAdoptionApplicationFunc: func(ctx context.Context, app *domain.AdoptionApplication) (*domain.AdoptionApplication, error) {
+			app.PrepareForCreate()
+			return app, nil
+		},
+	}
+	mockPub := &MockAdoptionEventPublisher{
+		PublishAdoptionApplicationCreatedFunc:       func(ctx context.Context, app *domain.AdoptionApplication) error { return nil },
+		PublishAdoptionApplicationStatusUpdatedFunc: func(ctx context.Context, app *domain.AdoptionApplication) error { return nil },
+	}
+	uc := usecase.NewAdoptionUsecase(mockRepo, &MockAdoptionCache{}, mockPub, usecase.AdoptionPolicy{AutoApproveAllowlist: []string{"pilot-user"}}, nil)
+
+	for userID, want := range map[string]domain.ApplicationStatus{
+		"pilot-user": domain.StatusAppApproved,
+		"other-user": domain.StatusAppPendingReview,
+	} {
+		app, err := uc.CreateAdoptionApplication(context.Background(), usecase.CreateAdoptionApplicationRequestData{
+			UserID:      userID,
+			PetID:       "pet1",
+			CallerRoles: []string{usecase.RoleTrusted},
+		})
+		if err != nil {
+			t.Fatalf("CreateAdoptionApplication(%s) error = %v", userID, err)
+		}
+		if app.Status != want {
+			t.Errorf("CreateAdoptionApplication(%s) Status = %s, want %s", userID, app.Status, want)
+		}
+	}
+}
+
+func TestAdoptionUsecase_CreateAdoptionApplication_AutoApprovalDisabled(t *testing.T) {
+	mockRepo := &MockAdoptionRepository{
+		CreateAdoptionApplicationFunc: func(ctx context.Context, app *domain.AdoptionApplication) (*domain.AdoptionApplication, error) {
+			app.PrepareForCreate()
+			return app, nil
+		},
+	}
+	mockPub := &MockAdoptionEventPublisher{
+		PublishAdoptionApplicationCreatedFunc: func(ctx context.Context, app *domain.AdoptionApplication) error { return nil },
+	}
+	uc := usecase.NewAdoptionUsecase(mockRepo, &MockAdoptionCache{}, mockPub, usecase.AdoptionPolicy{}, nil)
+
+	app, err := uc.CreateAdoptionApplication(context.Background(), usecase.CreateAdoptionApplicationRequestData{
+		UserID:      "user1",
+		PetID:       "pet1",
+		CallerRoles: []string{usecase.RoleTrusted},
+	})
+	if err != nil {
+		t.Fatalf("CreateAdoptionApplication() error = %v", err)
+	}
+	if app.Status != domain.StatusAppPendingReview {
+		t.Errorf("CreateAdoptionApplication() Status = %s, want %s when flag is off", app.Status, domain.StatusAppPendingReview)
+	}
+}
+
+func TestAdoptionUsecase_CreateAdoptionApplication_FlagsHighVelocityApplicant(t *testing.T) {
+	tests := []struct {
+		name        string
+		recentPets  int
+		wantFlagged bool
+	}{
+		{name: "below threshold", recentPets: 2, wantFlagged: false},
+		{name: "at threshold", recentPets: 3, wantFlagged: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotWindowStart time.Time
+			mockRepo := &MockAdoptionRepository{
+				CountOtherPetsAppliedForSinceFunc: func(ctx context.Context, userID, excludePetID string, since time.Time) (int, error) {
+					if userID != "user1" || excludePetID != "pet9" {
+						t.Errorf("CountOtherPetsAppliedForSince() called with (%s, %s), want (user1, pet9)", userID, excludePetID)
+					}
+					gotWindowStart = since
+					return tt.recentPets, nil
+				},
+				CreateAdoptionApplicationFunc: func(ctx context.Context, app *domain.AdoptionApplication) (*domain.AdoptionApplication, error) {
+					app.ID = "app1"
+					app.PrepareForCreate()
+					return app, nil
+				},
+			}
+			flaggedEventCount := -1
+			mockPub := &MockAdoptionEventPublisher{
+				PublishAdoptionApplicationCreatedFunc: func(ctx context.Context, app *domain.AdoptionApplication) error { return nil },
+				PublishApplicantFlaggedFunc: func(ctx context.Context, app *domain.AdoptionApplication, recentPetCount int) error {
+					flaggedEventCount = recentPetCount
+					return nil
+				},
+			}
+			policy := usecase.AdoptionPolicy{VelocityFlagThreshold: 3, VelocityWindow: time.Hour}
+			uc := usecase.NewAdoptionUsecase(mockRepo, &MockAdoptionCache{}, mockPub, policy, nil)
+
+			app, err := uc.CreateAdoptionApplication(context.Background(), usecase.CreateAdoptionApplicationRequestData{UserID: "user1", PetID: "pet9"})
+			if err != nil {
+				t.Fatalf("CreateAdoptionApplication() error = %v", err)
+			}
+			if app.Flagged != tt.wantFlagged {
+				t.Errorf("CreateAdoptionApplication() Flagged = %v, want %v", app.Flagged, tt.wantFlagged)
+			}
+			if app.Status != domain.StatusAppPendingReview {
+				t.Errorf("CreateAdoptionApplication() Status = %s, want %s; flagging must not block", app.Status, domain.StatusAppPendingReview)
+			}
+			if tt.wantFlagged && flaggedEventCount != tt.recentPets {
+				t.Errorf("ApplicantFlagged event recent pet count = %d, want %d", flaggedEventCount, tt.recentPets)
+			}
+			if !tt.wantFlagged && flaggedEventCount != -1 {
+				t.Errorf("ApplicantFlagged event published for an application below the threshold")
+			}
+			if window := time.Since(gotWindowStart); window < time.Hour || window > time.Hour+time.Minute {
+				t.Errorf("velocity window start is %s ago, want about 1h", window)
+			}
+		})
+	}
+}
+
+func TestAdoptionUsecase_CreateAdoptionApplication_ReapplyCooldown(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous *domain.AdoptionApplication
+		wantErr  error
+	}{
+		{name: "pending application", previous: &domain.AdoptionApplication{Status: domain.StatusAppPendingReview, UpdatedAt: time.Now().Add(-60 * 24 * time.Hour)}, wantErr: usecase.ErrDuplicateApplication},
+		{name: "rejected within cooldown", previous: &domain.AdoptionApplication{Status: domain.StatusAppRejected, UpdatedAt: time.Now().Add(-2 * 24 * time.Hour)}, wantErr: usecase.ErrReapplyCooldown},
+		{name: "rejected after cooldown", previous: &domain.AdoptionApplication{Status: domain.StatusAppRejected, UpdatedAt: time.Now().Add(-8 * 24 * time.Hour)}, wantErr: nil},
+		{name: "cancelled by user", previous: &domain.AdoptionApplication{Status: domain.StatusAppCancelledByUser, UpdatedAt: time.Now()}, wantErr: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created := false
+			mockRepo := &MockAdoptionRepository{
+				GetLatestApplicationForPetFunc: func(ctx context.Context, userID, petID string) (*domain.AdoptionApplication, error) {
+					return tt.previous, nil
+				},
+				CreateAdoptionApplicationFunc: func(ctx context.Context, app *domain.AdoptionApplication) (*domain.AdoptionApplication, error) {
+					created = true
+					app.ID = "app2"
+					app.PrepareForCreate()
+					return app, nil
+				},
+			}
+			mockPub := &MockAdoptionEventPublisher{
+				PublishAdoptionApplicationCreatedFunc: func(ctx context.Context, app *domain.AdoptionApplication) error { return nil },
+			}
+			uc := usecase.NewAdoptionUsecase(mockRepo, &MockAdoptionCache{}, mockPub, usecase.AdoptionPolicy{ReapplyCooldown: 7 * 24 * time.Hour}, nil)
+
+			_, err := uc.CreateAdoptionApplication(context.Background(), usecase.CreateAdoptionApplicationRequestData{UserID: "user1", PetID: "pet1"})
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("CreateAdoptionApplication() error = %v, want nil", err)
+				}
+				if !created {
+					t.Error("CreateAdoptionApplication() did not create the application")
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateAdoptionApplication() error = %v, want %v", err, tt.wantErr)
+			}
+			if created {
+				t.Error("CreateAdoptionApplication() created an application it should have blocked")
+			}
+		})
+	}
+}
+
+func TestAdoptionUsecase_UpdateAdoptionApplicationStatus_RejectWithoutNotes(t *testing.T) {
+	mockRepo := &MockAdoptionRepository{
+		UpdateAdoptionApplicationStatusFunc: func(ctx context.Context, id string, newStatus domain.ApplicationStatus, reviewNotes *string) (*domain.AdoptionApplication, error) {
+			t.Error("repository should not be called when review notes are missing")
+			return nil, errors.New("unexpected call")
+		},
+	}
+	policy := usecase.AdoptionPolicy{RequireReviewNotesOnRejection: true}
+	uc := usecase.NewAdoptionUsecase(mockRepo, &MockAdoptionCache{}, &MockAdoptionEventPublisher{}, policy, nil)
+
+	_, err := uc.UpdateAdoptionApplicationStatus(context.Background(), "app1", usecase.UpdateAdoptionApplicationStatusRequestData{
+		NewStatus:   domain.StatusAppRejected,
+		ReviewNotes: stringPtr("   "),
+	})
+	if !errors.Is(err, usecase.ErrReviewNotesRequired) {
+		t.Errorf("UpdateAdoptionApplicationStatus() error = %v, want %v", err, usecase.ErrReviewNotesRequired)
+	}
+}
+
+func TestAdoptionUsecase_UpdateAdoptionApplicationStatus_RejectWithNotes(t *testing.T) {
+	mockRepo := &MockAdoptionRepository{
+		UpdateAdoptionApplicationStatusFunc: func(ctx context.Context, id string, newStatus domain.ApplicationStatus, reviewNotes *string) (*domain.AdoptionApplication, error) {
+			return &domain.AdoptionApplication{ID: id, Status: newStatus, ReviewNotes: *reviewNotes}, nil
+		},
+	}
+	mockCache := &MockAdoptionCache{
+		DeleteAdoptionApplicationFunc: func(ctx context.Context, id string) error { return nil },
+	}
+	mockPub := &MockAdoptionEventPublisher{
+		PublishAdoptionApplicationStatusUpdatedFunc: func(ctx context.Context, app *domain.AdoptionApplication) error { return nil },
+	}
+	policy := usecase.AdoptionPolicy{RequireReviewNotesOnRejection: true}
+	uc := usecase.NewAdoptionUsecase(mockRepo, mockCache, mockPub, policy, nil)
+
+	app, err := uc.UpdateAdoptionApplicationStatus(context.Background(), "app1", usecase.UpdateAdoptionApplicationStatusRequestData{
+		NewStatus:   domain.StatusAppRejected,
+		ReviewNotes: stringPtr("Home visit did not meet requirements."),
+	})
+	if err != nil {
+		t.Fatalf("UpdateAdoptionApplicationStatus() error = %v", err)
+	}
+	if app.Status != domain.StatusAppRejected {
+		t.Errorf("UpdateAdoptionApplicationStatus() Status = %s, want %s", app.Status, domain.StatusAppRejected)
+	}
+}
+
+// newPetStatusMock returns a pet client that keeps one pet's status and adopter, starting at initial.
+func newPetStatusMock(initial pbPet.AdoptionStatus) (*MockPetServiceClient, *pbPet.AdoptionStatus, *string) {
+	petStatus, adopter := initial, ""
+	return &MockPetServiceClient{
+		GetPetAdoptionStatusFunc: func(ctx context.Context, petID string) (pbPet.AdoptionStatus, error) {
+			return petStatus, nil
+		},
+		MarkPetAsAdoptedFunc: func(ctx context.Context, petID, adopterUserID, reason string) error {
+			petStatus, adopter = pbPet.AdoptionStatus_ADOPTED, adopterUserID
+			return nil
+		},
+		MarkPetAsAvailableFunc: func(ctx context.Context, petID, reason string) error {
+			petStatus, adopter = pbPet.AdoptionStatus_AVAILABLE, ""
+			return nil
+		},
+	}, &petStatus, &adopter
+}
+
+// decidingRepository returns a repository whose application app1 by user1 for pet1 is updated to
+// any status, with activeOthers other applications for pet1 still pending review.
+func decidingRepository(activeOthers int64) *MockAdoptionRepository {
+	return &MockAdoptionRepository{
+		UpdateAdoptionApplicationStatusFunc: func(ctx context.Context, id string, newStatus domain.ApplicationStatus, reviewNotes *string) (*domain.AdoptionApplication, error) {
+			return &domain.AdoptionApplication{ID: id, UserID: "user1", PetID: "pet1", Status: newStatus}, nil
+		},
+		GetPetApplicationStatsFunc: func(ctx context.Context, petID string) (*domain.PetApplicationStats, error) {
+			return &domain.PetApplicationStats{PetID: petID, CountsByStatus: map[domain.ApplicationStatus]int64{
+				domain.StatusAppPendingReview: activeOthers,
+				domain.StatusAppRejected:      1,
+			}}, nil
+		},
+	}
+}
+
+func TestAdoptionUsecase_UpdateAdoptionApplicationStatus_ApprovalMarksPetAdopted(t *testing.T) {
+	petClient, petStatus, adopter := newPetStatusMock(pbPet.AdoptionStatus_PENDING_ADOPTION)
+	uc := usecase.NewAdoptionUsecase(decidingRepository(0), &MockAdoptionCache{}, &MockAdoptionEventPublisher{}, usecase.AdoptionPolicy{}, petClient)
+
+	if _, err := uc.UpdateAdoptionApplicationStatus(context.Background(), "app1", usecase.UpdateAdoptionApplicationStatusRequestData{NewStatus: domain.StatusAppApproved}); err != nil {
+		t.Fatalf("UpdateAdoptionApplicationStatus() error = %v", err)
+	}
+	if *petStatus != pbPet.AdoptionStatus_ADOPTED || *adopter != "user1" {
+		t.Errorf("pet status = %v adopted by %q, want ADOPTED by user1", *petStatus, *adopter)
+	}
+}
+
+func TestAdoptionUsecase_UpdateAdoptionApplicationStatus_PetServiceErrorDoesNotFail(t *testing.T) {
+	petClient := &MockPetServiceClient{
+		MarkPetAsAdoptedFunc: func(ctx context.Context, petID, adopterUserID, reason string) error {
+			return status.Error(codes.Unavailable, "connection refused")
+		},
+	}
+	uc := usecase.NewAdoptionUsecase(decidingRepository(0), &MockAdoptionCache{}, &MockAdoptionEventPublisher{}, usecase.AdoptionPolicy{}, petClient)
+
+	app, err := uc.UpdateAdoptionApplicationStatus(context.Background(), "app1", usecase.UpdateAdoptionApplicationStatusRequestData{NewStatus: domain.StatusAppApproved})
+	if err != nil || app.Status != domain.StatusAppApproved {
+		t.Fatalf("UpdateAdoptionApplicationStatus() = %v, %v; want APPROVED application", app, err)
+	}
+}
+
+func TestAdoptionUsecase_UpdateAdoptionApplicationStatus_RejectionReleasesPet(t *testing.T) {
+	tests := []struct {
+		name         string
+		initial      pbPet.AdoptionStatus
+		activeOthers int64
+		want         pbPet.AdoptionStatus
+	}{
+		{name: "pending pet without other applications", initial: pbPet.AdoptionStatus_PENDING_ADOPTION, want: pbPet.AdoptionStatus_AVAILABLE},
+		{name: "other application still pending", initial: pbPet.AdoptionStatus_PENDING_ADOPTION, activeOthers: 1, want: pbPet.AdoptionStatus_PENDING_ADOPTION},
+		{name: "pet already adopted", initial: pbPet.AdoptionStatus_ADOPTED, want: pbPet.AdoptionStatus_ADOPTED},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			petClient, petStatus, _ := newPetStatusMock(tt.initial)
+			uc := usecase.NewAdoptionUsecase(decidingRepository(tt.activeOthers), &MockAdoptionCache{}, &MockAdoptionEventPublisher{}, usecase.AdoptionPolicy{}, petClient)
+
+			if _, err := uc.UpdateAdoptionApplicationStatus(context.Background(), "app1", usecase.UpdateAdoptionApplicationStatusRequestData{NewStatus: domain.StatusAppRejected}); err != nil {
+				t.Fatalf("UpdateAdoptionApplicationStatus() error = %v", err)
+			}
+			if *petStatus != tt.want {
+				t.Errorf("pet status = %v, want %v", *petStatus, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdoptionUsecase_CancelAdoptionApplication(t *testing.T) {
+	apps := map[string]*domain.AdoptionApplication{
+		"pending":   {ID: "pending", UserID: "user1", PetID: "pet1", Status: domain.StatusAppPendingReview},
+		"approved":  {ID: "approved", UserID: "user1", PetID: "pet1", Status: domain.StatusAppApproved},
+		"rejected":  {ID: "rejected", UserID: "user1", PetID: "pet1", Status: domain.StatusAppRejected},
+		"cancelled": {ID: "cancelled", UserID: "user1", PetID: "pet1", Status: domain.StatusAppCancelledByUser},
+	}
+	var updated []string
+	mockRepo := &MockAdoptionRepository{
+		GetAdoptionApplicationByIDFunc: func(ctx context.Context, id string) (*domain.AdoptionApplication, error) {
+			if app, ok := apps[id]; ok {
+				return app, nil
+			}
+			return nil, errors.New("adoption application not found")
+		},
+		UpdateAdoptionApplicationStatusFunc: func(ctx context.Context, id string, newStatus domain.ApplicationStatus, reviewNotes *string) (*domain.AdoptionApplication, error) {
+			updated = append(updated, id)
+			app := *apps[id]
+			app.Status = newStatus
+			return &app, nil
+		},
+	}
+	var published []domain.ApplicationStatus
+	mockPub := &MockAdoptionEventPublisher{
+		PublishAdoptionApplicationStatusUpdatedFunc: func(ctx context.Context, app *domain.AdoptionApplication) error {
+			published = append(published, app.Status)
+			return nil
+		},
+	}
+	mockCache := &MockAdoptionCache{
+		DeleteAdoptionApplicationFunc: func(ctx context.Context, id string) error { return nil },
+	}
+	uc := usecase.NewAdoptionUsecase(mockRepo, mockCache, mockPub, usecase.AdoptionPolicy{}, nil)
+	ctx := context.Background()
+
+	tests := []struct {
+		name             string
+		applicationID    string
+		userID, callerID string
+		wantErr          error
+	}{
+		{name: "caller is not the user", applicationID: "pending", userID: "user1", callerID: "user2", wantErr: usecase.ErrCancelForbidden},
+		{name: "user is not the applicant", applicationID: "pending", userID: "user2", callerID: "user2", wantErr: usecase.ErrCancelForbidden},
+		{name: "approved", applicationID: "approved", userID: "user1", callerID: "user1", wantErr: usecase.ErrApplicationDecided},
+		{name: "rejected", applicationID: "rejected", userID: "user1", callerID: "user1", wantErr: usecase.ErrApplicationDecided},
+	}
+	for _, tt := range tests {
+		if _, err := uc.CancelAdoptionApplication(ctx, tt.applicationID, tt.userID, tt.callerID); !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: CancelAdoptionApplication() error = %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+	if len(updated) != 0 {
+		t.Fatalf("rejected cancellations updated %v", updated)
+	}
+
+	app, err := uc.CancelAdoptionApplication(ctx, "cancelled", "user1", "user1")
+	if err != nil || app.Status != domain.StatusAppCancelledByUser || len(updated) != 0 {
+		t.Errorf("cancelling a cancelled application = %v, %v (updated %v); want it unchanged", app, err, updated)
+	}
+
+	app, err = uc.CancelAdoptionApplication(ctx, "pending", "user1", "user1")
+	if err != nil {
+		t.Fatalf("CancelAdoptionApplication() error = %v", err)
+	}
+	if app.Status != domain.StatusAppCancelledByUser {
+		t.Errorf("CancelAdoptionApplication() Status = %s, want %s", app.Status, domain.StatusAppCancelledByUser)
+	}
+	if want := []domain.ApplicationStatus{domain.StatusAppCancelledByUser}; !reflect.DeepEqual(published, want) {
+		t.Errorf("published status updates = %v, want %v", published, want)
+	}
+}
+
+func TestAdoptionHandler_ListPetAdoptionApplications_PaginatesAndFilters(t *testing.T) {
+	var stored []*domain.AdoptionApplication
+	for i, st := range []domain.ApplicationStatus{
+		domain.StatusAppPendingReview, domain.StatusAppRejected, domain.StatusAppPendingReview,
+		domain.StatusAppPendingReview, domain.StatusAppCancelledByUser,
+	} {
+		stored = append(stored, &domain.AdoptionApplication{ID: fmt.Sprintf("app%d", i+1), PetID: "pet1", UserID: fmt.Sprintf("user%d", i+1), Status: st})
+	}
+	mockRepo := &MockAdoptionRepository{
+		ListAdoptionApplicationsByPetIDFunc: func(ctx context.Context, petID string, page, limit int, statusFilter *domain.ApplicationStatus) ([]*domain.AdoptionApplication, int64, error) {
+			var matching []*domain.AdoptionApplication
+			for _, app := range stored {
+				if app.PetID == petID && (statusFilter == nil || app.Status == *statusFilter) {
+					matching = append(matching, app)
+				}
+			}
+			start, end := (page-1)*limit, page*limit
+			if start > len(matching) {
+				start = len(matching)
+			}
+			if end > len(matching) {
+				end = len(matching)
+			}
+			return matching[start:end], int64(len(matching)), nil
+		},
+	}
+	uc := usecase.NewAdoptionUsecase(mockRepo, &MockAdoptionCache{}, &MockAdoptionEventPublisher{}, usecase.AdoptionPolicy{}, nil)
+	h := handler.NewAdoptionHandler(uc)
+	ctx := context.Background()
+
+	list := func(page, limit int32, statusFilter *pb.ApplicationStatus) *pb.ListAdoptionApplicationsResponse {
+		t.Helper()
+		res, err := h.ListPetAdoptionApplications(ctx, &pb.ListPetAdoptionApplicationsRequest{PetId: "pet1", Page: &page, Limit: &limit, StatusFilter: statusFilter})
+		if err != nil {
+			t.Fatalf("ListPetAdoptionApplications() error = %v", err)
+		}
+		return res
+	}
+	ids := func(res *pb.ListAdoptionApplicationsResponse) []string {
+		var got []string
+		for _, app := range res.GetApplications() {
+			got = append(got, app.GetId())
+		}
+		return got
+	}
+
+	res := list(2, 2, nil)
+	if want := []string{"app3", "app4"}; !reflect.DeepEqual(ids(res), want) || res.GetTotalCount() != 5 || res.GetPage() != 2 || res.GetLimit() != 2 {
+		t.Errorf("page 2 of all = %v (total %d, page %d, limit %d), want %v of 5", ids(res), res.GetTotalCount(), res.GetPage(), res.GetLimit(), want)
+	}
+
+	pending := pb.ApplicationStatus_PENDING_REVIEW
+	res = list(2, 2, &pending)
+	if want := []string{"app4"}; !reflect.DeepEqual(ids(res), want) || res.GetTotalCount() != 3 {
+		t.Errorf("page 2 of pending = %v (total %d), want %v of 3", ids(res), res.GetTotalCount(), want)
+	}
+
+	if res = list(1, 500, nil); res.GetLimit() != 100 {
+		t.Errorf("limit 500 served as %d, want it capped at 100", res.GetLimit())
+	}
+
+	invalid := pb.ApplicationStatus(42)
+	if _, err := h.ListPetAdoptionApplications(ctx, &pb.ListPetAdoptionApplicationsRequest{PetId: "pet1", StatusFilter: &invalid}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("ListPetAdoptionApplications() with invalid status filter error = %v, want InvalidArgument", err)
+	}
+}
+
+func TestAdoptionUsecase_ReopenApplication_AdminOnly(t *testing.T) {
+	var recorded *domain.ApplicationStatusChange
+	var published *domain.AdoptionApplication
+	mockRepo := &MockAdoptionRepository{
+		GetAdoptionApplicationByIDFunc: func(ctx context.Context, id string) (*domain.AdoptionApplication, error) {
+			return &domain.AdoptionApplication{ID: id, Status: domain.StatusAppRejected}, nil
+		},
+		ReopenAdoptionApplicationFunc: func(ctx context.Context, id string, change domain.ApplicationStatusChange) (*domain.AdoptionApplication, error) {
+			recorded = &change
+			return &domain.AdoptionApplication{ID: id, Status: change.ToStatus, StatusHistory: []domain.ApplicationStatusChange{change}}, nil
+		},
+	}
+	mockCache := &MockAdoptionCache{
+		DeleteAdoptionApplicationFunc: func(ctx context.Context, id string) error { return nil },
+	}
+	mockPub := &MockAdoptionEventPublisher{
+		PublishAdoptionApplicationStatusUpdatedFunc: func(ctx context.Context, app *domain.AdoptionApplication) error {
+			published = app
+			return nil
+		},
+	}
+	uc := usecase.NewAdoptionUsecase(mockRepo, mockCache, mockPub, usecase.AdoptionPolicy{}, nil)
+
+	_, err := uc.ReopenApplication(context.Background(), "app1", "Applicant appealed", []string{"trusted"})
+	if !errors.Is(err, usecase.ErrReopenForbidden) {
+		t.Fatalf("ReopenApplication() by non-admin error = %v, want %v", err, usecase.ErrReopenForbidden)
+	}
+	if recorded != nil || published != nil {
+		t.Fatal("ReopenApplication() by non-admin must not update or publish")
+	}
+
+	app, err := uc.ReopenApplication(context.Background(), "app1", "Applicant appealed", []string{usecase.RoleAdmin})
+	if err != nil {
+		t.Fatalf("ReopenApplication() by admin error = %v", err)
+	}
+	if app.Status != domain.StatusAppPendingReview {
+		t.Errorf("ReopenApplication() Status = %s, want %s", app.Status, domain.StatusAppPendingReview)
+	}
+	if recorded == nil || recorded.FromStatus != domain.StatusAppRejected || recorded.Reason != "Applicant appealed" {
+		t.Errorf("ReopenApplication() recorded history %+v, want REJECTED -> PENDING_REVIEW with the reason", recorded)
+	}
+	if published == nil || published.Status != domain.StatusAppPendingReview {
+		t.Errorf("ReopenApplication() published %+v, want the reopened application", published)
+	}
+}
+
+func TestAdoptionUsecase_ReopenApplication_RequiresRejectedStatus(t *testing.T) {
+	mockRepo := &MockAdoptionRepository{
+		GetAdoptionApplicationByIDFunc: func(ctx context.Context, id string) (*domain.AdoptionApplication, error) {
+			return &domain.AdoptionApplication{ID: id, Status: domain.StatusAppApproved}, nil
+		},
+	}
+	uc := usecase.NewAdoptionUsecase(mockRepo, &MockAdoptionCache{}, &MockAdoptionEventPublisher{}, usecase.AdoptionPolicy{}, nil)
+
+	_, err := uc.ReopenApplication(context.Background(), "app1", "Applicant appealed", []string{usecase.RoleAdmin})
+	if !errors.Is(err, usecase.ErrApplicationNotRejected) {
+		t.Errorf("ReopenApplication() error = %v, want %v", err, usecase.ErrApplicationNotRejected)
+	}
+}
+
+func TestSelfTest_Run(t *testing.T) {
+	reachable := func(ctx context.Context) error { return nil }
+	unreachable := func(ctx context.Context) error { return errors.New("connection refused") }
+	hanging := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	tests := []struct {
+		name       string
+		checks     []selftest.Check
+		wantFailed []string
+	}{
+		{
+			name:   "all dependencies reachable",
+			checks: []selftest.Check{{Name: "mongodb", Run: reachable}, {Name: "redis", Run: reachable}, {Name: "nats", Run: reachable}},
+		},
+		{
+			name:       "unreachable and hanging dependencies fail",
+			checks:     []selftest.Check{{Name: "mongodb", Run: reachable}, {Name: "redis", Run: unreachable}, {Name: "nats", Run: hanging}},
+			wantFailed: []string{"redis", "nats"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := selftest.Run(context.Background(), 50*time.Millisecond, tt.checks)
+			if len(results) != len(tt.checks) {
+				t.Fatalf("Run() returned %d results, want %d", len(results), len(tt.checks))
+			}
+			var failed []string
+			for _, r := range results {
+				if r.Err != nil {
+					failed = append(failed, r.Name)
+				}
+			}
+			if strings.Join(failed, ",") != strings.Join(tt.wantFailed, ",") {
+				t.Errorf("Run() failed checks = %v, want %v", failed, tt.wantFailed)
+			}
+			if (err != nil) != (len(tt.wantFailed) > 0) {
+				t.Errorf("Run() error = %v, want error: %t", err, len(tt.wantFailed) > 0)
+			}
+		})
+	}
+}
+
+// attachmentRepository stores one application in memory and applies attachment changes to it.
+func attachmentRepository(app *domain.AdoptionApplication) *MockAdoptionRepository {
+	return &MockAdoptionRepository{
+		GetAdoptionApplicationByIDFunc: func(ctx context.Context, id string) (*domain.AdoptionApplication, error) {
+			if id != app.ID {
+				return nil, errors.New("adoption application not found")
+			}
+			copied := *app
+			return &copied, nil
+		},
+		AddApplicationAttachmentFunc: func(ctx context.Context, id string, attachment domain.Attachment, maxAttachments int) (*domain.AdoptionApplication, error) {
+			app.Attachments = append(app.Attachments, attachment)
+			return app, nil
+		},
+		RemoveApplicationAttachmentFunc: func(ctx context.Context, id, url string) (*domain.AdoptionApplication, error) {
+			for i, a := range app.Attachments {
+				if a.URL == url {
+					app.Attachments = append(app.Attachments[:i], app.Attachments[i+1:]...)
+					return app, nil
+				}
+			}
+			return nil, errors.New("attachment not found")
+		},
+	}
+}
+
+func TestAdoptionUsecase_AddAttachment(t *testing.T) {
+	app := &domain.AdoptionApplication{ID: "app1", UserID: "user1"}
+	mockCache := &MockAdoptionCache{DeleteAdoptionApplicationFunc: func(ctx context.Context, id string) error { return nil }}
+	uc := usecase.NewAdoptionUsecase(attachmentRepository(app), mockCache, &MockAdoptionEventPublisher{}, usecase.AdoptionPolicy{MaxAttachments: 2}, nil)
+	ctx := context.Background()
+
+	updated, err := uc.AddAttachment(ctx, "app1", "user1", domain.Attachment{URL: "https://files.example.com/ref.pdf", Filename: "ref.pdf"})
+	if err != nil {
+		t.Fatalf("AddAttachment() error = %v", err)
+	}
+	if len(updated.Attachments) != 1 || updated.Attachments[0].Filename != "ref.pdf" || updated.Attachments[0].UploadedAt.IsZero() {
+		t.Errorf("AddAttachment() attachments = %+v, want ref.pdf with an upload time", updated.Attachments)
+	}
+
+	if _, err := uc.AddAttachment(ctx, "app1", "user2", domain.Attachment{URL: "https://files.example.com/x.pdf", Filename: "x.pdf"}); !errors.Is(err, usecase.ErrNotApplicant) {
+		t.Errorf("AddAttachment() by another user error = %v, want %v", err, usecase.ErrNotApplicant)
+	}
+	if _, err := uc.AddAttachment(ctx, "app1", "user1", domain.Attachment{URL: "ftp://files.example.com/x.pdf", Filename: "x.pdf"}); !errors.Is(err, usecase.ErrInvalidAttachment) {
+		t.Errorf("AddAttachment() with ftp URL error = %v, want %v", err, usecase.ErrInvalidAttachment)
+	}
+}
+
+func TestAdoptionUsecase_AddAttachment_ExceedsCap(t *testing.T) {
+	app := &domain.AdoptionApplication{ID: "app1", UserID: "user1"}
+	mockCache := &MockAdoptionCache{DeleteAdoptionApplicationFunc: func(ctx context.Context, id string) error { return nil }}
+	uc := usecase.NewAdoptionUsecase(attachmentRepository(app), mockCache, &MockAdoptionEventPublisher{}, usecase.AdoptionPolicy{MaxAttachments: 2}, nil)
+	ctx := context.Background()
+
+	for _, name := range []string{"a.pdf", "b.pdf"} {
+		if _, err := uc.AddAttachment(ctx, "app1", "user1", domain.Attachment{URL: "https://files.example.com/" + name, Filename: name}); err != nil {
+			t.Fatalf("AddAttachment(%s) error = %v", name, err)
+		}
+	}
+	if _, err := uc.AddAttachment(ctx, "app1", "user1", domain.Attachment{URL: "https://files.example.com/c.pdf", Filename: "c.pdf"}); !errors.Is(err, usecase.ErrTooManyAttachments) {
+		t.Errorf("AddAttachment() over the cap error = %v, want %v", err, usecase.ErrTooManyAttachments)
+	}
+	if len(app.Attachments) != 2 {
+		t.Errorf("application has %d attachments, want 2", len(app.Attachments))
+	}
+}
+
+func TestAdoptionUsecase_RemoveAttachment(t *testing.T) {
+	app := &domain.AdoptionApplication{ID: "app1", UserID: "user1", Attachments: []domain.Attachment{
+		{URL: "https://files.example.com/a.pdf", Filename: "a.pdf"},
+		{URL: "https://files.example.com/b.pdf", Filename: "b.pdf"},
+	}}
+	mockCache := &MockAdoptionCache{DeleteAdoptionApplicationFunc: func(ctx context.Context, id string) error { return nil }}
+	uc := usecase.NewAdoptionUsecase(attachmentRepository(app), mockCache, &MockAdoptionEventPublisher{}, usecase.AdoptionPolicy{MaxAttachments: 2}, nil)
+	ctx := context.Background()
+
+	updated, err := uc.RemoveAttachment(ctx, "app1", "user1", "https://files.example.com/a.pdf")
+	if err != nil {
+		t.Fatalf("RemoveAttachment() error = %v", err)
+	}
+	if len(updated.Attachments) != 1 || updated.Attachments[0].Filename != "b.pdf" {
+		t.Errorf("RemoveAttachment() attachments = %+v, want only b.pdf", updated.Attachments)
+	}
+	if _, err := uc.RemoveAttachment(ctx, "app1", "user1", "https://files.example.com/a.pdf"); !errors.Is(err, usecase.ErrAttachmentNotFound) {
+		t.Errorf("RemoveAttachment() twice error = %v, want %v", err, usecase.ErrAttachmentNotFound)
+	}
+}
+
+func stringPtr(s string) *string { return &s }
+
+func TestStatusUpdate_OmittedReviewNotesKeepPreviousNotes(t *testing.T) {
+	now := time.Now().UTC()
+
+	statusOnly := repository.StatusUpdate(domain.StatusAppApproved, nil, now)["$set"].(bson.M)
+	if _, ok := statusOnly["review_notes"]; ok {
+		t.Errorf("StatusUpdate() without notes sets review_notes: %v", statusOnly)
+	}
+	if statusOnly["status"] != domain.StatusAppApproved || statusOnly["updated_at"] != now {
+		t.Errorf("StatusUpdate() = %v, want status and updated_at set", statusOnly)
+	}
+
+	cleared := repository.StatusUpdate(domain.StatusAppApproved, stringPtr(""), now)["$set"].(bson.M)
+	if notes, ok := cleared["review_notes"]; !ok || notes != "" {
+		t.Errorf("StatusUpdate() with empty notes = %v, want review_notes cleared", cleared)
+	}
+}
+
+// memoryRateCounter is an in-memory RateCounter that never resets its window.
+type memoryRateCounter struct {
+	counts map[string]int64
+}
+
+func (c *memoryRateCounter) Incr(ctx context.Context, key string, window time.Duration) (int64, error) {
+	c.counts[key]++
+	return c.counts[key], nil
+}
+
+func TestUserRateLimitInterceptor_RejectsWritesBeyondLimit(t *testing.T) {
+	interceptor := server.NewUserRateLimitInterceptor(&memoryRateCounter{counts: map[string]int64{}},
+		server.UserRateLimits{ReadsPerWindow: 100, WritesPerWindow: 2, Window: time.Minute})
+	handlerCalls := 0
+	next := func(ctx context.Context, req interface{}) (interface{}, error) {
+		handlerCalls++
+		return "ok", nil
+	}
+	call := func(userID, method string) error {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-user-id", userID))
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, next)
+		return err
+	}
+	const write = "/adoption.AdoptionService/CreateAdoptionApplication"
+	const read = "/adoption.AdoptionService/GetAdoptionApplication"
+
+	for i := 0; i < 2; i++ {
+		if err := call("user1", write); err != nil {
+			t.Fatalf("write %d error = %v, want nil", i+1, err)
+		}
+	}
+	if err := call("user1", write); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("write beyond limit error = %v, want ResourceExhausted", err)
+	}
+	if err := call("user1", read); err != nil {
+		t.Errorf("read after write limit error = %v, want nil", err)
+	}
+	if err := call("user2", write); err != nil {
+		t.Errorf("write by another user error = %v, want nil", err)
+	}
+	if handlerCalls != 4 {
+		t.Errorf("handler called %d times, want 4", handlerCalls)
+	}
+}
+
+func TestInternalError_MapsContextErrors(t *testing.T) {
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	expiredCtx, cancelExpired := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancelExpired()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  error
+		want codes.Code
+	}{
+		{"cancelled error", context.Background(), context.Canceled, codes.Canceled},
+		{"wrapped cancelled error", context.Background(), fmt.Errorf("could not query: %w", context.Canceled), codes.Canceled},
+		{"cancelled context", cancelledCtx, errors.New("connection reset"), codes.Canceled},
+		{"deadline error", context.Background(), context.DeadlineExceeded, codes.DeadlineExceeded},
+		{"expired context", expiredCtx, errors.New("server selection timeout"), codes.DeadlineExceeded},
+		{"other error", context.Background(), errors.New("boom"), codes.Internal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := handler.InternalError(tt.ctx, tt.err, "Failed")
+			if got := status.Code(err); got != tt.want {
+				t.Errorf("InternalError() code = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdoptionUsecase_SendPendingReminders(t *testing.T) {
+	now := time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC)
+	after := 72 * time.Hour
+	apps := []*domain.AdoptionApplication{
+		{ID: "stale", Status: domain.StatusAppPendingReview, UpdatedAt: now.Add(-96 * time.Hour)},
+		{ID: "recent", Status: domain.StatusAppPendingReview, UpdatedAt: now.Add(-time.Hour)},
+		{ID: "reviewed", Status: domain.StatusAppApproved, UpdatedAt: now.Add(-96 * time.Hour)},
+		{ID: "claimed-elsewhere", Status: domain.StatusAppPendingReview, UpdatedAt: now.Add(-80 * time.Hour)},
+	}
+
+	var gotPendingSince time.Time
+	mockRepo := &MockAdoptionRepository{
+		ListStalePendingApplicationsFunc: func(ctx context.Context, pendingSince time.Time, limit int) ([]*domain.AdoptionApplication, error) {
+			gotPendingSince = pendingSince
+			return apps, nil
+		},
+		ClaimPendingReminderFunc: func(ctx context.Context, id string, pendingSince, at time.Time) (bool, error) {
+			return id != "claimed-elsewhere", nil
+		},
+	}
+	var published []string
+	mockPub := &MockAdoptionEventPublisher{
+		PublishApplicationPendingReminderFunc: func(ctx context.Context, app *domain.AdoptionApplication) error {
+			published = append(published, app.ID)
+			return nil
+		},
+	}
+	uc := usecase.NewAdoptionUsecase(mockRepo, &MockAdoptionCache{}, mockPub, usecase.AdoptionPolicy{PendingReminderAfter: after}, nil)
+
+	sent, err := uc.SendPendingReminders(context.Background(), now)
+	if err != nil {
+		t.Fatalf("SendPendingReminders() error = %v", err)
+	}
+	if want := now.Add(-after); !gotPendingSince.Equal(want) {
+		t.Errorf("SendPendingReminders() listed applications pending since %v, want %v", gotPendingSince, want)
+	}
+	if sent != 1 || !reflect.DeepEqual(published, []string{"stale"}) {
+		t.Errorf("SendPendingReminders() = %d, published %v, want 1 and [stale]", sent, published)
+	}
+
+	// The repository query selects the same applications.
+	filter := repository.StalePendingFilter(now.Add(-after))
+	if filter["status"] != domain.StatusAppPendingReview || !reflect.DeepEqual(filter["updated_at"], bson.M{"$lt": now.Add(-after)}) {
+		t.Errorf("StalePendingFilter() = %v, want PENDING_REVIEW applications updated before %v", filter, now.Add(-after))
+	}
+
+	// Disabled by default: nothing is listed or published.
+	disabled := usecase.NewAdoptionUsecase(&MockAdoptionRepository{}, &MockAdoptionCache{}, &MockAdoptionEventPublisher{}, usecase.AdoptionPolicy{}, nil)
+	if sent, err := disabled.SendPendingReminders(context.Background(), now); sent != 0 || err != nil {
+		t.Errorf("SendPendingReminders() with reminders disabled = (%d, %v), want (0, nil)", sent, err)
+	}
+}
+
+func TestConfig_Summary_IncludesEveryFlag(t *testing.T) {
+	cfg := &config.Config{
+		AutoApproveTrustedUsers:       true,
+		RequireReviewNotesOnRejection: false,
+		MaxInFlightRequests:           42,
+		RedisPassword:                 "s3cret",
+	}
+
+	summary := settings.Summary(cfg.Settings())
+	for _, s := range cfg.Settings() {
+		if want := s.Name + "=" + s.Value; !strings.Contains(summary, want) {
+			t.Errorf("Summary() = %q, missing %q", summary, want)
+		}
+	}
+	for _, want := range []string{"auto_approve_trusted_users=true", "require_review_notes_on_rejection=false", "max_in_flight_requests=42"} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("Summary() = %q, missing %q", summary, want)
+		}
+	}
+	if strings.Contains(summary, "s3cret") {
+		t.Errorf("Summary() = %q, should not contain secrets", summary)
+	}
 }
 
 // TODO: Add more unit tests for AdoptionUsecase methods:
@@ -2,16 +2,32 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log"
+	"net/http"
+	"os"
 	"time"
 
 	// Adjust these import paths to match your project's module path and structure
+	"github.com/zhandarbeks/petstore-final-project/adoption-service/internal/client"
 	"github.com/zhandarbeks/petstore-final-project/adoption-service/internal/config"
 	"github.com/zhandarbeks/petstore-final-project/adoption-service/internal/handler"
+	"github.com/zhandarbeks/petstore-final-project/adoption-service/internal/metrics"
 	"github.com/zhandarbeks/petstore-final-project/adoption-service/internal/publisher"
 	"github.com/zhandarbeks/petstore-final-project/adoption-service/internal/repository"
 	"github.com/zhandarbeks/petstore-final-project/adoption-service/internal/server" // Using the server package
 	"github.com/zhandarbeks/petstore-final-project/adoption-service/internal/usecase"
+	"github.com/zhandarbeks/petstore-final-project/internal/gatewayauth"
+	"github.com/zhandarbeks/petstore-final-project/internal/settings"
+
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc"
+)
+
+// MongoDB database and collection used by the service and by the migrate command.
+const (
+	mongoDBName         = "petstore_adoptions"
+	mongoCollectionName = "applications"
 )
 
 func main() {
@@ -22,11 +38,29 @@ func main() {
 	}
 
 	log.Println("Adoption Service | Configuration loaded.")
+	log.Printf("Adoption Service | Effective settings: %s", settings.Summary(cfg.Settings()))
 	log.Printf("Adoption Service | Server Port: %s", cfg.ServerPort)
 	log.Printf("Adoption Service | MongoDB URI: %s", cfg.MongoURI)
 	log.Printf("Adoption Service | Redis Address: %s, DB: %d", cfg.RedisAddr, cfg.RedisDB)
 	log.Printf("Adoption Service | NATS URL: %s", cfg.NatsURL)
 
+	// "migrate" ensures indexes (and optionally seeds data) and exits instead of serving.
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(cfg, os.Args[2:]); err != nil {
+			log.Fatalf("Adoption Service | FATAL: Migration failed: %v", err)
+		}
+		return
+	}
+
+	// --selftest (or RUN_MODE=selftest) checks the dependencies and exits instead of serving.
+	if isSelfTestMode(cfg, os.Args[1:]) {
+		if err := runSelfTest(cfg); err != nil {
+			log.Fatalf("Adoption Service | FATAL: %v", err)
+		}
+		log.Println("Adoption Service | Self-test passed.")
+		return
+	}
+
 	// Create a main context that can be used to signal shutdown
 	mainCtx, cancelMainCtx := context.WithCancel(context.Background())
 	defer cancelMainCtx()
@@ -37,7 +71,7 @@ func main() {
 	mongoInitCtx, mongoCancel := context.WithTimeout(mainCtx, initTimeout)
 	defer mongoCancel()
 	// Using "petstore_adoptions" as DB name and "applications" as collection name
-	adoptionMongoRepo, err := repository.NewMongoDBAdoptionRepository(mongoInitCtx, cfg.MongoURI, "petstore_adoptions", "applications")
+	adoptionMongoRepo, err := repository.NewMongoDBAdoptionRepository(mongoInitCtx, cfg.MongoURI, mongoDBName, mongoCollectionName, repository.IndexOptions{EnsureIndexes: cfg.EnsureIndexes})
 	if err != nil {
 		log.Fatalf("Adoption Service | FATAL: Failed to initialize MongoDB repository: %v", err)
 	}
@@ -77,31 +111,97 @@ func main() {
 	}
 
 	// 4. Initialize NATS Publisher
-	natsPublisher, err := publisher.NewNATSAdoptionPublisher(cfg.NatsURL)
+	natsPublisher, err := publisher.NewNATSAdoptionPublisher(cfg.NatsURL, cfg.NatsNamespace)
 	if err != nil {
 		log.Fatalf("Adoption Service | FATAL: Failed to initialize NATS publisher: %v", err)
 	}
 	log.Println("Adoption Service | NATS publisher initialized.")
 	defer natsPublisher.Close() // Ensure NATS connection is closed on shutdown
 
+	// 4b. Initialize Pet Service client (used to check pet availability and update pets on decisions)
+	petServiceClient, err := client.NewPetServiceGRPCClient(mainCtx, cfg.PetServiceGRPCURL)
+	if err != nil {
+		log.Fatalf("Adoption Service | FATAL: Failed to create Pet Service client: %v", err)
+	}
+	defer func() {
+		if err := petServiceClient.Close(); err != nil {
+			log.Printf("Adoption Service | Error closing Pet Service client: %v", err)
+		}
+	}()
+
 	// 5. Initialize Adoption Usecase
-	// If your usecase needs clients to other services (e.g., pet-service), initialize them here and pass them in.
-	adoptionUsecase := usecase.NewAdoptionUsecase(adoptionMongoRepo, adoptionRedisCache, natsPublisher)
+	adoptionPolicy := usecase.AdoptionPolicy{
+		AutoApproveTrustedUsers:       cfg.AutoApproveTrustedUsers,
+		AutoApproveAllowlist:          cfg.AutoApproveAllowlist,
+		RequireReviewNotesOnRejection: cfg.RequireReviewNotesOnRejection,
+		VelocityFlagThreshold:         cfg.VelocityFlagThreshold,
+		VelocityWindow:                cfg.VelocityWindow,
+		ReapplyCooldown:               time.Duration(cfg.ReapplyCooldownDays) * 24 * time.Hour,
+		MaxAttachments:                cfg.MaxAttachments,
+		MaxNotesLength:                cfg.MaxNotesLength,
+		SeedCacheOnCreate:             cfg.SeedCacheOnCreate,
+		PendingReminderAfter:          cfg.PendingReminderAfter,
+	}
+	adoptionUsecase := usecase.NewAdoptionUsecase(adoptionMongoRepo, adoptionRedisCache, natsPublisher, adoptionPolicy, petServiceClient)
 	log.Println("Adoption Service | Usecase layer initialized.")
 
+	if cfg.PendingReminderAfter > 0 {
+		go adoptionUsecase.RunPendingReminders(mainCtx, cfg.PendingReminderInterval)
+		log.Printf("Adoption Service | Reminding about applications pending for %s, checking every %s.", cfg.PendingReminderAfter, cfg.PendingReminderInterval)
+	}
+
 	// 6. Initialize Adoption gRPC Handler
 	adoptionGRPCHandler := handler.NewAdoptionHandler(adoptionUsecase)
 	log.Println("Adoption Service | gRPC handler initialized.")
 
 	// 7. Initialize and Start Adoption gRPC Server
 	// This uses the server.NewGRPCServer from adoption-service/internal/server/grpc_server.go (the selected code in Canvas)
-	grpcServer, err := server.NewGRPCServer(cfg.ServerPort, adoptionGRPCHandler)
+	// Per-user request limits, counted in Redis so they hold across instances
+	var rateLimitInterceptors []grpc.UnaryServerInterceptor
+	if cfg.UserRateLimitReads > 0 || cfg.UserRateLimitWrites > 0 {
+		rateLimitRedis := redis.NewClient(&redis.Options{Addr: cfg.RedisAddr, Password: cfg.RedisPassword, DB: cfg.RedisDB})
+		defer rateLimitRedis.Close()
+		rateLimitInterceptors = append(rateLimitInterceptors, server.NewUserRateLimitInterceptor(
+			server.NewRedisRateCounter(rateLimitRedis, "adoption:ratelimit:"),
+			server.UserRateLimits{ReadsPerWindow: cfg.UserRateLimitReads, WritesPerWindow: cfg.UserRateLimitWrites, Window: cfg.UserRateLimitWindow},
+		))
+		log.Printf("Adoption Service | Per-user rate limits: %d reads, %d writes per %s", cfg.UserRateLimitReads, cfg.UserRateLimitWrites, cfg.UserRateLimitWindow)
+	}
+
+	// Only the gateway may name the caller; checked before the rate limits count requests per user.
+	if cfg.GatewayAuthToken == "" {
+		log.Println("Adoption Service | WARNING: GATEWAY_AUTH_TOKEN is not set. Caller identity metadata is trusted from any client, so keep the gRPC port private.")
+	}
+	interceptors := append([]grpc.UnaryServerInterceptor{gatewayauth.UnaryServerInterceptor(cfg.GatewayAuthToken)}, rateLimitInterceptors...)
+	grpcServer, err := server.NewGRPCServer(cfg.ServerPort, adoptionGRPCHandler, cfg.MaxInFlightRequests, interceptors...)
 	if err != nil {
 		log.Fatalf("Adoption Service | FATAL: Failed to create gRPC server: %v", err)
 	}
 
+	// Publish the cache hit/miss counters for scraping
+	var metricsServer *http.Server
+	if cfg.MetricsHTTPPort != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Handler(metrics.CacheHits, metrics.CacheMisses))
+		metricsServer = &http.Server{Addr: cfg.MetricsHTTPPort, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+		go func() {
+			log.Printf("Adoption Service | Metrics HTTP server listening on %s", cfg.MetricsHTTPPort)
+			if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Printf("Adoption Service | Metrics HTTP server error: %v", err)
+			}
+		}()
+	}
+
 	log.Println("Adoption Service | Starting up...")
 	grpcServer.RunWithGracefulShutdown() // This will block until a shutdown signal is received
 
+	if metricsServer != nil {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := metricsServer.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Adoption Service | Error shutting down metrics HTTP server: %v", err)
+		}
+		cancel()
+	}
+
 	log.Println("Adoption Service | Shut down gracefully.")
 }
\ No newline at end of file
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	"github.com/zhandarbeks/petstore-final-project/adoption-service/internal/config"
+	"github.com/zhandarbeks/petstore-final-project/adoption-service/internal/migrate"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// runMigrate implements "adoption-service migrate": it ensures the adoption applications indexes.
+// Run it before starting instances with ENSURE_INDEXES=false.
+func runMigrate(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	timeout := fs.Duration("timeout", 60*time.Second, "overall migration timeout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(cfg.MongoURI))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Disconnect(context.Background()); err != nil {
+			log.Printf("Adoption Service | Error disconnecting MongoDB after migration: %v", err)
+		}
+	}()
+	if err := client.Ping(ctx, nil); err != nil {
+		return err
+	}
+
+	if err := migrate.Run(ctx, client.Database(mongoDBName), mongoCollectionName); err != nil {
+		return err
+	}
+	log.Println("Adoption Service | Migration complete.")
+	return nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/redis/go-redis/v9"
+	"github.com/zhandarbeks/petstore-final-project/adoption-service/internal/config"
+	"github.com/zhandarbeks/petstore-final-project/adoption-service/internal/selftest"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// selftestCheckTimeout bounds each dependency check, so an unreachable host fails the self-test quickly.
+const selftestCheckTimeout = 5 * time.Second
+
+// isSelfTestMode reports whether the service was started with --selftest or RUN_MODE=selftest.
+func isSelfTestMode(cfg *config.Config, args []string) bool {
+	for _, arg := range args {
+		if arg == "--selftest" || arg == "-selftest" {
+			return true
+		}
+	}
+	return cfg.RunMode == config.RunModeSelfTest
+}
+
+// runSelfTest checks every dependency of the service and returns an error if any is unreachable.
+// The adoption service does not call other gRPC services, so there are no downstream checks.
+func runSelfTest(cfg *config.Config) error {
+	_, err := selftest.Run(context.Background(), selftestCheckTimeout, dependencyChecks(cfg))
+	return err
+}
+
+func dependencyChecks(cfg *config.Config) []selftest.Check {
+	return []selftest.Check{
+		{Name: "mongodb", Run: func(ctx context.Context) error {
+			client, err := mongo.Connect(ctx, options.Client().ApplyURI(cfg.MongoURI))
+			if err != nil {
+				return err
+			}
+			defer client.Disconnect(context.Background())
+			return client.Ping(ctx, nil)
+		}},
+		{Name: "redis", Run: func(ctx context.Context) error {
+			client := redis.NewClient(&redis.Options{Addr: cfg.RedisAddr, Password: cfg.RedisPassword, DB: cfg.RedisDB})
+			defer client.Close()
+			return client.Ping(ctx).Err()
+		}},
+		{Name: "nats", Run: func(ctx context.Context) error {
+			timeout := selftestCheckTimeout
+			if deadline, ok := ctx.Deadline(); ok {
+				timeout = time.Until(deadline)
+			}
+			nc, err := nats.Connect(cfg.NatsURL, nats.Timeout(timeout), nats.NoReconnect())
+			if err != nil {
+				return err
+			}
+			defer nc.Close()
+			return nc.FlushWithContext(ctx)
+		}},
+	}
+}
@@ -0,0 +1,135 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	pbPet "github.com/zhandarbeks/petstore-final-project/genprotos/pet"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure" // For connecting without TLS (dev environment)
+	"google.golang.org/grpc/status"
+)
+
+// ErrPetNotFound is returned when the Pet Service does not know the pet.
+var ErrPetNotFound = errors.New("pet not found")
+
+// PetServiceClient defines the calls the adoption-service makes to the Pet gRPC service.
+// This helps in mocking the client for testing purposes.
+type PetServiceClient interface {
+	IsPetAvailableForAdoption(ctx context.Context, petID string) (bool, error)
+	GetPetAdoptionStatus(ctx context.Context, petID string) (pbPet.AdoptionStatus, error)
+	MarkPetAsAdopted(ctx context.Context, petID, adopterUserID, reason string) error
+	MarkPetAsAvailable(ctx context.Context, petID, reason string) error
+	Close() error
+}
+
+// petServiceGRPCClient is the gRPC implementation of PetServiceClient.
+type petServiceGRPCClient struct {
+	conn   *grpc.ClientConn
+	client pbPet.PetServiceClient
+}
+
+// NewPetServiceGRPCClient creates a new gRPC client for the Pet Service.
+// The connection is established lazily, so the adoption-service can start before the pet-service is up.
+func NewPetServiceGRPCClient(ctx context.Context, targetURL string) (PetServiceClient, error) {
+	if targetURL == "" {
+		return nil, fmt.Errorf("pet service target URL cannot be empty")
+	}
+
+	log.Printf("Adoption Service | Setting up Pet Service gRPC client for %s", targetURL)
+	conn, err := grpc.DialContext(
+		ctx,
+		targetURL,
+		grpc.WithTransportCredentials(insecure.NewCredentials()), // No TLS for now
+	)
+	if err != nil {
+		log.Printf("Adoption Service | Failed to set up Pet Service gRPC client for %s: %v", targetURL, err)
+		return nil, fmt.Errorf("did not connect to pet service: %w", err)
+	}
+
+	return &petServiceGRPCClient{
+		conn:   conn,
+		client: pbPet.NewPetServiceClient(conn),
+	}, nil
+}
+
+// IsPetAvailableForAdoption reports whether the pet is currently AVAILABLE. It returns
+// ErrPetNotFound when the Pet Service does not know the pet.
+func (c *petServiceGRPCClient) IsPetAvailableForAdoption(ctx context.Context, petID string) (bool, error) {
+	res, err := c.checkPetAvailability(ctx, petID)
+	if err != nil {
+		return false, err
+	}
+	return res.GetAvailable(), nil
+}
+
+// GetPetAdoptionStatus returns the pet's current adoption status. It returns ErrPetNotFound
+// when the Pet Service does not know the pet.
+func (c *petServiceGRPCClient) GetPetAdoptionStatus(ctx context.Context, petID string) (pbPet.AdoptionStatus, error) {
+	res, err := c.checkPetAvailability(ctx, petID)
+	if err != nil {
+		return pbPet.AdoptionStatus_ADOPTION_STATUS_UNSPECIFIED, err
+	}
+	return res.GetAdoptionStatus(), nil
+}
+
+func (c *petServiceGRPCClient) checkPetAvailability(ctx context.Context, petID string) (*pbPet.CheckPetAvailabilityResponse, error) {
+	callCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	res, err := c.client.CheckPetAvailability(callCtx, &pbPet.CheckPetAvailabilityRequest{PetId: petID})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, ErrPetNotFound
+		}
+		log.Printf("Adoption Service | Error calling Pet Service CheckPetAvailability for PetID %s: %v", petID, err)
+		return nil, fmt.Errorf("pet service CheckPetAvailability call failed: %w", err)
+	}
+	return res, nil
+}
+
+// MarkPetAsAdopted sets the pet's status to ADOPTED by adopterUserID. The reason is kept in the pet's status history.
+func (c *petServiceGRPCClient) MarkPetAsAdopted(ctx context.Context, petID, adopterUserID, reason string) error {
+	return c.updatePetAdoptionStatus(ctx, &pbPet.UpdatePetAdoptionStatusRequest{
+		PetId:         petID,
+		NewStatus:     pbPet.AdoptionStatus_ADOPTED,
+		AdopterUserId: adopterUserID,
+		Reason:        &reason,
+	})
+}
+
+// MarkPetAsAvailable sets the pet's status back to AVAILABLE. The reason is kept in the pet's status history.
+func (c *petServiceGRPCClient) MarkPetAsAvailable(ctx context.Context, petID, reason string) error {
+	return c.updatePetAdoptionStatus(ctx, &pbPet.UpdatePetAdoptionStatusRequest{
+		PetId:     petID,
+		NewStatus: pbPet.AdoptionStatus_AVAILABLE,
+		Reason:    &reason,
+	})
+}
+
+func (c *petServiceGRPCClient) updatePetAdoptionStatus(ctx context.Context, req *pbPet.UpdatePetAdoptionStatusRequest) error {
+	callCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := c.client.UpdatePetAdoptionStatus(callCtx, req); err != nil {
+		if status.Code(err) == codes.NotFound {
+			return ErrPetNotFound
+		}
+		log.Printf("Adoption Service | Error calling Pet Service UpdatePetAdoptionStatus for PetID %s: %v", req.GetPetId(), err)
+		return fmt.Errorf("pet service UpdatePetAdoptionStatus call failed: %w", err)
+	}
+	return nil
+}
+
+// Close closes the gRPC client connection to the Pet Service.
+func (c *petServiceGRPCClient) Close() error {
+	if c.conn != nil {
+		log.Println("Adoption Service | Closing Pet Service gRPC client connection...")
+		return c.conn.Close()
+	}
+	return nil
+}
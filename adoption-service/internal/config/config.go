@@ -4,9 +4,13 @@ import (
 	"log"
 	"os"
 	"strconv"
-	// "time" // Not immediately needed for basic adoption service config
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv" // For loading .env files (optional)
+	"github.com/zhandarbeks/petstore-final-project/internal/eventbus"
+	"github.com/zhandarbeks/petstore-final-project/internal/features"
+	"github.com/zhandarbeks/petstore-final-project/internal/settings"
 )
 
 // Config holds all configuration for the adoption-service
@@ -17,10 +21,60 @@ type Config struct {
 	RedisPassword string // Redis password (if any)
 	RedisDB       int    // Redis database number for adoption caching
 	NatsURL       string // NATS server URL (e.g., "nats://localhost:4222")
+	NatsNamespace eventbus.Namespace // Environment prefix for NATS subjects (NATS_SUBJECT_PREFIX), shared with the consumers
+	AutoApproveTrustedUsers bool // Create applications from "trusted" users directly as APPROVED
+	AutoApproveAllowlist []string // Trusted users auto-approval applies to while AutoApproveTrustedUsers is off (soft launch)
+	RequireReviewNotesOnRejection bool // Reject status updates to REJECTED without review notes
+	VelocityFlagThreshold int           // Flag applications from users who applied for this many other pets within VelocityWindow (0 = off)
+	VelocityWindow        time.Duration // Window for the velocity check
+	ReapplyCooldownDays   int           // Days after a rejection before the user may apply for the same pet again (0 = immediately)
+	MaxInFlightRequests int // Max concurrent gRPC requests before rejecting with ResourceExhausted (0 = unlimited)
+	EnsureIndexes       bool // Create MongoDB indexes on startup; disable when migrations manage them
+	SeedCacheOnCreate   bool // Cache newly created applications so an immediate read sees them
+	RunMode             string // RunModeServe, or RunModeSelfTest to check the dependencies and exit
+	MaxAttachments      int    // Max documents attached to one application (0 = unlimited)
+	MaxNotesLength      int    // Max characters of application and review notes after normalization (0 = unlimited)
+	MetricsHTTPPort     string // Port of the HTTP server publishing /metrics (e.g., ":9090"); empty disables it
+	UserRateLimitReads  int    // Read requests one user may make per UserRateLimitWindow (0 = unlimited)
+	UserRateLimitWrites int    // Write requests one user may make per UserRateLimitWindow (0 = unlimited)
+	UserRateLimitWindow time.Duration // Window of the per-user rate limits
+	GatewayAuthToken    string        // Token the API gateway attaches to its calls; caller identity metadata without it is rejected. Empty trusts every caller
+	PendingReminderAfter    time.Duration // Remind about applications pending review this long without an update (0 = off)
+	PendingReminderInterval time.Duration // How often to look for applications to remind about
+	PetServiceGRPCURL       string        // Pet Service address, for checking pet availability and marking approved pets adopted
+}
+
+// Run modes selected with RUN_MODE.
+const (
+	RunModeServe    = "serve"    // Serve gRPC traffic
+	RunModeSelfTest = "selftest" // Check connectivity to every dependency, then exit 0 or 1
+)
 
-	// Optional: If adoption service needs to directly call other services
-	// UserServiceClientURL string // e.g., "user-service:50051"
-	// PetServiceClientURL  string // e.g., "pet-service:50052"
+// Settings returns the feature flags and key tunables in effect. Secrets and connection strings are left out.
+func (c *Config) Settings() []settings.Setting {
+	return []settings.Setting{
+		{Name: "auto_approve_trusted_users", Value: strconv.FormatBool(c.AutoApproveTrustedUsers)},
+		{Name: "auto_approve_allowlist", Value: strings.Join(c.AutoApproveAllowlist, ",")},
+		{Name: "require_review_notes_on_rejection", Value: strconv.FormatBool(c.RequireReviewNotesOnRejection)},
+		{Name: "ensure_indexes", Value: strconv.FormatBool(c.EnsureIndexes)},
+		{Name: "seed_cache_on_create", Value: strconv.FormatBool(c.SeedCacheOnCreate)},
+		{Name: "max_in_flight_requests", Value: strconv.Itoa(c.MaxInFlightRequests)},
+		{Name: "velocity_flag_threshold", Value: strconv.Itoa(c.VelocityFlagThreshold)},
+		{Name: "velocity_window", Value: c.VelocityWindow.String()},
+		{Name: "reapply_cooldown_days", Value: strconv.Itoa(c.ReapplyCooldownDays)},
+		{Name: "redis_db", Value: strconv.Itoa(c.RedisDB)},
+		{Name: "run_mode", Value: c.RunMode},
+		{Name: "nats_subject_prefix", Value: c.NatsNamespace.Prefix()},
+		{Name: "max_attachments", Value: strconv.Itoa(c.MaxAttachments)},
+		{Name: "max_notes_length", Value: strconv.Itoa(c.MaxNotesLength)},
+		{Name: "metrics_http_port", Value: c.MetricsHTTPPort},
+		{Name: "user_rate_limit_reads", Value: strconv.Itoa(c.UserRateLimitReads)},
+		{Name: "user_rate_limit_writes", Value: strconv.Itoa(c.UserRateLimitWrites)},
+		{Name: "user_rate_limit_window", Value: c.UserRateLimitWindow.String()},
+		{Name: "gateway_auth_enabled", Value: strconv.FormatBool(c.GatewayAuthToken != "")},
+		{Name: "pending_reminder_after", Value: c.PendingReminderAfter.String()},
+		{Name: "pending_reminder_interval", Value: c.PendingReminderInterval.String()},
+	}
 }
 
 // Load loads configuration. It first attempts to load from a .env file (if present),
@@ -39,8 +93,9 @@ func Load() (*Config, error) {
 		RedisAddr:     getEnv("REDIS_ADDR_ADOPTIONS", "localhost:6379"),                       // Default for local
 		RedisPassword: getEnv("REDIS_PASSWORD_ADOPTIONS", ""),                                   // Default to no password
 		NatsURL:       getEnv("NATS_URL", "nats://localhost:4222"),                             // Default for local NATS
-		// UserServiceClientURL: getEnv("USER_SERVICE_GRPC_URL", "user-service:50051"), // Example
-		// PetServiceClientURL:  getEnv("PET_SERVICE_GRPC_URL", "pet-service:50052"),   // Example
+		MetricsHTTPPort: getEnv("METRICS_HTTP_PORT", ":9090"),
+		PetServiceGRPCURL: getEnv("PET_SERVICE_GRPC_URL", "localhost:50052"), // Default for local, Docker will override
+		GatewayAuthToken:  getEnv("GATEWAY_AUTH_TOKEN", ""),
 	}
 
 	redisDBStr := getEnv("REDIS_DB_ADOPTIONS", "2") // Using DB 2 for adoptions to separate
@@ -52,6 +107,135 @@ func Load() (*Config, error) {
 		cfg.RedisDB = redisDBVal
 	}
 
+	maxInFlightStr := getEnv("MAX_IN_FLIGHT_REQUESTS_ADOPTIONS", "100")
+	maxInFlightVal, err := strconv.Atoi(maxInFlightStr)
+	if err != nil || maxInFlightVal < 0 {
+		log.Printf("Adoption Service | Warning: Invalid MAX_IN_FLIGHT_REQUESTS_ADOPTIONS value: '%s'. Using default 100. Error: %v", maxInFlightStr, err)
+		cfg.MaxInFlightRequests = 100
+	} else {
+		cfg.MaxInFlightRequests = maxInFlightVal
+	}
+
+	autoApproveStr := getEnv("AUTO_APPROVE_TRUSTED_USERS", "false")
+	autoApproveVal, err := strconv.ParseBool(autoApproveStr)
+	if err != nil {
+		log.Printf("Adoption Service | Warning: Invalid AUTO_APPROVE_TRUSTED_USERS value: '%s'. Using default false. Error: %v", autoApproveStr, err)
+		autoApproveVal = false
+	}
+	cfg.AutoApproveTrustedUsers = autoApproveVal
+	cfg.AutoApproveAllowlist = features.ParseUserIDs(getEnv("AUTO_APPROVE_ALLOWLIST", ""))
+
+	requireNotesStr := getEnv("REQUIRE_REVIEW_NOTES_ON_REJECTION", "true")
+	requireNotesVal, err := strconv.ParseBool(requireNotesStr)
+	if err != nil {
+		log.Printf("Adoption Service | Warning: Invalid REQUIRE_REVIEW_NOTES_ON_REJECTION value: '%s'. Using default true. Error: %v", requireNotesStr, err)
+		requireNotesVal = true
+	}
+	cfg.RequireReviewNotesOnRejection = requireNotesVal
+
+	velocityThresholdStr := getEnv("VELOCITY_FLAG_THRESHOLD", "5")
+	velocityThresholdVal, err := strconv.Atoi(velocityThresholdStr)
+	if err != nil || velocityThresholdVal < 0 {
+		log.Printf("Adoption Service | Warning: Invalid VELOCITY_FLAG_THRESHOLD value: '%s'. Using default 5. Error: %v", velocityThresholdStr, err)
+		velocityThresholdVal = 5
+	}
+	cfg.VelocityFlagThreshold = velocityThresholdVal
+
+	velocityWindowStr := getEnv("VELOCITY_WINDOW", "24h")
+	velocityWindowVal, err := time.ParseDuration(velocityWindowStr)
+	if err != nil || velocityWindowVal <= 0 {
+		log.Printf("Adoption Service | Warning: Invalid VELOCITY_WINDOW value: '%s'. Using default 24h. Error: %v", velocityWindowStr, err)
+		velocityWindowVal = 24 * time.Hour
+	}
+	cfg.VelocityWindow = velocityWindowVal
+
+	reapplyCooldownStr := getEnv("REAPPLY_COOLDOWN_DAYS", "30")
+	reapplyCooldownVal, err := strconv.Atoi(reapplyCooldownStr)
+	if err != nil || reapplyCooldownVal < 0 {
+		log.Printf("Adoption Service | Warning: Invalid REAPPLY_COOLDOWN_DAYS value: '%s'. Using default 30. Error: %v", reapplyCooldownStr, err)
+		reapplyCooldownVal = 30
+	}
+	cfg.ReapplyCooldownDays = reapplyCooldownVal
+
+	ensureIndexesStr := getEnv("ENSURE_INDEXES", "true")
+	ensureIndexesVal, err := strconv.ParseBool(ensureIndexesStr)
+	if err != nil {
+		log.Printf("Adoption Service | Warning: Invalid ENSURE_INDEXES value: '%s'. Using default true. Error: %v", ensureIndexesStr, err)
+		ensureIndexesVal = true
+	}
+	cfg.EnsureIndexes = ensureIndexesVal
+
+	seedCacheStr := getEnv("SEED_CACHE_ON_CREATE", "true")
+	seedCacheVal, err := strconv.ParseBool(seedCacheStr)
+	if err != nil {
+		log.Printf("Adoption Service | Warning: Invalid SEED_CACHE_ON_CREATE value: '%s'. Using default true. Error: %v", seedCacheStr, err)
+		seedCacheVal = true
+	}
+	cfg.SeedCacheOnCreate = seedCacheVal
+
+	rateLimitReadsStr := getEnv("USER_RATE_LIMIT_READS", "600")
+	rateLimitReadsVal, err := strconv.Atoi(rateLimitReadsStr)
+	if err != nil || rateLimitReadsVal < 0 {
+		log.Printf("Adoption Service | Warning: Invalid USER_RATE_LIMIT_READS value: '%s'. Using default 600. Error: %v", rateLimitReadsStr, err)
+		rateLimitReadsVal = 600
+	}
+	cfg.UserRateLimitReads = rateLimitReadsVal
+
+	rateLimitWritesStr := getEnv("USER_RATE_LIMIT_WRITES", "60")
+	rateLimitWritesVal, err := strconv.Atoi(rateLimitWritesStr)
+	if err != nil || rateLimitWritesVal < 0 {
+		log.Printf("Adoption Service | Warning: Invalid USER_RATE_LIMIT_WRITES value: '%s'. Using default 60. Error: %v", rateLimitWritesStr, err)
+		rateLimitWritesVal = 60
+	}
+	cfg.UserRateLimitWrites = rateLimitWritesVal
+
+	rateLimitWindowStr := getEnv("USER_RATE_LIMIT_WINDOW", "1m")
+	rateLimitWindowVal, err := time.ParseDuration(rateLimitWindowStr)
+	if err != nil || rateLimitWindowVal <= 0 {
+		log.Printf("Adoption Service | Warning: Invalid USER_RATE_LIMIT_WINDOW value: '%s'. Using default 1m. Error: %v", rateLimitWindowStr, err)
+		rateLimitWindowVal = time.Minute
+	}
+	cfg.UserRateLimitWindow = rateLimitWindowVal
+
+	maxAttachmentsStr := getEnv("MAX_APPLICATION_ATTACHMENTS", "5")
+	maxAttachmentsVal, err := strconv.Atoi(maxAttachmentsStr)
+	if err != nil || maxAttachmentsVal < 0 {
+		log.Printf("Adoption Service | Warning: Invalid MAX_APPLICATION_ATTACHMENTS value: '%s'. Using default 5. Error: %v", maxAttachmentsStr, err)
+		maxAttachmentsVal = 5
+	}
+	cfg.MaxAttachments = maxAttachmentsVal
+
+	maxNotesLengthStr := getEnv("MAX_NOTES_LENGTH", "2000")
+	maxNotesLengthVal, err := strconv.Atoi(maxNotesLengthStr)
+	if err != nil || maxNotesLengthVal < 0 {
+		log.Printf("Adoption Service | Warning: Invalid MAX_NOTES_LENGTH value: '%s'. Using default 2000. Error: %v", maxNotesLengthStr, err)
+		maxNotesLengthVal = 2000
+	}
+	cfg.MaxNotesLength = maxNotesLengthVal
+
+	pendingReminderAfterStr := getEnv("PENDING_REMINDER_AFTER", "72h")
+	pendingReminderAfterVal, err := time.ParseDuration(pendingReminderAfterStr)
+	if err != nil || pendingReminderAfterVal < 0 {
+		log.Printf("Adoption Service | Warning: Invalid PENDING_REMINDER_AFTER value: '%s'. Using default 72h. Error: %v", pendingReminderAfterStr, err)
+		pendingReminderAfterVal = 72 * time.Hour
+	}
+	cfg.PendingReminderAfter = pendingReminderAfterVal
+
+	pendingReminderIntervalStr := getEnv("PENDING_REMINDER_INTERVAL", "1h")
+	pendingReminderIntervalVal, err := time.ParseDuration(pendingReminderIntervalStr)
+	if err != nil || pendingReminderIntervalVal <= 0 {
+		log.Printf("Adoption Service | Warning: Invalid PENDING_REMINDER_INTERVAL value: '%s'. Using default 1h. Error: %v", pendingReminderIntervalStr, err)
+		pendingReminderIntervalVal = time.Hour
+	}
+	cfg.PendingReminderInterval = pendingReminderIntervalVal
+
+	runMode := strings.ToLower(strings.TrimSpace(getEnv("RUN_MODE", RunModeServe)))
+	if runMode != RunModeServe && runMode != RunModeSelfTest {
+		log.Printf("Adoption Service | Warning: Invalid RUN_MODE value: '%s'. Using default %s.", runMode, RunModeServe)
+		runMode = RunModeServe
+	}
+	cfg.RunMode = runMode
+
 	// Critical validations
 	if cfg.MongoURI == "" {
 		log.Fatal("Adoption Service | FATAL: MONGO_URI_ADOPTIONS environment variable is required.")
@@ -62,6 +246,12 @@ func Load() (*Config, error) {
 	if cfg.NatsURL == "" {
 		log.Fatal("Adoption Service | FATAL: NATS_URL environment variable is required.")
 	}
+	// A bad prefix would make this environment miss its events or read another's, so don't start.
+	natsNamespace, err := eventbus.NewNamespace(getEnv("NATS_SUBJECT_PREFIX", ""))
+	if err != nil {
+		log.Fatalf("Adoption Service | FATAL: NATS_SUBJECT_PREFIX is invalid: %v", err)
+	}
+	cfg.NatsNamespace = natsNamespace
 
 	return cfg, nil
 }
@@ -26,10 +26,37 @@ type AdoptionApplication struct {
 	Status             ApplicationStatus `bson:"status" json:"status"`
 	ApplicationNotes   string            `bson:"application_notes,omitempty" json:"application_notes,omitempty"` // Notes from the applicant
 	ReviewNotes        string            `bson:"review_notes,omitempty" json:"review_notes,omitempty"`          // Notes from the admin/reviewer
+	Flagged            bool              `bson:"flagged,omitempty" json:"flagged,omitempty"`                    // Applicant applied for many pets in a short window; review with care
+	StatusHistory      []ApplicationStatusChange `bson:"status_history,omitempty" json:"status_history,omitempty"` // Administrative status changes, oldest first
+	Attachments        []Attachment      `bson:"attachments,omitempty" json:"attachments,omitempty"` // Documents supplied by the applicant, e.g. references
+	ReminderSentAt     *time.Time        `bson:"reminder_sent_at,omitempty" json:"reminder_sent_at,omitempty"` // Last pending review reminder; does not change UpdatedAt
 	CreatedAt          time.Time         `bson:"created_at" json:"created_at"`
 	UpdatedAt          time.Time         `bson:"updated_at" json:"updated_at"`
 }
 
+// ApplicationStatusChange records an administrative status change, such as reopening a rejected application.
+type ApplicationStatusChange struct {
+	FromStatus ApplicationStatus `bson:"from_status" json:"from_status"`
+	ToStatus   ApplicationStatus `bson:"to_status" json:"to_status"`
+	Reason     string            `bson:"reason" json:"reason"`
+	ChangedAt  time.Time         `bson:"changed_at" json:"changed_at"`
+}
+
+// Attachment is a document the applicant uploaded to object storage and attached to the application.
+type Attachment struct {
+	URL        string    `bson:"url" json:"url"`
+	Filename   string    `bson:"filename" json:"filename"`
+	UploadedAt time.Time `bson:"uploaded_at" json:"uploaded_at"`
+}
+
+// PetApplicationStats summarizes the applications received for one pet.
+type PetApplicationStats struct {
+	PetID          string
+	CountsByStatus map[ApplicationStatus]int64
+	Total          int64
+	LastAppliedAt  time.Time // Zero if the pet has no applications
+}
+
 // PrepareForCreate sets the CreatedAt, UpdatedAt timestamps and default status for a new application.
 func (app *AdoptionApplication) PrepareForCreate() {
 	now := time.Now().UTC()
@@ -4,13 +4,16 @@ import (
 	"context"
 	"errors" // This will be used now, or removed if not. Let's check usage.
 	"log"
+	"strings"
 	// "time" // Removed, as direct time operations might not be needed here if timestamppb handles all.
 
 	"github.com/zhandarbeks/petstore-final-project/adoption-service/internal/domain"   // Adjust import path
 	"github.com/zhandarbeks/petstore-final-project/adoption-service/internal/usecase" // Adjust import path
 	pb "github.com/zhandarbeks/petstore-final-project/genprotos/adoption"            // Adjust import path to your generated protos
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb" // For converting time.Time to/from protobuf Timestamp
 )
@@ -29,6 +32,42 @@ func NewAdoptionHandler(uc usecase.AdoptionUsecase) *AdoptionHandler {
 	return &AdoptionHandler{usecase: uc}
 }
 
+// userRolesMetadataKey is the incoming gRPC metadata key carrying the caller's roles (comma-separated).
+const userRolesMetadataKey = "x-user-roles"
+
+// userIDMetadataKey is the incoming gRPC metadata key carrying the authenticated caller's user ID.
+// The gateway sets it and the roles; the server's gatewayauth interceptor rejects them from anyone else.
+const userIDMetadataKey = "x-user-id"
+
+// callerUserID returns the authenticated caller's user ID from incoming gRPC metadata, or "".
+func callerUserID(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if ids := md.Get(userIDMetadataKey); len(ids) > 0 {
+		return strings.TrimSpace(ids[0])
+	}
+	return ""
+}
+
+// rolesFromContext extracts the caller's roles from incoming gRPC metadata.
+func rolesFromContext(ctx context.Context) []string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil
+	}
+	var roles []string
+	for _, v := range md.Get(userRolesMetadataKey) {
+		for _, role := range strings.Split(v, ",") {
+			if role = strings.TrimSpace(role); role != "" {
+				roles = append(roles, role)
+			}
+		}
+	}
+	return roles
+}
+
 // --- Helper Functions for Type Conversion ---
 
 func pbApplicationStatusToDomain(pbStatus pb.ApplicationStatus) domain.ApplicationStatus {
@@ -75,6 +114,25 @@ func domainAdoptionApplicationToPb(da *domain.AdoptionApplication) *pb.AdoptionA
 		updatedAtProto = timestamppb.New(da.UpdatedAt)
 	}
 
+	var history []*pb.ApplicationStatusChange
+	for _, change := range da.StatusHistory {
+		history = append(history, &pb.ApplicationStatusChange{
+			FromStatus: domainApplicationStatusToPb(change.FromStatus),
+			ToStatus:   domainApplicationStatusToPb(change.ToStatus),
+			Reason:     change.Reason,
+			ChangedAt:  timestamppb.New(change.ChangedAt),
+		})
+	}
+
+	var attachments []*pb.Attachment
+	for _, attachment := range da.Attachments {
+		attachments = append(attachments, &pb.Attachment{
+			Url:        attachment.URL,
+			Filename:   attachment.Filename,
+			UploadedAt: timestamppb.New(attachment.UploadedAt),
+		})
+	}
+
 	return &pb.AdoptionApplication{
 		Id:                da.ID,
 		UserId:            da.UserID,
@@ -82,8 +140,11 @@ func domainAdoptionApplicationToPb(da *domain.AdoptionApplication) *pb.AdoptionA
 		Status:            domainApplicationStatusToPb(da.Status),
 		ApplicationNotes:  da.ApplicationNotes,
 		ReviewNotes:       da.ReviewNotes,
+		Flagged:           da.Flagged,
 		CreatedAt:         createdAtProto,
 		UpdatedAt:         updatedAtProto,
+		StatusHistory:     history,
+		Attachments:       attachments,
 	}
 }
 
@@ -92,34 +153,58 @@ func domainAdoptionApplicationToPb(da *domain.AdoptionApplication) *pb.AdoptionA
 func (h *AdoptionHandler) CreateAdoptionApplication(ctx context.Context, req *pb.CreateAdoptionApplicationRequest) (*pb.AdoptionApplicationResponse, error) {
 	log.Printf("Adoption Service | gRPC CreateAdoptionApplication request received for UserID: %s, PetID: %s", req.GetUserId(), req.GetPetId())
 
-	if req.GetUserId() == "" || req.GetPetId() == "" {
-		return nil, status.Errorf(codes.InvalidArgument, "User ID and Pet ID are required")
-	}
-
 	reqData := usecase.CreateAdoptionApplicationRequestData{
 		UserID:           req.GetUserId(),
 		PetID:            req.GetPetId(),
 		ApplicationNotes: req.GetApplicationNotes(),
 	}
+	// The roles are the caller's, so they only count when the caller applies for themselves.
+	if callerID := callerUserID(ctx); callerID != "" && callerID == req.GetUserId() {
+		reqData.CallerRoles = rolesFromContext(ctx)
+	}
 
 	createdApp, err := h.usecase.CreateAdoptionApplication(ctx, reqData)
 	if err != nil {
 		log.Printf("Adoption Service | Error during CreateAdoptionApplication usecase call: %v", err)
-		// Example: Map specific domain errors if needed
-		// Using errors.Is for better error checking if usecase returns wrapped errors or defined error types.
-		if err.Error() == "pet is not available for adoption" { // Assuming usecase might return this specific string
+		var validationErr *usecase.ValidationError
+		if errors.As(err, &validationErr) {
+			return nil, validationStatus(validationErr)
+		}
+		if errors.Is(err, usecase.ErrPetNotAvailable) {
 			return nil, status.Errorf(codes.FailedPrecondition, err.Error())
 		}
-		if err.Error() == "active adoption application for this pet by this user already exists" { // Assuming usecase might return this
+		if errors.Is(err, usecase.ErrPetNotFound) {
+			return nil, status.Errorf(codes.NotFound, "Pet not found")
+		}
+		if errors.Is(err, usecase.ErrDuplicateApplication) {
 			return nil, status.Errorf(codes.AlreadyExists, err.Error())
 		}
-		return nil, status.Errorf(codes.Internal, "Failed to create adoption application: %v", err)
+		if errors.Is(err, usecase.ErrReapplyCooldown) {
+			return nil, status.Errorf(codes.FailedPrecondition, err.Error())
+		}
+		return nil, InternalError(ctx, err, "Failed to create adoption application")
 	}
 
 	log.Printf("Adoption Service | Adoption application created successfully via gRPC: ID %s", createdApp.ID)
 	return &pb.AdoptionApplicationResponse{Application: domainAdoptionApplicationToPb(createdApp)}, nil
 }
 
+// validationStatus maps a validation error to InvalidArgument with a BadRequest detail listing
+// each invalid field, so the gateway can report field-level errors.
+func validationStatus(validationErr *usecase.ValidationError) error {
+	st := status.New(codes.InvalidArgument, validationErr.Error())
+	badRequest := &errdetails.BadRequest{}
+	for _, v := range validationErr.Violations {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{Field: v.Field, Description: v.Description})
+	}
+	detailed, err := st.WithDetails(badRequest)
+	if err != nil {
+		log.Printf("Adoption Service | Error attaching field violations to status: %v", err)
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
 func (h *AdoptionHandler) GetAdoptionApplication(ctx context.Context, req *pb.GetAdoptionApplicationRequest) (*pb.AdoptionApplicationResponse, error) {
 	log.Printf("Adoption Service | gRPC GetAdoptionApplication request received for ID: %s", req.GetApplicationId())
 
@@ -133,7 +218,7 @@ func (h *AdoptionHandler) GetAdoptionApplication(ctx context.Context, req *pb.Ge
 		if err.Error() == "adoption application not found" { // Match error string from usecase/repo
 			return nil, status.Errorf(codes.NotFound, "Adoption application not found")
 		}
-		return nil, status.Errorf(codes.Internal, "Failed to get adoption application: %v", err)
+		return nil, InternalError(ctx, err, "Failed to get adoption application")
 	}
 
 	log.Printf("Adoption Service | Adoption application retrieved successfully via gRPC: ID %s", app.ID)
@@ -154,7 +239,7 @@ func (h *AdoptionHandler) UpdateAdoptionApplicationStatus(ctx context.Context, r
 
 	reqData := usecase.UpdateAdoptionApplicationStatusRequestData{
 		NewStatus:   domainStatus,
-		ReviewNotes: req.GetReviewNotes(),
+		ReviewNotes: req.ReviewNotes, // nil when the request omits review_notes
 	}
 
 	updatedApp, err := h.usecase.UpdateAdoptionApplicationStatus(ctx, req.GetApplicationId(), reqData)
@@ -170,13 +255,142 @@ func (h *AdoptionHandler) UpdateAdoptionApplicationStatus(ctx context.Context, r
 		if errors.Is(err, errors.New("adopter user ID is required when setting status to ADOPTED")) { // Assuming usecase returns this
 			return nil, status.Errorf(codes.InvalidArgument, err.Error())
 		}
-		return nil, status.Errorf(codes.Internal, "Failed to update application status: %v", err)
+		if errors.Is(err, usecase.ErrReviewNotesRequired) {
+			return nil, status.Errorf(codes.InvalidArgument, err.Error())
+		}
+		return nil, InternalError(ctx, err, "Failed to update application status")
 	}
 
 	log.Printf("Adoption Service | Adoption application status updated successfully via gRPC: ID %s to %s", updatedApp.ID, updatedApp.Status)
 	return &pb.AdoptionApplicationResponse{Application: domainAdoptionApplicationToPb(updatedApp)}, nil
 }
 
+func (h *AdoptionHandler) ReopenApplication(ctx context.Context, req *pb.ReopenApplicationRequest) (*pb.AdoptionApplicationResponse, error) {
+	log.Printf("Adoption Service | gRPC ReopenApplication request received for ID: %s", req.GetApplicationId())
+
+	if req.GetApplicationId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Application ID is required")
+	}
+
+	reopenedApp, err := h.usecase.ReopenApplication(ctx, req.GetApplicationId(), req.GetReason(), rolesFromContext(ctx))
+	if err != nil {
+		log.Printf("Adoption Service | Error during ReopenApplication usecase call for ID %s: %v", req.GetApplicationId(), err)
+		if errors.Is(err, usecase.ErrReopenForbidden) {
+			return nil, status.Errorf(codes.PermissionDenied, err.Error())
+		}
+		if errors.Is(err, usecase.ErrReopenReasonRequired) {
+			return nil, status.Errorf(codes.InvalidArgument, err.Error())
+		}
+		if errors.Is(err, usecase.ErrApplicationNotRejected) || strings.Contains(err.Error(), "no longer rejected") {
+			return nil, status.Errorf(codes.FailedPrecondition, err.Error())
+		}
+		if err.Error() == "adoption application not found" {
+			return nil, status.Errorf(codes.NotFound, "Adoption application not found")
+		}
+		return nil, InternalError(ctx, err, "Failed to reopen adoption application")
+	}
+
+	log.Printf("Adoption Service | Adoption application reopened successfully via gRPC: ID %s", reopenedApp.ID)
+	return &pb.AdoptionApplicationResponse{Application: domainAdoptionApplicationToPb(reopenedApp)}, nil
+}
+
+func (h *AdoptionHandler) AddApplicationAttachment(ctx context.Context, req *pb.AddApplicationAttachmentRequest) (*pb.AdoptionApplicationResponse, error) {
+	log.Printf("Adoption Service | gRPC AddApplicationAttachment request received for ID: %s", req.GetApplicationId())
+
+	if req.GetApplicationId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Application ID is required")
+	}
+
+	attachment := domain.Attachment{URL: req.GetUrl(), Filename: req.GetFilename()}
+	updatedApp, err := h.usecase.AddAttachment(ctx, req.GetApplicationId(), callerUserID(ctx), attachment)
+	if err != nil {
+		return nil, attachmentError(ctx, err, "Failed to add attachment")
+	}
+	return &pb.AdoptionApplicationResponse{Application: domainAdoptionApplicationToPb(updatedApp)}, nil
+}
+
+func (h *AdoptionHandler) RemoveApplicationAttachment(ctx context.Context, req *pb.RemoveApplicationAttachmentRequest) (*pb.AdoptionApplicationResponse, error) {
+	log.Printf("Adoption Service | gRPC RemoveApplicationAttachment request received for ID: %s", req.GetApplicationId())
+
+	if req.GetApplicationId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Application ID is required")
+	}
+
+	updatedApp, err := h.usecase.RemoveAttachment(ctx, req.GetApplicationId(), callerUserID(ctx), req.GetUrl())
+	if err != nil {
+		return nil, attachmentError(ctx, err, "Failed to remove attachment")
+	}
+	return &pb.AdoptionApplicationResponse{Application: domainAdoptionApplicationToPb(updatedApp)}, nil
+}
+
+// CancelUserApplications cancels all pending applications of the calling user.
+func (h *AdoptionHandler) CancelUserApplications(ctx context.Context, req *pb.CancelUserApplicationsRequest) (*pb.CancelUserApplicationsResponse, error) {
+	log.Printf("Adoption Service | gRPC CancelUserApplications request received for UserID: %s", req.GetUserId())
+
+	if req.GetUserId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "User ID is required")
+	}
+
+	cancelled, err := h.usecase.CancelUserApplications(ctx, req.GetUserId(), callerUserID(ctx))
+	if err != nil {
+		log.Printf("Adoption Service | Error during CancelUserApplications usecase call for UserID %s: %v", req.GetUserId(), err)
+		if errors.Is(err, usecase.ErrCancelForbidden) {
+			return nil, status.Errorf(codes.PermissionDenied, err.Error())
+		}
+		return nil, InternalError(ctx, err, "Failed to cancel adoption applications")
+	}
+	return &pb.CancelUserApplicationsResponse{CancelledCount: int32(cancelled)}, nil
+}
+
+// CancelAdoptionApplication withdraws one pending application of the calling user.
+func (h *AdoptionHandler) CancelAdoptionApplication(ctx context.Context, req *pb.CancelAdoptionApplicationRequest) (*pb.AdoptionApplicationResponse, error) {
+	log.Printf("Adoption Service | gRPC CancelAdoptionApplication request received for ID: %s, UserID: %s", req.GetApplicationId(), req.GetUserId())
+
+	if req.GetApplicationId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Application ID is required")
+	}
+	if req.GetUserId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "User ID is required")
+	}
+
+	cancelledApp, err := h.usecase.CancelAdoptionApplication(ctx, req.GetApplicationId(), req.GetUserId(), callerUserID(ctx))
+	if err != nil {
+		log.Printf("Adoption Service | Error during CancelAdoptionApplication usecase call for ID %s: %v", req.GetApplicationId(), err)
+		if errors.Is(err, usecase.ErrCancelForbidden) {
+			return nil, status.Errorf(codes.PermissionDenied, err.Error())
+		}
+		if errors.Is(err, usecase.ErrApplicationDecided) {
+			return nil, status.Errorf(codes.FailedPrecondition, err.Error())
+		}
+		if err.Error() == "adoption application not found" {
+			return nil, status.Errorf(codes.NotFound, "Adoption application not found")
+		}
+		return nil, InternalError(ctx, err, "Failed to cancel adoption application")
+	}
+
+	log.Printf("Adoption Service | Adoption application cancelled successfully via gRPC: ID %s", cancelledApp.ID)
+	return &pb.AdoptionApplicationResponse{Application: domainAdoptionApplicationToPb(cancelledApp)}, nil
+}
+
+// attachmentError maps errors of the attachment usecase methods to gRPC status errors.
+func attachmentError(ctx context.Context, err error, message string) error {
+	log.Printf("Adoption Service | %s: %v", message, err)
+	switch {
+	case errors.Is(err, usecase.ErrInvalidAttachment):
+		return status.Errorf(codes.InvalidArgument, err.Error())
+	case errors.Is(err, usecase.ErrNotApplicant):
+		return status.Errorf(codes.PermissionDenied, err.Error())
+	case errors.Is(err, usecase.ErrTooManyAttachments):
+		return status.Errorf(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, usecase.ErrAttachmentNotFound):
+		return status.Errorf(codes.NotFound, err.Error())
+	case err.Error() == "adoption application not found":
+		return status.Errorf(codes.NotFound, "Adoption application not found")
+	default:
+		return InternalError(ctx, err, message)
+	}
+}
+
 func (h *AdoptionHandler) ListUserAdoptionApplications(ctx context.Context, req *pb.ListUserAdoptionApplicationsRequest) (*pb.ListAdoptionApplicationsResponse, error) {
 	log.Printf("Adoption Service | gRPC ListUserAdoptionApplications request for UserID: %s, Page: %d, Limit: %d, StatusFilter: %s",
 		req.GetUserId(), req.GetPage(), req.GetLimit(), req.GetStatusFilter().String())
@@ -203,7 +417,7 @@ func (h *AdoptionHandler) ListUserAdoptionApplications(ctx context.Context, req
 	domainApps, totalCount, err := h.usecase.ListUserAdoptionApplications(ctx, req.GetUserId(), page, limit, statusFilter)
 	if err != nil {
 		log.Printf("Adoption Service | Error during ListUserAdoptionApplications usecase call: %v", err)
-		return nil, status.Errorf(codes.Internal, "Failed to list user adoption applications: %v", err)
+		return nil, InternalError(ctx, err, "Failed to list user adoption applications")
 	}
 
 	pbApps := make([]*pb.AdoptionApplication, len(domainApps))
@@ -218,4 +432,113 @@ func (h *AdoptionHandler) ListUserAdoptionApplications(ctx context.Context, req
 		Page:         int32(page),
 		Limit:        int32(limit),
 	}, nil
-}
\ No newline at end of file
+}
+
+// ListPetAdoptionApplications lists the applications for a pet. The API gateway serves it to the
+// pet's lister and admins; the notification-service uses it to find a pet's applicants.
+func (h *AdoptionHandler) ListPetAdoptionApplications(ctx context.Context, req *pb.ListPetAdoptionApplicationsRequest) (*pb.ListAdoptionApplicationsResponse, error) {
+	if req.GetPetId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Pet ID is required")
+	}
+
+	page := int(req.GetPage())
+	limit := int(req.GetLimit())
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > maxPetApplicationsPageSize {
+		limit = maxPetApplicationsPageSize
+	}
+
+	var statusFilter *domain.ApplicationStatus
+	if req.GetStatusFilter() != pb.ApplicationStatus_APPLICATION_STATUS_UNSPECIFIED {
+		ds := pbApplicationStatusToDomain(req.GetStatusFilter())
+		if ds == domain.StatusAppUnspecified {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid status filter value provided")
+		}
+		statusFilter = &ds
+	}
+
+	domainApps, totalCount, err := h.usecase.ListPetAdoptionApplications(ctx, req.GetPetId(), page, limit, statusFilter)
+	if err != nil {
+		log.Printf("Adoption Service | Error during ListPetAdoptionApplications usecase call: %v", err)
+		return nil, InternalError(ctx, err, "Failed to list pet adoption applications")
+	}
+
+	pbApps := make([]*pb.AdoptionApplication, len(domainApps))
+	for i, da := range domainApps {
+		pbApps[i] = domainAdoptionApplicationToPb(da)
+	}
+	return &pb.ListAdoptionApplicationsResponse{
+		Applications: pbApps,
+		TotalCount:   int32(totalCount),
+		Page:         int32(page),
+		Limit:        int32(limit),
+	}, nil
+}
+
+// maxPetApplicationsPageSize caps the page size of ListPetAdoptionApplications.
+const maxPetApplicationsPageSize = 100
+
+func domainPetApplicationStatsToPb(stats *domain.PetApplicationStats) *pb.PetApplicationStats {
+	pbStats := &pb.PetApplicationStats{
+		PetId:             stats.PetID,
+		TotalApplications: int32(stats.Total),
+		PendingReview:     int32(stats.CountsByStatus[domain.StatusAppPendingReview]),
+		Approved:          int32(stats.CountsByStatus[domain.StatusAppApproved]),
+		Rejected:          int32(stats.CountsByStatus[domain.StatusAppRejected]),
+		CancelledByUser:   int32(stats.CountsByStatus[domain.StatusAppCancelledByUser]),
+	}
+	if !stats.LastAppliedAt.IsZero() {
+		pbStats.LastAppliedAt = timestamppb.New(stats.LastAppliedAt)
+	}
+	return pbStats
+}
+
+// maxCountPetIDs caps how many pets one CountPetApplications request may ask about.
+const maxCountPetIDs = 100
+
+// CountPetApplications returns how many applications each of the requested pets received.
+func (h *AdoptionHandler) CountPetApplications(ctx context.Context, req *pb.CountPetApplicationsRequest) (*pb.CountPetApplicationsResponse, error) {
+	log.Printf("Adoption Service | gRPC CountPetApplications request received for %d pets", len(req.GetPetIds()))
+
+	if len(req.GetPetIds()) > maxCountPetIDs {
+		return nil, status.Errorf(codes.InvalidArgument, "At most %d pet IDs can be counted at once", maxCountPetIDs)
+	}
+	for _, petID := range req.GetPetIds() {
+		if petID == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "Pet IDs must not be empty")
+		}
+	}
+
+	counts, err := h.usecase.CountPetApplications(ctx, req.GetPetIds())
+	if err != nil {
+		log.Printf("Adoption Service | Error during CountPetApplications usecase call: %v", err)
+		return nil, InternalError(ctx, err, "Failed to count pet applications")
+	}
+
+	pbCounts := make(map[string]int32, len(counts))
+	for petID, count := range counts {
+		pbCounts[petID] = int32(count)
+	}
+	return &pb.CountPetApplicationsResponse{Counts: pbCounts}, nil
+}
+
+func (h *AdoptionHandler) GetPetApplicationStats(ctx context.Context, req *pb.GetPetApplicationStatsRequest) (*pb.PetApplicationStatsResponse, error) {
+	log.Printf("Adoption Service | gRPC GetPetApplicationStats request received for PetID: %s", req.GetPetId())
+
+	if req.GetPetId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Pet ID is required")
+	}
+
+	stats, err := h.usecase.GetPetApplicationStats(ctx, req.GetPetId())
+	if err != nil {
+		log.Printf("Adoption Service | Error during GetPetApplicationStats usecase call for PetID %s: %v", req.GetPetId(), err)
+		return nil, InternalError(ctx, err, "Failed to get pet application stats")
+	}
+
+	return &pb.PetApplicationStatsResponse{Stats: domainPetApplicationStatsToPb(stats)}, nil
+}
@@ -8,23 +8,28 @@ import (
 
 	"github.com/nats-io/nats.go"
 	"github.com/zhandarbeks/petstore-final-project/adoption-service/internal/domain" // Adjust import path
+	"github.com/zhandarbeks/petstore-final-project/internal/eventbus"
 )
 
 // AdoptionEventPublisher defines the interface for publishing adoption-related events.
 type AdoptionEventPublisher interface {
 	PublishAdoptionApplicationCreated(ctx context.Context, app *domain.AdoptionApplication) error
 	PublishAdoptionApplicationStatusUpdated(ctx context.Context, app *domain.AdoptionApplication) error
+	PublishApplicantFlagged(ctx context.Context, app *domain.AdoptionApplication, recentPetCount int) error
+	PublishApplicationPendingReminder(ctx context.Context, app *domain.AdoptionApplication) error
 	Close()
 }
 
 // natsAdoptionPublisher is the NATS implementation of AdoptionEventPublisher.
 type natsAdoptionPublisher struct {
-	nc *nats.Conn // NATS connection
+	nc        *nats.Conn         // NATS connection
+	namespace eventbus.Namespace // Environment prefix applied to every subject
 	// js nats.JetStreamContext // Uncomment if using NATS JetStream
 }
 
-// NewNATSAdoptionPublisher creates a new NATS publisher for adoption events.
-func NewNATSAdoptionPublisher(natsURL string) (AdoptionEventPublisher, error) {
+// NewNATSAdoptionPublisher creates a new NATS publisher for adoption events, published on the
+// subjects of the given namespace.
+func NewNATSAdoptionPublisher(natsURL string, namespace eventbus.Namespace) (AdoptionEventPublisher, error) {
 	nc, err := nats.Connect(natsURL, nats.Timeout(5*time.Second), nats.RetryOnFailedConnect(true), nats.MaxReconnects(3))
 	if err != nil {
 		log.Printf("Adoption Service | Error connecting to NATS at %s: %v", natsURL, err)
@@ -44,21 +49,21 @@ func NewNATSAdoptionPublisher(natsURL string) (AdoptionEventPublisher, error) {
 
 		// Example: Ensure stream exists (idempotent)
 		// _, err = js.AddStream(&nats.StreamConfig{
-		// 	Name:     "ADOPTIONS",
-		// 	Subjects: []string{"adoption.created", "adoption.status.updated"},
+		// 	Name:     namespace.Stream(eventbus.StreamAdoptions),
+		// 	Subjects: []string{namespace.Subject(eventbus.SubjectApplicationCreated), namespace.Subject(eventbus.SubjectApplicationStatusUpdated)},
 		// })
 		// if err != nil {
-		// 	log.Printf("Adoption Service | Error adding JetStream stream 'ADOPTIONS': %v", err)
+		// 	log.Printf("Adoption Service | Error adding JetStream stream '%s': %v", namespace.Stream(eventbus.StreamAdoptions), err)
 		// 	// Decide if this is a fatal error or just a warning
 		// }
 	*/
 
-	return &natsAdoptionPublisher{nc: nc /*, js: js */}, nil
+	return &natsAdoptionPublisher{nc: nc, namespace: namespace /*, js: js */}, nil
 }
 
 // PublishAdoptionApplicationCreated publishes an event when a new adoption application is created.
 func (p *natsAdoptionPublisher) PublishAdoptionApplicationCreated(ctx context.Context, app *domain.AdoptionApplication) error {
-	subject := "adoption.application.created" // Define your NATS subject
+	subject := p.namespace.Subject(eventbus.SubjectApplicationCreated)
 	eventData := map[string]interface{}{
 		"event_type":     "AdoptionApplicationCreated",
 		"application_id": app.ID,
@@ -66,6 +71,7 @@ func (p *natsAdoptionPublisher) PublishAdoptionApplicationCreated(ctx context.Co
 		"pet_id":         app.PetID,
 		"status":         app.Status,
 		"applied_at":     app.CreatedAt,
+		"published_at":   time.Now().UTC(), // Lets consumers measure end-to-end latency
 	}
 
 	payload, err := json.Marshal(eventData)
@@ -94,15 +100,17 @@ func (p *natsAdoptionPublisher) PublishAdoptionApplicationCreated(ctx context.Co
 
 // PublishAdoptionApplicationStatusUpdated publishes an event when an adoption application's status changes.
 func (p *natsAdoptionPublisher) PublishAdoptionApplicationStatusUpdated(ctx context.Context, app *domain.AdoptionApplication) error {
-	subject := "adoption.application.status.updated" // Define your NATS subject
+	subject := p.namespace.Subject(eventbus.SubjectApplicationStatusUpdated)
 	eventData := map[string]interface{}{
 		"event_type":     "AdoptionApplicationStatusUpdated",
 		"application_id": app.ID,
 		"user_id":        app.UserID,
 		"pet_id":         app.PetID,
 		"new_status":     app.Status,
+		"applied_at":     app.CreatedAt, // Lets consumers keep the application's events in order
 		"updated_at":     app.UpdatedAt,
 		"review_notes":   app.ReviewNotes, // Include review notes if relevant
+		"published_at":   time.Now().UTC(), // Lets consumers measure end-to-end latency
 	}
 
 	payload, err := json.Marshal(eventData)
@@ -128,6 +136,63 @@ func (p *natsAdoptionPublisher) PublishAdoptionApplicationStatusUpdated(ctx cont
 	return nil
 }
 
+// PublishApplicantFlagged publishes an admin event when an application is flagged by the velocity check.
+func (p *natsAdoptionPublisher) PublishApplicantFlagged(ctx context.Context, app *domain.AdoptionApplication, recentPetCount int) error {
+	subject := p.namespace.Subject(eventbus.SubjectApplicantFlagged)
+	eventData := map[string]interface{}{
+		"event_type":       "ApplicantFlagged",
+		"application_id":   app.ID,
+		"user_id":          app.UserID,
+		"pet_id":           app.PetID,
+		"recent_pet_count": recentPetCount, // Other pets the user applied for within the velocity window
+		"flagged_at":       app.CreatedAt,
+	}
+
+	payload, err := json.Marshal(eventData)
+	if err != nil {
+		log.Printf("Adoption Service | Error marshalling ApplicantFlagged event for app ID %s: %v", app.ID, err)
+		return err
+	}
+
+	if err = p.nc.Publish(subject, payload); err != nil {
+		log.Printf("Adoption Service | Error publishing ApplicantFlagged event to subject '%s' for app ID %s: %v", subject, app.ID, err)
+		return err
+	}
+
+	log.Printf("Adoption Service | Published event to '%s' for flagged application ID: %s", subject, app.ID)
+	return nil
+}
+
+// PublishApplicationPendingReminder publishes a reminder that an application has been waiting for review
+// since its last update.
+func (p *natsAdoptionPublisher) PublishApplicationPendingReminder(ctx context.Context, app *domain.AdoptionApplication) error {
+	subject := p.namespace.Subject(eventbus.SubjectApplicationPendingReminder)
+	eventData := map[string]interface{}{
+		"event_type":     "AdoptionApplicationPendingReminder",
+		"application_id": app.ID,
+		"user_id":        app.UserID,
+		"pet_id":         app.PetID,
+		"status":         app.Status,
+		"applied_at":     app.CreatedAt,
+		"pending_since":  app.UpdatedAt,
+		"published_at":   time.Now().UTC(), // Lets consumers measure end-to-end latency
+	}
+
+	payload, err := json.Marshal(eventData)
+	if err != nil {
+		log.Printf("Adoption Service | Error marshalling AdoptionApplicationPendingReminder event for app ID %s: %v", app.ID, err)
+		return err
+	}
+
+	if err = p.nc.Publish(subject, payload); err != nil {
+		log.Printf("Adoption Service | Error publishing AdoptionApplicationPendingReminder event to subject '%s' for app ID %s: %v", subject, app.ID, err)
+		return err
+	}
+
+	log.Printf("Adoption Service | Published event to '%s' for pending application ID: %s", subject, app.ID)
+	return nil
+}
+
 // Close drains and closes the NATS connection.
 func (p *natsAdoptionPublisher) Close() {
 	if p.nc != nil {
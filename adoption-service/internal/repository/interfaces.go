@@ -11,9 +11,35 @@ import (
 type AdoptionRepository interface {
 	CreateAdoptionApplication(ctx context.Context, app *domain.AdoptionApplication) (*domain.AdoptionApplication, error)
 	GetAdoptionApplicationByID(ctx context.Context, id string) (*domain.AdoptionApplication, error)
-	UpdateAdoptionApplicationStatus(ctx context.Context, id string, newStatus domain.ApplicationStatus, reviewNotes string) (*domain.AdoptionApplication, error)
+	// UpdateAdoptionApplicationStatus sets the status, and the review notes if reviewNotes is not nil.
+	UpdateAdoptionApplicationStatus(ctx context.Context, id string, newStatus domain.ApplicationStatus, reviewNotes *string) (*domain.AdoptionApplication, error)
 	ListAdoptionApplicationsByUserID(ctx context.Context, userID string, page, limit int, statusFilter *domain.ApplicationStatus) ([]*domain.AdoptionApplication, int64, error)
-	// ListAdoptionApplicationsByPetID(ctx context.Context, petID string, page, limit int) ([]*domain.AdoptionApplication, int64, error) // Optional
+	// CountOtherPetsAppliedForSince returns how many different pets, other than excludePetID,
+	// the user has applied for since the given time.
+	CountOtherPetsAppliedForSince(ctx context.Context, userID, excludePetID string, since time.Time) (int, error)
+	// GetLatestApplicationForPet returns the user's most recent application for the pet, or nil if there is none.
+	GetLatestApplicationForPet(ctx context.Context, userID, petID string) (*domain.AdoptionApplication, error)
+	GetPetApplicationStats(ctx context.Context, petID string) (*domain.PetApplicationStats, error)
+	// CountApplicationsByPetIDs returns the number of applications of any status per pet. Pets
+	// without applications are missing from the map.
+	CountApplicationsByPetIDs(ctx context.Context, petIDs []string) (map[string]int64, error)
+	// ReopenAdoptionApplication moves a REJECTED application back to PENDING_REVIEW and appends
+	// change to its status history. It fails if the application is not (or no longer) rejected.
+	ReopenAdoptionApplication(ctx context.Context, id string, change domain.ApplicationStatusChange) (*domain.AdoptionApplication, error)
+	// AddApplicationAttachment appends attachment unless the application already has maxAttachments.
+	AddApplicationAttachment(ctx context.Context, id string, attachment domain.Attachment, maxAttachments int) (*domain.AdoptionApplication, error)
+	// RemoveApplicationAttachment removes the attachment with the given URL.
+	RemoveApplicationAttachment(ctx context.Context, id, url string) (*domain.AdoptionApplication, error)
+	// ListStalePendingApplications returns up to limit applications still PENDING_REVIEW that were
+	// last updated before pendingSince and not reminded about since then, oldest first.
+	ListStalePendingApplications(ctx context.Context, pendingSince time.Time, limit int) ([]*domain.AdoptionApplication, error)
+	// ClaimPendingReminder records that a reminder is sent at for the application, unless it is no
+	// longer pending or another instance already reminded about it after pendingSince. It reports
+	// whether the caller won the claim and should publish the reminder.
+	ClaimPendingReminder(ctx context.Context, id string, pendingSince, at time.Time) (bool, error)
+	// ListAdoptionApplicationsByPetID lists the applications for the pet page by page, oldest first,
+	// optionally only those in statusFilter.
+	ListAdoptionApplicationsByPetID(ctx context.Context, petID string, page, limit int, statusFilter *domain.ApplicationStatus) ([]*domain.AdoptionApplication, int64, error)
 	// ListAllAdoptionApplications(ctx context.Context, page, limit int, statusFilter *domain.ApplicationStatus) ([]*domain.AdoptionApplication, int64, error) // Optional for admin
 }
 
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
 	"time" // Required for UpdateAdoptionApplicationStatus
 
@@ -19,8 +20,44 @@ type mongoAdoptionRepository struct {
 	collection *mongo.Collection
 }
 
+// IndexCreator creates indexes on the adoption applications collection. mongo.IndexView satisfies it;
+// tests substitute their own to observe index creation.
+type IndexCreator interface {
+	CreateMany(ctx context.Context, models []mongo.IndexModel, opts ...*options.CreateIndexesOptions) ([]string, error)
+}
+
+// IndexOptions controls index creation when the repository is constructed.
+type IndexOptions struct {
+	EnsureIndexes bool         // Create indexes on startup; disable when migrations manage them or on read-only replicas
+	Creator       IndexCreator // Nil uses the collection's index view
+}
+
+// EnsureIndexes creates the adoption applications collection's indexes. It is shared by repository startup and
+// the migrate command so both use the same definitions; creating an existing index is a no-op.
+func EnsureIndexes(ctx context.Context, creator IndexCreator) error {
+	_, err := creator.CreateMany(ctx, adoptionIndexModels())
+	return err
+}
+
+// adoptionIndexModels lists the indexes for the adoption applications collection.
+func adoptionIndexModels() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{Keys: bson.D{{Key: "user_id", Value: 1}}},
+		{Keys: bson.D{{Key: "pet_id", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}}},
+		// Composite index for common query in ListAdoptionApplicationsByUserID
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "status", Value: 1}}},
+		// Latest application per user and pet, for the duplicate and reapply cooldown checks
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "pet_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		// Recent applications per user, for the velocity check in CountOtherPetsAppliedForSince
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		// Applications waiting for review the longest, for the pending reminders
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "updated_at", Value: 1}}},
+	}
+}
+
 // NewMongoDBAdoptionRepository creates a new instance of mongoAdoptionRepository.
-func NewMongoDBAdoptionRepository(ctx context.Context, uri, dbName, collectionName string) (AdoptionRepository, error) {
+func NewMongoDBAdoptionRepository(ctx context.Context, uri, dbName, collectionName string, indexOpts IndexOptions) (AdoptionRepository, error) {
 	clientOptions := options.Client().ApplyURI(uri)
 	client, err := mongo.Connect(ctx, clientOptions)
 	if err != nil {
@@ -37,29 +74,34 @@ func NewMongoDBAdoptionRepository(ctx context.Context, uri, dbName, collectionNa
 	}
 	log.Println("Adoption Service | Successfully connected to MongoDB!")
 
+	return NewMongoDBAdoptionRepositoryFromClient(ctx, client, dbName, collectionName, indexOpts), nil
+}
+
+// NewMongoDBAdoptionRepositoryFromClient builds the repository on an already connected client and
+// creates the collection's indexes if indexOpts.EnsureIndexes is set. Index failures are logged, not returned.
+func NewMongoDBAdoptionRepositoryFromClient(ctx context.Context, client *mongo.Client, dbName, collectionName string, indexOpts IndexOptions) AdoptionRepository {
 	db := client.Database(dbName)
 	collection := db.Collection(collectionName)
 
-	// Example indexes for adoption_applications collection
-	indexModels := []mongo.IndexModel{
-		{Keys: bson.D{{Key: "user_id", Value: 1}}},
-		{Keys: bson.D{{Key: "pet_id", Value: 1}}},
-		{Keys: bson.D{{Key: "status", Value: 1}}},
-		// Composite index for common query in ListAdoptionApplicationsByUserID
-		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "status", Value: 1}}},
-	}
-	_, err = collection.Indexes().CreateMany(ctx, indexModels)
-	if err != nil {
-		log.Printf("Adoption Service | Warning: Could not create indexes for collection %s: %v", collectionName, err)
+	if !indexOpts.EnsureIndexes {
+		log.Printf("Adoption Service | Skipping index creation for collection %s (ENSURE_INDEXES=false)", collectionName)
 	} else {
-		log.Printf("Adoption Service | Indexes ensured for collection %s", collectionName)
+		creator := indexOpts.Creator
+		if creator == nil {
+			creator = collection.Indexes()
+		}
+		if err := EnsureIndexes(ctx, creator); err != nil {
+			log.Printf("Adoption Service | Warning: Could not create indexes for collection %s: %v", collectionName, err)
+		} else {
+			log.Printf("Adoption Service | Indexes ensured for collection %s", collectionName)
+		}
 	}
 
 	return &mongoAdoptionRepository{
 		client:     client,
 		db:         db,
 		collection: collection,
-	}, nil
+	}
 }
 
 func (r *mongoAdoptionRepository) Close(ctx context.Context) error {
@@ -76,17 +118,7 @@ func (r *mongoAdoptionRepository) CreateAdoptionApplication(ctx context.Context,
 	}
 	app.PrepareForCreate() // Sets CreatedAt, UpdatedAt, default Status
 
-	// Optional: Check if an active application already exists for this user and pet
-	// filter := bson.M{"user_id": app.UserID, "pet_id": app.PetID, "status": bson.M{"$in": []domain.ApplicationStatus{domain.StatusAppPendingReview, domain.StatusAppApproved}}}
-	// count, err := r.collection.CountDocuments(ctx, filter)
-	// if err != nil {
-	// 	log.Printf("Adoption Service | Error checking existing application: %v", err)
-	// 	return nil, errors.New("failed to verify existing applications")
-	// }
-	// if count > 0 {
-	// 	return nil, errors.New("active adoption application for this pet by this user already exists")
-	// }
-
+	// Duplicate and reapply-cooldown checks are done by the usecase (see GetLatestApplicationForPet).
 
 	_, err := r.collection.InsertOne(ctx, app)
 	if err != nil {
@@ -110,7 +142,20 @@ func (r *mongoAdoptionRepository) GetAdoptionApplicationByID(ctx context.Context
 	return &app, nil
 }
 
-func (r *mongoAdoptionRepository) UpdateAdoptionApplicationStatus(ctx context.Context, id string, newStatus domain.ApplicationStatus, reviewNotes string) (*domain.AdoptionApplication, error) {
+// StatusUpdate builds the update document for a status change. review_notes is only set when
+// reviewNotes is not nil, so a status-only change keeps the reviewer's earlier notes.
+func StatusUpdate(newStatus domain.ApplicationStatus, reviewNotes *string, now time.Time) bson.M {
+	updateFields := bson.M{
+		"status":     newStatus,
+		"updated_at": now,
+	}
+	if reviewNotes != nil {
+		updateFields["review_notes"] = *reviewNotes
+	}
+	return bson.M{"$set": updateFields}
+}
+
+func (r *mongoAdoptionRepository) UpdateAdoptionApplicationStatus(ctx context.Context, id string, newStatus domain.ApplicationStatus, reviewNotes *string) (*domain.AdoptionApplication, error) {
 	if id == "" {
 		return nil, errors.New("application ID cannot be empty for status update")
 	}
@@ -118,14 +163,7 @@ func (r *mongoAdoptionRepository) UpdateAdoptionApplicationStatus(ctx context.Co
 		return nil, errors.New("invalid new application status provided")
 	}
 
-	updateFields := bson.M{
-		"status":       newStatus,
-		"review_notes": reviewNotes, // Update review notes regardless
-		"updated_at":   time.Now().UTC(),
-	}
-	update := bson.M{"$set": updateFields}
-
-	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, StatusUpdate(newStatus, reviewNotes, time.Now().UTC()))
 	if err != nil {
 		log.Printf("Adoption Service | Error updating application status for ID '%s': %v", id, err)
 		return nil, err
@@ -139,6 +177,273 @@ func (r *mongoAdoptionRepository) UpdateAdoptionApplicationStatus(ctx context.Co
 	return r.GetAdoptionApplicationByID(ctx, id)
 }
 
+func (r *mongoAdoptionRepository) ReopenAdoptionApplication(ctx context.Context, id string, change domain.ApplicationStatusChange) (*domain.AdoptionApplication, error) {
+	if id == "" {
+		return nil, errors.New("application ID cannot be empty for reopen")
+	}
+	update := bson.M{
+		"$set":  bson.M{"status": change.ToStatus, "updated_at": change.ChangedAt},
+		"$push": bson.M{"status_history": change},
+	}
+
+	// Matching on the current status makes the transition atomic: a concurrent status update wins.
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id, "status": change.FromStatus}, update)
+	if err != nil {
+		log.Printf("Adoption Service | Error reopening application ID '%s': %v", id, err)
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, errors.New("adoption application not found or no longer rejected")
+	}
+	return r.GetAdoptionApplicationByID(ctx, id)
+}
+
+func (r *mongoAdoptionRepository) AddApplicationAttachment(ctx context.Context, id string, attachment domain.Attachment, maxAttachments int) (*domain.AdoptionApplication, error) {
+	if id == "" {
+		return nil, errors.New("application ID cannot be empty to add an attachment")
+	}
+	filter := bson.M{"_id": id}
+	if maxAttachments > 0 {
+		// Only match while the array has fewer than maxAttachments entries, so concurrent adds cannot exceed the cap.
+		filter[fmt.Sprintf("attachments.%d", maxAttachments-1)] = bson.M{"$exists": false}
+	}
+	update := bson.M{
+		"$push": bson.M{"attachments": attachment},
+		"$set":  bson.M{"updated_at": time.Now().UTC()},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		log.Printf("Adoption Service | Error adding attachment to application ID '%s': %v", id, err)
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, errors.New("adoption application not found or attachment limit reached")
+	}
+	return r.GetAdoptionApplicationByID(ctx, id)
+}
+
+func (r *mongoAdoptionRepository) RemoveApplicationAttachment(ctx context.Context, id, url string) (*domain.AdoptionApplication, error) {
+	if id == "" {
+		return nil, errors.New("application ID cannot be empty to remove an attachment")
+	}
+	update := bson.M{
+		"$pull": bson.M{"attachments": bson.M{"url": url}},
+		"$set":  bson.M{"updated_at": time.Now().UTC()},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id, "attachments.url": url}, update)
+	if err != nil {
+		log.Printf("Adoption Service | Error removing attachment from application ID '%s': %v", id, err)
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, errors.New("attachment not found")
+	}
+	return r.GetAdoptionApplicationByID(ctx, id)
+}
+
+func (r *mongoAdoptionRepository) CountOtherPetsAppliedForSince(ctx context.Context, userID, excludePetID string, since time.Time) (int, error) {
+	if userID == "" {
+		return 0, errors.New("user ID is required to count recent applications")
+	}
+	petIDs, err := r.collection.Distinct(ctx, "pet_id", bson.M{
+		"user_id":    userID,
+		"pet_id":     bson.M{"$ne": excludePetID},
+		"created_at": bson.M{"$gte": since.UTC()},
+	})
+	if err != nil {
+		log.Printf("Adoption Service | Error counting recent applications for UserID '%s': %v", userID, err)
+		return 0, err
+	}
+	return len(petIDs), nil
+}
+
+func (r *mongoAdoptionRepository) GetLatestApplicationForPet(ctx context.Context, userID, petID string) (*domain.AdoptionApplication, error) {
+	if userID == "" || petID == "" {
+		return nil, errors.New("user ID and pet ID are required to look up previous applications")
+	}
+	var app domain.AdoptionApplication
+	findOptions := options.FindOne().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	err := r.collection.FindOne(ctx, bson.M{"user_id": userID, "pet_id": petID}, findOptions).Decode(&app)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		log.Printf("Adoption Service | Error getting latest application for UserID '%s' and PetID '%s': %v", userID, petID, err)
+		return nil, err
+	}
+	return &app, nil
+}
+
+// PetApplicationStatsPipeline builds the aggregation counting a pet's applications per status.
+func PetApplicationStatsPipeline(petID string) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"pet_id": petID}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":             "$status",
+			"count":           bson.M{"$sum": 1},
+			"last_applied_at": bson.M{"$max": "$created_at"},
+		}}},
+	}
+}
+
+func (r *mongoAdoptionRepository) GetPetApplicationStats(ctx context.Context, petID string) (*domain.PetApplicationStats, error) {
+	if petID == "" {
+		return nil, errors.New("pet ID is required to get application stats")
+	}
+	cursor, err := r.collection.Aggregate(ctx, PetApplicationStatsPipeline(petID))
+	if err != nil {
+		log.Printf("Adoption Service | Error aggregating application stats for PetID '%s': %v", petID, err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var groups []struct {
+		Status        domain.ApplicationStatus `bson:"_id"`
+		Count         int64                    `bson:"count"`
+		LastAppliedAt time.Time                `bson:"last_applied_at"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		log.Printf("Adoption Service | Error decoding application stats for PetID '%s': %v", petID, err)
+		return nil, err
+	}
+
+	stats := &domain.PetApplicationStats{PetID: petID, CountsByStatus: make(map[domain.ApplicationStatus]int64)}
+	for _, g := range groups {
+		stats.CountsByStatus[g.Status] = g.Count
+		stats.Total += g.Count
+		if g.LastAppliedAt.After(stats.LastAppliedAt) {
+			stats.LastAppliedAt = g.LastAppliedAt
+		}
+	}
+	return stats, nil
+}
+
+func (r *mongoAdoptionRepository) CountApplicationsByPetIDs(ctx context.Context, petIDs []string) (map[string]int64, error) {
+	counts := make(map[string]int64, len(petIDs))
+	if len(petIDs) == 0 {
+		return counts, nil
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"pet_id": bson.M{"$in": petIDs}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$pet_id", "count": bson.M{"$sum": 1}}}},
+	}
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		log.Printf("Adoption Service | Error counting applications for %d pets: %v", len(petIDs), err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var groups []struct {
+		PetID string `bson:"_id"`
+		Count int64  `bson:"count"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		log.Printf("Adoption Service | Error decoding application counts for %d pets: %v", len(petIDs), err)
+		return nil, err
+	}
+	for _, g := range groups {
+		counts[g.PetID] = g.Count
+	}
+	return counts, nil
+}
+
+// StalePendingFilter matches applications in PENDING_REVIEW last updated before pendingSince and
+// not reminded about since then.
+func StalePendingFilter(pendingSince time.Time) bson.M {
+	pendingSince = pendingSince.UTC()
+	return bson.M{
+		"status":     domain.StatusAppPendingReview,
+		"updated_at": bson.M{"$lt": pendingSince},
+		"$or": bson.A{
+			bson.M{"reminder_sent_at": bson.M{"$exists": false}},
+			bson.M{"reminder_sent_at": bson.M{"$lt": pendingSince}},
+		},
+	}
+}
+
+func (r *mongoAdoptionRepository) ListStalePendingApplications(ctx context.Context, pendingSince time.Time, limit int) ([]*domain.AdoptionApplication, error) {
+	findOptions := options.Find().SetSort(bson.D{{Key: "updated_at", Value: 1}, {Key: "_id", Value: 1}})
+	if limit > 0 {
+		findOptions.SetLimit(int64(limit))
+	}
+	cursor, err := r.collection.Find(ctx, StalePendingFilter(pendingSince), findOptions)
+	if err != nil {
+		log.Printf("Adoption Service | Error listing applications pending since before %s: %v", pendingSince.Format(time.RFC3339), err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var applications []*domain.AdoptionApplication
+	if err := cursor.All(ctx, &applications); err != nil {
+		log.Printf("Adoption Service | Error decoding stale pending applications: %v", err)
+		return nil, err
+	}
+	return applications, nil
+}
+
+func (r *mongoAdoptionRepository) ClaimPendingReminder(ctx context.Context, id string, pendingSince, at time.Time) (bool, error) {
+	if id == "" {
+		return false, errors.New("application ID cannot be empty to record a reminder")
+	}
+	filter := StalePendingFilter(pendingSince)
+	filter["_id"] = id
+	// Leaves updated_at alone: a reminder is not activity on the application.
+	result, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"reminder_sent_at": at.UTC()}})
+	if err != nil {
+		log.Printf("Adoption Service | Error recording reminder for application ID '%s': %v", id, err)
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+func (r *mongoAdoptionRepository) ListAdoptionApplicationsByPetID(ctx context.Context, petID string, page, limit int, statusFilter *domain.ApplicationStatus) ([]*domain.AdoptionApplication, int64, error) {
+	if petID == "" {
+		return nil, 0, errors.New("pet ID is required to list adoption applications")
+	}
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10 // Default limit
+	}
+
+	findOptions := options.Find()
+	findOptions.SetSkip(int64((page - 1) * limit))
+	findOptions.SetLimit(int64(limit))
+	findOptions.SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}) // Oldest first, stable across pages
+
+	query := bson.M{"pet_id": petID}
+	if statusFilter != nil && *statusFilter != "" && *statusFilter != domain.StatusAppUnspecified {
+		if !domain.IsValidApplicationStatus(*statusFilter) {
+			return nil, 0, errors.New("invalid status filter value")
+		}
+		query["status"] = *statusFilter
+	}
+	cursor, err := r.collection.Find(ctx, query, findOptions)
+	if err != nil {
+		log.Printf("Adoption Service | Error listing adoption applications by PetID '%s': %v", petID, err)
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var applications []*domain.AdoptionApplication
+	if err = cursor.All(ctx, &applications); err != nil {
+		log.Printf("Adoption Service | Error decoding listed adoption applications: %v", err)
+		return nil, 0, err
+	}
+
+	totalCount, err := r.collection.CountDocuments(ctx, query)
+	if err != nil {
+		log.Printf("Adoption Service | Error counting adoption applications for PetID '%s': %v", petID, err)
+		return nil, 0, err
+	}
+
+	return applications, totalCount, nil
+}
+
 func (r *mongoAdoptionRepository) ListAdoptionApplicationsByUserID(ctx context.Context, userID string, page, limit int, statusFilter *domain.ApplicationStatus) ([]*domain.AdoptionApplication, int64, error) {
 	if userID == "" {
 		return nil, 0, errors.New("user ID is required to list adoption applications")
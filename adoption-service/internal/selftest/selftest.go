@@ -0,0 +1,54 @@
+// Package selftest verifies that the service can reach its dependencies without serving traffic,
+// for use as a container readiness probe or CI smoke test.
+package selftest
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// Check verifies one dependency, e.g. by pinging it.
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// Result is the outcome of one Check.
+type Result struct {
+	Name     string
+	Err      error // nil if the check passed
+	Duration time.Duration
+}
+
+// Run runs every check with its own timeout and logs the outcome of each. It returns the results
+// in the order of checks and an error naming the failed checks, or nil if all of them passed.
+func Run(ctx context.Context, timeout time.Duration, checks []Check) ([]Result, error) {
+	results := make([]Result, 0, len(checks))
+	var failed []string
+	for _, check := range checks {
+		checkCtx, cancel := context.WithTimeout(ctx, timeout)
+		start := time.Now()
+		err := check.Run(checkCtx)
+		if err == nil && checkCtx.Err() != nil {
+			err = checkCtx.Err() // The check ignored its context and overran the timeout
+		}
+		cancel()
+
+		result := Result{Name: check.Name, Err: err, Duration: time.Since(start)}
+		results = append(results, result)
+		if err != nil {
+			failed = append(failed, check.Name)
+			log.Printf("Adoption Service | Self-test FAIL %s (%s): %v", check.Name, result.Duration.Round(time.Millisecond), err)
+			continue
+		}
+		log.Printf("Adoption Service | Self-test PASS %s (%s)", check.Name, result.Duration.Round(time.Millisecond))
+	}
+
+	if len(failed) > 0 {
+		return results, fmt.Errorf("self-test failed for %s", strings.Join(failed, ", "))
+	}
+	return results, nil
+}
@@ -10,6 +10,7 @@ import (
 
 	pb "github.com/zhandarbeks/petstore-final-project/genprotos/adoption" // Adjust import path
 	"google.golang.org/grpc"
+	_ "google.golang.org/grpc/encoding/gzip" // Registers gzip so clients may send compressed requests
 	"google.golang.org/grpc/health"
 	"google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
@@ -19,12 +20,15 @@ import (
 type GRPCServer struct {
 	server   *grpc.Server
 	listener net.Listener
+	inFlight *InFlightTracker
 	Port     string
 }
 
 // NewGRPCServer creates and configures a new gRPC server instance for the Adoption Service.
-// It takes the port string (e.g., ":50053") and the AdoptionServiceServer implementation.
-func NewGRPCServer(port string, adoptionService pb.AdoptionServiceServer) (*GRPCServer, error) {
+// It takes the port string (e.g., ":50053") and the AdoptionServiceServer implementation,
+// plus the maximum number of concurrent in-flight requests (0 disables the limit). Any extra
+// interceptors run after the concurrency limit, in order.
+func NewGRPCServer(port string, adoptionService pb.AdoptionServiceServer, maxInFlight int, interceptors ...grpc.UnaryServerInterceptor) (*GRPCServer, error) {
 	if port == "" {
 		return nil, fmt.Errorf("port cannot be empty for Adoption Service gRPC server")
 	}
@@ -38,8 +42,12 @@ func NewGRPCServer(port string, adoptionService pb.AdoptionServiceServer) (*GRPC
 		return nil, fmt.Errorf("failed to listen on port %s: %w", port, err)
 	}
 
+	inFlight := &InFlightTracker{}
 	s := grpc.NewServer(
-	// Add interceptors here if needed in the future
+		grpc.ChainUnaryInterceptor(append([]grpc.UnaryServerInterceptor{
+			NewConcurrencyLimitInterceptor(maxInFlight, inFlight), // Rejects requests beyond maxInFlight with ResourceExhausted
+		}, interceptors...)...),
+		// grpc.StreamInterceptor(yourStreamInterceptor),
 	)
 
 	// Register your adoption service implementation.
@@ -60,6 +68,7 @@ func NewGRPCServer(port string, adoptionService pb.AdoptionServiceServer) (*GRPC
 	return &GRPCServer{
 		server:   s,
 		listener: lis,
+		inFlight: inFlight,
 		Port:     port,
 	}, nil
 }
@@ -74,11 +83,17 @@ func (gs *GRPCServer) Start() error {
 	return nil
 }
 
+// Addr returns the address the server listens on, useful when it was started on port 0.
+func (gs *GRPCServer) Addr() string {
+	return gs.listener.Addr().String()
+}
+
 // Stop gracefully shuts down the gRPC server for the Adoption Service.
 func (gs *GRPCServer) Stop() {
-	log.Println("Adoption Service | Attempting to gracefully stop gRPC server...")
+	inFlight := gs.inFlight.StartDraining()
+	log.Printf("Adoption Service | Attempting to gracefully stop gRPC server... in_flight=%d", inFlight)
 	gs.server.GracefulStop()
-	log.Println("Adoption Service | gRPC server stopped.")
+	log.Printf("Adoption Service | gRPC server stopped. in_flight_at_shutdown=%d drained=%d", inFlight, gs.inFlight.Drained())
 }
 
 // RunWithGracefulShutdown starts the Adoption Service server and handles OS signals.
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// InFlightTracker counts the requests admitted by the concurrency-limit interceptor that are still
+// being handled. Once StartDraining is called it also counts how many of them completed, so
+// graceful shutdown can report what it drained.
+type InFlightTracker struct {
+	inFlight atomic.Int64
+	draining atomic.Bool
+	drained  atomic.Int64
+}
+
+// InFlight returns the number of requests currently being handled.
+func (t *InFlightTracker) InFlight() int64 { return t.inFlight.Load() }
+
+// StartDraining marks the start of shutdown and returns the number of requests in flight at that moment.
+func (t *InFlightTracker) StartDraining() int64 {
+	t.draining.Store(true)
+	return t.inFlight.Load()
+}
+
+// Drained returns the number of requests that completed after StartDraining.
+func (t *InFlightTracker) Drained() int64 { return t.drained.Load() }
+
+func (t *InFlightTracker) begin() {
+	if t != nil {
+		t.inFlight.Add(1)
+	}
+}
+
+func (t *InFlightTracker) end() {
+	if t == nil {
+		return
+	}
+	t.inFlight.Add(-1)
+	if t.draining.Load() {
+		t.drained.Add(1)
+	}
+}
+
+// NewConcurrencyLimitInterceptor returns a unary interceptor that allows at most maxInFlight
+// requests to be processed concurrently. Requests arriving while the limit is reached are
+// rejected immediately with codes.ResourceExhausted instead of being queued.
+// A maxInFlight of 0 or less disables the limit. Admitted requests are counted in tracker, which may be nil.
+func NewConcurrencyLimitInterceptor(maxInFlight int, tracker *InFlightTracker) grpc.UnaryServerInterceptor {
+	if maxInFlight <= 0 {
+		return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			tracker.begin()
+			defer tracker.end()
+			return handler(ctx, req)
+		}
+	}
+
+	sem := make(chan struct{}, maxInFlight)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		select {
+		case sem <- struct{}{}:
+			defer func() { <-sem }()
+			tracker.begin()
+			defer tracker.end()
+			return handler(ctx, req)
+		default:
+			log.Printf("Adoption Service | Rejecting %s: max in-flight requests (%d) reached", info.FullMethod, maxInFlight)
+			return nil, status.Errorf(codes.ResourceExhausted, "server is busy, too many concurrent requests")
+		}
+	}
+}
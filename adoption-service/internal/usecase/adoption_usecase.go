@@ -5,63 +5,211 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net/url"
+	"strings"
 	"time"
+	"unicode/utf8"
 
+	"github.com/zhandarbeks/petstore-final-project/adoption-service/internal/client"
 	"github.com/zhandarbeks/petstore-final-project/adoption-service/internal/domain" // Adjust import path
+	"github.com/zhandarbeks/petstore-final-project/adoption-service/internal/metrics"
 	"github.com/zhandarbeks/petstore-final-project/adoption-service/internal/publisher"
 	"github.com/zhandarbeks/petstore-final-project/adoption-service/internal/repository"
+	pbPet "github.com/zhandarbeks/petstore-final-project/genprotos/pet"
+	"github.com/zhandarbeks/petstore-final-project/internal/features"
+	"github.com/zhandarbeks/petstore-final-project/internal/textnorm"
 )
 
 type adoptionUsecase struct {
 	repo      repository.AdoptionRepository
 	cache     repository.AdoptionCache
 	publisher publisher.AdoptionEventPublisher
-	// petServiceClient PetServiceInternalClient // Interface for internal PetService gRPC calls
+	policy    AdoptionPolicy
+	features  *features.Flags // Global switches and soft-launch allowlists, built from policy
+	petClient client.PetServiceClient // Checks and updates pet adoption status; nil skips both
 }
 
+// applicationCacheTTL is how long a single adoption application stays in the cache.
+const applicationCacheTTL = 1 * time.Hour
+
 // NewAdoptionUsecase creates a new instance of adoptionUsecase.
 func NewAdoptionUsecase(
 	repo repository.AdoptionRepository,
 	cache repository.AdoptionCache,
 	pub publisher.AdoptionEventPublisher,
-	// petClient PetServiceInternalClient, // Inject if needed
+	policy AdoptionPolicy,
+	petClient client.PetServiceClient,
 ) AdoptionUsecase {
+	flags := features.NewFlags()
+	flags.Set(features.AutoApproval, policy.AutoApproveTrustedUsers, policy.AutoApproveAllowlist)
 	return &adoptionUsecase{
 		repo:      repo,
 		cache:     cache,
 		publisher: pub,
-		// petServiceClient: petClient,
+		policy:    policy,
+		features:  flags,
+		petClient: petClient,
+	}
+}
+
+// FieldViolation describes one invalid field of a request. Field is the request's proto field
+// name, e.g. "pet_id".
+type FieldViolation struct {
+	Field       string
+	Description string
+}
+
+// ValidationError is returned when a request has invalid fields. It lists every invalid field,
+// not just the first, so clients can point at all of them at once.
+type ValidationError struct {
+	Violations []FieldViolation
+}
+
+func (e *ValidationError) Error() string {
+	descriptions := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		descriptions[i] = v.Description
 	}
+	return strings.Join(descriptions, "; ")
+}
+
+// ErrReviewNotesRequired is returned when an application is rejected without review notes
+// while AdoptionPolicy.RequireReviewNotesOnRejection is enabled.
+var ErrReviewNotesRequired = errors.New("review notes are required when rejecting an application")
+
+// ErrDuplicateApplication is returned when the user already has a pending or approved application for the pet.
+var ErrDuplicateApplication = errors.New("active adoption application for this pet by this user already exists")
+
+// ErrPetNotAvailable is returned when applying for a pet that is pending adoption or already adopted.
+var ErrPetNotAvailable = errors.New("pet is not available for adoption")
+
+// ErrPetNotFound is returned when applying for a pet the pet-service does not know.
+var ErrPetNotFound = errors.New("pet not found")
+
+// ErrReapplyCooldown is returned when the user's last application for the pet was rejected
+// less than AdoptionPolicy.ReapplyCooldown ago. It is wrapped with the time reapplying is allowed.
+var ErrReapplyCooldown = errors.New("application for this pet was recently rejected")
+
+// ErrReopenForbidden is returned when a caller without the admin role tries to reopen an application.
+var ErrReopenForbidden = errors.New("only admins can reopen adoption applications")
+
+// ErrReopenReasonRequired is returned when an application is reopened without a reason.
+var ErrReopenReasonRequired = errors.New("a reason is required to reopen an application")
+
+// ErrApplicationNotRejected is returned when reopening an application that is not in REJECTED status.
+var ErrApplicationNotRejected = errors.New("only rejected applications can be reopened")
+
+// ErrNotApplicant is returned when someone other than the applicant changes an application's attachments.
+var ErrNotApplicant = errors.New("only the applicant can change this application's attachments")
+
+// ErrCancelForbidden is returned when someone cancels the applications of another user.
+var ErrCancelForbidden = errors.New("only the applicant can cancel their applications")
+
+// ErrApplicationDecided is returned when cancelling an application that was already approved or rejected.
+var ErrApplicationDecided = errors.New("application has already been approved or rejected")
+
+// ErrInvalidAttachment is returned for an attachment without an http(s) URL or a file name.
+var ErrInvalidAttachment = errors.New("attachment needs an http(s) URL and a file name")
+
+// ErrTooManyAttachments is returned when an application already has AdoptionPolicy.MaxAttachments attachments.
+var ErrTooManyAttachments = errors.New("application has reached the maximum number of attachments")
+
+// ErrAttachmentNotFound is returned when removing an attachment the application does not have.
+var ErrAttachmentNotFound = errors.New("attachment not found")
+
+// maxAttachmentFilenameLength bounds the stored file name.
+const maxAttachmentFilenameLength = 255
+
+// cancelUserApplicationsPageSize is how many applications CancelUserApplications lists at a time.
+const cancelUserApplicationsPageSize = 100
+
+// RoleTrusted marks pre-vetted users whose applications may be auto-approved.
+const RoleTrusted = "trusted"
+
+// RoleAdmin marks administrators, who may reopen rejected applications.
+const RoleAdmin = "admin"
+
+func hasRole(roles []string, role string) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// validateCreateApplication returns a *ValidationError listing every invalid field of reqData.
+// Notes are measured after normalization, so whitespace the user cannot see does not count.
+func (uc *adoptionUsecase) validateCreateApplication(reqData CreateAdoptionApplicationRequestData) error {
+	var violations []FieldViolation
+	if reqData.UserID == "" {
+		violations = append(violations, FieldViolation{Field: "user_id", Description: "user ID is required"})
+	}
+	if reqData.PetID == "" {
+		violations = append(violations, FieldViolation{Field: "pet_id", Description: "pet ID is required"})
+	}
+	if limit := uc.policy.MaxNotesLength; limit > 0 && utf8.RuneCountInString(textnorm.Normalize(reqData.ApplicationNotes, 0)) > limit {
+		violations = append(violations, FieldViolation{Field: "application_notes", Description: fmt.Sprintf("application notes must be at most %d characters", limit)})
+	}
+	if len(violations) > 0 {
+		return &ValidationError{Violations: violations}
+	}
+	return nil
 }
 
 func (uc *adoptionUsecase) CreateAdoptionApplication(ctx context.Context, reqData CreateAdoptionApplicationRequestData) (*domain.AdoptionApplication, error) {
-	if reqData.UserID == "" || reqData.PetID == "" {
-		return nil, errors.New("user ID and pet ID are required")
+	if err := uc.validateCreateApplication(reqData); err != nil {
+		return nil, err
 	}
 
-	// Optional: Check if pet is available for adoption by calling Pet Service
-	// available, err := uc.petServiceClient.IsPetAvailableForAdoption(ctx, reqData.PetID)
-	// if err != nil {
-	// 	log.Printf("Adoption Service | Error checking pet availability for PetID %s: %v", reqData.PetID, err)
-	// 	return nil, fmt.Errorf("failed to verify pet status: %w", err)
-	// }
-	// if !available {
-	// 	return nil, errors.New("pet is not available for adoption")
-	// }
+	if uc.petClient != nil {
+		available, err := uc.petClient.IsPetAvailableForAdoption(ctx, reqData.PetID)
+		if errors.Is(err, client.ErrPetNotFound) {
+			return nil, ErrPetNotFound
+		}
+		if err != nil {
+			log.Printf("Adoption Service | Error checking pet availability for PetID %s: %v", reqData.PetID, err)
+			return nil, fmt.Errorf("failed to verify pet status: %w", err)
+		}
+		if !available {
+			return nil, ErrPetNotAvailable
+		}
+	}
+
+	if err := uc.checkPreviousApplication(ctx, reqData.UserID, reqData.PetID); err != nil {
+		return nil, err
+	}
 
 	app := &domain.AdoptionApplication{
 		UserID:           reqData.UserID,
 		PetID:            reqData.PetID,
-		ApplicationNotes: reqData.ApplicationNotes,
+		ApplicationNotes: textnorm.Normalize(reqData.ApplicationNotes, 0), // Length checked by validateCreateApplication
 		// Status will be defaulted by PrepareForCreate
 	}
 	// app.PrepareForCreate() // Called by repository
 
+	recentPets := uc.countRecentPetsAppliedFor(ctx, reqData.UserID, reqData.PetID)
+	if uc.policy.VelocityFlagThreshold > 0 && recentPets >= uc.policy.VelocityFlagThreshold {
+		app.Flagged = true
+	}
+
+	// Flagged applications always go to a human reviewer, even from trusted users.
+	autoApproved := uc.features.IsFeatureEnabledForUser(features.AutoApproval, reqData.UserID) && hasRole(reqData.CallerRoles, RoleTrusted) && !app.Flagged
+	if autoApproved {
+		app.Status = domain.StatusAppApproved
+		app.ReviewNotes = "Automatically approved: applicant is a trusted user."
+	}
+
 	createdApp, err := uc.repo.CreateAdoptionApplication(ctx, app)
 	if err != nil {
 		log.Printf("Adoption Service | Error creating adoption application in repository: %v", err)
 		return nil, fmt.Errorf("could not create adoption application: %w", err)
 	}
+	if uc.policy.SeedCacheOnCreate {
+		if cacheErr := uc.cache.SetAdoptionApplication(ctx, createdApp.ID, createdApp, applicationCacheTTL); cacheErr != nil {
+			log.Printf("Adoption Service | Warning: Failed to seed cache with new application %s: %v", createdApp.ID, cacheErr)
+		}
+	}
 
 	// Publish event to NATS
 	if pubErr := uc.publisher.PublishAdoptionApplicationCreated(ctx, createdApp); pubErr != nil {
@@ -69,11 +217,62 @@ func (uc *adoptionUsecase) CreateAdoptionApplication(ctx context.Context, reqDat
 		// This depends on business requirements; sometimes event publishing failure is critical.
 		log.Printf("Adoption Service | Warning: Failed to publish AdoptionApplicationCreated event for app ID %s: %v", createdApp.ID, pubErr)
 	}
+	if createdApp.Flagged {
+		log.Printf("Adoption Service | Application %s flagged: user %s applied for %d other pets in the last %s", createdApp.ID, createdApp.UserID, recentPets, uc.policy.VelocityWindow)
+		if pubErr := uc.publisher.PublishApplicantFlagged(ctx, createdApp, recentPets); pubErr != nil {
+			log.Printf("Adoption Service | Warning: Failed to publish ApplicantFlagged event for app ID %s: %v", createdApp.ID, pubErr)
+		}
+	}
+	if autoApproved {
+		// Downstream consumers (notifications) react to the approval like any reviewer decision.
+		if pubErr := uc.publisher.PublishAdoptionApplicationStatusUpdated(ctx, createdApp); pubErr != nil {
+			log.Printf("Adoption Service | Warning: Failed to publish approval event for auto-approved app ID %s: %v", createdApp.ID, pubErr)
+		}
+		log.Printf("Adoption Service | Application %s auto-approved for trusted user %s", createdApp.ID, createdApp.UserID)
+		uc.syncPetStatus(ctx, createdApp)
+	}
 
 	log.Printf("Adoption Service | Adoption application created successfully: ID %s", createdApp.ID)
 	return createdApp, nil
 }
 
+// checkPreviousApplication blocks a new application while the user's last one for the pet is
+// still active, or was rejected within the reapply cooldown.
+func (uc *adoptionUsecase) checkPreviousApplication(ctx context.Context, userID, petID string) error {
+	previous, err := uc.repo.GetLatestApplicationForPet(ctx, userID, petID)
+	if err != nil {
+		log.Printf("Adoption Service | Error checking previous applications of user %s for pet %s: %v", userID, petID, err)
+		return fmt.Errorf("could not verify existing applications: %w", err)
+	}
+	if previous == nil {
+		return nil
+	}
+	switch previous.Status {
+	case domain.StatusAppPendingReview, domain.StatusAppApproved:
+		return ErrDuplicateApplication
+	case domain.StatusAppRejected:
+		// The status update sets UpdatedAt, so it is the time of the rejection.
+		if allowedAt := previous.UpdatedAt.Add(uc.policy.ReapplyCooldown); time.Now().Before(allowedAt) {
+			return fmt.Errorf("%w; you can reapply after %s", ErrReapplyCooldown, allowedAt.UTC().Format(time.RFC3339))
+		}
+	}
+	return nil
+}
+
+// countRecentPetsAppliedFor returns how many other pets the user applied for within the velocity window.
+// Errors are logged and count as zero, so the anti-fraud check never blocks an application.
+func (uc *adoptionUsecase) countRecentPetsAppliedFor(ctx context.Context, userID, petID string) int {
+	if uc.policy.VelocityFlagThreshold <= 0 {
+		return 0
+	}
+	count, err := uc.repo.CountOtherPetsAppliedForSince(ctx, userID, petID, time.Now().Add(-uc.policy.VelocityWindow))
+	if err != nil {
+		log.Printf("Adoption Service | Warning: Could not check application velocity for user %s: %v", userID, err)
+		return 0
+	}
+	return count
+}
+
 func (uc *adoptionUsecase) GetAdoptionApplicationByID(ctx context.Context, applicationID string) (*domain.AdoptionApplication, error) {
 	if applicationID == "" {
 		return nil, errors.New("application ID is required")
@@ -83,8 +282,10 @@ func (uc *adoptionUsecase) GetAdoptionApplicationByID(ctx context.Context, appli
 	cachedApp, err := uc.cache.GetAdoptionApplication(ctx, applicationID)
 	if err == nil && cachedApp != nil {
 		log.Printf("Adoption Service | Application %s found in cache", applicationID)
+		metrics.CacheHits.Inc(metrics.CacheApplication)
 		return cachedApp, nil
 	}
+	metrics.CacheMisses.Inc(metrics.CacheApplication)
 	if err != nil && err.Error() != "adoption application not found in cache" {
 		log.Printf("Adoption Service | Error fetching application %s from cache: %v", applicationID, err)
 	}
@@ -98,8 +299,7 @@ func (uc *adoptionUsecase) GetAdoptionApplicationByID(ctx context.Context, appli
 	}
 
 	// 3. Set in cache
-	// Use a reasonable expiration, e.g., 1 hour
-	cacheErr := uc.cache.SetAdoptionApplication(ctx, applicationID, app, 1*time.Hour)
+	cacheErr := uc.cache.SetAdoptionApplication(ctx, applicationID, app, applicationCacheTTL)
 	if cacheErr != nil {
 		log.Printf("Adoption Service | Warning: Failed to set application %s in cache: %v", applicationID, cacheErr)
 	}
@@ -114,6 +314,13 @@ func (uc *adoptionUsecase) UpdateAdoptionApplicationStatus(ctx context.Context,
 	if !domain.IsValidApplicationStatus(reqData.NewStatus) {
 		return nil, errors.New("invalid new application status")
 	}
+	if reqData.ReviewNotes != nil {
+		reviewNotes := textnorm.Normalize(*reqData.ReviewNotes, uc.policy.MaxNotesLength)
+		reqData.ReviewNotes = &reviewNotes
+	}
+	if uc.policy.RequireReviewNotesOnRejection && reqData.NewStatus == domain.StatusAppRejected && (reqData.ReviewNotes == nil || *reqData.ReviewNotes == "") {
+		return nil, ErrReviewNotesRequired
+	}
 
 	// Fetch the application to ensure it exists before updating
 	// (though the repository update method might also do this check)
@@ -145,22 +352,245 @@ func (uc *adoptionUsecase) UpdateAdoptionApplicationStatus(ctx context.Context,
 		log.Printf("Adoption Service | Warning: Failed to publish AdoptionApplicationStatusUpdated event for app ID %s: %v", updatedApp.ID, pubErr)
 	}
 
-	// If status is APPROVED, consider interaction with Pet Service to update pet's status.
-	// This could be done here via a gRPC call to Pet Service, or Pet Service could subscribe to NATS events.
-	// For simplicity and to avoid distributed transactions in this call, Pet Service could subscribe to "adoption.application.status.updated"
-	// and if new_status is APPROVED, it then updates its own pet record.
-	// if updatedApp.Status == domain.StatusAppApproved {
-	// 	 petUpdateErr := uc.petServiceClient.MarkPetAsPendingOrAdopted(ctx, updatedApp.PetID, updatedApp.UserID)
-	// 	 if petUpdateErr != nil {
-	// 	 	log.Printf("Adoption Service | Warning: Failed to update pet status for PetID %s after application approval: %v", updatedApp.PetID, petUpdateErr)
-	// 	 	// Decide how to handle this - is it critical? Should the adoption status be rolled back?
-	// 	 }
-	// }
+	uc.syncPetStatus(ctx, updatedApp)
 
 	log.Printf("Adoption Service | Application status updated successfully for ID: %s to %s", updatedApp.ID, updatedApp.Status)
 	return updatedApp, nil
 }
 
+// syncPetStatus updates the pet after a decision on app: an approval marks the pet ADOPTED by the
+// applicant, and a rejection or cancellation puts a pet that was PENDING_ADOPTION back to AVAILABLE
+// unless another application for it is still pending review or approved. Pet-service errors are only logged: the
+// application's status has been saved and must not be reported as failed.
+func (uc *adoptionUsecase) syncPetStatus(ctx context.Context, app *domain.AdoptionApplication) {
+	if uc.petClient == nil {
+		return
+	}
+	switch app.Status {
+	case domain.StatusAppApproved:
+		if err := uc.petClient.MarkPetAsAdopted(ctx, app.PetID, app.UserID, "adoption application "+app.ID+" approved"); err != nil {
+			log.Printf("Adoption Service | Warning: Failed to mark pet %s as adopted after approving application %s: %v", app.PetID, app.ID, err)
+		}
+	case domain.StatusAppRejected, domain.StatusAppCancelledByUser:
+		petStatus, err := uc.petClient.GetPetAdoptionStatus(ctx, app.PetID)
+		if err != nil {
+			log.Printf("Adoption Service | Warning: Failed to get status of pet %s after application %s was %s: %v", app.PetID, app.ID, app.Status, err)
+			return
+		}
+		if petStatus != pbPet.AdoptionStatus_PENDING_ADOPTION {
+			return
+		}
+		stats, err := uc.repo.GetPetApplicationStats(ctx, app.PetID)
+		if err != nil {
+			log.Printf("Adoption Service | Warning: Failed to count active applications for pet %s: %v", app.PetID, err)
+			return
+		}
+		if stats.CountsByStatus[domain.StatusAppPendingReview]+stats.CountsByStatus[domain.StatusAppApproved] > 0 {
+			return
+		}
+		if err := uc.petClient.MarkPetAsAvailable(ctx, app.PetID, "adoption application "+app.ID+" "+strings.ToLower(string(app.Status))); err != nil {
+			log.Printf("Adoption Service | Warning: Failed to mark pet %s as available after application %s was %s: %v", app.PetID, app.ID, app.Status, err)
+		}
+	}
+}
+
+// ReopenApplication moves a rejected application back to PENDING_REVIEW, for example after the
+// applicant appealed the decision. Only admins may reopen; the reason is kept in the status history.
+func (uc *adoptionUsecase) ReopenApplication(ctx context.Context, applicationID, reason string, callerRoles []string) (*domain.AdoptionApplication, error) {
+	if applicationID == "" {
+		return nil, errors.New("application ID is required to reopen")
+	}
+	if !hasRole(callerRoles, RoleAdmin) {
+		return nil, ErrReopenForbidden
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, ErrReopenReasonRequired
+	}
+
+	existingApp, err := uc.repo.GetAdoptionApplicationByID(ctx, applicationID)
+	if err != nil {
+		log.Printf("Adoption Service | Error fetching application %s to reopen: %v", applicationID, err)
+		return nil, err // Could be "not found"
+	}
+	if existingApp.Status != domain.StatusAppRejected {
+		return nil, fmt.Errorf("%w; application is %s", ErrApplicationNotRejected, existingApp.Status)
+	}
+
+	reopenedApp, err := uc.repo.ReopenAdoptionApplication(ctx, applicationID, domain.ApplicationStatusChange{
+		FromStatus: domain.StatusAppRejected,
+		ToStatus:   domain.StatusAppPendingReview,
+		Reason:     reason,
+		ChangedAt:  time.Now().UTC(),
+	})
+	if err != nil {
+		log.Printf("Adoption Service | Error reopening application %s in repository: %v", applicationID, err)
+		return nil, fmt.Errorf("could not reopen application: %w", err)
+	}
+
+	if cacheErr := uc.cache.DeleteAdoptionApplication(ctx, applicationID); cacheErr != nil {
+		log.Printf("Adoption Service | Warning: Failed to delete application %s from cache after reopen: %v", applicationID, cacheErr)
+	}
+	if pubErr := uc.publisher.PublishAdoptionApplicationStatusUpdated(ctx, reopenedApp); pubErr != nil {
+		log.Printf("Adoption Service | Warning: Failed to publish AdoptionApplicationStatusUpdated event for reopened app ID %s: %v", reopenedApp.ID, pubErr)
+	}
+
+	log.Printf("Adoption Service | Application %s reopened for review. Reason: %s", reopenedApp.ID, reason)
+	return reopenedApp, nil
+}
+
+// AddAttachment attaches a document, uploaded to object storage beforehand, to the caller's own
+// application. Attaching a URL that is already attached leaves the application unchanged.
+func (uc *adoptionUsecase) AddAttachment(ctx context.Context, applicationID, callerID string, attachment domain.Attachment) (*domain.AdoptionApplication, error) {
+	attachment.Filename = strings.TrimSpace(attachment.Filename)
+	u, err := url.Parse(strings.TrimSpace(attachment.URL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || attachment.Filename == "" || len(attachment.Filename) > maxAttachmentFilenameLength {
+		return nil, ErrInvalidAttachment
+	}
+	attachment.URL = u.String()
+
+	app, err := uc.applicantApplication(ctx, applicationID, callerID)
+	if err != nil {
+		return nil, err
+	}
+	for _, existing := range app.Attachments {
+		if existing.URL == attachment.URL {
+			return app, nil
+		}
+	}
+	if uc.policy.MaxAttachments > 0 && len(app.Attachments) >= uc.policy.MaxAttachments {
+		return nil, ErrTooManyAttachments
+	}
+
+	attachment.UploadedAt = time.Now().UTC()
+	updatedApp, err := uc.repo.AddApplicationAttachment(ctx, applicationID, attachment, uc.policy.MaxAttachments)
+	if err != nil {
+		log.Printf("Adoption Service | Error adding attachment to application %s: %v", applicationID, err)
+		if err.Error() == "adoption application not found or attachment limit reached" {
+			return nil, ErrTooManyAttachments // The application exists, so another request filled the last slot
+		}
+		return nil, fmt.Errorf("could not add attachment: %w", err)
+	}
+	uc.invalidateCachedApplication(ctx, applicationID)
+
+	log.Printf("Adoption Service | Attachment %q added to application %s", attachment.Filename, applicationID)
+	return updatedApp, nil
+}
+
+// RemoveAttachment removes the attachment with the given URL from the caller's own application.
+// The file itself stays in object storage.
+func (uc *adoptionUsecase) RemoveAttachment(ctx context.Context, applicationID, callerID, attachmentURL string) (*domain.AdoptionApplication, error) {
+	if strings.TrimSpace(attachmentURL) == "" {
+		return nil, ErrInvalidAttachment
+	}
+	if _, err := uc.applicantApplication(ctx, applicationID, callerID); err != nil {
+		return nil, err
+	}
+
+	updatedApp, err := uc.repo.RemoveApplicationAttachment(ctx, applicationID, strings.TrimSpace(attachmentURL))
+	if err != nil {
+		log.Printf("Adoption Service | Error removing attachment from application %s: %v", applicationID, err)
+		if err.Error() == "attachment not found" {
+			return nil, ErrAttachmentNotFound
+		}
+		return nil, fmt.Errorf("could not remove attachment: %w", err)
+	}
+	uc.invalidateCachedApplication(ctx, applicationID)
+
+	log.Printf("Adoption Service | Attachment removed from application %s", applicationID)
+	return updatedApp, nil
+}
+
+// CancelUserApplications moves all of the user's applications that are still pending review to
+// CANCELLED_BY_USER, for example when the user withdraws consent to processing their data. Only
+// the user themselves may do this. Every cancellation goes through the regular status update, so
+// it is published like any other. It returns how many applications were cancelled; when one
+// fails, the ones cancelled before it stay cancelled.
+func (uc *adoptionUsecase) CancelUserApplications(ctx context.Context, userID, callerID string) (int, error) {
+	if userID == "" {
+		return 0, errors.New("user ID is required")
+	}
+	if callerID != userID {
+		return 0, ErrCancelForbidden
+	}
+
+	// Collect the IDs first: cancelling while paging would shift the later pages.
+	pending := domain.StatusAppPendingReview
+	var applicationIDs []string
+	for page := 1; ; page++ {
+		apps, totalCount, err := uc.repo.ListAdoptionApplicationsByUserID(ctx, userID, page, cancelUserApplicationsPageSize, &pending)
+		if err != nil {
+			log.Printf("Adoption Service | Error listing pending applications of UserID %s to cancel: %v", userID, err)
+			return 0, fmt.Errorf("could not list pending applications: %w", err)
+		}
+		for _, app := range apps {
+			applicationIDs = append(applicationIDs, app.ID)
+		}
+		if len(apps) < cancelUserApplicationsPageSize || int64(len(applicationIDs)) >= totalCount {
+			break
+		}
+	}
+
+	cancelled := 0
+	for _, applicationID := range applicationIDs {
+		_, err := uc.UpdateAdoptionApplicationStatus(ctx, applicationID, UpdateAdoptionApplicationStatusRequestData{NewStatus: domain.StatusAppCancelledByUser})
+		if err != nil {
+			return cancelled, err
+		}
+		cancelled++
+	}
+	log.Printf("Adoption Service | Cancelled %d pending applications of UserID %s", cancelled, userID)
+	return cancelled, nil
+}
+
+// CancelAdoptionApplication withdraws one of userID's applications by moving it to
+// CANCELLED_BY_USER. callerID must be userID, and userID the applicant. Approved and rejected
+// applications cannot be cancelled; cancelling an already cancelled application returns it
+// unchanged. The cancellation goes through the regular status update, so it is published like
+// any other.
+func (uc *adoptionUsecase) CancelAdoptionApplication(ctx context.Context, applicationID, userID, callerID string) (*domain.AdoptionApplication, error) {
+	if userID == "" || callerID != userID {
+		return nil, ErrCancelForbidden
+	}
+	app, err := uc.applicantApplication(ctx, applicationID, userID)
+	if errors.Is(err, ErrNotApplicant) {
+		return nil, ErrCancelForbidden
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch app.Status {
+	case domain.StatusAppApproved, domain.StatusAppRejected:
+		return nil, fmt.Errorf("%w; application is %s", ErrApplicationDecided, app.Status)
+	case domain.StatusAppCancelledByUser:
+		return app, nil
+	}
+	return uc.UpdateAdoptionApplicationStatus(ctx, applicationID, UpdateAdoptionApplicationStatusRequestData{NewStatus: domain.StatusAppCancelledByUser})
+}
+
+// applicantApplication loads the application from the repository and checks that callerID is its applicant.
+func (uc *adoptionUsecase) applicantApplication(ctx context.Context, applicationID, callerID string) (*domain.AdoptionApplication, error) {
+	if applicationID == "" {
+		return nil, errors.New("application ID is required")
+	}
+	app, err := uc.repo.GetAdoptionApplicationByID(ctx, applicationID)
+	if err != nil {
+		log.Printf("Adoption Service | Error fetching application %s: %v", applicationID, err)
+		return nil, err // Could be "not found"
+	}
+	if callerID == "" || callerID != app.UserID {
+		return nil, ErrNotApplicant
+	}
+	return app, nil
+}
+
+func (uc *adoptionUsecase) invalidateCachedApplication(ctx context.Context, applicationID string) {
+	if err := uc.cache.DeleteAdoptionApplication(ctx, applicationID); err != nil {
+		log.Printf("Adoption Service | Warning: Failed to delete application %s from cache: %v", applicationID, err)
+	}
+}
+
 func (uc *adoptionUsecase) ListUserAdoptionApplications(ctx context.Context, userID string, page, limit int, statusFilter *domain.ApplicationStatus) ([]*domain.AdoptionApplication, int64, error) {
 	if userID == "" {
 		return nil, 0, errors.New("user ID is required")
@@ -174,4 +604,51 @@ func (uc *adoptionUsecase) ListUserAdoptionApplications(ctx context.Context, use
 		return nil, 0, fmt.Errorf("could not list user adoption applications: %w", err)
 	}
 	return apps, totalCount, nil
-}
\ No newline at end of file
+}
+
+// ListPetAdoptionApplications lists the applications for a pet page by page, oldest first,
+// optionally only those in statusFilter.
+func (uc *adoptionUsecase) ListPetAdoptionApplications(ctx context.Context, petID string, page, limit int, statusFilter *domain.ApplicationStatus) ([]*domain.AdoptionApplication, int64, error) {
+	if petID == "" {
+		return nil, 0, errors.New("pet ID is required")
+	}
+	apps, totalCount, err := uc.repo.ListAdoptionApplicationsByPetID(ctx, petID, page, limit, statusFilter)
+	if err != nil {
+		log.Printf("Adoption Service | Error listing adoption applications for PetID %s: %v", petID, err)
+		return nil, 0, fmt.Errorf("could not list pet adoption applications: %w", err)
+	}
+	return apps, totalCount, nil
+}
+
+// CountPetApplications returns the number of applications per pet, including pets with none.
+func (uc *adoptionUsecase) CountPetApplications(ctx context.Context, petIDs []string) (map[string]int64, error) {
+	for _, petID := range petIDs {
+		if petID == "" {
+			return nil, errors.New("pet IDs must not be empty")
+		}
+	}
+	counts, err := uc.repo.CountApplicationsByPetIDs(ctx, petIDs)
+	if err != nil {
+		log.Printf("Adoption Service | Error counting applications for %d pets: %v", len(petIDs), err)
+		return nil, fmt.Errorf("could not count pet applications: %w", err)
+	}
+	for _, petID := range petIDs {
+		if _, ok := counts[petID]; !ok {
+			counts[petID] = 0
+		}
+	}
+	return counts, nil
+}
+
+// GetPetApplicationStats returns how many applications a pet received, by status.
+func (uc *adoptionUsecase) GetPetApplicationStats(ctx context.Context, petID string) (*domain.PetApplicationStats, error) {
+	if petID == "" {
+		return nil, errors.New("pet ID is required")
+	}
+	stats, err := uc.repo.GetPetApplicationStats(ctx, petID)
+	if err != nil {
+		log.Printf("Adoption Service | Error getting application stats for PetID %s: %v", petID, err)
+		return nil, fmt.Errorf("could not get pet application stats: %w", err)
+	}
+	return stats, nil
+}
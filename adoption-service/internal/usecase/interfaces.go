@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	"github.com/zhandarbeks/petstore-final-project/adoption-service/internal/domain" // Adjust import path
 )
@@ -11,12 +12,44 @@ type CreateAdoptionApplicationRequestData struct {
 	UserID           string
 	PetID            string
 	ApplicationNotes string
+	CallerRoles      []string // Roles of the applicant, taken from request metadata (e.g. "trusted")
+}
+
+// AdoptionPolicy holds the configurable business rules applied by the adoption usecase.
+type AdoptionPolicy struct {
+	// AutoApproveTrustedUsers creates applications from users with the "trusted" role
+	// directly in APPROVED status instead of PENDING_REVIEW.
+	AutoApproveTrustedUsers bool
+	// AutoApproveAllowlist soft-launches auto-approval: while AutoApproveTrustedUsers is off it
+	// still applies to the trusted users listed here (see features.IsFeatureEnabledForUser).
+	AutoApproveAllowlist []string
+	// RequireReviewNotesOnRejection rejects status updates to REJECTED that do not explain why.
+	RequireReviewNotesOnRejection bool
+	// VelocityFlagThreshold flags (but does not block) an application when the applicant has already
+	// applied for this many other pets within VelocityWindow. 0 disables the check.
+	VelocityFlagThreshold int
+	VelocityWindow        time.Duration
+	// ReapplyCooldown is how long after a rejection the user must wait before applying for the
+	// same pet again. 0 allows reapplying immediately.
+	ReapplyCooldown time.Duration
+	// MaxAttachments caps the documents an applicant can attach to one application. 0 means no limit.
+	MaxAttachments int
+	// SeedCacheOnCreate caches a newly created application right away, so a read that follows
+	// the create is served the new application instead of depending on a possibly lagging database read.
+	SeedCacheOnCreate bool
+	// MaxNotesLength caps application and review notes, in characters, after they have been
+	// normalized (see textnorm.Normalize): longer application notes are rejected, longer review
+	// notes are cut. 0 means no limit.
+	MaxNotesLength int
+	// PendingReminderAfter is how long an application may wait in PENDING_REVIEW without an update
+	// before a reminder event is published, and how often the reminder repeats. 0 disables reminders.
+	PendingReminderAfter time.Duration
 }
 
 // UpdateAdoptionApplicationStatusRequestData holds data for updating an application's status.
 type UpdateAdoptionApplicationStatusRequestData struct {
 	NewStatus   domain.ApplicationStatus
-	ReviewNotes string
+	ReviewNotes *string // nil keeps the application's current review notes
 }
 
 // AdoptionUsecase defines the interface for adoption application business logic.
@@ -25,4 +58,27 @@ type AdoptionUsecase interface {
 	GetAdoptionApplicationByID(ctx context.Context, applicationID string) (*domain.AdoptionApplication, error)
 	UpdateAdoptionApplicationStatus(ctx context.Context, applicationID string, reqData UpdateAdoptionApplicationStatusRequestData) (*domain.AdoptionApplication, error)
 	ListUserAdoptionApplications(ctx context.Context, userID string, page, limit int, statusFilter *domain.ApplicationStatus) ([]*domain.AdoptionApplication, int64, error)
+	GetPetApplicationStats(ctx context.Context, petID string) (*domain.PetApplicationStats, error)
+	// CountPetApplications returns the number of applications per pet, including pets with none.
+	CountPetApplications(ctx context.Context, petIDs []string) (map[string]int64, error)
+	// ListPetAdoptionApplications lists the applications for a pet page by page, oldest first,
+	// optionally only those in statusFilter.
+	ListPetAdoptionApplications(ctx context.Context, petID string, page, limit int, statusFilter *domain.ApplicationStatus) ([]*domain.AdoptionApplication, int64, error)
+	// ReopenApplication moves a REJECTED application back to PENDING_REVIEW. callerRoles must include RoleAdmin.
+	ReopenApplication(ctx context.Context, applicationID, reason string, callerRoles []string) (*domain.AdoptionApplication, error)
+	// AddAttachment attaches an uploaded document to the caller's own application.
+	AddAttachment(ctx context.Context, applicationID, callerID string, attachment domain.Attachment) (*domain.AdoptionApplication, error)
+	// RemoveAttachment removes the attachment with the given URL from the caller's own application.
+	RemoveAttachment(ctx context.Context, applicationID, callerID, url string) (*domain.AdoptionApplication, error)
+	// CancelUserApplications cancels all of the user's pending applications and returns how many
+	// were cancelled. callerID must be userID.
+	CancelUserApplications(ctx context.Context, userID, callerID string) (int, error)
+	// CancelAdoptionApplication withdraws one of userID's applications that is still pending
+	// review. callerID must be userID.
+	CancelAdoptionApplication(ctx context.Context, applicationID, userID, callerID string) (*domain.AdoptionApplication, error)
+	// SendPendingReminders publishes a reminder for every application pending review longer than
+	// AdoptionPolicy.PendingReminderAfter as of now, and returns how many were published.
+	SendPendingReminders(ctx context.Context, now time.Time) (int, error)
+	// RunPendingReminders calls SendPendingReminders every interval until ctx is cancelled.
+	RunPendingReminders(ctx context.Context, interval time.Duration)
 }
\ No newline at end of file
@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/zhandarbeks/petstore-final-project/adoption-service/internal/domain"
+)
+
+// pendingReminderBatchSize bounds how many applications one SendPendingReminders call reminds
+// about; the rest are picked up by the next run.
+const pendingReminderBatchSize = 100
+
+func (uc *adoptionUsecase) SendPendingReminders(ctx context.Context, now time.Time) (int, error) {
+	if uc.policy.PendingReminderAfter <= 0 {
+		return 0, nil
+	}
+	pendingSince := now.Add(-uc.policy.PendingReminderAfter)
+	applications, err := uc.repo.ListStalePendingApplications(ctx, pendingSince, pendingReminderBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("could not list pending applications: %w", err)
+	}
+
+	sent := 0
+	for _, app := range applications {
+		// The query already filters, but a status change may have landed since it ran.
+		if app.Status != domain.StatusAppPendingReview || !app.UpdatedAt.Before(pendingSince) {
+			continue
+		}
+		claimed, err := uc.repo.ClaimPendingReminder(ctx, app.ID, pendingSince, now)
+		if err != nil {
+			log.Printf("Adoption Service | Error claiming pending reminder for application ID %s: %v", app.ID, err)
+			continue
+		}
+		if !claimed {
+			continue // Reviewed meanwhile, or another instance sent it
+		}
+		if err := uc.publisher.PublishApplicationPendingReminder(ctx, app); err != nil {
+			// The claim stays, so the reminder is retried once PendingReminderAfter has passed again.
+			log.Printf("Adoption Service | Error publishing pending reminder for application ID %s: %v", app.ID, err)
+			continue
+		}
+		sent++
+	}
+	if sent > 0 {
+		log.Printf("Adoption Service | Sent %d pending review reminders.", sent)
+	}
+	return sent, nil
+}
+
+func (uc *adoptionUsecase) RunPendingReminders(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := uc.SendPendingReminders(ctx, time.Now().UTC()); err != nil {
+				log.Printf("Adoption Service | Pending reminder run failed: %v", err)
+			}
+		}
+	}
+}
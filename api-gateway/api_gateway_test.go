@@ -0,0 +1,3041 @@
+package main_test // Or use a package name like 'apigatewaytest'
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+
+	// Adjust these import paths to match your project's module path and structure
+	"github.com/zhandarbeks/petstore-final-project/api-gateway/internal/client"
+	"github.com/zhandarbeks/petstore-final-project/api-gateway/internal/config"
+	"github.com/zhandarbeks/petstore-final-project/api-gateway/internal/fanout"
+	"github.com/zhandarbeks/petstore-final-project/api-gateway/internal/handler"
+	"github.com/zhandarbeks/petstore-final-project/api-gateway/internal/middleware"
+	"github.com/zhandarbeks/petstore-final-project/api-gateway/internal/oauth"
+	"github.com/zhandarbeks/petstore-final-project/api-gateway/internal/router"
+	"github.com/zhandarbeks/petstore-final-project/api-gateway/internal/server"
+	pbAdoption "github.com/zhandarbeks/petstore-final-project/genprotos/adoption"
+	pbPet "github.com/zhandarbeks/petstore-final-project/genprotos/pet"
+	pbUser "github.com/zhandarbeks/petstore-final-project/genprotos/user"
+	"github.com/zhandarbeks/petstore-final-project/internal/settings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+)
+
+// --- Mock Implementations ---
+// Manual mocks of the gateway's gRPC client interfaces.
+
+// MockUserServiceClient is a mock implementation of client.UserServiceClient.
+type MockUserServiceClient struct {
+	RegisterUserFunc      func(ctx context.Context, req *pbUser.RegisterUserRequest) (*pbUser.UserResponse, error)
+	LoginUserFunc         func(ctx context.Context, req *pbUser.LoginUserRequest) (*pbUser.LoginUserResponse, error)
+	RefreshTokenFunc      func(ctx context.Context, req *pbUser.RefreshTokenRequest) (*pbUser.RefreshTokenResponse, error)
+	LogoutUserFunc        func(ctx context.Context, req *pbUser.LogoutRequest) (*pbUser.EmptyResponse, error)
+	GetUserFunc           func(ctx context.Context, req *pbUser.GetUserRequest) (*pbUser.UserResponse, error)
+	UpdateUserProfileFunc func(ctx context.Context, req *pbUser.UpdateUserProfileRequest) (*pbUser.UserResponse, error)
+	DeleteUserFunc        func(ctx context.Context, req *pbUser.DeleteUserRequest) (*pbUser.EmptyResponse, error)
+	AddFavoritePetFunc    func(ctx context.Context, req *pbUser.FavoritePetRequest) (*pbUser.FavoritePetsResponse, error)
+	RemoveFavoritePetFunc func(ctx context.Context, req *pbUser.FavoritePetRequest) (*pbUser.FavoritePetsResponse, error)
+	ListFavoritePetsFunc  func(ctx context.Context, req *pbUser.ListFavoritePetsRequest) (*pbUser.FavoritePetsResponse, error)
+	AnonymizeUserFunc     func(ctx context.Context, req *pbUser.AnonymizeUserRequest) (*pbUser.UserResponse, error)
+	HealthCheckFunc       func(ctx context.Context) (grpc_health_v1.HealthCheckResponse_ServingStatus, error)
+}
+
+// Ensure MockUserServiceClient implements client.UserServiceClient
+var _ client.UserServiceClient = (*MockUserServiceClient)(nil)
+
+func (m *MockUserServiceClient) RegisterUser(ctx context.Context, req *pbUser.RegisterUserRequest) (*pbUser.UserResponse, error) {
+	if m.RegisterUserFunc != nil {
+		return m.RegisterUserFunc(ctx, req)
+	}
+	return nil, errors.New("RegisterUserFunc not implemented in mock")
+}
+
+func (m *MockUserServiceClient) LoginUser(ctx context.Context, req *pbUser.LoginUserRequest) (*pbUser.LoginUserResponse, error) {
+	if m.LoginUserFunc != nil {
+		return m.LoginUserFunc(ctx, req)
+	}
+	return nil, errors.New("LoginUserFunc not implemented in mock")
+}
+
+func (m *MockUserServiceClient) RefreshToken(ctx context.Context, req *pbUser.RefreshTokenRequest) (*pbUser.RefreshTokenResponse, error) {
+	if m.RefreshTokenFunc != nil {
+		return m.RefreshTokenFunc(ctx, req)
+	}
+	return nil, errors.New("RefreshTokenFunc not implemented in mock")
+}
+
+func (m *MockUserServiceClient) LogoutUser(ctx context.Context, req *pbUser.LogoutRequest) (*pbUser.EmptyResponse, error) {
+	if m.LogoutUserFunc != nil {
+		return m.LogoutUserFunc(ctx, req)
+	}
+	return nil, errors.New("LogoutUserFunc not implemented in mock")
+}
+
+func (m *MockUserServiceClient) GetUser(ctx context.Context, req *pbUser.GetUserRequest) (*pbUser.UserResponse, error) {
+	if m.GetUserFunc != nil {
+		return m.GetUserFunc(ctx, req)
+	}
+	return nil, errors.New("GetUserFunc not implemented in mock")
+}
+
+func (m *MockUserServiceClient) UpdateUserProfile(ctx context.Context, req *pbUser.UpdateUserProfileRequest) (*pbUser.UserResponse, error) {
+	if m.UpdateUserProfileFunc != nil {
+		return m.UpdateUserProfileFunc(ctx, req)
+	}
+	return nil, errors.New("UpdateUserProfileFunc not implemented in mock")
+}
+
+func (m *MockUserServiceClient) DeleteUser(ctx context.Context, req *pbUser.DeleteUserRequest) (*pbUser.EmptyResponse, error) {
+	if m.DeleteUserFunc != nil {
+		return m.DeleteUserFunc(ctx, req)
+	}
+	return nil, errors.New("DeleteUserFunc not implemented in mock")
+}
+
+func (m *MockUserServiceClient) AddFavoritePet(ctx context.Context, req *pbUser.FavoritePetRequest) (*pbUser.FavoritePetsResponse, error) {
+	if m.AddFavoritePetFunc != nil {
+		return m.AddFavoritePetFunc(ctx, req)
+	}
+	return nil, errors.New("AddFavoritePetFunc not implemented in mock")
+}
+
+func (m *MockUserServiceClient) RemoveFavoritePet(ctx context.Context, req *pbUser.FavoritePetRequest) (*pbUser.FavoritePetsResponse, error) {
+	if m.RemoveFavoritePetFunc != nil {
+		return m.RemoveFavoritePetFunc(ctx, req)
+	}
+	return nil, errors.New("RemoveFavoritePetFunc not implemented in mock")
+}
+
+func (m *MockUserServiceClient) ListFavoritePets(ctx context.Context, req *pbUser.ListFavoritePetsRequest) (*pbUser.FavoritePetsResponse, error) {
+	if m.ListFavoritePetsFunc != nil {
+		return m.ListFavoritePetsFunc(ctx, req)
+	}
+	return nil, errors.New("ListFavoritePetsFunc not implemented in mock")
+}
+
+func (m *MockUserServiceClient) AnonymizeUser(ctx context.Context, req *pbUser.AnonymizeUserRequest) (*pbUser.UserResponse, error) {
+	if m.AnonymizeUserFunc != nil {
+		return m.AnonymizeUserFunc(ctx, req)
+	}
+	return nil, errors.New("AnonymizeUserFunc not implemented in mock")
+}
+
+func (m *MockUserServiceClient) HealthCheck(ctx context.Context) (grpc_health_v1.HealthCheckResponse_ServingStatus, error) {
+	if m.HealthCheckFunc != nil {
+		return m.HealthCheckFunc(ctx)
+	}
+	return grpc_health_v1.HealthCheckResponse_SERVING, nil
+}
+
+func (m *MockUserServiceClient) Close() error { return nil }
+
+// MockPetServiceClient is a mock implementation of client.PetServiceClient.
+type MockPetServiceClient struct {
+	CreatePetFunc               func(ctx context.Context, req *pbPet.CreatePetRequest) (*pbPet.PetResponse, error)
+	GetPetFunc                  func(ctx context.Context, req *pbPet.GetPetRequest) (*pbPet.PetResponse, error)
+	UpdatePetFunc               func(ctx context.Context, req *pbPet.UpdatePetRequest) (*pbPet.PetResponse, error)
+	DeletePetFunc               func(ctx context.Context, req *pbPet.DeletePetRequest) (*pbPet.EmptyResponse, error)
+	ListPetsFunc                func(ctx context.Context, req *pbPet.ListPetsRequest) (*pbPet.ListPetsResponse, error)
+	UpdatePetAdoptionStatusFunc func(ctx context.Context, req *pbPet.UpdatePetAdoptionStatusRequest) (*pbPet.PetResponse, error)
+	GetImageUploadURLFunc       func(ctx context.Context, req *pbPet.GetImageUploadURLRequest) (*pbPet.ImageUploadTarget, error)
+	AddImageURLsFunc            func(ctx context.Context, req *pbPet.AddImageURLsRequest) (*pbPet.PetResponse, error)
+	ListRecentlyAdoptedFunc     func(ctx context.Context, req *pbPet.ListRecentlyAdoptedRequest) (*pbPet.ListRecentlyAdoptedResponse, error)
+	GetPetFacetsFunc            func(ctx context.Context, req *pbPet.GetPetFacetsRequest) (*pbPet.PetFacetsResponse, error)
+	SuggestBreedsFunc           func(ctx context.Context, req *pbPet.SuggestBreedsRequest) (*pbPet.SuggestBreedsResponse, error)
+	AddPetTagsFunc              func(ctx context.Context, req *pbPet.PetTagsRequest) (*pbPet.PetResponse, error)
+	RemovePetTagsFunc           func(ctx context.Context, req *pbPet.PetTagsRequest) (*pbPet.PetResponse, error)
+	AdminSetPetStatusFunc       func(ctx context.Context, req *pbPet.AdminSetPetStatusRequest) (*pbPet.PetResponse, error)
+	TransferPetListingFunc      func(ctx context.Context, req *pbPet.TransferPetListingRequest) (*pbPet.PetResponse, error)
+	RestorePetFunc              func(ctx context.Context, req *pbPet.RestorePetRequest) (*pbPet.PetResponse, error)
+	HealthCheckFunc             func(ctx context.Context) (grpc_health_v1.HealthCheckResponse_ServingStatus, error)
+}
+
+// Ensure MockPetServiceClient implements client.PetServiceClient
+var _ client.PetServiceClient = (*MockPetServiceClient)(nil)
+
+func (m *MockPetServiceClient) CreatePet(ctx context.Context, req *pbPet.CreatePetRequest) (*pbPet.PetResponse, error) {
+	if m.CreatePetFunc != nil {
+		return m.CreatePetFunc(ctx, req)
+	}
+	return nil, errors.New("CreatePetFunc not implemented in mock")
+}
+
+func (m *MockPetServiceClient) GetPet(ctx context.Context, req *pbPet.GetPetRequest) (*pbPet.PetResponse, error) {
+	if m.GetPetFunc != nil {
+		return m.GetPetFunc(ctx, req)
+	}
+	return nil, errors.New("GetPetFunc not implemented in mock")
+}
+
+func (m *MockPetServiceClient) UpdatePet(ctx context.Context, req *pbPet.UpdatePetRequest) (*pbPet.PetResponse, error) {
+	if m.UpdatePetFunc != nil {
+		return m.UpdatePetFunc(ctx, req)
+	}
+	return nil, errors.New("UpdatePetFunc not implemented in mock")
+}
+
+func (m *MockPetServiceClient) DeletePet(ctx context.Context, req *pbPet.DeletePetRequest) (*pbPet.EmptyResponse, error) {
+	if m.DeletePetFunc != nil {
+		return m.DeletePetFunc(ctx, req)
+	}
+	return nil, errors.New("DeletePetFunc not implemented in mock")
+}
+
+func (m *MockPetServiceClient) ListPets(ctx context.Context, req *pbPet.ListPetsRequest) (*pbPet.ListPetsResponse, error) {
+	if m.ListPetsFunc != nil {
+		return m.ListPetsFunc(ctx, req)
+	}
+	return nil, errors.New("ListPetsFunc not implemented in mock")
+}
+
+func (m *MockPetServiceClient) UpdatePetAdoptionStatus(ctx context.Context, req *pbPet.UpdatePetAdoptionStatusRequest) (*pbPet.PetResponse, error) {
+	if m.UpdatePetAdoptionStatusFunc != nil {
+		return m.UpdatePetAdoptionStatusFunc(ctx, req)
+	}
+	return nil, errors.New("UpdatePetAdoptionStatusFunc not implemented in mock")
+}
+
+func (m *MockPetServiceClient) GetImageUploadURL(ctx context.Context, req *pbPet.GetImageUploadURLRequest) (*pbPet.ImageUploadTarget, error) {
+	if m.GetImageUploadURLFunc != nil {
+		return m.GetImageUploadURLFunc(ctx, req)
+	}
+	return nil, errors.New("GetImageUploadURLFunc not implemented in mock")
+}
+
+func (m *MockPetServiceClient) AddImageURLs(ctx context.Context, req *pbPet.AddImageURLsRequest) (*pbPet.PetResponse, error) {
+	if m.AddImageURLsFunc != nil {
+		return m.AddImageURLsFunc(ctx, req)
+	}
+	return nil, errors.New("AddImageURLsFunc not implemented in mock")
+}
+
+func (m *MockPetServiceClient) ListRecentlyAdopted(ctx context.Context, req *pbPet.ListRecentlyAdoptedRequest) (*pbPet.ListRecentlyAdoptedResponse, error) {
+	if m.ListRecentlyAdoptedFunc != nil {
+		return m.ListRecentlyAdoptedFunc(ctx, req)
+	}
+	return nil, errors.New("ListRecentlyAdoptedFunc not implemented in mock")
+}
+
+func (m *MockPetServiceClient) GetPetFacets(ctx context.Context, req *pbPet.GetPetFacetsRequest) (*pbPet.PetFacetsResponse, error) {
+	if m.GetPetFacetsFunc != nil {
+		return m.GetPetFacetsFunc(ctx, req)
+	}
+	return nil, errors.New("GetPetFacetsFunc not implemented in mock")
+}
+
+func (m *MockPetServiceClient) SuggestBreeds(ctx context.Context, req *pbPet.SuggestBreedsRequest) (*pbPet.SuggestBreedsResponse, error) {
+	if m.SuggestBreedsFunc != nil {
+		return m.SuggestBreedsFunc(ctx, req)
+	}
+	return nil, errors.New("SuggestBreedsFunc not implemented in mock")
+}
+
+func (m *MockPetServiceClient) AddPetTags(ctx context.Context, req *pbPet.PetTagsRequest) (*pbPet.PetResponse, error) {
+	if m.AddPetTagsFunc != nil {
+		return m.AddPetTagsFunc(ctx, req)
+	}
+	return nil, errors.New("AddPetTagsFunc not implemented in mock")
+}
+
+func (m *MockPetServiceClient) RemovePetTags(ctx context.Context, req *pbPet.PetTagsRequest) (*pbPet.PetResponse, error) {
+	if m.RemovePetTagsFunc != nil {
+		return m.RemovePetTagsFunc(ctx, req)
+	}
+	return nil, errors.New("RemovePetTagsFunc not implemented in mock")
+}
+
+func (m *MockPetServiceClient) AdminSetPetStatus(ctx context.Context, req *pbPet.AdminSetPetStatusRequest) (*pbPet.PetResponse, error) {
+	if m.AdminSetPetStatusFunc != nil {
+		return m.AdminSetPetStatusFunc(ctx, req)
+	}
+	return nil, errors.New("AdminSetPetStatusFunc not implemented in mock")
+}
+
+func (m *MockPetServiceClient) TransferPetListing(ctx context.Context, req *pbPet.TransferPetListingRequest) (*pbPet.PetResponse, error) {
+	if m.TransferPetListingFunc != nil {
+		return m.TransferPetListingFunc(ctx, req)
+	}
+	return nil, errors.New("TransferPetListingFunc not implemented in mock")
+}
+
+func (m *MockPetServiceClient) RestorePet(ctx context.Context, req *pbPet.RestorePetRequest) (*pbPet.PetResponse, error) {
+	if m.RestorePetFunc != nil {
+		return m.RestorePetFunc(ctx, req)
+	}
+	return nil, errors.New("RestorePetFunc not implemented in mock")
+}
+
+func (m *MockPetServiceClient) HealthCheck(ctx context.Context) (grpc_health_v1.HealthCheckResponse_ServingStatus, error) {
+	if m.HealthCheckFunc != nil {
+		return m.HealthCheckFunc(ctx)
+	}
+	return grpc_health_v1.HealthCheckResponse_SERVING, nil
+}
+
+func (m *MockPetServiceClient) Close() error { return nil }
+
+// MockAdoptionServiceClient is a mock implementation of client.AdoptionServiceClient.
+type MockAdoptionServiceClient struct {
+	CreateAdoptionApplicationFunc       func(ctx context.Context, req *pbAdoption.CreateAdoptionApplicationRequest) (*pbAdoption.AdoptionApplicationResponse, error)
+	GetAdoptionApplicationFunc          func(ctx context.Context, req *pbAdoption.GetAdoptionApplicationRequest) (*pbAdoption.AdoptionApplicationResponse, error)
+	UpdateAdoptionApplicationStatusFunc func(ctx context.Context, req *pbAdoption.UpdateAdoptionApplicationStatusRequest) (*pbAdoption.AdoptionApplicationResponse, error)
+	ListUserAdoptionApplicationsFunc    func(ctx context.Context, req *pbAdoption.ListUserAdoptionApplicationsRequest) (*pbAdoption.ListAdoptionApplicationsResponse, error)
+	ListPetAdoptionApplicationsFunc     func(ctx context.Context, req *pbAdoption.ListPetAdoptionApplicationsRequest) (*pbAdoption.ListAdoptionApplicationsResponse, error)
+	GetPetApplicationStatsFunc          func(ctx context.Context, req *pbAdoption.GetPetApplicationStatsRequest) (*pbAdoption.PetApplicationStatsResponse, error)
+	CountPetApplicationsFunc            func(ctx context.Context, req *pbAdoption.CountPetApplicationsRequest) (*pbAdoption.CountPetApplicationsResponse, error)
+	ReopenApplicationFunc               func(ctx context.Context, req *pbAdoption.ReopenApplicationRequest) (*pbAdoption.AdoptionApplicationResponse, error)
+	AddApplicationAttachmentFunc        func(ctx context.Context, req *pbAdoption.AddApplicationAttachmentRequest) (*pbAdoption.AdoptionApplicationResponse, error)
+	RemoveApplicationAttachmentFunc     func(ctx context.Context, req *pbAdoption.RemoveApplicationAttachmentRequest) (*pbAdoption.AdoptionApplicationResponse, error)
+	CancelUserApplicationsFunc          func(ctx context.Context, req *pbAdoption.CancelUserApplicationsRequest) (*pbAdoption.CancelUserApplicationsResponse, error)
+	CancelAdoptionApplicationFunc       func(ctx context.Context, req *pbAdoption.CancelAdoptionApplicationRequest) (*pbAdoption.AdoptionApplicationResponse, error)
+	HealthCheckFunc                     func(ctx context.Context) (grpc_health_v1.HealthCheckResponse_ServingStatus, error)
+}
+
+// Ensure MockAdoptionServiceClient implements client.AdoptionServiceClient
+var _ client.AdoptionServiceClient = (*MockAdoptionServiceClient)(nil)
+
+func (m *MockAdoptionServiceClient) CreateAdoptionApplication(ctx context.Context, req *pbAdoption.CreateAdoptionApplicationRequest) (*pbAdoption.AdoptionApplicationResponse, error) {
+	if m.CreateAdoptionApplicationFunc != nil {
+		return m.CreateAdoptionApplicationFunc(ctx, req)
+	}
+	return nil, errors.New("CreateAdoptionApplicationFunc not implemented in mock")
+}
+
+func (m *MockAdoptionServiceClient) GetAdoptionApplication(ctx context.Context, req *pbAdoption.GetAdoptionApplicationRequest) (*pbAdoption.AdoptionApplicationResponse, error) {
+	if m.GetAdoptionApplicationFunc != nil {
+		return m.GetAdoptionApplicationFunc(ctx, req)
+	}
+	return nil, errors.New("GetAdoptionApplicationFunc not implemented in mock")
+}
+
+func (m *MockAdoptionServiceClient) UpdateAdoptionApplicationStatus(ctx context.Context, req *pbAdoption.UpdateAdoptionApplicationStatusRequest) (*pbAdoption.AdoptionApplicationResponse, error) {
+	if m.UpdateAdoptionApplicationStatusFunc != nil {
+		return m.UpdateAdoptionApplicationStatusFunc(ctx, req)
+	}
+	return nil, errors.New("UpdateAdoptionApplicationStatusFunc not implemented in mock")
+}
+
+func (m *MockAdoptionServiceClient) ListUserAdoptionApplications(ctx context.Context, req *pbAdoption.ListUserAdoptionApplicationsRequest) (*pbAdoption.ListAdoptionApplicationsResponse, error) {
+	if m.ListUserAdoptionApplicationsFunc != nil {
+		return m.ListUserAdoptionApplicationsFunc(ctx, req)
+	}
+	return nil, errors.New("ListUserAdoptionApplicationsFunc not implemented in mock")
+}
+
+func (m *MockAdoptionServiceClient) GetPetApplicationStats(ctx context.Context, req *pbAdoption.GetPetApplicationStatsRequest) (*pbAdoption.PetApplicationStatsResponse, error) {
+	if m.GetPetApplicationStatsFunc != nil {
+		return m.GetPetApplicationStatsFunc(ctx, req)
+	}
+	return nil, errors.New("GetPetApplicationStatsFunc not implemented in mock")
+}
+
+func (m *MockAdoptionServiceClient) CountPetApplications(ctx context.Context, req *pbAdoption.CountPetApplicationsRequest) (*pbAdoption.CountPetApplicationsResponse, error) {
+	if m.CountPetApplicationsFunc != nil {
+		return m.CountPetApplicationsFunc(ctx, req)
+	}
+	return nil, errors.New("CountPetApplicationsFunc not implemented in mock")
+}
+
+func (m *MockAdoptionServiceClient) ReopenApplication(ctx context.Context, req *pbAdoption.ReopenApplicationRequest) (*pbAdoption.AdoptionApplicationResponse, error) {
+	if m.ReopenApplicationFunc != nil {
+		return m.ReopenApplicationFunc(ctx, req)
+	}
+	return nil, errors.New("ReopenApplicationFunc not implemented in mock")
+}
+
+func (m *MockAdoptionServiceClient) AddApplicationAttachment(ctx context.Context, req *pbAdoption.AddApplicationAttachmentRequest) (*pbAdoption.AdoptionApplicationResponse, error) {
+	if m.AddApplicationAttachmentFunc != nil {
+		return m.AddApplicationAttachmentFunc(ctx, req)
+	}
+	return nil, errors.New("AddApplicationAttachmentFunc not implemented in mock")
+}
+
+func (m *MockAdoptionServiceClient) RemoveApplicationAttachment(ctx context.Context, req *pbAdoption.RemoveApplicationAttachmentRequest) (*pbAdoption.AdoptionApplicationResponse, error) {
+	if m.RemoveApplicationAttachmentFunc != nil {
+		return m.RemoveApplicationAttachmentFunc(ctx, req)
+	}
+	return nil, errors.New("RemoveApplicationAttachmentFunc not implemented in mock")
+}
+
+func (m *MockAdoptionServiceClient) CancelUserApplications(ctx context.Context, req *pbAdoption.CancelUserApplicationsRequest) (*pbAdoption.CancelUserApplicationsResponse, error) {
+	if m.CancelUserApplicationsFunc != nil {
+		return m.CancelUserApplicationsFunc(ctx, req)
+	}
+	return nil, errors.New("CancelUserApplicationsFunc not implemented in mock")
+}
+
+func (m *MockAdoptionServiceClient) ListPetAdoptionApplications(ctx context.Context, req *pbAdoption.ListPetAdoptionApplicationsRequest) (*pbAdoption.ListAdoptionApplicationsResponse, error) {
+	if m.ListPetAdoptionApplicationsFunc != nil {
+		return m.ListPetAdoptionApplicationsFunc(ctx, req)
+	}
+	return nil, errors.New("ListPetAdoptionApplicationsFunc not implemented in mock")
+}
+
+func (m *MockAdoptionServiceClient) CancelAdoptionApplication(ctx context.Context, req *pbAdoption.CancelAdoptionApplicationRequest) (*pbAdoption.AdoptionApplicationResponse, error) {
+	if m.CancelAdoptionApplicationFunc != nil {
+		return m.CancelAdoptionApplicationFunc(ctx, req)
+	}
+	return nil, errors.New("CancelAdoptionApplicationFunc not implemented in mock")
+}
+
+func (m *MockAdoptionServiceClient) HealthCheck(ctx context.Context) (grpc_health_v1.HealthCheckResponse_ServingStatus, error) {
+	if m.HealthCheckFunc != nil {
+		return m.HealthCheckFunc(ctx)
+	}
+	return grpc_health_v1.HealthCheckResponse_SERVING, nil
+}
+
+func (m *MockAdoptionServiceClient) Close() error { return nil }
+
+// --- Helpers ---
+
+// performRequest runs a single request against a Gin engine and returns the recorder.
+func performRequest(r http.Handler, method, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+const testJWTSecret = "test-secret-key-that-is-at-least-32-bytes"
+
+// signTestToken issues an access token shaped like the user-service's.
+func signTestToken(t *testing.T, userID string) string {
+	t.Helper()
+	return signTestTokenWithRole(t, userID, "")
+}
+
+// signTestTokenWithRole signs an access token carrying role in its "rol" claim; an empty role
+// leaves the claim out, like tokens issued before users had roles.
+func signTestTokenWithRole(t *testing.T, userID, role string) string {
+	t.Helper()
+	extra := jwt.MapClaims{}
+	if role != "" {
+		extra["rol"] = role
+	}
+	return signTestTokenWithClaims(t, userID, extra)
+}
+
+// signTestTokenWithClaims signs an access token with the usual claims plus extra.
+func signTestTokenWithClaims(t *testing.T, userID string, extra jwt.MapClaims) string {
+	t.Helper()
+	claims := jwt.MapClaims{
+		"sub": userID,
+		"unm": "tester",
+		"exp": time.Now().Add(time.Hour).Unix(),
+		"iat": time.Now().Unix(),
+		"iss": "petstore-user-service",
+		"aud": "petstore-clients",
+	}
+	for name, value := range extra {
+		claims[name] = value
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signed, err := token.SignedString([]byte(testJWTSecret))
+	if err != nil {
+		t.Fatalf("could not sign test token: %v", err)
+	}
+	return signed
+}
+
+// newTestRouter builds the full gateway router around mock clients.
+func newTestRouter(userClient *MockUserServiceClient, petClient *MockPetServiceClient, adoptionClient *MockAdoptionServiceClient, maintenance *middleware.Maintenance, adminToken string) *gin.Engine {
+	return router.New(
+		handler.NewUserHandler(userClient),
+		handler.NewPetHandler(petClient),
+		handler.NewAdoptionHandler(adoptionClient),
+		handler.NewCompositeHandler(userClient, petClient, adoptionClient),
+		handler.NewAdminHandler(maintenance, nil), // No notification client; email previews are tested in notification-service
+		handler.NewHealthHandler(userClient, petClient, adoptionClient),
+		maintenance,
+		middleware.RequireAdminToken(adminToken),
+		middleware.RequireAuth(testJWTSecret),
+		nil, // No body logging
+		nil, // Default access logger
+		nil, // No downstream health checks
+		nil, // No composite limit
+	)
+}
+
+// --- Test Functions ---
+
+func TestCompositeHandler_GetPetOverview_MergesStats(t *testing.T) {
+	mockPetClient := &MockPetServiceClient{
+		GetPetFunc: func(ctx context.Context, req *pbPet.GetPetRequest) (*pbPet.PetResponse, error) {
+			return &pbPet.PetResponse{Pet: &pbPet.Pet{Id: req.GetPetId(), Name: "Buddy"}}, nil
+		},
+	}
+	statsAvailable := true
+	mockAdoptionClient := &MockAdoptionServiceClient{
+		GetPetApplicationStatsFunc: func(ctx context.Context, req *pbAdoption.GetPetApplicationStatsRequest) (*pbAdoption.PetApplicationStatsResponse, error) {
+			if !statsAvailable {
+				return nil, status.Error(codes.Unavailable, "adoption service down")
+			}
+			return &pbAdoption.PetApplicationStatsResponse{Stats: &pbAdoption.PetApplicationStats{PetId: req.GetPetId(), TotalApplications: 3, PendingReview: 2, Rejected: 1}}, nil
+		},
+	}
+	h := handler.NewCompositeHandler(&MockUserServiceClient{}, mockPetClient, mockAdoptionClient)
+	r := gin.New()
+	r.GET("/pets/:petId/overview", h.GetPetOverview)
+
+	w := performRequest(r, http.MethodGet, "/pets/pet1/overview")
+	if w.Code != http.StatusOK {
+		t.Fatalf("GetPetOverview() status = %d, want %d (body: %s)", w.Code, http.StatusOK, w.Body.String())
+	}
+	var resp handler.PetOverviewResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("could not decode response: %v", err)
+	}
+	if resp.Pet.GetName() != "Buddy" {
+		t.Errorf("GetPetOverview() pet = %v, want Buddy", resp.Pet)
+	}
+	if resp.Stats.GetTotalApplications() != 3 || resp.Stats.GetPendingReview() != 2 || resp.Stats.GetRejected() != 1 {
+		t.Errorf("GetPetOverview() stats = %v, want 3 total, 2 pending, 1 rejected", resp.Stats)
+	}
+	if len(resp.Warnings) != 0 {
+		t.Errorf("GetPetOverview() warnings = %v, want none", resp.Warnings)
+	}
+
+	// Stats failures degrade to a warning; the pet is still returned.
+	statsAvailable = false
+	w = performRequest(r, http.MethodGet, "/pets/pet1/overview")
+	if w.Code != http.StatusOK {
+		t.Fatalf("GetPetOverview() without stats status = %d, want %d", w.Code, http.StatusOK)
+	}
+	resp = handler.PetOverviewResponse{}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("could not decode response: %v", err)
+	}
+	if resp.Pet.GetName() != "Buddy" || resp.Stats != nil {
+		t.Errorf("GetPetOverview() without stats = pet %v, stats %v; want pet and no stats", resp.Pet, resp.Stats)
+	}
+	if len(resp.Warnings) != 1 || resp.Warnings[0].Section != "stats" {
+		t.Errorf("GetPetOverview() warnings = %v, want one stats warning", resp.Warnings)
+	}
+}
+
+func TestCompositeHandler_ListPetsNeedingAttention(t *testing.T) {
+	now := time.Now().UTC()
+	daysAgo := func(days int) string { return now.AddDate(0, 0, -days).Format(time.RFC3339) }
+	seededPets := []*pbPet.Pet{
+		{Id: "old-unwanted", CreatedAt: daysAgo(90), AdoptionStatus: pbPet.AdoptionStatus_AVAILABLE},
+		{Id: "old-with-applications", CreatedAt: daysAgo(80), AdoptionStatus: pbPet.AdoptionStatus_AVAILABLE},
+		{Id: "old-pending", CreatedAt: daysAgo(70), AdoptionStatus: pbPet.AdoptionStatus_PENDING_ADOPTION},
+		{Id: "borderline-unwanted", CreatedAt: daysAgo(31), AdoptionStatus: pbPet.AdoptionStatus_AVAILABLE},
+		{Id: "new-unwanted", CreatedAt: daysAgo(5), AdoptionStatus: pbPet.AdoptionStatus_AVAILABLE},
+	}
+	seededApplicationCounts := map[string]int32{"old-with-applications": 2, "old-pending": 1}
+
+	// The mocks filter the seeded data the way pet-service and adoption-service do.
+	petClient := &MockPetServiceClient{
+		ListPetsFunc: func(ctx context.Context, req *pbPet.ListPetsRequest) (*pbPet.ListPetsResponse, error) {
+			if req.GetSort() != "oldest" {
+				t.Errorf("ListPets() sort = %q, want oldest", req.GetSort())
+			}
+			before, err := time.Parse(time.RFC3339, req.GetCreatedBefore())
+			if err != nil {
+				t.Fatalf("ListPets() created_before = %q: %v", req.GetCreatedBefore(), err)
+			}
+			var matching []*pbPet.Pet
+			for _, pet := range seededPets {
+				createdAt, _ := time.Parse(time.RFC3339, pet.GetCreatedAt())
+				if pet.GetAdoptionStatus() == req.GetStatusFilter() && createdAt.Before(before) {
+					matching = append(matching, pet)
+				}
+			}
+			start := int((req.GetPage() - 1) * req.GetLimit())
+			if start > len(matching) {
+				start = len(matching)
+			}
+			end := start + int(req.GetLimit())
+			if end > len(matching) {
+				end = len(matching)
+			}
+			return &pbPet.ListPetsResponse{Pets: matching[start:end], TotalCount: int32(len(matching)), Page: req.GetPage(), Limit: req.GetLimit()}, nil
+		},
+	}
+	adoptionClient := &MockAdoptionServiceClient{
+		CountPetApplicationsFunc: func(ctx context.Context, req *pbAdoption.CountPetApplicationsRequest) (*pbAdoption.CountPetApplicationsResponse, error) {
+			counts := make(map[string]int32, len(req.GetPetIds()))
+			for _, petID := range req.GetPetIds() {
+				counts[petID] = seededApplicationCounts[petID]
+			}
+			return &pbAdoption.CountPetApplicationsResponse{Counts: counts}, nil
+		},
+	}
+	r := newTestRouter(&MockUserServiceClient{}, petClient, adoptionClient, middleware.NewMaintenance(middleware.MaintenanceOff, ""), "admin-token")
+
+	report := func(query string) (int, handler.PetsNeedingAttentionResponse) {
+		req := httptest.NewRequest(http.MethodGet, "/admin/reports/pets-needing-attention"+query, nil)
+		req.Header.Set("X-Admin-Token", "admin-token")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var resp handler.PetsNeedingAttentionResponse
+		if w.Code == http.StatusOK {
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("could not decode response: %v", err)
+			}
+		}
+		return w.Code, resp
+	}
+	petIDs := func(resp handler.PetsNeedingAttentionResponse) []string {
+		ids := []string{}
+		for _, pet := range resp.Pets {
+			ids = append(ids, pet.GetId())
+		}
+		return ids
+	}
+
+	code, resp := report("")
+	if code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", code, http.StatusOK)
+	}
+	if want := []string{"old-unwanted", "borderline-unwanted"}; !reflect.DeepEqual(petIDs(resp), want) {
+		t.Errorf("pets with default min_days = %v, want %v", petIDs(resp), want)
+	}
+	if resp.MinDays != 30 {
+		t.Errorf("min_days = %d, want 30", resp.MinDays)
+	}
+
+	if _, resp := report("?min_days=60"); !reflect.DeepEqual(petIDs(resp), []string{"old-unwanted"}) {
+		t.Errorf("pets with min_days=60 = %v, want [old-unwanted]", petIDs(resp))
+	}
+	if _, resp := report("?min_days=1&limit=1"); !reflect.DeepEqual(petIDs(resp), []string{"old-unwanted"}) {
+		t.Errorf("pets with limit=1 = %v, want [old-unwanted]", petIDs(resp))
+	}
+	if code, _ := report("?min_days=soon"); code != http.StatusBadRequest {
+		t.Errorf("status with invalid min_days = %d, want %d", code, http.StatusBadRequest)
+	}
+}
+
+func TestCompositeHandler_GetMyDashboard_DegradesPerSection(t *testing.T) {
+	var applicationsDown atomic.Bool
+	mockUserClient := &MockUserServiceClient{
+		GetUserFunc: func(ctx context.Context, req *pbUser.GetUserRequest) (*pbUser.UserResponse, error) {
+			return &pbUser.UserResponse{User: &pbUser.User{Id: req.GetUserId(), Username: "jane"}}, nil
+		},
+		ListFavoritePetsFunc: func(ctx context.Context, req *pbUser.ListFavoritePetsRequest) (*pbUser.FavoritePetsResponse, error) {
+			return &pbUser.FavoritePetsResponse{PetIds: []string{"fav1", "fav2"}}, nil
+		},
+	}
+	mockPetClient := &MockPetServiceClient{
+		ListPetsFunc: func(ctx context.Context, req *pbPet.ListPetsRequest) (*pbPet.ListPetsResponse, error) {
+			if req.GetListedByUserIdFilter() != "user1" {
+				t.Errorf("ListPets() listed_by_user_id_filter = %q, want user1", req.GetListedByUserIdFilter())
+			}
+			return &pbPet.ListPetsResponse{Pets: []*pbPet.Pet{{Id: "pet1", Name: "Buddy"}}}, nil
+		},
+	}
+	mockAdoptionClient := &MockAdoptionServiceClient{
+		ListUserAdoptionApplicationsFunc: func(ctx context.Context, req *pbAdoption.ListUserAdoptionApplicationsRequest) (*pbAdoption.ListAdoptionApplicationsResponse, error) {
+			if applicationsDown.Load() {
+				return nil, status.Error(codes.Unavailable, "adoption service down")
+			}
+			return &pbAdoption.ListAdoptionApplicationsResponse{TotalCount: 3, Applications: []*pbAdoption.AdoptionApplication{
+				{Id: "app1", Status: pbAdoption.ApplicationStatus_PENDING_REVIEW},
+				{Id: "app2", Status: pbAdoption.ApplicationStatus_PENDING_REVIEW},
+				{Id: "app3", Status: pbAdoption.ApplicationStatus_REJECTED},
+			}}, nil
+		},
+	}
+	h := handler.NewCompositeHandler(mockUserClient, mockPetClient, mockAdoptionClient)
+	r := gin.New()
+	r.GET("/users/me/dashboard", func(c *gin.Context) { c.Set(middleware.ContextUserIDKey, "user1") }, h.GetMyDashboard)
+
+	w := performRequest(r, http.MethodGet, "/users/me/dashboard")
+	if w.Code != http.StatusOK {
+		t.Fatalf("GetMyDashboard() status = %d, want %d (body: %s)", w.Code, http.StatusOK, w.Body.String())
+	}
+	var resp handler.DashboardResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("could not decode response: %v", err)
+	}
+	if resp.Profile.GetUsername() != "jane" || len(resp.ListedPets) != 1 || len(resp.Applications) != 3 || len(resp.FavoritePetIDs) != 2 {
+		t.Errorf("GetMyDashboard() = %+v, want every section", resp)
+	}
+	if resp.ApplicationSummary == nil || resp.ApplicationSummary.Total != 3 || resp.ApplicationSummary.PendingReview != 2 || resp.ApplicationSummary.Rejected != 1 {
+		t.Errorf("GetMyDashboard() summary = %+v, want 3 total, 2 pending, 1 rejected", resp.ApplicationSummary)
+	}
+	if len(resp.Warnings) != 0 {
+		t.Errorf("GetMyDashboard() warnings = %v, want none", resp.Warnings)
+	}
+
+	// An unavailable service only drops its own section.
+	applicationsDown.Store(true)
+	w = performRequest(r, http.MethodGet, "/users/me/dashboard")
+	if w.Code != http.StatusOK {
+		t.Fatalf("GetMyDashboard() without applications status = %d, want %d", w.Code, http.StatusOK)
+	}
+	resp = handler.DashboardResponse{}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("could not decode response: %v", err)
+	}
+	if resp.Applications != nil || resp.ApplicationSummary != nil || resp.Profile == nil || len(resp.ListedPets) != 1 || len(resp.FavoritePetIDs) != 2 {
+		t.Errorf("GetMyDashboard() without applications = %+v, want every other section", resp)
+	}
+	if len(resp.Warnings) != 1 || resp.Warnings[0].Section != "applications" {
+		t.Errorf("GetMyDashboard() warnings = %v, want one applications warning", resp.Warnings)
+	}
+}
+
+func TestFanout_Run_AggregatesResultsAndErrors(t *testing.T) {
+	var running, maxRunning int32
+	track := func(result interface{}, err error) func(ctx context.Context) (interface{}, error) {
+		return func(ctx context.Context) (interface{}, error) {
+			n := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			for {
+				m := atomic.LoadInt32(&maxRunning)
+				if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			return result, err
+		}
+	}
+	downstreamErr := status.Error(codes.Unavailable, "down")
+
+	results, errs := fanout.Run(context.Background(), fanout.Options{MaxConcurrency: 2},
+		fanout.Call{Name: "a", Run: track("A", nil)},
+		fanout.Call{Name: "b", Run: track(nil, downstreamErr)},
+		fanout.Call{Name: "c", Run: track("C", nil)},
+	)
+	if len(results) != 2 || results["a"] != "A" || results["c"] != "C" {
+		t.Errorf("Run() results = %v, want a and c", results)
+	}
+	if len(errs) != 1 || !errors.Is(errs["b"], downstreamErr) {
+		t.Errorf("Run() errors = %v, want only b", errs)
+	}
+	if got := atomic.LoadInt32(&maxRunning); got > 2 {
+		t.Errorf("Run() ran %d calls at once, want at most 2", got)
+	}
+}
+
+func TestFanout_Run_TimeoutMarksSlowCalls(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	start := time.Now()
+	results, errs := fanout.Run(context.Background(), fanout.Options{Timeout: 50 * time.Millisecond},
+		fanout.Call{Name: "fast", Run: func(ctx context.Context) (interface{}, error) { return "ok", nil }},
+		fanout.Call{Name: "hung", Run: func(ctx context.Context) (interface{}, error) {
+			<-release // Ignores ctx, like a misbehaving client
+			return "late", nil
+		}},
+	)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Run() took %s, want it to return at the deadline", elapsed)
+	}
+	if results["fast"] != "ok" {
+		t.Errorf("Run() results = %v, want fast", results)
+	}
+	if _, ok := results["hung"]; ok || !errors.Is(errs["hung"], context.DeadlineExceeded) {
+		t.Errorf("Run() errors = %v, want hung to exceed the deadline", errs)
+	}
+}
+
+func TestCompositeHandler_GetPetDetails_ListerLookupFails(t *testing.T) {
+	mockPetClient := &MockPetServiceClient{
+		GetPetFunc: func(ctx context.Context, req *pbPet.GetPetRequest) (*pbPet.PetResponse, error) {
+			return &pbPet.PetResponse{Pet: &pbPet.Pet{Id: req.GetPetId(), Name: "Buddy", ListedByUserId: "user1"}}, nil
+		},
+	}
+	mockUserClient := &MockUserServiceClient{
+		GetUserFunc: func(ctx context.Context, req *pbUser.GetUserRequest) (*pbUser.UserResponse, error) {
+			return nil, status.Error(codes.Unavailable, "user service down")
+		},
+	}
+	h := handler.NewCompositeHandler(mockUserClient, mockPetClient, &MockAdoptionServiceClient{})
+	r := gin.New()
+	r.GET("/pets/:petId/details", h.GetPetDetails)
+
+	w := performRequest(r, http.MethodGet, "/pets/pet1/details")
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("GetPetDetails() status = %d, want %d (body: %s)", w.Code, http.StatusOK, w.Body.String())
+	}
+	var resp handler.PetDetailsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("could not decode response: %v", err)
+	}
+	if resp.Pet == nil || resp.Pet.GetName() != "Buddy" {
+		t.Errorf("GetPetDetails() pet = %v, want Buddy", resp.Pet)
+	}
+	if resp.Lister != nil {
+		t.Errorf("GetPetDetails() lister = %v, want nil when lookup fails", resp.Lister)
+	}
+	if len(resp.Warnings) != 1 || resp.Warnings[0].Section != "lister" {
+		t.Errorf("GetPetDetails() warnings = %v, want one lister warning", resp.Warnings)
+	}
+}
+
+func TestCompositeHandler_GetPetDetails_Success(t *testing.T) {
+	mockPetClient := &MockPetServiceClient{
+		GetPetFunc: func(ctx context.Context, req *pbPet.GetPetRequest) (*pbPet.PetResponse, error) {
+			return &pbPet.PetResponse{Pet: &pbPet.Pet{Id: req.GetPetId(), Name: "Buddy", ListedByUserId: "user1"}}, nil
+		},
+	}
+	mockUserClient := &MockUserServiceClient{
+		GetUserFunc: func(ctx context.Context, req *pbUser.GetUserRequest) (*pbUser.UserResponse, error) {
+			return &pbUser.UserResponse{User: &pbUser.User{Id: req.GetUserId(), Username: "shelter", Email: "private@example.com"}}, nil
+		},
+	}
+	h := handler.NewCompositeHandler(mockUserClient, mockPetClient, &MockAdoptionServiceClient{})
+	r := gin.New()
+	r.GET("/pets/:petId/details", h.GetPetDetails)
+
+	w := performRequest(r, http.MethodGet, "/pets/pet1/details")
+
+	var resp handler.PetDetailsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("could not decode response: %v", err)
+	}
+	if resp.Lister == nil || resp.Lister.Username != "shelter" {
+		t.Errorf("GetPetDetails() lister = %v, want shelter", resp.Lister)
+	}
+	if len(resp.Warnings) != 0 {
+		t.Errorf("GetPetDetails() warnings = %v, want none", resp.Warnings)
+	}
+}
+
+func TestCompositeHandler_GetPetDetails_PetNotFound(t *testing.T) {
+	mockPetClient := &MockPetServiceClient{
+		GetPetFunc: func(ctx context.Context, req *pbPet.GetPetRequest) (*pbPet.PetResponse, error) {
+			return nil, status.Error(codes.NotFound, "Pet not found")
+		},
+	}
+	h := handler.NewCompositeHandler(&MockUserServiceClient{}, mockPetClient, &MockAdoptionServiceClient{})
+	r := gin.New()
+	r.GET("/pets/:petId/details", h.GetPetDetails)
+
+	w := performRequest(r, http.MethodGet, "/pets/missing/details")
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("GetPetDetails() status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestDialConfig_RoundRobinPolicy(t *testing.T) {
+	cfg := client.DialConfig{LoadBalancingPolicy: client.LoadBalancingRoundRobin}
+
+	sc := client.ServiceConfigJSON(cfg.LoadBalancingPolicy)
+	if !json.Valid([]byte(sc)) {
+		t.Fatalf("ServiceConfigJSON() = %q, not valid JSON", sc)
+	}
+	var parsed struct {
+		LoadBalancingConfig []map[string]json.RawMessage `json:"loadBalancingConfig"`
+	}
+	if err := json.Unmarshal([]byte(sc), &parsed); err != nil {
+		t.Fatalf("could not parse service config: %v", err)
+	}
+	if len(parsed.LoadBalancingConfig) != 1 {
+		t.Fatalf("service config has %d policies, want 1", len(parsed.LoadBalancingConfig))
+	}
+	if _, ok := parsed.LoadBalancingConfig[0]["round_robin"]; !ok {
+		t.Errorf("service config %q does not select round_robin", sc)
+	}
+
+	if got := client.DialTarget("pet-service:50052", cfg); got != "dns:///pet-service:50052" {
+		t.Errorf("DialTarget() = %q, want dns resolver target", got)
+	}
+	if got := client.DialTarget("passthrough:///pet-service:50052", cfg); got != "passthrough:///pet-service:50052" {
+		t.Errorf("DialTarget() = %q, want explicit scheme kept", got)
+	}
+	if len(client.DialOptions(cfg)) == 0 {
+		t.Errorf("DialOptions() returned no options")
+	}
+}
+
+func TestDialConfig_DefaultsToPickFirst(t *testing.T) {
+	if sc := client.ServiceConfigJSON(""); !strings.Contains(sc, client.LoadBalancingPickFirst) {
+		t.Errorf("ServiceConfigJSON(\"\") = %q, want pick_first", sc)
+	}
+	if got := client.DialTarget("pet-service:50052", client.DialConfig{}); got != "pet-service:50052" {
+		t.Errorf("DialTarget() = %q, want target unchanged for pick_first", got)
+	}
+}
+
+// compressionRecordingPetServer answers ListPets and records the encoding of incoming requests.
+type compressionRecordingPetServer struct {
+	pbPet.UnimplementedPetServiceServer
+	mu          sync.Mutex
+	compression string
+}
+
+func (s *compressionRecordingPetServer) ListPets(ctx context.Context, req *pbPet.ListPetsRequest) (*pbPet.ListPetsResponse, error) {
+	return &pbPet.ListPetsResponse{Pets: []*pbPet.Pet{{Id: "pet1", Name: strings.Repeat("Buddy ", 100)}}, TotalCount: 1}, nil
+}
+
+func (s *compressionRecordingPetServer) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+func (s *compressionRecordingPetServer) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+func (s *compressionRecordingPetServer) HandleConn(context.Context, stats.ConnStats) {}
+func (s *compressionRecordingPetServer) HandleRPC(_ context.Context, rs stats.RPCStats) {
+	if in, ok := rs.(*stats.InHeader); ok {
+		s.mu.Lock()
+		s.compression = in.Compression
+		s.mu.Unlock()
+	}
+}
+
+func TestDialConfig_GzipCompression(t *testing.T) {
+	cfg := client.DialConfig{LoadBalancingPolicy: client.LoadBalancingPickFirst, Compression: client.CompressionGzip}
+
+	callOpts := client.CallOptions(cfg)
+	if len(callOpts) != 1 {
+		t.Fatalf("CallOptions() returned %d options, want 1", len(callOpts))
+	}
+	if compressor, ok := callOpts[0].(grpc.CompressorCallOption); !ok || compressor.CompressorType != "gzip" {
+		t.Errorf("CallOptions()[0] = %#v, want gzip compressor", callOpts[0])
+	}
+	if opts := client.CallOptions(cfg.WithCompression(client.CompressionNone)); len(opts) != 0 {
+		t.Errorf("CallOptions() with compression none = %v, want none", opts)
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	petServer := &compressionRecordingPetServer{}
+	srv := grpc.NewServer(grpc.StatsHandler(petServer))
+	pbPet.RegisterPetServiceServer(srv, petServer)
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	petClient, err := client.NewPetServiceGRPCClient(ctx, lis.Addr().String(), cfg)
+	if err != nil {
+		t.Fatalf("NewPetServiceGRPCClient() error = %v", err)
+	}
+	defer petClient.Close()
+
+	resp, err := petClient.ListPets(ctx, &pbPet.ListPetsRequest{})
+	if err != nil {
+		t.Fatalf("ListPets() with gzip compression error = %v", err)
+	}
+	if len(resp.GetPets()) != 1 || resp.GetPets()[0].GetId() != "pet1" {
+		t.Errorf("ListPets() = %v, want one pet", resp.GetPets())
+	}
+	petServer.mu.Lock()
+	defer petServer.mu.Unlock()
+	if petServer.compression != "gzip" {
+		t.Errorf("server saw request compression %q, want gzip", petServer.compression)
+	}
+}
+
+func TestMaintenance_ReadOnly_BlocksWritesAllowsReads(t *testing.T) {
+	createCalled := false
+	mockPetClient := &MockPetServiceClient{
+		GetPetFunc: func(ctx context.Context, req *pbPet.GetPetRequest) (*pbPet.PetResponse, error) {
+			return &pbPet.PetResponse{Pet: &pbPet.Pet{Id: req.GetPetId(), Name: "Buddy"}}, nil
+		},
+		CreatePetFunc: func(ctx context.Context, req *pbPet.CreatePetRequest) (*pbPet.PetResponse, error) {
+			createCalled = true
+			return &pbPet.PetResponse{}, nil
+		},
+	}
+	maintenance := middleware.NewMaintenance(middleware.MaintenanceReadOnly, "")
+	r := newTestRouter(&MockUserServiceClient{}, mockPetClient, &MockAdoptionServiceClient{}, maintenance, "")
+
+	if w := performRequest(r, http.MethodGet, "/api/v1/pets/pet1"); w.Code != http.StatusOK {
+		t.Errorf("GET during read_only status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/pets", strings.NewReader(`{"name":"Buddy"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("POST during read_only status = %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+	if !strings.Contains(w.Body.String(), "maintenance") {
+		t.Errorf("POST during read_only body = %s, want maintenance message", w.Body.String())
+	}
+	if createCalled {
+		t.Error("CreatePet was called during read_only maintenance")
+	}
+}
+
+func TestMaintenance_Full_BlocksAllButHealth(t *testing.T) {
+	mockPetClient := &MockPetServiceClient{
+		GetPetFunc: func(ctx context.Context, req *pbPet.GetPetRequest) (*pbPet.PetResponse, error) {
+			t.Error("GetPet was called during full maintenance")
+			return nil, errors.New("unexpected call")
+		},
+	}
+	maintenance := middleware.NewMaintenance(middleware.MaintenanceFull, "Back soon")
+	r := newTestRouter(&MockUserServiceClient{}, mockPetClient, &MockAdoptionServiceClient{}, maintenance, "")
+
+	w := performRequest(r, http.MethodGet, "/api/v1/pets/pet1")
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("GET during full status = %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+	if !strings.Contains(w.Body.String(), "Back soon") {
+		t.Errorf("GET during full body = %s, want custom message", w.Body.String())
+	}
+	if w := performRequest(r, http.MethodGet, "/health"); w.Code != http.StatusOK {
+		t.Errorf("GET /health during full status = %d, want %d", w.Code, http.StatusOK)
+	}
+}
+
+func TestMaintenance_AdminToggle(t *testing.T) {
+	maintenance := middleware.NewMaintenance(middleware.MaintenanceFull, "")
+	r := newTestRouter(&MockUserServiceClient{}, &MockPetServiceClient{}, &MockAdoptionServiceClient{}, maintenance, "secret-token")
+
+	put := func(token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/admin/maintenance", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("X-Admin-Token", token)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := put("wrong", `{"mode":"off"}`); w.Code != http.StatusUnauthorized {
+		t.Errorf("PUT with wrong token status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+	if w := put("secret-token", `{"mode":"sideways"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("PUT with invalid mode status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	if w := put("secret-token", `{"mode":"off"}`); w.Code != http.StatusOK {
+		t.Fatalf("PUT with valid token status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if mode, _ := maintenance.State(); mode != middleware.MaintenanceOff {
+		t.Errorf("maintenance mode = %s, want off", mode)
+	}
+}
+
+func TestRequireAdmin_ConfiguredAdminUserPasses(t *testing.T) {
+	maintenance := middleware.NewMaintenance(middleware.MaintenanceOff, "")
+	userClient, petClient, adoptionClient := &MockUserServiceClient{}, &MockPetServiceClient{}, &MockAdoptionServiceClient{}
+	verifier := middleware.NewHMACVerifier([]byte(testJWTSecret))
+	roles := middleware.NewRoles([]string{"admin-1", "admin-2"})
+	r := router.New(
+		handler.NewUserHandler(userClient),
+		handler.NewPetHandler(petClient),
+		handler.NewAdoptionHandler(adoptionClient),
+		handler.NewCompositeHandler(userClient, petClient, adoptionClient),
+		handler.NewAdminHandler(maintenance, nil),
+		handler.NewHealthHandler(userClient, petClient, adoptionClient),
+		maintenance,
+		middleware.RequireAdmin("secret-token", verifier, roles),
+		middleware.RequireAuthWithRoles(verifier, roles),
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	get := func(header, value string) int {
+		req := httptest.NewRequest(http.MethodGet, "/admin/maintenance", nil)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := get("Authorization", "Bearer "+signTestToken(t, "admin-2")); code != http.StatusOK {
+		t.Errorf("GET as configured admin status = %d, want %d", code, http.StatusOK)
+	}
+	if code := get("Authorization", "Bearer "+signTestToken(t, "user-1")); code != http.StatusUnauthorized {
+		t.Errorf("GET as regular user status = %d, want %d", code, http.StatusUnauthorized)
+	}
+	if code := get("", ""); code != http.StatusUnauthorized {
+		t.Errorf("GET without credentials status = %d, want %d", code, http.StatusUnauthorized)
+	}
+	if code := get("X-Admin-Token", "secret-token"); code != http.StatusOK {
+		t.Errorf("GET with admin token status = %d, want %d", code, http.StatusOK)
+	}
+}
+
+func TestRequireAdmin_AdminRoleClaimPassesWithoutConfiguredAdmins(t *testing.T) {
+	maintenance := middleware.NewMaintenance(middleware.MaintenanceOff, "")
+	userClient, petClient, adoptionClient := &MockUserServiceClient{}, &MockPetServiceClient{}, &MockAdoptionServiceClient{}
+	verifier := middleware.NewHMACVerifier([]byte(testJWTSecret))
+	roles := middleware.NewRoles(nil) // ADMIN_USER_IDS unset
+	r := router.New(
+		handler.NewUserHandler(userClient),
+		handler.NewPetHandler(petClient),
+		handler.NewAdoptionHandler(adoptionClient),
+		handler.NewCompositeHandler(userClient, petClient, adoptionClient),
+		handler.NewAdminHandler(maintenance, nil),
+		handler.NewHealthHandler(userClient, petClient, adoptionClient),
+		maintenance,
+		middleware.RequireAdmin("", verifier, roles),
+		middleware.RequireAuthWithRoles(verifier, roles),
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	get := func(token string) int {
+		req := httptest.NewRequest(http.MethodGet, "/admin/maintenance", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := get(signTestTokenWithRole(t, "user-9", middleware.RoleAdmin)); code != http.StatusOK {
+		t.Errorf("GET with admin role claim status = %d, want %d", code, http.StatusOK)
+	}
+	if code := get(signTestTokenWithRole(t, "user-1", middleware.RoleUser)); code != http.StatusUnauthorized {
+		t.Errorf("GET as regular user status = %d, want %d", code, http.StatusUnauthorized)
+	}
+}
+
+func TestUserHandler_VerifyToken(t *testing.T) {
+	maintenance := middleware.NewMaintenance(middleware.MaintenanceOff, "")
+	userClient, petClient, adoptionClient := &MockUserServiceClient{}, &MockPetServiceClient{}, &MockAdoptionServiceClient{}
+	verifier := middleware.NewHMACVerifier([]byte(testJWTSecret))
+	roles := middleware.NewRoles([]string{"admin-1"})
+	r := router.New(
+		handler.NewUserHandler(userClient),
+		handler.NewPetHandler(petClient),
+		handler.NewAdoptionHandler(adoptionClient),
+		handler.NewCompositeHandler(userClient, petClient, adoptionClient),
+		handler.NewAdminHandler(maintenance, nil),
+		handler.NewHealthHandler(userClient, petClient, adoptionClient),
+		maintenance,
+		middleware.RequireAdmin("", verifier, roles),
+		middleware.RequireAuthWithRoles(verifier, roles),
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	expiresAt := time.Now().Add(30 * time.Minute).Truncate(time.Second)
+	validToken, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": "admin-1",
+		"unm": "tester",
+		"exp": expiresAt.Unix(),
+		"iss": "petstore-user-service",
+		"aud": "petstore-clients",
+	}).SignedString([]byte(testJWTSecret))
+	expiredToken, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": "user1",
+		"unm": "tester",
+		"exp": time.Now().Add(-time.Minute).Unix(),
+		"iss": "petstore-user-service",
+		"aud": "petstore-clients",
+	}).SignedString([]byte(testJWTSecret))
+
+	verify := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/users/token/verify", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("valid token", func(t *testing.T) {
+		w := verify(validToken)
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d; body: %s", w.Code, http.StatusOK, w.Body.String())
+		}
+		var got handler.TokenClaimsResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+			t.Fatalf("could not decode response: %v", err)
+		}
+		if got.UserID != "admin-1" || got.Username != "tester" {
+			t.Errorf("user = %q/%q, want %q/%q", got.UserID, got.Username, "admin-1", "tester")
+		}
+		if !reflect.DeepEqual(got.Roles, []string{middleware.RoleAdmin}) {
+			t.Errorf("roles = %v, want [%s]", got.Roles, middleware.RoleAdmin)
+		}
+		if !got.ExpiresAt.Equal(expiresAt) {
+			t.Errorf("expires_at = %v, want %v", got.ExpiresAt, expiresAt)
+		}
+	})
+
+	for name, token := range map[string]string{
+		"expired token":   expiredToken,
+		"malformed token": "not.a.jwt",
+		"wrong signature": validToken[:len(validToken)-4] + "abcd",
+		"missing token":   "",
+	} {
+		t.Run(name, func(t *testing.T) {
+			if w := verify(token); w.Code != http.StatusUnauthorized {
+				t.Errorf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+			}
+		})
+	}
+}
+
+func TestUserHandler_RefreshToken(t *testing.T) {
+	mockUserClient := &MockUserServiceClient{
+		RefreshTokenFunc: func(ctx context.Context, req *pbUser.RefreshTokenRequest) (*pbUser.RefreshTokenResponse, error) {
+			if req.GetRefreshToken() != "valid" {
+				return nil, status.Error(codes.Unauthenticated, "invalid or expired refresh token")
+			}
+			return &pbUser.RefreshTokenResponse{AccessToken: "new-access", RefreshToken: "rotated"}, nil
+		},
+	}
+	maintenance := middleware.NewMaintenance(middleware.MaintenanceOff, "")
+	r := newTestRouter(mockUserClient, &MockPetServiceClient{}, &MockAdoptionServiceClient{}, maintenance, "")
+	refresh := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/users/refresh", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := refresh(`{"refresh_token": "valid"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("RefreshToken() status = %d, want %d (body %s)", w.Code, http.StatusOK, w.Body.String())
+	}
+	var resp pbUser.RefreshTokenResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("could not decode response: %v", err)
+	}
+	if resp.GetAccessToken() != "new-access" || resp.GetRefreshToken() != "rotated" {
+		t.Errorf("RefreshToken() = %v, want the new access and rotated refresh token", &resp)
+	}
+
+	if w := refresh(`{"refresh_token": "used"}`); w.Code != http.StatusUnauthorized {
+		t.Errorf("RefreshToken() with a used token status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+	if w := refresh(`{}`); w.Code != http.StatusBadRequest {
+		t.Errorf("RefreshToken() without a token status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestHealthHandler_Readyz_ReportsFailingDependency(t *testing.T) {
+	maintenance := middleware.NewMaintenance(middleware.MaintenanceOff, "")
+	mockPetClient := &MockPetServiceClient{
+		HealthCheckFunc: func(ctx context.Context) (grpc_health_v1.HealthCheckResponse_ServingStatus, error) {
+			return grpc_health_v1.HealthCheckResponse_NOT_SERVING, nil
+		},
+	}
+	r := newTestRouter(&MockUserServiceClient{}, mockPetClient, &MockAdoptionServiceClient{}, maintenance, "")
+
+	w := performRequest(r, http.MethodGet, "/readyz")
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Readyz() status = %d, want %d. Body: %s", w.Code, http.StatusServiceUnavailable, w.Body.String())
+	}
+	var resp handler.ReadinessResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode readiness response: %v", err)
+	}
+	want := map[string]string{"user-service": "SERVING", "pet-service": "NOT_SERVING", "adoption-service": "SERVING"}
+	if resp.Status != "NOT_READY" || len(resp.Dependencies) != len(want) {
+		t.Fatalf("Readyz() = %+v, want NOT_READY with %d dependencies", resp, len(want))
+	}
+	for name, status := range want {
+		if got := resp.Dependencies[name].Status; got != status {
+			t.Errorf("Readyz() %s status = %q, want %q", name, got, status)
+		}
+	}
+
+	mockPetClient.HealthCheckFunc = nil
+	if w := performRequest(r, http.MethodGet, "/readyz"); w.Code != http.StatusOK {
+		t.Errorf("Readyz() with all dependencies serving status = %d, want %d", w.Code, http.StatusOK)
+	}
+}
+
+func TestDownstreamHealth_NotServingFailsFastWithoutCall(t *testing.T) {
+	var petCalls int32
+	mockPetClient := &MockPetServiceClient{
+		GetPetFunc: func(ctx context.Context, req *pbPet.GetPetRequest) (*pbPet.PetResponse, error) {
+			atomic.AddInt32(&petCalls, 1)
+			return &pbPet.PetResponse{Pet: &pbPet.Pet{Id: req.GetPetId()}}, nil
+		},
+		HealthCheckFunc: func(ctx context.Context) (grpc_health_v1.HealthCheckResponse_ServingStatus, error) {
+			return grpc_health_v1.HealthCheckResponse_NOT_SERVING, nil
+		},
+	}
+	userClient, adoptionClient := &MockUserServiceClient{}, &MockAdoptionServiceClient{}
+	downstreamHealth := middleware.NewDownstreamHealth(map[string]middleware.HealthChecker{
+		"user-service":     userClient,
+		"pet-service":      mockPetClient,
+		"adoption-service": adoptionClient,
+	})
+	maintenance := middleware.NewMaintenance(middleware.MaintenanceOff, "")
+	r := router.New(
+		handler.NewUserHandler(userClient),
+		handler.NewPetHandler(mockPetClient),
+		handler.NewAdoptionHandler(adoptionClient),
+		handler.NewCompositeHandler(userClient, mockPetClient, adoptionClient),
+		handler.NewAdminHandler(maintenance, nil),
+		handler.NewHealthHandler(userClient, mockPetClient, adoptionClient),
+		maintenance,
+		middleware.RequireAdminToken(""),
+		middleware.RequireAuth(testJWTSecret),
+		nil,
+		nil,
+		downstreamHealth,
+		nil,
+	)
+
+	// Before the first probe the service is assumed to be serving.
+	if w := performRequest(r, http.MethodGet, "/api/v1/pets/pet1"); w.Code != http.StatusOK {
+		t.Fatalf("GetPet() before probe status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	downstreamHealth.Probe(context.Background())
+	if got := downstreamHealth.Status("pet-service"); got != grpc_health_v1.HealthCheckResponse_NOT_SERVING {
+		t.Fatalf("pet-service status after probe = %s, want NOT_SERVING", got)
+	}
+	w := performRequest(r, http.MethodGet, "/api/v1/pets/pet1")
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("GetPet() with NOT_SERVING pet-service status = %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+	if calls := atomic.LoadInt32(&petCalls); calls != 1 {
+		t.Errorf("GetPet() reached the client %d times, want 1 (only before the probe)", calls)
+	}
+
+	// Other services are unaffected, and a recovered service is called again.
+	if w := performRequest(r, http.MethodGet, "/api/v1/meta/enums"); w.Code != http.StatusOK {
+		t.Errorf("ListEnums() status = %d, want %d", w.Code, http.StatusOK)
+	}
+	downstreamHealth.Set("pet-service", grpc_health_v1.HealthCheckResponse_SERVING)
+	if w := performRequest(r, http.MethodGet, "/api/v1/pets/pet1"); w.Code != http.StatusOK {
+		t.Errorf("GetPet() after recovery status = %d, want %d", w.Code, http.StatusOK)
+	}
+}
+
+func TestConcurrencyLimiter_SaturatedCompositeRejectsOverflow(t *testing.T) {
+	entered := make(chan struct{}, 1)
+	release := make(chan struct{})
+	var petCalls int32
+	mockPetClient := &MockPetServiceClient{
+		GetPetFunc: func(ctx context.Context, req *pbPet.GetPetRequest) (*pbPet.PetResponse, error) {
+			atomic.AddInt32(&petCalls, 1)
+			if req.GetPetId() == "slow" {
+				entered <- struct{}{}
+				<-release
+			}
+			return &pbPet.PetResponse{Pet: &pbPet.Pet{Id: req.GetPetId(), ListedByUserId: "user1"}}, nil
+		},
+	}
+	userClient := &MockUserServiceClient{
+		GetUserFunc: func(ctx context.Context, req *pbUser.GetUserRequest) (*pbUser.UserResponse, error) {
+			return &pbUser.UserResponse{User: &pbUser.User{Id: req.GetUserId(), Username: "shelter"}}, nil
+		},
+	}
+	adoptionClient := &MockAdoptionServiceClient{}
+	limiter := middleware.NewConcurrencyLimiter(1)
+	maintenance := middleware.NewMaintenance(middleware.MaintenanceOff, "")
+	r := router.New(
+		handler.NewUserHandler(userClient),
+		handler.NewPetHandler(mockPetClient),
+		handler.NewAdoptionHandler(adoptionClient),
+		handler.NewCompositeHandler(userClient, mockPetClient, adoptionClient),
+		handler.NewAdminHandler(maintenance, nil),
+		handler.NewHealthHandler(userClient, mockPetClient, adoptionClient),
+		maintenance,
+		middleware.RequireAdminToken(""),
+		middleware.RequireAuth(testJWTSecret),
+		nil,
+		nil,
+		nil,
+		limiter,
+	)
+
+	// Occupy the only slot with a composite request stuck on the pet service.
+	done := make(chan int)
+	go func() { done <- performRequest(r, http.MethodGet, "/api/v1/pets/slow/details").Code }()
+	<-entered
+
+	w := performRequest(r, http.MethodGet, "/api/v1/pets/pet1/details")
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("GetPetDetails() while saturated status = %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Error("GetPetDetails() while saturated: expected a Retry-After header")
+	}
+	if calls := atomic.LoadInt32(&petCalls); calls != 1 {
+		t.Errorf("pet service called %d times, want 1 (the overflow request must not reach it)", calls)
+	}
+	// Plain endpoints are not limited.
+	if w := performRequest(r, http.MethodGet, "/api/v1/pets/pet1"); w.Code != http.StatusOK {
+		t.Errorf("GetPet() while composites are saturated status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	close(release)
+	if code := <-done; code != http.StatusOK {
+		t.Errorf("slot-holding GetPetDetails() status = %d, want %d", code, http.StatusOK)
+	}
+	if got := limiter.InFlight(); got != 0 {
+		t.Errorf("InFlight() after the request finished = %d, want 0", got)
+	}
+	if w := performRequest(r, http.MethodGet, "/api/v1/pets/pet1/details"); w.Code != http.StatusOK {
+		t.Errorf("GetPetDetails() after the slot was freed status = %d, want %d", w.Code, http.StatusOK)
+	}
+}
+
+func TestTokenVerifier_HS256AndRS256(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey() error = %v", err)
+	}
+	claims := jwt.MapClaims{
+		"sub": "user1",
+		"unm": "tester",
+		"exp": time.Now().Add(time.Hour).Unix(),
+		"iss": "petstore-user-service",
+		"aud": "petstore-clients",
+	}
+	hsToken, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testJWTSecret))
+	rsToken, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(privateKey)
+	if err != nil {
+		t.Fatalf("could not sign RS256 token: %v", err)
+	}
+	hmacVerifier := middleware.NewHMACVerifier([]byte(testJWTSecret))
+	rsaVerifier := middleware.NewRSAVerifier(&privateKey.PublicKey)
+
+	tests := []struct {
+		name     string
+		verifier middleware.TokenVerifier
+		token    string
+		wantErr  bool
+	}{
+		{"HS256 token, HS256 verifier", hmacVerifier, hsToken, false},
+		{"RS256 token, RS256 verifier", rsaVerifier, rsToken, false},
+		{"RS256 token, HS256 verifier", hmacVerifier, rsToken, true},
+		{"HS256 token, RS256 verifier", rsaVerifier, hsToken, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID, _, err := tt.verifier.ParseAccessToken(tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAccessToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && userID != "user1" {
+				t.Errorf("ParseAccessToken() userID = %q, want %q", userID, "user1")
+			}
+		})
+	}
+}
+
+func TestTokenVerifier_LeewayToleratesClockSkew(t *testing.T) {
+	sign := func(claims jwt.MapClaims) string {
+		claims["sub"] = "user1"
+		claims["iss"] = "petstore-user-service"
+		claims["aud"] = "petstore-clients"
+		signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testJWTSecret))
+		if err != nil {
+			t.Fatalf("could not sign test token: %v", err)
+		}
+		return signed
+	}
+	now := time.Now()
+	verifier := middleware.NewHMACVerifier([]byte(testJWTSecret)).WithLeeway(30 * time.Second)
+
+	tests := []struct {
+		name     string
+		verifier middleware.TokenVerifier
+		token    string
+		wantErr  bool
+	}{
+		{"expired within leeway", verifier, sign(jwt.MapClaims{"exp": now.Add(-10 * time.Second).Unix()}), false},
+		{"expired beyond leeway", verifier, sign(jwt.MapClaims{"exp": now.Add(-time.Minute).Unix()}), true},
+		{"not yet valid within leeway", verifier, sign(jwt.MapClaims{"exp": now.Add(time.Hour).Unix(), "nbf": now.Add(10 * time.Second).Unix()}), false},
+		{"not yet valid beyond leeway", verifier, sign(jwt.MapClaims{"exp": now.Add(time.Hour).Unix(), "nbf": now.Add(time.Minute).Unix()}), true},
+		{"expired, no leeway", middleware.NewHMACVerifier([]byte(testJWTSecret)), sign(jwt.MapClaims{"exp": now.Add(-10 * time.Second).Unix()}), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID, _, err := tt.verifier.ParseAccessToken(tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAccessToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && userID != "user1" {
+				t.Errorf("ParseAccessToken() userID = %q, want %q", userID, "user1")
+			}
+		})
+	}
+}
+
+func TestJWKSVerifier_ValidatesWithFetchedKey(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey() error = %v", err)
+	}
+	var fetches atomic.Int32
+	jwksServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+			"kty": "RSA",
+			"use": "sig",
+			"alg": "RS256",
+			"kid": "key-1",
+			"n":   base64.RawURLEncoding.EncodeToString(privateKey.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(privateKey.E)).Bytes()),
+		}}})
+	}))
+	defer jwksServer.Close()
+	verifier := middleware.NewJWKSVerifier(middleware.NewJWKSCache(jwksServer.URL, time.Minute, jwksServer.Client()))
+
+	sign := func(keyID string) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"sub": "user1",
+			"exp": time.Now().Add(time.Hour).Unix(),
+			"iss": "petstore-user-service",
+			"aud": "petstore-clients",
+		})
+		token.Header["kid"] = keyID
+		signed, err := token.SignedString(privateKey)
+		if err != nil {
+			t.Fatalf("could not sign test token: %v", err)
+		}
+		return signed
+	}
+
+	for i := 0; i < 2; i++ {
+		if userID, _, err := verifier.ParseAccessToken(sign("key-1")); err != nil || userID != "user1" {
+			t.Fatalf("ParseAccessToken() = %q, %v; want user1", userID, err)
+		}
+	}
+	if got := fetches.Load(); got != 1 {
+		t.Errorf("JWKS fetched %d times, want 1 (cached)", got)
+	}
+	if _, _, err := verifier.ParseAccessToken(sign("unknown-key")); err == nil {
+		t.Error("ParseAccessToken() with an unknown key ID: expected an error")
+	}
+}
+
+func TestTokenVerifier_AcceptsOldAndNewKeysAcrossRotation(t *testing.T) {
+	oldKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey() error = %v", err)
+	}
+	newKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey() error = %v", err)
+	}
+	sign := func(key *rsa.PrivateKey) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"sub": "user1",
+			"exp": time.Now().Add(time.Hour).Unix(),
+			"iss": "petstore-user-service",
+			"aud": "petstore-clients",
+		})
+		token.Header["kid"] = middleware.RSAKeyID(&key.PublicKey)
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatalf("could not sign test token: %v", err)
+		}
+		return signed
+	}
+	jwk := func(key *rsa.PrivateKey) map[string]string {
+		return map[string]string{
+			"kty": "RSA",
+			"kid": middleware.RSAKeyID(&key.PublicKey),
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}
+	}
+
+	// The JWKS serves only the old key until the rotation.
+	var rotated atomic.Bool
+	jwksServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys := []map[string]string{jwk(oldKey)}
+		if rotated.Load() {
+			keys = []map[string]string{jwk(newKey), jwk(oldKey)}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": keys})
+	}))
+	defer jwksServer.Close()
+
+	verifiers := map[string]middleware.TokenVerifier{
+		"configured key set": middleware.NewRSAVerifier(&newKey.PublicKey, &oldKey.PublicKey),
+		"JWKS":               middleware.NewJWKSVerifier(middleware.NewJWKSCache(jwksServer.URL, time.Millisecond, jwksServer.Client())),
+	}
+	for name, verifier := range verifiers {
+		t.Run(name, func(t *testing.T) {
+			rotated.Store(false)
+			oldToken := sign(oldKey)
+			if _, _, err := verifier.ParseAccessToken(oldToken); err != nil {
+				t.Fatalf("ParseAccessToken() before rotation error = %v", err)
+			}
+
+			rotated.Store(true)
+			time.Sleep(2 * time.Millisecond) // Let the JWKS cache expire
+			if _, _, err := verifier.ParseAccessToken(sign(newKey)); err != nil {
+				t.Errorf("ParseAccessToken() with the new key error = %v", err)
+			}
+			if _, _, err := verifier.ParseAccessToken(oldToken); err != nil {
+				t.Errorf("ParseAccessToken() with the old key after rotation error = %v", err)
+			}
+		})
+	}
+}
+
+func TestLogBadRequestBodies_LogsRedactedBodyOnlyWhenEnabled(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	const password = "hunter2-very-secret"
+	badBody := `{"username":"jane","email":"jane@example.com","password":"` + password + `","full_name":` // Cut off, so the bind fails
+
+	for _, enabled := range []bool{false, true} {
+		logs.Reset()
+		var badRequestLogger gin.HandlerFunc
+		if enabled {
+			badRequestLogger = middleware.LogBadRequestBodies(1024)
+		}
+		userClient := &MockUserServiceClient{}
+		r := router.New(
+			handler.NewUserHandler(userClient),
+			handler.NewPetHandler(&MockPetServiceClient{}),
+			handler.NewAdoptionHandler(&MockAdoptionServiceClient{}),
+			handler.NewCompositeHandler(userClient, &MockPetServiceClient{}, &MockAdoptionServiceClient{}),
+			handler.NewAdminHandler(middleware.NewMaintenance(middleware.MaintenanceOff, ""), nil),
+			handler.NewHealthHandler(userClient, &MockPetServiceClient{}, &MockAdoptionServiceClient{}),
+			middleware.NewMaintenance(middleware.MaintenanceOff, ""),
+			middleware.RequireAdminToken(""),
+			middleware.RequireAuth(testJWTSecret),
+			badRequestLogger,
+			nil,
+			nil,
+			nil,
+		)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/users/register", strings.NewReader(badBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("RegisterUser() status = %d, want %d", w.Code, http.StatusBadRequest)
+		}
+
+		output := logs.String()
+		if strings.Contains(output, password) {
+			t.Errorf("enabled=%v: log contains the password:\n%s", enabled, output)
+		}
+		logged := strings.Contains(output, `"username":"jane"`) && strings.Contains(output, `"password":"[REDACTED]"`)
+		if logged != enabled {
+			t.Errorf("enabled=%v: redacted body logged = %v, want %v. Log:\n%s", enabled, logged, enabled, output)
+		}
+	}
+}
+
+func TestAccessLog_WritesOneStructuredLinePerRequest(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	mockPetClient := &MockPetServiceClient{
+		GetPetFunc: func(ctx context.Context, req *pbPet.GetPetRequest) (*pbPet.PetResponse, error) {
+			if req.GetPetId() == "missing" {
+				return nil, status.Error(codes.NotFound, "Pet not found")
+			}
+			return &pbPet.PetResponse{Pet: &pbPet.Pet{Id: req.GetPetId(), Name: "Buddy"}}, nil
+		},
+	}
+	newRouter := func(level middleware.AccessLogLevel) *gin.Engine {
+		userClient, adoptionClient := &MockUserServiceClient{}, &MockAdoptionServiceClient{}
+		maintenance := middleware.NewMaintenance(middleware.MaintenanceOff, "")
+		return router.New(
+			handler.NewUserHandler(userClient),
+			handler.NewPetHandler(mockPetClient),
+			handler.NewAdoptionHandler(adoptionClient),
+			handler.NewCompositeHandler(userClient, mockPetClient, adoptionClient),
+			handler.NewAdminHandler(maintenance, nil),
+			handler.NewHealthHandler(userClient, mockPetClient, adoptionClient),
+			maintenance,
+			middleware.RequireAdminToken(""),
+			middleware.RequireAuth(testJWTSecret),
+			nil,
+			middleware.AccessLog(level),
+			nil,
+			nil,
+		)
+	}
+
+	r := newRouter(middleware.AccessLogInfo)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/token/verify", nil)
+	req.Header.Set("Authorization", "Bearer "+signTestToken(t, "user1"))
+	req.Header.Set(middleware.RequestIDHeader, "req-42")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("VerifyToken() status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if got := w.Header().Get(middleware.RequestIDHeader); got != "req-42" {
+		t.Errorf("response %s = %q, want the client's req-42", middleware.RequestIDHeader, got)
+	}
+
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("access log has %d lines, want 1:\n%s", len(lines), logs.String())
+	}
+	for _, field := range []string{
+		"level=info", "method=GET", "path=/api/v1/users/token/verify", "status=200",
+		"bytes=" + strconv.Itoa(w.Body.Len()), "latency_ms=", "latency_bucket=", "user_id=user1", "request_id=req-42",
+	} {
+		if !strings.Contains(lines[0], field) {
+			t.Errorf("access log line is missing %q: %s", field, lines[0])
+		}
+	}
+
+	// An anonymous request gets a generated ID; below the configured level nothing is logged.
+	r = newRouter(middleware.AccessLogWarn)
+	logs.Reset()
+	w = performRequest(r, http.MethodGet, "/api/v1/pets/pet1")
+	if w.Header().Get(middleware.RequestIDHeader) == "" {
+		t.Errorf("response has no generated %s", middleware.RequestIDHeader)
+	}
+	if logs.Len() != 0 {
+		t.Errorf("200 response logged at level warn:\n%s", logs.String())
+	}
+	performRequest(r, http.MethodGet, "/api/v1/pets/missing")
+	if output := logs.String(); !strings.Contains(output, "level=warn") || !strings.Contains(output, "status=404") || !strings.Contains(output, "user_id=-") {
+		t.Errorf("404 response log = %q, want a warn line without user", output)
+	}
+}
+
+func TestLatencyBucket(t *testing.T) {
+	tests := []struct {
+		latency time.Duration
+		want    string
+	}{
+		{3 * time.Millisecond, "le_10ms"},
+		{10 * time.Millisecond, "le_10ms"},
+		{300 * time.Millisecond, "le_500ms"},
+		{2 * time.Second, "le_2.5s"},
+		{6 * time.Second, "gt_5s"},
+	}
+	for _, tt := range tests {
+		if got := middleware.LatencyBucket(tt.latency); got != tt.want {
+			t.Errorf("LatencyBucket(%v) = %s, want %s", tt.latency, got, tt.want)
+		}
+	}
+}
+
+func TestCompositeHandler_GetMyFavoritePetsDetail(t *testing.T) {
+	mockUserClient := &MockUserServiceClient{
+		ListFavoritePetsFunc: func(ctx context.Context, req *pbUser.ListFavoritePetsRequest) (*pbUser.FavoritePetsResponse, error) {
+			if req.GetUserId() != "user1" {
+				t.Errorf("ListFavoritePets() user = %s, want user1 from token", req.GetUserId())
+			}
+			return &pbUser.FavoritePetsResponse{PetIds: []string{"pet1", "deleted", "pet2", "flaky"}}, nil
+		},
+	}
+	mockPetClient := &MockPetServiceClient{
+		GetPetFunc: func(ctx context.Context, req *pbPet.GetPetRequest) (*pbPet.PetResponse, error) {
+			switch req.GetPetId() {
+			case "deleted":
+				return nil, status.Error(codes.NotFound, "Pet not found")
+			case "flaky":
+				return nil, status.Error(codes.Unavailable, "pet-service unavailable")
+			default:
+				return &pbPet.PetResponse{Pet: &pbPet.Pet{Id: req.GetPetId(), Name: "Pet " + req.GetPetId()}}, nil
+			}
+		},
+	}
+	maintenance := middleware.NewMaintenance(middleware.MaintenanceOff, "")
+	r := newTestRouter(mockUserClient, mockPetClient, &MockAdoptionServiceClient{}, maintenance, "")
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/me/favorites/detail", nil)
+	req.Header.Set("Authorization", "Bearer "+signTestToken(t, "user1"))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("GetMyFavoritePetsDetail() status = %d, want %d (body %s)", w.Code, http.StatusOK, w.Body.String())
+	}
+	var resp handler.FavoritePetsDetailResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("could not decode response: %v", err)
+	}
+	if len(resp.Pets) != 2 || resp.Pets[0].GetId() != "pet1" || resp.Pets[1].GetId() != "pet2" {
+		t.Errorf("GetMyFavoritePetsDetail() pets = %v, want pet1 and pet2 in favorite order", resp.Pets)
+	}
+	if len(resp.MissingPetIDs) != 1 || resp.MissingPetIDs[0] != "deleted" {
+		t.Errorf("GetMyFavoritePetsDetail() missing = %v, want [deleted]", resp.MissingPetIDs)
+	}
+	if len(resp.Warnings) != 1 || resp.Warnings[0].Section != "pets" {
+		t.Errorf("GetMyFavoritePetsDetail() warnings = %v, want one pets warning", resp.Warnings)
+	}
+}
+
+func TestCompositeHandler_GetMyFavoritePetsDetail_Unauthenticated(t *testing.T) {
+	maintenance := middleware.NewMaintenance(middleware.MaintenanceOff, "")
+	r := newTestRouter(&MockUserServiceClient{}, &MockPetServiceClient{}, &MockAdoptionServiceClient{}, maintenance, "")
+
+	if w := performRequest(r, http.MethodGet, "/api/v1/users/me/favorites/detail"); w.Code != http.StatusUnauthorized {
+		t.Errorf("GetMyFavoritePetsDetail() without token status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestCompositeHandler_DeleteMyData_CancelsThenAnonymizesAuthenticatedUser(t *testing.T) {
+	var calls []string
+	mockUserClient := &MockUserServiceClient{
+		AnonymizeUserFunc: func(ctx context.Context, req *pbUser.AnonymizeUserRequest) (*pbUser.UserResponse, error) {
+			calls = append(calls, "anonymize:"+req.GetUserId())
+			return &pbUser.UserResponse{User: &pbUser.User{Id: req.GetUserId()}}, nil
+		},
+	}
+	mockAdoptionClient := &MockAdoptionServiceClient{
+		CancelUserApplicationsFunc: func(ctx context.Context, req *pbAdoption.CancelUserApplicationsRequest) (*pbAdoption.CancelUserApplicationsResponse, error) {
+			calls = append(calls, "cancel:"+req.GetUserId())
+			return &pbAdoption.CancelUserApplicationsResponse{CancelledCount: 2}, nil
+		},
+	}
+	maintenance := middleware.NewMaintenance(middleware.MaintenanceOff, "")
+	r := newTestRouter(mockUserClient, &MockPetServiceClient{}, mockAdoptionClient, maintenance, "")
+	deleteMyData := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/users/me/data", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+signTestToken(t, "user1"))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	for _, body := range []string{"", `{}`, `{"confirmation": "yes"}`} {
+		if w := deleteMyData(body); w.Code != http.StatusBadRequest {
+			t.Errorf("DeleteMyData(%q) status = %d, want %d", body, w.Code, http.StatusBadRequest)
+		}
+	}
+	if len(calls) != 0 {
+		t.Fatalf("DeleteMyData() without confirmation called %v, want no downstream calls", calls)
+	}
+
+	w := deleteMyData(`{"confirmation": "` + handler.DeleteMyDataConfirmation + `"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("DeleteMyData() status = %d, want %d (body %s)", w.Code, http.StatusOK, w.Body.String())
+	}
+	if want := []string{"cancel:user1", "anonymize:user1"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("DeleteMyData() calls = %v, want %v for the user from the token", calls, want)
+	}
+	var resp handler.DeleteMyDataResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("could not decode response: %v", err)
+	}
+	if resp.CancelledApplications != 2 {
+		t.Errorf("DeleteMyData() cancelled_applications = %d, want 2", resp.CancelledApplications)
+	}
+}
+
+func TestCompositeHandler_GetAdoptionApplicationDetails_ContactOnlyForListerOrAdmin(t *testing.T) {
+	appStatus := pbAdoption.ApplicationStatus_APPROVED
+	mockAdoptionClient := &MockAdoptionServiceClient{
+		GetAdoptionApplicationFunc: func(ctx context.Context, req *pbAdoption.GetAdoptionApplicationRequest) (*pbAdoption.AdoptionApplicationResponse, error) {
+			return &pbAdoption.AdoptionApplicationResponse{Application: &pbAdoption.AdoptionApplication{
+				Id: req.GetApplicationId(), UserId: "applicant", PetId: "pet1", Status: appStatus,
+			}}, nil
+		},
+	}
+	mockPetClient := &MockPetServiceClient{
+		GetPetFunc: func(ctx context.Context, req *pbPet.GetPetRequest) (*pbPet.PetResponse, error) {
+			return &pbPet.PetResponse{Pet: &pbPet.Pet{Id: req.GetPetId(), ListedByUserId: "lister"}}, nil
+		},
+	}
+	mockUserClient := &MockUserServiceClient{
+		GetUserFunc: func(ctx context.Context, req *pbUser.GetUserRequest) (*pbUser.UserResponse, error) {
+			return &pbUser.UserResponse{User: &pbUser.User{Id: req.GetUserId(), FullName: "Ann Applicant", Email: "ann@example.com"}}, nil
+		},
+	}
+	maintenance := middleware.NewMaintenance(middleware.MaintenanceOff, "")
+	verifier := middleware.NewHMACVerifier([]byte(testJWTSecret))
+	roles := middleware.NewRoles([]string{"admin-1"})
+	r := router.New(
+		handler.NewUserHandler(mockUserClient),
+		handler.NewPetHandler(mockPetClient),
+		handler.NewAdoptionHandler(mockAdoptionClient),
+		handler.NewCompositeHandler(mockUserClient, mockPetClient, mockAdoptionClient),
+		handler.NewAdminHandler(maintenance, nil),
+		handler.NewHealthHandler(mockUserClient, mockPetClient, mockAdoptionClient),
+		maintenance,
+		middleware.RequireAdmin("", verifier, roles),
+		middleware.RequireAuthWithRoles(verifier, roles),
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	get := func(callerID string) handler.AdoptionApplicationDetailsResponse {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/adoptions/app1/details", nil)
+		req.Header.Set("Authorization", "Bearer "+signTestToken(t, callerID))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("GetAdoptionApplicationDetails() as %s status = %d, want %d (body %s)", callerID, w.Code, http.StatusOK, w.Body.String())
+		}
+		var resp handler.AdoptionApplicationDetailsResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("could not decode response: %v", err)
+		}
+		if resp.Application.GetId() != "app1" {
+			t.Errorf("GetAdoptionApplicationDetails() application = %v, want app1", resp.Application)
+		}
+		return resp
+	}
+
+	for _, callerID := range []string{"lister", "admin-1"} {
+		contact := get(callerID).ApplicantContact
+		if contact == nil || contact.Email != "ann@example.com" || contact.UserID != "applicant" {
+			t.Errorf("GetAdoptionApplicationDetails() as %s contact = %+v, want the applicant's", callerID, contact)
+		}
+	}
+	for _, callerID := range []string{"applicant", "stranger"} {
+		if contact := get(callerID).ApplicantContact; contact != nil {
+			t.Errorf("GetAdoptionApplicationDetails() as %s contact = %+v, want none", callerID, contact)
+		}
+	}
+
+	appStatus = pbAdoption.ApplicationStatus_PENDING_REVIEW
+	if contact := get("lister").ApplicantContact; contact != nil {
+		t.Errorf("GetAdoptionApplicationDetails() of pending application contact = %+v, want none", contact)
+	}
+
+	if w := performRequest(r, http.MethodGet, "/api/v1/adoptions/app1/details"); w.Code != http.StatusUnauthorized {
+		t.Errorf("GetAdoptionApplicationDetails() without token status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestPetHandler_FieldsParam_FiltersResponse(t *testing.T) {
+	pet := &pbPet.Pet{Id: "pet1", Name: "Buddy", Species: "Dog", Breed: "Beagle", Age: 3, Description: "Friendly", ListedByUserId: "user1"}
+	petClient := &MockPetServiceClient{
+		GetPetFunc: func(ctx context.Context, req *pbPet.GetPetRequest) (*pbPet.PetResponse, error) {
+			return &pbPet.PetResponse{Pet: pet}, nil
+		},
+		ListPetsFunc: func(ctx context.Context, req *pbPet.ListPetsRequest) (*pbPet.ListPetsResponse, error) {
+			return &pbPet.ListPetsResponse{Pets: []*pbPet.Pet{pet, pet}, TotalCount: 2, Page: 1, Limit: 10}, nil
+		},
+	}
+	r := newTestRouter(&MockUserServiceClient{}, petClient, &MockAdoptionServiceClient{}, middleware.NewMaintenance(middleware.MaintenanceOff, ""), "")
+
+	w := performRequest(r, http.MethodGet, "/api/v1/pets/pet1?fields=id,%20name,unknown")
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET pet status = %d, want %d", w.Code, http.StatusOK)
+	}
+	var single map[string]map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &single); err != nil {
+		t.Fatalf("could not decode response: %v", err)
+	}
+	if want := map[string]interface{}{"id": "pet1", "name": "Buddy"}; !reflect.DeepEqual(single["pet"], want) {
+		t.Errorf("filtered pet = %v, want %v", single["pet"], want)
+	}
+
+	w = performRequest(r, http.MethodGet, "/api/v1/pets?fields=species,age")
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET pets status = %d, want %d", w.Code, http.StatusOK)
+	}
+	var list struct {
+		Pets       []map[string]interface{} `json:"pets"`
+		TotalCount int                      `json:"total_count"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatalf("could not decode response: %v", err)
+	}
+	if list.TotalCount != 2 {
+		t.Errorf("total_count = %d, want 2 (paging info is not filtered)", list.TotalCount)
+	}
+	for i, p := range list.Pets {
+		if want := map[string]interface{}{"species": "Dog", "age": float64(3)}; !reflect.DeepEqual(p, want) {
+			t.Errorf("filtered pets[%d] = %v, want %v", i, p, want)
+		}
+	}
+
+	// Without the parameter the full pet is returned.
+	w = performRequest(r, http.MethodGet, "/api/v1/pets/pet1")
+	if !strings.Contains(w.Body.String(), `"description":"Friendly"`) {
+		t.Errorf("unfiltered response = %s, want every field", w.Body.String())
+	}
+}
+
+func TestPetHandler_GetPetHistory_SurfacesLastReason(t *testing.T) {
+	petClient := &MockPetServiceClient{
+		GetPetFunc: func(ctx context.Context, req *pbPet.GetPetRequest) (*pbPet.PetResponse, error) {
+			return &pbPet.PetResponse{Pet: &pbPet.Pet{
+				Id:             req.GetPetId(),
+				AdoptionStatus: pbPet.AdoptionStatus_PENDING_ADOPTION,
+				StatusHistory: []*pbPet.PetStatusChange{
+					{FromStatus: pbPet.AdoptionStatus_PENDING_ADOPTION, ToStatus: pbPet.AdoptionStatus_AVAILABLE, ChangedAt: "2026-01-01T10:00:00Z"},
+					{FromStatus: pbPet.AdoptionStatus_AVAILABLE, ToStatus: pbPet.AdoptionStatus_PENDING_ADOPTION, Reason: "reserved pending home check", ChangedBy: "user1", ChangedAt: "2026-01-02T10:00:00Z"},
+				},
+			}}, nil
+		},
+	}
+	r := newTestRouter(&MockUserServiceClient{}, petClient, &MockAdoptionServiceClient{}, middleware.NewMaintenance(middleware.MaintenanceOff, ""), "")
+
+	w := performRequest(r, http.MethodGet, "/api/v1/pets/pet1/history")
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET history status = %d, want %d; body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var got handler.PetHistoryResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("could not decode response: %v", err)
+	}
+	if got.PetID != "pet1" || len(got.StatusHistory) != 2 {
+		t.Fatalf("history = %+v, want pet1 with 2 changes", got)
+	}
+	if got.LastStatusChange == nil || got.LastStatusChange.GetReason() != "reserved pending home check" {
+		t.Errorf("last_status_change = %v, want the reserved change", got.LastStatusChange)
+	}
+}
+
+func TestPetHandler_UpdatePet_MergePatchSetsOnlyPresentFields(t *testing.T) {
+	var got *pbPet.UpdatePetRequest
+	petClient := &MockPetServiceClient{
+		UpdatePetFunc: func(ctx context.Context, req *pbPet.UpdatePetRequest) (*pbPet.PetResponse, error) {
+			got = req
+			return &pbPet.PetResponse{Pet: &pbPet.Pet{Id: req.GetPetId()}}, nil
+		},
+	}
+	r := newTestRouter(&MockUserServiceClient{}, petClient, &MockAdoptionServiceClient{}, middleware.NewMaintenance(middleware.MaintenanceOff, ""), "")
+	patch := func(body string) *httptest.ResponseRecorder {
+		got = nil
+		req := httptest.NewRequest(http.MethodPatch, "/api/v1/pets/pet1", strings.NewReader(body))
+		req.Header.Set("Content-Type", handler.MergePatchContentType)
+		req.Header.Set("Authorization", "Bearer "+signTestToken(t, "owner1"))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := patch(`{"age": 0}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("PATCH age status = %d, want %d; body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if got.GetPetId() != "pet1" {
+		t.Errorf("PetId = %q, want pet1", got.GetPetId())
+	}
+	if got.Age == nil || *got.Age != 0 {
+		t.Errorf("Age = %v, want explicitly set to 0", got.Age)
+	}
+	if got.Name != nil || got.Species != nil || got.Breed != nil || got.Description != nil || got.ImageUrls != nil {
+		t.Errorf("request = %v, want only age set", got)
+	}
+
+	// null removes an optional field.
+	if w := patch(`{"description": null}`); w.Code != http.StatusOK {
+		t.Fatalf("PATCH description null status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if got.Description == nil || *got.Description != "" || got.Age != nil {
+		t.Errorf("request = %v, want only description set to empty", got)
+	}
+
+	for _, body := range []string{`{"name": null}`, `{"age": "three"}`, `[1, 2]`} {
+		if w := patch(body); w.Code != http.StatusBadRequest {
+			t.Errorf("PATCH %s status = %d, want %d", body, w.Code, http.StatusBadRequest)
+		}
+		if got != nil {
+			t.Errorf("PATCH %s reached the pet service", body)
+		}
+	}
+}
+
+func TestPetHandler_ListPets_TagsFilter(t *testing.T) {
+	var got *pbPet.ListPetsRequest
+	mockPetClient := &MockPetServiceClient{
+		ListPetsFunc: func(ctx context.Context, req *pbPet.ListPetsRequest) (*pbPet.ListPetsResponse, error) {
+			got = req
+			return &pbPet.ListPetsResponse{}, nil
+		},
+	}
+	r := gin.New()
+	r.GET("/pets", handler.NewPetHandler(mockPetClient).ListPets)
+
+	if w := performRequest(r, http.MethodGet, "/pets?tags=house-trained,good%20with%20kids&tags_match=all"); w.Code != http.StatusOK {
+		t.Fatalf("ListPets() status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if len(got.GetTagsFilter()) != 2 || got.GetTagsFilter()[1] != "good with kids" || !got.GetMatchAllTags() {
+		t.Errorf("ListPets() request = %v, want both tags with match_all_tags", got)
+	}
+
+	if w := performRequest(r, http.MethodGet, "/pets?tags=house-trained&tags_match=most"); w.Code != http.StatusBadRequest {
+		t.Errorf("ListPets() with invalid tags_match status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestPetHandler_ListPets_SearchQuery(t *testing.T) {
+	var got *pbPet.ListPetsRequest
+	mockPetClient := &MockPetServiceClient{
+		ListPetsFunc: func(ctx context.Context, req *pbPet.ListPetsRequest) (*pbPet.ListPetsResponse, error) {
+			got = req
+			return &pbPet.ListPetsResponse{}, nil
+		},
+	}
+	r := gin.New()
+	r.GET("/pets", handler.NewPetHandler(mockPetClient).ListPets)
+
+	if w := performRequest(r, http.MethodGet, "/pets?q=%20golden%20retriever%20&species_filter=Dog"); w.Code != http.StatusOK {
+		t.Fatalf("ListPets() status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if got.SearchQuery == nil || got.GetSearchQuery() != "golden retriever" || got.GetSpeciesFilter() != "Dog" {
+		t.Errorf("ListPets() request = %v, want the trimmed search query with the species filter", got)
+	}
+
+	if w := performRequest(r, http.MethodGet, "/pets?q="); w.Code != http.StatusOK {
+		t.Fatalf("ListPets() with empty q status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if got.SearchQuery != nil {
+		t.Errorf("ListPets() with empty q sent search_query %q, want none", got.GetSearchQuery())
+	}
+}
+
+func TestPetHandler_ListPets_MultipleSpecies(t *testing.T) {
+	var got *pbPet.ListPetsRequest
+	mockPetClient := &MockPetServiceClient{
+		ListPetsFunc: func(ctx context.Context, req *pbPet.ListPetsRequest) (*pbPet.ListPetsResponse, error) {
+			got = req
+			return &pbPet.ListPetsResponse{}, nil
+		},
+	}
+	r := gin.New()
+	r.GET("/pets", handler.NewPetHandler(mockPetClient).ListPets)
+
+	tests := []struct {
+		query       string
+		wantFilter  string
+		wantFilters []string
+	}{
+		{"", "", nil},
+		{"species_filter=Dog", "Dog", nil},
+		{"species=Dog", "", []string{"Dog"}},
+		{"species=Dog&species=Cat", "", []string{"Dog", "Cat"}},
+	}
+	for _, tt := range tests {
+		if w := performRequest(r, http.MethodGet, "/pets?"+tt.query); w.Code != http.StatusOK {
+			t.Fatalf("ListPets(%q) status = %d, want %d", tt.query, w.Code, http.StatusOK)
+		}
+		if got.GetSpeciesFilter() != tt.wantFilter || !reflect.DeepEqual(got.GetSpeciesFilters(), tt.wantFilters) {
+			t.Errorf("ListPets(%q) species_filter = %q, species_filters = %v; want %q, %v", tt.query, got.GetSpeciesFilter(), got.GetSpeciesFilters(), tt.wantFilter, tt.wantFilters)
+		}
+	}
+}
+
+func TestPetHandler_ListPets_AgeRange(t *testing.T) {
+	var got *pbPet.ListPetsRequest
+	mockPetClient := &MockPetServiceClient{
+		ListPetsFunc: func(ctx context.Context, req *pbPet.ListPetsRequest) (*pbPet.ListPetsResponse, error) {
+			got = req
+			return &pbPet.ListPetsResponse{}, nil
+		},
+	}
+	r := gin.New()
+	r.GET("/pets", handler.NewPetHandler(mockPetClient).ListPets)
+
+	if w := performRequest(r, http.MethodGet, "/pets?min_age=0&max_age=2"); w.Code != http.StatusOK {
+		t.Fatalf("ListPets() status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if got.MinAge == nil || got.GetMinAge() != 0 || got.MaxAge == nil || got.GetMaxAge() != 2 {
+		t.Errorf("ListPets() request = %v, want min_age 0 and max_age 2", got)
+	}
+
+	if w := performRequest(r, http.MethodGet, "/pets?min_age=8"); w.Code != http.StatusOK || got.MaxAge != nil {
+		t.Errorf("ListPets() with only min_age status = %d, max_age = %v, want %d and no max_age", w.Code, got.MaxAge, http.StatusOK)
+	}
+	if w := performRequest(r, http.MethodGet, "/pets?max_age=two"); w.Code != http.StatusBadRequest {
+		t.Errorf("ListPets() with non-numeric max_age status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestPetHandler_ListPets_CursorPagination(t *testing.T) {
+	var got *pbPet.ListPetsRequest
+	mockPetClient := &MockPetServiceClient{
+		ListPetsFunc: func(ctx context.Context, req *pbPet.ListPetsRequest) (*pbPet.ListPetsResponse, error) {
+			got = req
+			if req.GetCursor() == "malformed" {
+				return nil, status.Error(codes.InvalidArgument, "invalid pagination cursor")
+			}
+			return &pbPet.ListPetsResponse{NextCursor: "next"}, nil
+		},
+	}
+	r := gin.New()
+	r.GET("/pets", handler.NewPetHandler(mockPetClient).ListPets)
+
+	if w := performRequest(r, http.MethodGet, "/pets?cursor=&limit=5"); w.Code != http.StatusOK {
+		t.Fatalf("ListPets() with cursor status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if got.Cursor == nil || got.Page != nil {
+		t.Errorf("ListPets() with cursor request = %v, want a cursor and no page", got)
+	}
+
+	got = nil
+	if w := performRequest(r, http.MethodGet, "/pets?cursor=next&page=2"); w.Code != http.StatusBadRequest {
+		t.Errorf("ListPets() with cursor and page status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	if got != nil {
+		t.Errorf("ListPets() with cursor and page called the pet service")
+	}
+
+	if w := performRequest(r, http.MethodGet, "/pets?cursor=malformed"); w.Code != http.StatusBadRequest {
+		t.Errorf("ListPets() with malformed cursor status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestPetHandler_BrowsePets_AppliesPreset(t *testing.T) {
+	var got *pbPet.ListPetsRequest
+	mockPetClient := &MockPetServiceClient{
+		ListPetsFunc: func(ctx context.Context, req *pbPet.ListPetsRequest) (*pbPet.ListPetsResponse, error) {
+			got = req
+			return &pbPet.ListPetsResponse{}, nil
+		},
+	}
+	presets, err := handler.ParseBrowsePresets("available_newest:AVAILABLE:newest,raw::")
+	if err != nil {
+		t.Fatalf("ParseBrowsePresets() error = %v", err)
+	}
+	petHandler := handler.NewPetHandler(mockPetClient)
+	if err := petHandler.SetBrowsePresets(presets, "available_newest"); err != nil {
+		t.Fatalf("SetBrowsePresets() error = %v", err)
+	}
+	r := gin.New()
+	r.GET("/pets/browse", petHandler.BrowsePets)
+
+	tests := []struct {
+		query      string
+		wantStatus pbPet.AdoptionStatus
+		wantSort   string
+	}{
+		{"", pbPet.AdoptionStatus_AVAILABLE, "newest"},                                          // default preset
+		{"?status_filter=ADOPTED", pbPet.AdoptionStatus_ADOPTED, "newest"},                      // explicit filter wins
+		{"?sort=oldest", pbPet.AdoptionStatus_AVAILABLE, "oldest"},                              // explicit sort wins
+		{"?preset=raw", pbPet.AdoptionStatus_ADOPTION_STATUS_UNSPECIFIED, ""},                   // no defaults
+		{"?preset=raw&sort=newest", pbPet.AdoptionStatus_ADOPTION_STATUS_UNSPECIFIED, "newest"}, // raw plus explicit sort
+	}
+	for _, tt := range tests {
+		got = nil
+		if w := performRequest(r, http.MethodGet, "/pets/browse"+tt.query); w.Code != http.StatusOK {
+			t.Fatalf("BrowsePets(%q) status = %d, want %d", tt.query, w.Code, http.StatusOK)
+		}
+		if got.GetStatusFilter() != tt.wantStatus || got.GetSort() != tt.wantSort {
+			t.Errorf("BrowsePets(%q) status_filter = %v, sort = %q; want %v, %q", tt.query, got.GetStatusFilter(), got.GetSort(), tt.wantStatus, tt.wantSort)
+		}
+	}
+
+	if w := performRequest(r, http.MethodGet, "/pets/browse?preset=unknown"); w.Code != http.StatusBadRequest {
+		t.Errorf("BrowsePets() with unknown preset status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	if w := performRequest(r, http.MethodGet, "/pets/browse?sort=name"); w.Code != http.StatusBadRequest {
+		t.Errorf("BrowsePets() with invalid sort status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	if _, err := handler.ParseBrowsePresets("broken:SOLD:newest"); err == nil {
+		t.Error("ParseBrowsePresets() with invalid status_filter should fail")
+	}
+}
+
+func TestListEnums_ReturnsProtoValues(t *testing.T) {
+	maintenance := middleware.NewMaintenance(middleware.MaintenanceOff, "")
+	r := newTestRouter(&MockUserServiceClient{}, &MockPetServiceClient{}, &MockAdoptionServiceClient{}, maintenance, "")
+
+	w := performRequest(r, http.MethodGet, "/api/v1/meta/enums")
+	if w.Code != http.StatusOK {
+		t.Fatalf("ListEnums() status = %d, want %d", w.Code, http.StatusOK)
+	}
+	var resp handler.EnumsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode enums response: %v", err)
+	}
+	want := map[string][]string{
+		"pet_adoption_statuses": {"AVAILABLE", "PENDING_ADOPTION", "ADOPTED"},
+		"application_statuses":  {"PENDING_REVIEW", "APPROVED", "REJECTED", "CANCELLED_BY_USER"},
+		"pet_sort_orders":       {"newest", "oldest"},
+		"tags_match_modes":      {"any", "all"},
+	}
+	got := map[string][]string{
+		"pet_adoption_statuses": resp.PetAdoptionStatuses,
+		"application_statuses":  resp.ApplicationStatuses,
+		"pet_sort_orders":       resp.PetSortOrders,
+		"tags_match_modes":      resp.TagsMatchModes,
+	}
+	for name, values := range want {
+		if strings.Join(got[name], ",") != strings.Join(values, ",") {
+			t.Errorf("ListEnums() %s = %v, want %v", name, got[name], values)
+		}
+	}
+}
+
+// startBlockingServer serves requests that block until release is closed, counting them in a new RequestTracker.
+func startBlockingServer(t *testing.T, entered chan<- struct{}, release <-chan struct{}) (*http.Server, *middleware.RequestTracker, string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	tracker := middleware.NewRequestTracker()
+	srv := &http.Server{Handler: tracker.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		entered <- struct{}{}
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))}
+	go srv.Serve(ln)
+	return srv, tracker, "http://" + ln.Addr().String()
+}
+
+// waitForListenerClosed returns once addr stops accepting connections, i.e. shutdown has begun.
+func waitForListenerClosed(t *testing.T, url string) {
+	t.Helper()
+	addr := strings.TrimPrefix(url, "http://")
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		conn, err := net.Dial("tcp", addr)
+		if err != nil {
+			return
+		}
+		conn.Close()
+		if time.Now().After(deadline) {
+			t.Fatal("server kept accepting connections after shutdown began")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestShutdown_DrainsActiveRequests(t *testing.T) {
+	entered, release := make(chan struct{}, 2), make(chan struct{})
+	srv, tracker, url := startBlockingServer(t, entered, release)
+
+	responses := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			resp, err := http.Get(url)
+			if err == nil {
+				resp.Body.Close()
+			}
+			responses <- err
+		}()
+	}
+	<-entered
+	<-entered
+
+	done := make(chan server.DrainStats, 1)
+	go func() {
+		stats, err := server.Shutdown(srv, tracker, 5*time.Second)
+		if err != nil {
+			t.Errorf("Shutdown() error = %v", err)
+		}
+		done <- stats
+	}()
+	waitForListenerClosed(t, url)
+	close(release)
+
+	stats := <-done
+	if stats.ActiveAtShutdown != 2 || stats.Drained != 2 || stats.Forced {
+		t.Errorf("Shutdown() = %+v, want 2 active, 2 drained, not forced", stats)
+	}
+	for i := 0; i < 2; i++ {
+		if err := <-responses; err != nil {
+			t.Errorf("in-flight request failed during shutdown: %v", err)
+		}
+	}
+}
+
+func TestShutdown_ForcesCloseAfterTimeout(t *testing.T) {
+	entered, release := make(chan struct{}, 1), make(chan struct{})
+	defer close(release)
+	srv, tracker, url := startBlockingServer(t, entered, release)
+
+	go func() {
+		if resp, err := http.Get(url); err == nil {
+			resp.Body.Close()
+		}
+	}()
+	<-entered
+
+	stats, err := server.Shutdown(srv, tracker, 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if !stats.Forced || stats.ActiveAtShutdown != 1 || stats.Drained != 0 {
+		t.Errorf("Shutdown() = %+v, want 1 active, 0 drained, forced", stats)
+	}
+}
+
+func TestRedirectAllowlist_Validate(t *testing.T) {
+	allowlist, err := oauth.NewRedirectAllowlist([]string{
+		"https://app.example.com/oauth/callback",
+		"https://mobile.example.com/auth/*",
+		"http://localhost:3000/callback",
+	})
+	if err != nil {
+		t.Fatalf("NewRedirectAllowlist() error = %v", err)
+	}
+
+	tests := []struct {
+		redirect string
+		allowed  bool
+	}{
+		{"https://app.example.com/oauth/callback", true},
+		{"https://APP.example.com/oauth/callback", true},
+		{"https://mobile.example.com/auth/", true},
+		{"https://mobile.example.com/auth/ios/done?state=xyz", true},
+		{"http://localhost:3000/callback", true},
+		{"https://app.example.com/oauth/callback/extra", false},
+		{"https://app.example.com/oauth/callback?next=/admin", false},
+		{"http://app.example.com/oauth/callback", false},
+		{"https://app.example.com.evil.com/oauth/callback", false},
+		{"https://evil.com/oauth/callback", false},
+		{"https://mobile.example.com/auth/../admin", false},
+		{"https://mobile.example.com/other", false},
+		{"https://user@app.example.com/oauth/callback", false},
+		{"https://app.example.com/oauth/callback#token", false},
+		{"//app.example.com/oauth/callback", false},
+		{"javascript:alert(1)", false},
+		{"http://localhost:4000/callback", false},
+	}
+	for _, tt := range tests {
+		err := allowlist.Validate(tt.redirect)
+		if tt.allowed && err != nil {
+			t.Errorf("Validate(%q) error = %v, want allowed", tt.redirect, err)
+		}
+		if !tt.allowed && !errors.Is(err, oauth.ErrRedirectNotAllowed) {
+			t.Errorf("Validate(%q) error = %v, want ErrRedirectNotAllowed", tt.redirect, err)
+		}
+	}
+
+	for _, entry := range []string{"ftp://example.com/cb", "https:///cb", "http://app.example.com/cb", "https://app.example.com/cb?x=1*"} {
+		if _, err := oauth.NewRedirectAllowlist([]string{entry}); err == nil {
+			t.Errorf("NewRedirectAllowlist(%q) error = nil, want an error", entry)
+		}
+	}
+	if err := (oauth.RedirectAllowlist{}).Validate("https://app.example.com/oauth/callback"); !errors.Is(err, oauth.ErrRedirectNotAllowed) {
+		t.Errorf("empty allowlist Validate() error = %v, want ErrRedirectNotAllowed", err)
+	}
+}
+
+func TestConfig_Summary_IncludesEveryFlag(t *testing.T) {
+	cfg := &config.Config{
+		GinMode:                 "release",
+		GRPCLoadBalancingPolicy: client.LoadBalancingRoundRobin,
+		MaintenanceMode:         "read_only",
+		AdminAPIToken:           "admin-secret",
+		JWTSecretKey:            "jwt-secret",
+	}
+
+	summary := settings.Summary(cfg.Settings())
+	for _, s := range cfg.Settings() {
+		if want := s.Name + "=" + s.Value; !strings.Contains(summary, want) {
+			t.Errorf("Summary() = %q, missing %q", summary, want)
+		}
+	}
+	if !strings.Contains(summary, "maintenance_mode=read_only") || !strings.Contains(summary, "admin_endpoints_enabled=true") {
+		t.Errorf("Summary() = %q, want resolved maintenance mode and admin flag", summary)
+	}
+	if strings.Contains(summary, "admin-secret") || strings.Contains(summary, "jwt-secret") {
+		t.Errorf("Summary() = %q, should not contain secrets", summary)
+	}
+}
+
+func TestRequireRole_AdminClaimAllowedUserDenied(t *testing.T) {
+	var forwardedRoles []string
+	petClient := &MockPetServiceClient{
+		UpdatePetAdoptionStatusFunc: func(ctx context.Context, req *pbPet.UpdatePetAdoptionStatusRequest) (*pbPet.PetResponse, error) {
+			md, _ := metadata.FromOutgoingContext(ctx)
+			forwardedRoles = md.Get("x-user-roles")
+			return &pbPet.PetResponse{Pet: &pbPet.Pet{Id: req.GetPetId()}}, nil
+		},
+	}
+	r := newTestRouter(&MockUserServiceClient{}, petClient, &MockAdoptionServiceClient{}, middleware.NewMaintenance(middleware.MaintenanceOff, ""), "")
+
+	patch := func(token string) int {
+		req := httptest.NewRequest(http.MethodPatch, "/api/v1/pets/pet-1/status", strings.NewReader(`{"new_status":3}`))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := patch(signTestTokenWithRole(t, "admin-1", middleware.RoleAdmin)); code != http.StatusOK {
+		t.Errorf("PATCH as admin status = %d, want %d", code, http.StatusOK)
+	}
+	if len(forwardedRoles) != 1 || forwardedRoles[0] != middleware.RoleAdmin {
+		t.Errorf("forwarded roles = %v, want [%s]", forwardedRoles, middleware.RoleAdmin)
+	}
+
+	forwardedRoles = nil
+	if code := patch(signTestTokenWithRole(t, "user-1", middleware.RoleUser)); code != http.StatusForbidden {
+		t.Errorf("PATCH as regular user status = %d, want %d", code, http.StatusForbidden)
+	}
+	if code := patch(signTestToken(t, "user-2")); code != http.StatusForbidden {
+		t.Errorf("PATCH with a token without role status = %d, want %d", code, http.StatusForbidden)
+	}
+	if forwardedRoles != nil {
+		t.Error("pet service was called for a user without the admin role")
+	}
+	if code := patch(""); code != http.StatusUnauthorized {
+		t.Errorf("PATCH without token status = %d, want %d", code, http.StatusUnauthorized)
+	}
+}
+
+// fakeRevokedTokens is an in-memory middleware.RevokedTokens.
+type fakeRevokedTokens struct {
+	mu  sync.Mutex
+	ids map[string]bool
+	err error // Returned by every check when set, like an unreachable Redis
+}
+
+func (f *fakeRevokedTokens) IsTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return false, f.err
+	}
+	return f.ids[tokenID], nil
+}
+
+func TestLogoutUser_RevokedTokenRejected(t *testing.T) {
+	revoked := &fakeRevokedTokens{ids: make(map[string]bool)}
+	userClient := &MockUserServiceClient{
+		// Like the user-service, blocklist the ID of the token being logged out.
+		LogoutUserFunc: func(ctx context.Context, req *pbUser.LogoutRequest) (*pbUser.EmptyResponse, error) {
+			claims, err := middleware.NewHMACVerifier([]byte(testJWTSecret)).VerifyAccessToken(req.GetAccessToken())
+			if err != nil {
+				return nil, status.Error(codes.Unauthenticated, "invalid token")
+			}
+			revoked.mu.Lock()
+			revoked.ids[claims.TokenID] = true
+			revoked.mu.Unlock()
+			return &pbUser.EmptyResponse{}, nil
+		},
+	}
+	petClient, adoptionClient := &MockPetServiceClient{}, &MockAdoptionServiceClient{}
+	maintenance := middleware.NewMaintenance(middleware.MaintenanceOff, "")
+	verifier := middleware.NewHMACVerifier([]byte(testJWTSecret)).WithRevokedTokens(revoked)
+	r := router.New(
+		handler.NewUserHandler(userClient),
+		handler.NewPetHandler(petClient),
+		handler.NewAdoptionHandler(adoptionClient),
+		handler.NewCompositeHandler(userClient, petClient, adoptionClient),
+		handler.NewAdminHandler(maintenance, nil),
+		handler.NewHealthHandler(userClient, petClient, adoptionClient),
+		maintenance,
+		middleware.RequireAdmin("", verifier, nil),
+		middleware.RequireAuthWithRoles(verifier, nil),
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+	send := func(method, path, token string) int {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	token := signTestTokenWithClaims(t, "user-1", jwt.MapClaims{"jti": "token-1"})
+	otherToken := signTestTokenWithClaims(t, "user-1", jwt.MapClaims{"jti": "token-2"})
+	if code := send(http.MethodGet, "/api/v1/users/token/verify", token); code != http.StatusOK {
+		t.Fatalf("verify before logout status = %d, want %d", code, http.StatusOK)
+	}
+	if code := send(http.MethodPost, "/api/v1/users/logout", token); code != http.StatusNoContent {
+		t.Fatalf("logout status = %d, want %d", code, http.StatusNoContent)
+	}
+	if code := send(http.MethodGet, "/api/v1/users/token/verify", token); code != http.StatusUnauthorized {
+		t.Errorf("verify after logout status = %d, want %d", code, http.StatusUnauthorized)
+	}
+	if code := send(http.MethodPost, "/api/v1/users/logout", token); code != http.StatusUnauthorized {
+		t.Errorf("second logout status = %d, want %d", code, http.StatusUnauthorized)
+	}
+	if code := send(http.MethodGet, "/api/v1/users/token/verify", otherToken); code != http.StatusOK {
+		t.Errorf("verify with another token of the user status = %d, want %d", code, http.StatusOK)
+	}
+}
+
+func TestRequireAuth_BlocklistUnavailable(t *testing.T) {
+	revoked := &fakeRevokedTokens{err: errors.New("redis: connection refused")}
+	token := signTestTokenWithClaims(t, "user-1", jwt.MapClaims{"jti": "token-1"})
+	for _, tc := range []struct {
+		failOpen bool
+		want     int
+	}{
+		{false, http.StatusServiceUnavailable},
+		{true, http.StatusOK},
+	} {
+		verifier := middleware.NewHMACVerifier([]byte(testJWTSecret)).WithRevokedTokens(revoked).WithRevocationFailOpen(tc.failOpen)
+		r := gin.New()
+		r.GET("/protected", middleware.RequireAuthWithRoles(verifier, nil), func(c *gin.Context) { c.Status(http.StatusOK) })
+		req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tc.want {
+			t.Errorf("fail-open %v: status = %d, want %d", tc.failOpen, w.Code, tc.want)
+		}
+	}
+}
+
+// TODO: Add more test cases:
+// - UserHandler/PetHandler/AdoptionHandler gRPC error code to HTTP status mapping
+// - Request binding failures (400) for create/update endpoints
+
+func TestPetHandler_AdminListPets_IncludeDeleted(t *testing.T) {
+	var gotReq *pbPet.ListPetsRequest
+	var gotRoles []string
+	petClient := &MockPetServiceClient{
+		ListPetsFunc: func(ctx context.Context, req *pbPet.ListPetsRequest) (*pbPet.ListPetsResponse, error) {
+			gotReq = req
+			md, _ := metadata.FromOutgoingContext(ctx)
+			gotRoles = md.Get("x-user-roles")
+			return &pbPet.ListPetsResponse{}, nil
+		},
+	}
+	r := newTestRouter(&MockUserServiceClient{}, petClient, &MockAdoptionServiceClient{}, middleware.NewMaintenance(middleware.MaintenanceOff, ""), "admin-token")
+
+	adminGet := func(path string) int {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("X-Admin-Token", "admin-token")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := adminGet("/admin/pets?include_deleted=true"); code != http.StatusOK {
+		t.Fatalf("AdminListPets() status = %d, want %d", code, http.StatusOK)
+	}
+	if !gotReq.GetIncludeDeleted() || !slices.Contains(gotRoles, "admin") {
+		t.Errorf("AdminListPets() include_deleted = %v, roles = %v, want true with the admin role", gotReq.GetIncludeDeleted(), gotRoles)
+	}
+	if code := adminGet("/admin/pets?include_deleted=maybe"); code != http.StatusBadRequest {
+		t.Errorf("AdminListPets() with include_deleted=maybe status = %d, want %d", code, http.StatusBadRequest)
+	}
+
+	gotReq = nil
+	if w := performRequest(r, http.MethodGet, "/api/v1/pets?include_deleted=true"); w.Code != http.StatusOK {
+		t.Fatalf("ListPets() status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if gotReq.IncludeDeleted != nil {
+		t.Error("public ListPets() forwarded include_deleted, want it ignored")
+	}
+}
+
+func TestPetHandler_RestorePet(t *testing.T) {
+	petClient := &MockPetServiceClient{
+		RestorePetFunc: func(ctx context.Context, req *pbPet.RestorePetRequest) (*pbPet.PetResponse, error) {
+			md, _ := metadata.FromOutgoingContext(ctx)
+			if callers := md.Get("x-user-id"); len(callers) == 0 || callers[0] != "owner1" {
+				return nil, status.Error(codes.PermissionDenied, "Only the user who listed this pet or an admin can restore it")
+			}
+			if req.GetPetId() == "active" {
+				return nil, status.Error(codes.FailedPrecondition, "Pet is not deleted")
+			}
+			return &pbPet.PetResponse{Pet: &pbPet.Pet{Id: req.GetPetId()}}, nil
+		},
+	}
+	r := newTestRouter(&MockUserServiceClient{}, petClient, &MockAdoptionServiceClient{}, middleware.NewMaintenance(middleware.MaintenanceOff, ""), "")
+
+	restore := func(petID, userID string) int {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/pets/"+petID+"/restore", nil)
+		if userID != "" {
+			req.Header.Set("Authorization", "Bearer "+signTestToken(t, userID))
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	for _, tc := range []struct {
+		petID, userID string
+		want          int
+	}{
+		{"pet1", "owner1", http.StatusOK},
+		{"pet1", "", http.StatusUnauthorized},
+		{"pet1", "intruder", http.StatusForbidden},
+		{"active", "owner1", http.StatusConflict},
+	} {
+		if code := restore(tc.petID, tc.userID); code != tc.want {
+			t.Errorf("RestorePet(%s) as %q status = %d, want %d", tc.petID, tc.userID, code, tc.want)
+		}
+	}
+}
+
+func TestPetHandler_CreatePet_ShelterCanListPendingAdoption(t *testing.T) {
+	var got *pbPet.CreatePetRequest
+	mockPetClient := &MockPetServiceClient{
+		CreatePetFunc: func(ctx context.Context, req *pbPet.CreatePetRequest) (*pbPet.PetResponse, error) {
+			got = req
+			md, _ := metadata.FromOutgoingContext(ctx)
+			if req.AdoptionStatus != nil && !slices.Contains(md.Get("x-user-roles"), "shelter") {
+				return nil, status.Error(codes.PermissionDenied, "Admin or shelter role is required to set a pet's initial status")
+			}
+			return &pbPet.PetResponse{Pet: &pbPet.Pet{Id: "pet1", Name: req.GetName(), ListedByUserId: req.GetListedByUserId(), AdoptionStatus: req.GetAdoptionStatus()}}, nil
+		},
+	}
+	maintenance := middleware.NewMaintenance(middleware.MaintenanceOff, "")
+	verifier := middleware.NewHMACVerifier([]byte(testJWTSecret))
+	roles := middleware.NewRoles(nil)
+	r := router.New(
+		handler.NewUserHandler(&MockUserServiceClient{}),
+		handler.NewPetHandler(mockPetClient),
+		handler.NewAdoptionHandler(&MockAdoptionServiceClient{}),
+		handler.NewCompositeHandler(&MockUserServiceClient{}, mockPetClient, &MockAdoptionServiceClient{}),
+		handler.NewAdminHandler(maintenance, nil),
+		handler.NewHealthHandler(&MockUserServiceClient{}, mockPetClient, &MockAdoptionServiceClient{}),
+		maintenance,
+		middleware.RequireAdmin("", verifier, roles),
+		middleware.RequireAuthWithRoles(verifier, roles),
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	create := func(token string) int {
+		got = nil
+		body := `{"name": "Buddy", "species": "Dog", "listed_by_user_id": "someone-else", "adoption_status": ` + strconv.Itoa(int(pbPet.AdoptionStatus_PENDING_ADOPTION)) + `}`
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/pets", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := create(signTestTokenWithRole(t, "shelter-1", "shelter")); code != http.StatusCreated {
+		t.Fatalf("POST /pets as shelter status = %d, want %d", code, http.StatusCreated)
+	}
+	if got.GetListedByUserId() != "shelter-1" {
+		t.Errorf("ListedByUserId = %q, want the authenticated user shelter-1", got.GetListedByUserId())
+	}
+	if code := create(signTestToken(t, "user-1")); code != http.StatusForbidden {
+		t.Errorf("POST /pets as regular user status = %d, want %d", code, http.StatusForbidden)
+	}
+	if code := create(""); code != http.StatusUnauthorized || got != nil {
+		t.Errorf("POST /pets without token status = %d (reached pet service: %v), want %d", code, got != nil, http.StatusUnauthorized)
+	}
+}
+
+func TestAdoptionHandler_CreateAdoptionApplication_AppliesAsAuthenticatedUser(t *testing.T) {
+	var got *pbAdoption.CreateAdoptionApplicationRequest
+	var gotCallers, gotRoles []string
+	mockAdoptionClient := &MockAdoptionServiceClient{
+		CreateAdoptionApplicationFunc: func(ctx context.Context, req *pbAdoption.CreateAdoptionApplicationRequest) (*pbAdoption.AdoptionApplicationResponse, error) {
+			got = req
+			md, _ := metadata.FromOutgoingContext(ctx)
+			gotCallers, gotRoles = md.Get("x-user-id"), md.Get("x-user-roles")
+			// The adoption-service auto-approves when the applicant is the caller and has the trusted role.
+			appStatus := pbAdoption.ApplicationStatus_PENDING_REVIEW
+			if len(gotCallers) == 1 && gotCallers[0] == req.GetUserId() && slices.Contains(gotRoles, "trusted") {
+				appStatus = pbAdoption.ApplicationStatus_APPROVED
+			}
+			return &pbAdoption.AdoptionApplicationResponse{Application: &pbAdoption.AdoptionApplication{Id: "app1", UserId: req.GetUserId(), PetId: req.GetPetId(), Status: appStatus}}, nil
+		},
+	}
+	maintenance := middleware.NewMaintenance(middleware.MaintenanceOff, "")
+	verifier := middleware.NewHMACVerifier([]byte(testJWTSecret))
+	roles := middleware.NewRoles(nil)
+	r := router.New(
+		handler.NewUserHandler(&MockUserServiceClient{}),
+		handler.NewPetHandler(&MockPetServiceClient{}),
+		handler.NewAdoptionHandler(mockAdoptionClient),
+		handler.NewCompositeHandler(&MockUserServiceClient{}, &MockPetServiceClient{}, mockAdoptionClient),
+		handler.NewAdminHandler(maintenance, nil),
+		handler.NewHealthHandler(&MockUserServiceClient{}, &MockPetServiceClient{}, mockAdoptionClient),
+		maintenance,
+		middleware.RequireAdmin("", verifier, roles),
+		middleware.RequireAuthWithRoles(verifier, roles),
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	apply := func(token string) *httptest.ResponseRecorder {
+		got = nil
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/adoptions", strings.NewReader(`{"user_id": "someone-else", "pet_id": "pet1"}`))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := apply(signTestTokenWithRole(t, "trusted-1", "trusted"))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("POST /adoptions as trusted user status = %d, want %d: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+	if got.GetUserId() != "trusted-1" || !reflect.DeepEqual(gotCallers, []string{"trusted-1"}) {
+		t.Errorf("applicant = %q, x-user-id = %v, want the authenticated user trusted-1", got.GetUserId(), gotCallers)
+	}
+	if !slices.Contains(gotRoles, "trusted") {
+		t.Errorf("forwarded roles = %v, want the token's trusted role", gotRoles)
+	}
+	var resp pbAdoption.AdoptionApplicationResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("could not decode response: %v", err)
+	}
+	if resp.GetApplication().GetStatus() != pbAdoption.ApplicationStatus_APPROVED {
+		t.Errorf("application status = %v, want APPROVED", resp.GetApplication().GetStatus())
+	}
+
+	if w := apply(""); w.Code != http.StatusUnauthorized || got != nil {
+		t.Errorf("POST /adoptions without token status = %d (reached adoption service: %v), want %d", w.Code, got != nil, http.StatusUnauthorized)
+	}
+}
+
+func TestPetHandler_AdminSetPetStatus_RecordsAuthenticatedAdmin(t *testing.T) {
+	var got *pbPet.AdminSetPetStatusRequest
+	mockPetClient := &MockPetServiceClient{
+		AdminSetPetStatusFunc: func(ctx context.Context, req *pbPet.AdminSetPetStatusRequest) (*pbPet.PetResponse, error) {
+			got = req
+			return &pbPet.PetResponse{Pet: &pbPet.Pet{Id: req.GetPetId(), AdoptionStatus: req.GetNewStatus()}}, nil
+		},
+	}
+	maintenance := middleware.NewMaintenance(middleware.MaintenanceOff, "")
+	verifier := middleware.NewHMACVerifier([]byte(testJWTSecret))
+	roles := middleware.NewRoles(nil)
+	r := router.New(
+		handler.NewUserHandler(&MockUserServiceClient{}),
+		handler.NewPetHandler(mockPetClient),
+		handler.NewAdoptionHandler(&MockAdoptionServiceClient{}),
+		handler.NewCompositeHandler(&MockUserServiceClient{}, mockPetClient, &MockAdoptionServiceClient{}),
+		handler.NewAdminHandler(maintenance, nil),
+		handler.NewHealthHandler(&MockUserServiceClient{}, mockPetClient, &MockAdoptionServiceClient{}),
+		maintenance,
+		middleware.RequireAdmin("admin-secret", verifier, roles),
+		middleware.RequireAuthWithRoles(verifier, roles),
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	tests := []struct {
+		name          string
+		header        string
+		value         string
+		wantChangedBy string
+	}{
+		{name: "bearer admin", header: "Authorization", value: "Bearer " + signTestTokenWithRole(t, "admin-1", middleware.RoleAdmin), wantChangedBy: "admin-1"},
+		{name: "admin token", header: "X-Admin-Token", value: "admin-secret", wantChangedBy: "admin-token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = nil
+			body := `{"new_status": ` + strconv.Itoa(int(pbPet.AdoptionStatus_AVAILABLE)) + `, "reason": "data fix", "changed_by": "somebody else"}`
+			req := httptest.NewRequest(http.MethodPut, "/admin/pets/pet1/status", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set(tt.header, tt.value)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("PUT /admin/pets/pet1/status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+			}
+			if got.GetChangedBy() != tt.wantChangedBy {
+				t.Errorf("ChangedBy = %q, want %q", got.GetChangedBy(), tt.wantChangedBy)
+			}
+		})
+	}
+}
+
+func TestPetHandler_ImageRoutes_OwnerOnly(t *testing.T) {
+	ownerOnly := func(ctx context.Context) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		if callers := md.Get("x-user-id"); len(callers) == 0 || callers[0] != "owner1" {
+			return status.Error(codes.PermissionDenied, "Only the user who listed this pet can add its images")
+		}
+		return nil
+	}
+	petClient := &MockPetServiceClient{
+		GetImageUploadURLFunc: func(ctx context.Context, req *pbPet.GetImageUploadURLRequest) (*pbPet.ImageUploadTarget, error) {
+			if err := ownerOnly(ctx); err != nil {
+				return nil, err
+			}
+			return &pbPet.ImageUploadTarget{Url: "http://images.test/bucket"}, nil
+		},
+		AddImageURLsFunc: func(ctx context.Context, req *pbPet.AddImageURLsRequest) (*pbPet.PetResponse, error) {
+			if err := ownerOnly(ctx); err != nil {
+				return nil, err
+			}
+			return &pbPet.PetResponse{Pet: &pbPet.Pet{Id: req.GetPetId(), ImageUrls: req.GetImageUrls()}}, nil
+		},
+	}
+	r := newTestRouter(&MockUserServiceClient{}, petClient, &MockAdoptionServiceClient{}, middleware.NewMaintenance(middleware.MaintenanceOff, ""), "")
+
+	post := func(path, body, userID string) int {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if userID != "" {
+			req.Header.Set("Authorization", "Bearer "+signTestToken(t, userID))
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	for _, tc := range []struct {
+		path, body string
+	}{
+		{"/api/v1/pets/pet1/images/upload-url", `{"filename": "a.jpg", "content_type": "image/jpeg"}`},
+		{"/api/v1/pets/pet1/images", `{"image_urls": ["http://images.test/bucket/pets/pet1/a.jpg"]}`},
+	} {
+		for userID, want := range map[string]int{"owner1": http.StatusOK, "": http.StatusUnauthorized, "intruder": http.StatusForbidden} {
+			if code := post(tc.path, tc.body, userID); code != want {
+				t.Errorf("POST %s as %q status = %d, want %d", tc.path, userID, code, want)
+			}
+		}
+	}
+}
+
+func TestAdoptionHandler_CancelAdoptionApplication(t *testing.T) {
+	adoptionClient := &MockAdoptionServiceClient{
+		CancelAdoptionApplicationFunc: func(ctx context.Context, req *pbAdoption.CancelAdoptionApplicationRequest) (*pbAdoption.AdoptionApplicationResponse, error) {
+			md, _ := metadata.FromOutgoingContext(ctx)
+			if callers := md.Get("x-user-id"); len(callers) == 0 || callers[0] != req.GetUserId() {
+				t.Errorf("x-user-id metadata = %v, want %q", md.Get("x-user-id"), req.GetUserId())
+			}
+			if req.GetUserId() != "applicant1" {
+				return nil, status.Error(codes.PermissionDenied, "only the applicant can cancel their applications")
+			}
+			if req.GetApplicationId() == "approved" {
+				return nil, status.Error(codes.FailedPrecondition, "application has already been approved or rejected")
+			}
+			return &pbAdoption.AdoptionApplicationResponse{Application: &pbAdoption.AdoptionApplication{Id: req.GetApplicationId(), Status: pbAdoption.ApplicationStatus_CANCELLED_BY_USER}}, nil
+		},
+	}
+	r := newTestRouter(&MockUserServiceClient{}, &MockPetServiceClient{}, adoptionClient, middleware.NewMaintenance(middleware.MaintenanceOff, ""), "")
+
+	cancel := func(applicationID, userID string) int {
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/adoptions/"+applicationID, nil)
+		if userID != "" {
+			req.Header.Set("Authorization", "Bearer "+signTestToken(t, userID))
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	for _, tc := range []struct {
+		applicationID, userID string
+		want                  int
+	}{
+		{"app1", "applicant1", http.StatusOK},
+		{"app1", "", http.StatusUnauthorized},
+		{"app1", "someone-else", http.StatusForbidden},
+		{"approved", "applicant1", http.StatusConflict},
+	} {
+		if code := cancel(tc.applicationID, tc.userID); code != tc.want {
+			t.Errorf("CancelAdoptionApplication(%s) as %q status = %d, want %d", tc.applicationID, tc.userID, code, tc.want)
+		}
+	}
+}
+
+func TestCompositeHandler_ListPetAdoptionApplications_ListerOrAdminOnly(t *testing.T) {
+	mockPetClient := &MockPetServiceClient{
+		GetPetFunc: func(ctx context.Context, req *pbPet.GetPetRequest) (*pbPet.PetResponse, error) {
+			if req.GetPetId() != "pet1" {
+				return nil, status.Error(codes.NotFound, "Pet not found")
+			}
+			return &pbPet.PetResponse{Pet: &pbPet.Pet{Id: "pet1", ListedByUserId: "lister"}}, nil
+		},
+	}
+	var got *pbAdoption.ListPetAdoptionApplicationsRequest
+	mockAdoptionClient := &MockAdoptionServiceClient{
+		ListPetAdoptionApplicationsFunc: func(ctx context.Context, req *pbAdoption.ListPetAdoptionApplicationsRequest) (*pbAdoption.ListAdoptionApplicationsResponse, error) {
+			got = req
+			return &pbAdoption.ListAdoptionApplicationsResponse{
+				Applications: []*pbAdoption.AdoptionApplication{{Id: "app1", PetId: req.GetPetId()}},
+				TotalCount:   1,
+				Page:         req.GetPage(),
+				Limit:        req.GetLimit(),
+			}, nil
+		},
+	}
+	mockUserClient := &MockUserServiceClient{}
+	maintenance := middleware.NewMaintenance(middleware.MaintenanceOff, "")
+	verifier := middleware.NewHMACVerifier([]byte(testJWTSecret))
+	roles := middleware.NewRoles([]string{"admin-1"})
+	r := router.New(
+		handler.NewUserHandler(mockUserClient),
+		handler.NewPetHandler(mockPetClient),
+		handler.NewAdoptionHandler(mockAdoptionClient),
+		handler.NewCompositeHandler(mockUserClient, mockPetClient, mockAdoptionClient),
+		handler.NewAdminHandler(maintenance, nil),
+		handler.NewHealthHandler(mockUserClient, mockPetClient, mockAdoptionClient),
+		maintenance,
+		middleware.RequireAdmin("", verifier, roles),
+		middleware.RequireAuthWithRoles(verifier, roles),
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	list := func(path, callerID string) int {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer "+signTestToken(t, callerID))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	for _, tc := range []struct {
+		path, callerID string
+		want           int
+	}{
+		{"/api/v1/pets/pet1/adoptions", "lister", http.StatusOK},
+		{"/api/v1/pets/pet1/adoptions", "admin-1", http.StatusOK},
+		{"/api/v1/pets/pet1/adoptions", "stranger", http.StatusForbidden},
+		{"/api/v1/pets/missing/adoptions", "lister", http.StatusNotFound},
+		{"/api/v1/pets/pet1/adoptions?status_filter=BOGUS", "lister", http.StatusBadRequest},
+	} {
+		if code := list(tc.path, tc.callerID); code != tc.want {
+			t.Errorf("GET %s as %s status = %d, want %d", tc.path, tc.callerID, code, tc.want)
+		}
+	}
+
+	got = nil
+	if code := list("/api/v1/pets/pet1/adoptions?page=2&limit=5&status_filter=PENDING_REVIEW", "lister"); code != http.StatusOK {
+		t.Fatalf("filtered listing status = %d, want %d", code, http.StatusOK)
+	}
+	if got.GetPetId() != "pet1" || got.GetPage() != 2 || got.GetLimit() != 5 || got.GetStatusFilter() != pbAdoption.ApplicationStatus_PENDING_REVIEW {
+		t.Errorf("forwarded request = %v, want pet1 page 2 limit 5 PENDING_REVIEW", got)
+	}
+
+	if w := performRequest(r, http.MethodGet, "/api/v1/pets/pet1/adoptions"); w.Code != http.StatusUnauthorized {
+		t.Errorf("listing without token status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
@@ -13,8 +13,13 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/zhandarbeks/petstore-final-project/api-gateway/internal/client"
 	"github.com/zhandarbeks/petstore-final-project/api-gateway/internal/config"
+	"github.com/zhandarbeks/petstore-final-project/api-gateway/internal/fanout"
 	"github.com/zhandarbeks/petstore-final-project/api-gateway/internal/handler"
+	"github.com/zhandarbeks/petstore-final-project/api-gateway/internal/middleware"
+	"github.com/zhandarbeks/petstore-final-project/api-gateway/internal/oauth"
 	"github.com/zhandarbeks/petstore-final-project/api-gateway/internal/router"
+	"github.com/zhandarbeks/petstore-final-project/api-gateway/internal/server"
+	"github.com/zhandarbeks/petstore-final-project/internal/settings"
 )
 
 func main() {
@@ -25,11 +30,14 @@ func main() {
 	}
 
 	log.Println("API Gateway | Configuration loaded.")
+	log.Printf("API Gateway | Effective settings: %s", settings.Summary(cfg.Settings()))
 	log.Printf("API Gateway | Server Port: %s", cfg.ServerPort)
 	log.Printf("API Gateway | User Service URL: %s", cfg.UserServiceGRPCURL)
 	log.Printf("API Gateway | Pet Service URL: %s", cfg.PetServiceGRPCURL)
 	log.Printf("API Gateway | Adoption Service URL: %s", cfg.AdoptionServiceGRPCURL)
+	log.Printf("API Gateway | Notification Service URL: %s", cfg.NotificationServiceHTTPURL)
 	log.Printf("API Gateway | Gin Mode: %s", cfg.GinMode)
+	log.Printf("API Gateway | Maintenance Mode: %s", cfg.MaintenanceMode)
 
 	// Set Gin mode
 	gin.SetMode(cfg.GinMode)
@@ -41,9 +49,12 @@ func main() {
 	initTimeout := 10 * time.Second // Timeout for client initializations
 
 	// 2. Initialize gRPC Clients
+	dialCfg := client.DialConfig{LoadBalancingPolicy: cfg.GRPCLoadBalancingPolicy, AuthToken: cfg.GatewayAuthToken}
+	log.Printf("API Gateway | gRPC client load balancing policy: %s", dialCfg.LoadBalancingPolicy)
+	log.Printf("API Gateway | gRPC compression: user=%s pet=%s adoption=%s", cfg.UserServiceGRPCCompression, cfg.PetServiceGRPCCompression, cfg.AdoptionServiceGRPCCompression)
 	userClientInitCtx, userClientCancel := context.WithTimeout(mainCtx, initTimeout)
 	defer userClientCancel()
-	userServiceClient, err := client.NewUserServiceGRPCClient(userClientInitCtx, cfg.UserServiceGRPCURL)
+	userServiceClient, err := client.NewUserServiceGRPCClient(userClientInitCtx, cfg.UserServiceGRPCURL, dialCfg.WithCompression(cfg.UserServiceGRPCCompression))
 	if err != nil {
 		log.Fatalf("API Gateway | FATAL: Failed to initialize User Service gRPC client: %v", err)
 	}
@@ -57,7 +68,7 @@ func main() {
 
 	petClientInitCtx, petClientCancel := context.WithTimeout(mainCtx, initTimeout)
 	defer petClientCancel()
-	petServiceClient, err := client.NewPetServiceGRPCClient(petClientInitCtx, cfg.PetServiceGRPCURL)
+	petServiceClient, err := client.NewPetServiceGRPCClient(petClientInitCtx, cfg.PetServiceGRPCURL, dialCfg.WithCompression(cfg.PetServiceGRPCCompression))
 	if err != nil {
 		log.Fatalf("API Gateway | FATAL: Failed to initialize Pet Service gRPC client: %v", err)
 	}
@@ -71,7 +82,7 @@ func main() {
 
 	adoptionClientInitCtx, adoptionClientCancel := context.WithTimeout(mainCtx, initTimeout)
 	defer adoptionClientCancel()
-	adoptionServiceClient, err := client.NewAdoptionServiceGRPCClient(adoptionClientInitCtx, cfg.AdoptionServiceGRPCURL)
+	adoptionServiceClient, err := client.NewAdoptionServiceGRPCClient(adoptionClientInitCtx, cfg.AdoptionServiceGRPCURL, dialCfg.WithCompression(cfg.AdoptionServiceGRPCCompression))
 	if err != nil {
 		log.Fatalf("API Gateway | FATAL: Failed to initialize Adoption Service gRPC client: %v", err)
 	}
@@ -83,24 +94,99 @@ func main() {
 		}
 	}()
 
+	// The Notification Service is only reached over HTTP, for admin email previews
+	notificationServiceClient, err := client.NewNotificationServiceHTTPClient(cfg.NotificationServiceHTTPURL, cfg.AdminAPIToken)
+	if err != nil {
+		log.Fatalf("API Gateway | FATAL: Failed to initialize Notification Service client: %v", err)
+	}
+	log.Println("API Gateway | Notification Service HTTP client initialized.")
+
 	// 3. Initialize HTTP Handlers (injecting gRPC clients)
 	userHandler := handler.NewUserHandler(userServiceClient)
 	petHandler := handler.NewPetHandler(petServiceClient)
+	browsePresets, err := handler.ParseBrowsePresets(cfg.BrowsePresets)
+	if err != nil {
+		log.Fatalf("API Gateway | FATAL: Invalid BROWSE_PRESETS: %v", err)
+	}
+	if err := petHandler.SetBrowsePresets(browsePresets, cfg.BrowseDefaultPreset); err != nil {
+		log.Fatalf("API Gateway | FATAL: Invalid BROWSE_DEFAULT_PRESET: %v", err)
+	}
+	// Social login is not wired up yet; validating the allowlist now catches bad entries before it is.
+	redirectAllowlist, err := oauth.NewRedirectAllowlist(cfg.OAuthRedirectAllowlist)
+	if err != nil {
+		log.Fatalf("API Gateway | FATAL: Invalid OAUTH_REDIRECT_ALLOWLIST: %v", err)
+	}
+	log.Printf("API Gateway | %d OAuth redirect URI(s) allowed.", redirectAllowlist.Len())
 	adoptionHandler := handler.NewAdoptionHandler(adoptionServiceClient)
+	compositeHandler := handler.NewCompositeHandler(userServiceClient, petServiceClient, adoptionServiceClient)
+	compositeHandler.SetFanoutOptions(fanout.Options{Timeout: cfg.CompositeTimeout, MaxConcurrency: cfg.CompositeConcurrency})
+	maintenanceMode, _ := middleware.ParseMaintenanceMode(cfg.MaintenanceMode) // Already validated by config.Load
+	maintenance := middleware.NewMaintenance(maintenanceMode, cfg.MaintenanceMessage)
+	adminHandler := handler.NewAdminHandler(maintenance, notificationServiceClient)
+	healthHandler := handler.NewHealthHandler(userServiceClient, petServiceClient, adoptionServiceClient)
 	log.Println("API Gateway | HTTP handlers initialized.")
 
+	tokenVerifier := middleware.NewHMACVerifier([]byte(cfg.JWTSecretKey))
+	if cfg.JWTSigningAlgorithm == "RS256" && cfg.JWTJWKSURL != "" {
+		tokenVerifier = middleware.NewJWKSVerifier(middleware.NewJWKSCache(cfg.JWTJWKSURL, cfg.JWTJWKSCacheTTL, &http.Client{Timeout: 5 * time.Second}))
+		log.Printf("API Gateway | Fetching token signing keys from %s", cfg.JWTJWKSURL)
+	} else if cfg.JWTSigningAlgorithm == "RS256" {
+		tokenVerifier, err = middleware.LoadRSAVerifier(cfg.JWTPublicKeyFiles...)
+		if err != nil {
+			log.Fatalf("API Gateway | FATAL: Failed to load JWT_PUBLIC_KEY_FILE: %v", err)
+		}
+	}
+	tokenVerifier = tokenVerifier.WithLeeway(cfg.JWTLeeway)
+	log.Printf("API Gateway | Validating %s access tokens with %s leeway.", cfg.JWTSigningAlgorithm, cfg.JWTLeeway)
+	if cfg.TokenBlocklistRedisAddr != "" {
+		blocklistCtx, blocklistCancel := context.WithTimeout(mainCtx, initTimeout)
+		// The user-service writes the blocklist under the "revoked:" prefix.
+		revokedTokens, err := middleware.NewRedisRevokedTokens(blocklistCtx, cfg.TokenBlocklistRedisAddr, cfg.TokenBlocklistRedisPassword, cfg.TokenBlocklistRedisDB, "revoked:")
+		blocklistCancel()
+		if err != nil {
+			log.Fatalf("API Gateway | FATAL: Failed to connect to the token blocklist Redis: %v", err)
+		}
+		defer revokedTokens.Close()
+		tokenVerifier = tokenVerifier.WithRevokedTokens(revokedTokens).WithRevocationFailOpen(cfg.TokenBlocklistFailOpen)
+		log.Println("API Gateway | Rejecting access tokens revoked by logout.")
+		if cfg.TokenBlocklistFailOpen {
+			log.Println("API Gateway | Warning: Accepting access tokens while the token blocklist is unreachable.")
+		}
+	}
+	roles := middleware.NewRoles(cfg.AdminUserIDs)
+	if len(cfg.AdminUserIDs) > 0 {
+		log.Printf("API Gateway | Treating %d configured user(s) as admins.", len(cfg.AdminUserIDs))
+	}
+
 	// 4. Initialize Gin Router (injecting handlers)
 	// Since authMiddleware is not implemented yet, we pass nil or don't include it in New's signature.
 	// For now, assuming router.New doesn't strictly require authMiddleware if it's not used.
 	// If router.New expects it, we'd pass a dummy or nil.
 	// Based on the router.go in Canvas (ID: api_gateway_router_go), it doesn't require it.
-	r := router.New(userHandler, petHandler, adoptionHandler)
+	var badRequestLogger gin.HandlerFunc
+	if cfg.DebugLogBadRequestBodies {
+		badRequestLogger = middleware.LogBadRequestBodies(cfg.DebugBodyLogMaxBytes)
+		log.Printf("API Gateway | Logging bodies of 400 responses, up to %d bytes.", cfg.DebugBodyLogMaxBytes)
+	}
+	accessLogLevel, _ := middleware.ParseAccessLogLevel(cfg.AccessLogLevel) // Already validated by config.Load
+	var downstreamHealth *middleware.DownstreamHealth
+	if cfg.DownstreamProbeInterval > 0 {
+		downstreamHealth = middleware.NewDownstreamHealth(map[string]middleware.HealthChecker{
+			"user-service":     userServiceClient,
+			"pet-service":      petServiceClient,
+			"adoption-service": adoptionServiceClient,
+		})
+		go downstreamHealth.Run(mainCtx, cfg.DownstreamProbeInterval)
+		log.Printf("API Gateway | Probing downstream health every %s; requests to a NOT_SERVING service fail fast.", cfg.DownstreamProbeInterval)
+	}
+	r := router.New(userHandler, petHandler, adoptionHandler, compositeHandler, adminHandler, healthHandler, maintenance, middleware.RequireAdmin(cfg.AdminAPIToken, tokenVerifier, roles), middleware.RequireAuthWithRoles(tokenVerifier, roles), badRequestLogger, middleware.AccessLog(accessLogLevel), downstreamHealth, middleware.NewConcurrencyLimiter(cfg.CompositeMaxInFlight))
 	log.Println("API Gateway | Gin router initialized.")
 
 	// 5. Start HTTP Server
+	requestTracker := middleware.NewRequestTracker()
 	srv := &http.Server{
 		Addr:    cfg.ServerPort,
-		Handler: r,
+		Handler: requestTracker.Handler(r),
 	}
 
 	// Goroutine for graceful shutdown
@@ -117,11 +203,8 @@ func main() {
 	sig := <-quit
 	log.Printf("API Gateway | Received signal: %v. Shutting down HTTP server...", sig)
 
-	// Create a context with timeout for the shutdown
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second) // 10-second timeout for shutdown
-	defer shutdownCancel()
-
-	if err := srv.Shutdown(shutdownCtx); err != nil {
+	// Let active requests finish; connections still open after the timeout are closed.
+	if _, err := server.Shutdown(srv, requestTracker, cfg.ShutdownTimeout); err != nil {
 		log.Fatalf("API Gateway | Server forced to shutdown: %v", err)
 	}
 
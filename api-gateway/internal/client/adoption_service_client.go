@@ -4,11 +4,10 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"time"
 
 	pbAdoption "github.com/zhandarbeks/petstore-final-project/genprotos/adoption" // Adjust import path
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
 )
 
 // AdoptionServiceClient defines the interface for the Adoption Service client.
@@ -17,6 +16,15 @@ type AdoptionServiceClient interface {
 	GetAdoptionApplication(ctx context.Context, req *pbAdoption.GetAdoptionApplicationRequest) (*pbAdoption.AdoptionApplicationResponse, error)
 	UpdateAdoptionApplicationStatus(ctx context.Context, req *pbAdoption.UpdateAdoptionApplicationStatusRequest) (*pbAdoption.AdoptionApplicationResponse, error)
 	ListUserAdoptionApplications(ctx context.Context, req *pbAdoption.ListUserAdoptionApplicationsRequest) (*pbAdoption.ListAdoptionApplicationsResponse, error)
+	ListPetAdoptionApplications(ctx context.Context, req *pbAdoption.ListPetAdoptionApplicationsRequest) (*pbAdoption.ListAdoptionApplicationsResponse, error)
+	GetPetApplicationStats(ctx context.Context, req *pbAdoption.GetPetApplicationStatsRequest) (*pbAdoption.PetApplicationStatsResponse, error)
+	CountPetApplications(ctx context.Context, req *pbAdoption.CountPetApplicationsRequest) (*pbAdoption.CountPetApplicationsResponse, error)
+	ReopenApplication(ctx context.Context, req *pbAdoption.ReopenApplicationRequest) (*pbAdoption.AdoptionApplicationResponse, error)
+	AddApplicationAttachment(ctx context.Context, req *pbAdoption.AddApplicationAttachmentRequest) (*pbAdoption.AdoptionApplicationResponse, error)
+	RemoveApplicationAttachment(ctx context.Context, req *pbAdoption.RemoveApplicationAttachmentRequest) (*pbAdoption.AdoptionApplicationResponse, error)
+	CancelUserApplications(ctx context.Context, req *pbAdoption.CancelUserApplicationsRequest) (*pbAdoption.CancelUserApplicationsResponse, error)
+	CancelAdoptionApplication(ctx context.Context, req *pbAdoption.CancelAdoptionApplicationRequest) (*pbAdoption.AdoptionApplicationResponse, error)
+	HealthCheck(ctx context.Context) (grpc_health_v1.HealthCheckResponse_ServingStatus, error)
 	Close() error
 }
 
@@ -26,19 +34,13 @@ type adoptionServiceGRPCClient struct {
 }
 
 // NewAdoptionServiceGRPCClient creates a new gRPC client for the Adoption Service.
-func NewAdoptionServiceGRPCClient(ctx context.Context, targetURL string) (AdoptionServiceClient, error) {
+func NewAdoptionServiceGRPCClient(ctx context.Context, targetURL string, dialCfg DialConfig) (AdoptionServiceClient, error) {
 	if targetURL == "" {
 		return nil, fmt.Errorf("adoption service target URL cannot be empty for API Gateway client")
 	}
 	log.Printf("API Gateway | Attempting to connect to Adoption Service gRPC at %s", targetURL)
 
-	conn, err := grpc.DialContext(
-		ctx,
-		targetURL,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithBlock(),
-		grpc.WithTimeout(5*time.Second), // Connection timeout
-	)
+	conn, err := grpc.DialContext(ctx, DialTarget(targetURL, dialCfg), DialOptions(dialCfg)...)
 	if err != nil {
 		log.Printf("API Gateway | Failed to connect to Adoption Service gRPC at %s: %v", targetURL, err)
 		return nil, fmt.Errorf("did not connect to adoption service: %w", err)
@@ -70,6 +72,50 @@ func (c *adoptionServiceGRPCClient) ListUserAdoptionApplications(ctx context.Con
 	return c.client.ListUserAdoptionApplications(ctx, req)
 }
 
+func (c *adoptionServiceGRPCClient) GetPetApplicationStats(ctx context.Context, req *pbAdoption.GetPetApplicationStatsRequest) (*pbAdoption.PetApplicationStatsResponse, error) {
+	log.Printf("API Gateway | Calling Adoption Service GetPetApplicationStats for PetID: %s", req.GetPetId())
+	return c.client.GetPetApplicationStats(ctx, req)
+}
+
+func (c *adoptionServiceGRPCClient) CountPetApplications(ctx context.Context, req *pbAdoption.CountPetApplicationsRequest) (*pbAdoption.CountPetApplicationsResponse, error) {
+	log.Printf("API Gateway | Calling Adoption Service CountPetApplications for %d pets", len(req.GetPetIds()))
+	return c.client.CountPetApplications(ctx, req)
+}
+
+func (c *adoptionServiceGRPCClient) ReopenApplication(ctx context.Context, req *pbAdoption.ReopenApplicationRequest) (*pbAdoption.AdoptionApplicationResponse, error) {
+	log.Printf("API Gateway | Calling Adoption Service ReopenApplication for ID: %s", req.GetApplicationId())
+	return c.client.ReopenApplication(ctx, req)
+}
+
+func (c *adoptionServiceGRPCClient) AddApplicationAttachment(ctx context.Context, req *pbAdoption.AddApplicationAttachmentRequest) (*pbAdoption.AdoptionApplicationResponse, error) {
+	log.Printf("API Gateway | Calling Adoption Service AddApplicationAttachment for ID: %s", req.GetApplicationId())
+	return c.client.AddApplicationAttachment(ctx, req)
+}
+
+func (c *adoptionServiceGRPCClient) RemoveApplicationAttachment(ctx context.Context, req *pbAdoption.RemoveApplicationAttachmentRequest) (*pbAdoption.AdoptionApplicationResponse, error) {
+	log.Printf("API Gateway | Calling Adoption Service RemoveApplicationAttachment for ID: %s", req.GetApplicationId())
+	return c.client.RemoveApplicationAttachment(ctx, req)
+}
+
+func (c *adoptionServiceGRPCClient) CancelUserApplications(ctx context.Context, req *pbAdoption.CancelUserApplicationsRequest) (*pbAdoption.CancelUserApplicationsResponse, error) {
+	log.Printf("API Gateway | Calling Adoption Service CancelUserApplications for UserID: %s", req.GetUserId())
+	return c.client.CancelUserApplications(ctx, req)
+}
+
+func (c *adoptionServiceGRPCClient) CancelAdoptionApplication(ctx context.Context, req *pbAdoption.CancelAdoptionApplicationRequest) (*pbAdoption.AdoptionApplicationResponse, error) {
+	log.Printf("API Gateway | Calling Adoption Service CancelAdoptionApplication for ID: %s", req.GetApplicationId())
+	return c.client.CancelAdoptionApplication(ctx, req)
+}
+
+func (c *adoptionServiceGRPCClient) ListPetAdoptionApplications(ctx context.Context, req *pbAdoption.ListPetAdoptionApplicationsRequest) (*pbAdoption.ListAdoptionApplicationsResponse, error) {
+	log.Printf("API Gateway | Calling Adoption Service ListPetAdoptionApplications for PetID: %s", req.GetPetId())
+	return c.client.ListPetAdoptionApplications(ctx, req)
+}
+
+func (c *adoptionServiceGRPCClient) HealthCheck(ctx context.Context) (grpc_health_v1.HealthCheckResponse_ServingStatus, error) {
+	return checkHealth(ctx, c.conn)
+}
+
 func (c *adoptionServiceGRPCClient) Close() error {
 	if c.conn != nil {
 		log.Println("API Gateway | Closing Adoption Service gRPC client connection...")
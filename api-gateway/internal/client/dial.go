@@ -0,0 +1,92 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zhandarbeks/petstore-final-project/internal/gatewayauth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding/gzip" // Registers the gzip compressor
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Supported client-side load balancing policies.
+const (
+	LoadBalancingPickFirst  = "pick_first"  // gRPC default: one connection to the first resolved address
+	LoadBalancingRoundRobin = "round_robin" // Spread calls across every resolved replica
+)
+
+// Supported message compression settings.
+const (
+	CompressionNone = "none"
+	CompressionGzip = "gzip" // Compress requests; services answer with the same compressor
+)
+
+// DialConfig holds the connection settings shared by all downstream gRPC clients.
+type DialConfig struct {
+	LoadBalancingPolicy string // LoadBalancingPickFirst or LoadBalancingRoundRobin
+	Compression         string // CompressionNone or CompressionGzip; empty means none
+	AuthToken           string // Shared token attached to every call (see gatewayauth); empty attaches none
+}
+
+// WithCompression returns a copy of the config using the given compression, so each
+// client can be dialed with its own setting.
+func (cfg DialConfig) WithCompression(compression string) DialConfig {
+	cfg.Compression = compression
+	return cfg
+}
+
+// ServiceConfigJSON returns the gRPC service config selecting the given load balancing policy.
+func ServiceConfigJSON(policy string) string {
+	if policy == "" {
+		policy = LoadBalancingPickFirst
+	}
+	return fmt.Sprintf(`{"loadBalancingConfig": [{"%s":{}}]}`, policy)
+}
+
+// DialTarget returns the target to dial for the given URL and policy.
+// Round robin only helps when the resolver returns several addresses, so plain "host:port"
+// targets are switched to the DNS resolver, which returns every replica behind the name.
+func DialTarget(targetURL string, cfg DialConfig) string {
+	if cfg.LoadBalancingPolicy == LoadBalancingRoundRobin && !strings.Contains(targetURL, "://") {
+		return "dns:///" + targetURL
+	}
+	return targetURL
+}
+
+// CallOptions returns the default call options for every call on the connection.
+func CallOptions(cfg DialConfig) []grpc.CallOption {
+	if cfg.Compression == CompressionGzip {
+		return []grpc.CallOption{grpc.UseCompressor(gzip.Name)}
+	}
+	return nil
+}
+
+// DialOptions builds the dial options used by every downstream client.
+func DialOptions(cfg DialConfig) []grpc.DialOption {
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultServiceConfig(ServiceConfigJSON(cfg.LoadBalancingPolicy)),
+		grpc.WithBlock(),
+		grpc.WithTimeout(5 * time.Second), // Connection timeout
+	}
+	if callOpts := CallOptions(cfg); len(callOpts) > 0 {
+		opts = append(opts, grpc.WithDefaultCallOptions(callOpts...))
+	}
+	if cfg.AuthToken != "" {
+		opts = append(opts, grpc.WithChainUnaryInterceptor(gatewayauth.UnaryClientInterceptor(cfg.AuthToken)))
+	}
+	return opts
+}
+
+// checkHealth asks the standard gRPC health service on conn for the server's overall status.
+func checkHealth(ctx context.Context, conn *grpc.ClientConn) (grpc_health_v1.HealthCheckResponse_ServingStatus, error) {
+	resp, err := grpc_health_v1.NewHealthClient(conn).Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+	if err != nil {
+		return grpc_health_v1.HealthCheckResponse_UNKNOWN, err
+	}
+	return resp.GetStatus(), nil
+}
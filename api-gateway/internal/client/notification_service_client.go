@@ -0,0 +1,84 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// EmailPreview is an email rendered by the Notification Service without being sent.
+type EmailPreview struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"` // HTML
+}
+
+// NotificationServiceError is returned when the Notification Service answers with a non-2xx status.
+type NotificationServiceError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *NotificationServiceError) Error() string {
+	return fmt.Sprintf("notification service returned %d: %s", e.StatusCode, e.Message)
+}
+
+// NotificationServiceClient defines the interface for the Notification Service client.
+// The Notification Service has no gRPC API, so this talks to its HTTP server.
+type NotificationServiceClient interface {
+	PreviewEmail(ctx context.Context, emailType, userID, petID, status string) (*EmailPreview, error)
+}
+
+type notificationServiceHTTPClient struct {
+	baseURL    string
+	adminToken string
+	httpClient *http.Client
+}
+
+// NewNotificationServiceHTTPClient creates a client for the Notification Service HTTP API.
+// adminToken is forwarded in X-Admin-Token, as the preview endpoint requires it.
+func NewNotificationServiceHTTPClient(baseURL, adminToken string) (NotificationServiceClient, error) {
+	if baseURL == "" {
+		return nil, fmt.Errorf("notification service base URL cannot be empty for API Gateway client")
+	}
+	return &notificationServiceHTTPClient{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		adminToken: adminToken,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+func (c *notificationServiceHTTPClient) PreviewEmail(ctx context.Context, emailType, userID, petID, status string) (*EmailPreview, error) {
+	log.Printf("API Gateway | Calling Notification Service email preview. Type: %s, UserID: %s, PetID: %s", emailType, userID, petID)
+	query := url.Values{"type": {emailType}, "userId": {userID}, "petId": {petID}}
+	if status != "" {
+		query.Set("status", status)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/admin/email-previews?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build email preview request: %w", err)
+	}
+	req.Header.Set("X-Admin-Token", c.adminToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call notification service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+		return nil, &NotificationServiceError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(message))}
+	}
+	var preview EmailPreview
+	if err := json.NewDecoder(resp.Body).Decode(&preview); err != nil {
+		return nil, fmt.Errorf("failed to decode email preview: %w", err)
+	}
+	return &preview, nil
+}
@@ -4,11 +4,10 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"time"
 
 	pbPet "github.com/zhandarbeks/petstore-final-project/genprotos/pet" // Adjust import path
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
 )
 
 // PetServiceClient defines the interface for the Pet Service client.
@@ -19,6 +18,17 @@ type PetServiceClient interface {
 	DeletePet(ctx context.Context, req *pbPet.DeletePetRequest) (*pbPet.EmptyResponse, error)
 	ListPets(ctx context.Context, req *pbPet.ListPetsRequest) (*pbPet.ListPetsResponse, error)
 	UpdatePetAdoptionStatus(ctx context.Context, req *pbPet.UpdatePetAdoptionStatusRequest) (*pbPet.PetResponse, error)
+	GetImageUploadURL(ctx context.Context, req *pbPet.GetImageUploadURLRequest) (*pbPet.ImageUploadTarget, error)
+	AddImageURLs(ctx context.Context, req *pbPet.AddImageURLsRequest) (*pbPet.PetResponse, error)
+	ListRecentlyAdopted(ctx context.Context, req *pbPet.ListRecentlyAdoptedRequest) (*pbPet.ListRecentlyAdoptedResponse, error)
+	GetPetFacets(ctx context.Context, req *pbPet.GetPetFacetsRequest) (*pbPet.PetFacetsResponse, error)
+	SuggestBreeds(ctx context.Context, req *pbPet.SuggestBreedsRequest) (*pbPet.SuggestBreedsResponse, error)
+	AddPetTags(ctx context.Context, req *pbPet.PetTagsRequest) (*pbPet.PetResponse, error)
+	RemovePetTags(ctx context.Context, req *pbPet.PetTagsRequest) (*pbPet.PetResponse, error)
+	AdminSetPetStatus(ctx context.Context, req *pbPet.AdminSetPetStatusRequest) (*pbPet.PetResponse, error)
+	TransferPetListing(ctx context.Context, req *pbPet.TransferPetListingRequest) (*pbPet.PetResponse, error)
+	RestorePet(ctx context.Context, req *pbPet.RestorePetRequest) (*pbPet.PetResponse, error)
+	HealthCheck(ctx context.Context) (grpc_health_v1.HealthCheckResponse_ServingStatus, error)
 	Close() error
 }
 
@@ -28,19 +38,13 @@ type petServiceGRPCClient struct {
 }
 
 // NewPetServiceGRPCClient creates a new gRPC client for the Pet Service.
-func NewPetServiceGRPCClient(ctx context.Context, targetURL string) (PetServiceClient, error) {
+func NewPetServiceGRPCClient(ctx context.Context, targetURL string, dialCfg DialConfig) (PetServiceClient, error) {
 	if targetURL == "" {
 		return nil, fmt.Errorf("pet service target URL cannot be empty for API Gateway client")
 	}
 	log.Printf("API Gateway | Attempting to connect to Pet Service gRPC at %s", targetURL)
 
-	conn, err := grpc.DialContext(
-		ctx,
-		targetURL,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithBlock(),
-		grpc.WithTimeout(5*time.Second), // Connection timeout
-	)
+	conn, err := grpc.DialContext(ctx, DialTarget(targetURL, dialCfg), DialOptions(dialCfg)...)
 	if err != nil {
 		log.Printf("API Gateway | Failed to connect to Pet Service gRPC at %s: %v", targetURL, err)
 		return nil, fmt.Errorf("did not connect to pet service: %w", err)
@@ -82,6 +86,60 @@ func (c *petServiceGRPCClient) UpdatePetAdoptionStatus(ctx context.Context, req
 	return c.client.UpdatePetAdoptionStatus(ctx, req)
 }
 
+func (c *petServiceGRPCClient) GetImageUploadURL(ctx context.Context, req *pbPet.GetImageUploadURLRequest) (*pbPet.ImageUploadTarget, error) {
+	log.Printf("API Gateway | Calling Pet Service GetImageUploadURL for pet ID: %s", req.GetPetId())
+	return c.client.GetImageUploadURL(ctx, req)
+}
+
+func (c *petServiceGRPCClient) AddImageURLs(ctx context.Context, req *pbPet.AddImageURLsRequest) (*pbPet.PetResponse, error) {
+	log.Printf("API Gateway | Calling Pet Service AddImageURLs for pet ID: %s", req.GetPetId())
+	return c.client.AddImageURLs(ctx, req)
+}
+
+func (c *petServiceGRPCClient) ListRecentlyAdopted(ctx context.Context, req *pbPet.ListRecentlyAdoptedRequest) (*pbPet.ListRecentlyAdoptedResponse, error) {
+	log.Printf("API Gateway | Calling Pet Service ListRecentlyAdopted. Limit: %d", req.GetLimit())
+	return c.client.ListRecentlyAdopted(ctx, req)
+}
+
+func (c *petServiceGRPCClient) GetPetFacets(ctx context.Context, req *pbPet.GetPetFacetsRequest) (*pbPet.PetFacetsResponse, error) {
+	log.Println("API Gateway | Calling Pet Service GetPetFacets")
+	return c.client.GetPetFacets(ctx, req)
+}
+
+func (c *petServiceGRPCClient) SuggestBreeds(ctx context.Context, req *pbPet.SuggestBreedsRequest) (*pbPet.SuggestBreedsResponse, error) {
+	log.Printf("API Gateway | Calling Pet Service SuggestBreeds. Species: %s, Prefix: %s", req.GetSpecies(), req.GetPrefix())
+	return c.client.SuggestBreeds(ctx, req)
+}
+
+func (c *petServiceGRPCClient) AddPetTags(ctx context.Context, req *pbPet.PetTagsRequest) (*pbPet.PetResponse, error) {
+	log.Printf("API Gateway | Calling Pet Service AddPetTags for ID: %s", req.GetPetId())
+	return c.client.AddPetTags(ctx, req)
+}
+
+func (c *petServiceGRPCClient) RemovePetTags(ctx context.Context, req *pbPet.PetTagsRequest) (*pbPet.PetResponse, error) {
+	log.Printf("API Gateway | Calling Pet Service RemovePetTags for ID: %s", req.GetPetId())
+	return c.client.RemovePetTags(ctx, req)
+}
+
+func (c *petServiceGRPCClient) AdminSetPetStatus(ctx context.Context, req *pbPet.AdminSetPetStatusRequest) (*pbPet.PetResponse, error) {
+	log.Printf("API Gateway | Calling Pet Service AdminSetPetStatus for ID: %s, NewStatus: %s", req.GetPetId(), req.GetNewStatus().String())
+	return c.client.AdminSetPetStatus(ctx, req)
+}
+
+func (c *petServiceGRPCClient) TransferPetListing(ctx context.Context, req *pbPet.TransferPetListingRequest) (*pbPet.PetResponse, error) {
+	log.Printf("API Gateway | Calling Pet Service TransferPetListing for ID: %s, NewOwner: %s", req.GetPetId(), req.GetNewOwnerUserId())
+	return c.client.TransferPetListing(ctx, req)
+}
+
+func (c *petServiceGRPCClient) RestorePet(ctx context.Context, req *pbPet.RestorePetRequest) (*pbPet.PetResponse, error) {
+	log.Printf("API Gateway | Calling Pet Service RestorePet for ID: %s", req.GetPetId())
+	return c.client.RestorePet(ctx, req)
+}
+
+func (c *petServiceGRPCClient) HealthCheck(ctx context.Context) (grpc_health_v1.HealthCheckResponse_ServingStatus, error) {
+	return checkHealth(ctx, c.conn)
+}
+
 func (c *petServiceGRPCClient) Close() error {
 	if c.conn != nil {
 		log.Println("API Gateway | Closing Pet Service gRPC client connection...")
@@ -4,20 +4,26 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"time"
 
 	pbUser "github.com/zhandarbeks/petstore-final-project/genprotos/user" // Adjust import path
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
 )
 
 // UserServiceClient defines the interface for the User Service client.
 type UserServiceClient interface {
 	RegisterUser(ctx context.Context, req *pbUser.RegisterUserRequest) (*pbUser.UserResponse, error)
 	LoginUser(ctx context.Context, req *pbUser.LoginUserRequest) (*pbUser.LoginUserResponse, error)
+	RefreshToken(ctx context.Context, req *pbUser.RefreshTokenRequest) (*pbUser.RefreshTokenResponse, error)
+	LogoutUser(ctx context.Context, req *pbUser.LogoutRequest) (*pbUser.EmptyResponse, error)
 	GetUser(ctx context.Context, req *pbUser.GetUserRequest) (*pbUser.UserResponse, error)
 	UpdateUserProfile(ctx context.Context, req *pbUser.UpdateUserProfileRequest) (*pbUser.UserResponse, error)
 	DeleteUser(ctx context.Context, req *pbUser.DeleteUserRequest) (*pbUser.EmptyResponse, error)
+	AddFavoritePet(ctx context.Context, req *pbUser.FavoritePetRequest) (*pbUser.FavoritePetsResponse, error)
+	RemoveFavoritePet(ctx context.Context, req *pbUser.FavoritePetRequest) (*pbUser.FavoritePetsResponse, error)
+	ListFavoritePets(ctx context.Context, req *pbUser.ListFavoritePetsRequest) (*pbUser.FavoritePetsResponse, error)
+	AnonymizeUser(ctx context.Context, req *pbUser.AnonymizeUserRequest) (*pbUser.UserResponse, error)
+	HealthCheck(ctx context.Context) (grpc_health_v1.HealthCheckResponse_ServingStatus, error)
 	Close() error
 }
 
@@ -27,19 +33,13 @@ type userServiceGRPCClient struct {
 }
 
 // NewUserServiceGRPCClient creates a new gRPC client for the User Service.
-func NewUserServiceGRPCClient(ctx context.Context, targetURL string) (UserServiceClient, error) {
+func NewUserServiceGRPCClient(ctx context.Context, targetURL string, dialCfg DialConfig) (UserServiceClient, error) {
 	if targetURL == "" {
 		return nil, fmt.Errorf("user service target URL cannot be empty for API Gateway client")
 	}
 	log.Printf("API Gateway | Attempting to connect to User Service gRPC at %s", targetURL)
 
-	conn, err := grpc.DialContext(
-		ctx,
-		targetURL,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithBlock(),
-		grpc.WithTimeout(5*time.Second), // Connection timeout
-	)
+	conn, err := grpc.DialContext(ctx, DialTarget(targetURL, dialCfg), DialOptions(dialCfg)...)
 	if err != nil {
 		log.Printf("API Gateway | Failed to connect to User Service gRPC at %s: %v", targetURL, err)
 		return nil, fmt.Errorf("did not connect to user service: %w", err)
@@ -64,6 +64,16 @@ func (c *userServiceGRPCClient) LoginUser(ctx context.Context, req *pbUser.Login
 	return c.client.LoginUser(ctx, req)
 }
 
+func (c *userServiceGRPCClient) RefreshToken(ctx context.Context, req *pbUser.RefreshTokenRequest) (*pbUser.RefreshTokenResponse, error) {
+	log.Printf("API Gateway | Calling User Service RefreshToken")
+	return c.client.RefreshToken(ctx, req)
+}
+
+func (c *userServiceGRPCClient) LogoutUser(ctx context.Context, req *pbUser.LogoutRequest) (*pbUser.EmptyResponse, error) {
+	log.Printf("API Gateway | Calling User Service LogoutUser")
+	return c.client.LogoutUser(ctx, req)
+}
+
 func (c *userServiceGRPCClient) GetUser(ctx context.Context, req *pbUser.GetUserRequest) (*pbUser.UserResponse, error) {
 	log.Printf("API Gateway | Calling User Service GetUser for ID: %s", req.GetUserId())
 	return c.client.GetUser(ctx, req)
@@ -79,6 +89,30 @@ func (c *userServiceGRPCClient) DeleteUser(ctx context.Context, req *pbUser.Dele
 	return c.client.DeleteUser(ctx, req)
 }
 
+func (c *userServiceGRPCClient) AddFavoritePet(ctx context.Context, req *pbUser.FavoritePetRequest) (*pbUser.FavoritePetsResponse, error) {
+	log.Printf("API Gateway | Calling User Service AddFavoritePet for user %s, pet %s", req.GetUserId(), req.GetPetId())
+	return c.client.AddFavoritePet(ctx, req)
+}
+
+func (c *userServiceGRPCClient) RemoveFavoritePet(ctx context.Context, req *pbUser.FavoritePetRequest) (*pbUser.FavoritePetsResponse, error) {
+	log.Printf("API Gateway | Calling User Service RemoveFavoritePet for user %s, pet %s", req.GetUserId(), req.GetPetId())
+	return c.client.RemoveFavoritePet(ctx, req)
+}
+
+func (c *userServiceGRPCClient) ListFavoritePets(ctx context.Context, req *pbUser.ListFavoritePetsRequest) (*pbUser.FavoritePetsResponse, error) {
+	log.Printf("API Gateway | Calling User Service ListFavoritePets for user %s", req.GetUserId())
+	return c.client.ListFavoritePets(ctx, req)
+}
+
+func (c *userServiceGRPCClient) AnonymizeUser(ctx context.Context, req *pbUser.AnonymizeUserRequest) (*pbUser.UserResponse, error) {
+	log.Printf("API Gateway | Calling User Service AnonymizeUser for ID: %s", req.GetUserId())
+	return c.client.AnonymizeUser(ctx, req)
+}
+
+func (c *userServiceGRPCClient) HealthCheck(ctx context.Context) (grpc_health_v1.HealthCheckResponse_ServingStatus, error) {
+	return checkHealth(ctx, c.conn)
+}
+
 func (c *userServiceGRPCClient) Close() error {
 	if c.conn != nil {
 		log.Println("API Gateway | Closing User Service gRPC client connection...")
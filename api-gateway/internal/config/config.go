@@ -3,10 +3,12 @@ package config
 import (
 	"log"
 	"os"
-	// "strconv" // Not immediately needed for this basic config
-	// "time"    // Not immediately needed for this basic config
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv" // For loading .env files (optional)
+	"github.com/zhandarbeks/petstore-final-project/internal/settings"
 )
 
 // Config holds all configuration for the api-gateway service
@@ -16,7 +18,69 @@ type Config struct {
 	PetServiceGRPCURL    string // Target URL for the Pet gRPC Service
 	AdoptionServiceGRPCURL string // Target URL for the Adoption gRPC Service
 	JWTSecretKey         string // Secret key for validating JWT tokens (if gateway handles this)
+	JWTSigningAlgorithm  string // Algorithm the user-service signs tokens with: "HS256" (shared secret) or "RS256" (key pair)
+	JWTPublicKeyFiles    []string // PEM files (comma-separated JWT_PUBLIC_KEY_FILE) with the RSA public keys accepted for RS256 tokens; list the new and previous keys during a rotation
+	JWTJWKSURL           string // JWKS endpoint of the user-service; used instead of JWTPublicKeyFiles for RS256 when set
+	JWTJWKSCacheTTL      time.Duration // How long fetched JWKS keys are used before refetching
+	JWTLeeway            time.Duration // Clock skew tolerated when checking a token's exp and nbf claims
+	TokenBlocklistRedisAddr     string // Redis holding the user-service's blocklist of logged-out tokens; empty disables the check
+	TokenBlocklistRedisPassword string // Password of that Redis (if any)
+	TokenBlocklistRedisDB       int    // Redis database of the blocklist; must match the user-service's REDIS_DB
+	TokenBlocklistFailOpen      bool   // Accept tokens when the blocklist cannot be reached instead of answering 503
 	GinMode              string // Gin's run mode (e.g., "debug", "release", "test")
+	GRPCLoadBalancingPolicy string // Client-side load balancing for downstream services ("pick_first" or "round_robin")
+	UserServiceGRPCCompression     string // Message compression for User Service calls ("none" or "gzip")
+	PetServiceGRPCCompression      string // Message compression for Pet Service calls ("none" or "gzip")
+	AdoptionServiceGRPCCompression string // Message compression for Adoption Service calls ("none" or "gzip")
+	MaintenanceMode      string // Initial maintenance mode: "off", "read_only" or "full"
+	MaintenanceMessage   string // Message returned with 503 responses during maintenance
+	AdminAPIToken        string // Token for the /admin endpoints (X-Admin-Token header); empty disables them
+	AdminUserIDs         []string // Users (comma-separated ADMIN_USER_IDS) treated as admins, e.g. to bootstrap the first admin
+	GatewayAuthToken     string   // Attached to every gRPC call so the services accept the forwarded caller identity; must match theirs
+	NotificationServiceHTTPURL string // Base URL of the Notification Service HTTP server, used for email previews
+	BrowsePresets        string // Presets for GET /pets/browse as comma-separated "name:status_filter:sort" entries
+	BrowseDefaultPreset  string // Preset applied when a browse request does not name one
+	DebugLogBadRequestBodies bool // Log the (redacted) body of every request answered with 400
+	DebugBodyLogMaxBytes     int  // Cap on the logged body size
+	AccessLogLevel           string // Least severe access log line written: "info" (all requests), "warn" (4xx/5xx), "error" (5xx) or "off"
+	ShutdownTimeout      time.Duration // How long shutdown waits for active requests before forcing connections closed
+	CompositeTimeout     time.Duration // Shared deadline for the concurrent downstream calls of composite endpoints
+	CompositeConcurrency int           // Downstream calls a composite request runs at once (0 = all)
+	CompositeMaxInFlight int           // Composite requests handled at once across all clients; more get 503 (0 = unlimited)
+	OAuthRedirectAllowlist []string    // Allowed OAuth redirect URIs (comma-separated OAUTH_REDIRECT_ALLOWLIST); an entry ending in "*" allows paths below it
+	DownstreamProbeInterval time.Duration // How often downstream health is probed; requests to a NOT_SERVING service fail fast (0 = never)
+}
+
+// Settings returns the feature flags and key tunables in effect. Secrets and connection strings are left out.
+func (c *Config) Settings() []settings.Setting {
+	return []settings.Setting{
+		{Name: "gin_mode", Value: c.GinMode},
+		{Name: "grpc_lb_policy", Value: c.GRPCLoadBalancingPolicy},
+		{Name: "grpc_compression_user_service", Value: c.UserServiceGRPCCompression},
+		{Name: "grpc_compression_pet_service", Value: c.PetServiceGRPCCompression},
+		{Name: "grpc_compression_adoption_service", Value: c.AdoptionServiceGRPCCompression},
+		{Name: "jwt_signing_algorithm", Value: c.JWTSigningAlgorithm},
+		{Name: "jwt_jwks_enabled", Value: strconv.FormatBool(c.JWTJWKSURL != "")},
+		{Name: "jwt_jwks_cache_ttl", Value: c.JWTJWKSCacheTTL.String()},
+		{Name: "jwt_leeway", Value: c.JWTLeeway.String()},
+		{Name: "token_blocklist_enabled", Value: strconv.FormatBool(c.TokenBlocklistRedisAddr != "")},
+		{Name: "token_blocklist_fail_open", Value: strconv.FormatBool(c.TokenBlocklistFailOpen)},
+		{Name: "maintenance_mode", Value: c.MaintenanceMode},
+		{Name: "admin_endpoints_enabled", Value: strconv.FormatBool(c.AdminAPIToken != "" || len(c.AdminUserIDs) > 0)},
+		{Name: "admin_user_ids", Value: strconv.Itoa(len(c.AdminUserIDs))},
+		{Name: "gateway_auth_enabled", Value: strconv.FormatBool(c.GatewayAuthToken != "")},
+		{Name: "browse_presets", Value: c.BrowsePresets},
+		{Name: "browse_default_preset", Value: c.BrowseDefaultPreset},
+		{Name: "debug_log_bad_request_bodies", Value: strconv.FormatBool(c.DebugLogBadRequestBodies)},
+		{Name: "debug_body_log_max_bytes", Value: strconv.Itoa(c.DebugBodyLogMaxBytes)},
+		{Name: "access_log_level", Value: c.AccessLogLevel},
+		{Name: "shutdown_timeout", Value: c.ShutdownTimeout.String()},
+		{Name: "composite_timeout", Value: c.CompositeTimeout.String()},
+		{Name: "composite_max_concurrency", Value: strconv.Itoa(c.CompositeConcurrency)},
+		{Name: "composite_max_in_flight", Value: strconv.Itoa(c.CompositeMaxInFlight)},
+		{Name: "oauth_redirect_allowlist", Value: strings.Join(c.OAuthRedirectAllowlist, ",")},
+		{Name: "downstream_probe_interval", Value: c.DownstreamProbeInterval.String()},
+	}
 }
 
 // Load loads configuration. It first attempts to load from a .env file (if present),
@@ -35,8 +99,137 @@ func Load() (*Config, error) {
 		PetServiceGRPCURL:    getEnv("PET_SERVICE_GRPC_URL", "localhost:50052"),     // Default for local, Docker will override
 		AdoptionServiceGRPCURL: getEnv("ADOPTION_SERVICE_GRPC_URL", "localhost:50053"), // Default for local, Docker will override
 		JWTSecretKey:         getEnv("JWT_SECRET_KEY", "your_default_strong_jwt_secret_key_for_gateway"), // Should match user-service if gateway validates
+		JWTSigningAlgorithm:  getEnv("JWT_SIGNING_ALGORITHM", "HS256"),
+		JWTJWKSURL:           getEnv("JWT_JWKS_URL", ""),
+		TokenBlocklistRedisAddr:     getEnv("TOKEN_BLOCKLIST_REDIS_ADDR", ""),
+		TokenBlocklistRedisPassword: getEnv("TOKEN_BLOCKLIST_REDIS_PASSWORD", ""),
 		GinMode:              getEnv("GIN_MODE", "debug"),                               // Default to debug mode
+		GRPCLoadBalancingPolicy: getEnv("GRPC_LB_POLICY", "round_robin"),                // Spread calls across service replicas
+		MaintenanceMode:      getEnv("MAINTENANCE_MODE", "off"),
+		MaintenanceMessage:   getEnv("MAINTENANCE_MESSAGE", "The service is temporarily unavailable due to maintenance. Please try again later."),
+		AdminAPIToken:        getEnv("ADMIN_API_TOKEN", ""),
+		GatewayAuthToken:     getEnv("GATEWAY_AUTH_TOKEN", ""),
+		NotificationServiceHTTPURL: getEnv("NOTIFICATION_SERVICE_HTTP_URL", "http://localhost:8081"), // Default for local, Docker will override
+		BrowsePresets:        getEnv("BROWSE_PRESETS", "available_newest:AVAILABLE:newest,newest::newest,raw::"),
+		BrowseDefaultPreset:  getEnv("BROWSE_DEFAULT_PRESET", "available_newest"),
+	}
+
+	for _, file := range strings.Split(getEnv("JWT_PUBLIC_KEY_FILE", ""), ",") {
+		if file = strings.TrimSpace(file); file != "" {
+			cfg.JWTPublicKeyFiles = append(cfg.JWTPublicKeyFiles, file)
+		}
+	}
+	for _, userID := range strings.Split(getEnv("ADMIN_USER_IDS", ""), ",") {
+		if userID = strings.TrimSpace(userID); userID != "" {
+			cfg.AdminUserIDs = append(cfg.AdminUserIDs, userID)
+		}
+	}
+	for _, redirect := range strings.Split(getEnv("OAUTH_REDIRECT_ALLOWLIST", ""), ",") {
+		if redirect = strings.TrimSpace(redirect); redirect != "" {
+			cfg.OAuthRedirectAllowlist = append(cfg.OAuthRedirectAllowlist, redirect)
+		}
+	}
+
+	jwksCacheTTLStr := getEnv("JWT_JWKS_CACHE_TTL", "5m")
+	jwksCacheTTL, err := time.ParseDuration(jwksCacheTTLStr)
+	if err != nil || jwksCacheTTL <= 0 {
+		log.Printf("API Gateway | Warning: Invalid JWT_JWKS_CACHE_TTL value: '%s'. Using default 5m.", jwksCacheTTLStr)
+		jwksCacheTTL = 5 * time.Minute
+	}
+	cfg.JWTJWKSCacheTTL = jwksCacheTTL
+
+	jwtLeewayStr := getEnv("JWT_LEEWAY", "30s")
+	jwtLeeway, err := time.ParseDuration(jwtLeewayStr)
+	if err != nil || jwtLeeway < 0 {
+		log.Printf("API Gateway | Warning: Invalid JWT_LEEWAY value: '%s'. Using default 30s.", jwtLeewayStr)
+		jwtLeeway = 30 * time.Second
+	}
+	cfg.JWTLeeway = jwtLeeway
+
+	blocklistDBStr := getEnv("TOKEN_BLOCKLIST_REDIS_DB", "0")
+	blocklistDB, err := strconv.Atoi(blocklistDBStr)
+	if err != nil || blocklistDB < 0 {
+		log.Printf("API Gateway | Warning: Invalid TOKEN_BLOCKLIST_REDIS_DB value: '%s'. Using default 0.", blocklistDBStr)
+		blocklistDB = 0
+	}
+	cfg.TokenBlocklistRedisDB = blocklistDB
+
+	blocklistFailOpenStr := getEnv("TOKEN_BLOCKLIST_FAIL_OPEN", "false")
+	blocklistFailOpen, err := strconv.ParseBool(blocklistFailOpenStr)
+	if err != nil {
+		log.Printf("API Gateway | Warning: Invalid TOKEN_BLOCKLIST_FAIL_OPEN value: '%s'. Using default false.", blocklistFailOpenStr)
+		blocklistFailOpen = false
+	}
+	cfg.TokenBlocklistFailOpen = blocklistFailOpen
+
+	debugLogBodiesStr := getEnv("DEBUG_LOG_BAD_REQUEST_BODIES", "false")
+	debugLogBodies, err := strconv.ParseBool(debugLogBodiesStr)
+	if err != nil {
+		log.Printf("API Gateway | Warning: Invalid DEBUG_LOG_BAD_REQUEST_BODIES value: '%s'. Using default false.", debugLogBodiesStr)
+		debugLogBodies = false
+	}
+	cfg.DebugLogBadRequestBodies = debugLogBodies
+
+	bodyLogMaxStr := getEnv("DEBUG_BODY_LOG_MAX_BYTES", "2048")
+	bodyLogMax, err := strconv.Atoi(bodyLogMaxStr)
+	if err != nil || bodyLogMax <= 0 {
+		log.Printf("API Gateway | Warning: Invalid DEBUG_BODY_LOG_MAX_BYTES value: '%s'. Using default 2048.", bodyLogMaxStr)
+		bodyLogMax = 2048
+	}
+	cfg.DebugBodyLogMaxBytes = bodyLogMax
+
+	accessLogLevel := strings.ToLower(strings.TrimSpace(getEnv("ACCESS_LOG_LEVEL", "info")))
+	if accessLogLevel != "info" && accessLogLevel != "warn" && accessLogLevel != "error" && accessLogLevel != "off" {
+		log.Printf("API Gateway | Warning: Invalid ACCESS_LOG_LEVEL value: '%s'. Using default info.", accessLogLevel)
+		accessLogLevel = "info"
+	}
+	cfg.AccessLogLevel = accessLogLevel
+
+	shutdownTimeoutStr := getEnv("SHUTDOWN_TIMEOUT", "10s")
+	shutdownTimeout, err := time.ParseDuration(shutdownTimeoutStr)
+	if err != nil || shutdownTimeout <= 0 {
+		log.Printf("API Gateway | Warning: Invalid SHUTDOWN_TIMEOUT value: '%s'. Using default 10s.", shutdownTimeoutStr)
+		shutdownTimeout = 10 * time.Second
+	}
+	cfg.ShutdownTimeout = shutdownTimeout
+
+	compositeTimeoutStr := getEnv("COMPOSITE_TIMEOUT", "5s")
+	compositeTimeout, err := time.ParseDuration(compositeTimeoutStr)
+	if err != nil || compositeTimeout <= 0 {
+		log.Printf("API Gateway | Warning: Invalid COMPOSITE_TIMEOUT value: '%s'. Using default 5s.", compositeTimeoutStr)
+		compositeTimeout = 5 * time.Second
+	}
+	cfg.CompositeTimeout = compositeTimeout
+
+	compositeConcurrencyStr := getEnv("COMPOSITE_MAX_CONCURRENCY", "8")
+	compositeConcurrency, err := strconv.Atoi(compositeConcurrencyStr)
+	if err != nil || compositeConcurrency < 0 {
+		log.Printf("API Gateway | Warning: Invalid COMPOSITE_MAX_CONCURRENCY value: '%s'. Using default 8.", compositeConcurrencyStr)
+		compositeConcurrency = 8
+	}
+	cfg.CompositeConcurrency = compositeConcurrency
+
+	compositeMaxInFlightStr := getEnv("COMPOSITE_MAX_IN_FLIGHT", "64")
+	compositeMaxInFlight, err := strconv.Atoi(compositeMaxInFlightStr)
+	if err != nil || compositeMaxInFlight < 0 {
+		log.Printf("API Gateway | Warning: Invalid COMPOSITE_MAX_IN_FLIGHT value: '%s'. Using default 64.", compositeMaxInFlightStr)
+		compositeMaxInFlight = 64
+	}
+	cfg.CompositeMaxInFlight = compositeMaxInFlight
+
+	probeIntervalStr := getEnv("DOWNSTREAM_PROBE_INTERVAL", "10s")
+	probeInterval, err := time.ParseDuration(probeIntervalStr)
+	if err != nil || probeInterval < 0 {
+		log.Printf("API Gateway | Warning: Invalid DOWNSTREAM_PROBE_INTERVAL value: '%s'. Using default 10s.", probeIntervalStr)
+		probeInterval = 10 * time.Second
 	}
+	cfg.DownstreamProbeInterval = probeInterval
+
+	// GRPC_COMPRESSION sets the default; GRPC_COMPRESSION_<SERVICE> overrides it per client.
+	defaultCompression := parseCompression("GRPC_COMPRESSION", getEnv("GRPC_COMPRESSION", "gzip"), "gzip")
+	cfg.UserServiceGRPCCompression = parseCompression("GRPC_COMPRESSION_USER_SERVICE", getEnv("GRPC_COMPRESSION_USER_SERVICE", defaultCompression), defaultCompression)
+	cfg.PetServiceGRPCCompression = parseCompression("GRPC_COMPRESSION_PET_SERVICE", getEnv("GRPC_COMPRESSION_PET_SERVICE", defaultCompression), defaultCompression)
+	cfg.AdoptionServiceGRPCCompression = parseCompression("GRPC_COMPRESSION_ADOPTION_SERVICE", getEnv("GRPC_COMPRESSION_ADOPTION_SERVICE", defaultCompression), defaultCompression)
 
 	// Critical validations
 	if cfg.ServerPort == "" {
@@ -51,13 +244,45 @@ func Load() (*Config, error) {
 	if cfg.AdoptionServiceGRPCURL == "" {
 		log.Fatal("API Gateway | FATAL: ADOPTION_SERVICE_GRPC_URL environment variable is required.")
 	}
-	if cfg.JWTSecretKey == "your_default_strong_jwt_secret_key_for_gateway" || len(cfg.JWTSecretKey) < 32 {
+	if cfg.GRPCLoadBalancingPolicy != "pick_first" && cfg.GRPCLoadBalancingPolicy != "round_robin" {
+		log.Printf("API Gateway | Warning: Invalid GRPC_LB_POLICY value: '%s'. Using default round_robin.", cfg.GRPCLoadBalancingPolicy)
+		cfg.GRPCLoadBalancingPolicy = "round_robin"
+	}
+	if cfg.MaintenanceMode != "off" && cfg.MaintenanceMode != "read_only" && cfg.MaintenanceMode != "full" {
+		log.Printf("API Gateway | Warning: Invalid MAINTENANCE_MODE value: '%s'. Using default off.", cfg.MaintenanceMode)
+		cfg.MaintenanceMode = "off"
+	}
+	if cfg.AdminAPIToken == "" && len(cfg.AdminUserIDs) == 0 {
+		log.Println("API Gateway | Info: Neither ADMIN_API_TOKEN nor ADMIN_USER_IDS set. Admin endpoints (maintenance toggle) are only open to users whose token carries the admin role.")
+	}
+	if cfg.GatewayAuthToken == "" {
+		log.Println("API Gateway | WARNING: GATEWAY_AUTH_TOKEN is not set. The services cannot tell the gateway's calls from others, so keep their gRPC ports private.")
+	}
+	if cfg.JWTSigningAlgorithm != "HS256" && cfg.JWTSigningAlgorithm != "RS256" {
+		log.Printf("API Gateway | Warning: Invalid JWT_SIGNING_ALGORITHM value: '%s'. Using default HS256.", cfg.JWTSigningAlgorithm)
+		cfg.JWTSigningAlgorithm = "HS256"
+	}
+	if cfg.JWTSigningAlgorithm == "RS256" && len(cfg.JWTPublicKeyFiles) == 0 && cfg.JWTJWKSURL == "" {
+		log.Fatal("API Gateway | FATAL: JWT_PUBLIC_KEY_FILE or JWT_JWKS_URL is required when JWT_SIGNING_ALGORITHM is RS256.")
+	}
+	if cfg.JWTSigningAlgorithm == "HS256" && (cfg.JWTSecretKey == "your_default_strong_jwt_secret_key_for_gateway" || len(cfg.JWTSecretKey) < 32) {
 		log.Println("API Gateway | WARNING: JWT_SECRET_KEY is using a default or is too short. Ensure it matches the signing key if validating tokens.")
 	}
 
 	return cfg, nil
 }
 
+// parseCompression validates a gRPC compression setting, falling back to the given default.
+func parseCompression(key, value, fallback string) string {
+	switch value {
+	case "none", "gzip":
+		return value
+	default:
+		log.Printf("API Gateway | Warning: Invalid %s value: '%s'. Using default %s.", key, value, fallback)
+		return fallback
+	}
+}
+
 // Helper function to get an environment variable or return a default value.
 func getEnv(key, fallback string) string {
 	if value, exists := os.LookupEnv(key); exists {
@@ -0,0 +1,84 @@
+// Package fanout runs independent downstream calls concurrently for the gateway's composite endpoints.
+package fanout
+
+import (
+	"context"
+	"time"
+)
+
+// Call is one named downstream call. Names must be unique within one Run.
+// Run must honour ctx, which carries the shared deadline.
+type Call struct {
+	Name string
+	Run  func(ctx context.Context) (interface{}, error)
+}
+
+// Options configures Run.
+type Options struct {
+	Timeout        time.Duration // Shared deadline for all calls; 0 keeps only the parent context's deadline
+	MaxConcurrency int           // Calls running at the same time; 0 or less runs all of them at once
+}
+
+type outcome struct {
+	name   string
+	result interface{}
+	err    error
+}
+
+// Run starts every call concurrently and waits until all of them finished or the shared deadline
+// passed. It returns the results of the successful calls and the errors of the failed ones, both
+// keyed by call name. Calls still running when the deadline passes, or that never started, are
+// reported with the context's error; Run does not wait for them.
+func Run(ctx context.Context, opts Options, calls ...Call) (map[string]interface{}, map[string]error) {
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	// Buffered so calls finishing after the deadline can still deliver and exit.
+	outcomes := make(chan outcome, len(calls))
+	var sem chan struct{}
+	if opts.MaxConcurrency > 0 {
+		sem = make(chan struct{}, opts.MaxConcurrency)
+	}
+	for _, call := range calls {
+		go func(call Call) {
+			if sem != nil {
+				select {
+				case sem <- struct{}{}:
+					defer func() { <-sem }()
+				case <-ctx.Done():
+					outcomes <- outcome{name: call.Name, err: ctx.Err()}
+					return
+				}
+			}
+			result, err := call.Run(ctx)
+			outcomes <- outcome{name: call.Name, result: result, err: err}
+		}(call)
+	}
+
+	results := make(map[string]interface{}, len(calls))
+	errs := make(map[string]error)
+	pending := make(map[string]bool, len(calls))
+	for _, call := range calls {
+		pending[call.Name] = true
+	}
+	for len(pending) > 0 {
+		select {
+		case o := <-outcomes:
+			delete(pending, o.name)
+			if o.err != nil {
+				errs[o.name] = o.err
+			} else {
+				results[o.name] = o.result
+			}
+		case <-ctx.Done():
+			for name := range pending {
+				errs[name] = ctx.Err()
+			}
+			return results, errs
+		}
+	}
+	return results, errs
+}
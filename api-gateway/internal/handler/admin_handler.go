@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhandarbeks/petstore-final-project/api-gateway/internal/client"     // Adjust import path
+	"github.com/zhandarbeks/petstore-final-project/api-gateway/internal/middleware" // Adjust import path
+)
+
+// AdminHandler handles operator-only HTTP requests for the gateway itself.
+type AdminHandler struct {
+	maintenance        *middleware.Maintenance
+	notificationClient client.NotificationServiceClient
+}
+
+// NewAdminHandler creates a new AdminHandler.
+func NewAdminHandler(maintenance *middleware.Maintenance, notificationClient client.NotificationServiceClient) *AdminHandler {
+	return &AdminHandler{maintenance: maintenance, notificationClient: notificationClient}
+}
+
+// MaintenanceStatus is the body of the maintenance endpoints.
+type MaintenanceStatus struct {
+	Mode    string `json:"mode" binding:"required"` // "off", "read_only" or "full"
+	Message string `json:"message,omitempty"`
+}
+
+// GetMaintenance godoc
+// @Summary Get the maintenance mode
+// @Description Returns the gateway's current maintenance mode. Requires the X-Admin-Token header.
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Success 200 {object} MaintenanceStatus "Current maintenance mode"
+// @Failure 401 {object} map[string]string "Invalid or missing admin token"
+// @Router /admin/maintenance [get]
+func (h *AdminHandler) GetMaintenance(c *gin.Context) {
+	mode, message := h.maintenance.State()
+	c.JSON(http.StatusOK, MaintenanceStatus{Mode: string(mode), Message: message})
+}
+
+// SetMaintenance godoc
+// @Summary Set the maintenance mode
+// @Description Switches the gateway between "off", "read_only" and "full" maintenance at runtime. Requires the X-Admin-Token header.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param status body MaintenanceStatus true "New maintenance mode"
+// @Success 200 {object} MaintenanceStatus "Updated maintenance mode"
+// @Failure 400 {object} map[string]string "Invalid mode"
+// @Failure 401 {object} map[string]string "Invalid or missing admin token"
+// @Router /admin/maintenance [put]
+func (h *AdminHandler) SetMaintenance(c *gin.Context) {
+	var req MaintenanceStatus
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload: " + err.Error()})
+		return
+	}
+	mode, ok := middleware.ParseMaintenanceMode(req.Mode)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid maintenance mode. Use 'off', 'read_only' or 'full'"})
+		return
+	}
+
+	h.maintenance.Set(mode, req.Message)
+	log.Printf("API Gateway | Maintenance mode set to '%s' by admin request from %s", mode, c.ClientIP())
+
+	mode, message := h.maintenance.State()
+	c.JSON(http.StatusOK, MaintenanceStatus{Mode: string(mode), Message: message})
+}
+
+// PreviewNotification godoc
+// @Summary Preview a notification email
+// @Description Renders the email a user would receive for a pet, using real user and pet data, without sending it. Returns the HTML body; the subject and recipient are in the X-Email-Subject and X-Email-To headers. Requires the X-Admin-Token header.
+// @Tags admin
+// @Produce html
+// @Param X-Admin-Token header string true "Admin token"
+// @Param type query string true "Email type: application_created or application_status_updated"
+// @Param userId query string true "ID of the recipient user"
+// @Param petId query string true "ID of the pet"
+// @Param status query string false "Application status to render (defaults per type)"
+// @Success 200 {string} string "Rendered email HTML"
+// @Failure 400 {object} map[string]string "Missing parameters or unknown email type"
+// @Failure 401 {object} map[string]string "Invalid or missing admin token"
+// @Failure 502 {object} map[string]string "Notification service error"
+// @Router /admin/notifications/preview [get]
+func (h *AdminHandler) PreviewNotification(c *gin.Context) {
+	emailType := c.Query("type")
+	userID := c.Query("userId")
+	petID := c.Query("petId")
+	if emailType == "" || userID == "" || petID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "type, userId and petId query parameters are required"})
+		return
+	}
+
+	preview, err := h.notificationClient.PreviewEmail(c.Request.Context(), emailType, userID, petID, c.Query("status"))
+	if err != nil {
+		var notificationErr *client.NotificationServiceError
+		if errors.As(err, &notificationErr) && notificationErr.StatusCode == http.StatusBadRequest {
+			c.JSON(http.StatusBadRequest, gin.H{"error": notificationErr.Message})
+			return
+		}
+		log.Printf("API Gateway | Error previewing notification email: %v", err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to render email preview"})
+		return
+	}
+
+	c.Header("X-Email-Subject", preview.Subject)
+	c.Header("X-Email-To", preview.To)
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(preview.Body))
+}
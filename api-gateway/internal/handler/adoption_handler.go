@@ -7,8 +7,10 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/zhandarbeks/petstore-final-project/api-gateway/internal/client" // Adjust import path
 	pbAdoption "github.com/zhandarbeks/petstore-final-project/genprotos/adoption" // Adjust import path
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	// "google.golang.org/protobuf/types/known/timestamppb" // If converting strings to timestamp for gRPC
 )
 
@@ -24,26 +26,35 @@ func NewAdoptionHandler(adoptionClient client.AdoptionServiceClient) *AdoptionHa
 
 // CreateAdoptionApplication godoc
 // @Summary Create a new adoption application
-// @Description Submits an application to adopt a pet. Requires authentication.
+// @Description Submits an application to adopt a pet for the authenticated user; a user_id in the body is ignored. Requires authentication.
 // @Tags adoptions
 // @Accept json
 // @Produce json
 // @Param application body pbAdoption.CreateAdoptionApplicationRequest true "Adoption application details"
 // @Security BearerAuth
 // @Success 201 {object} pbAdoption.AdoptionApplicationResponse "Successfully created adoption application"
-// @Failure 400 {object} map[string]string "Invalid request payload"
+// @Failure 400 {object} map[string]interface{} "Invalid request payload; field_violations lists each invalid field when known"
 // @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Pet not found"
+// @Failure 409 {object} map[string]string "Pet not available for adoption, or an active application already exists"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /adoptions [post]
 func (h *AdoptionHandler) CreateAdoptionApplication(c *gin.Context) {
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
 	var req pbAdoption.CreateAdoptionApplicationRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload: " + err.Error()})
 		return
 	}
+	req.UserId = userID // The authenticated user applies, whatever the body says
 
-	if req.UserId == "" || req.PetId == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID and Pet ID are required"})
+	if req.PetId == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Pet ID is required"})
 		return
 	}
 
@@ -54,11 +65,17 @@ func (h *AdoptionHandler) CreateAdoptionApplication(c *gin.Context) {
 		if ok {
 			switch st.Code() {
 			case codes.InvalidArgument:
-				c.JSON(http.StatusBadRequest, gin.H{"error": st.Message()})
+				body := gin.H{"error": st.Message()}
+				if violations := fieldViolations(st); len(violations) > 0 {
+					body["field_violations"] = violations
+				}
+				c.JSON(http.StatusBadRequest, body)
 			case codes.FailedPrecondition: 
 				c.JSON(http.StatusConflict, gin.H{"error": st.Message()})
 			case codes.AlreadyExists: 
 				c.JSON(http.StatusConflict, gin.H{"error": st.Message()})
+			case codes.NotFound:
+				c.JSON(http.StatusNotFound, gin.H{"error": st.Message()})
 			default:
 				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create application: " + st.Message()})
 			}
@@ -70,6 +87,25 @@ func (h *AdoptionHandler) CreateAdoptionApplication(c *gin.Context) {
 	c.JSON(http.StatusCreated, resp)
 }
 
+// FieldViolation is one invalid request field reported by a downstream service.
+type FieldViolation struct {
+	Field       string `json:"field"` // Proto field name, e.g. "application_notes"
+	Description string `json:"description"`
+}
+
+// fieldViolations returns the BadRequest field violations in st's details, or nil if there are none.
+func fieldViolations(st *status.Status) []FieldViolation {
+	var violations []FieldViolation
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			for _, v := range badRequest.GetFieldViolations() {
+				violations = append(violations, FieldViolation{Field: v.GetField(), Description: v.GetDescription()})
+			}
+		}
+	}
+	return violations
+}
+
 // GetAdoptionApplication godoc
 // @Summary Get an adoption application by ID
 // @Description Retrieves details of a specific adoption application. Requires authentication (applicant or admin).
@@ -113,7 +149,7 @@ func (h *AdoptionHandler) GetAdoptionApplication(c *gin.Context) {
 
 // UpdateAdoptionApplicationStatus godoc
 // @Summary Update an adoption application's status
-// @Description Updates the status of an adoption application (e.g., by an admin). Requires authentication.
+// @Description Updates the status of an adoption application. Requires authentication as an admin.
 // @Tags adoptions
 // @Accept json
 // @Produce json
@@ -162,6 +198,210 @@ func (h *AdoptionHandler) UpdateAdoptionApplicationStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
+// AttachmentRequest is the body of POST /adoptions/{applicationId}/attachments.
+type AttachmentRequest struct {
+	URL      string `json:"url" binding:"required"`      // Public URL of the uploaded file
+	Filename string `json:"filename" binding:"required"` // Name shown to reviewers
+}
+
+// AddApplicationAttachment godoc
+// @Summary Attach a document to an adoption application
+// @Description Attaches a document, such as a reference letter, that was uploaded to object storage beforehand. Only the applicant can add attachments, up to a configured maximum per application.
+// @Tags adoptions
+// @Accept json
+// @Produce json
+// @Param applicationId path string true "Application ID"
+// @Param attachment body AttachmentRequest true "Uploaded file URL and name"
+// @Security BearerAuth
+// @Success 200 {object} pbAdoption.AdoptionApplicationResponse "Application with the attachment"
+// @Failure 400 {object} map[string]string "Invalid URL or file name"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Caller is not the applicant"
+// @Failure 404 {object} map[string]string "Application not found"
+// @Failure 409 {object} map[string]string "Attachment limit reached"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /adoptions/{applicationId}/attachments [post]
+func (h *AdoptionHandler) AddApplicationAttachment(c *gin.Context) {
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	var req AttachmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload: " + err.Error()})
+		return
+	}
+
+	grpcCtx := metadata.AppendToOutgoingContext(c.Request.Context(), "x-user-id", userID)
+	resp, err := h.adoptionClient.AddApplicationAttachment(grpcCtx, &pbAdoption.AddApplicationAttachmentRequest{
+		ApplicationId: c.Param("applicationId"),
+		Url:           req.URL,
+		Filename:      req.Filename,
+	})
+	if err != nil {
+		writeAttachmentError(c, err, "Failed to add attachment")
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// RemoveApplicationAttachment godoc
+// @Summary Remove a document from an adoption application
+// @Description Removes the attachment with the given URL. Only the applicant can remove attachments; the file stays in object storage.
+// @Tags adoptions
+// @Produce json
+// @Param applicationId path string true "Application ID"
+// @Param url query string true "URL of the attachment to remove"
+// @Security BearerAuth
+// @Success 200 {object} pbAdoption.AdoptionApplicationResponse "Application without the attachment"
+// @Failure 400 {object} map[string]string "Missing URL"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Caller is not the applicant"
+// @Failure 404 {object} map[string]string "Application or attachment not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /adoptions/{applicationId}/attachments [delete]
+func (h *AdoptionHandler) RemoveApplicationAttachment(c *gin.Context) {
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	attachmentURL := c.Query("url")
+	if attachmentURL == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "url query parameter is required"})
+		return
+	}
+
+	grpcCtx := metadata.AppendToOutgoingContext(c.Request.Context(), "x-user-id", userID)
+	resp, err := h.adoptionClient.RemoveApplicationAttachment(grpcCtx, &pbAdoption.RemoveApplicationAttachmentRequest{
+		ApplicationId: c.Param("applicationId"),
+		Url:           attachmentURL,
+	})
+	if err != nil {
+		writeAttachmentError(c, err, "Failed to remove attachment")
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// CancelAdoptionApplication godoc
+// @Summary Cancel an adoption application
+// @Description Withdraws one of the authenticated user's applications that is still pending review; its status becomes CANCELLED_BY_USER. Approved and rejected applications cannot be cancelled. Cancelling an already cancelled application returns it unchanged.
+// @Tags adoptions
+// @Produce json
+// @Param applicationId path string true "Application ID"
+// @Security BearerAuth
+// @Success 200 {object} pbAdoption.AdoptionApplicationResponse "Cancelled application"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Caller is not the applicant"
+// @Failure 404 {object} map[string]string "Application not found"
+// @Failure 409 {object} map[string]string "Application was already approved or rejected"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /adoptions/{applicationId} [delete]
+func (h *AdoptionHandler) CancelAdoptionApplication(c *gin.Context) {
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	grpcCtx := metadata.AppendToOutgoingContext(c.Request.Context(), "x-user-id", userID)
+	resp, err := h.adoptionClient.CancelAdoptionApplication(grpcCtx, &pbAdoption.CancelAdoptionApplicationRequest{
+		ApplicationId: c.Param("applicationId"),
+		UserId:        userID,
+	})
+	if err != nil {
+		st, ok := status.FromError(err)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel application: " + err.Error()})
+			return
+		}
+		switch st.Code() {
+		case codes.NotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": st.Message()})
+		case codes.PermissionDenied:
+			c.JSON(http.StatusForbidden, gin.H{"error": st.Message()})
+		case codes.FailedPrecondition:
+			c.JSON(http.StatusConflict, gin.H{"error": st.Message()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel application: " + st.Message()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func writeAttachmentError(c *gin.Context, err error, message string) {
+	st, ok := status.FromError(err)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message + ": " + err.Error()})
+		return
+	}
+	switch st.Code() {
+	case codes.NotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": st.Message()})
+	case codes.InvalidArgument:
+		c.JSON(http.StatusBadRequest, gin.H{"error": st.Message()})
+	case codes.PermissionDenied:
+		c.JSON(http.StatusForbidden, gin.H{"error": st.Message()})
+	case codes.FailedPrecondition:
+		c.JSON(http.StatusConflict, gin.H{"error": st.Message()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message + ": " + st.Message()})
+	}
+}
+
+// ReopenApplication godoc
+// @Summary Reopen a rejected adoption application
+// @Description Moves a REJECTED application back to PENDING_REVIEW, e.g. after an appeal. The reason is stored in the application's status history. Requires the X-Admin-Token header.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param applicationId path string true "Application ID"
+// @Param reopen body pbAdoption.ReopenApplicationRequest true "Reason for reopening"
+// @Success 200 {object} pbAdoption.AdoptionApplicationResponse "Successfully reopened application"
+// @Failure 400 {object} map[string]string "Invalid request or missing reason"
+// @Failure 401 {object} map[string]string "Invalid or missing admin token"
+// @Failure 404 {object} map[string]string "Application not found"
+// @Failure 409 {object} map[string]string "Application is not rejected"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/adoptions/{applicationId}/reopen [post]
+func (h *AdoptionHandler) ReopenApplication(c *gin.Context) {
+	var reqBody pbAdoption.ReopenApplicationRequest
+	if err := c.ShouldBindJSON(&reqBody); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload: " + err.Error()})
+		return
+	}
+	reqBody.ApplicationId = c.Param("applicationId")
+
+	// The admin token was checked by the router; tell the adoption service the caller is an admin.
+	grpcCtx := metadata.AppendToOutgoingContext(c.Request.Context(), "x-user-roles", "admin")
+	resp, err := h.adoptionClient.ReopenApplication(grpcCtx, &reqBody)
+	if err != nil {
+		st, ok := status.FromError(err)
+		if ok {
+			switch st.Code() {
+			case codes.NotFound:
+				c.JSON(http.StatusNotFound, gin.H{"error": st.Message()})
+			case codes.InvalidArgument:
+				c.JSON(http.StatusBadRequest, gin.H{"error": st.Message()})
+			case codes.FailedPrecondition:
+				c.JSON(http.StatusConflict, gin.H{"error": st.Message()})
+			case codes.PermissionDenied:
+				c.JSON(http.StatusForbidden, gin.H{"error": st.Message()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reopen application: " + st.Message()})
+			}
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reopen application: " + err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
 // ListUserAdoptionApplications godoc
 // @Summary List adoption applications for a user
 // @Description Retrieves all adoption applications submitted by the authenticated user. Requires authentication.
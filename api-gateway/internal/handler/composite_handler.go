@@ -0,0 +1,730 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhandarbeks/petstore-final-project/api-gateway/internal/client" // Adjust import path
+	"github.com/zhandarbeks/petstore-final-project/api-gateway/internal/fanout"
+	"github.com/zhandarbeks/petstore-final-project/api-gateway/internal/middleware"
+	pbAdoption "github.com/zhandarbeks/petstore-final-project/genprotos/adoption" // Adjust import path
+	pbPet "github.com/zhandarbeks/petstore-final-project/genprotos/pet"           // Adjust import path
+	pbUser "github.com/zhandarbeks/petstore-final-project/genprotos/user"         // Adjust import path
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CompositeHandler serves endpoints that combine data from several downstream services.
+// The primary resource decides the HTTP status; failures while enriching it with secondary
+// data are reported in a "warnings" array instead of failing the whole request.
+type CompositeHandler struct {
+	userClient     client.UserServiceClient
+	petClient      client.PetServiceClient
+	adoptionClient client.AdoptionServiceClient
+	fanoutOptions  fanout.Options // Deadline and concurrency for the sections of multi-section composites
+}
+
+// SetFanoutOptions configures how the sections of the dashboard are loaded concurrently.
+func (h *CompositeHandler) SetFanoutOptions(opts fanout.Options) {
+	h.fanoutOptions = opts
+}
+
+// NewCompositeHandler creates a new CompositeHandler.
+func NewCompositeHandler(userClient client.UserServiceClient, petClient client.PetServiceClient, adoptionClient client.AdoptionServiceClient) *CompositeHandler {
+	return &CompositeHandler{
+		userClient:     userClient,
+		petClient:      petClient,
+		adoptionClient: adoptionClient,
+	}
+}
+
+// CompositeWarning describes a secondary section that could not be loaded.
+type CompositeWarning struct {
+	Section string `json:"section"` // Which part of the response is missing, e.g. "lister"
+	Message string `json:"message"`
+}
+
+// PublicUserProfile is the subset of a user that is safe to show to other users.
+type PublicUserProfile struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	FullName string `json:"full_name"`
+}
+
+// PetDetailsResponse is the pet+lister composite.
+type PetDetailsResponse struct {
+	Pet      *pbPet.Pet         `json:"pet"`
+	Lister   *PublicUserProfile `json:"lister,omitempty"`
+	Warnings []CompositeWarning `json:"warnings,omitempty"`
+}
+
+func toPublicUserProfile(u *pbUser.User) *PublicUserProfile {
+	if u == nil {
+		return nil
+	}
+	return &PublicUserProfile{ID: u.GetId(), Username: u.GetUsername(), FullName: u.GetFullName()}
+}
+
+// GetPetDetails godoc
+// @Summary Get a pet together with its lister
+// @Description Retrieves a pet and the public profile of the user who listed it. If the lister cannot be loaded the pet is still returned, with a warning.
+// @Tags pets
+// @Produce json
+// @Param petId path string true "Pet ID"
+// @Success 200 {object} PetDetailsResponse "Pet with lister (or warnings)"
+// @Failure 400 {object} map[string]string "Invalid pet ID"
+// @Failure 404 {object} map[string]string "Pet not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /pets/{petId}/details [get]
+func (h *CompositeHandler) GetPetDetails(c *gin.Context) {
+	petID := c.Param("petId")
+	if petID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Pet ID is required"})
+		return
+	}
+
+	grpcCtx := c.Request.Context()
+	petResp, err := h.petClient.GetPet(grpcCtx, &pbPet.GetPetRequest{PetId: petID})
+	if err != nil {
+		// The pet is the primary resource, so its failure fails the request.
+		st, ok := status.FromError(err)
+		if ok {
+			switch st.Code() {
+			case codes.NotFound:
+				c.JSON(http.StatusNotFound, gin.H{"error": st.Message()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get pet: " + st.Message()})
+			}
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get pet: " + err.Error()})
+		}
+		return
+	}
+
+	resp := PetDetailsResponse{Pet: petResp.GetPet()}
+
+	if listerID := petResp.GetPet().GetListedByUserId(); listerID != "" {
+		userResp, err := h.userClient.GetUser(grpcCtx, &pbUser.GetUserRequest{UserId: listerID})
+		if err != nil {
+			log.Printf("API Gateway | Warning: Could not load lister %s for pet %s: %v", listerID, petID, err)
+			resp.Warnings = append(resp.Warnings, CompositeWarning{Section: "lister", Message: "Lister details are temporarily unavailable"})
+		} else {
+			resp.Lister = toPublicUserProfile(userResp.GetUser())
+		}
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// PetOverviewResponse is the pet+application stats composite.
+type PetOverviewResponse struct {
+	Pet      *pbPet.Pet                      `json:"pet"`
+	Stats    *pbAdoption.PetApplicationStats `json:"stats,omitempty"`
+	Warnings []CompositeWarning              `json:"warnings,omitempty"`
+}
+
+// GetPetOverview godoc
+// @Summary Get a pet together with its application stats
+// @Description Retrieves a pet and how many adoption applications it received, by status, in one round trip. If the stats cannot be loaded the pet is still returned, with a warning.
+// @Tags pets
+// @Produce json
+// @Param petId path string true "Pet ID"
+// @Success 200 {object} PetOverviewResponse "Pet with application stats (or warnings)"
+// @Failure 400 {object} map[string]string "Invalid pet ID"
+// @Failure 404 {object} map[string]string "Pet not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /pets/{petId}/overview [get]
+func (h *CompositeHandler) GetPetOverview(c *gin.Context) {
+	petID := c.Param("petId")
+	if petID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Pet ID is required"})
+		return
+	}
+
+	grpcCtx := c.Request.Context()
+
+	// The stats only need the pet ID, so they are loaded while the pet is fetched.
+	var (
+		statsResp *pbAdoption.PetApplicationStatsResponse
+		statsErr  error
+		wg        sync.WaitGroup
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		statsResp, statsErr = h.adoptionClient.GetPetApplicationStats(grpcCtx, &pbAdoption.GetPetApplicationStatsRequest{PetId: petID})
+	}()
+
+	petResp, err := h.petClient.GetPet(grpcCtx, &pbPet.GetPetRequest{PetId: petID})
+	wg.Wait()
+	if err != nil {
+		// The pet is the primary resource, so its failure fails the request.
+		st, ok := status.FromError(err)
+		if ok {
+			switch st.Code() {
+			case codes.NotFound:
+				c.JSON(http.StatusNotFound, gin.H{"error": st.Message()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get pet: " + st.Message()})
+			}
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get pet: " + err.Error()})
+		}
+		return
+	}
+
+	resp := PetOverviewResponse{Pet: petResp.GetPet()}
+	if statsErr != nil {
+		log.Printf("API Gateway | Warning: Could not load application stats for pet %s: %v", petID, statsErr)
+		resp.Warnings = append(resp.Warnings, CompositeWarning{Section: "stats", Message: "Application stats are temporarily unavailable"})
+	} else {
+		resp.Stats = statsResp.GetStats()
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// dashboardSectionLimit caps the listed pets and applications returned by the dashboard.
+const dashboardSectionLimit = 50
+
+// ApplicationSummary counts a user's adoption applications by status.
+type ApplicationSummary struct {
+	Total           int32 `json:"total"` // All of the user's applications, including any beyond the returned page
+	PendingReview   int   `json:"pending_review"`
+	Approved        int   `json:"approved"`
+	Rejected        int   `json:"rejected"`
+	CancelledByUser int   `json:"cancelled_by_user"`
+}
+
+// DashboardResponse is the profile+pets+applications+favorites composite for the authenticated user.
+// Sections that could not be loaded are omitted and reported in warnings.
+type DashboardResponse struct {
+	Profile            *pbUser.User                      `json:"profile,omitempty"`
+	ListedPets         []*pbPet.Pet                      `json:"listed_pets,omitempty"`
+	Applications       []*pbAdoption.AdoptionApplication `json:"applications,omitempty"`
+	ApplicationSummary *ApplicationSummary               `json:"application_summary,omitempty"`
+	FavoritePetIDs     []string                          `json:"favorite_pet_ids,omitempty"`
+	Warnings           []CompositeWarning                `json:"warnings,omitempty"`
+}
+
+func summarizeApplications(resp *pbAdoption.ListAdoptionApplicationsResponse) *ApplicationSummary {
+	summary := &ApplicationSummary{Total: resp.GetTotalCount()}
+	for _, app := range resp.GetApplications() {
+		switch app.GetStatus() {
+		case pbAdoption.ApplicationStatus_PENDING_REVIEW:
+			summary.PendingReview++
+		case pbAdoption.ApplicationStatus_APPROVED:
+			summary.Approved++
+		case pbAdoption.ApplicationStatus_REJECTED:
+			summary.Rejected++
+		case pbAdoption.ApplicationStatus_CANCELLED_BY_USER:
+			summary.CancelledByUser++
+		}
+	}
+	return summary
+}
+
+// GetMyDashboard godoc
+// @Summary Get the authenticated user's dashboard
+// @Description Returns the user's profile, the pets they listed, their adoption applications with counts by status, and their favorite pet IDs in one round trip. The sections are loaded concurrently under a shared deadline; a section that cannot be loaded in time is left out and reported as a warning.
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} DashboardResponse "Dashboard (or warnings)"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "User not found"
+// @Router /users/me/dashboard [get]
+func (h *CompositeHandler) GetMyDashboard(c *gin.Context) {
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	limit := int32(dashboardSectionLimit)
+	results, errs := fanout.Run(c.Request.Context(), h.fanoutOptions,
+		fanout.Call{Name: "profile", Run: func(ctx context.Context) (interface{}, error) {
+			return h.userClient.GetUser(ctx, &pbUser.GetUserRequest{UserId: userID})
+		}},
+		fanout.Call{Name: "listed_pets", Run: func(ctx context.Context) (interface{}, error) {
+			return h.petClient.ListPets(ctx, &pbPet.ListPetsRequest{ListedByUserIdFilter: &userID, Limit: &limit})
+		}},
+		fanout.Call{Name: "applications", Run: func(ctx context.Context) (interface{}, error) {
+			return h.adoptionClient.ListUserAdoptionApplications(ctx, &pbAdoption.ListUserAdoptionApplicationsRequest{UserId: userID, Limit: &limit})
+		}},
+		fanout.Call{Name: "favorites", Run: func(ctx context.Context) (interface{}, error) {
+			return h.userClient.ListFavoritePets(ctx, &pbUser.ListFavoritePetsRequest{UserId: userID})
+		}},
+	)
+
+	// A user deleted after the token was issued has no dashboard.
+	if status.Code(errs["profile"]) == codes.NotFound {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	var resp DashboardResponse
+	for section, err := range errs {
+		log.Printf("API Gateway | Warning: Could not load %s of user %s for dashboard: %v", section, userID, err)
+	}
+	for _, section := range []struct{ name, message string }{
+		{"profile", "Profile is temporarily unavailable"},
+		{"listed_pets", "Listed pets are temporarily unavailable"},
+		{"applications", "Adoption applications are temporarily unavailable"},
+		{"favorites", "Favorites are temporarily unavailable"},
+	} {
+		if errs[section.name] != nil {
+			resp.Warnings = append(resp.Warnings, CompositeWarning{Section: section.name, Message: section.message})
+		}
+	}
+	if userResp, ok := results["profile"].(*pbUser.UserResponse); ok {
+		resp.Profile = userResp.GetUser()
+	}
+	if petsResp, ok := results["listed_pets"].(*pbPet.ListPetsResponse); ok {
+		resp.ListedPets = petsResp.GetPets()
+	}
+	if appsResp, ok := results["applications"].(*pbAdoption.ListAdoptionApplicationsResponse); ok {
+		resp.Applications = appsResp.GetApplications()
+		resp.ApplicationSummary = summarizeApplications(appsResp)
+	}
+	if favResp, ok := results["favorites"].(*pbUser.FavoritePetsResponse); ok {
+		resp.FavoritePetIDs = favResp.GetPetIds()
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeleteMyDataConfirmation must be sent as the confirmation of a DELETE /users/me/data request,
+// so the data is not withdrawn by accident.
+const DeleteMyDataConfirmation = "DELETE MY DATA"
+
+// DeleteMyDataRequest is the body of DELETE /users/me/data.
+type DeleteMyDataRequest struct {
+	Confirmation string `json:"confirmation"` // Must be DeleteMyDataConfirmation
+}
+
+// DeleteMyDataResponse reports what withdrawing the user's data did.
+type DeleteMyDataResponse struct {
+	CancelledApplications int32 `json:"cancelled_applications"` // Pending applications that were cancelled
+}
+
+// DeleteMyData godoc
+// @Summary Withdraw consent and delete the authenticated user's data
+// @Description Cancels all of the authenticated user's pending adoption applications, then anonymizes their account: personal data is replaced with placeholders and the account can no longer log in. The body must confirm the request with {"confirmation": "DELETE MY DATA"}. If the applications cannot be cancelled the account is left untouched, so the request can be retried.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body DeleteMyDataRequest true "Confirmation"
+// @Success 200 {object} DeleteMyDataResponse "Data withdrawn"
+// @Failure 400 {object} map[string]string "Missing or wrong confirmation"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "User not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /users/me/data [delete]
+func (h *CompositeHandler) DeleteMyData(c *gin.Context) {
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	var req DeleteMyDataRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Confirmation != DeleteMyDataConfirmation {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Confirm by sending {\"confirmation\": \"" + DeleteMyDataConfirmation + "\"}"})
+		return
+	}
+
+	// Cancel the applications first: once anonymized, the user can no longer log in to retry.
+	cancelResp, err := h.adoptionClient.CancelUserApplications(c.Request.Context(), &pbAdoption.CancelUserApplicationsRequest{UserId: userID})
+	if err != nil {
+		log.Printf("API Gateway | Error cancelling applications of user %s while deleting their data: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel adoption applications: " + status.Convert(err).Message()})
+		return
+	}
+
+	if _, err := h.userClient.AnonymizeUser(c.Request.Context(), &pbUser.AnonymizeUserRequest{UserId: userID}); err != nil {
+		log.Printf("API Gateway | Error anonymizing user %s while deleting their data: %v", userID, err)
+		if status.Code(err) == codes.NotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to anonymize user: " + status.Convert(err).Message()})
+		return
+	}
+
+	log.Printf("API Gateway | Data of user %s withdrawn; %d pending applications cancelled", userID, cancelResp.GetCancelledCount())
+	c.JSON(http.StatusOK, DeleteMyDataResponse{CancelledApplications: cancelResp.GetCancelledCount()})
+}
+
+// maxConcurrentPetLookups bounds the parallel GetPet calls made for a single composite request.
+const maxConcurrentPetLookups = 8
+
+// FavoritePetsDetailResponse is the favorites+pets composite.
+type FavoritePetsDetailResponse struct {
+	Pets          []*pbPet.Pet       `json:"pets"`                      // Favorited pets that still exist, in favorite order
+	MissingPetIDs []string           `json:"missing_pet_ids,omitempty"` // Favorites pointing to pets that were deleted
+	Warnings      []CompositeWarning `json:"warnings,omitempty"`
+}
+
+// authenticatedUserID returns the user ID set by middleware.RequireAuth.
+func authenticatedUserID(c *gin.Context) (string, bool) {
+	userID := c.GetString(middleware.ContextUserIDKey)
+	return userID, userID != ""
+}
+
+// GetMyFavoritePetsDetail godoc
+// @Summary Get the authenticated user's favorite pets
+// @Description Returns full details for every pet the authenticated user has favorited. Favorites pointing to deleted pets are listed in missing_pet_ids; pets that could not be loaded for other reasons are reported as warnings.
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} FavoritePetsDetailResponse "Favorite pets (or warnings)"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "User not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /users/me/favorites/detail [get]
+func (h *CompositeHandler) GetMyFavoritePetsDetail(c *gin.Context) {
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	grpcCtx := c.Request.Context()
+	favResp, err := h.userClient.ListFavoritePets(grpcCtx, &pbUser.ListFavoritePetsRequest{UserId: userID})
+	if err != nil {
+		// The favorites list is the primary resource, so its failure fails the request.
+		st, ok := status.FromError(err)
+		if ok {
+			switch st.Code() {
+			case codes.NotFound:
+				c.JSON(http.StatusNotFound, gin.H{"error": st.Message()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get favorites: " + st.Message()})
+			}
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get favorites: " + err.Error()})
+		}
+		return
+	}
+
+	petIDs := favResp.GetPetIds()
+	pets := make([]*pbPet.Pet, len(petIDs))
+	errs := make([]error, len(petIDs))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentPetLookups)
+	for i, petID := range petIDs {
+		wg.Add(1)
+		go func(i int, petID string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			petResp, err := h.petClient.GetPet(grpcCtx, &pbPet.GetPetRequest{PetId: petID})
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			pets[i] = petResp.GetPet()
+		}(i, petID)
+	}
+	wg.Wait()
+
+	resp := FavoritePetsDetailResponse{Pets: make([]*pbPet.Pet, 0, len(petIDs))}
+	failed := 0
+	for i, petID := range petIDs {
+		switch {
+		case errs[i] == nil:
+			resp.Pets = append(resp.Pets, pets[i])
+		case status.Code(errs[i]) == codes.NotFound:
+			resp.MissingPetIDs = append(resp.MissingPetIDs, petID)
+		default:
+			log.Printf("API Gateway | Warning: Could not load favorite pet %s for user %s: %v", petID, userID, errs[i])
+			failed++
+		}
+	}
+	if failed > 0 {
+		resp.Warnings = append(resp.Warnings, CompositeWarning{Section: "pets", Message: "Some favorite pets are temporarily unavailable"})
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Limits of the pets needing attention report.
+const (
+	defaultAttentionMinDays = 30
+	defaultAttentionLimit   = 50
+	maxAttentionLimit       = 100
+	attentionBatchSize      = 100 // Pets listed, and their applications counted, per round trip
+	maxAttentionBatches     = 20  // Stops the scan after this many batches, so huge catalogues stay cheap
+)
+
+// PetsNeedingAttentionResponse lists available pets nobody has applied for.
+type PetsNeedingAttentionResponse struct {
+	MinDays      int          `json:"min_days"`
+	ListedBefore string       `json:"listed_before"` // RFC 3339
+	Pets         []*pbPet.Pet `json:"pets"`          // Longest-listed first
+	Truncated    bool         `json:"truncated"`     // The scan stopped early; more pets may qualify
+}
+
+// ListPetsNeedingAttention godoc
+// @Summary List hard-to-place pets
+// @Description Lists pets that have been AVAILABLE for more than min_days days without receiving a single adoption application, longest-listed first. Requires admin access.
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Token header string false "Admin token (or a bearer token of an admin user)"
+// @Param min_days query int false "Minimum days since the pet was listed" default(30)
+// @Param limit query int false "Number of pets to return (max 100)" default(50)
+// @Success 200 {object} PetsNeedingAttentionResponse "Pets needing attention"
+// @Failure 400 {object} map[string]string "Invalid min_days"
+// @Failure 401 {object} map[string]string "Invalid or missing admin token"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/reports/pets-needing-attention [get]
+func (h *CompositeHandler) ListPetsNeedingAttention(c *gin.Context) {
+	minDays, err := strconv.Atoi(c.DefaultQuery("min_days", strconv.Itoa(defaultAttentionMinDays)))
+	if err != nil || minDays < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "min_days must be a non-negative number of days"})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultAttentionLimit)))
+	if err != nil || limit < 1 {
+		limit = defaultAttentionLimit
+	}
+	if limit > maxAttentionLimit {
+		limit = maxAttentionLimit
+	}
+
+	listedBefore := time.Now().UTC().AddDate(0, 0, -minDays).Format(time.RFC3339)
+	available := pbPet.AdoptionStatus_AVAILABLE
+	sort := "oldest"
+	batchSize := int32(attentionBatchSize)
+	grpcCtx := c.Request.Context()
+
+	resp := PetsNeedingAttentionResponse{MinDays: minDays, ListedBefore: listedBefore, Pets: []*pbPet.Pet{}}
+	for page := int32(1); len(resp.Pets) < limit; page++ {
+		if page > maxAttentionBatches {
+			resp.Truncated = true
+			break
+		}
+		petsResp, err := h.petClient.ListPets(grpcCtx, &pbPet.ListPetsRequest{
+			Page:          &page,
+			Limit:         &batchSize,
+			StatusFilter:  &available,
+			Sort:          &sort,
+			CreatedBefore: &listedBefore,
+		})
+		if err != nil {
+			log.Printf("API Gateway | Error listing pets for the attention report: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list pets: " + status.Convert(err).Message()})
+			return
+		}
+		pets := petsResp.GetPets()
+		if len(pets) == 0 {
+			break
+		}
+
+		petIDs := make([]string, len(pets))
+		for i, pet := range pets {
+			petIDs[i] = pet.GetId()
+		}
+		countsResp, err := h.adoptionClient.CountPetApplications(grpcCtx, &pbAdoption.CountPetApplicationsRequest{PetIds: petIDs})
+		if err != nil {
+			// Without the counts every pet would look unwanted, so fail instead of guessing.
+			log.Printf("API Gateway | Error counting applications for the attention report: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count applications: " + status.Convert(err).Message()})
+			return
+		}
+		for _, pet := range pets {
+			if countsResp.GetCounts()[pet.GetId()] == 0 && len(resp.Pets) < limit {
+				resp.Pets = append(resp.Pets, pet)
+			}
+		}
+		if int(page)*attentionBatchSize >= int(petsResp.GetTotalCount()) {
+			break
+		}
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// ApplicantContact is how the shelter reaches an applicant once their application is approved.
+// The user-service stores no phone number, so email is the only channel.
+type ApplicantContact struct {
+	UserID   string `json:"user_id"`
+	FullName string `json:"full_name"`
+	Email    string `json:"email"`
+}
+
+// AdoptionApplicationDetailsResponse is the application+applicant contact composite.
+type AdoptionApplicationDetailsResponse struct {
+	Application      *pbAdoption.AdoptionApplication `json:"application"`
+	ApplicantContact *ApplicantContact               `json:"applicant_contact,omitempty"` // Only for approved applications, shown to the pet's lister or an admin
+	Warnings         []CompositeWarning              `json:"warnings,omitempty"`
+}
+
+// GetAdoptionApplicationDetails godoc
+// @Summary Get an adoption application together with the applicant's contact
+// @Description Retrieves an adoption application. Once it is APPROVED, the pet's lister and admins also get the applicant's contact details; other callers, and everyone before approval, get the application only. If the pet or applicant cannot be loaded the application is still returned, with a warning.
+// @Tags adoptions
+// @Produce json
+// @Param applicationId path string true "Application ID"
+// @Security BearerAuth
+// @Success 200 {object} AdoptionApplicationDetailsResponse "Application with applicant contact (or warnings)"
+// @Failure 400 {object} map[string]string "Invalid application ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Application not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /adoptions/{applicationId}/details [get]
+func (h *CompositeHandler) GetAdoptionApplicationDetails(c *gin.Context) {
+	callerID, ok := authenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	appID := c.Param("applicationId")
+	if appID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Application ID is required"})
+		return
+	}
+
+	grpcCtx := c.Request.Context()
+	appResp, err := h.adoptionClient.GetAdoptionApplication(grpcCtx, &pbAdoption.GetAdoptionApplicationRequest{ApplicationId: appID})
+	if err != nil {
+		st, ok := status.FromError(err)
+		if ok {
+			switch st.Code() {
+			case codes.NotFound:
+				c.JSON(http.StatusNotFound, gin.H{"error": st.Message()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get application: " + st.Message()})
+			}
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get application: " + err.Error()})
+		}
+		return
+	}
+
+	app := appResp.GetApplication()
+	resp := AdoptionApplicationDetailsResponse{Application: app}
+	if app.GetStatus() != pbAdoption.ApplicationStatus_APPROVED {
+		c.JSON(http.StatusOK, resp)
+		return
+	}
+
+	if !hasRole(c, middleware.RoleAdmin) {
+		// Only the lister of the pet gets to contact the applicant, so the pet decides.
+		petResp, err := h.petClient.GetPet(grpcCtx, &pbPet.GetPetRequest{PetId: app.GetPetId()})
+		if err != nil {
+			log.Printf("API Gateway | Warning: Could not load pet %s for application %s: %v", app.GetPetId(), appID, err)
+			resp.Warnings = append(resp.Warnings, CompositeWarning{Section: "applicant_contact", Message: "Applicant contact is temporarily unavailable"})
+			c.JSON(http.StatusOK, resp)
+			return
+		}
+		if petResp.GetPet().GetListedByUserId() != callerID {
+			c.JSON(http.StatusOK, resp)
+			return
+		}
+	}
+
+	userResp, err := h.userClient.GetUser(grpcCtx, &pbUser.GetUserRequest{UserId: app.GetUserId()})
+	if err != nil {
+		log.Printf("API Gateway | Warning: Could not load applicant %s for application %s: %v", app.GetUserId(), appID, err)
+		resp.Warnings = append(resp.Warnings, CompositeWarning{Section: "applicant_contact", Message: "Applicant contact is temporarily unavailable"})
+	} else {
+		applicant := userResp.GetUser()
+		resp.ApplicantContact = &ApplicantContact{UserID: applicant.GetId(), FullName: applicant.GetFullName(), Email: applicant.GetEmail()}
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// ListPetAdoptionApplications godoc
+// @Summary List the adoption applications for a pet
+// @Description Lists who applied for the pet, oldest application first. Only the pet's lister and admins may see them.
+// @Tags adoptions
+// @Produce json
+// @Param petId path string true "Pet ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Number of items per page (max 100)" default(10)
+// @Param status_filter query string false "Filter by application status (PENDING_REVIEW, APPROVED, REJECTED, CANCELLED_BY_USER)"
+// @Security BearerAuth
+// @Success 200 {object} pbAdoption.ListAdoptionApplicationsResponse "Applications for the pet"
+// @Failure 400 {object} map[string]string "Invalid status_filter value"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Caller is neither the pet's lister nor an admin"
+// @Failure 404 {object} map[string]string "Pet not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /pets/{petId}/adoptions [get]
+func (h *CompositeHandler) ListPetAdoptionApplications(c *gin.Context) {
+	callerID, ok := authenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	petID := c.Param("petId")
+
+	pageVal, err := strconv.ParseInt(c.DefaultQuery("page", "1"), 10, 32)
+	if err != nil || pageVal < 1 {
+		pageVal = 1
+	}
+	limitVal, err := strconv.ParseInt(c.DefaultQuery("limit", "10"), 10, 32)
+	if err != nil || limitVal < 1 {
+		limitVal = 10
+	}
+	page, limit := int32(pageVal), int32(limitVal)
+	req := &pbAdoption.ListPetAdoptionApplicationsRequest{PetId: petID, Page: &page, Limit: &limit}
+	if statusFilterStr := c.Query("status_filter"); statusFilterStr != "" {
+		val, ok := pbAdoption.ApplicationStatus_value[statusFilterStr]
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status_filter value"})
+			return
+		}
+		statusEnum := pbAdoption.ApplicationStatus(val)
+		req.StatusFilter = &statusEnum
+	}
+
+	grpcCtx := c.Request.Context()
+	if !hasRole(c, middleware.RoleAdmin) {
+		// Applicants are only shown to the lister of the pet, so the pet decides.
+		petResp, err := h.petClient.GetPet(grpcCtx, &pbPet.GetPetRequest{PetId: petID})
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Pet not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get pet: " + status.Convert(err).Message()})
+			return
+		}
+		if petResp.GetPet().GetListedByUserId() != callerID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only the pet's lister or an admin can see its applications"})
+			return
+		}
+	}
+
+	resp, err := h.adoptionClient.ListPetAdoptionApplications(grpcCtx, req)
+	if err != nil {
+		st := status.Convert(err)
+		if st.Code() == codes.InvalidArgument {
+			c.JSON(http.StatusBadRequest, gin.H{"error": st.Message()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list applications: " + st.Message()})
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// hasRole reports whether the authenticated user has role, as set by middleware.RequireAuthWithRoles.
+func hasRole(c *gin.Context, role string) bool {
+	for _, r := range c.GetStringSlice(middleware.ContextUserRolesKey) {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fieldsQueryParam is the query parameter clients use to ask for a subset of a resource's
+// fields, e.g. ?fields=id,name,thumbnail_url.
+const fieldsQueryParam = "fields"
+
+// requestedFields returns the field names listed in the fields query parameter, or nil if the
+// client did not ask for a subset.
+func requestedFields(c *gin.Context) map[string]bool {
+	raw, ok := c.GetQuery(fieldsQueryParam)
+	if !ok {
+		return nil
+	}
+	fields := make(map[string]bool)
+	for _, field := range strings.Split(raw, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields[field] = true
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return fields
+}
+
+// respondWithFields writes resp like c.JSON. If the request has a fields parameter, the
+// resource under resourceKey (an object, or each object of a list) keeps only the requested
+// top-level fields; the rest of the response, such as paging info, is left as is. Unknown
+// field names are ignored.
+func respondWithFields(c *gin.Context, code int, resp interface{}, resourceKey string) {
+	fields := requestedFields(c)
+	if fields == nil {
+		c.JSON(code, resp)
+		return
+	}
+
+	// The gRPC responses are filtered in their JSON form, so the field names are the ones clients see.
+	data, err := json.Marshal(resp)
+	if err != nil {
+		log.Printf("API Gateway | Error marshalling response for field filtering: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encode response"})
+		return
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber() // Keep 64-bit integers exact
+	var body map[string]interface{}
+	if err := decoder.Decode(&body); err != nil {
+		log.Printf("API Gateway | Error decoding response for field filtering: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encode response"})
+		return
+	}
+
+	switch resource := body[resourceKey].(type) {
+	case map[string]interface{}:
+		body[resourceKey] = pickFields(resource, fields)
+	case []interface{}:
+		for i, item := range resource {
+			if object, ok := item.(map[string]interface{}); ok {
+				resource[i] = pickFields(object, fields)
+			}
+		}
+	}
+	c.JSON(code, body)
+}
+
+// pickFields returns the entries of object whose keys are in fields.
+func pickFields(object map[string]interface{}, fields map[string]bool) map[string]interface{} {
+	picked := make(map[string]interface{}, len(fields))
+	for key, value := range object {
+		if fields[key] {
+			picked[key] = value
+		}
+	}
+	return picked
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// readinessCheckTimeout bounds each downstream health check, so one hung service
+// does not stall the whole readiness probe.
+const readinessCheckTimeout = 2 * time.Second
+
+// HealthChecker is implemented by every downstream gRPC client.
+type HealthChecker interface {
+	HealthCheck(ctx context.Context) (grpc_health_v1.HealthCheckResponse_ServingStatus, error)
+}
+
+// HealthHandler reports the gateway's readiness based on its downstream services.
+type HealthHandler struct {
+	dependencies map[string]HealthChecker // Keyed by the name shown in the readiness response
+}
+
+// NewHealthHandler creates a new HealthHandler checking the user, pet and adoption services.
+func NewHealthHandler(userClient, petClient, adoptionClient HealthChecker) *HealthHandler {
+	return &HealthHandler{dependencies: map[string]HealthChecker{
+		"user-service":     userClient,
+		"pet-service":      petClient,
+		"adoption-service": adoptionClient,
+	}}
+}
+
+// DependencyHealth is the health of one downstream service.
+type DependencyHealth struct {
+	Status string `json:"status"`          // gRPC serving status, e.g. "SERVING" or "NOT_SERVING"
+	Error  string `json:"error,omitempty"` // Set when the health check itself failed
+}
+
+// ReadinessResponse is the body of the readiness endpoint.
+type ReadinessResponse struct {
+	Status       string                      `json:"status"` // "READY" or "NOT_READY"
+	Dependencies map[string]DependencyHealth `json:"dependencies"`
+}
+
+// Readyz godoc
+// @Summary Readiness check
+// @Description Checks every downstream service and reports the health of each. Returns 503 if any of them is not serving.
+// @Tags health
+// @Produce json
+// @Success 200 {object} ReadinessResponse "All dependencies are serving"
+// @Failure 503 {object} ReadinessResponse "At least one dependency is not serving"
+// @Router /readyz [get]
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	resp := ReadinessResponse{Status: "READY", Dependencies: make(map[string]DependencyHealth, len(h.dependencies))}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, checker := range h.dependencies {
+		wg.Add(1)
+		go func(name string, checker HealthChecker) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(c.Request.Context(), readinessCheckTimeout)
+			defer cancel()
+
+			servingStatus, err := checker.HealthCheck(ctx)
+			health := DependencyHealth{Status: servingStatus.String()}
+			if err != nil {
+				health.Error = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			resp.Dependencies[name] = health
+			if err != nil || servingStatus != grpc_health_v1.HealthCheckResponse_SERVING {
+				resp.Status = "NOT_READY"
+			}
+		}(name, checker)
+	}
+	wg.Wait()
+
+	if resp.Status != "READY" {
+		c.JSON(http.StatusServiceUnavailable, resp)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
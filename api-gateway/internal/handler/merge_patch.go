@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	pbPet "github.com/zhandarbeks/petstore-final-project/genprotos/pet"
+)
+
+// MergePatchContentType is the media type of RFC 7396 JSON Merge Patch bodies. PATCH endpoints
+// also accept plain application/json with the same semantics.
+const MergePatchContentType = "application/merge-patch+json"
+
+// petMergePatchFields lists the pet fields a merge patch may set, and whether null (i.e. remove)
+// is allowed for them: removing an optional text field clears it, required ones cannot be removed.
+var petMergePatchFields = map[string]bool{
+	"name":        false,
+	"species":     false,
+	"breed":       true,
+	"age":         false,
+	"description": true,
+	"image_urls":  false,
+}
+
+// petUpdateFromMergePatch builds an UpdatePetRequest from an RFC 7396 merge patch. Only the keys
+// present in patch are set on the request, so an explicit zero such as "age": 0 is sent to the
+// pet service instead of being mistaken for an absent field. null removes a field. Keys that are
+// not pet fields are ignored.
+func petUpdateFromMergePatch(patch map[string]json.RawMessage) (*pbPet.UpdatePetRequest, error) {
+	req := &pbPet.UpdatePetRequest{}
+	keys := make([]string, 0, len(patch))
+	for key := range patch {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys) // Report the first invalid field deterministically
+
+	for _, key := range keys {
+		nullable, known := petMergePatchFields[key]
+		if !known {
+			continue
+		}
+		value := patch[key]
+		if string(value) == "null" {
+			if !nullable {
+				return nil, fmt.Errorf("field %q cannot be removed", key)
+			}
+			value = json.RawMessage(`""`)
+		}
+
+		var err error
+		switch key {
+		case "name":
+			req.Name = new(string)
+			err = json.Unmarshal(value, req.Name)
+		case "species":
+			req.Species = new(string)
+			err = json.Unmarshal(value, req.Species)
+		case "breed":
+			req.Breed = new(string)
+			err = json.Unmarshal(value, req.Breed)
+		case "age":
+			req.Age = new(int32)
+			err = json.Unmarshal(value, req.Age)
+		case "description":
+			req.Description = new(string)
+			err = json.Unmarshal(value, req.Description)
+		case "image_urls":
+			err = json.Unmarshal(value, &req.ImageUrls)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("field %q has an invalid value %s", key, value)
+		}
+	}
+	return req, nil
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	pbAdoption "github.com/zhandarbeks/petstore-final-project/genprotos/adoption" // Adjust import path
+	pbPet "github.com/zhandarbeks/petstore-final-project/genprotos/pet"           // Adjust import path
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// EnumsResponse lists the accepted values of every enum-like field, so clients do not have to hard-code them.
+type EnumsResponse struct {
+	PetAdoptionStatuses []string `json:"pet_adoption_statuses"` // Pet.adoption_status and the status_filter query parameter
+	ApplicationStatuses []string `json:"application_statuses"`  // AdoptionApplication.status
+	PetSortOrders       []string `json:"pet_sort_orders"`       // The sort query parameter of the pet listings
+	TagsMatchModes      []string `json:"tags_match_modes"`      // The tags_match query parameter of the pet listings
+}
+
+// enumValueNames returns the names of an enum's values in declaration order, leaving out the
+// zero value, which is the UNSPECIFIED placeholder in every proto enum.
+func enumValueNames(enum protoreflect.EnumDescriptor) []string {
+	values := enum.Values()
+	names := make([]string, 0, values.Len())
+	for i := 0; i < values.Len(); i++ {
+		if values.Get(i).Number() == 0 {
+			continue
+		}
+		names = append(names, string(values.Get(i).Name()))
+	}
+	return names
+}
+
+// ListEnums godoc
+// @Summary List enum values
+// @Description Returns the valid values of the pet and application statuses and of the pet listing query options,
+// @Description taken from the proto definitions.
+// @Tags meta
+// @Produce json
+// @Success 200 {object} EnumsResponse "Valid values per enum"
+// @Router /meta/enums [get]
+func ListEnums(c *gin.Context) {
+	c.JSON(http.StatusOK, EnumsResponse{
+		PetAdoptionStatuses: enumValueNames(pbPet.AdoptionStatus(0).Descriptor()),
+		ApplicationStatuses: enumValueNames(pbAdoption.ApplicationStatus(0).Descriptor()),
+		PetSortOrders:       petSortOrders,
+		TagsMatchModes:      tagsMatchModes,
+	})
+}
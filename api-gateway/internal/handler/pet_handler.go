@@ -1,19 +1,26 @@
 package handler
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/zhandarbeks/petstore-final-project/api-gateway/internal/client" // Adjust import path
 	pbPet "github.com/zhandarbeks/petstore-final-project/genprotos/pet"       // Adjust import path
 	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 )
 
 // PetHandler handles HTTP requests related to pets.
 type PetHandler struct {
 	petClient client.PetServiceClient // gRPC client for the pet service
+
+	browsePresets       map[string]BrowsePreset // Named defaults for BrowsePets
+	defaultBrowsePreset string                  // Preset used when the browse request names none
 }
 
 // NewPetHandler creates a new PetHandler.
@@ -23,7 +30,8 @@ func NewPetHandler(petClient client.PetServiceClient) *PetHandler {
 
 // CreatePet godoc
 // @Summary Create a new pet listing
-// @Description Adds a new pet to the store. Requires authentication.
+// @Description Adds a new pet to the store. Requires authentication. An adoption_status other than AVAILABLE
+// @Description is only accepted from shelters and admins. image_urls must be empty: upload images once the pet exists.
 // @Tags pets
 // @Accept json
 // @Produce json
@@ -32,29 +40,36 @@ func NewPetHandler(petClient client.PetServiceClient) *PetHandler {
 // @Success 201 {object} pbPet.PetResponse "Successfully created pet"
 // @Failure 400 {object} map[string]string "Invalid request payload"
 // @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Initial status not allowed for the caller"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /pets [post]
 func (h *PetHandler) CreatePet(c *gin.Context) {
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
 	var req pbPet.CreatePetRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload: " + err.Error()})
 		return
 	}
-
-	// Example: Get authenticated user ID from context (set by auth middleware)
-	// userID, exists := c.Get("userID")
-	// if !exists {
-	// 	c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
-	// 	return
-	// }
-	// req.ListedByUserId = userID.(string) // Set the user who is listing the pet
+	req.ListedByUserId = userID // The authenticated user lists the pet, whatever the body says
 
 	grpcCtx := c.Request.Context()
 	resp, err := h.petClient.CreatePet(grpcCtx, &req)
 	if err != nil {
 		st, ok := status.FromError(err)
 		if ok {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create pet: " + st.Message()})
+			switch st.Code() {
+			case codes.InvalidArgument:
+				c.JSON(http.StatusBadRequest, gin.H{"error": st.Message()})
+			case codes.PermissionDenied:
+				c.JSON(http.StatusForbidden, gin.H{"error": st.Message()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create pet: " + st.Message()})
+			}
 		} else {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create pet: " + err.Error()})
 		}
@@ -69,6 +84,7 @@ func (h *PetHandler) CreatePet(c *gin.Context) {
 // @Tags pets
 // @Produce json
 // @Param petId path string true "Pet ID"
+// @Param fields query string false "Comma-separated pet fields to return, e.g. id,name; all fields if omitted"
 // @Success 200 {object} pbPet.PetResponse "Successfully retrieved pet"
 // @Failure 400 {object} map[string]string "Invalid pet ID"
 // @Failure 404 {object} map[string]string "Pet not found"
@@ -98,14 +114,72 @@ func (h *PetHandler) GetPet(c *gin.Context) {
 		}
 		return
 	}
-	c.JSON(http.StatusOK, resp)
+	respondWithFields(c, http.StatusOK, resp, "pet")
+}
+
+// PetHistoryResponse is a pet's status history, with its most recent change broken out so
+// clients can show why the pet has its current status.
+type PetHistoryResponse struct {
+	PetID            string                   `json:"pet_id"`
+	AdoptionStatus   pbPet.AdoptionStatus     `json:"adoption_status"`
+	LastStatusChange *pbPet.PetStatusChange   `json:"last_status_change,omitempty"`
+	StatusHistory    []*pbPet.PetStatusChange `json:"status_history"` // Oldest first
+}
+
+// GetPetHistory godoc
+// @Summary Get a pet's status history
+// @Description Lists the pet's status changes, oldest first, with the reason given for each, and the most recent change as last_status_change.
+// @Tags pets
+// @Produce json
+// @Param petId path string true "Pet ID"
+// @Success 200 {object} PetHistoryResponse "Successfully retrieved status history"
+// @Failure 400 {object} map[string]string "Invalid pet ID"
+// @Failure 404 {object} map[string]string "Pet not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /pets/{petId}/history [get]
+func (h *PetHandler) GetPetHistory(c *gin.Context) {
+	petID := c.Param("petId")
+	if petID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Pet ID is required"})
+		return
+	}
+
+	resp, err := h.petClient.GetPet(c.Request.Context(), &pbPet.GetPetRequest{PetId: petID})
+	if err != nil {
+		st, ok := status.FromError(err)
+		if ok {
+			switch st.Code() {
+			case codes.NotFound:
+				c.JSON(http.StatusNotFound, gin.H{"error": st.Message()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get pet history: " + st.Message()})
+			}
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get pet history: " + err.Error()})
+		}
+		return
+	}
+
+	pet := resp.GetPet()
+	history := PetHistoryResponse{
+		PetID:          pet.GetId(),
+		AdoptionStatus: pet.GetAdoptionStatus(),
+		StatusHistory:  pet.GetStatusHistory(),
+	}
+	if n := len(history.StatusHistory); n > 0 {
+		history.LastStatusChange = history.StatusHistory[n-1]
+	} else {
+		history.StatusHistory = []*pbPet.PetStatusChange{}
+	}
+	c.JSON(http.StatusOK, history)
 }
 
 // UpdatePet godoc
 // @Summary Update a pet's details
-// @Description Updates information for an existing pet. Requires authentication.
+// @Description Updates information for an existing pet with RFC 7396 JSON Merge Patch semantics: only the fields in the body are changed, an explicit zero (e.g. "age": 0) is applied, and null clears breed or description. image_urls replaces the gallery and may only keep the pet's images or add ones uploaded through POST /pets/{petId}/images/upload-url. Requires authentication as the user who listed the pet.
 // @Tags pets
 // @Accept json
+// @Accept application/merge-patch+json
 // @Produce json
 // @Param petId path string true "Pet ID"
 // @Param pet body pbPet.UpdatePetRequest true "Pet update details"
@@ -115,6 +189,7 @@ func (h *PetHandler) GetPet(c *gin.Context) {
 // @Failure 401 {object} map[string]string "Unauthorized"
 // @Failure 403 {object} map[string]string "Forbidden (e.g., not owner)"
 // @Failure 404 {object} map[string]string "Pet not found"
+// @Failure 409 {object} map[string]string "Pet was modified concurrently"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /pets/{petId} [patch]
 func (h *PetHandler) UpdatePet(c *gin.Context) {
@@ -124,15 +199,22 @@ func (h *PetHandler) UpdatePet(c *gin.Context) {
 		return
 	}
 
-	var req pbPet.UpdatePetRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	// Bind into a map rather than the proto type, so fields the client left out can be told
+	// apart from fields explicitly set to their zero value.
+	var patch map[string]json.RawMessage
+	if err := c.ShouldBindJSON(&patch); err != nil || patch == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload: the body must be a JSON object"})
+		return
+	}
+	req, err := petUpdateFromMergePatch(patch)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload: " + err.Error()})
 		return
 	}
 	req.PetId = petID // Ensure PetId from path is used
 
 	grpcCtx := c.Request.Context()
-	resp, err := h.petClient.UpdatePet(grpcCtx, &req)
+	resp, err := h.petClient.UpdatePet(grpcCtx, req)
 	if err != nil {
 		st, ok := status.FromError(err)
 		if ok {
@@ -141,6 +223,10 @@ func (h *PetHandler) UpdatePet(c *gin.Context) {
 				c.JSON(http.StatusNotFound, gin.H{"error": st.Message()})
 			case codes.InvalidArgument:
 				c.JSON(http.StatusBadRequest, gin.H{"error": st.Message()})
+			case codes.PermissionDenied:
+				c.JSON(http.StatusForbidden, gin.H{"error": st.Message()})
+			case codes.Aborted:
+				c.JSON(http.StatusConflict, gin.H{"error": st.Message()})
 			default:
 				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update pet: " + st.Message()})
 			}
@@ -154,7 +240,7 @@ func (h *PetHandler) UpdatePet(c *gin.Context) {
 
 // DeletePet godoc
 // @Summary Delete a pet listing
-// @Description Deletes a pet. Requires authentication.
+// @Description Deletes a pet. The pet is kept, with its history, and can be brought back with POST /pets/{petId}/restore. Requires authentication as the user who listed the pet.
 // @Tags pets
 // @Produce json
 // @Param petId path string true "Pet ID"
@@ -182,6 +268,8 @@ func (h *PetHandler) DeletePet(c *gin.Context) {
 			switch st.Code() {
 			case codes.NotFound:
 				c.JSON(http.StatusNotFound, gin.H{"error": st.Message()})
+			case codes.PermissionDenied:
+				c.JSON(http.StatusForbidden, gin.H{"error": st.Message()})
 			default:
 				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete pet: " + st.Message()})
 			}
@@ -199,18 +287,63 @@ func (h *PetHandler) DeletePet(c *gin.Context) {
 // @Tags pets
 // @Produce json
 // @Param page query int false "Page number" default(1)
+// @Param cursor query string false "next_cursor of the previous page, or empty to start cursor pagination; cannot be combined with page"
 // @Param limit query int false "Number of items per page" default(10)
 // @Param species_filter query string false "Filter by species"
+// @Param species query []string false "Pets of any of these species; repeat the parameter, e.g. species=Dog&species=Cat" collectionFormat(multi)
 // @Param status_filter query string false "Filter by adoption status (AVAILABLE, PENDING_ADOPTION, ADOPTED)"
+// @Param tags query string false "Comma-separated tags, e.g. house-trained,good with kids"
+// @Param tags_match query string false "any (default): pet has at least one of the tags; all: pet has every tag"
+// @Param sort query string false "newest or oldest by listing date; unset keeps the storage order"
+// @Param q query string false "Words to search for in the name and description (whole words, case-insensitive)"
+// @Param min_age query int false "Only pets at least this old"
+// @Param max_age query int false "Only pets at most this old"
+// @Param fields query string false "Comma-separated listed pet fields to return, e.g. id,name; all fields if omitted"
 // @Success 200 {object} pbPet.ListPetsResponse "Successfully retrieved list of pets"
 // @Failure 400 {object} map[string]string "Invalid query parameters"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /pets [get]
 func (h *PetHandler) ListPets(c *gin.Context) {
-	pageStr := c.DefaultQuery("page", "1")
+	h.listPets(c, BrowsePreset{}, false)
+}
+
+// AdminListPets godoc
+// @Summary List pets, including deleted ones
+// @Description Same as GET /pets, but deleted pets can be listed too with include_deleted. Requires the X-Admin-Token header.
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param include_deleted query bool false "Also list deleted pets"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Number of items per page" default(10)
+// @Param status_filter query string false "Filter by adoption status (AVAILABLE, PENDING_ADOPTION, ADOPTED)"
+// @Success 200 {object} pbPet.ListPetsResponse "Successfully retrieved list of pets"
+// @Failure 400 {object} map[string]string "Invalid query parameters"
+// @Failure 401 {object} map[string]string "Invalid or missing admin token"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/pets [get]
+func (h *PetHandler) AdminListPets(c *gin.Context) {
+	h.listPets(c, BrowsePreset{}, true)
+}
+
+// listPets serves a pet listing from the query parameters, using defaults for the
+// status_filter and sort parameters that are not given. Only admin listings accept include_deleted.
+func (h *PetHandler) listPets(c *gin.Context, defaults BrowsePreset, admin bool) {
+	pageStr, pageSet := c.GetQuery("page")
+	cursorStr, cursorSet := c.GetQuery("cursor")
 	limitStr := c.DefaultQuery("limit", "10")
 	speciesFilterQuery := c.Query("species_filter")
-	statusFilterStr := c.Query("status_filter")
+	speciesQuery := c.QueryArray("species")
+	statusFilterStr := c.DefaultQuery("status_filter", defaults.StatusFilter)
+	sortStr := c.DefaultQuery("sort", defaults.Sort)
+	tagsQuery := c.Query("tags")
+	tagsMatch := c.DefaultQuery("tags_match", "any")
+	searchQuery := strings.TrimSpace(c.Query("q"))
+
+	if pageSet && cursorSet {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page: cursor and page cannot be combined"})
+		return
+	}
 
 	pageVal, err := strconv.ParseInt(pageStr, 10, 32)
 	if err != nil || pageVal < 1 {
@@ -225,13 +358,33 @@ func (h *PetHandler) ListPets(c *gin.Context) {
 	limitInt32 := int32(limitVal)
 
 	req := &pbPet.ListPetsRequest{
-		Page:  &pageInt32,  // Pass pointer
 		Limit: &limitInt32, // Pass pointer
 	}
+	if cursorSet {
+		req.Cursor = &cursorStr // Cursor pages replace page numbers; an empty cursor starts from the first pet
+	} else {
+		req.Page = &pageInt32
+	}
 
 	if speciesFilterQuery != "" {
 		req.SpeciesFilter = &speciesFilterQuery // Pass pointer
 	}
+	for _, species := range speciesQuery {
+		if species = strings.TrimSpace(species); species != "" {
+			req.SpeciesFilters = append(req.SpeciesFilters, species)
+		}
+	}
+	if searchQuery != "" {
+		req.SearchQuery = &searchQuery
+	}
+	if req.MinAge, err = optionalInt32Query(c, "min_age"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.MaxAge, err = optionalInt32Query(c, "max_age"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	if statusFilterStr != "" {
 		if val, ok := pbPet.AdoptionStatus_value[statusFilterStr]; ok {
@@ -243,23 +396,166 @@ func (h *PetHandler) ListPets(c *gin.Context) {
 		}
 	}
 
+	if tagsQuery != "" {
+		if tagsMatch != "any" && tagsMatch != "all" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tags_match value. Valid values: any, all"})
+			return
+		}
+		req.TagsFilter = strings.Split(tagsQuery, ",")
+		matchAll := tagsMatch == "all"
+		req.MatchAllTags = &matchAll
+	}
+
+	if sortStr != "" {
+		if !isValidPetSort(sortStr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sort value. Valid values: newest, oldest"})
+			return
+		}
+		req.Sort = &sortStr
+	}
+
 	grpcCtx := c.Request.Context()
+	if admin {
+		if includeDeletedStr := c.Query("include_deleted"); includeDeletedStr != "" {
+			includeDeleted, err := strconv.ParseBool(includeDeletedStr)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid include_deleted value: must be true or false"})
+				return
+			}
+			req.IncludeDeleted = &includeDeleted
+		}
+		// The admin token was checked by the router; tell the pet service the caller is an admin.
+		grpcCtx = metadata.AppendToOutgoingContext(grpcCtx, "x-user-roles", "admin")
+	}
 	resp, err := h.petClient.ListPets(grpcCtx, req)
 	if err != nil {
 		st, ok := status.FromError(err)
 		if ok {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list pets: " + st.Message()})
+			switch st.Code() {
+			case codes.InvalidArgument:
+				c.JSON(http.StatusBadRequest, gin.H{"error": st.Message()})
+			case codes.PermissionDenied:
+				c.JSON(http.StatusForbidden, gin.H{"error": st.Message()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list pets: " + st.Message()})
+			}
 		} else {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list pets: " + err.Error()})
 		}
 		return
 	}
-	c.JSON(http.StatusOK, resp)
+	respondWithFields(c, http.StatusOK, resp, "pets")
+}
+
+// optionalInt32Query parses the query parameter name as an int32. It returns nil if the parameter
+// is absent or empty.
+func optionalInt32Query(c *gin.Context, name string) (*int32, error) {
+	valueStr := c.Query(name)
+	if valueStr == "" {
+		return nil, nil
+	}
+	value, err := strconv.ParseInt(valueStr, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid %s value: must be a whole number", name)
+	}
+	value32 := int32(value)
+	return &value32, nil
+}
+
+// Accepted values of the sort and tags_match query parameters.
+var (
+	petSortOrders  = []string{"newest", "oldest"}
+	tagsMatchModes = []string{"any", "all"}
+)
+
+// isValidPetSort reports whether sort is an order the pet service accepts.
+func isValidPetSort(sort string) bool {
+	for _, order := range petSortOrders {
+		if sort == order {
+			return true
+		}
+	}
+	return false
+}
+
+// BrowsePreset is a named set of defaults for the browse endpoint. Empty fields apply no default.
+type BrowsePreset struct {
+	StatusFilter string // e.g., "AVAILABLE"
+	Sort         string // "newest" or "oldest"
+}
+
+// ParseBrowsePresets parses presets written as comma-separated "name:status_filter:sort"
+// entries, e.g. "available_newest:AVAILABLE:newest,raw::". Empty fields apply no default.
+func ParseBrowsePresets(spec string) (map[string]BrowsePreset, error) {
+	presets := make(map[string]BrowsePreset)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, ":")
+		if len(parts) != 3 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid browse preset %q, want name:status_filter:sort", entry)
+		}
+		preset := BrowsePreset{StatusFilter: parts[1], Sort: parts[2]}
+		if _, ok := pbPet.AdoptionStatus_value[preset.StatusFilter]; preset.StatusFilter != "" && !ok {
+			return nil, fmt.Errorf("browse preset %q has invalid status_filter %q", parts[0], preset.StatusFilter)
+		}
+		if preset.Sort != "" && !isValidPetSort(preset.Sort) {
+			return nil, fmt.Errorf("browse preset %q has invalid sort %q", parts[0], preset.Sort)
+		}
+		presets[parts[0]] = preset
+	}
+	return presets, nil
+}
+
+// SetBrowsePresets configures the presets for BrowsePets. defaultPreset must be one of presets.
+func (h *PetHandler) SetBrowsePresets(presets map[string]BrowsePreset, defaultPreset string) error {
+	if _, ok := presets[defaultPreset]; !ok {
+		return fmt.Errorf("default browse preset %q is not defined", defaultPreset)
+	}
+	h.browsePresets = presets
+	h.defaultBrowsePreset = defaultPreset
+	return nil
+}
+
+// BrowsePets godoc
+// @Summary Browse pets
+// @Description Lists pets like GET /pets, with status_filter and sort defaulting to a named preset
+// @Description (by default available pets, newest first). Explicit query parameters override the preset.
+// @Tags pets
+// @Produce json
+// @Param preset query string false "Preset name, e.g. available_newest or raw; defaults to the configured preset"
+// @Param page query int false "Page number" default(1)
+// @Param cursor query string false "next_cursor of the previous page, or empty to start cursor pagination; cannot be combined with page"
+// @Param limit query int false "Number of items per page" default(10)
+// @Param species_filter query string false "Filter by species"
+// @Param species query []string false "Pets of any of these species; repeat the parameter" collectionFormat(multi)
+// @Param status_filter query string false "Filter by adoption status, overrides the preset"
+// @Param tags query string false "Comma-separated tags"
+// @Param tags_match query string false "any (default) or all"
+// @Param sort query string false "newest or oldest, overrides the preset"
+// @Param q query string false "Words to search for in the name and description"
+// @Param min_age query int false "Only pets at least this old"
+// @Param max_age query int false "Only pets at most this old"
+// @Param fields query string false "Comma-separated listed pet fields to return, e.g. id,name; all fields if omitted"
+// @Success 200 {object} pbPet.ListPetsResponse "Successfully retrieved list of pets"
+// @Failure 400 {object} map[string]string "Unknown preset or invalid query parameters"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /pets/browse [get]
+func (h *PetHandler) BrowsePets(c *gin.Context) {
+	name := c.DefaultQuery("preset", h.defaultBrowsePreset)
+	preset, ok := h.browsePresets[name]
+	if !ok && name != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown preset: " + name})
+		return
+	}
+	h.listPets(c, preset, false)
 }
 
 // UpdatePetAdoptionStatus godoc
 // @Summary Update a pet's adoption status
-// @Description Updates the adoption status of a pet. Requires authentication (e.g. admin or involved user).
+// @Description Updates the adoption status of a pet. An optional reason, e.g. "reserved pending home check", is kept in the pet's status history. Requires authentication as an admin.
 // @Tags pets
 // @Accept json
 // @Produce json
@@ -269,8 +565,9 @@ func (h *PetHandler) ListPets(c *gin.Context) {
 // @Success 200 {object} pbPet.PetResponse "Successfully updated pet adoption status"
 // @Failure 400 {object} map[string]string "Invalid request"
 // @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 403 {object} map[string]string "Forbidden (not admin)"
 // @Failure 404 {object} map[string]string "Pet not found"
+// @Failure 409 {object} map[string]string "Pet was modified concurrently"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /pets/{petId}/status [patch]
 func (h *PetHandler) UpdatePetAdoptionStatus(c *gin.Context) {
@@ -297,6 +594,8 @@ func (h *PetHandler) UpdatePetAdoptionStatus(c *gin.Context) {
 				c.JSON(http.StatusNotFound, gin.H{"error": st.Message()})
 			case codes.InvalidArgument:
 				c.JSON(http.StatusBadRequest, gin.H{"error": st.Message()})
+			case codes.Aborted:
+				c.JSON(http.StatusConflict, gin.H{"error": st.Message()})
 			default:
 				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update pet status: " + st.Message()})
 			}
@@ -306,4 +605,447 @@ func (h *PetHandler) UpdatePetAdoptionStatus(c *gin.Context) {
 		return
 	}
 	c.JSON(http.StatusOK, resp)
-}
\ No newline at end of file
+}
+
+// RestorePet godoc
+// @Summary Restore a deleted pet listing
+// @Description Brings back a pet deleted with DELETE /pets/{petId}. Requires authentication as the user who listed the pet, or as an admin.
+// @Tags pets
+// @Produce json
+// @Param petId path string true "Pet ID"
+// @Security BearerAuth
+// @Success 200 {object} pbPet.PetResponse "Successfully restored pet"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Not the owner or an admin"
+// @Failure 404 {object} map[string]string "Pet not found"
+// @Failure 409 {object} map[string]string "Pet is not deleted"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /pets/{petId}/restore [post]
+func (h *PetHandler) RestorePet(c *gin.Context) {
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	grpcCtx := metadata.AppendToOutgoingContext(c.Request.Context(), "x-user-id", userID)
+	resp, err := h.petClient.RestorePet(grpcCtx, &pbPet.RestorePetRequest{PetId: c.Param("petId")})
+	if err != nil {
+		st, ok := status.FromError(err)
+		if ok {
+			switch st.Code() {
+			case codes.NotFound:
+				c.JSON(http.StatusNotFound, gin.H{"error": st.Message()})
+			case codes.InvalidArgument:
+				c.JSON(http.StatusBadRequest, gin.H{"error": st.Message()})
+			case codes.PermissionDenied:
+				c.JSON(http.StatusForbidden, gin.H{"error": st.Message()})
+			case codes.FailedPrecondition:
+				c.JSON(http.StatusConflict, gin.H{"error": st.Message()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore pet: " + st.Message()})
+			}
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore pet: " + err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// adminTokenActor is recorded as the actor of changes made with the X-Admin-Token header, which
+// does not identify a user.
+const adminTokenActor = "admin-token"
+
+// AdminSetPetStatus godoc
+// @Summary Force a pet's adoption status
+// @Description Sets a pet's status without the usual transition checks, e.g. to correct data. The reason is stored in the pet's status history, along with the admin's user ID, or "admin-token" for X-Admin-Token callers. Requires the X-Admin-Token header or an admin's bearer token.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Token header string false "Admin token"
+// @Param petId path string true "Pet ID"
+// @Param statusUpdate body pbPet.AdminSetPetStatusRequest true "New status and reason; changed_by is ignored"
+// @Success 200 {object} pbPet.PetResponse "Successfully overridden pet status"
+// @Failure 400 {object} map[string]string "Invalid request or missing reason"
+// @Failure 401 {object} map[string]string "Invalid or missing admin token"
+// @Failure 404 {object} map[string]string "Pet not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/pets/{petId}/status [put]
+func (h *PetHandler) AdminSetPetStatus(c *gin.Context) {
+	var reqBody pbPet.AdminSetPetStatusRequest
+	if err := c.ShouldBindJSON(&reqBody); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload: " + err.Error()})
+		return
+	}
+	reqBody.PetId = c.Param("petId")
+	// The status history names the authenticated admin, whatever the body says.
+	if userID, ok := authenticatedUserID(c); ok {
+		reqBody.ChangedBy = userID
+	} else {
+		reqBody.ChangedBy = adminTokenActor
+	}
+
+	// The admin token was checked by the router; tell the pet service the caller is an admin.
+	grpcCtx := metadata.AppendToOutgoingContext(c.Request.Context(), "x-user-roles", "admin")
+	resp, err := h.petClient.AdminSetPetStatus(grpcCtx, &reqBody)
+	if err != nil {
+		st, ok := status.FromError(err)
+		if ok {
+			switch st.Code() {
+			case codes.NotFound:
+				c.JSON(http.StatusNotFound, gin.H{"error": st.Message()})
+			case codes.InvalidArgument:
+				c.JSON(http.StatusBadRequest, gin.H{"error": st.Message()})
+			case codes.PermissionDenied:
+				c.JSON(http.StatusForbidden, gin.H{"error": st.Message()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to override pet status: " + st.Message()})
+			}
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to override pet status: " + err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetImageUploadURL godoc
+// @Summary Get a presigned upload target for a pet image
+// @Description Returns a URL and form fields for uploading an image directly to object storage. After uploading, register the returned public_url with POST /pets/{petId}/images. Only the user who listed the pet can do this. Requires authentication.
+// @Tags pets
+// @Accept json
+// @Produce json
+// @Param petId path string true "Pet ID"
+// @Param upload body pbPet.GetImageUploadURLRequest true "File name and content type"
+// @Security BearerAuth
+// @Success 200 {object} pbPet.ImageUploadTarget "Presigned upload target"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Not the pet's owner"
+// @Failure 404 {object} map[string]string "Pet not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /pets/{petId}/images/upload-url [post]
+func (h *PetHandler) GetImageUploadURL(c *gin.Context) {
+	petID := c.Param("petId")
+	if petID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Pet ID is required in path"})
+		return
+	}
+
+	var req pbPet.GetImageUploadURLRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload: " + err.Error()})
+		return
+	}
+	req.PetId = petID // Ensure PetId from path is used
+
+	grpcCtx := c.Request.Context()
+	resp, err := h.petClient.GetImageUploadURL(grpcCtx, &req)
+	if err != nil {
+		st, ok := status.FromError(err)
+		if ok {
+			switch st.Code() {
+			case codes.NotFound:
+				c.JSON(http.StatusNotFound, gin.H{"error": st.Message()})
+			case codes.InvalidArgument:
+				c.JSON(http.StatusBadRequest, gin.H{"error": st.Message()})
+			case codes.PermissionDenied:
+				c.JSON(http.StatusForbidden, gin.H{"error": st.Message()})
+			case codes.Unimplemented:
+				c.JSON(http.StatusNotImplemented, gin.H{"error": st.Message()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get image upload URL: " + st.Message()})
+			}
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get image upload URL: " + err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// AddImageURLs godoc
+// @Summary Add uploaded image URLs to a pet
+// @Description Appends image URLs to a pet's gallery. Each URL must be the public_url of an upload issued for this pet by POST /pets/{petId}/images/upload-url. Only the user who listed the pet can do this. Requires authentication.
+// @Tags pets
+// @Accept json
+// @Produce json
+// @Param petId path string true "Pet ID"
+// @Param images body pbPet.AddImageURLsRequest true "Image URLs to add"
+// @Security BearerAuth
+// @Success 200 {object} pbPet.PetResponse "Successfully added images"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Not the pet's owner"
+// @Failure 404 {object} map[string]string "Pet not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /pets/{petId}/images [post]
+func (h *PetHandler) AddImageURLs(c *gin.Context) {
+	petID := c.Param("petId")
+	if petID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Pet ID is required in path"})
+		return
+	}
+
+	var req pbPet.AddImageURLsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload: " + err.Error()})
+		return
+	}
+	req.PetId = petID // Ensure PetId from path is used
+
+	grpcCtx := c.Request.Context()
+	resp, err := h.petClient.AddImageURLs(grpcCtx, &req)
+	if err != nil {
+		st, ok := status.FromError(err)
+		if ok {
+			switch st.Code() {
+			case codes.NotFound:
+				c.JSON(http.StatusNotFound, gin.H{"error": st.Message()})
+			case codes.InvalidArgument:
+				c.JSON(http.StatusBadRequest, gin.H{"error": st.Message()})
+			case codes.PermissionDenied:
+				c.JSON(http.StatusForbidden, gin.H{"error": st.Message()})
+			case codes.Unimplemented:
+				c.JSON(http.StatusNotImplemented, gin.H{"error": st.Message()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add image URLs: " + st.Message()})
+			}
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add image URLs: " + err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// ListRecentlyAdopted godoc
+// @Summary List recently adopted pets
+// @Description Retrieves the most recently adopted pets, newest first, for showcase pages.
+// @Tags pets
+// @Produce json
+// @Param limit query int false "Number of pets to return (max 50)" default(10)
+// @Param fields query string false "Comma-separated listed pet fields to return, e.g. id,name; all fields if omitted"
+// @Success 200 {object} pbPet.ListRecentlyAdoptedResponse "Successfully retrieved recently adopted pets"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /pets/recently-adopted [get]
+func (h *PetHandler) ListRecentlyAdopted(c *gin.Context) {
+	limitVal, err := strconv.ParseInt(c.DefaultQuery("limit", "10"), 10, 32)
+	if err != nil || limitVal < 1 {
+		limitVal = 10
+	}
+	limitInt32 := int32(limitVal)
+
+	grpcCtx := c.Request.Context()
+	resp, err := h.petClient.ListRecentlyAdopted(grpcCtx, &pbPet.ListRecentlyAdoptedRequest{Limit: &limitInt32})
+	if err != nil {
+		st, ok := status.FromError(err)
+		if ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list recently adopted pets: " + st.Message()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list recently adopted pets: " + err.Error()})
+		}
+		return
+	}
+	respondWithFields(c, http.StatusOK, resp, "pets")
+}
+
+// SuggestBreeds godoc
+// @Summary Suggest breeds
+// @Description Autocompletes the breed of a listing: returns the distinct breeds already used for the species that start with prefix, both matched case-insensitively, in alphabetical order.
+// @Tags pets
+// @Produce json
+// @Param species query string true "Species, e.g. Dog"
+// @Param prefix query string false "Start of the breed name"
+// @Param limit query int false "Number of breeds to return (max 50)" default(10)
+// @Success 200 {object} pbPet.SuggestBreedsResponse "Matching breeds"
+// @Failure 400 {object} map[string]string "Missing species"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /pets/breeds/suggest [get]
+func (h *PetHandler) SuggestBreeds(c *gin.Context) {
+	species := strings.TrimSpace(c.Query("species"))
+	if species == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "species query parameter is required"})
+		return
+	}
+	limitVal, err := strconv.ParseInt(c.DefaultQuery("limit", "10"), 10, 32)
+	if err != nil || limitVal < 1 {
+		limitVal = 10
+	}
+	limitInt32 := int32(limitVal)
+
+	grpcCtx := c.Request.Context()
+	resp, err := h.petClient.SuggestBreeds(grpcCtx, &pbPet.SuggestBreedsRequest{Species: species, Prefix: c.Query("prefix"), Limit: &limitInt32})
+	if err != nil {
+		st, ok := status.FromError(err)
+		if ok {
+			switch st.Code() {
+			case codes.InvalidArgument:
+				c.JSON(http.StatusBadRequest, gin.H{"error": st.Message()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to suggest breeds: " + st.Message()})
+			}
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to suggest breeds: " + err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetPetFacets godoc
+// @Summary Get pet search facets
+// @Description Returns the distinct species, breeds and adoption statuses with pet counts, for building search filters. Results may be cached for a few minutes.
+// @Tags pets
+// @Produce json
+// @Success 200 {object} pbPet.PetFacetsResponse "Successfully retrieved pet facets"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /pets/facets [get]
+func (h *PetHandler) GetPetFacets(c *gin.Context) {
+	grpcCtx := c.Request.Context()
+	resp, err := h.petClient.GetPetFacets(grpcCtx, &pbPet.GetPetFacetsRequest{})
+	if err != nil {
+		st, ok := status.FromError(err)
+		if ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get pet facets: " + st.Message()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get pet facets: " + err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// petTagsBody is the request body of AddPetTags.
+type petTagsBody struct {
+	Tags []string `json:"tags" binding:"required"`
+}
+
+// respondPetTagsResult writes the result of a tag update.
+func respondPetTagsResult(c *gin.Context, resp *pbPet.PetResponse, err error) {
+	if err != nil {
+		st, ok := status.FromError(err)
+		if ok {
+			switch st.Code() {
+			case codes.NotFound:
+				c.JSON(http.StatusNotFound, gin.H{"error": st.Message()})
+			case codes.InvalidArgument:
+				c.JSON(http.StatusBadRequest, gin.H{"error": st.Message()})
+			case codes.PermissionDenied:
+				c.JSON(http.StatusForbidden, gin.H{"error": st.Message()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update pet tags: " + st.Message()})
+			}
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update pet tags: " + err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// AddPetTags godoc
+// @Summary Tag a pet
+// @Description Adds tags (e.g. "good with kids", "house-trained") to a pet. Tags are lowercased; existing tags are kept. Only the user who listed the pet can do this. Requires authentication.
+// @Tags pets
+// @Accept json
+// @Produce json
+// @Param petId path string true "Pet ID"
+// @Param tags body petTagsBody true "Tags to add"
+// @Security BearerAuth
+// @Success 200 {object} pbPet.PetResponse "Successfully tagged pet"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Not the pet's owner"
+// @Failure 404 {object} map[string]string "Pet not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /pets/{petId}/tags [post]
+func (h *PetHandler) AddPetTags(c *gin.Context) {
+	var body petTagsBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload: " + err.Error()})
+		return
+	}
+	resp, err := h.petClient.AddPetTags(c.Request.Context(), &pbPet.PetTagsRequest{PetId: c.Param("petId"), Tags: body.Tags})
+	respondPetTagsResult(c, resp, err)
+}
+
+// RemovePetTag godoc
+// @Summary Remove a tag from a pet
+// @Description Removes a single tag from a pet. Only the user who listed the pet can do this. Requires authentication.
+// @Tags pets
+// @Produce json
+// @Param petId path string true "Pet ID"
+// @Param tag path string true "Tag to remove"
+// @Security BearerAuth
+// @Success 200 {object} pbPet.PetResponse "Successfully removed tag"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Not the pet's owner"
+// @Failure 404 {object} map[string]string "Pet not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /pets/{petId}/tags/{tag} [delete]
+func (h *PetHandler) RemovePetTag(c *gin.Context) {
+	resp, err := h.petClient.RemovePetTags(c.Request.Context(), &pbPet.PetTagsRequest{PetId: c.Param("petId"), Tags: []string{c.Param("tag")}})
+	respondPetTagsResult(c, resp, err)
+}
+
+// TransferPetListingRequest is the body of the listing transfer endpoint.
+type TransferPetListingRequest struct {
+	NewOwnerUserID string `json:"new_owner_user_id" binding:"required"`
+}
+
+// TransferPetListing godoc
+// @Summary Transfer a pet listing to another user
+// @Description Reassigns the pet's listing to another existing user. Only the current owner (or an admin) can do this; the change is kept in the pet's listing history. Requires authentication.
+// @Tags pets
+// @Accept json
+// @Produce json
+// @Param petId path string true "Pet ID"
+// @Param transfer body TransferPetListingRequest true "New owner"
+// @Security BearerAuth
+// @Success 200 {object} pbPet.PetResponse "Successfully transferred listing"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Not the current owner"
+// @Failure 404 {object} map[string]string "Pet or new owner not found"
+// @Failure 409 {object} map[string]string "Pet was modified concurrently"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /pets/{petId}/transfer [post]
+func (h *PetHandler) TransferPetListing(c *gin.Context) {
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	var req TransferPetListingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload: " + err.Error()})
+		return
+	}
+
+	grpcCtx := metadata.AppendToOutgoingContext(c.Request.Context(), "x-user-id", userID)
+	resp, err := h.petClient.TransferPetListing(grpcCtx, &pbPet.TransferPetListingRequest{PetId: c.Param("petId"), NewOwnerUserId: req.NewOwnerUserID})
+	if err != nil {
+		st, ok := status.FromError(err)
+		if ok {
+			switch st.Code() {
+			case codes.NotFound:
+				c.JSON(http.StatusNotFound, gin.H{"error": st.Message()})
+			case codes.InvalidArgument:
+				c.JSON(http.StatusBadRequest, gin.H{"error": st.Message()})
+			case codes.PermissionDenied:
+				c.JSON(http.StatusForbidden, gin.H{"error": st.Message()})
+			case codes.Aborted:
+				c.JSON(http.StatusConflict, gin.H{"error": st.Message()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to transfer pet listing: " + st.Message()})
+			}
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to transfer pet listing: " + err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
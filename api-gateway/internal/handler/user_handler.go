@@ -3,10 +3,13 @@ package handler
 import (
 	"net/http"
 	"strings" // For parsing Bearer token
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/zhandarbeks/petstore-final-project/api-gateway/internal/client" // Adjust import path
+	"github.com/zhandarbeks/petstore-final-project/api-gateway/internal/middleware"
 	pbUser "github.com/zhandarbeks/petstore-final-project/genprotos/user"       // Adjust import path
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/codes"
 )
@@ -29,7 +32,8 @@ func NewUserHandler(userClient client.UserServiceClient) *UserHandler {
 // @Produce json
 // @Param user body pbUser.RegisterUserRequest true "User registration details"
 // @Success 201 {object} pbUser.UserResponse "Successfully registered user"
-// @Failure 400 {object} map[string]string "Invalid request payload or already exists"
+// @Failure 400 {object} map[string]string "Invalid request payload"
+// @Failure 409 {object} map[string]string "Email or username already taken; field names which"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /users/register [post]
 func (h *UserHandler) RegisterUser(c *gin.Context) {
@@ -55,7 +59,11 @@ func (h *UserHandler) RegisterUser(c *gin.Context) {
 			case codes.InvalidArgument:
 				c.JSON(http.StatusBadRequest, gin.H{"error": st.Message()})
 			case codes.AlreadyExists:
-				c.JSON(http.StatusConflict, gin.H{"error": st.Message()})
+				body := gin.H{"error": st.Message()}
+				if field := conflictingField(st); field != "" {
+					body["field"] = field // "email" or "username"
+				}
+				c.JSON(http.StatusConflict, body)
 			default:
 				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to register user: " + st.Message()})
 			}
@@ -67,9 +75,20 @@ func (h *UserHandler) RegisterUser(c *gin.Context) {
 	c.JSON(http.StatusCreated, resp)
 }
 
+// conflictingField returns the field named by the first BadRequest field violation in st's
+// details, or "" if there is none.
+func conflictingField(st *status.Status) string {
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok && len(badRequest.GetFieldViolations()) > 0 {
+			return badRequest.GetFieldViolations()[0].GetField()
+		}
+	}
+	return ""
+}
+
 // LoginUser godoc
 // @Summary Log in a user
-// @Description Authenticates a user and returns an access token.
+// @Description Authenticates a user and returns an access token, and a refresh token for POST /users/refresh.
 // @Tags users
 // @Accept json
 // @Produce json
@@ -112,6 +131,94 @@ func (h *UserHandler) LoginUser(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
+// RefreshToken godoc
+// @Summary Refresh an access token
+// @Description Exchanges the refresh token returned at login for a new access token. The refresh token is rotated: the response carries a new one and the one sent can no longer be used.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body pbUser.RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} pbUser.RefreshTokenResponse "New access and refresh tokens"
+// @Failure 400 {object} map[string]string "Invalid request payload"
+// @Failure 401 {object} map[string]string "Invalid, expired or already used refresh token"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /users/refresh [post]
+func (h *UserHandler) RefreshToken(c *gin.Context) {
+	var req pbUser.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.RefreshToken == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload: refresh_token is required"})
+		return
+	}
+
+	resp, err := h.userClient.RefreshToken(c.Request.Context(), &req)
+	if err != nil {
+		st, ok := status.FromError(err)
+		if ok {
+			switch st.Code() {
+			case codes.InvalidArgument:
+				c.JSON(http.StatusBadRequest, gin.H{"error": st.Message()})
+			case codes.Unauthenticated:
+				c.JSON(http.StatusUnauthorized, gin.H{"error": st.Message()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Token refresh failed: " + st.Message()})
+			}
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Token refresh failed: " + err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// LogoutRequest is the optional body of POST /users/logout.
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"` // Revoked as well when set
+}
+
+// LogoutUser godoc
+// @Summary Log out
+// @Description Revokes the bearer token for the rest of its lifetime, and the refresh token if one is sent. Requires authentication.
+// @Tags users
+// @Accept json
+// @Param request body LogoutRequest false "Refresh token to revoke"
+// @Security BearerAuth
+// @Success 204 "Logged out"
+// @Failure 400 {object} map[string]string "Invalid request payload"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 501 {object} map[string]string "Logout is not available"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /users/logout [post]
+func (h *UserHandler) LogoutUser(c *gin.Context) {
+	var body LogoutRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload: " + err.Error()})
+			return
+		}
+	}
+
+	req := &pbUser.LogoutRequest{AccessToken: extractToken(c), RefreshToken: body.RefreshToken}
+	if _, err := h.userClient.LogoutUser(c.Request.Context(), req); err != nil {
+		st, ok := status.FromError(err)
+		if ok {
+			switch st.Code() {
+			case codes.InvalidArgument:
+				c.JSON(http.StatusBadRequest, gin.H{"error": st.Message()})
+			case codes.Unauthenticated:
+				c.JSON(http.StatusUnauthorized, gin.H{"error": st.Message()})
+			case codes.Unimplemented:
+				c.JSON(http.StatusNotImplemented, gin.H{"error": st.Message()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Logout failed: " + st.Message()})
+			}
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Logout failed: " + err.Error()})
+		}
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
 // GetUser godoc
 // @Summary Get user profile
 // @Description Retrieves the profile of a user by their ID.
@@ -119,6 +226,7 @@ func (h *UserHandler) LoginUser(c *gin.Context) {
 // @Produce json
 // @Param userId path string true "User ID"
 // @Security BearerAuth
+// @Param fields query string false "Comma-separated user fields to return, e.g. id,name; all fields if omitted"
 // @Success 200 {object} pbUser.UserResponse "Successfully retrieved user profile"
 // @Failure 400 {object} map[string]string "Invalid user ID"
 // @Failure 401 {object} map[string]string "Unauthorized"
@@ -163,7 +271,7 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 		}
 		return
 	}
-	c.JSON(http.StatusOK, resp)
+	respondWithFields(c, http.StatusOK, resp, "user")
 }
 
 // UpdateUserProfile godoc
@@ -173,13 +281,14 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param userId path string true "User ID (must match authenticated user)"
-// @Param user body pbUser.UpdateUserProfileRequest true "User profile update details (only username and full_name can be updated)"
+// @Param user body pbUser.UpdateUserProfileRequest true "User profile update details (only username, full_name and locale can be updated)"
 // @Security BearerAuth
 // @Success 200 {object} pbUser.UserResponse "Successfully updated user profile"
 // @Failure 400 {object} map[string]string "Invalid request"
 // @Failure 401 {object} map[string]string "Unauthorized"
 // @Failure 403 {object} map[string]string "Forbidden"
 // @Failure 404 {object} map[string]string "User not found"
+// @Failure 409 {object} map[string]string "Profile was modified concurrently"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /users/{userId} [patch]
 func (h *UserHandler) UpdateUserProfile(c *gin.Context) {
@@ -213,6 +322,8 @@ func (h *UserHandler) UpdateUserProfile(c *gin.Context) {
 				c.JSON(http.StatusBadRequest, gin.H{"error": st.Message()})
 			case codes.NotFound:
 				c.JSON(http.StatusNotFound, gin.H{"error": st.Message()})
+			case codes.Aborted:
+				c.JSON(http.StatusConflict, gin.H{"error": st.Message()})
 			default:
 				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update profile: " + st.Message()})
 			}
@@ -274,15 +385,141 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
-// Helper to extract token from Authorization header (example)
+// extractToken returns the token of an "Authorization: Bearer <token>" header, parsed the same
+// way as by the auth middleware.
 func extractToken(c *gin.Context) string {
 	authHeader := c.GetHeader("Authorization")
-	if authHeader == "" {
-		return ""
-	}
-	parts := strings.Split(authHeader, "Bearer ")
-	if len(parts) == 2 {
-		return parts[1]
+	if len(authHeader) > 7 && strings.EqualFold(authHeader[:7], "Bearer ") {
+		return strings.TrimSpace(authHeader[7:])
 	}
 	return ""
-}
\ No newline at end of file
+}
+
+// respondFavoritesError maps a favorites gRPC error to an HTTP response.
+func respondFavoritesError(c *gin.Context, err error) {
+	st, ok := status.FromError(err)
+	if ok {
+		switch st.Code() {
+		case codes.InvalidArgument:
+			c.JSON(http.StatusBadRequest, gin.H{"error": st.Message()})
+		case codes.NotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": st.Message()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update favorites: " + st.Message()})
+		}
+	} else {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update favorites: " + err.Error()})
+	}
+}
+
+// ListMyFavoritePets godoc
+// @Summary List the authenticated user's favorite pet IDs
+// @Description Returns the IDs of the pets the authenticated user has favorited. Use /users/me/favorites/detail for full pet objects.
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} pbUser.FavoritePetsResponse "Favorite pet IDs"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "User not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /users/me/favorites [get]
+func (h *UserHandler) ListMyFavoritePets(c *gin.Context) {
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	resp, err := h.userClient.ListFavoritePets(c.Request.Context(), &pbUser.ListFavoritePetsRequest{UserId: userID})
+	if err != nil {
+		respondFavoritesError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// AddMyFavoritePet godoc
+// @Summary Favorite a pet
+// @Description Adds a pet to the authenticated user's favorites. Favoriting a pet twice has no effect.
+// @Tags users
+// @Produce json
+// @Param petId path string true "Pet ID"
+// @Security BearerAuth
+// @Success 200 {object} pbUser.FavoritePetsResponse "Updated favorite pet IDs"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "User not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /users/me/favorites/{petId} [put]
+func (h *UserHandler) AddMyFavoritePet(c *gin.Context) {
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	resp, err := h.userClient.AddFavoritePet(c.Request.Context(), &pbUser.FavoritePetRequest{UserId: userID, PetId: c.Param("petId")})
+	if err != nil {
+		respondFavoritesError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// RemoveMyFavoritePet godoc
+// @Summary Unfavorite a pet
+// @Description Removes a pet from the authenticated user's favorites.
+// @Tags users
+// @Produce json
+// @Param petId path string true "Pet ID"
+// @Security BearerAuth
+// @Success 200 {object} pbUser.FavoritePetsResponse "Updated favorite pet IDs"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "User not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /users/me/favorites/{petId} [delete]
+func (h *UserHandler) RemoveMyFavoritePet(c *gin.Context) {
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	resp, err := h.userClient.RemoveFavoritePet(c.Request.Context(), &pbUser.FavoritePetRequest{UserId: userID, PetId: c.Param("petId")})
+	if err != nil {
+		respondFavoritesError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// TokenClaimsResponse describes a valid access token.
+type TokenClaimsResponse struct {
+	UserID    string    `json:"user_id"`
+	Username  string    `json:"username"`
+	Roles     []string  `json:"roles"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// VerifyToken godoc
+// @Summary Check an access token
+// @Description Returns the claims of the bearer token if it is valid, so clients can check a stored token without calling a real endpoint. Invalid, expired and missing tokens get 401.
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} TokenClaimsResponse "Decoded token claims"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /users/token/verify [get]
+func (h *UserHandler) VerifyToken(c *gin.Context) {
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	roles := c.GetStringSlice(middleware.ContextUserRolesKey)
+	if roles == nil {
+		roles = []string{}
+	}
+	c.JSON(http.StatusOK, TokenClaimsResponse{
+		UserID:    userID,
+		Username:  c.GetString(middleware.ContextUsernameKey),
+		Roles:     roles,
+		ExpiresAt: c.GetTime(middleware.ContextTokenExpiresAtKey),
+	})
+}
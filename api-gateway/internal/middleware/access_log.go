@@ -0,0 +1,153 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader carries the request ID. A valid ID sent by the client (or a proxy in front
+// of the gateway) is kept, otherwise one is generated; either way it is echoed in the response.
+const RequestIDHeader = "X-Request-ID"
+
+// ContextRequestIDKey is the Gin context key under which the request ID is stored.
+const ContextRequestIDKey = "requestID"
+
+// maxRequestIDLength caps client-supplied request IDs, so they cannot bloat the logs.
+const maxRequestIDLength = 128
+
+// AccessLogLevel is the least severe access log line that is written. Each request is logged at
+// error (5xx), warn (4xx) or info (everything else).
+type AccessLogLevel string
+
+const (
+	AccessLogInfo  AccessLogLevel = "info"  // Every request is logged
+	AccessLogWarn  AccessLogLevel = "warn"  // Only 4xx and 5xx responses are logged
+	AccessLogError AccessLogLevel = "error" // Only 5xx responses are logged
+	AccessLogOff   AccessLogLevel = "off"   // Nothing is logged; request IDs are still assigned
+)
+
+// accessLogSeverity orders the levels; off is above every line's level.
+var accessLogSeverity = map[AccessLogLevel]int{
+	AccessLogInfo:  0,
+	AccessLogWarn:  1,
+	AccessLogError: 2,
+	AccessLogOff:   3,
+}
+
+// ParseAccessLogLevel converts a config/env value into an AccessLogLevel.
+func ParseAccessLogLevel(value string) (AccessLogLevel, bool) {
+	level := AccessLogLevel(strings.ToLower(strings.TrimSpace(value)))
+	switch level {
+	case "":
+		return AccessLogInfo, true
+	case "warning":
+		return AccessLogWarn, true
+	}
+	if _, ok := accessLogSeverity[level]; !ok {
+		return "", false
+	}
+	return level, true
+}
+
+// latencyBuckets are the upper bounds latencies are grouped under in the access log, so slow
+// requests can be found with a plain text search, e.g. for latency_bucket=le_5s.
+var latencyBuckets = []struct {
+	bound time.Duration
+	name  string
+}{
+	{10 * time.Millisecond, "le_10ms"},
+	{50 * time.Millisecond, "le_50ms"},
+	{100 * time.Millisecond, "le_100ms"},
+	{250 * time.Millisecond, "le_250ms"},
+	{500 * time.Millisecond, "le_500ms"},
+	{time.Second, "le_1s"},
+	{2500 * time.Millisecond, "le_2.5s"},
+	{5 * time.Second, "le_5s"},
+}
+
+// LatencyBucket returns the name of the smallest bucket latency fits in, "gt_5s" past the last.
+func LatencyBucket(latency time.Duration) string {
+	for _, bucket := range latencyBuckets {
+		if latency <= bucket.bound {
+			return bucket.name
+		}
+	}
+	return "gt_5s"
+}
+
+// AccessLog returns middleware that writes one key=value line per request with its method,
+// path, status, response size, latency, the authenticated user (if any) and the request ID.
+// Lines below level are skipped. It also assigns the request ID, so it should run first.
+func AccessLog(level AccessLogLevel) gin.HandlerFunc {
+	threshold, ok := accessLogSeverity[level]
+	if !ok {
+		threshold = accessLogSeverity[AccessLogInfo]
+	}
+	return func(c *gin.Context) {
+		start := time.Now()
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = newRequestID()
+		}
+		c.Set(ContextRequestIDKey, requestID)
+		c.Header(RequestIDHeader, requestID)
+
+		c.Next()
+
+		latency := time.Since(start)
+		status := c.Writer.Status()
+		lineLevel := AccessLogInfo
+		switch {
+		case status >= 500:
+			lineLevel = AccessLogError
+		case status >= 400:
+			lineLevel = AccessLogWarn
+		}
+		if accessLogSeverity[lineLevel] < threshold {
+			return
+		}
+		userID := c.GetString(ContextUserIDKey)
+		if userID == "" {
+			userID = "-"
+		}
+		log.Printf("API Gateway | access level=%s method=%s path=%s status=%d bytes=%d latency_ms=%s latency_bucket=%s user_id=%s request_id=%s",
+			lineLevel, c.Request.Method, logfmtValue(c.Request.URL.Path), status, c.Writer.Size(),
+			strconv.FormatFloat(float64(latency.Microseconds())/1000, 'f', 3, 64), LatencyBucket(latency),
+			logfmtValue(userID), requestID)
+	}
+}
+
+// validRequestID reports whether a client-supplied request ID is safe to log as is.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		if r <= ' ' || r > '~' || r == '"' || r == '=' {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(b)
+}
+
+// logfmtValue quotes value if it would otherwise break the key=value format.
+func logfmtValue(value string) string {
+	if value == "" || strings.ContainsAny(value, " \t\r\n\"=") {
+		return strconv.Quote(value)
+	}
+	return value
+}
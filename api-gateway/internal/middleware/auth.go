@@ -0,0 +1,268 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc/metadata"
+)
+
+// Gin context keys set by RequireAuth.
+const (
+	ContextUserIDKey         = "userID"
+	ContextUsernameKey       = "username"
+	ContextTokenExpiresAtKey = "tokenExpiresAt" // time.Time
+)
+
+// userIDMetadataKey is the gRPC metadata key the services read the caller's user ID from.
+const userIDMetadataKey = "x-user-id"
+
+// Claims the user-service puts in its access tokens.
+const (
+	tokenIssuer   = "petstore-user-service"
+	tokenAudience = "petstore-clients"
+)
+
+// TokenVerifier checks the signature of access tokens. The user-service signs them either
+// with a shared HMAC secret (HS256) or with an RSA private key (RS256), in which case the
+// gateway only needs the public key.
+type TokenVerifier struct {
+	algorithm          string // jwt signing method name, e.g. "HS256"
+	keyFunc            jwt.Keyfunc
+	leeway             time.Duration // Clock skew tolerated when checking "exp" and "nbf"
+	revoked            RevokedTokens // Tokens revoked before they expired; nil if not checked
+	revocationFailOpen bool          // Accept tokens whose revocation could not be checked instead of rejecting them
+}
+
+// ErrRevocationCheckFailed is returned by Authenticate when it could not find out whether a token
+// was revoked, e.g. because the blocklist Redis is down. The middlewares answer 503 for it.
+var ErrRevocationCheckFailed = errors.New("could not check whether the token was revoked")
+
+// RevokedTokens reports whether an access token, identified by its "jti" claim, was revoked
+// before it expired, e.g. because its user logged out.
+type RevokedTokens interface {
+	IsTokenRevoked(ctx context.Context, tokenID string) (bool, error)
+}
+
+// WithRevokedTokens returns a copy of v whose Authenticate rejects the tokens in revoked.
+func (v TokenVerifier) WithRevokedTokens(revoked RevokedTokens) TokenVerifier {
+	v.revoked = revoked
+	return v
+}
+
+// WithRevocationFailOpen returns a copy of v whose Authenticate accepts a token, with a logged
+// warning, when the revocation check fails, so an outage of the blocklist does not lock every
+// user out. Logged-out tokens then work again until the blocklist is back. Off by default.
+func (v TokenVerifier) WithRevocationFailOpen(failOpen bool) TokenVerifier {
+	v.revocationFailOpen = failOpen
+	return v
+}
+
+// WithLeeway returns a copy of v that accepts tokens up to leeway past their "exp", or before
+// their "nbf", so small clock differences between the user-service and the gateway do not
+// reject fresh tokens. The verifiers returned by the constructors have no leeway.
+func (v TokenVerifier) WithLeeway(leeway time.Duration) TokenVerifier {
+	v.leeway = leeway
+	return v
+}
+
+// NewHMACVerifier verifies HS256 tokens signed with secret.
+func NewHMACVerifier(secret []byte) TokenVerifier {
+	return TokenVerifier{
+		algorithm: jwt.SigningMethodHS256.Alg(),
+		keyFunc:   func(*jwt.Token) (interface{}, error) { return secret, nil },
+	}
+}
+
+// NewRSAVerifier verifies RS256 tokens against any of publicKeys. During a key rotation both the
+// new and the previous keys are configured; tokens pick theirs with the "kid" header, which must
+// be the key's RFC 7638 thumbprint. A token without "kid" is accepted only with a single key.
+func NewRSAVerifier(publicKeys ...*rsa.PublicKey) TokenVerifier {
+	byID := make(map[string]*rsa.PublicKey, len(publicKeys))
+	for _, publicKey := range publicKeys {
+		byID[RSAKeyID(publicKey)] = publicKey
+	}
+	return TokenVerifier{
+		algorithm: jwt.SigningMethodRS256.Alg(),
+		keyFunc: func(t *jwt.Token) (interface{}, error) {
+			keyID, _ := t.Header["kid"].(string)
+			if keyID == "" && len(publicKeys) == 1 {
+				return publicKeys[0], nil
+			}
+			if publicKey, ok := byID[keyID]; ok {
+				return publicKey, nil
+			}
+			return nil, fmt.Errorf("unknown signing key %q", keyID)
+		},
+	}
+}
+
+// RSAKeyID returns the RFC 7638 thumbprint of publicKey, which the user-service uses as its key ID.
+func RSAKeyID(publicKey *rsa.PublicKey) string {
+	thumbprintInput := fmt.Sprintf(`{"e":"%s","kty":"RSA","n":"%s"}`,
+		base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+		base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()))
+	sum := sha256.Sum256([]byte(thumbprintInput))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// LoadRSAVerifier reads PEM-encoded RSA public keys and returns an RS256 verifier accepting any of them.
+func LoadRSAVerifier(publicKeyFiles ...string) (TokenVerifier, error) {
+	publicKeys := make([]*rsa.PublicKey, 0, len(publicKeyFiles))
+	for _, file := range publicKeyFiles {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return TokenVerifier{}, fmt.Errorf("reading JWT public key: %w", err)
+		}
+		publicKey, err := jwt.ParseRSAPublicKeyFromPEM(data)
+		if err != nil {
+			return TokenVerifier{}, fmt.Errorf("parsing JWT public key %s: %w", file, err)
+		}
+		publicKeys = append(publicKeys, publicKey)
+	}
+	if len(publicKeys) == 0 {
+		return TokenVerifier{}, errors.New("no JWT public key configured")
+	}
+	return NewRSAVerifier(publicKeys...), nil
+}
+
+// AccessTokenClaims are the claims of a valid access token that the gateway uses.
+type AccessTokenClaims struct {
+	UserID    string
+	Username  string
+	TokenID   string // "jti" claim; empty in tokens issued before tokens could be revoked
+	Role      string // "rol" claim; empty in tokens issued before users had roles
+	ExpiresAt time.Time
+}
+
+// VerifyAccessToken validates an access token issued by the user-service and returns its claims.
+// Tokens signed with any other algorithm than the verifier's are rejected.
+func (v TokenVerifier) VerifyAccessToken(tokenString string) (AccessTokenClaims, error) {
+	token, err := jwt.Parse(tokenString, v.keyFunc,
+		jwt.WithValidMethods([]string{v.algorithm}),
+		jwt.WithIssuer(tokenIssuer),
+		jwt.WithAudience(tokenAudience),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(v.leeway),
+	)
+	if err != nil {
+		return AccessTokenClaims{}, err
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return AccessTokenClaims{}, errors.New("unexpected token claims")
+	}
+	userID, _ := claims["sub"].(string)
+	if userID == "" {
+		return AccessTokenClaims{}, errors.New("token has no subject")
+	}
+	username, _ := claims["unm"].(string)
+	role, _ := claims["rol"].(string)
+	tokenID, _ := claims["jti"].(string)
+	expiresAt, err := claims.GetExpirationTime()
+	if err != nil || expiresAt == nil {
+		return AccessTokenClaims{}, errors.New("token has no valid expiry")
+	}
+	return AccessTokenClaims{UserID: userID, Username: username, TokenID: tokenID, Role: role, ExpiresAt: expiresAt.Time}, nil
+}
+
+// Authenticate is VerifyAccessToken that also rejects tokens that were revoked. When the
+// revocation check itself fails, e.g. because Redis is down, the token is rejected with
+// ErrRevocationCheckFailed, unless WithRevocationFailOpen was set.
+func (v TokenVerifier) Authenticate(ctx context.Context, tokenString string) (AccessTokenClaims, error) {
+	claims, err := v.VerifyAccessToken(tokenString)
+	if err != nil || v.revoked == nil || claims.TokenID == "" {
+		return claims, err
+	}
+	revoked, err := v.revoked.IsTokenRevoked(ctx, claims.TokenID)
+	if err != nil {
+		if v.revocationFailOpen {
+			log.Printf("API Gateway | Warning: Could not check whether token of user %s is revoked, accepting it: %v", claims.UserID, err)
+			return claims, nil
+		}
+		log.Printf("API Gateway | Could not check whether token of user %s is revoked, rejecting it: %v", claims.UserID, err)
+		return AccessTokenClaims{}, fmt.Errorf("%w: %v", ErrRevocationCheckFailed, err)
+	}
+	if revoked {
+		return AccessTokenClaims{}, errors.New("token has been revoked")
+	}
+	return claims, nil
+}
+
+// ParseAccessToken validates an access token issued by the user-service and returns its subject (user ID)
+// and username. Tokens signed with any other algorithm than the verifier's are rejected.
+func (v TokenVerifier) ParseAccessToken(tokenString string) (string, string, error) {
+	claims, err := v.VerifyAccessToken(tokenString)
+	if err != nil {
+		return "", "", err
+	}
+	return claims.UserID, claims.Username, nil
+}
+
+// ParseAccessToken validates an HS256 access token issued by the user-service and returns its subject (user ID)
+// and username.
+func ParseAccessToken(tokenString string, secret []byte) (string, string, error) {
+	return NewHMACVerifier(secret).ParseAccessToken(tokenString)
+}
+
+// bearerToken extracts the token from an "Authorization: Bearer <token>" header.
+func bearerToken(c *gin.Context) string {
+	authHeader := c.GetHeader("Authorization")
+	if len(authHeader) > 7 && strings.EqualFold(authHeader[:7], "Bearer ") {
+		return strings.TrimSpace(authHeader[7:])
+	}
+	return ""
+}
+
+// RequireAuth returns middleware that rejects requests without a valid HS256 access token and
+// stores the authenticated user ID under ContextUserIDKey, and the token's expiry under
+// ContextTokenExpiresAtKey, for the handlers. The user ID is also added to the request context
+// as x-user-id gRPC metadata, so downstream services can apply per-user limits to every call
+// made for the request.
+func RequireAuth(jwtSecret string) gin.HandlerFunc {
+	return RequireAuthWithVerifier(NewHMACVerifier([]byte(jwtSecret)))
+}
+
+// RequireAuthWithVerifier is RequireAuth for tokens checked by verifier.
+func RequireAuthWithVerifier(verifier TokenVerifier) gin.HandlerFunc {
+	return RequireAuthWithRoles(verifier, nil)
+}
+
+// RequireAuthWithRoles is RequireAuthWithVerifier that also stores the user's roles under
+// ContextUserRolesKey and forwards them to the services as x-user-roles gRPC metadata.
+func RequireAuthWithRoles(verifier TokenVerifier, roles *Roles) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenString := bearerToken(c)
+		if tokenString == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: missing bearer token"})
+			return
+		}
+		claims, err := verifier.Authenticate(c.Request.Context(), tokenString)
+		if errors.Is(err, ErrRevocationCheckFailed) {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "Could not verify the access token, please try again later"})
+			return
+		}
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: invalid, expired or revoked token"})
+			return
+		}
+		c.Set(ContextUserIDKey, claims.UserID)
+		c.Set(ContextUsernameKey, claims.Username)
+		c.Set(ContextTokenExpiresAtKey, claims.ExpiresAt)
+		c.Request = c.Request.WithContext(metadata.AppendToOutgoingContext(c.Request.Context(), userIDMetadataKey, claims.UserID))
+		assignRoles(c, roles, claims)
+		c.Next()
+	}
+}
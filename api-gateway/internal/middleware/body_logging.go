@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultBodyLogMaxBytes caps logged request bodies when no limit is configured.
+const DefaultBodyLogMaxBytes = 2048
+
+// redactedValue replaces the values of sensitive fields in logged bodies.
+const redactedValue = "[REDACTED]"
+
+// sensitiveBodyFields are JSON keys whose values are never logged, matched case-insensitively.
+var sensitiveBodyFields = map[string]bool{
+	"password":      true,
+	"new_password":  true,
+	"old_password":  true,
+	"token":         true,
+	"access_token":  true,
+	"refresh_token": true,
+}
+
+// sensitiveFieldPattern finds sensitive string values in bodies that are not valid JSON, such as
+// malformed or truncated ones.
+var sensitiveFieldPattern = regexp.MustCompile(`(?i)("(?:password|new_password|old_password|token|access_token|refresh_token)"\s*:\s*)"(?:[^"\\]|\\.)*("|$)`)
+
+// LogBadRequestBodies returns middleware that logs the body of every request answered with 400,
+// to help debug failed binds. Sensitive fields are redacted and at most maxBytes of the body are
+// logged; 0 or less means DefaultBodyLogMaxBytes.
+func LogBadRequestBodies(maxBytes int) gin.HandlerFunc {
+	if maxBytes <= 0 {
+		maxBytes = DefaultBodyLogMaxBytes
+	}
+	return func(c *gin.Context) {
+		if c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+		captured := &cappedBuffer{limit: maxBytes}
+		c.Request.Body = readCloser{Reader: io.TeeReader(c.Request.Body, captured), Closer: c.Request.Body}
+
+		c.Next()
+
+		if c.Writer.Status() != http.StatusBadRequest {
+			return
+		}
+		// Read whatever the handler left unread, up to the cap.
+		if !captured.full() {
+			io.Copy(io.Discard, io.LimitReader(c.Request.Body, int64(maxBytes)))
+		}
+		suffix := ""
+		if captured.truncated {
+			suffix = " (truncated)"
+		}
+		log.Printf("API Gateway | 400 %s %s request body%s: %s", c.Request.Method, c.Request.URL.Path, suffix, RedactBody(captured.Bytes()))
+	}
+}
+
+// RedactBody replaces the values of sensitive fields in a JSON request body.
+func RedactBody(body []byte) string {
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return sensitiveFieldPattern.ReplaceAllString(string(body), `${1}"`+redactedValue+`"`)
+	}
+	redacted, err := json.Marshal(redactJSONValue(value))
+	if err != nil {
+		return redactedValue
+	}
+	return string(redacted)
+}
+
+func redactJSONValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if sensitiveBodyFields[strings.ToLower(key)] {
+				v[key] = redactedValue
+			} else {
+				v[key] = redactJSONValue(field)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactJSONValue(item)
+		}
+	}
+	return value
+}
+
+// cappedBuffer keeps the first limit bytes written to it and drops the rest.
+type cappedBuffer struct {
+	bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.Len(); room < len(p) {
+		if room > 0 {
+			b.Buffer.Write(p[:room])
+		}
+		b.truncated = true
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
+
+func (b *cappedBuffer) full() bool {
+	return b.truncated || b.Len() >= b.limit
+}
+
+// readCloser combines the tee reader with the original body's Close.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConcurrencyLimiter bounds how many requests to a group of expensive routes, such as the
+// composite endpoints that fan out to several services, are handled at once. Requests beyond the
+// limit are rejected right away instead of queueing up more downstream calls. It is safe for
+// concurrent use. A nil *ConcurrencyLimiter lets every request through.
+type ConcurrencyLimiter struct {
+	slots chan struct{}
+}
+
+// NewConcurrencyLimiter creates a limiter admitting at most maxInFlight requests at once. It
+// returns nil, i.e. no limit, when maxInFlight is 0 or less.
+func NewConcurrencyLimiter(maxInFlight int) *ConcurrencyLimiter {
+	if maxInFlight <= 0 {
+		return nil
+	}
+	return &ConcurrencyLimiter{slots: make(chan struct{}, maxInFlight)}
+}
+
+// InFlight returns the number of requests currently holding a slot.
+func (l *ConcurrencyLimiter) InFlight() int {
+	if l == nil {
+		return 0
+	}
+	return len(l.slots)
+}
+
+// Limit returns middleware that answers 503 with Retry-After when every slot is taken, and
+// otherwise holds a slot until the rest of the chain has finished.
+func (l *ConcurrencyLimiter) Limit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if l == nil {
+			c.Next()
+			return
+		}
+		select {
+		case l.slots <- struct{}{}:
+		default:
+			c.Header("Retry-After", "1")
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "Too many requests for this resource right now, please try again shortly"})
+			return
+		}
+		defer func() { <-l.slots }()
+		c.Next()
+	}
+}
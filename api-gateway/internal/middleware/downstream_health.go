@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// downstreamProbeTimeout bounds each periodic health probe.
+const downstreamProbeTimeout = 2 * time.Second
+
+// HealthChecker is implemented by every downstream gRPC client.
+type HealthChecker interface {
+	HealthCheck(ctx context.Context) (grpc_health_v1.HealthCheckResponse_ServingStatus, error)
+}
+
+// DownstreamHealth caches the last health probe result of each downstream service, so requests
+// to a service that reported NOT_SERVING fail fast with 503 instead of waiting on the call. It is
+// safe for concurrent use. A nil *DownstreamHealth lets every request through.
+type DownstreamHealth struct {
+	checkers map[string]HealthChecker // Keyed by service name, e.g. "pet-service"
+
+	mu       sync.RWMutex
+	statuses map[string]grpc_health_v1.HealthCheckResponse_ServingStatus
+}
+
+// NewDownstreamHealth creates a DownstreamHealth probing checkers. Until the first probe every
+// service is assumed to be serving.
+func NewDownstreamHealth(checkers map[string]HealthChecker) *DownstreamHealth {
+	return &DownstreamHealth{
+		checkers: checkers,
+		statuses: make(map[string]grpc_health_v1.HealthCheckResponse_ServingStatus, len(checkers)),
+	}
+}
+
+// Set records the serving status of a service.
+func (h *DownstreamHealth) Set(service string, servingStatus grpc_health_v1.HealthCheckResponse_ServingStatus) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	previous, known := h.statuses[service]
+	h.statuses[service] = servingStatus
+	if known && previous != servingStatus {
+		log.Printf("API Gateway | Downstream %s is now %s (was %s).", service, servingStatus, previous)
+	}
+}
+
+// Status returns the last known serving status of a service, UNKNOWN if it was never probed.
+func (h *DownstreamHealth) Status(service string) grpc_health_v1.HealthCheckResponse_ServingStatus {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if servingStatus, ok := h.statuses[service]; ok {
+		return servingStatus
+	}
+	return grpc_health_v1.HealthCheckResponse_UNKNOWN
+}
+
+// Probe checks every service once and records the results. A probe that fails, e.g. because the
+// service is unreachable, records UNKNOWN: only an explicit NOT_SERVING short-circuits calls.
+func (h *DownstreamHealth) Probe(ctx context.Context) {
+	var wg sync.WaitGroup
+	for service, checker := range h.checkers {
+		wg.Add(1)
+		go func(service string, checker HealthChecker) {
+			defer wg.Done()
+			probeCtx, cancel := context.WithTimeout(ctx, downstreamProbeTimeout)
+			defer cancel()
+			servingStatus, err := checker.HealthCheck(probeCtx)
+			if err != nil {
+				servingStatus = grpc_health_v1.HealthCheckResponse_UNKNOWN
+			}
+			h.Set(service, servingStatus)
+		}(service, checker)
+	}
+	wg.Wait()
+}
+
+// Run probes every interval until ctx is done.
+func (h *DownstreamHealth) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		h.Probe(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RequireServing returns middleware that answers 503 without calling the handler while any of
+// services last reported NOT_SERVING.
+func (h *DownstreamHealth) RequireServing(services ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if h == nil {
+			c.Next()
+			return
+		}
+		for _, service := range services {
+			if h.Status(service) == grpc_health_v1.HealthCheckResponse_NOT_SERVING {
+				c.Header("Retry-After", "5")
+				c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": service + " is temporarily unavailable"})
+				return
+			}
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwksMinRefreshInterval limits refetches triggered by unknown key IDs, so tokens with made-up
+// key IDs cannot make the gateway hammer the JWKS endpoint.
+const jwksMinRefreshInterval = 10 * time.Second
+
+// jwksFetchTimeout bounds one JWKS request.
+const jwksFetchTimeout = 5 * time.Second
+
+// JWKSCache fetches the user-service's public signing keys from a JWKS endpoint and caches
+// them by key ID. Keys are refetched after ttl, or earlier when a token names an unknown key,
+// which is how a newly rotated-in key is picked up.
+type JWKSCache struct {
+	url    string
+	ttl    time.Duration
+	client *http.Client
+
+	mu          sync.Mutex
+	keys        map[string]*rsa.PublicKey
+	fetchedAt   time.Time // Time of the last successful fetch
+	lastAttempt time.Time // Time of the last fetch, successful or not
+}
+
+// NewJWKSCache creates a cache for the JWKS at url. A nil client uses http.DefaultClient.
+func NewJWKSCache(url string, ttl time.Duration, client *http.Client) *JWKSCache {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &JWKSCache{url: url, ttl: ttl, client: client}
+}
+
+// Key returns the public key with the given key ID.
+func (c *JWKSCache) Key(ctx context.Context, keyID string) (*rsa.PublicKey, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key, known := c.keys[keyID]
+	if known && time.Since(c.fetchedAt) < c.ttl {
+		return key, nil
+	}
+	minInterval := jwksMinRefreshInterval
+	if c.ttl < minInterval {
+		minInterval = c.ttl
+	}
+	if c.lastAttempt.IsZero() || time.Since(c.lastAttempt) >= minInterval {
+		c.lastAttempt = time.Now()
+		if err := c.refresh(ctx); err != nil {
+			if !known {
+				return nil, err
+			}
+			// Keep validating with the cached key while the endpoint is unavailable.
+		} else {
+			key, known = c.keys[keyID]
+		}
+	}
+	if !known {
+		return nil, fmt.Errorf("unknown signing key %q", keyID)
+	}
+	return key, nil
+}
+
+// refresh replaces the cached keys with the current JWKS. c.mu must be held.
+func (c *JWKSCache) refresh(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, jwksFetchTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("fetching JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetching JWKS: unexpected status %d", resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []struct {
+			KeyType  string `json:"kty"`
+			KeyID    string `json:"kid"`
+			Modulus  string `json:"n"`
+			Exponent string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return fmt.Errorf("decoding JWKS: %w", err)
+	}
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.KeyType != "RSA" {
+			continue
+		}
+		n, errN := base64.RawURLEncoding.DecodeString(k.Modulus)
+		e, errE := base64.RawURLEncoding.DecodeString(k.Exponent)
+		if errN != nil || errE != nil || len(e) == 0 || len(e) > 4 {
+			return fmt.Errorf("decoding JWKS key %q: malformed modulus or exponent", k.KeyID)
+		}
+		keys[k.KeyID] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+	c.keys = keys
+	c.fetchedAt = time.Now()
+	return nil
+}
+
+// NewJWKSVerifier verifies RS256 tokens against the key named by their "kid" header.
+func NewJWKSVerifier(cache *JWKSCache) TokenVerifier {
+	return TokenVerifier{
+		algorithm: jwt.SigningMethodRS256.Alg(),
+		keyFunc: func(t *jwt.Token) (interface{}, error) {
+			keyID, _ := t.Header["kid"].(string)
+			if keyID == "" {
+				return nil, errors.New("token has no key ID")
+			}
+			return cache.Key(context.Background(), keyID)
+		},
+	}
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// RequestTracker counts the HTTP requests the gateway is still handling. Once StartDraining is
+// called it also counts how many of them completed, so graceful shutdown can report what it drained.
+type RequestTracker struct {
+	active   atomic.Int64
+	draining atomic.Bool
+	drained  atomic.Int64
+}
+
+// NewRequestTracker creates an empty RequestTracker.
+func NewRequestTracker() *RequestTracker {
+	return &RequestTracker{}
+}
+
+// Active returns the number of requests currently being handled.
+func (t *RequestTracker) Active() int64 { return t.active.Load() }
+
+// StartDraining marks the start of shutdown and returns the number of requests active at that moment.
+func (t *RequestTracker) StartDraining() int64 {
+	t.draining.Store(true)
+	return t.active.Load()
+}
+
+// Drained returns the number of requests that completed after StartDraining.
+func (t *RequestTracker) Drained() int64 { return t.drained.Load() }
+
+// Handler wraps next so every request is counted while it is being handled.
+func (t *RequestTracker) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.active.Add(1)
+		defer func() {
+			t.active.Add(-1)
+			if t.draining.Load() {
+				t.drained.Add(1)
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisRevokedTokens reads the blocklist of revoked access tokens that the user-service keeps in
+// Redis when users log out. Each revoked token is a key made of the prefix and the token's ID,
+// which expires together with the token.
+type RedisRevokedTokens struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisRevokedTokens connects to the Redis database of the user-service's token blocklist.
+// keyPrefix must match the one the user-service writes with.
+func NewRedisRevokedTokens(ctx context.Context, addr, password string, db int, keyPrefix string) (*RedisRevokedTokens, error) {
+	rdb := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: password,
+		DB:       db,
+	})
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		rdb.Close()
+		return nil, err
+	}
+	return &RedisRevokedTokens{client: rdb, prefix: keyPrefix}, nil
+}
+
+// IsTokenRevoked reports whether the token with ID tokenID is on the blocklist.
+func (r *RedisRevokedTokens) IsTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	n, err := r.client.Exists(ctx, r.prefix+tokenID).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// Close closes the Redis connection.
+func (r *RedisRevokedTokens) Close() error {
+	return r.client.Close()
+}
@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/metadata"
+)
+
+// RoleAdmin grants access to the /admin routes and to admin-only operations in the services.
+const RoleAdmin = "admin"
+
+// RoleUser is the role of every regular user. It grants nothing beyond being authenticated, so it
+// is not forwarded to the services.
+const RoleUser = "user"
+
+// ContextUserRolesKey is the Gin context key under which the authenticated user's roles are stored.
+const ContextUserRolesKey = "userRoles"
+
+// userRolesMetadataKey is the gRPC metadata key the services read the caller's roles from.
+const userRolesMetadataKey = "x-user-roles"
+
+// Roles decides the roles of authenticated users besides the role in their token: the users
+// listed in ADMIN_USER_IDS are admins, so operators can bootstrap the first admin without
+// touching the database.
+type Roles struct {
+	adminUserIDs map[string]bool
+}
+
+// NewRoles creates Roles treating the given users as admins.
+func NewRoles(adminUserIDs []string) *Roles {
+	r := &Roles{adminUserIDs: make(map[string]bool, len(adminUserIDs))}
+	for _, id := range adminUserIDs {
+		if id != "" {
+			r.adminUserIDs[id] = true
+		}
+	}
+	return r
+}
+
+// Of returns the roles of the user. A nil *Roles grants no roles.
+func (r *Roles) Of(userID string) []string {
+	if r == nil || !r.adminUserIDs[userID] {
+		return nil
+	}
+	return []string{RoleAdmin}
+}
+
+// HasAdmins reports whether any user is configured as an admin.
+func (r *Roles) HasAdmins() bool {
+	return r != nil && len(r.adminUserIDs) > 0
+}
+
+// assignRoles stores the user's roles, from the token's role claim and from roles, for the
+// handlers and forwards them to the services.
+func assignRoles(c *gin.Context, roles *Roles, claims AccessTokenClaims) {
+	userRoles := roles.Of(claims.UserID)
+	if claims.Role != "" && claims.Role != RoleUser && !slices.Contains(userRoles, claims.Role) {
+		userRoles = append(userRoles, claims.Role)
+	}
+	c.Set(ContextUserRolesKey, userRoles)
+	if len(userRoles) > 0 {
+		ctx := c.Request.Context()
+		for _, role := range userRoles {
+			ctx = metadata.AppendToOutgoingContext(ctx, userRolesMetadataKey, role)
+		}
+		c.Request = c.Request.WithContext(ctx)
+	}
+}
+
+// RequireAdmin returns middleware for the /admin routes. It lets through requests carrying token
+// in the X-Admin-Token header, or a valid bearer token of a user with the admin role. The roles
+// are assigned like RequireAuthWithRoles does, so the role can come from the token's "rol" claim
+// or from ADMIN_USER_IDS, and are forwarded to the services too.
+func RequireAdmin(token string, verifier TokenVerifier, roles *Roles) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if provided := c.GetHeader("X-Admin-Token"); token != "" && provided != "" {
+			if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) == 1 {
+				c.Next()
+				return
+			}
+		}
+		if tokenString := bearerToken(c); tokenString != "" {
+			claims, err := verifier.Authenticate(c.Request.Context(), tokenString)
+			if errors.Is(err, ErrRevocationCheckFailed) {
+				c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "Could not verify the access token, please try again later"})
+				return
+			}
+			if err == nil {
+				assignRoles(c, roles, claims)
+				if slices.Contains(c.GetStringSlice(ContextUserRolesKey), RoleAdmin) {
+					c.Set(ContextUserIDKey, claims.UserID)
+					c.Set(ContextUsernameKey, claims.Username)
+					c.Set(ContextTokenExpiresAtKey, claims.ExpiresAt)
+					c.Request = c.Request.WithContext(metadata.AppendToOutgoingContext(c.Request.Context(), userIDMetadataKey, claims.UserID))
+					c.Next()
+					return
+				}
+			}
+		}
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid or missing admin token"})
+	}
+}
+
+// RequireRole returns middleware that only lets through users with role, whether it comes from
+// their token or from Roles. It must run after RequireAuth, which assigns the roles.
+func RequireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !slices.Contains(c.GetStringSlice(ContextUserRolesKey), role) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Forbidden: the " + role + " role is required"})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,118 @@
+// Package oauth holds the pieces of the OAuth (social login) flow that the gateway validates
+// on its own, starting with the allowlist of redirect URIs.
+package oauth
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// ErrRedirectNotAllowed is returned by Validate for a redirect URI that matches no allowlist entry.
+var ErrRedirectNotAllowed = errors.New("redirect URI is not allowed")
+
+// prefixWildcard ends an allowlist entry that matches every path below it.
+const prefixWildcard = "*"
+
+// RedirectAllowlist is the set of redirect URIs an OAuth flow may send users back to. The zero
+// value allows none.
+type RedirectAllowlist struct {
+	entries []redirectEntry
+}
+
+// redirectEntry is one parsed allowlist entry.
+type redirectEntry struct {
+	raw    string
+	url    *url.URL
+	prefix bool // Match paths starting with url.Path instead of the exact URI
+}
+
+// NewRedirectAllowlist parses the allowed redirect URIs. An entry is an absolute http(s) URI that
+// must be matched exactly, or one ending in "*", e.g. "https://app.example.com/oauth/*", that
+// allows any path below it on the same scheme and host. Plain http is only accepted for localhost.
+func NewRedirectAllowlist(entries []string) (RedirectAllowlist, error) {
+	var allowlist RedirectAllowlist
+	for _, raw := range entries {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		entry := redirectEntry{raw: raw}
+		if strings.HasSuffix(raw, prefixWildcard) {
+			entry.prefix = true
+			raw = strings.TrimSuffix(raw, prefixWildcard)
+		}
+		u, err := parseRedirect(raw)
+		if err != nil {
+			return RedirectAllowlist{}, fmt.Errorf("invalid redirect allowlist entry %q: %w", entry.raw, err)
+		}
+		if entry.prefix && u.RawQuery != "" {
+			return RedirectAllowlist{}, fmt.Errorf("invalid redirect allowlist entry %q: a prefix entry cannot have a query", entry.raw)
+		}
+		if u.Scheme == "http" && u.Hostname() != "localhost" && u.Hostname() != "127.0.0.1" {
+			return RedirectAllowlist{}, fmt.Errorf("invalid redirect allowlist entry %q: only localhost may use http", entry.raw)
+		}
+		entry.url = u
+		allowlist.entries = append(allowlist.entries, entry)
+	}
+	return allowlist, nil
+}
+
+// Len returns the number of allowlist entries.
+func (a RedirectAllowlist) Len() int {
+	return len(a.entries)
+}
+
+// Validate returns nil if redirect matches an allowlist entry and an error wrapping
+// ErrRedirectNotAllowed otherwise. Scheme and host compare case-insensitively; the path,
+// query and port must match exactly, or for a prefix entry the path must lie below the prefix.
+func (a RedirectAllowlist) Validate(redirect string) error {
+	u, err := parseRedirect(redirect)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrRedirectNotAllowed, err)
+	}
+	// "/oauth/../admin" must not pass as being below "/oauth/".
+	if cleaned := path.Clean("/" + u.Path); cleaned != u.Path && cleaned+"/" != u.Path {
+		return fmt.Errorf("%w: path is not canonical", ErrRedirectNotAllowed)
+	}
+	for _, entry := range a.entries {
+		if entry.matches(u) {
+			return nil
+		}
+	}
+	return ErrRedirectNotAllowed
+}
+
+func (e redirectEntry) matches(u *url.URL) bool {
+	if !strings.EqualFold(e.url.Scheme, u.Scheme) || !strings.EqualFold(e.url.Host, u.Host) {
+		return false
+	}
+	if e.prefix {
+		return strings.HasPrefix(u.Path, e.url.Path)
+	}
+	return e.url.Path == u.Path && e.url.RawQuery == u.RawQuery
+}
+
+// parseRedirect parses an absolute http(s) URI without credentials or a fragment.
+func parseRedirect(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case u.Scheme != "https" && u.Scheme != "http":
+		return nil, errors.New("scheme must be http or https")
+	case u.Host == "":
+		return nil, errors.New("host is required")
+	case u.User != nil:
+		return nil, errors.New("credentials are not allowed")
+	case u.Fragment != "" || strings.Contains(raw, "#"):
+		return nil, errors.New("fragments are not allowed")
+	}
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	return u, nil
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/zhandarbeks/petstore-final-project/api-gateway/internal/handler" // Adjust import path
+	"github.com/zhandarbeks/petstore-final-project/api-gateway/internal/middleware"
 
 	// For Swagger (if you integrate it later)
 	// swaggerFiles "github.com/swaggo/files"
@@ -20,24 +21,45 @@ func New(
 	userHandler *handler.UserHandler,
 	petHandler *handler.PetHandler,
 	adoptionHandler *handler.AdoptionHandler,
+	compositeHandler *handler.CompositeHandler, // Endpoints that aggregate several services
+	adminHandler *handler.AdminHandler, // Operator endpoints for the gateway itself
+	healthHandler *handler.HealthHandler, // Readiness based on the downstream services
+	maintenance *middleware.Maintenance, // Maintenance mode state, toggled via adminHandler
+	adminMiddleware gin.HandlerFunc, // Guards the /admin routes, e.g. middleware.RequireAdmin
+	authMiddleware gin.HandlerFunc, // Validates bearer tokens for /users/me routes
+	badRequestLogger gin.HandlerFunc, // Logs the bodies of requests answered with 400; nil disables it
+	accessLogger gin.HandlerFunc, // One structured line per request, e.g. middleware.AccessLog; nil falls back to gin's default logger
+	downstreamHealth *middleware.DownstreamHealth, // Last probed downstream health; calls to a NOT_SERVING service fail fast. nil disables it
+	compositeLimiter *middleware.ConcurrencyLimiter, // Bounds concurrent composite requests, which fan out to several services; nil disables it
 	// authMiddleware gin.HandlerFunc, // Placeholder for your auth middleware
 ) *gin.Engine {
 	router := gin.New() // Create a new Gin engine without default middleware
 
 	// --- Global Middleware ---
-	// Logger middleware will write the logs to gin.DefaultWriter even if you run with "release" mode.
-	router.Use(gin.Logger())
+	// Access log middleware also assigns the request ID, so it runs before everything else.
+	// Without one, gin's logger writes the logs to gin.DefaultWriter even in "release" mode.
+	if accessLogger != nil {
+		router.Use(accessLogger)
+	} else {
+		router.Use(gin.Logger())
+	}
 	// Recovery middleware recovers from any panics and writes a 500 if there was one.
 	router.Use(gin.Recovery())
 	// CORS middleware
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"*"}, // Allow all origins for simplicity, restrict in production
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", middleware.RequestIDHeader},
+		ExposeHeaders:    []string{"Content-Length", middleware.RequestIDHeader},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
+	// Maintenance middleware rejects writes (read_only) or everything (full) with 503.
+	// /health and /admin/* are always let through.
+	router.Use(maintenance.Handler())
+	if badRequestLogger != nil {
+		router.Use(badRequestLogger)
+	}
 
 	// --- Swagger Documentation Route (if you integrate Swag) ---
 	// router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
@@ -45,12 +67,20 @@ func New(
 
 	// --- API Versioning (Optional but good practice) ---
 	apiV1 := router.Group("/api/v1")
+	// Composite endpoints share one limiter: past it they answer 503 instead of piling up backend calls.
+	limitComposite := compositeLimiter.Limit()
+	// Staff-only endpoints: authenticated users whose token or ADMIN_USER_IDS makes them admins.
+	requireAdminRole := middleware.RequireRole(middleware.RoleAdmin)
 	{
 		// --- User Routes ---
-		users := apiV1.Group("/users")
+		// Each group fails fast with 503 while the service it is named after reports NOT_SERVING.
+		users := apiV1.Group("/users", downstreamHealth.RequireServing("user-service"))
 		{
 			users.POST("/register", userHandler.RegisterUser)
 			users.POST("/login", userHandler.LoginUser)
+			users.POST("/refresh", userHandler.RefreshToken) // Rotates the refresh token
+			users.POST("/logout", authMiddleware, userHandler.LogoutUser) // Revokes the bearer token until it expires
+			users.GET("/token/verify", authMiddleware, userHandler.VerifyToken) // Claims of the bearer token, 401 if invalid
 
 			// Routes that might require authentication
 			// authRequiredUsers := users.Group("/")
@@ -61,18 +91,38 @@ func New(
 			// 	authRequiredUsers.DELETE("/:userId", userHandler.DeleteUser)
 			// 	authRequiredUsers.GET("/:userId/adoptions", adoptionHandler.ListUserAdoptionApplications) // Moved here as it's user-specific
 			// }
+			// Routes for the authenticated user ("me"), resolved from the bearer token
+			me := users.Group("/me")
+			me.Use(authMiddleware)
+			{
+				me.GET("/favorites", userHandler.ListMyFavoritePets)
+				me.GET("/favorites/detail", limitComposite, compositeHandler.GetMyFavoritePetsDetail) // Favorites with full pet details
+				me.GET("/dashboard", limitComposite, compositeHandler.GetMyDashboard)                 // Profile, listed pets, applications and favorites
+				me.PUT("/favorites/:petId", userHandler.AddMyFavoritePet)
+				me.DELETE("/favorites/:petId", userHandler.RemoveMyFavoritePet)
+				me.DELETE("/data", compositeHandler.DeleteMyData) // Withdraw consent: cancel pending applications and anonymize the account
+			}
+
 			// For now, without auth middleware for simplicity in initial setup:
 			users.GET("/:userId", userHandler.GetUser)
 			users.PATCH("/:userId", userHandler.UpdateUserProfile)
 			users.DELETE("/:userId", userHandler.DeleteUser)
-			users.GET("/:userId/adoptions", adoptionHandler.ListUserAdoptionApplications)
+			users.GET("/:userId/adoptions", downstreamHealth.RequireServing("adoption-service"), adoptionHandler.ListUserAdoptionApplications)
 		}
 
 		// --- Pet Routes ---
-		pets := apiV1.Group("/pets")
+		pets := apiV1.Group("/pets", downstreamHealth.RequireServing("pet-service"))
 		{
 			pets.GET("", petHandler.ListPets)       // List all pets (public)
+			pets.GET("/recently-adopted", petHandler.ListRecentlyAdopted) // Showcase of recent adoptions (public)
+			pets.GET("/facets", petHandler.GetPetFacets)                  // Distinct species/breeds/statuses with counts (public)
+			pets.GET("/breeds/suggest", petHandler.SuggestBreeds)         // Breed autocomplete for a species (public)
+			pets.GET("/browse", petHandler.BrowsePets)                    // Listing with preset defaults, e.g. available newest-first (public)
 			pets.GET("/:petId", petHandler.GetPet) // Get a specific pet (public)
+			pets.GET("/:petId/details", limitComposite, compositeHandler.GetPetDetails) // Pet with its lister's public profile (public)
+			pets.GET("/:petId/overview", limitComposite, compositeHandler.GetPetOverview) // Pet with its application stats (public)
+			pets.GET("/:petId/history", petHandler.GetPetHistory)          // Status changes with their reasons (public)
+			pets.GET("/:petId/adoptions", authMiddleware, downstreamHealth.RequireServing("adoption-service"), compositeHandler.ListPetAdoptionApplications) // Lister or admin
 
 			// Routes that might require authentication (e.g., for creating/modifying pets)
 			// authRequiredPets := pets.Group("/")
@@ -84,14 +134,23 @@ func New(
 			// 	authRequiredPets.PATCH("/:petId/status", petHandler.UpdatePetAdoptionStatus) // Admin or specific role
 			// }
 			// For now, without auth middleware:
-			pets.POST("", petHandler.CreatePet)
-			pets.PATCH("/:petId", petHandler.UpdatePet)
-			pets.DELETE("/:petId", petHandler.DeletePet)
-			pets.PATCH("/:petId/status", petHandler.UpdatePetAdoptionStatus)
+			pets.POST("", authMiddleware, petHandler.CreatePet) // Listed by the caller; shelters and admins may set the initial status
+			pets.PATCH("/:petId", authMiddleware, petHandler.UpdatePet)  // Owner only
+			pets.DELETE("/:petId", authMiddleware, petHandler.DeletePet) // Owner only
+			pets.POST("/:petId/restore", authMiddleware, petHandler.RestorePet) // Owner or admin; undoes DELETE
+			pets.PATCH("/:petId/status", authMiddleware, requireAdminRole, petHandler.UpdatePetAdoptionStatus)
+			pets.POST("/:petId/images/upload-url", authMiddleware, petHandler.GetImageUploadURL) // Owner only
+			pets.POST("/:petId/images", authMiddleware, petHandler.AddImageURLs)                 // Owner only; URLs must come from upload-url
+			pets.POST("/:petId/tags", authMiddleware, petHandler.AddPetTags)        // Owner only
+			pets.DELETE("/:petId/tags/:tag", authMiddleware, petHandler.RemovePetTag) // Owner only
+			pets.POST("/:petId/transfer", authMiddleware, petHandler.TransferPetListing) // Current owner only
 		}
 
+		// --- Meta Routes ---
+		apiV1.GET("/meta/enums", handler.ListEnums) // Valid status and query option values for clients (public)
+
 		// --- Adoption Routes ---
-		adoptions := apiV1.Group("/adoptions")
+		adoptions := apiV1.Group("/adoptions", downstreamHealth.RequireServing("adoption-service"))
 		// authRequiredAdoptions := adoptions.Group("/")
 		// authRequiredAdoptions.Use(authMiddleware)
 		// {
@@ -101,16 +160,35 @@ func New(
 		// }
 		// For now, without auth middleware:
 		{
-			adoptions.POST("", adoptionHandler.CreateAdoptionApplication)
+			adoptions.POST("", authMiddleware, adoptionHandler.CreateAdoptionApplication) // The caller applies; trusted users may be auto-approved
 			adoptions.GET("/:applicationId", adoptionHandler.GetAdoptionApplication)
-			adoptions.PATCH("/:applicationId/status", adoptionHandler.UpdateAdoptionApplicationStatus)
+			adoptions.DELETE("/:applicationId", authMiddleware, adoptionHandler.CancelAdoptionApplication) // Applicant only
+			adoptions.GET("/:applicationId/details", authMiddleware, limitComposite, compositeHandler.GetAdoptionApplicationDetails) // Applicant contact for the lister or admin once approved
+			adoptions.PATCH("/:applicationId/status", authMiddleware, requireAdminRole, adoptionHandler.UpdateAdoptionApplicationStatus) // Staff only
+			adoptions.POST("/:applicationId/attachments", authMiddleware, adoptionHandler.AddApplicationAttachment)     // Applicant only
+			adoptions.DELETE("/:applicationId/attachments", authMiddleware, adoptionHandler.RemoveApplicationAttachment) // Applicant only
 		}
 	}
 
+	// --- Admin Routes ---
+	admin := router.Group("/admin")
+	admin.Use(adminMiddleware)
+	{
+		admin.GET("/maintenance", adminHandler.GetMaintenance)
+		admin.PUT("/maintenance", adminHandler.SetMaintenance)
+		admin.GET("/pets", petHandler.AdminListPets) // Pet listing that can include deleted pets
+		admin.PUT("/pets/:petId/status", petHandler.AdminSetPetStatus)
+		admin.POST("/adoptions/:applicationId/reopen", adoptionHandler.ReopenApplication)
+		admin.GET("/reports/pets-needing-attention", limitComposite, compositeHandler.ListPetsNeedingAttention) // Long-listed AVAILABLE pets without applications
+		admin.GET("/notifications/preview", adminHandler.PreviewNotification)
+	}
+
 	// Health Check Endpoint (optional, but good for orchestrators)
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"status": "UP"}) // http.StatusOK was undefined
 	})
+	// Readiness reports each downstream service's health and fails with 503 if one is down
+	router.GET("/readyz", healthHandler.Readyz)
 
 	return router
 }
\ No newline at end of file
@@ -0,0 +1,45 @@
+// Package server holds the lifecycle helpers for the gateway's HTTP server.
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/zhandarbeks/petstore-final-project/api-gateway/internal/middleware"
+)
+
+// DrainStats describes what a graceful shutdown did with the requests in flight.
+type DrainStats struct {
+	ActiveAtShutdown int64 // Requests being handled when shutdown began
+	Drained          int64 // Requests that completed during shutdown
+	Forced           bool  // The timeout passed and the remaining connections were closed
+}
+
+// Shutdown stops srv from accepting new requests and waits up to timeout for the active ones,
+// counted by tracker, to finish. If they do not finish in time, the remaining connections are
+// closed so the process can exit.
+func Shutdown(srv *http.Server, tracker *middleware.RequestTracker, timeout time.Duration) (DrainStats, error) {
+	stats := DrainStats{ActiveAtShutdown: tracker.StartDraining()}
+	log.Printf("API Gateway | Draining HTTP server. active=%d timeout=%s", stats.ActiveAtShutdown, timeout)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	err := srv.Shutdown(ctx)
+	if errors.Is(err, context.DeadlineExceeded) {
+		stats.Forced = true
+		log.Printf("API Gateway | Shutdown timeout of %s reached with %d requests still active. Forcing close.", timeout, tracker.Active())
+		err = srv.Close()
+	}
+	stats.Drained = tracker.Drained()
+	if err != nil {
+		return stats, fmt.Errorf("shutting down HTTP server: %w", err)
+	}
+
+	log.Printf("API Gateway | HTTP server stopped. active_at_shutdown=%d drained=%d forced=%t", stats.ActiveAtShutdown, stats.Drained, stats.Forced)
+	return stats, nil
+}
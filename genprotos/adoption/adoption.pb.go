@@ -78,15 +78,18 @@ func (ApplicationStatus) EnumDescriptor() ([]byte, []int) {
 }
 
 type AdoptionApplication struct {
-	state            protoimpl.MessageState `protogen:"open.v1"`
-	Id               string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	UserId           string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	PetId            string                 `protobuf:"bytes,3,opt,name=pet_id,json=petId,proto3" json:"pet_id,omitempty"`
-	Status           ApplicationStatus      `protobuf:"varint,4,opt,name=status,proto3,enum=adoption.ApplicationStatus" json:"status,omitempty"`
-	ApplicationNotes string                 `protobuf:"bytes,5,opt,name=application_notes,json=applicationNotes,proto3" json:"application_notes,omitempty"`
-	ReviewNotes      string                 `protobuf:"bytes,6,opt,name=review_notes,json=reviewNotes,proto3" json:"review_notes,omitempty"`
-	CreatedAt        *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // Or use string if preferred
-	UpdatedAt        *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"` // Or use string
+	state            protoimpl.MessageState     `protogen:"open.v1"`
+	Id               string                     `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId           string                     `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	PetId            string                     `protobuf:"bytes,3,opt,name=pet_id,json=petId,proto3" json:"pet_id,omitempty"`
+	Status           ApplicationStatus          `protobuf:"varint,4,opt,name=status,proto3,enum=adoption.ApplicationStatus" json:"status,omitempty"`
+	ApplicationNotes string                     `protobuf:"bytes,5,opt,name=application_notes,json=applicationNotes,proto3" json:"application_notes,omitempty"`
+	ReviewNotes      string                     `protobuf:"bytes,6,opt,name=review_notes,json=reviewNotes,proto3" json:"review_notes,omitempty"`
+	CreatedAt        *timestamppb.Timestamp     `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`              // Or use string if preferred
+	UpdatedAt        *timestamppb.Timestamp     `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`              // Or use string
+	Flagged          bool                       `protobuf:"varint,9,opt,name=flagged,proto3" json:"flagged,omitempty"`                                  // Applicant applied for many pets in a short window (anti-fraud); not a rejection
+	StatusHistory    []*ApplicationStatusChange `protobuf:"bytes,10,rep,name=status_history,json=statusHistory,proto3" json:"status_history,omitempty"` // Administrative status changes, oldest first
+	Attachments      []*Attachment              `protobuf:"bytes,11,rep,name=attachments,proto3" json:"attachments,omitempty"`                          // Documents supplied by the applicant
 	unknownFields    protoimpl.UnknownFields
 	sizeCache        protoimpl.SizeCache
 }
@@ -177,6 +180,156 @@ func (x *AdoptionApplication) GetUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *AdoptionApplication) GetFlagged() bool {
+	if x != nil {
+		return x.Flagged
+	}
+	return false
+}
+
+func (x *AdoptionApplication) GetStatusHistory() []*ApplicationStatusChange {
+	if x != nil {
+		return x.StatusHistory
+	}
+	return nil
+}
+
+func (x *AdoptionApplication) GetAttachments() []*Attachment {
+	if x != nil {
+		return x.Attachments
+	}
+	return nil
+}
+
+// A document uploaded to object storage and attached to an application.
+type Attachment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Filename      string                 `protobuf:"bytes,2,opt,name=filename,proto3" json:"filename,omitempty"`
+	UploadedAt    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=uploaded_at,json=uploadedAt,proto3" json:"uploaded_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Attachment) Reset() {
+	*x = Attachment{}
+	mi := &file_adoption_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Attachment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Attachment) ProtoMessage() {}
+
+func (x *Attachment) ProtoReflect() protoreflect.Message {
+	mi := &file_adoption_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Attachment.ProtoReflect.Descriptor instead.
+func (*Attachment) Descriptor() ([]byte, []int) {
+	return file_adoption_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Attachment) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Attachment) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *Attachment) GetUploadedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UploadedAt
+	}
+	return nil
+}
+
+type ApplicationStatusChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FromStatus    ApplicationStatus      `protobuf:"varint,1,opt,name=from_status,json=fromStatus,proto3,enum=adoption.ApplicationStatus" json:"from_status,omitempty"`
+	ToStatus      ApplicationStatus      `protobuf:"varint,2,opt,name=to_status,json=toStatus,proto3,enum=adoption.ApplicationStatus" json:"to_status,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	ChangedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=changed_at,json=changedAt,proto3" json:"changed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApplicationStatusChange) Reset() {
+	*x = ApplicationStatusChange{}
+	mi := &file_adoption_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApplicationStatusChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApplicationStatusChange) ProtoMessage() {}
+
+func (x *ApplicationStatusChange) ProtoReflect() protoreflect.Message {
+	mi := &file_adoption_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApplicationStatusChange.ProtoReflect.Descriptor instead.
+func (*ApplicationStatusChange) Descriptor() ([]byte, []int) {
+	return file_adoption_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ApplicationStatusChange) GetFromStatus() ApplicationStatus {
+	if x != nil {
+		return x.FromStatus
+	}
+	return ApplicationStatus_APPLICATION_STATUS_UNSPECIFIED
+}
+
+func (x *ApplicationStatusChange) GetToStatus() ApplicationStatus {
+	if x != nil {
+		return x.ToStatus
+	}
+	return ApplicationStatus_APPLICATION_STATUS_UNSPECIFIED
+}
+
+func (x *ApplicationStatusChange) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ApplicationStatusChange) GetChangedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ChangedAt
+	}
+	return nil
+}
+
 type CreateAdoptionApplicationRequest struct {
 	state            protoimpl.MessageState `protogen:"open.v1"`
 	UserId           string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
@@ -188,7 +341,7 @@ type CreateAdoptionApplicationRequest struct {
 
 func (x *CreateAdoptionApplicationRequest) Reset() {
 	*x = CreateAdoptionApplicationRequest{}
-	mi := &file_adoption_proto_msgTypes[1]
+	mi := &file_adoption_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -200,7 +353,7 @@ func (x *CreateAdoptionApplicationRequest) String() string {
 func (*CreateAdoptionApplicationRequest) ProtoMessage() {}
 
 func (x *CreateAdoptionApplicationRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_adoption_proto_msgTypes[1]
+	mi := &file_adoption_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -213,7 +366,7 @@ func (x *CreateAdoptionApplicationRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateAdoptionApplicationRequest.ProtoReflect.Descriptor instead.
 func (*CreateAdoptionApplicationRequest) Descriptor() ([]byte, []int) {
-	return file_adoption_proto_rawDescGZIP(), []int{1}
+	return file_adoption_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *CreateAdoptionApplicationRequest) GetUserId() string {
@@ -246,7 +399,7 @@ type GetAdoptionApplicationRequest struct {
 
 func (x *GetAdoptionApplicationRequest) Reset() {
 	*x = GetAdoptionApplicationRequest{}
-	mi := &file_adoption_proto_msgTypes[2]
+	mi := &file_adoption_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -258,7 +411,7 @@ func (x *GetAdoptionApplicationRequest) String() string {
 func (*GetAdoptionApplicationRequest) ProtoMessage() {}
 
 func (x *GetAdoptionApplicationRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_adoption_proto_msgTypes[2]
+	mi := &file_adoption_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -271,7 +424,7 @@ func (x *GetAdoptionApplicationRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetAdoptionApplicationRequest.ProtoReflect.Descriptor instead.
 func (*GetAdoptionApplicationRequest) Descriptor() ([]byte, []int) {
-	return file_adoption_proto_rawDescGZIP(), []int{2}
+	return file_adoption_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *GetAdoptionApplicationRequest) GetApplicationId() string {
@@ -285,14 +438,14 @@ type UpdateAdoptionApplicationStatusRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ApplicationId string                 `protobuf:"bytes,1,opt,name=application_id,json=applicationId,proto3" json:"application_id,omitempty"`
 	NewStatus     ApplicationStatus      `protobuf:"varint,2,opt,name=new_status,json=newStatus,proto3,enum=adoption.ApplicationStatus" json:"new_status,omitempty"`
-	ReviewNotes   string                 `protobuf:"bytes,3,opt,name=review_notes,json=reviewNotes,proto3" json:"review_notes,omitempty"`
+	ReviewNotes   *string                `protobuf:"bytes,3,opt,name=review_notes,json=reviewNotes,proto3,oneof" json:"review_notes,omitempty"` // Unset keeps the current notes; an empty string clears them
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *UpdateAdoptionApplicationStatusRequest) Reset() {
 	*x = UpdateAdoptionApplicationStatusRequest{}
-	mi := &file_adoption_proto_msgTypes[3]
+	mi := &file_adoption_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -304,7 +457,7 @@ func (x *UpdateAdoptionApplicationStatusRequest) String() string {
 func (*UpdateAdoptionApplicationStatusRequest) ProtoMessage() {}
 
 func (x *UpdateAdoptionApplicationStatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_adoption_proto_msgTypes[3]
+	mi := &file_adoption_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -317,7 +470,7 @@ func (x *UpdateAdoptionApplicationStatusRequest) ProtoReflect() protoreflect.Mes
 
 // Deprecated: Use UpdateAdoptionApplicationStatusRequest.ProtoReflect.Descriptor instead.
 func (*UpdateAdoptionApplicationStatusRequest) Descriptor() ([]byte, []int) {
-	return file_adoption_proto_rawDescGZIP(), []int{3}
+	return file_adoption_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *UpdateAdoptionApplicationStatusRequest) GetApplicationId() string {
@@ -335,37 +488,35 @@ func (x *UpdateAdoptionApplicationStatusRequest) GetNewStatus() ApplicationStatu
 }
 
 func (x *UpdateAdoptionApplicationStatusRequest) GetReviewNotes() string {
-	if x != nil {
-		return x.ReviewNotes
+	if x != nil && x.ReviewNotes != nil {
+		return *x.ReviewNotes
 	}
 	return ""
 }
 
-type ListUserAdoptionApplicationsRequest struct {
+type ReopenApplicationRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Page          *int32                 `protobuf:"varint,2,opt,name=page,proto3,oneof" json:"page,omitempty"`
-	Limit         *int32                 `protobuf:"varint,3,opt,name=limit,proto3,oneof" json:"limit,omitempty"`
-	StatusFilter  *ApplicationStatus     `protobuf:"varint,4,opt,name=status_filter,json=statusFilter,proto3,enum=adoption.ApplicationStatus,oneof" json:"status_filter,omitempty"`
+	ApplicationId string                 `protobuf:"bytes,1,opt,name=application_id,json=applicationId,proto3" json:"application_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListUserAdoptionApplicationsRequest) Reset() {
-	*x = ListUserAdoptionApplicationsRequest{}
-	mi := &file_adoption_proto_msgTypes[4]
+func (x *ReopenApplicationRequest) Reset() {
+	*x = ReopenApplicationRequest{}
+	mi := &file_adoption_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListUserAdoptionApplicationsRequest) String() string {
+func (x *ReopenApplicationRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListUserAdoptionApplicationsRequest) ProtoMessage() {}
+func (*ReopenApplicationRequest) ProtoMessage() {}
 
-func (x *ListUserAdoptionApplicationsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_adoption_proto_msgTypes[4]
+func (x *ReopenApplicationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_adoption_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -376,64 +527,49 @@ func (x *ListUserAdoptionApplicationsRequest) ProtoReflect() protoreflect.Messag
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListUserAdoptionApplicationsRequest.ProtoReflect.Descriptor instead.
-func (*ListUserAdoptionApplicationsRequest) Descriptor() ([]byte, []int) {
-	return file_adoption_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use ReopenApplicationRequest.ProtoReflect.Descriptor instead.
+func (*ReopenApplicationRequest) Descriptor() ([]byte, []int) {
+	return file_adoption_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *ListUserAdoptionApplicationsRequest) GetUserId() string {
+func (x *ReopenApplicationRequest) GetApplicationId() string {
 	if x != nil {
-		return x.UserId
+		return x.ApplicationId
 	}
 	return ""
 }
 
-func (x *ListUserAdoptionApplicationsRequest) GetPage() int32 {
-	if x != nil && x.Page != nil {
-		return *x.Page
-	}
-	return 0
-}
-
-func (x *ListUserAdoptionApplicationsRequest) GetLimit() int32 {
-	if x != nil && x.Limit != nil {
-		return *x.Limit
-	}
-	return 0
-}
-
-func (x *ListUserAdoptionApplicationsRequest) GetStatusFilter() ApplicationStatus {
-	if x != nil && x.StatusFilter != nil {
-		return *x.StatusFilter
+func (x *ReopenApplicationRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
 	}
-	return ApplicationStatus_APPLICATION_STATUS_UNSPECIFIED
+	return ""
 }
 
-type ListAdoptionApplicationsResponse struct {
+type AddApplicationAttachmentRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Applications  []*AdoptionApplication `protobuf:"bytes,1,rep,name=applications,proto3" json:"applications,omitempty"`
-	TotalCount    int32                  `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
-	Page          int32                  `protobuf:"varint,3,opt,name=page,proto3" json:"page,omitempty"`
-	Limit         int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	ApplicationId string                 `protobuf:"bytes,1,opt,name=application_id,json=applicationId,proto3" json:"application_id,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"` // Public URL of the uploaded file
+	Filename      string                 `protobuf:"bytes,3,opt,name=filename,proto3" json:"filename,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListAdoptionApplicationsResponse) Reset() {
-	*x = ListAdoptionApplicationsResponse{}
-	mi := &file_adoption_proto_msgTypes[5]
+func (x *AddApplicationAttachmentRequest) Reset() {
+	*x = AddApplicationAttachmentRequest{}
+	mi := &file_adoption_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListAdoptionApplicationsResponse) String() string {
+func (x *AddApplicationAttachmentRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListAdoptionApplicationsResponse) ProtoMessage() {}
+func (*AddApplicationAttachmentRequest) ProtoMessage() {}
 
-func (x *ListAdoptionApplicationsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_adoption_proto_msgTypes[5]
+func (x *AddApplicationAttachmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_adoption_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -444,61 +580,106 @@ func (x *ListAdoptionApplicationsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListAdoptionApplicationsResponse.ProtoReflect.Descriptor instead.
-func (*ListAdoptionApplicationsResponse) Descriptor() ([]byte, []int) {
-	return file_adoption_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use AddApplicationAttachmentRequest.ProtoReflect.Descriptor instead.
+func (*AddApplicationAttachmentRequest) Descriptor() ([]byte, []int) {
+	return file_adoption_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *ListAdoptionApplicationsResponse) GetApplications() []*AdoptionApplication {
+func (x *AddApplicationAttachmentRequest) GetApplicationId() string {
 	if x != nil {
-		return x.Applications
+		return x.ApplicationId
 	}
-	return nil
+	return ""
 }
 
-func (x *ListAdoptionApplicationsResponse) GetTotalCount() int32 {
+func (x *AddApplicationAttachmentRequest) GetUrl() string {
 	if x != nil {
-		return x.TotalCount
+		return x.Url
 	}
-	return 0
+	return ""
 }
 
-func (x *ListAdoptionApplicationsResponse) GetPage() int32 {
+func (x *AddApplicationAttachmentRequest) GetFilename() string {
 	if x != nil {
-		return x.Page
+		return x.Filename
 	}
-	return 0
+	return ""
 }
 
-func (x *ListAdoptionApplicationsResponse) GetLimit() int32 {
+type RemoveApplicationAttachmentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApplicationId string                 `protobuf:"bytes,1,opt,name=application_id,json=applicationId,proto3" json:"application_id,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveApplicationAttachmentRequest) Reset() {
+	*x = RemoveApplicationAttachmentRequest{}
+	mi := &file_adoption_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveApplicationAttachmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveApplicationAttachmentRequest) ProtoMessage() {}
+
+func (x *RemoveApplicationAttachmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_adoption_proto_msgTypes[8]
 	if x != nil {
-		return x.Limit
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-type AdoptionApplicationResponse struct {
+// Deprecated: Use RemoveApplicationAttachmentRequest.ProtoReflect.Descriptor instead.
+func (*RemoveApplicationAttachmentRequest) Descriptor() ([]byte, []int) {
+	return file_adoption_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RemoveApplicationAttachmentRequest) GetApplicationId() string {
+	if x != nil {
+		return x.ApplicationId
+	}
+	return ""
+}
+
+func (x *RemoveApplicationAttachmentRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+type CancelUserApplicationsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Application   *AdoptionApplication   `protobuf:"bytes,1,opt,name=application,proto3" json:"application,omitempty"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AdoptionApplicationResponse) Reset() {
-	*x = AdoptionApplicationResponse{}
-	mi := &file_adoption_proto_msgTypes[6]
+func (x *CancelUserApplicationsRequest) Reset() {
+	*x = CancelUserApplicationsRequest{}
+	mi := &file_adoption_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AdoptionApplicationResponse) String() string {
+func (x *CancelUserApplicationsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AdoptionApplicationResponse) ProtoMessage() {}
+func (*CancelUserApplicationsRequest) ProtoMessage() {}
 
-func (x *AdoptionApplicationResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_adoption_proto_msgTypes[6]
+func (x *CancelUserApplicationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_adoption_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -509,24 +690,637 @@ func (x *AdoptionApplicationResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AdoptionApplicationResponse.ProtoReflect.Descriptor instead.
-func (*AdoptionApplicationResponse) Descriptor() ([]byte, []int) {
-	return file_adoption_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use CancelUserApplicationsRequest.ProtoReflect.Descriptor instead.
+func (*CancelUserApplicationsRequest) Descriptor() ([]byte, []int) {
+	return file_adoption_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *AdoptionApplicationResponse) GetApplication() *AdoptionApplication {
+func (x *CancelUserApplicationsRequest) GetUserId() string {
 	if x != nil {
-		return x.Application
+		return x.UserId
 	}
-	return nil
+	return ""
 }
 
-var File_adoption_proto protoreflect.FileDescriptor
+type CancelUserApplicationsResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	CancelledCount int32                  `protobuf:"varint,1,opt,name=cancelled_count,json=cancelledCount,proto3" json:"cancelled_count,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
 
-const file_adoption_proto_rawDesc = "" +
-	"\n" +
-	"\x0eadoption.proto\x12\badoption\x1a\x1fgoogle/protobuf/timestamp.proto\"\xd0\x02\n" +
-	"\x13AdoptionApplication\x12\x0e\n" +
+func (x *CancelUserApplicationsResponse) Reset() {
+	*x = CancelUserApplicationsResponse{}
+	mi := &file_adoption_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelUserApplicationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelUserApplicationsResponse) ProtoMessage() {}
+
+func (x *CancelUserApplicationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_adoption_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelUserApplicationsResponse.ProtoReflect.Descriptor instead.
+func (*CancelUserApplicationsResponse) Descriptor() ([]byte, []int) {
+	return file_adoption_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CancelUserApplicationsResponse) GetCancelledCount() int32 {
+	if x != nil {
+		return x.CancelledCount
+	}
+	return 0
+}
+
+type CancelAdoptionApplicationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApplicationId string                 `protobuf:"bytes,1,opt,name=application_id,json=applicationId,proto3" json:"application_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // Must be the applicant and the caller (x-user-id metadata)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelAdoptionApplicationRequest) Reset() {
+	*x = CancelAdoptionApplicationRequest{}
+	mi := &file_adoption_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelAdoptionApplicationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelAdoptionApplicationRequest) ProtoMessage() {}
+
+func (x *CancelAdoptionApplicationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_adoption_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelAdoptionApplicationRequest.ProtoReflect.Descriptor instead.
+func (*CancelAdoptionApplicationRequest) Descriptor() ([]byte, []int) {
+	return file_adoption_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CancelAdoptionApplicationRequest) GetApplicationId() string {
+	if x != nil {
+		return x.ApplicationId
+	}
+	return ""
+}
+
+func (x *CancelAdoptionApplicationRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type ListUserAdoptionApplicationsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Page          *int32                 `protobuf:"varint,2,opt,name=page,proto3,oneof" json:"page,omitempty"`
+	Limit         *int32                 `protobuf:"varint,3,opt,name=limit,proto3,oneof" json:"limit,omitempty"`
+	StatusFilter  *ApplicationStatus     `protobuf:"varint,4,opt,name=status_filter,json=statusFilter,proto3,enum=adoption.ApplicationStatus,oneof" json:"status_filter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUserAdoptionApplicationsRequest) Reset() {
+	*x = ListUserAdoptionApplicationsRequest{}
+	mi := &file_adoption_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUserAdoptionApplicationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUserAdoptionApplicationsRequest) ProtoMessage() {}
+
+func (x *ListUserAdoptionApplicationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_adoption_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUserAdoptionApplicationsRequest.ProtoReflect.Descriptor instead.
+func (*ListUserAdoptionApplicationsRequest) Descriptor() ([]byte, []int) {
+	return file_adoption_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListUserAdoptionApplicationsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListUserAdoptionApplicationsRequest) GetPage() int32 {
+	if x != nil && x.Page != nil {
+		return *x.Page
+	}
+	return 0
+}
+
+func (x *ListUserAdoptionApplicationsRequest) GetLimit() int32 {
+	if x != nil && x.Limit != nil {
+		return *x.Limit
+	}
+	return 0
+}
+
+func (x *ListUserAdoptionApplicationsRequest) GetStatusFilter() ApplicationStatus {
+	if x != nil && x.StatusFilter != nil {
+		return *x.StatusFilter
+	}
+	return ApplicationStatus_APPLICATION_STATUS_UNSPECIFIED
+}
+
+type ListPetAdoptionApplicationsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PetId         string                 `protobuf:"bytes,1,opt,name=pet_id,json=petId,proto3" json:"pet_id,omitempty"`
+	Page          *int32                 `protobuf:"varint,2,opt,name=page,proto3,oneof" json:"page,omitempty"`
+	Limit         *int32                 `protobuf:"varint,3,opt,name=limit,proto3,oneof" json:"limit,omitempty"` // At most 100
+	StatusFilter  *ApplicationStatus     `protobuf:"varint,4,opt,name=status_filter,json=statusFilter,proto3,enum=adoption.ApplicationStatus,oneof" json:"status_filter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPetAdoptionApplicationsRequest) Reset() {
+	*x = ListPetAdoptionApplicationsRequest{}
+	mi := &file_adoption_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPetAdoptionApplicationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPetAdoptionApplicationsRequest) ProtoMessage() {}
+
+func (x *ListPetAdoptionApplicationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_adoption_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPetAdoptionApplicationsRequest.ProtoReflect.Descriptor instead.
+func (*ListPetAdoptionApplicationsRequest) Descriptor() ([]byte, []int) {
+	return file_adoption_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListPetAdoptionApplicationsRequest) GetPetId() string {
+	if x != nil {
+		return x.PetId
+	}
+	return ""
+}
+
+func (x *ListPetAdoptionApplicationsRequest) GetPage() int32 {
+	if x != nil && x.Page != nil {
+		return *x.Page
+	}
+	return 0
+}
+
+func (x *ListPetAdoptionApplicationsRequest) GetLimit() int32 {
+	if x != nil && x.Limit != nil {
+		return *x.Limit
+	}
+	return 0
+}
+
+func (x *ListPetAdoptionApplicationsRequest) GetStatusFilter() ApplicationStatus {
+	if x != nil && x.StatusFilter != nil {
+		return *x.StatusFilter
+	}
+	return ApplicationStatus_APPLICATION_STATUS_UNSPECIFIED
+}
+
+type ListAdoptionApplicationsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Applications  []*AdoptionApplication `protobuf:"bytes,1,rep,name=applications,proto3" json:"applications,omitempty"`
+	TotalCount    int32                  `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	Page          int32                  `protobuf:"varint,3,opt,name=page,proto3" json:"page,omitempty"`
+	Limit         int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAdoptionApplicationsResponse) Reset() {
+	*x = ListAdoptionApplicationsResponse{}
+	mi := &file_adoption_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAdoptionApplicationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAdoptionApplicationsResponse) ProtoMessage() {}
+
+func (x *ListAdoptionApplicationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_adoption_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAdoptionApplicationsResponse.ProtoReflect.Descriptor instead.
+func (*ListAdoptionApplicationsResponse) Descriptor() ([]byte, []int) {
+	return file_adoption_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ListAdoptionApplicationsResponse) GetApplications() []*AdoptionApplication {
+	if x != nil {
+		return x.Applications
+	}
+	return nil
+}
+
+func (x *ListAdoptionApplicationsResponse) GetTotalCount() int32 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+func (x *ListAdoptionApplicationsResponse) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListAdoptionApplicationsResponse) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type AdoptionApplicationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Application   *AdoptionApplication   `protobuf:"bytes,1,opt,name=application,proto3" json:"application,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdoptionApplicationResponse) Reset() {
+	*x = AdoptionApplicationResponse{}
+	mi := &file_adoption_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdoptionApplicationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdoptionApplicationResponse) ProtoMessage() {}
+
+func (x *AdoptionApplicationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_adoption_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdoptionApplicationResponse.ProtoReflect.Descriptor instead.
+func (*AdoptionApplicationResponse) Descriptor() ([]byte, []int) {
+	return file_adoption_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *AdoptionApplicationResponse) GetApplication() *AdoptionApplication {
+	if x != nil {
+		return x.Application
+	}
+	return nil
+}
+
+type GetPetApplicationStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PetId         string                 `protobuf:"bytes,1,opt,name=pet_id,json=petId,proto3" json:"pet_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPetApplicationStatsRequest) Reset() {
+	*x = GetPetApplicationStatsRequest{}
+	mi := &file_adoption_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPetApplicationStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPetApplicationStatsRequest) ProtoMessage() {}
+
+func (x *GetPetApplicationStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_adoption_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPetApplicationStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetPetApplicationStatsRequest) Descriptor() ([]byte, []int) {
+	return file_adoption_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetPetApplicationStatsRequest) GetPetId() string {
+	if x != nil {
+		return x.PetId
+	}
+	return ""
+}
+
+// Interest in a pet: how many applications it received, by status.
+type PetApplicationStats struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	PetId             string                 `protobuf:"bytes,1,opt,name=pet_id,json=petId,proto3" json:"pet_id,omitempty"`
+	TotalApplications int32                  `protobuf:"varint,2,opt,name=total_applications,json=totalApplications,proto3" json:"total_applications,omitempty"`
+	PendingReview     int32                  `protobuf:"varint,3,opt,name=pending_review,json=pendingReview,proto3" json:"pending_review,omitempty"`
+	Approved          int32                  `protobuf:"varint,4,opt,name=approved,proto3" json:"approved,omitempty"`
+	Rejected          int32                  `protobuf:"varint,5,opt,name=rejected,proto3" json:"rejected,omitempty"`
+	CancelledByUser   int32                  `protobuf:"varint,6,opt,name=cancelled_by_user,json=cancelledByUser,proto3" json:"cancelled_by_user,omitempty"`
+	LastAppliedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=last_applied_at,json=lastAppliedAt,proto3" json:"last_applied_at,omitempty"` // Unset if the pet has no applications
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *PetApplicationStats) Reset() {
+	*x = PetApplicationStats{}
+	mi := &file_adoption_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PetApplicationStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PetApplicationStats) ProtoMessage() {}
+
+func (x *PetApplicationStats) ProtoReflect() protoreflect.Message {
+	mi := &file_adoption_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PetApplicationStats.ProtoReflect.Descriptor instead.
+func (*PetApplicationStats) Descriptor() ([]byte, []int) {
+	return file_adoption_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *PetApplicationStats) GetPetId() string {
+	if x != nil {
+		return x.PetId
+	}
+	return ""
+}
+
+func (x *PetApplicationStats) GetTotalApplications() int32 {
+	if x != nil {
+		return x.TotalApplications
+	}
+	return 0
+}
+
+func (x *PetApplicationStats) GetPendingReview() int32 {
+	if x != nil {
+		return x.PendingReview
+	}
+	return 0
+}
+
+func (x *PetApplicationStats) GetApproved() int32 {
+	if x != nil {
+		return x.Approved
+	}
+	return 0
+}
+
+func (x *PetApplicationStats) GetRejected() int32 {
+	if x != nil {
+		return x.Rejected
+	}
+	return 0
+}
+
+func (x *PetApplicationStats) GetCancelledByUser() int32 {
+	if x != nil {
+		return x.CancelledByUser
+	}
+	return 0
+}
+
+func (x *PetApplicationStats) GetLastAppliedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastAppliedAt
+	}
+	return nil
+}
+
+type PetApplicationStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stats         *PetApplicationStats   `protobuf:"bytes,1,opt,name=stats,proto3" json:"stats,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PetApplicationStatsResponse) Reset() {
+	*x = PetApplicationStatsResponse{}
+	mi := &file_adoption_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PetApplicationStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PetApplicationStatsResponse) ProtoMessage() {}
+
+func (x *PetApplicationStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_adoption_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PetApplicationStatsResponse.ProtoReflect.Descriptor instead.
+func (*PetApplicationStatsResponse) Descriptor() ([]byte, []int) {
+	return file_adoption_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *PetApplicationStatsResponse) GetStats() *PetApplicationStats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+type CountPetApplicationsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PetIds        []string               `protobuf:"bytes,1,rep,name=pet_ids,json=petIds,proto3" json:"pet_ids,omitempty"` // At most 100
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CountPetApplicationsRequest) Reset() {
+	*x = CountPetApplicationsRequest{}
+	mi := &file_adoption_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CountPetApplicationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountPetApplicationsRequest) ProtoMessage() {}
+
+func (x *CountPetApplicationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_adoption_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountPetApplicationsRequest.ProtoReflect.Descriptor instead.
+func (*CountPetApplicationsRequest) Descriptor() ([]byte, []int) {
+	return file_adoption_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *CountPetApplicationsRequest) GetPetIds() []string {
+	if x != nil {
+		return x.PetIds
+	}
+	return nil
+}
+
+type CountPetApplicationsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Counts        map[string]int32       `protobuf:"bytes,1,rep,name=counts,proto3" json:"counts,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"` // Applications of any status per pet; pets without any are included with 0
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CountPetApplicationsResponse) Reset() {
+	*x = CountPetApplicationsResponse{}
+	mi := &file_adoption_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CountPetApplicationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountPetApplicationsResponse) ProtoMessage() {}
+
+func (x *CountPetApplicationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_adoption_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountPetApplicationsResponse.ProtoReflect.Descriptor instead.
+func (*CountPetApplicationsResponse) Descriptor() ([]byte, []int) {
+	return file_adoption_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *CountPetApplicationsResponse) GetCounts() map[string]int32 {
+	if x != nil {
+		return x.Counts
+	}
+	return nil
+}
+
+var File_adoption_proto protoreflect.FileDescriptor
+
+const file_adoption_proto_rawDesc = "" +
+	"\n" +
+	"\x0eadoption.proto\x12\badoption\x1a\x1fgoogle/protobuf/timestamp.proto\"\xec\x03\n" +
+	"\x13AdoptionApplication\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x15\n" +
 	"\x06pet_id\x18\x03 \x01(\tR\x05petId\x123\n" +
@@ -536,18 +1330,53 @@ const file_adoption_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
-	"updated_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\x7f\n" +
+	"updated_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x18\n" +
+	"\aflagged\x18\t \x01(\bR\aflagged\x12H\n" +
+	"\x0estatus_history\x18\n" +
+	" \x03(\v2!.adoption.ApplicationStatusChangeR\rstatusHistory\x126\n" +
+	"\vattachments\x18\v \x03(\v2\x14.adoption.AttachmentR\vattachments\"w\n" +
+	"\n" +
+	"Attachment\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x1a\n" +
+	"\bfilename\x18\x02 \x01(\tR\bfilename\x12;\n" +
+	"\vuploaded_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"uploadedAt\"\xe4\x01\n" +
+	"\x17ApplicationStatusChange\x12<\n" +
+	"\vfrom_status\x18\x01 \x01(\x0e2\x1b.adoption.ApplicationStatusR\n" +
+	"fromStatus\x128\n" +
+	"\tto_status\x18\x02 \x01(\x0e2\x1b.adoption.ApplicationStatusR\btoStatus\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x129\n" +
+	"\n" +
+	"changed_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tchangedAt\"\x7f\n" +
 	" CreateAdoptionApplicationRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x15\n" +
 	"\x06pet_id\x18\x02 \x01(\tR\x05petId\x12+\n" +
 	"\x11application_notes\x18\x03 \x01(\tR\x10applicationNotes\"F\n" +
 	"\x1dGetAdoptionApplicationRequest\x12%\n" +
-	"\x0eapplication_id\x18\x01 \x01(\tR\rapplicationId\"\xae\x01\n" +
+	"\x0eapplication_id\x18\x01 \x01(\tR\rapplicationId\"\xc4\x01\n" +
 	"&UpdateAdoptionApplicationStatusRequest\x12%\n" +
 	"\x0eapplication_id\x18\x01 \x01(\tR\rapplicationId\x12:\n" +
 	"\n" +
-	"new_status\x18\x02 \x01(\x0e2\x1b.adoption.ApplicationStatusR\tnewStatus\x12!\n" +
-	"\freview_notes\x18\x03 \x01(\tR\vreviewNotes\"\xde\x01\n" +
+	"new_status\x18\x02 \x01(\x0e2\x1b.adoption.ApplicationStatusR\tnewStatus\x12&\n" +
+	"\freview_notes\x18\x03 \x01(\tH\x00R\vreviewNotes\x88\x01\x01B\x0f\n" +
+	"\r_review_notes\"Y\n" +
+	"\x18ReopenApplicationRequest\x12%\n" +
+	"\x0eapplication_id\x18\x01 \x01(\tR\rapplicationId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"v\n" +
+	"\x1fAddApplicationAttachmentRequest\x12%\n" +
+	"\x0eapplication_id\x18\x01 \x01(\tR\rapplicationId\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x1a\n" +
+	"\bfilename\x18\x03 \x01(\tR\bfilename\"]\n" +
+	"\"RemoveApplicationAttachmentRequest\x12%\n" +
+	"\x0eapplication_id\x18\x01 \x01(\tR\rapplicationId\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\"8\n" +
+	"\x1dCancelUserApplicationsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"I\n" +
+	"\x1eCancelUserApplicationsResponse\x12'\n" +
+	"\x0fcancelled_count\x18\x01 \x01(\x05R\x0ecancelledCount\"b\n" +
+	" CancelAdoptionApplicationRequest\x12%\n" +
+	"\x0eapplication_id\x18\x01 \x01(\tR\rapplicationId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"\xde\x01\n" +
 	"#ListUserAdoptionApplicationsRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x17\n" +
 	"\x04page\x18\x02 \x01(\x05H\x00R\x04page\x88\x01\x01\x12\x19\n" +
@@ -555,6 +1384,14 @@ const file_adoption_proto_rawDesc = "" +
 	"\rstatus_filter\x18\x04 \x01(\x0e2\x1b.adoption.ApplicationStatusH\x02R\fstatusFilter\x88\x01\x01B\a\n" +
 	"\x05_pageB\b\n" +
 	"\x06_limitB\x10\n" +
+	"\x0e_status_filter\"\xdb\x01\n" +
+	"\"ListPetAdoptionApplicationsRequest\x12\x15\n" +
+	"\x06pet_id\x18\x01 \x01(\tR\x05petId\x12\x17\n" +
+	"\x04page\x18\x02 \x01(\x05H\x00R\x04page\x88\x01\x01\x12\x19\n" +
+	"\x05limit\x18\x03 \x01(\x05H\x01R\x05limit\x88\x01\x01\x12E\n" +
+	"\rstatus_filter\x18\x04 \x01(\x0e2\x1b.adoption.ApplicationStatusH\x02R\fstatusFilter\x88\x01\x01B\a\n" +
+	"\x05_pageB\b\n" +
+	"\x06_limitB\x10\n" +
 	"\x0e_status_filter\"\xb0\x01\n" +
 	" ListAdoptionApplicationsResponse\x12A\n" +
 	"\fapplications\x18\x01 \x03(\v2\x1d.adoption.AdoptionApplicationR\fapplications\x12\x1f\n" +
@@ -563,18 +1400,46 @@ const file_adoption_proto_rawDesc = "" +
 	"\x04page\x18\x03 \x01(\x05R\x04page\x12\x14\n" +
 	"\x05limit\x18\x04 \x01(\x05R\x05limit\"^\n" +
 	"\x1bAdoptionApplicationResponse\x12?\n" +
-	"\vapplication\x18\x01 \x01(\v2\x1d.adoption.AdoptionApplicationR\vapplication*~\n" +
+	"\vapplication\x18\x01 \x01(\v2\x1d.adoption.AdoptionApplicationR\vapplication\"6\n" +
+	"\x1dGetPetApplicationStatsRequest\x12\x15\n" +
+	"\x06pet_id\x18\x01 \x01(\tR\x05petId\"\xaa\x02\n" +
+	"\x13PetApplicationStats\x12\x15\n" +
+	"\x06pet_id\x18\x01 \x01(\tR\x05petId\x12-\n" +
+	"\x12total_applications\x18\x02 \x01(\x05R\x11totalApplications\x12%\n" +
+	"\x0epending_review\x18\x03 \x01(\x05R\rpendingReview\x12\x1a\n" +
+	"\bapproved\x18\x04 \x01(\x05R\bapproved\x12\x1a\n" +
+	"\brejected\x18\x05 \x01(\x05R\brejected\x12*\n" +
+	"\x11cancelled_by_user\x18\x06 \x01(\x05R\x0fcancelledByUser\x12B\n" +
+	"\x0flast_applied_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\rlastAppliedAt\"R\n" +
+	"\x1bPetApplicationStatsResponse\x123\n" +
+	"\x05stats\x18\x01 \x01(\v2\x1d.adoption.PetApplicationStatsR\x05stats\"6\n" +
+	"\x1bCountPetApplicationsRequest\x12\x17\n" +
+	"\apet_ids\x18\x01 \x03(\tR\x06petIds\"\xa5\x01\n" +
+	"\x1cCountPetApplicationsResponse\x12J\n" +
+	"\x06counts\x18\x01 \x03(\v22.adoption.CountPetApplicationsResponse.CountsEntryR\x06counts\x1a9\n" +
+	"\vCountsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01*~\n" +
 	"\x11ApplicationStatus\x12\"\n" +
 	"\x1eAPPLICATION_STATUS_UNSPECIFIED\x10\x00\x12\x12\n" +
 	"\x0ePENDING_REVIEW\x10\x01\x12\f\n" +
 	"\bAPPROVED\x10\x02\x12\f\n" +
 	"\bREJECTED\x10\x03\x12\x15\n" +
-	"\x11CANCELLED_BY_USER\x10\x052\xe2\x03\n" +
+	"\x11CANCELLED_BY_USER\x10\x052\xcb\n" +
+	"\n" +
 	"\x0fAdoptionService\x12n\n" +
 	"\x19CreateAdoptionApplication\x12*.adoption.CreateAdoptionApplicationRequest\x1a%.adoption.AdoptionApplicationResponse\x12h\n" +
 	"\x16GetAdoptionApplication\x12'.adoption.GetAdoptionApplicationRequest\x1a%.adoption.AdoptionApplicationResponse\x12z\n" +
 	"\x1fUpdateAdoptionApplicationStatus\x120.adoption.UpdateAdoptionApplicationStatusRequest\x1a%.adoption.AdoptionApplicationResponse\x12y\n" +
-	"\x1cListUserAdoptionApplications\x12-.adoption.ListUserAdoptionApplicationsRequest\x1a*.adoption.ListAdoptionApplicationsResponseBBZ@github.com/zhandarbeks/petstore-final-project/genprotos/adoptionb\x06proto3"
+	"\x1cListUserAdoptionApplications\x12-.adoption.ListUserAdoptionApplicationsRequest\x1a*.adoption.ListAdoptionApplicationsResponse\x12h\n" +
+	"\x16GetPetApplicationStats\x12'.adoption.GetPetApplicationStatsRequest\x1a%.adoption.PetApplicationStatsResponse\x12e\n" +
+	"\x14CountPetApplications\x12%.adoption.CountPetApplicationsRequest\x1a&.adoption.CountPetApplicationsResponse\x12w\n" +
+	"\x1bListPetAdoptionApplications\x12,.adoption.ListPetAdoptionApplicationsRequest\x1a*.adoption.ListAdoptionApplicationsResponse\x12^\n" +
+	"\x11ReopenApplication\x12\".adoption.ReopenApplicationRequest\x1a%.adoption.AdoptionApplicationResponse\x12l\n" +
+	"\x18AddApplicationAttachment\x12).adoption.AddApplicationAttachmentRequest\x1a%.adoption.AdoptionApplicationResponse\x12r\n" +
+	"\x1bRemoveApplicationAttachment\x12,.adoption.RemoveApplicationAttachmentRequest\x1a%.adoption.AdoptionApplicationResponse\x12k\n" +
+	"\x16CancelUserApplications\x12'.adoption.CancelUserApplicationsRequest\x1a(.adoption.CancelUserApplicationsResponse\x12n\n" +
+	"\x19CancelAdoptionApplication\x12*.adoption.CancelAdoptionApplicationRequest\x1a%.adoption.AdoptionApplicationResponseBBZ@github.com/zhandarbeks/petstore-final-project/genprotos/adoptionb\x06proto3"
 
 var (
 	file_adoption_proto_rawDescOnce sync.Once
@@ -589,39 +1454,80 @@ func file_adoption_proto_rawDescGZIP() []byte {
 }
 
 var file_adoption_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_adoption_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_adoption_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
 var file_adoption_proto_goTypes = []any{
 	(ApplicationStatus)(0),                         // 0: adoption.ApplicationStatus
 	(*AdoptionApplication)(nil),                    // 1: adoption.AdoptionApplication
-	(*CreateAdoptionApplicationRequest)(nil),       // 2: adoption.CreateAdoptionApplicationRequest
-	(*GetAdoptionApplicationRequest)(nil),          // 3: adoption.GetAdoptionApplicationRequest
-	(*UpdateAdoptionApplicationStatusRequest)(nil), // 4: adoption.UpdateAdoptionApplicationStatusRequest
-	(*ListUserAdoptionApplicationsRequest)(nil),    // 5: adoption.ListUserAdoptionApplicationsRequest
-	(*ListAdoptionApplicationsResponse)(nil),       // 6: adoption.ListAdoptionApplicationsResponse
-	(*AdoptionApplicationResponse)(nil),            // 7: adoption.AdoptionApplicationResponse
-	(*timestamppb.Timestamp)(nil),                  // 8: google.protobuf.Timestamp
+	(*Attachment)(nil),                             // 2: adoption.Attachment
+	(*ApplicationStatusChange)(nil),                // 3: adoption.ApplicationStatusChange
+	(*CreateAdoptionApplicationRequest)(nil),       // 4: adoption.CreateAdoptionApplicationRequest
+	(*GetAdoptionApplicationRequest)(nil),          // 5: adoption.GetAdoptionApplicationRequest
+	(*UpdateAdoptionApplicationStatusRequest)(nil), // 6: adoption.UpdateAdoptionApplicationStatusRequest
+	(*ReopenApplicationRequest)(nil),               // 7: adoption.ReopenApplicationRequest
+	(*AddApplicationAttachmentRequest)(nil),        // 8: adoption.AddApplicationAttachmentRequest
+	(*RemoveApplicationAttachmentRequest)(nil),     // 9: adoption.RemoveApplicationAttachmentRequest
+	(*CancelUserApplicationsRequest)(nil),          // 10: adoption.CancelUserApplicationsRequest
+	(*CancelUserApplicationsResponse)(nil),         // 11: adoption.CancelUserApplicationsResponse
+	(*CancelAdoptionApplicationRequest)(nil),       // 12: adoption.CancelAdoptionApplicationRequest
+	(*ListUserAdoptionApplicationsRequest)(nil),    // 13: adoption.ListUserAdoptionApplicationsRequest
+	(*ListPetAdoptionApplicationsRequest)(nil),     // 14: adoption.ListPetAdoptionApplicationsRequest
+	(*ListAdoptionApplicationsResponse)(nil),       // 15: adoption.ListAdoptionApplicationsResponse
+	(*AdoptionApplicationResponse)(nil),            // 16: adoption.AdoptionApplicationResponse
+	(*GetPetApplicationStatsRequest)(nil),          // 17: adoption.GetPetApplicationStatsRequest
+	(*PetApplicationStats)(nil),                    // 18: adoption.PetApplicationStats
+	(*PetApplicationStatsResponse)(nil),            // 19: adoption.PetApplicationStatsResponse
+	(*CountPetApplicationsRequest)(nil),            // 20: adoption.CountPetApplicationsRequest
+	(*CountPetApplicationsResponse)(nil),           // 21: adoption.CountPetApplicationsResponse
+	nil,                                            // 22: adoption.CountPetApplicationsResponse.CountsEntry
+	(*timestamppb.Timestamp)(nil),                  // 23: google.protobuf.Timestamp
 }
 var file_adoption_proto_depIdxs = []int32{
 	0,  // 0: adoption.AdoptionApplication.status:type_name -> adoption.ApplicationStatus
-	8,  // 1: adoption.AdoptionApplication.created_at:type_name -> google.protobuf.Timestamp
-	8,  // 2: adoption.AdoptionApplication.updated_at:type_name -> google.protobuf.Timestamp
-	0,  // 3: adoption.UpdateAdoptionApplicationStatusRequest.new_status:type_name -> adoption.ApplicationStatus
-	0,  // 4: adoption.ListUserAdoptionApplicationsRequest.status_filter:type_name -> adoption.ApplicationStatus
-	1,  // 5: adoption.ListAdoptionApplicationsResponse.applications:type_name -> adoption.AdoptionApplication
-	1,  // 6: adoption.AdoptionApplicationResponse.application:type_name -> adoption.AdoptionApplication
-	2,  // 7: adoption.AdoptionService.CreateAdoptionApplication:input_type -> adoption.CreateAdoptionApplicationRequest
-	3,  // 8: adoption.AdoptionService.GetAdoptionApplication:input_type -> adoption.GetAdoptionApplicationRequest
-	4,  // 9: adoption.AdoptionService.UpdateAdoptionApplicationStatus:input_type -> adoption.UpdateAdoptionApplicationStatusRequest
-	5,  // 10: adoption.AdoptionService.ListUserAdoptionApplications:input_type -> adoption.ListUserAdoptionApplicationsRequest
-	7,  // 11: adoption.AdoptionService.CreateAdoptionApplication:output_type -> adoption.AdoptionApplicationResponse
-	7,  // 12: adoption.AdoptionService.GetAdoptionApplication:output_type -> adoption.AdoptionApplicationResponse
-	7,  // 13: adoption.AdoptionService.UpdateAdoptionApplicationStatus:output_type -> adoption.AdoptionApplicationResponse
-	6,  // 14: adoption.AdoptionService.ListUserAdoptionApplications:output_type -> adoption.ListAdoptionApplicationsResponse
-	11, // [11:15] is the sub-list for method output_type
-	7,  // [7:11] is the sub-list for method input_type
-	7,  // [7:7] is the sub-list for extension type_name
-	7,  // [7:7] is the sub-list for extension extendee
-	0,  // [0:7] is the sub-list for field type_name
+	23, // 1: adoption.AdoptionApplication.created_at:type_name -> google.protobuf.Timestamp
+	23, // 2: adoption.AdoptionApplication.updated_at:type_name -> google.protobuf.Timestamp
+	3,  // 3: adoption.AdoptionApplication.status_history:type_name -> adoption.ApplicationStatusChange
+	2,  // 4: adoption.AdoptionApplication.attachments:type_name -> adoption.Attachment
+	23, // 5: adoption.Attachment.uploaded_at:type_name -> google.protobuf.Timestamp
+	0,  // 6: adoption.ApplicationStatusChange.from_status:type_name -> adoption.ApplicationStatus
+	0,  // 7: adoption.ApplicationStatusChange.to_status:type_name -> adoption.ApplicationStatus
+	23, // 8: adoption.ApplicationStatusChange.changed_at:type_name -> google.protobuf.Timestamp
+	0,  // 9: adoption.UpdateAdoptionApplicationStatusRequest.new_status:type_name -> adoption.ApplicationStatus
+	0,  // 10: adoption.ListUserAdoptionApplicationsRequest.status_filter:type_name -> adoption.ApplicationStatus
+	0,  // 11: adoption.ListPetAdoptionApplicationsRequest.status_filter:type_name -> adoption.ApplicationStatus
+	1,  // 12: adoption.ListAdoptionApplicationsResponse.applications:type_name -> adoption.AdoptionApplication
+	1,  // 13: adoption.AdoptionApplicationResponse.application:type_name -> adoption.AdoptionApplication
+	23, // 14: adoption.PetApplicationStats.last_applied_at:type_name -> google.protobuf.Timestamp
+	18, // 15: adoption.PetApplicationStatsResponse.stats:type_name -> adoption.PetApplicationStats
+	22, // 16: adoption.CountPetApplicationsResponse.counts:type_name -> adoption.CountPetApplicationsResponse.CountsEntry
+	4,  // 17: adoption.AdoptionService.CreateAdoptionApplication:input_type -> adoption.CreateAdoptionApplicationRequest
+	5,  // 18: adoption.AdoptionService.GetAdoptionApplication:input_type -> adoption.GetAdoptionApplicationRequest
+	6,  // 19: adoption.AdoptionService.UpdateAdoptionApplicationStatus:input_type -> adoption.UpdateAdoptionApplicationStatusRequest
+	13, // 20: adoption.AdoptionService.ListUserAdoptionApplications:input_type -> adoption.ListUserAdoptionApplicationsRequest
+	17, // 21: adoption.AdoptionService.GetPetApplicationStats:input_type -> adoption.GetPetApplicationStatsRequest
+	20, // 22: adoption.AdoptionService.CountPetApplications:input_type -> adoption.CountPetApplicationsRequest
+	14, // 23: adoption.AdoptionService.ListPetAdoptionApplications:input_type -> adoption.ListPetAdoptionApplicationsRequest
+	7,  // 24: adoption.AdoptionService.ReopenApplication:input_type -> adoption.ReopenApplicationRequest
+	8,  // 25: adoption.AdoptionService.AddApplicationAttachment:input_type -> adoption.AddApplicationAttachmentRequest
+	9,  // 26: adoption.AdoptionService.RemoveApplicationAttachment:input_type -> adoption.RemoveApplicationAttachmentRequest
+	10, // 27: adoption.AdoptionService.CancelUserApplications:input_type -> adoption.CancelUserApplicationsRequest
+	12, // 28: adoption.AdoptionService.CancelAdoptionApplication:input_type -> adoption.CancelAdoptionApplicationRequest
+	16, // 29: adoption.AdoptionService.CreateAdoptionApplication:output_type -> adoption.AdoptionApplicationResponse
+	16, // 30: adoption.AdoptionService.GetAdoptionApplication:output_type -> adoption.AdoptionApplicationResponse
+	16, // 31: adoption.AdoptionService.UpdateAdoptionApplicationStatus:output_type -> adoption.AdoptionApplicationResponse
+	15, // 32: adoption.AdoptionService.ListUserAdoptionApplications:output_type -> adoption.ListAdoptionApplicationsResponse
+	19, // 33: adoption.AdoptionService.GetPetApplicationStats:output_type -> adoption.PetApplicationStatsResponse
+	21, // 34: adoption.AdoptionService.CountPetApplications:output_type -> adoption.CountPetApplicationsResponse
+	15, // 35: adoption.AdoptionService.ListPetAdoptionApplications:output_type -> adoption.ListAdoptionApplicationsResponse
+	16, // 36: adoption.AdoptionService.ReopenApplication:output_type -> adoption.AdoptionApplicationResponse
+	16, // 37: adoption.AdoptionService.AddApplicationAttachment:output_type -> adoption.AdoptionApplicationResponse
+	16, // 38: adoption.AdoptionService.RemoveApplicationAttachment:output_type -> adoption.AdoptionApplicationResponse
+	11, // 39: adoption.AdoptionService.CancelUserApplications:output_type -> adoption.CancelUserApplicationsResponse
+	16, // 40: adoption.AdoptionService.CancelAdoptionApplication:output_type -> adoption.AdoptionApplicationResponse
+	29, // [29:41] is the sub-list for method output_type
+	17, // [17:29] is the sub-list for method input_type
+	17, // [17:17] is the sub-list for extension type_name
+	17, // [17:17] is the sub-list for extension extendee
+	0,  // [0:17] is the sub-list for field type_name
 }
 
 func init() { file_adoption_proto_init() }
@@ -629,14 +1535,16 @@ func file_adoption_proto_init() {
 	if File_adoption_proto != nil {
 		return
 	}
-	file_adoption_proto_msgTypes[4].OneofWrappers = []any{}
+	file_adoption_proto_msgTypes[5].OneofWrappers = []any{}
+	file_adoption_proto_msgTypes[12].OneofWrappers = []any{}
+	file_adoption_proto_msgTypes[13].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_adoption_proto_rawDesc), len(file_adoption_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   7,
+			NumMessages:   22,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
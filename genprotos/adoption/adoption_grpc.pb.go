@@ -23,6 +23,14 @@ const (
 	AdoptionService_GetAdoptionApplication_FullMethodName          = "/adoption.AdoptionService/GetAdoptionApplication"
 	AdoptionService_UpdateAdoptionApplicationStatus_FullMethodName = "/adoption.AdoptionService/UpdateAdoptionApplicationStatus"
 	AdoptionService_ListUserAdoptionApplications_FullMethodName    = "/adoption.AdoptionService/ListUserAdoptionApplications"
+	AdoptionService_GetPetApplicationStats_FullMethodName          = "/adoption.AdoptionService/GetPetApplicationStats"
+	AdoptionService_CountPetApplications_FullMethodName            = "/adoption.AdoptionService/CountPetApplications"
+	AdoptionService_ListPetAdoptionApplications_FullMethodName     = "/adoption.AdoptionService/ListPetAdoptionApplications"
+	AdoptionService_ReopenApplication_FullMethodName               = "/adoption.AdoptionService/ReopenApplication"
+	AdoptionService_AddApplicationAttachment_FullMethodName        = "/adoption.AdoptionService/AddApplicationAttachment"
+	AdoptionService_RemoveApplicationAttachment_FullMethodName     = "/adoption.AdoptionService/RemoveApplicationAttachment"
+	AdoptionService_CancelUserApplications_FullMethodName          = "/adoption.AdoptionService/CancelUserApplications"
+	AdoptionService_CancelAdoptionApplication_FullMethodName       = "/adoption.AdoptionService/CancelAdoptionApplication"
 )
 
 // AdoptionServiceClient is the client API for AdoptionService service.
@@ -33,6 +41,14 @@ type AdoptionServiceClient interface {
 	GetAdoptionApplication(ctx context.Context, in *GetAdoptionApplicationRequest, opts ...grpc.CallOption) (*AdoptionApplicationResponse, error)
 	UpdateAdoptionApplicationStatus(ctx context.Context, in *UpdateAdoptionApplicationStatusRequest, opts ...grpc.CallOption) (*AdoptionApplicationResponse, error)
 	ListUserAdoptionApplications(ctx context.Context, in *ListUserAdoptionApplicationsRequest, opts ...grpc.CallOption) (*ListAdoptionApplicationsResponse, error)
+	GetPetApplicationStats(ctx context.Context, in *GetPetApplicationStatsRequest, opts ...grpc.CallOption) (*PetApplicationStatsResponse, error)
+	CountPetApplications(ctx context.Context, in *CountPetApplicationsRequest, opts ...grpc.CallOption) (*CountPetApplicationsResponse, error)
+	ListPetAdoptionApplications(ctx context.Context, in *ListPetAdoptionApplicationsRequest, opts ...grpc.CallOption) (*ListAdoptionApplicationsResponse, error)
+	ReopenApplication(ctx context.Context, in *ReopenApplicationRequest, opts ...grpc.CallOption) (*AdoptionApplicationResponse, error)
+	AddApplicationAttachment(ctx context.Context, in *AddApplicationAttachmentRequest, opts ...grpc.CallOption) (*AdoptionApplicationResponse, error)
+	RemoveApplicationAttachment(ctx context.Context, in *RemoveApplicationAttachmentRequest, opts ...grpc.CallOption) (*AdoptionApplicationResponse, error)
+	CancelUserApplications(ctx context.Context, in *CancelUserApplicationsRequest, opts ...grpc.CallOption) (*CancelUserApplicationsResponse, error)
+	CancelAdoptionApplication(ctx context.Context, in *CancelAdoptionApplicationRequest, opts ...grpc.CallOption) (*AdoptionApplicationResponse, error)
 }
 
 type adoptionServiceClient struct {
@@ -83,6 +99,86 @@ func (c *adoptionServiceClient) ListUserAdoptionApplications(ctx context.Context
 	return out, nil
 }
 
+func (c *adoptionServiceClient) GetPetApplicationStats(ctx context.Context, in *GetPetApplicationStatsRequest, opts ...grpc.CallOption) (*PetApplicationStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PetApplicationStatsResponse)
+	err := c.cc.Invoke(ctx, AdoptionService_GetPetApplicationStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adoptionServiceClient) CountPetApplications(ctx context.Context, in *CountPetApplicationsRequest, opts ...grpc.CallOption) (*CountPetApplicationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CountPetApplicationsResponse)
+	err := c.cc.Invoke(ctx, AdoptionService_CountPetApplications_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adoptionServiceClient) ListPetAdoptionApplications(ctx context.Context, in *ListPetAdoptionApplicationsRequest, opts ...grpc.CallOption) (*ListAdoptionApplicationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAdoptionApplicationsResponse)
+	err := c.cc.Invoke(ctx, AdoptionService_ListPetAdoptionApplications_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adoptionServiceClient) ReopenApplication(ctx context.Context, in *ReopenApplicationRequest, opts ...grpc.CallOption) (*AdoptionApplicationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AdoptionApplicationResponse)
+	err := c.cc.Invoke(ctx, AdoptionService_ReopenApplication_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adoptionServiceClient) AddApplicationAttachment(ctx context.Context, in *AddApplicationAttachmentRequest, opts ...grpc.CallOption) (*AdoptionApplicationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AdoptionApplicationResponse)
+	err := c.cc.Invoke(ctx, AdoptionService_AddApplicationAttachment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adoptionServiceClient) RemoveApplicationAttachment(ctx context.Context, in *RemoveApplicationAttachmentRequest, opts ...grpc.CallOption) (*AdoptionApplicationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AdoptionApplicationResponse)
+	err := c.cc.Invoke(ctx, AdoptionService_RemoveApplicationAttachment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adoptionServiceClient) CancelUserApplications(ctx context.Context, in *CancelUserApplicationsRequest, opts ...grpc.CallOption) (*CancelUserApplicationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelUserApplicationsResponse)
+	err := c.cc.Invoke(ctx, AdoptionService_CancelUserApplications_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adoptionServiceClient) CancelAdoptionApplication(ctx context.Context, in *CancelAdoptionApplicationRequest, opts ...grpc.CallOption) (*AdoptionApplicationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AdoptionApplicationResponse)
+	err := c.cc.Invoke(ctx, AdoptionService_CancelAdoptionApplication_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AdoptionServiceServer is the server API for AdoptionService service.
 // All implementations must embed UnimplementedAdoptionServiceServer
 // for forward compatibility.
@@ -91,6 +187,14 @@ type AdoptionServiceServer interface {
 	GetAdoptionApplication(context.Context, *GetAdoptionApplicationRequest) (*AdoptionApplicationResponse, error)
 	UpdateAdoptionApplicationStatus(context.Context, *UpdateAdoptionApplicationStatusRequest) (*AdoptionApplicationResponse, error)
 	ListUserAdoptionApplications(context.Context, *ListUserAdoptionApplicationsRequest) (*ListAdoptionApplicationsResponse, error)
+	GetPetApplicationStats(context.Context, *GetPetApplicationStatsRequest) (*PetApplicationStatsResponse, error)
+	CountPetApplications(context.Context, *CountPetApplicationsRequest) (*CountPetApplicationsResponse, error)
+	ListPetAdoptionApplications(context.Context, *ListPetAdoptionApplicationsRequest) (*ListAdoptionApplicationsResponse, error)
+	ReopenApplication(context.Context, *ReopenApplicationRequest) (*AdoptionApplicationResponse, error)
+	AddApplicationAttachment(context.Context, *AddApplicationAttachmentRequest) (*AdoptionApplicationResponse, error)
+	RemoveApplicationAttachment(context.Context, *RemoveApplicationAttachmentRequest) (*AdoptionApplicationResponse, error)
+	CancelUserApplications(context.Context, *CancelUserApplicationsRequest) (*CancelUserApplicationsResponse, error)
+	CancelAdoptionApplication(context.Context, *CancelAdoptionApplicationRequest) (*AdoptionApplicationResponse, error)
 	mustEmbedUnimplementedAdoptionServiceServer()
 }
 
@@ -113,6 +217,30 @@ func (UnimplementedAdoptionServiceServer) UpdateAdoptionApplicationStatus(contex
 func (UnimplementedAdoptionServiceServer) ListUserAdoptionApplications(context.Context, *ListUserAdoptionApplicationsRequest) (*ListAdoptionApplicationsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListUserAdoptionApplications not implemented")
 }
+func (UnimplementedAdoptionServiceServer) GetPetApplicationStats(context.Context, *GetPetApplicationStatsRequest) (*PetApplicationStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPetApplicationStats not implemented")
+}
+func (UnimplementedAdoptionServiceServer) CountPetApplications(context.Context, *CountPetApplicationsRequest) (*CountPetApplicationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CountPetApplications not implemented")
+}
+func (UnimplementedAdoptionServiceServer) ListPetAdoptionApplications(context.Context, *ListPetAdoptionApplicationsRequest) (*ListAdoptionApplicationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPetAdoptionApplications not implemented")
+}
+func (UnimplementedAdoptionServiceServer) ReopenApplication(context.Context, *ReopenApplicationRequest) (*AdoptionApplicationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReopenApplication not implemented")
+}
+func (UnimplementedAdoptionServiceServer) AddApplicationAttachment(context.Context, *AddApplicationAttachmentRequest) (*AdoptionApplicationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddApplicationAttachment not implemented")
+}
+func (UnimplementedAdoptionServiceServer) RemoveApplicationAttachment(context.Context, *RemoveApplicationAttachmentRequest) (*AdoptionApplicationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveApplicationAttachment not implemented")
+}
+func (UnimplementedAdoptionServiceServer) CancelUserApplications(context.Context, *CancelUserApplicationsRequest) (*CancelUserApplicationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelUserApplications not implemented")
+}
+func (UnimplementedAdoptionServiceServer) CancelAdoptionApplication(context.Context, *CancelAdoptionApplicationRequest) (*AdoptionApplicationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelAdoptionApplication not implemented")
+}
 func (UnimplementedAdoptionServiceServer) mustEmbedUnimplementedAdoptionServiceServer() {}
 func (UnimplementedAdoptionServiceServer) testEmbeddedByValue()                         {}
 
@@ -206,6 +334,150 @@ func _AdoptionService_ListUserAdoptionApplications_Handler(srv interface{}, ctx
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AdoptionService_GetPetApplicationStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPetApplicationStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdoptionServiceServer).GetPetApplicationStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdoptionService_GetPetApplicationStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdoptionServiceServer).GetPetApplicationStats(ctx, req.(*GetPetApplicationStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdoptionService_CountPetApplications_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CountPetApplicationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdoptionServiceServer).CountPetApplications(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdoptionService_CountPetApplications_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdoptionServiceServer).CountPetApplications(ctx, req.(*CountPetApplicationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdoptionService_ListPetAdoptionApplications_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPetAdoptionApplicationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdoptionServiceServer).ListPetAdoptionApplications(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdoptionService_ListPetAdoptionApplications_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdoptionServiceServer).ListPetAdoptionApplications(ctx, req.(*ListPetAdoptionApplicationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdoptionService_ReopenApplication_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReopenApplicationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdoptionServiceServer).ReopenApplication(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdoptionService_ReopenApplication_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdoptionServiceServer).ReopenApplication(ctx, req.(*ReopenApplicationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdoptionService_AddApplicationAttachment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddApplicationAttachmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdoptionServiceServer).AddApplicationAttachment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdoptionService_AddApplicationAttachment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdoptionServiceServer).AddApplicationAttachment(ctx, req.(*AddApplicationAttachmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdoptionService_RemoveApplicationAttachment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveApplicationAttachmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdoptionServiceServer).RemoveApplicationAttachment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdoptionService_RemoveApplicationAttachment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdoptionServiceServer).RemoveApplicationAttachment(ctx, req.(*RemoveApplicationAttachmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdoptionService_CancelUserApplications_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelUserApplicationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdoptionServiceServer).CancelUserApplications(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdoptionService_CancelUserApplications_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdoptionServiceServer).CancelUserApplications(ctx, req.(*CancelUserApplicationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdoptionService_CancelAdoptionApplication_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelAdoptionApplicationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdoptionServiceServer).CancelAdoptionApplication(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdoptionService_CancelAdoptionApplication_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdoptionServiceServer).CancelAdoptionApplication(ctx, req.(*CancelAdoptionApplicationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // AdoptionService_ServiceDesc is the grpc.ServiceDesc for AdoptionService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -229,6 +501,38 @@ var AdoptionService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListUserAdoptionApplications",
 			Handler:    _AdoptionService_ListUserAdoptionApplications_Handler,
 		},
+		{
+			MethodName: "GetPetApplicationStats",
+			Handler:    _AdoptionService_GetPetApplicationStats_Handler,
+		},
+		{
+			MethodName: "CountPetApplications",
+			Handler:    _AdoptionService_CountPetApplications_Handler,
+		},
+		{
+			MethodName: "ListPetAdoptionApplications",
+			Handler:    _AdoptionService_ListPetAdoptionApplications_Handler,
+		},
+		{
+			MethodName: "ReopenApplication",
+			Handler:    _AdoptionService_ReopenApplication_Handler,
+		},
+		{
+			MethodName: "AddApplicationAttachment",
+			Handler:    _AdoptionService_AddApplicationAttachment_Handler,
+		},
+		{
+			MethodName: "RemoveApplicationAttachment",
+			Handler:    _AdoptionService_RemoveApplicationAttachment_Handler,
+		},
+		{
+			MethodName: "CancelUserApplications",
+			Handler:    _AdoptionService_CancelUserApplications_Handler,
+		},
+		{
+			MethodName: "CancelAdoptionApplication",
+			Handler:    _AdoptionService_CancelAdoptionApplication_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "adoption.proto",
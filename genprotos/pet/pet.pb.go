@@ -87,6 +87,12 @@ type Pet struct {
 	CreatedAt       string                 `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	UpdatedAt       string                 `protobuf:"bytes,11,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
 	ImageUrls       []string               `protobuf:"bytes,12,rep,name=image_urls,json=imageUrls,proto3" json:"image_urls,omitempty"`
+	Tags            []string               `protobuf:"bytes,13,rep,name=tags,proto3" json:"tags,omitempty"` // Lowercase labels like "good with kids"
+	StatusHistory   []*PetStatusChange     `protobuf:"bytes,14,rep,name=status_history,json=statusHistory,proto3" json:"status_history,omitempty"`
+	ThumbnailUrl    string                 `protobuf:"bytes,15,opt,name=thumbnail_url,json=thumbnailUrl,proto3" json:"thumbnail_url,omitempty"` // First image, or the configured placeholder when the pet has no images (not stored)
+	ListingHistory  []*ListingTransfer     `protobuf:"bytes,16,rep,name=listing_history,json=listingHistory,proto3" json:"listing_history,omitempty"`
+	Deleted         bool                   `protobuf:"varint,17,opt,name=deleted,proto3" json:"deleted,omitempty"` // Soft-deleted; such pets are only listed with include_deleted
+	DeletedAt       string                 `protobuf:"bytes,18,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
 	unknownFields   protoimpl.UnknownFields
 	sizeCache       protoimpl.SizeCache
 }
@@ -205,6 +211,200 @@ func (x *Pet) GetImageUrls() []string {
 	return nil
 }
 
+func (x *Pet) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Pet) GetStatusHistory() []*PetStatusChange {
+	if x != nil {
+		return x.StatusHistory
+	}
+	return nil
+}
+
+func (x *Pet) GetThumbnailUrl() string {
+	if x != nil {
+		return x.ThumbnailUrl
+	}
+	return ""
+}
+
+func (x *Pet) GetListingHistory() []*ListingTransfer {
+	if x != nil {
+		return x.ListingHistory
+	}
+	return nil
+}
+
+func (x *Pet) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
+func (x *Pet) GetDeletedAt() string {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return ""
+}
+
+type ListingTransfer struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FromUserId    string                 `protobuf:"bytes,1,opt,name=from_user_id,json=fromUserId,proto3" json:"from_user_id,omitempty"`
+	ToUserId      string                 `protobuf:"bytes,2,opt,name=to_user_id,json=toUserId,proto3" json:"to_user_id,omitempty"`
+	TransferredBy string                 `protobuf:"bytes,3,opt,name=transferred_by,json=transferredBy,proto3" json:"transferred_by,omitempty"`
+	TransferredAt string                 `protobuf:"bytes,4,opt,name=transferred_at,json=transferredAt,proto3" json:"transferred_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListingTransfer) Reset() {
+	*x = ListingTransfer{}
+	mi := &file_pet_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListingTransfer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListingTransfer) ProtoMessage() {}
+
+func (x *ListingTransfer) ProtoReflect() protoreflect.Message {
+	mi := &file_pet_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListingTransfer.ProtoReflect.Descriptor instead.
+func (*ListingTransfer) Descriptor() ([]byte, []int) {
+	return file_pet_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListingTransfer) GetFromUserId() string {
+	if x != nil {
+		return x.FromUserId
+	}
+	return ""
+}
+
+func (x *ListingTransfer) GetToUserId() string {
+	if x != nil {
+		return x.ToUserId
+	}
+	return ""
+}
+
+func (x *ListingTransfer) GetTransferredBy() string {
+	if x != nil {
+		return x.TransferredBy
+	}
+	return ""
+}
+
+func (x *ListingTransfer) GetTransferredAt() string {
+	if x != nil {
+		return x.TransferredAt
+	}
+	return ""
+}
+
+type PetStatusChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FromStatus    AdoptionStatus         `protobuf:"varint,1,opt,name=from_status,json=fromStatus,proto3,enum=pet.AdoptionStatus" json:"from_status,omitempty"`
+	ToStatus      AdoptionStatus         `protobuf:"varint,2,opt,name=to_status,json=toStatus,proto3,enum=pet.AdoptionStatus" json:"to_status,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	ChangedBy     string                 `protobuf:"bytes,4,opt,name=changed_by,json=changedBy,proto3" json:"changed_by,omitempty"`
+	Forced        bool                   `protobuf:"varint,5,opt,name=forced,proto3" json:"forced,omitempty"` // Set by an admin override
+	ChangedAt     string                 `protobuf:"bytes,6,opt,name=changed_at,json=changedAt,proto3" json:"changed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PetStatusChange) Reset() {
+	*x = PetStatusChange{}
+	mi := &file_pet_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PetStatusChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PetStatusChange) ProtoMessage() {}
+
+func (x *PetStatusChange) ProtoReflect() protoreflect.Message {
+	mi := &file_pet_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PetStatusChange.ProtoReflect.Descriptor instead.
+func (*PetStatusChange) Descriptor() ([]byte, []int) {
+	return file_pet_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PetStatusChange) GetFromStatus() AdoptionStatus {
+	if x != nil {
+		return x.FromStatus
+	}
+	return AdoptionStatus_ADOPTION_STATUS_UNSPECIFIED
+}
+
+func (x *PetStatusChange) GetToStatus() AdoptionStatus {
+	if x != nil {
+		return x.ToStatus
+	}
+	return AdoptionStatus_ADOPTION_STATUS_UNSPECIFIED
+}
+
+func (x *PetStatusChange) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *PetStatusChange) GetChangedBy() string {
+	if x != nil {
+		return x.ChangedBy
+	}
+	return ""
+}
+
+func (x *PetStatusChange) GetForced() bool {
+	if x != nil {
+		return x.Forced
+	}
+	return false
+}
+
+func (x *PetStatusChange) GetChangedAt() string {
+	if x != nil {
+		return x.ChangedAt
+	}
+	return ""
+}
+
 type CreatePetRequest struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	Name           string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
@@ -214,13 +414,17 @@ type CreatePetRequest struct {
 	Description    string                 `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
 	ListedByUserId string                 `protobuf:"bytes,6,opt,name=listed_by_user_id,json=listedByUserId,proto3" json:"listed_by_user_id,omitempty"`
 	ImageUrls      []string               `protobuf:"bytes,7,rep,name=image_urls,json=imageUrls,proto3" json:"image_urls,omitempty"`
+	Tags           []string               `protobuf:"bytes,8,rep,name=tags,proto3" json:"tags,omitempty"`
+	// Initial status, AVAILABLE if unset. AVAILABLE or PENDING_ADOPTION; anything other than
+	// AVAILABLE requires the "admin" or "shelter" role (x-user-roles metadata).
+	AdoptionStatus *AdoptionStatus `protobuf:"varint,9,opt,name=adoption_status,json=adoptionStatus,proto3,enum=pet.AdoptionStatus,oneof" json:"adoption_status,omitempty"`
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
 
 func (x *CreatePetRequest) Reset() {
 	*x = CreatePetRequest{}
-	mi := &file_pet_proto_msgTypes[1]
+	mi := &file_pet_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -232,7 +436,7 @@ func (x *CreatePetRequest) String() string {
 func (*CreatePetRequest) ProtoMessage() {}
 
 func (x *CreatePetRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pet_proto_msgTypes[1]
+	mi := &file_pet_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -245,7 +449,7 @@ func (x *CreatePetRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreatePetRequest.ProtoReflect.Descriptor instead.
 func (*CreatePetRequest) Descriptor() ([]byte, []int) {
-	return file_pet_proto_rawDescGZIP(), []int{1}
+	return file_pet_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *CreatePetRequest) GetName() string {
@@ -290,35 +494,1029 @@ func (x *CreatePetRequest) GetListedByUserId() string {
 	return ""
 }
 
-func (x *CreatePetRequest) GetImageUrls() []string {
+func (x *CreatePetRequest) GetImageUrls() []string {
+	if x != nil {
+		return x.ImageUrls
+	}
+	return nil
+}
+
+func (x *CreatePetRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *CreatePetRequest) GetAdoptionStatus() AdoptionStatus {
+	if x != nil && x.AdoptionStatus != nil {
+		return *x.AdoptionStatus
+	}
+	return AdoptionStatus_ADOPTION_STATUS_UNSPECIFIED
+}
+
+type GetPetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PetId         string                 `protobuf:"bytes,1,opt,name=pet_id,json=petId,proto3" json:"pet_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPetRequest) Reset() {
+	*x = GetPetRequest{}
+	mi := &file_pet_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPetRequest) ProtoMessage() {}
+
+func (x *GetPetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pet_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPetRequest.ProtoReflect.Descriptor instead.
+func (*GetPetRequest) Descriptor() ([]byte, []int) {
+	return file_pet_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetPetRequest) GetPetId() string {
+	if x != nil {
+		return x.PetId
+	}
+	return ""
+}
+
+type CheckPetAvailabilityRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PetId         string                 `protobuf:"bytes,1,opt,name=pet_id,json=petId,proto3" json:"pet_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckPetAvailabilityRequest) Reset() {
+	*x = CheckPetAvailabilityRequest{}
+	mi := &file_pet_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckPetAvailabilityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckPetAvailabilityRequest) ProtoMessage() {}
+
+func (x *CheckPetAvailabilityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pet_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckPetAvailabilityRequest.ProtoReflect.Descriptor instead.
+func (*CheckPetAvailabilityRequest) Descriptor() ([]byte, []int) {
+	return file_pet_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CheckPetAvailabilityRequest) GetPetId() string {
+	if x != nil {
+		return x.PetId
+	}
+	return ""
+}
+
+type CheckPetAvailabilityResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	AdoptionStatus AdoptionStatus         `protobuf:"varint,1,opt,name=adoption_status,json=adoptionStatus,proto3,enum=pet.AdoptionStatus" json:"adoption_status,omitempty"`
+	Available      bool                   `protobuf:"varint,2,opt,name=available,proto3" json:"available,omitempty"` // True only while the pet is AVAILABLE
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CheckPetAvailabilityResponse) Reset() {
+	*x = CheckPetAvailabilityResponse{}
+	mi := &file_pet_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckPetAvailabilityResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckPetAvailabilityResponse) ProtoMessage() {}
+
+func (x *CheckPetAvailabilityResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pet_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckPetAvailabilityResponse.ProtoReflect.Descriptor instead.
+func (*CheckPetAvailabilityResponse) Descriptor() ([]byte, []int) {
+	return file_pet_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CheckPetAvailabilityResponse) GetAdoptionStatus() AdoptionStatus {
+	if x != nil {
+		return x.AdoptionStatus
+	}
+	return AdoptionStatus_ADOPTION_STATUS_UNSPECIFIED
+}
+
+func (x *CheckPetAvailabilityResponse) GetAvailable() bool {
+	if x != nil {
+		return x.Available
+	}
+	return false
+}
+
+type BatchGetPetsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"` // At most 100; duplicates are returned once
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetPetsRequest) Reset() {
+	*x = BatchGetPetsRequest{}
+	mi := &file_pet_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetPetsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetPetsRequest) ProtoMessage() {}
+
+func (x *BatchGetPetsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pet_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetPetsRequest.ProtoReflect.Descriptor instead.
+func (*BatchGetPetsRequest) Descriptor() ([]byte, []int) {
+	return file_pet_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *BatchGetPetsRequest) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+type BatchGetPetsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pets          []*Pet                 `protobuf:"bytes,1,rep,name=pets,proto3" json:"pets,omitempty"` // In the order of the requested IDs
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetPetsResponse) Reset() {
+	*x = BatchGetPetsResponse{}
+	mi := &file_pet_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetPetsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetPetsResponse) ProtoMessage() {}
+
+func (x *BatchGetPetsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pet_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetPetsResponse.ProtoReflect.Descriptor instead.
+func (*BatchGetPetsResponse) Descriptor() ([]byte, []int) {
+	return file_pet_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *BatchGetPetsResponse) GetPets() []*Pet {
+	if x != nil {
+		return x.Pets
+	}
+	return nil
+}
+
+type UpdatePetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PetId         string                 `protobuf:"bytes,1,opt,name=pet_id,json=petId,proto3" json:"pet_id,omitempty"`
+	Name          *string                `protobuf:"bytes,2,opt,name=name,proto3,oneof" json:"name,omitempty"`
+	Species       *string                `protobuf:"bytes,3,opt,name=species,proto3,oneof" json:"species,omitempty"`
+	Breed         *string                `protobuf:"bytes,4,opt,name=breed,proto3,oneof" json:"breed,omitempty"`
+	Age           *int32                 `protobuf:"varint,5,opt,name=age,proto3,oneof" json:"age,omitempty"`
+	Description   *string                `protobuf:"bytes,6,opt,name=description,proto3,oneof" json:"description,omitempty"`
+	ImageUrls     []string               `protobuf:"bytes,7,rep,name=image_urls,json=imageUrls,proto3" json:"image_urls,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdatePetRequest) Reset() {
+	*x = UpdatePetRequest{}
+	mi := &file_pet_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdatePetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdatePetRequest) ProtoMessage() {}
+
+func (x *UpdatePetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pet_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdatePetRequest.ProtoReflect.Descriptor instead.
+func (*UpdatePetRequest) Descriptor() ([]byte, []int) {
+	return file_pet_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *UpdatePetRequest) GetPetId() string {
+	if x != nil {
+		return x.PetId
+	}
+	return ""
+}
+
+func (x *UpdatePetRequest) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *UpdatePetRequest) GetSpecies() string {
+	if x != nil && x.Species != nil {
+		return *x.Species
+	}
+	return ""
+}
+
+func (x *UpdatePetRequest) GetBreed() string {
+	if x != nil && x.Breed != nil {
+		return *x.Breed
+	}
+	return ""
+}
+
+func (x *UpdatePetRequest) GetAge() int32 {
+	if x != nil && x.Age != nil {
+		return *x.Age
+	}
+	return 0
+}
+
+func (x *UpdatePetRequest) GetDescription() string {
+	if x != nil && x.Description != nil {
+		return *x.Description
+	}
+	return ""
+}
+
+func (x *UpdatePetRequest) GetImageUrls() []string {
+	if x != nil {
+		return x.ImageUrls
+	}
+	return nil
+}
+
+type DeletePetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PetId         string                 `protobuf:"bytes,1,opt,name=pet_id,json=petId,proto3" json:"pet_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeletePetRequest) Reset() {
+	*x = DeletePetRequest{}
+	mi := &file_pet_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeletePetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletePetRequest) ProtoMessage() {}
+
+func (x *DeletePetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pet_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletePetRequest.ProtoReflect.Descriptor instead.
+func (*DeletePetRequest) Descriptor() ([]byte, []int) {
+	return file_pet_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DeletePetRequest) GetPetId() string {
+	if x != nil {
+		return x.PetId
+	}
+	return ""
+}
+
+type RestorePetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PetId         string                 `protobuf:"bytes,1,opt,name=pet_id,json=petId,proto3" json:"pet_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestorePetRequest) Reset() {
+	*x = RestorePetRequest{}
+	mi := &file_pet_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestorePetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestorePetRequest) ProtoMessage() {}
+
+func (x *RestorePetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pet_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestorePetRequest.ProtoReflect.Descriptor instead.
+func (*RestorePetRequest) Descriptor() ([]byte, []int) {
+	return file_pet_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *RestorePetRequest) GetPetId() string {
+	if x != nil {
+		return x.PetId
+	}
+	return ""
+}
+
+type ListPetsRequest struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Page                 *int32                 `protobuf:"varint,1,opt,name=page,proto3,oneof" json:"page,omitempty"`
+	Limit                *int32                 `protobuf:"varint,2,opt,name=limit,proto3,oneof" json:"limit,omitempty"`
+	SpeciesFilter        *string                `protobuf:"bytes,3,opt,name=species_filter,json=speciesFilter,proto3,oneof" json:"species_filter,omitempty"`
+	StatusFilter         *AdoptionStatus        `protobuf:"varint,4,opt,name=status_filter,json=statusFilter,proto3,enum=pet.AdoptionStatus,oneof" json:"status_filter,omitempty"`
+	TagsFilter           []string               `protobuf:"bytes,5,rep,name=tags_filter,json=tagsFilter,proto3" json:"tags_filter,omitempty"`
+	MatchAllTags         *bool                  `protobuf:"varint,6,opt,name=match_all_tags,json=matchAllTags,proto3,oneof" json:"match_all_tags,omitempty"`                            // true: pet must have every tag; false (default): any of them
+	Sort                 *string                `protobuf:"bytes,7,opt,name=sort,proto3,oneof" json:"sort,omitempty"`                                                                   // "newest" or "oldest" by creation time; empty keeps the storage order
+	Cursor               *string                `protobuf:"bytes,8,opt,name=cursor,proto3,oneof" json:"cursor,omitempty"`                                                               // next_cursor of the previous page; set (even empty) to page by cursor instead of page number
+	ListedByUserIdFilter *string                `protobuf:"bytes,9,opt,name=listed_by_user_id_filter,json=listedByUserIdFilter,proto3,oneof" json:"listed_by_user_id_filter,omitempty"` // Only pets listed by this user
+	CreatedBefore        *string                `protobuf:"bytes,10,opt,name=created_before,json=createdBefore,proto3,oneof" json:"created_before,omitempty"`                           // RFC 3339; only pets listed before this time
+	SearchQuery          *string                `protobuf:"bytes,11,opt,name=search_query,json=searchQuery,proto3,oneof" json:"search_query,omitempty"`                                 // Words to find in the name or description (full-text, case-insensitive); empty matches every pet
+	MinAge               *int32                 `protobuf:"varint,12,opt,name=min_age,json=minAge,proto3,oneof" json:"min_age,omitempty"`                                               // Only pets at least this old
+	MaxAge               *int32                 `protobuf:"varint,13,opt,name=max_age,json=maxAge,proto3,oneof" json:"max_age,omitempty"`                                               // Only pets at most this old
+	SpeciesFilters       []string               `protobuf:"bytes,14,rep,name=species_filters,json=speciesFilters,proto3" json:"species_filters,omitempty"`                              // Pets of any of these species; combined with species_filter
+	IncludeDeleted       *bool                  `protobuf:"varint,15,opt,name=include_deleted,json=includeDeleted,proto3,oneof" json:"include_deleted,omitempty"`                       // Also list soft-deleted pets; requires the "admin" role (x-user-roles metadata)
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *ListPetsRequest) Reset() {
+	*x = ListPetsRequest{}
+	mi := &file_pet_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPetsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPetsRequest) ProtoMessage() {}
+
+func (x *ListPetsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pet_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPetsRequest.ProtoReflect.Descriptor instead.
+func (*ListPetsRequest) Descriptor() ([]byte, []int) {
+	return file_pet_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListPetsRequest) GetPage() int32 {
+	if x != nil && x.Page != nil {
+		return *x.Page
+	}
+	return 0
+}
+
+func (x *ListPetsRequest) GetLimit() int32 {
+	if x != nil && x.Limit != nil {
+		return *x.Limit
+	}
+	return 0
+}
+
+func (x *ListPetsRequest) GetSpeciesFilter() string {
+	if x != nil && x.SpeciesFilter != nil {
+		return *x.SpeciesFilter
+	}
+	return ""
+}
+
+func (x *ListPetsRequest) GetStatusFilter() AdoptionStatus {
+	if x != nil && x.StatusFilter != nil {
+		return *x.StatusFilter
+	}
+	return AdoptionStatus_ADOPTION_STATUS_UNSPECIFIED
+}
+
+func (x *ListPetsRequest) GetTagsFilter() []string {
+	if x != nil {
+		return x.TagsFilter
+	}
+	return nil
+}
+
+func (x *ListPetsRequest) GetMatchAllTags() bool {
+	if x != nil && x.MatchAllTags != nil {
+		return *x.MatchAllTags
+	}
+	return false
+}
+
+func (x *ListPetsRequest) GetSort() string {
+	if x != nil && x.Sort != nil {
+		return *x.Sort
+	}
+	return ""
+}
+
+func (x *ListPetsRequest) GetCursor() string {
+	if x != nil && x.Cursor != nil {
+		return *x.Cursor
+	}
+	return ""
+}
+
+func (x *ListPetsRequest) GetListedByUserIdFilter() string {
+	if x != nil && x.ListedByUserIdFilter != nil {
+		return *x.ListedByUserIdFilter
+	}
+	return ""
+}
+
+func (x *ListPetsRequest) GetCreatedBefore() string {
+	if x != nil && x.CreatedBefore != nil {
+		return *x.CreatedBefore
+	}
+	return ""
+}
+
+func (x *ListPetsRequest) GetSearchQuery() string {
+	if x != nil && x.SearchQuery != nil {
+		return *x.SearchQuery
+	}
+	return ""
+}
+
+func (x *ListPetsRequest) GetMinAge() int32 {
+	if x != nil && x.MinAge != nil {
+		return *x.MinAge
+	}
+	return 0
+}
+
+func (x *ListPetsRequest) GetMaxAge() int32 {
+	if x != nil && x.MaxAge != nil {
+		return *x.MaxAge
+	}
+	return 0
+}
+
+func (x *ListPetsRequest) GetSpeciesFilters() []string {
+	if x != nil {
+		return x.SpeciesFilters
+	}
+	return nil
+}
+
+func (x *ListPetsRequest) GetIncludeDeleted() bool {
+	if x != nil && x.IncludeDeleted != nil {
+		return *x.IncludeDeleted
+	}
+	return false
+}
+
+type ListPetsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pets          []*Pet                 `protobuf:"bytes,1,rep,name=pets,proto3" json:"pets,omitempty"`
+	TotalCount    int32                  `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	Page          int32                  `protobuf:"varint,3,opt,name=page,proto3" json:"page,omitempty"`
+	Limit         int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,5,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"` // Set on full cursor pages; pass it as cursor to get the next page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPetsResponse) Reset() {
+	*x = ListPetsResponse{}
+	mi := &file_pet_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPetsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPetsResponse) ProtoMessage() {}
+
+func (x *ListPetsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pet_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPetsResponse.ProtoReflect.Descriptor instead.
+func (*ListPetsResponse) Descriptor() ([]byte, []int) {
+	return file_pet_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListPetsResponse) GetPets() []*Pet {
+	if x != nil {
+		return x.Pets
+	}
+	return nil
+}
+
+func (x *ListPetsResponse) GetTotalCount() int32 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+func (x *ListPetsResponse) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListPetsResponse) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListPetsResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type UpdatePetAdoptionStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PetId         string                 `protobuf:"bytes,1,opt,name=pet_id,json=petId,proto3" json:"pet_id,omitempty"`
+	NewStatus     AdoptionStatus         `protobuf:"varint,2,opt,name=new_status,json=newStatus,proto3,enum=pet.AdoptionStatus" json:"new_status,omitempty"`
+	AdopterUserId string                 `protobuf:"bytes,3,opt,name=adopter_user_id,json=adopterUserId,proto3" json:"adopter_user_id,omitempty"`
+	Reason        *string                `protobuf:"bytes,4,opt,name=reason,proto3,oneof" json:"reason,omitempty"` // Why the status changed, e.g. "reserved pending home check"; kept in the status history
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdatePetAdoptionStatusRequest) Reset() {
+	*x = UpdatePetAdoptionStatusRequest{}
+	mi := &file_pet_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdatePetAdoptionStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdatePetAdoptionStatusRequest) ProtoMessage() {}
+
+func (x *UpdatePetAdoptionStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pet_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdatePetAdoptionStatusRequest.ProtoReflect.Descriptor instead.
+func (*UpdatePetAdoptionStatusRequest) Descriptor() ([]byte, []int) {
+	return file_pet_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *UpdatePetAdoptionStatusRequest) GetPetId() string {
+	if x != nil {
+		return x.PetId
+	}
+	return ""
+}
+
+func (x *UpdatePetAdoptionStatusRequest) GetNewStatus() AdoptionStatus {
+	if x != nil {
+		return x.NewStatus
+	}
+	return AdoptionStatus_ADOPTION_STATUS_UNSPECIFIED
+}
+
+func (x *UpdatePetAdoptionStatusRequest) GetAdopterUserId() string {
+	if x != nil {
+		return x.AdopterUserId
+	}
+	return ""
+}
+
+func (x *UpdatePetAdoptionStatusRequest) GetReason() string {
+	if x != nil && x.Reason != nil {
+		return *x.Reason
+	}
+	return ""
+}
+
+type GetImageUploadURLRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PetId         string                 `protobuf:"bytes,1,opt,name=pet_id,json=petId,proto3" json:"pet_id,omitempty"`
+	Filename      string                 `protobuf:"bytes,2,opt,name=filename,proto3" json:"filename,omitempty"`                          // Original file name, used only for the object key extension
+	ContentType   string                 `protobuf:"bytes,3,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"` // e.g. "image/jpeg"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetImageUploadURLRequest) Reset() {
+	*x = GetImageUploadURLRequest{}
+	mi := &file_pet_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetImageUploadURLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetImageUploadURLRequest) ProtoMessage() {}
+
+func (x *GetImageUploadURLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pet_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetImageUploadURLRequest.ProtoReflect.Descriptor instead.
+func (*GetImageUploadURLRequest) Descriptor() ([]byte, []int) {
+	return file_pet_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetImageUploadURLRequest) GetPetId() string {
+	if x != nil {
+		return x.PetId
+	}
+	return ""
+}
+
+func (x *GetImageUploadURLRequest) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *GetImageUploadURLRequest) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+// ImageUploadTarget describes a presigned upload: the client POSTs a multipart form
+// containing all `fields` plus the file to `url`, then registers `public_url` via AddImageURLs.
+type ImageUploadTarget struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Fields        map[string]string      `protobuf:"bytes,2,rep,name=fields,proto3" json:"fields,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	PublicUrl     string                 `protobuf:"bytes,3,opt,name=public_url,json=publicUrl,proto3" json:"public_url,omitempty"`
+	ExpiresAt     string                 `protobuf:"bytes,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImageUploadTarget) Reset() {
+	*x = ImageUploadTarget{}
+	mi := &file_pet_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImageUploadTarget) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImageUploadTarget) ProtoMessage() {}
+
+func (x *ImageUploadTarget) ProtoReflect() protoreflect.Message {
+	mi := &file_pet_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImageUploadTarget.ProtoReflect.Descriptor instead.
+func (*ImageUploadTarget) Descriptor() ([]byte, []int) {
+	return file_pet_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ImageUploadTarget) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *ImageUploadTarget) GetFields() map[string]string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+func (x *ImageUploadTarget) GetPublicUrl() string {
+	if x != nil {
+		return x.PublicUrl
+	}
+	return ""
+}
+
+func (x *ImageUploadTarget) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+type AddImageURLsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PetId         string                 `protobuf:"bytes,1,opt,name=pet_id,json=petId,proto3" json:"pet_id,omitempty"`
+	ImageUrls     []string               `protobuf:"bytes,2,rep,name=image_urls,json=imageUrls,proto3" json:"image_urls,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddImageURLsRequest) Reset() {
+	*x = AddImageURLsRequest{}
+	mi := &file_pet_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddImageURLsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddImageURLsRequest) ProtoMessage() {}
+
+func (x *AddImageURLsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pet_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddImageURLsRequest.ProtoReflect.Descriptor instead.
+func (*AddImageURLsRequest) Descriptor() ([]byte, []int) {
+	return file_pet_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *AddImageURLsRequest) GetPetId() string {
+	if x != nil {
+		return x.PetId
+	}
+	return ""
+}
+
+func (x *AddImageURLsRequest) GetImageUrls() []string {
+	if x != nil {
+		return x.ImageUrls
+	}
+	return nil
+}
+
+type ListRecentlyAdoptedRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         *int32                 `protobuf:"varint,1,opt,name=limit,proto3,oneof" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRecentlyAdoptedRequest) Reset() {
+	*x = ListRecentlyAdoptedRequest{}
+	mi := &file_pet_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRecentlyAdoptedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRecentlyAdoptedRequest) ProtoMessage() {}
+
+func (x *ListRecentlyAdoptedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pet_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRecentlyAdoptedRequest.ProtoReflect.Descriptor instead.
+func (*ListRecentlyAdoptedRequest) Descriptor() ([]byte, []int) {
+	return file_pet_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ListRecentlyAdoptedRequest) GetLimit() int32 {
+	if x != nil && x.Limit != nil {
+		return *x.Limit
+	}
+	return 0
+}
+
+// Pets are ordered by updated_at descending; for adopted pets updated_at is the adoption time.
+type ListRecentlyAdoptedResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pets          []*Pet                 `protobuf:"bytes,1,rep,name=pets,proto3" json:"pets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRecentlyAdoptedResponse) Reset() {
+	*x = ListRecentlyAdoptedResponse{}
+	mi := &file_pet_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRecentlyAdoptedResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRecentlyAdoptedResponse) ProtoMessage() {}
+
+func (x *ListRecentlyAdoptedResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pet_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRecentlyAdoptedResponse.ProtoReflect.Descriptor instead.
+func (*ListRecentlyAdoptedResponse) Descriptor() ([]byte, []int) {
+	return file_pet_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ListRecentlyAdoptedResponse) GetPets() []*Pet {
 	if x != nil {
-		return x.ImageUrls
+		return x.Pets
 	}
 	return nil
 }
 
-type GetPetRequest struct {
+type PetTagsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	PetId         string                 `protobuf:"bytes,1,opt,name=pet_id,json=petId,proto3" json:"pet_id,omitempty"`
+	Tags          []string               `protobuf:"bytes,2,rep,name=tags,proto3" json:"tags,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetPetRequest) Reset() {
-	*x = GetPetRequest{}
-	mi := &file_pet_proto_msgTypes[2]
+func (x *PetTagsRequest) Reset() {
+	*x = PetTagsRequest{}
+	mi := &file_pet_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetPetRequest) String() string {
+func (x *PetTagsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetPetRequest) ProtoMessage() {}
+func (*PetTagsRequest) ProtoMessage() {}
 
-func (x *GetPetRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pet_proto_msgTypes[2]
+func (x *PetTagsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pet_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -329,46 +1527,50 @@ func (x *GetPetRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetPetRequest.ProtoReflect.Descriptor instead.
-func (*GetPetRequest) Descriptor() ([]byte, []int) {
-	return file_pet_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use PetTagsRequest.ProtoReflect.Descriptor instead.
+func (*PetTagsRequest) Descriptor() ([]byte, []int) {
+	return file_pet_proto_rawDescGZIP(), []int{20}
 }
 
-func (x *GetPetRequest) GetPetId() string {
+func (x *PetTagsRequest) GetPetId() string {
 	if x != nil {
 		return x.PetId
 	}
 	return ""
 }
 
-type UpdatePetRequest struct {
+func (x *PetTagsRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type AdminSetPetStatusRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	PetId         string                 `protobuf:"bytes,1,opt,name=pet_id,json=petId,proto3" json:"pet_id,omitempty"`
-	Name          *string                `protobuf:"bytes,2,opt,name=name,proto3,oneof" json:"name,omitempty"`
-	Species       *string                `protobuf:"bytes,3,opt,name=species,proto3,oneof" json:"species,omitempty"`
-	Breed         *string                `protobuf:"bytes,4,opt,name=breed,proto3,oneof" json:"breed,omitempty"`
-	Age           *int32                 `protobuf:"varint,5,opt,name=age,proto3,oneof" json:"age,omitempty"`
-	Description   *string                `protobuf:"bytes,6,opt,name=description,proto3,oneof" json:"description,omitempty"`
-	ImageUrls     []string               `protobuf:"bytes,7,rep,name=image_urls,json=imageUrls,proto3" json:"image_urls,omitempty"`
+	NewStatus     AdoptionStatus         `protobuf:"varint,2,opt,name=new_status,json=newStatus,proto3,enum=pet.AdoptionStatus" json:"new_status,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`                        // Required; recorded in the pet's status history
+	ChangedBy     string                 `protobuf:"bytes,4,opt,name=changed_by,json=changedBy,proto3" json:"changed_by,omitempty"` // Admin identifier for the audit trail
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdatePetRequest) Reset() {
-	*x = UpdatePetRequest{}
-	mi := &file_pet_proto_msgTypes[3]
+func (x *AdminSetPetStatusRequest) Reset() {
+	*x = AdminSetPetStatusRequest{}
+	mi := &file_pet_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdatePetRequest) String() string {
+func (x *AdminSetPetStatusRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdatePetRequest) ProtoMessage() {}
+func (*AdminSetPetStatusRequest) ProtoMessage() {}
 
-func (x *UpdatePetRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pet_proto_msgTypes[3]
+func (x *AdminSetPetStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pet_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -379,82 +1581,112 @@ func (x *UpdatePetRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdatePetRequest.ProtoReflect.Descriptor instead.
-func (*UpdatePetRequest) Descriptor() ([]byte, []int) {
-	return file_pet_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use AdminSetPetStatusRequest.ProtoReflect.Descriptor instead.
+func (*AdminSetPetStatusRequest) Descriptor() ([]byte, []int) {
+	return file_pet_proto_rawDescGZIP(), []int{21}
 }
 
-func (x *UpdatePetRequest) GetPetId() string {
+func (x *AdminSetPetStatusRequest) GetPetId() string {
 	if x != nil {
 		return x.PetId
 	}
 	return ""
 }
 
-func (x *UpdatePetRequest) GetName() string {
-	if x != nil && x.Name != nil {
-		return *x.Name
+func (x *AdminSetPetStatusRequest) GetNewStatus() AdoptionStatus {
+	if x != nil {
+		return x.NewStatus
 	}
-	return ""
+	return AdoptionStatus_ADOPTION_STATUS_UNSPECIFIED
 }
 
-func (x *UpdatePetRequest) GetSpecies() string {
-	if x != nil && x.Species != nil {
-		return *x.Species
+func (x *AdminSetPetStatusRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
 	}
 	return ""
 }
 
-func (x *UpdatePetRequest) GetBreed() string {
-	if x != nil && x.Breed != nil {
-		return *x.Breed
+func (x *AdminSetPetStatusRequest) GetChangedBy() string {
+	if x != nil {
+		return x.ChangedBy
 	}
 	return ""
 }
 
-func (x *UpdatePetRequest) GetAge() int32 {
-	if x != nil && x.Age != nil {
-		return *x.Age
+type TransferPetListingRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	PetId          string                 `protobuf:"bytes,1,opt,name=pet_id,json=petId,proto3" json:"pet_id,omitempty"`
+	NewOwnerUserId string                 `protobuf:"bytes,2,opt,name=new_owner_user_id,json=newOwnerUserId,proto3" json:"new_owner_user_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *TransferPetListingRequest) Reset() {
+	*x = TransferPetListingRequest{}
+	mi := &file_pet_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferPetListingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferPetListingRequest) ProtoMessage() {}
+
+func (x *TransferPetListingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pet_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *UpdatePetRequest) GetDescription() string {
-	if x != nil && x.Description != nil {
-		return *x.Description
+// Deprecated: Use TransferPetListingRequest.ProtoReflect.Descriptor instead.
+func (*TransferPetListingRequest) Descriptor() ([]byte, []int) {
+	return file_pet_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *TransferPetListingRequest) GetPetId() string {
+	if x != nil {
+		return x.PetId
 	}
 	return ""
 }
 
-func (x *UpdatePetRequest) GetImageUrls() []string {
+func (x *TransferPetListingRequest) GetNewOwnerUserId() string {
 	if x != nil {
-		return x.ImageUrls
+		return x.NewOwnerUserId
 	}
-	return nil
+	return ""
 }
 
-type DeletePetRequest struct {
+type GetPetFacetsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	PetId         string                 `protobuf:"bytes,1,opt,name=pet_id,json=petId,proto3" json:"pet_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeletePetRequest) Reset() {
-	*x = DeletePetRequest{}
-	mi := &file_pet_proto_msgTypes[4]
+func (x *GetPetFacetsRequest) Reset() {
+	*x = GetPetFacetsRequest{}
+	mi := &file_pet_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeletePetRequest) String() string {
+func (x *GetPetFacetsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeletePetRequest) ProtoMessage() {}
+func (*GetPetFacetsRequest) ProtoMessage() {}
 
-func (x *DeletePetRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pet_proto_msgTypes[4]
+func (x *GetPetFacetsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pet_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -465,43 +1697,34 @@ func (x *DeletePetRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeletePetRequest.ProtoReflect.Descriptor instead.
-func (*DeletePetRequest) Descriptor() ([]byte, []int) {
-	return file_pet_proto_rawDescGZIP(), []int{4}
-}
-
-func (x *DeletePetRequest) GetPetId() string {
-	if x != nil {
-		return x.PetId
-	}
-	return ""
+// Deprecated: Use GetPetFacetsRequest.ProtoReflect.Descriptor instead.
+func (*GetPetFacetsRequest) Descriptor() ([]byte, []int) {
+	return file_pet_proto_rawDescGZIP(), []int{23}
 }
 
-type ListPetsRequest struct {
+type FacetCount struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Page          *int32                 `protobuf:"varint,1,opt,name=page,proto3,oneof" json:"page,omitempty"`
-	Limit         *int32                 `protobuf:"varint,2,opt,name=limit,proto3,oneof" json:"limit,omitempty"`
-	SpeciesFilter *string                `protobuf:"bytes,3,opt,name=species_filter,json=speciesFilter,proto3,oneof" json:"species_filter,omitempty"`
-	StatusFilter  *AdoptionStatus        `protobuf:"varint,4,opt,name=status_filter,json=statusFilter,proto3,enum=pet.AdoptionStatus,oneof" json:"status_filter,omitempty"`
+	Value         string                 `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListPetsRequest) Reset() {
-	*x = ListPetsRequest{}
-	mi := &file_pet_proto_msgTypes[5]
+func (x *FacetCount) Reset() {
+	*x = FacetCount{}
+	mi := &file_pet_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListPetsRequest) String() string {
+func (x *FacetCount) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListPetsRequest) ProtoMessage() {}
+func (*FacetCount) ProtoMessage() {}
 
-func (x *ListPetsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pet_proto_msgTypes[5]
+func (x *FacetCount) ProtoReflect() protoreflect.Message {
+	mi := &file_pet_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -512,64 +1735,50 @@ func (x *ListPetsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListPetsRequest.ProtoReflect.Descriptor instead.
-func (*ListPetsRequest) Descriptor() ([]byte, []int) {
-	return file_pet_proto_rawDescGZIP(), []int{5}
-}
-
-func (x *ListPetsRequest) GetPage() int32 {
-	if x != nil && x.Page != nil {
-		return *x.Page
-	}
-	return 0
-}
-
-func (x *ListPetsRequest) GetLimit() int32 {
-	if x != nil && x.Limit != nil {
-		return *x.Limit
-	}
-	return 0
+// Deprecated: Use FacetCount.ProtoReflect.Descriptor instead.
+func (*FacetCount) Descriptor() ([]byte, []int) {
+	return file_pet_proto_rawDescGZIP(), []int{24}
 }
 
-func (x *ListPetsRequest) GetSpeciesFilter() string {
-	if x != nil && x.SpeciesFilter != nil {
-		return *x.SpeciesFilter
+func (x *FacetCount) GetValue() string {
+	if x != nil {
+		return x.Value
 	}
 	return ""
 }
 
-func (x *ListPetsRequest) GetStatusFilter() AdoptionStatus {
-	if x != nil && x.StatusFilter != nil {
-		return *x.StatusFilter
+func (x *FacetCount) GetCount() int64 {
+	if x != nil {
+		return x.Count
 	}
-	return AdoptionStatus_ADOPTION_STATUS_UNSPECIFIED
+	return 0
 }
 
-type ListPetsResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Pets          []*Pet                 `protobuf:"bytes,1,rep,name=pets,proto3" json:"pets,omitempty"`
-	TotalCount    int32                  `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
-	Page          int32                  `protobuf:"varint,3,opt,name=page,proto3" json:"page,omitempty"`
-	Limit         int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// Distinct values with pet counts, for building search filters.
+type PetFacetsResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Species          []*FacetCount          `protobuf:"bytes,1,rep,name=species,proto3" json:"species,omitempty"`
+	Breeds           []*FacetCount          `protobuf:"bytes,2,rep,name=breeds,proto3" json:"breeds,omitempty"`
+	AdoptionStatuses []*FacetCount          `protobuf:"bytes,3,rep,name=adoption_statuses,json=adoptionStatuses,proto3" json:"adoption_statuses,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
-func (x *ListPetsResponse) Reset() {
-	*x = ListPetsResponse{}
-	mi := &file_pet_proto_msgTypes[6]
+func (x *PetFacetsResponse) Reset() {
+	*x = PetFacetsResponse{}
+	mi := &file_pet_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListPetsResponse) String() string {
+func (x *PetFacetsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListPetsResponse) ProtoMessage() {}
+func (*PetFacetsResponse) ProtoMessage() {}
 
-func (x *ListPetsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pet_proto_msgTypes[6]
+func (x *PetFacetsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pet_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -580,63 +1789,57 @@ func (x *ListPetsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListPetsResponse.ProtoReflect.Descriptor instead.
-func (*ListPetsResponse) Descriptor() ([]byte, []int) {
-	return file_pet_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use PetFacetsResponse.ProtoReflect.Descriptor instead.
+func (*PetFacetsResponse) Descriptor() ([]byte, []int) {
+	return file_pet_proto_rawDescGZIP(), []int{25}
 }
 
-func (x *ListPetsResponse) GetPets() []*Pet {
+func (x *PetFacetsResponse) GetSpecies() []*FacetCount {
 	if x != nil {
-		return x.Pets
+		return x.Species
 	}
 	return nil
 }
 
-func (x *ListPetsResponse) GetTotalCount() int32 {
-	if x != nil {
-		return x.TotalCount
-	}
-	return 0
-}
-
-func (x *ListPetsResponse) GetPage() int32 {
+func (x *PetFacetsResponse) GetBreeds() []*FacetCount {
 	if x != nil {
-		return x.Page
+		return x.Breeds
 	}
-	return 0
+	return nil
 }
 
-func (x *ListPetsResponse) GetLimit() int32 {
+func (x *PetFacetsResponse) GetAdoptionStatuses() []*FacetCount {
 	if x != nil {
-		return x.Limit
+		return x.AdoptionStatuses
 	}
-	return 0
+	return nil
 }
 
-type UpdatePetAdoptionStatusRequest struct {
+// Breeds already used for a species, for autocompleting the breed of a new listing.
+type SuggestBreedsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	PetId         string                 `protobuf:"bytes,1,opt,name=pet_id,json=petId,proto3" json:"pet_id,omitempty"`
-	NewStatus     AdoptionStatus         `protobuf:"varint,2,opt,name=new_status,json=newStatus,proto3,enum=pet.AdoptionStatus" json:"new_status,omitempty"`
-	AdopterUserId string                 `protobuf:"bytes,3,opt,name=adopter_user_id,json=adopterUserId,proto3" json:"adopter_user_id,omitempty"`
+	Species       string                 `protobuf:"bytes,1,opt,name=species,proto3" json:"species,omitempty"`    // Required; matched case-insensitively
+	Prefix        string                 `protobuf:"bytes,2,opt,name=prefix,proto3" json:"prefix,omitempty"`      // Matched case-insensitively against the start of the breed; empty returns all breeds
+	Limit         *int32                 `protobuf:"varint,3,opt,name=limit,proto3,oneof" json:"limit,omitempty"` // Default 10, max 50
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdatePetAdoptionStatusRequest) Reset() {
-	*x = UpdatePetAdoptionStatusRequest{}
-	mi := &file_pet_proto_msgTypes[7]
+func (x *SuggestBreedsRequest) Reset() {
+	*x = SuggestBreedsRequest{}
+	mi := &file_pet_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdatePetAdoptionStatusRequest) String() string {
+func (x *SuggestBreedsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdatePetAdoptionStatusRequest) ProtoMessage() {}
+func (*SuggestBreedsRequest) ProtoMessage() {}
 
-func (x *UpdatePetAdoptionStatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pet_proto_msgTypes[7]
+func (x *SuggestBreedsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pet_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -647,30 +1850,75 @@ func (x *UpdatePetAdoptionStatusRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdatePetAdoptionStatusRequest.ProtoReflect.Descriptor instead.
-func (*UpdatePetAdoptionStatusRequest) Descriptor() ([]byte, []int) {
-	return file_pet_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use SuggestBreedsRequest.ProtoReflect.Descriptor instead.
+func (*SuggestBreedsRequest) Descriptor() ([]byte, []int) {
+	return file_pet_proto_rawDescGZIP(), []int{26}
 }
 
-func (x *UpdatePetAdoptionStatusRequest) GetPetId() string {
+func (x *SuggestBreedsRequest) GetSpecies() string {
 	if x != nil {
-		return x.PetId
+		return x.Species
 	}
 	return ""
 }
 
-func (x *UpdatePetAdoptionStatusRequest) GetNewStatus() AdoptionStatus {
+func (x *SuggestBreedsRequest) GetPrefix() string {
 	if x != nil {
-		return x.NewStatus
+		return x.Prefix
 	}
-	return AdoptionStatus_ADOPTION_STATUS_UNSPECIFIED
+	return ""
 }
 
-func (x *UpdatePetAdoptionStatusRequest) GetAdopterUserId() string {
+func (x *SuggestBreedsRequest) GetLimit() int32 {
+	if x != nil && x.Limit != nil {
+		return *x.Limit
+	}
+	return 0
+}
+
+// Distinct breeds in alphabetical order.
+type SuggestBreedsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Breeds        []string               `protobuf:"bytes,1,rep,name=breeds,proto3" json:"breeds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SuggestBreedsResponse) Reset() {
+	*x = SuggestBreedsResponse{}
+	mi := &file_pet_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SuggestBreedsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SuggestBreedsResponse) ProtoMessage() {}
+
+func (x *SuggestBreedsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pet_proto_msgTypes[27]
 	if x != nil {
-		return x.AdopterUserId
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SuggestBreedsResponse.ProtoReflect.Descriptor instead.
+func (*SuggestBreedsResponse) Descriptor() ([]byte, []int) {
+	return file_pet_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *SuggestBreedsResponse) GetBreeds() []string {
+	if x != nil {
+		return x.Breeds
+	}
+	return nil
 }
 
 type PetResponse struct {
@@ -682,7 +1930,7 @@ type PetResponse struct {
 
 func (x *PetResponse) Reset() {
 	*x = PetResponse{}
-	mi := &file_pet_proto_msgTypes[8]
+	mi := &file_pet_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -694,7 +1942,7 @@ func (x *PetResponse) String() string {
 func (*PetResponse) ProtoMessage() {}
 
 func (x *PetResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pet_proto_msgTypes[8]
+	mi := &file_pet_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -707,7 +1955,7 @@ func (x *PetResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PetResponse.ProtoReflect.Descriptor instead.
 func (*PetResponse) Descriptor() ([]byte, []int) {
-	return file_pet_proto_rawDescGZIP(), []int{8}
+	return file_pet_proto_rawDescGZIP(), []int{28}
 }
 
 func (x *PetResponse) GetPet() *Pet {
@@ -725,7 +1973,7 @@ type EmptyResponse struct {
 
 func (x *EmptyResponse) Reset() {
 	*x = EmptyResponse{}
-	mi := &file_pet_proto_msgTypes[9]
+	mi := &file_pet_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -737,7 +1985,7 @@ func (x *EmptyResponse) String() string {
 func (*EmptyResponse) ProtoMessage() {}
 
 func (x *EmptyResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pet_proto_msgTypes[9]
+	mi := &file_pet_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -750,14 +1998,14 @@ func (x *EmptyResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EmptyResponse.ProtoReflect.Descriptor instead.
 func (*EmptyResponse) Descriptor() ([]byte, []int) {
-	return file_pet_proto_rawDescGZIP(), []int{9}
+	return file_pet_proto_rawDescGZIP(), []int{29}
 }
 
 var File_pet_proto protoreflect.FileDescriptor
 
 const file_pet_proto_rawDesc = "" +
 	"\n" +
-	"\tpet.proto\x12\x03pet\"\x80\x03\n" +
+	"\tpet.proto\x12\x03pet\"\xee\x04\n" +
 	"\x03Pet\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x18\n" +
@@ -774,7 +2022,31 @@ const file_pet_proto_rawDesc = "" +
 	"\n" +
 	"updated_at\x18\v \x01(\tR\tupdatedAt\x12\x1d\n" +
 	"\n" +
-	"image_urls\x18\f \x03(\tR\timageUrls\"\xd4\x01\n" +
+	"image_urls\x18\f \x03(\tR\timageUrls\x12\x12\n" +
+	"\x04tags\x18\r \x03(\tR\x04tags\x12;\n" +
+	"\x0estatus_history\x18\x0e \x03(\v2\x14.pet.PetStatusChangeR\rstatusHistory\x12#\n" +
+	"\rthumbnail_url\x18\x0f \x01(\tR\fthumbnailUrl\x12=\n" +
+	"\x0flisting_history\x18\x10 \x03(\v2\x14.pet.ListingTransferR\x0elistingHistory\x12\x18\n" +
+	"\adeleted\x18\x11 \x01(\bR\adeleted\x12\x1d\n" +
+	"\n" +
+	"deleted_at\x18\x12 \x01(\tR\tdeletedAt\"\x9f\x01\n" +
+	"\x0fListingTransfer\x12 \n" +
+	"\ffrom_user_id\x18\x01 \x01(\tR\n" +
+	"fromUserId\x12\x1c\n" +
+	"\n" +
+	"to_user_id\x18\x02 \x01(\tR\btoUserId\x12%\n" +
+	"\x0etransferred_by\x18\x03 \x01(\tR\rtransferredBy\x12%\n" +
+	"\x0etransferred_at\x18\x04 \x01(\tR\rtransferredAt\"\xe7\x01\n" +
+	"\x0fPetStatusChange\x124\n" +
+	"\vfrom_status\x18\x01 \x01(\x0e2\x13.pet.AdoptionStatusR\n" +
+	"fromStatus\x120\n" +
+	"\tto_status\x18\x02 \x01(\x0e2\x13.pet.AdoptionStatusR\btoStatus\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12\x1d\n" +
+	"\n" +
+	"changed_by\x18\x04 \x01(\tR\tchangedBy\x12\x16\n" +
+	"\x06forced\x18\x05 \x01(\bR\x06forced\x12\x1d\n" +
+	"\n" +
+	"changed_at\x18\x06 \x01(\tR\tchangedAt\"\xbf\x02\n" +
 	"\x10CreatePetRequest\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
 	"\aspecies\x18\x02 \x01(\tR\aspecies\x12\x14\n" +
@@ -783,9 +2055,21 @@ const file_pet_proto_rawDesc = "" +
 	"\vdescription\x18\x05 \x01(\tR\vdescription\x12)\n" +
 	"\x11listed_by_user_id\x18\x06 \x01(\tR\x0elistedByUserId\x12\x1d\n" +
 	"\n" +
-	"image_urls\x18\a \x03(\tR\timageUrls\"&\n" +
+	"image_urls\x18\a \x03(\tR\timageUrls\x12\x12\n" +
+	"\x04tags\x18\b \x03(\tR\x04tags\x12A\n" +
+	"\x0fadoption_status\x18\t \x01(\x0e2\x13.pet.AdoptionStatusH\x00R\x0eadoptionStatus\x88\x01\x01B\x12\n" +
+	"\x10_adoption_status\"&\n" +
 	"\rGetPetRequest\x12\x15\n" +
-	"\x06pet_id\x18\x01 \x01(\tR\x05petId\"\x90\x02\n" +
+	"\x06pet_id\x18\x01 \x01(\tR\x05petId\"4\n" +
+	"\x1bCheckPetAvailabilityRequest\x12\x15\n" +
+	"\x06pet_id\x18\x01 \x01(\tR\x05petId\"z\n" +
+	"\x1cCheckPetAvailabilityResponse\x12<\n" +
+	"\x0fadoption_status\x18\x01 \x01(\x0e2\x13.pet.AdoptionStatusR\x0eadoptionStatus\x12\x1c\n" +
+	"\tavailable\x18\x02 \x01(\bR\tavailable\"'\n" +
+	"\x13BatchGetPetsRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\"4\n" +
+	"\x14BatchGetPetsResponse\x12\x1c\n" +
+	"\x04pets\x18\x01 \x03(\v2\b.pet.PetR\x04pets\"\x90\x02\n" +
 	"\x10UpdatePetRequest\x12\x15\n" +
 	"\x06pet_id\x18\x01 \x01(\tR\x05petId\x12\x17\n" +
 	"\x04name\x18\x02 \x01(\tH\x00R\x04name\x88\x01\x01\x12\x1d\n" +
@@ -802,27 +2086,110 @@ const file_pet_proto_rawDesc = "" +
 	"\x04_ageB\x0e\n" +
 	"\f_description\")\n" +
 	"\x10DeletePetRequest\x12\x15\n" +
-	"\x06pet_id\x18\x01 \x01(\tR\x05petId\"\xe8\x01\n" +
+	"\x06pet_id\x18\x01 \x01(\tR\x05petId\"*\n" +
+	"\x11RestorePetRequest\x12\x15\n" +
+	"\x06pet_id\x18\x01 \x01(\tR\x05petId\"\xa2\x06\n" +
 	"\x0fListPetsRequest\x12\x17\n" +
 	"\x04page\x18\x01 \x01(\x05H\x00R\x04page\x88\x01\x01\x12\x19\n" +
 	"\x05limit\x18\x02 \x01(\x05H\x01R\x05limit\x88\x01\x01\x12*\n" +
 	"\x0especies_filter\x18\x03 \x01(\tH\x02R\rspeciesFilter\x88\x01\x01\x12=\n" +
-	"\rstatus_filter\x18\x04 \x01(\x0e2\x13.pet.AdoptionStatusH\x03R\fstatusFilter\x88\x01\x01B\a\n" +
+	"\rstatus_filter\x18\x04 \x01(\x0e2\x13.pet.AdoptionStatusH\x03R\fstatusFilter\x88\x01\x01\x12\x1f\n" +
+	"\vtags_filter\x18\x05 \x03(\tR\n" +
+	"tagsFilter\x12)\n" +
+	"\x0ematch_all_tags\x18\x06 \x01(\bH\x04R\fmatchAllTags\x88\x01\x01\x12\x17\n" +
+	"\x04sort\x18\a \x01(\tH\x05R\x04sort\x88\x01\x01\x12\x1b\n" +
+	"\x06cursor\x18\b \x01(\tH\x06R\x06cursor\x88\x01\x01\x12;\n" +
+	"\x18listed_by_user_id_filter\x18\t \x01(\tH\aR\x14listedByUserIdFilter\x88\x01\x01\x12*\n" +
+	"\x0ecreated_before\x18\n" +
+	" \x01(\tH\bR\rcreatedBefore\x88\x01\x01\x12&\n" +
+	"\fsearch_query\x18\v \x01(\tH\tR\vsearchQuery\x88\x01\x01\x12\x1c\n" +
+	"\amin_age\x18\f \x01(\x05H\n" +
+	"R\x06minAge\x88\x01\x01\x12\x1c\n" +
+	"\amax_age\x18\r \x01(\x05H\vR\x06maxAge\x88\x01\x01\x12'\n" +
+	"\x0fspecies_filters\x18\x0e \x03(\tR\x0especiesFilters\x12,\n" +
+	"\x0finclude_deleted\x18\x0f \x01(\bH\fR\x0eincludeDeleted\x88\x01\x01B\a\n" +
 	"\x05_pageB\b\n" +
 	"\x06_limitB\x11\n" +
 	"\x0f_species_filterB\x10\n" +
-	"\x0e_status_filter\"{\n" +
+	"\x0e_status_filterB\x11\n" +
+	"\x0f_match_all_tagsB\a\n" +
+	"\x05_sortB\t\n" +
+	"\a_cursorB\x1b\n" +
+	"\x19_listed_by_user_id_filterB\x11\n" +
+	"\x0f_created_beforeB\x0f\n" +
+	"\r_search_queryB\n" +
+	"\n" +
+	"\b_min_ageB\n" +
+	"\n" +
+	"\b_max_ageB\x12\n" +
+	"\x10_include_deleted\"\x9c\x01\n" +
 	"\x10ListPetsResponse\x12\x1c\n" +
 	"\x04pets\x18\x01 \x03(\v2\b.pet.PetR\x04pets\x12\x1f\n" +
 	"\vtotal_count\x18\x02 \x01(\x05R\n" +
 	"totalCount\x12\x12\n" +
 	"\x04page\x18\x03 \x01(\x05R\x04page\x12\x14\n" +
-	"\x05limit\x18\x04 \x01(\x05R\x05limit\"\x93\x01\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\x12\x1f\n" +
+	"\vnext_cursor\x18\x05 \x01(\tR\n" +
+	"nextCursor\"\xbb\x01\n" +
 	"\x1eUpdatePetAdoptionStatusRequest\x12\x15\n" +
 	"\x06pet_id\x18\x01 \x01(\tR\x05petId\x122\n" +
 	"\n" +
 	"new_status\x18\x02 \x01(\x0e2\x13.pet.AdoptionStatusR\tnewStatus\x12&\n" +
-	"\x0fadopter_user_id\x18\x03 \x01(\tR\radopterUserId\")\n" +
+	"\x0fadopter_user_id\x18\x03 \x01(\tR\radopterUserId\x12\x1b\n" +
+	"\x06reason\x18\x04 \x01(\tH\x00R\x06reason\x88\x01\x01B\t\n" +
+	"\a_reason\"p\n" +
+	"\x18GetImageUploadURLRequest\x12\x15\n" +
+	"\x06pet_id\x18\x01 \x01(\tR\x05petId\x12\x1a\n" +
+	"\bfilename\x18\x02 \x01(\tR\bfilename\x12!\n" +
+	"\fcontent_type\x18\x03 \x01(\tR\vcontentType\"\xda\x01\n" +
+	"\x11ImageUploadTarget\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12:\n" +
+	"\x06fields\x18\x02 \x03(\v2\".pet.ImageUploadTarget.FieldsEntryR\x06fields\x12\x1d\n" +
+	"\n" +
+	"public_url\x18\x03 \x01(\tR\tpublicUrl\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\tR\texpiresAt\x1a9\n" +
+	"\vFieldsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"K\n" +
+	"\x13AddImageURLsRequest\x12\x15\n" +
+	"\x06pet_id\x18\x01 \x01(\tR\x05petId\x12\x1d\n" +
+	"\n" +
+	"image_urls\x18\x02 \x03(\tR\timageUrls\"A\n" +
+	"\x1aListRecentlyAdoptedRequest\x12\x19\n" +
+	"\x05limit\x18\x01 \x01(\x05H\x00R\x05limit\x88\x01\x01B\b\n" +
+	"\x06_limit\";\n" +
+	"\x1bListRecentlyAdoptedResponse\x12\x1c\n" +
+	"\x04pets\x18\x01 \x03(\v2\b.pet.PetR\x04pets\";\n" +
+	"\x0ePetTagsRequest\x12\x15\n" +
+	"\x06pet_id\x18\x01 \x01(\tR\x05petId\x12\x12\n" +
+	"\x04tags\x18\x02 \x03(\tR\x04tags\"\x9c\x01\n" +
+	"\x18AdminSetPetStatusRequest\x12\x15\n" +
+	"\x06pet_id\x18\x01 \x01(\tR\x05petId\x122\n" +
+	"\n" +
+	"new_status\x18\x02 \x01(\x0e2\x13.pet.AdoptionStatusR\tnewStatus\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12\x1d\n" +
+	"\n" +
+	"changed_by\x18\x04 \x01(\tR\tchangedBy\"]\n" +
+	"\x19TransferPetListingRequest\x12\x15\n" +
+	"\x06pet_id\x18\x01 \x01(\tR\x05petId\x12)\n" +
+	"\x11new_owner_user_id\x18\x02 \x01(\tR\x0enewOwnerUserId\"\x15\n" +
+	"\x13GetPetFacetsRequest\"8\n" +
+	"\n" +
+	"FacetCount\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\tR\x05value\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\"\xa5\x01\n" +
+	"\x11PetFacetsResponse\x12)\n" +
+	"\aspecies\x18\x01 \x03(\v2\x0f.pet.FacetCountR\aspecies\x12'\n" +
+	"\x06breeds\x18\x02 \x03(\v2\x0f.pet.FacetCountR\x06breeds\x12<\n" +
+	"\x11adoption_statuses\x18\x03 \x03(\v2\x0f.pet.FacetCountR\x10adoptionStatuses\"m\n" +
+	"\x14SuggestBreedsRequest\x12\x18\n" +
+	"\aspecies\x18\x01 \x01(\tR\aspecies\x12\x16\n" +
+	"\x06prefix\x18\x02 \x01(\tR\x06prefix\x12\x19\n" +
+	"\x05limit\x18\x03 \x01(\x05H\x00R\x05limit\x88\x01\x01B\b\n" +
+	"\x06_limit\"/\n" +
+	"\x15SuggestBreedsResponse\x12\x16\n" +
+	"\x06breeds\x18\x01 \x03(\tR\x06breeds\")\n" +
 	"\vPetResponse\x12\x1a\n" +
 	"\x03pet\x18\x01 \x01(\v2\b.pet.PetR\x03pet\"\x0f\n" +
 	"\rEmptyResponse*c\n" +
@@ -830,15 +2197,29 @@ const file_pet_proto_rawDesc = "" +
 	"\x1bADOPTION_STATUS_UNSPECIFIED\x10\x00\x12\r\n" +
 	"\tAVAILABLE\x10\x01\x12\x14\n" +
 	"\x10PENDING_ADOPTION\x10\x02\x12\v\n" +
-	"\aADOPTED\x10\x032\xeb\x02\n" +
+	"\aADOPTED\x10\x032\xac\t\n" +
 	"\n" +
 	"PetService\x124\n" +
 	"\tCreatePet\x12\x15.pet.CreatePetRequest\x1a\x10.pet.PetResponse\x12.\n" +
-	"\x06GetPet\x12\x12.pet.GetPetRequest\x1a\x10.pet.PetResponse\x124\n" +
+	"\x06GetPet\x12\x12.pet.GetPetRequest\x1a\x10.pet.PetResponse\x12[\n" +
+	"\x14CheckPetAvailability\x12 .pet.CheckPetAvailabilityRequest\x1a!.pet.CheckPetAvailabilityResponse\x12C\n" +
+	"\fBatchGetPets\x12\x18.pet.BatchGetPetsRequest\x1a\x19.pet.BatchGetPetsResponse\x124\n" +
 	"\tUpdatePet\x12\x15.pet.UpdatePetRequest\x1a\x10.pet.PetResponse\x126\n" +
-	"\tDeletePet\x12\x15.pet.DeletePetRequest\x1a\x12.pet.EmptyResponse\x127\n" +
+	"\tDeletePet\x12\x15.pet.DeletePetRequest\x1a\x12.pet.EmptyResponse\x126\n" +
+	"\n" +
+	"RestorePet\x12\x16.pet.RestorePetRequest\x1a\x10.pet.PetResponse\x127\n" +
 	"\bListPets\x12\x14.pet.ListPetsRequest\x1a\x15.pet.ListPetsResponse\x12P\n" +
-	"\x17UpdatePetAdoptionStatus\x12#.pet.UpdatePetAdoptionStatusRequest\x1a\x10.pet.PetResponseB=Z;github.com/zhandarbeks/petstore-final-project/genprotos/petb\x06proto3"
+	"\x17UpdatePetAdoptionStatus\x12#.pet.UpdatePetAdoptionStatusRequest\x1a\x10.pet.PetResponse\x12J\n" +
+	"\x11GetImageUploadURL\x12\x1d.pet.GetImageUploadURLRequest\x1a\x16.pet.ImageUploadTarget\x12:\n" +
+	"\fAddImageURLs\x12\x18.pet.AddImageURLsRequest\x1a\x10.pet.PetResponse\x12X\n" +
+	"\x13ListRecentlyAdopted\x12\x1f.pet.ListRecentlyAdoptedRequest\x1a .pet.ListRecentlyAdoptedResponse\x12@\n" +
+	"\fGetPetFacets\x12\x18.pet.GetPetFacetsRequest\x1a\x16.pet.PetFacetsResponse\x12F\n" +
+	"\rSuggestBreeds\x12\x19.pet.SuggestBreedsRequest\x1a\x1a.pet.SuggestBreedsResponse\x123\n" +
+	"\n" +
+	"AddPetTags\x12\x13.pet.PetTagsRequest\x1a\x10.pet.PetResponse\x126\n" +
+	"\rRemovePetTags\x12\x13.pet.PetTagsRequest\x1a\x10.pet.PetResponse\x12D\n" +
+	"\x11AdminSetPetStatus\x12\x1d.pet.AdminSetPetStatusRequest\x1a\x10.pet.PetResponse\x12F\n" +
+	"\x12TransferPetListing\x12\x1e.pet.TransferPetListingRequest\x1a\x10.pet.PetResponseB=Z;github.com/zhandarbeks/petstore-final-project/genprotos/petb\x06proto3"
 
 var (
 	file_pet_proto_rawDescOnce sync.Once
@@ -853,43 +2234,101 @@ func file_pet_proto_rawDescGZIP() []byte {
 }
 
 var file_pet_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_pet_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_pet_proto_msgTypes = make([]protoimpl.MessageInfo, 31)
 var file_pet_proto_goTypes = []any{
 	(AdoptionStatus)(0),                    // 0: pet.AdoptionStatus
 	(*Pet)(nil),                            // 1: pet.Pet
-	(*CreatePetRequest)(nil),               // 2: pet.CreatePetRequest
-	(*GetPetRequest)(nil),                  // 3: pet.GetPetRequest
-	(*UpdatePetRequest)(nil),               // 4: pet.UpdatePetRequest
-	(*DeletePetRequest)(nil),               // 5: pet.DeletePetRequest
-	(*ListPetsRequest)(nil),                // 6: pet.ListPetsRequest
-	(*ListPetsResponse)(nil),               // 7: pet.ListPetsResponse
-	(*UpdatePetAdoptionStatusRequest)(nil), // 8: pet.UpdatePetAdoptionStatusRequest
-	(*PetResponse)(nil),                    // 9: pet.PetResponse
-	(*EmptyResponse)(nil),                  // 10: pet.EmptyResponse
+	(*ListingTransfer)(nil),                // 2: pet.ListingTransfer
+	(*PetStatusChange)(nil),                // 3: pet.PetStatusChange
+	(*CreatePetRequest)(nil),               // 4: pet.CreatePetRequest
+	(*GetPetRequest)(nil),                  // 5: pet.GetPetRequest
+	(*CheckPetAvailabilityRequest)(nil),    // 6: pet.CheckPetAvailabilityRequest
+	(*CheckPetAvailabilityResponse)(nil),   // 7: pet.CheckPetAvailabilityResponse
+	(*BatchGetPetsRequest)(nil),            // 8: pet.BatchGetPetsRequest
+	(*BatchGetPetsResponse)(nil),           // 9: pet.BatchGetPetsResponse
+	(*UpdatePetRequest)(nil),               // 10: pet.UpdatePetRequest
+	(*DeletePetRequest)(nil),               // 11: pet.DeletePetRequest
+	(*RestorePetRequest)(nil),              // 12: pet.RestorePetRequest
+	(*ListPetsRequest)(nil),                // 13: pet.ListPetsRequest
+	(*ListPetsResponse)(nil),               // 14: pet.ListPetsResponse
+	(*UpdatePetAdoptionStatusRequest)(nil), // 15: pet.UpdatePetAdoptionStatusRequest
+	(*GetImageUploadURLRequest)(nil),       // 16: pet.GetImageUploadURLRequest
+	(*ImageUploadTarget)(nil),              // 17: pet.ImageUploadTarget
+	(*AddImageURLsRequest)(nil),            // 18: pet.AddImageURLsRequest
+	(*ListRecentlyAdoptedRequest)(nil),     // 19: pet.ListRecentlyAdoptedRequest
+	(*ListRecentlyAdoptedResponse)(nil),    // 20: pet.ListRecentlyAdoptedResponse
+	(*PetTagsRequest)(nil),                 // 21: pet.PetTagsRequest
+	(*AdminSetPetStatusRequest)(nil),       // 22: pet.AdminSetPetStatusRequest
+	(*TransferPetListingRequest)(nil),      // 23: pet.TransferPetListingRequest
+	(*GetPetFacetsRequest)(nil),            // 24: pet.GetPetFacetsRequest
+	(*FacetCount)(nil),                     // 25: pet.FacetCount
+	(*PetFacetsResponse)(nil),              // 26: pet.PetFacetsResponse
+	(*SuggestBreedsRequest)(nil),           // 27: pet.SuggestBreedsRequest
+	(*SuggestBreedsResponse)(nil),          // 28: pet.SuggestBreedsResponse
+	(*PetResponse)(nil),                    // 29: pet.PetResponse
+	(*EmptyResponse)(nil),                  // 30: pet.EmptyResponse
+	nil,                                    // 31: pet.ImageUploadTarget.FieldsEntry
 }
 var file_pet_proto_depIdxs = []int32{
 	0,  // 0: pet.Pet.adoption_status:type_name -> pet.AdoptionStatus
-	0,  // 1: pet.ListPetsRequest.status_filter:type_name -> pet.AdoptionStatus
-	1,  // 2: pet.ListPetsResponse.pets:type_name -> pet.Pet
-	0,  // 3: pet.UpdatePetAdoptionStatusRequest.new_status:type_name -> pet.AdoptionStatus
-	1,  // 4: pet.PetResponse.pet:type_name -> pet.Pet
-	2,  // 5: pet.PetService.CreatePet:input_type -> pet.CreatePetRequest
-	3,  // 6: pet.PetService.GetPet:input_type -> pet.GetPetRequest
-	4,  // 7: pet.PetService.UpdatePet:input_type -> pet.UpdatePetRequest
-	5,  // 8: pet.PetService.DeletePet:input_type -> pet.DeletePetRequest
-	6,  // 9: pet.PetService.ListPets:input_type -> pet.ListPetsRequest
-	8,  // 10: pet.PetService.UpdatePetAdoptionStatus:input_type -> pet.UpdatePetAdoptionStatusRequest
-	9,  // 11: pet.PetService.CreatePet:output_type -> pet.PetResponse
-	9,  // 12: pet.PetService.GetPet:output_type -> pet.PetResponse
-	9,  // 13: pet.PetService.UpdatePet:output_type -> pet.PetResponse
-	10, // 14: pet.PetService.DeletePet:output_type -> pet.EmptyResponse
-	7,  // 15: pet.PetService.ListPets:output_type -> pet.ListPetsResponse
-	9,  // 16: pet.PetService.UpdatePetAdoptionStatus:output_type -> pet.PetResponse
-	11, // [11:17] is the sub-list for method output_type
-	5,  // [5:11] is the sub-list for method input_type
-	5,  // [5:5] is the sub-list for extension type_name
-	5,  // [5:5] is the sub-list for extension extendee
-	0,  // [0:5] is the sub-list for field type_name
+	3,  // 1: pet.Pet.status_history:type_name -> pet.PetStatusChange
+	2,  // 2: pet.Pet.listing_history:type_name -> pet.ListingTransfer
+	0,  // 3: pet.PetStatusChange.from_status:type_name -> pet.AdoptionStatus
+	0,  // 4: pet.PetStatusChange.to_status:type_name -> pet.AdoptionStatus
+	0,  // 5: pet.CreatePetRequest.adoption_status:type_name -> pet.AdoptionStatus
+	0,  // 6: pet.CheckPetAvailabilityResponse.adoption_status:type_name -> pet.AdoptionStatus
+	1,  // 7: pet.BatchGetPetsResponse.pets:type_name -> pet.Pet
+	0,  // 8: pet.ListPetsRequest.status_filter:type_name -> pet.AdoptionStatus
+	1,  // 9: pet.ListPetsResponse.pets:type_name -> pet.Pet
+	0,  // 10: pet.UpdatePetAdoptionStatusRequest.new_status:type_name -> pet.AdoptionStatus
+	31, // 11: pet.ImageUploadTarget.fields:type_name -> pet.ImageUploadTarget.FieldsEntry
+	1,  // 12: pet.ListRecentlyAdoptedResponse.pets:type_name -> pet.Pet
+	0,  // 13: pet.AdminSetPetStatusRequest.new_status:type_name -> pet.AdoptionStatus
+	25, // 14: pet.PetFacetsResponse.species:type_name -> pet.FacetCount
+	25, // 15: pet.PetFacetsResponse.breeds:type_name -> pet.FacetCount
+	25, // 16: pet.PetFacetsResponse.adoption_statuses:type_name -> pet.FacetCount
+	1,  // 17: pet.PetResponse.pet:type_name -> pet.Pet
+	4,  // 18: pet.PetService.CreatePet:input_type -> pet.CreatePetRequest
+	5,  // 19: pet.PetService.GetPet:input_type -> pet.GetPetRequest
+	6,  // 20: pet.PetService.CheckPetAvailability:input_type -> pet.CheckPetAvailabilityRequest
+	8,  // 21: pet.PetService.BatchGetPets:input_type -> pet.BatchGetPetsRequest
+	10, // 22: pet.PetService.UpdatePet:input_type -> pet.UpdatePetRequest
+	11, // 23: pet.PetService.DeletePet:input_type -> pet.DeletePetRequest
+	12, // 24: pet.PetService.RestorePet:input_type -> pet.RestorePetRequest
+	13, // 25: pet.PetService.ListPets:input_type -> pet.ListPetsRequest
+	15, // 26: pet.PetService.UpdatePetAdoptionStatus:input_type -> pet.UpdatePetAdoptionStatusRequest
+	16, // 27: pet.PetService.GetImageUploadURL:input_type -> pet.GetImageUploadURLRequest
+	18, // 28: pet.PetService.AddImageURLs:input_type -> pet.AddImageURLsRequest
+	19, // 29: pet.PetService.ListRecentlyAdopted:input_type -> pet.ListRecentlyAdoptedRequest
+	24, // 30: pet.PetService.GetPetFacets:input_type -> pet.GetPetFacetsRequest
+	27, // 31: pet.PetService.SuggestBreeds:input_type -> pet.SuggestBreedsRequest
+	21, // 32: pet.PetService.AddPetTags:input_type -> pet.PetTagsRequest
+	21, // 33: pet.PetService.RemovePetTags:input_type -> pet.PetTagsRequest
+	22, // 34: pet.PetService.AdminSetPetStatus:input_type -> pet.AdminSetPetStatusRequest
+	23, // 35: pet.PetService.TransferPetListing:input_type -> pet.TransferPetListingRequest
+	29, // 36: pet.PetService.CreatePet:output_type -> pet.PetResponse
+	29, // 37: pet.PetService.GetPet:output_type -> pet.PetResponse
+	7,  // 38: pet.PetService.CheckPetAvailability:output_type -> pet.CheckPetAvailabilityResponse
+	9,  // 39: pet.PetService.BatchGetPets:output_type -> pet.BatchGetPetsResponse
+	29, // 40: pet.PetService.UpdatePet:output_type -> pet.PetResponse
+	30, // 41: pet.PetService.DeletePet:output_type -> pet.EmptyResponse
+	29, // 42: pet.PetService.RestorePet:output_type -> pet.PetResponse
+	14, // 43: pet.PetService.ListPets:output_type -> pet.ListPetsResponse
+	29, // 44: pet.PetService.UpdatePetAdoptionStatus:output_type -> pet.PetResponse
+	17, // 45: pet.PetService.GetImageUploadURL:output_type -> pet.ImageUploadTarget
+	29, // 46: pet.PetService.AddImageURLs:output_type -> pet.PetResponse
+	20, // 47: pet.PetService.ListRecentlyAdopted:output_type -> pet.ListRecentlyAdoptedResponse
+	26, // 48: pet.PetService.GetPetFacets:output_type -> pet.PetFacetsResponse
+	28, // 49: pet.PetService.SuggestBreeds:output_type -> pet.SuggestBreedsResponse
+	29, // 50: pet.PetService.AddPetTags:output_type -> pet.PetResponse
+	29, // 51: pet.PetService.RemovePetTags:output_type -> pet.PetResponse
+	29, // 52: pet.PetService.AdminSetPetStatus:output_type -> pet.PetResponse
+	29, // 53: pet.PetService.TransferPetListing:output_type -> pet.PetResponse
+	36, // [36:54] is the sub-list for method output_type
+	18, // [18:36] is the sub-list for method input_type
+	18, // [18:18] is the sub-list for extension type_name
+	18, // [18:18] is the sub-list for extension extendee
+	0,  // [0:18] is the sub-list for field type_name
 }
 
 func init() { file_pet_proto_init() }
@@ -898,14 +2337,18 @@ func file_pet_proto_init() {
 		return
 	}
 	file_pet_proto_msgTypes[3].OneofWrappers = []any{}
-	file_pet_proto_msgTypes[5].OneofWrappers = []any{}
+	file_pet_proto_msgTypes[9].OneofWrappers = []any{}
+	file_pet_proto_msgTypes[12].OneofWrappers = []any{}
+	file_pet_proto_msgTypes[14].OneofWrappers = []any{}
+	file_pet_proto_msgTypes[18].OneofWrappers = []any{}
+	file_pet_proto_msgTypes[26].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pet_proto_rawDesc), len(file_pet_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   10,
+			NumMessages:   31,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
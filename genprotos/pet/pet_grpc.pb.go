@@ -21,10 +21,22 @@ const _ = grpc.SupportPackageIsVersion9
 const (
 	PetService_CreatePet_FullMethodName               = "/pet.PetService/CreatePet"
 	PetService_GetPet_FullMethodName                  = "/pet.PetService/GetPet"
+	PetService_CheckPetAvailability_FullMethodName    = "/pet.PetService/CheckPetAvailability"
+	PetService_BatchGetPets_FullMethodName            = "/pet.PetService/BatchGetPets"
 	PetService_UpdatePet_FullMethodName               = "/pet.PetService/UpdatePet"
 	PetService_DeletePet_FullMethodName               = "/pet.PetService/DeletePet"
+	PetService_RestorePet_FullMethodName              = "/pet.PetService/RestorePet"
 	PetService_ListPets_FullMethodName                = "/pet.PetService/ListPets"
 	PetService_UpdatePetAdoptionStatus_FullMethodName = "/pet.PetService/UpdatePetAdoptionStatus"
+	PetService_GetImageUploadURL_FullMethodName       = "/pet.PetService/GetImageUploadURL"
+	PetService_AddImageURLs_FullMethodName            = "/pet.PetService/AddImageURLs"
+	PetService_ListRecentlyAdopted_FullMethodName     = "/pet.PetService/ListRecentlyAdopted"
+	PetService_GetPetFacets_FullMethodName            = "/pet.PetService/GetPetFacets"
+	PetService_SuggestBreeds_FullMethodName           = "/pet.PetService/SuggestBreeds"
+	PetService_AddPetTags_FullMethodName              = "/pet.PetService/AddPetTags"
+	PetService_RemovePetTags_FullMethodName           = "/pet.PetService/RemovePetTags"
+	PetService_AdminSetPetStatus_FullMethodName       = "/pet.PetService/AdminSetPetStatus"
+	PetService_TransferPetListing_FullMethodName      = "/pet.PetService/TransferPetListing"
 )
 
 // PetServiceClient is the client API for PetService service.
@@ -33,10 +45,27 @@ const (
 type PetServiceClient interface {
 	CreatePet(ctx context.Context, in *CreatePetRequest, opts ...grpc.CallOption) (*PetResponse, error)
 	GetPet(ctx context.Context, in *GetPetRequest, opts ...grpc.CallOption) (*PetResponse, error)
+	// Whether a pet can currently be adopted; used by the adoption-service before accepting applications.
+	CheckPetAvailability(ctx context.Context, in *CheckPetAvailabilityRequest, opts ...grpc.CallOption) (*CheckPetAvailabilityResponse, error)
+	// Several pets in one call. Pets that do not exist are left out rather than failing the call.
+	BatchGetPets(ctx context.Context, in *BatchGetPetsRequest, opts ...grpc.CallOption) (*BatchGetPetsResponse, error)
 	UpdatePet(ctx context.Context, in *UpdatePetRequest, opts ...grpc.CallOption) (*PetResponse, error)
 	DeletePet(ctx context.Context, in *DeletePetRequest, opts ...grpc.CallOption) (*EmptyResponse, error)
+	// Undoes DeletePet. The caller (x-user-id metadata) must have listed the pet or be an admin.
+	RestorePet(ctx context.Context, in *RestorePetRequest, opts ...grpc.CallOption) (*PetResponse, error)
 	ListPets(ctx context.Context, in *ListPetsRequest, opts ...grpc.CallOption) (*ListPetsResponse, error)
 	UpdatePetAdoptionStatus(ctx context.Context, in *UpdatePetAdoptionStatusRequest, opts ...grpc.CallOption) (*PetResponse, error)
+	GetImageUploadURL(ctx context.Context, in *GetImageUploadURLRequest, opts ...grpc.CallOption) (*ImageUploadTarget, error)
+	AddImageURLs(ctx context.Context, in *AddImageURLsRequest, opts ...grpc.CallOption) (*PetResponse, error)
+	ListRecentlyAdopted(ctx context.Context, in *ListRecentlyAdoptedRequest, opts ...grpc.CallOption) (*ListRecentlyAdoptedResponse, error)
+	GetPetFacets(ctx context.Context, in *GetPetFacetsRequest, opts ...grpc.CallOption) (*PetFacetsResponse, error)
+	SuggestBreeds(ctx context.Context, in *SuggestBreedsRequest, opts ...grpc.CallOption) (*SuggestBreedsResponse, error)
+	AddPetTags(ctx context.Context, in *PetTagsRequest, opts ...grpc.CallOption) (*PetResponse, error)
+	RemovePetTags(ctx context.Context, in *PetTagsRequest, opts ...grpc.CallOption) (*PetResponse, error)
+	// Admin-only (x-user-roles must contain "admin"): sets any status, bypassing transition rules.
+	AdminSetPetStatus(ctx context.Context, in *AdminSetPetStatusRequest, opts ...grpc.CallOption) (*PetResponse, error)
+	// Reassigns a listing to another user. The caller (x-user-id metadata) must be the current owner or an admin.
+	TransferPetListing(ctx context.Context, in *TransferPetListingRequest, opts ...grpc.CallOption) (*PetResponse, error)
 }
 
 type petServiceClient struct {
@@ -67,6 +96,26 @@ func (c *petServiceClient) GetPet(ctx context.Context, in *GetPetRequest, opts .
 	return out, nil
 }
 
+func (c *petServiceClient) CheckPetAvailability(ctx context.Context, in *CheckPetAvailabilityRequest, opts ...grpc.CallOption) (*CheckPetAvailabilityResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CheckPetAvailabilityResponse)
+	err := c.cc.Invoke(ctx, PetService_CheckPetAvailability_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *petServiceClient) BatchGetPets(ctx context.Context, in *BatchGetPetsRequest, opts ...grpc.CallOption) (*BatchGetPetsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchGetPetsResponse)
+	err := c.cc.Invoke(ctx, PetService_BatchGetPets_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *petServiceClient) UpdatePet(ctx context.Context, in *UpdatePetRequest, opts ...grpc.CallOption) (*PetResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(PetResponse)
@@ -87,6 +136,16 @@ func (c *petServiceClient) DeletePet(ctx context.Context, in *DeletePetRequest,
 	return out, nil
 }
 
+func (c *petServiceClient) RestorePet(ctx context.Context, in *RestorePetRequest, opts ...grpc.CallOption) (*PetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PetResponse)
+	err := c.cc.Invoke(ctx, PetService_RestorePet_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *petServiceClient) ListPets(ctx context.Context, in *ListPetsRequest, opts ...grpc.CallOption) (*ListPetsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ListPetsResponse)
@@ -107,16 +166,123 @@ func (c *petServiceClient) UpdatePetAdoptionStatus(ctx context.Context, in *Upda
 	return out, nil
 }
 
+func (c *petServiceClient) GetImageUploadURL(ctx context.Context, in *GetImageUploadURLRequest, opts ...grpc.CallOption) (*ImageUploadTarget, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ImageUploadTarget)
+	err := c.cc.Invoke(ctx, PetService_GetImageUploadURL_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *petServiceClient) AddImageURLs(ctx context.Context, in *AddImageURLsRequest, opts ...grpc.CallOption) (*PetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PetResponse)
+	err := c.cc.Invoke(ctx, PetService_AddImageURLs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *petServiceClient) ListRecentlyAdopted(ctx context.Context, in *ListRecentlyAdoptedRequest, opts ...grpc.CallOption) (*ListRecentlyAdoptedResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRecentlyAdoptedResponse)
+	err := c.cc.Invoke(ctx, PetService_ListRecentlyAdopted_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *petServiceClient) GetPetFacets(ctx context.Context, in *GetPetFacetsRequest, opts ...grpc.CallOption) (*PetFacetsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PetFacetsResponse)
+	err := c.cc.Invoke(ctx, PetService_GetPetFacets_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *petServiceClient) SuggestBreeds(ctx context.Context, in *SuggestBreedsRequest, opts ...grpc.CallOption) (*SuggestBreedsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SuggestBreedsResponse)
+	err := c.cc.Invoke(ctx, PetService_SuggestBreeds_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *petServiceClient) AddPetTags(ctx context.Context, in *PetTagsRequest, opts ...grpc.CallOption) (*PetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PetResponse)
+	err := c.cc.Invoke(ctx, PetService_AddPetTags_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *petServiceClient) RemovePetTags(ctx context.Context, in *PetTagsRequest, opts ...grpc.CallOption) (*PetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PetResponse)
+	err := c.cc.Invoke(ctx, PetService_RemovePetTags_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *petServiceClient) AdminSetPetStatus(ctx context.Context, in *AdminSetPetStatusRequest, opts ...grpc.CallOption) (*PetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PetResponse)
+	err := c.cc.Invoke(ctx, PetService_AdminSetPetStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *petServiceClient) TransferPetListing(ctx context.Context, in *TransferPetListingRequest, opts ...grpc.CallOption) (*PetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PetResponse)
+	err := c.cc.Invoke(ctx, PetService_TransferPetListing_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PetServiceServer is the server API for PetService service.
 // All implementations must embed UnimplementedPetServiceServer
 // for forward compatibility.
 type PetServiceServer interface {
 	CreatePet(context.Context, *CreatePetRequest) (*PetResponse, error)
 	GetPet(context.Context, *GetPetRequest) (*PetResponse, error)
+	// Whether a pet can currently be adopted; used by the adoption-service before accepting applications.
+	CheckPetAvailability(context.Context, *CheckPetAvailabilityRequest) (*CheckPetAvailabilityResponse, error)
+	// Several pets in one call. Pets that do not exist are left out rather than failing the call.
+	BatchGetPets(context.Context, *BatchGetPetsRequest) (*BatchGetPetsResponse, error)
 	UpdatePet(context.Context, *UpdatePetRequest) (*PetResponse, error)
 	DeletePet(context.Context, *DeletePetRequest) (*EmptyResponse, error)
+	// Undoes DeletePet. The caller (x-user-id metadata) must have listed the pet or be an admin.
+	RestorePet(context.Context, *RestorePetRequest) (*PetResponse, error)
 	ListPets(context.Context, *ListPetsRequest) (*ListPetsResponse, error)
 	UpdatePetAdoptionStatus(context.Context, *UpdatePetAdoptionStatusRequest) (*PetResponse, error)
+	GetImageUploadURL(context.Context, *GetImageUploadURLRequest) (*ImageUploadTarget, error)
+	AddImageURLs(context.Context, *AddImageURLsRequest) (*PetResponse, error)
+	ListRecentlyAdopted(context.Context, *ListRecentlyAdoptedRequest) (*ListRecentlyAdoptedResponse, error)
+	GetPetFacets(context.Context, *GetPetFacetsRequest) (*PetFacetsResponse, error)
+	SuggestBreeds(context.Context, *SuggestBreedsRequest) (*SuggestBreedsResponse, error)
+	AddPetTags(context.Context, *PetTagsRequest) (*PetResponse, error)
+	RemovePetTags(context.Context, *PetTagsRequest) (*PetResponse, error)
+	// Admin-only (x-user-roles must contain "admin"): sets any status, bypassing transition rules.
+	AdminSetPetStatus(context.Context, *AdminSetPetStatusRequest) (*PetResponse, error)
+	// Reassigns a listing to another user. The caller (x-user-id metadata) must be the current owner or an admin.
+	TransferPetListing(context.Context, *TransferPetListingRequest) (*PetResponse, error)
 	mustEmbedUnimplementedPetServiceServer()
 }
 
@@ -133,18 +299,54 @@ func (UnimplementedPetServiceServer) CreatePet(context.Context, *CreatePetReques
 func (UnimplementedPetServiceServer) GetPet(context.Context, *GetPetRequest) (*PetResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetPet not implemented")
 }
+func (UnimplementedPetServiceServer) CheckPetAvailability(context.Context, *CheckPetAvailabilityRequest) (*CheckPetAvailabilityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckPetAvailability not implemented")
+}
+func (UnimplementedPetServiceServer) BatchGetPets(context.Context, *BatchGetPetsRequest) (*BatchGetPetsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchGetPets not implemented")
+}
 func (UnimplementedPetServiceServer) UpdatePet(context.Context, *UpdatePetRequest) (*PetResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdatePet not implemented")
 }
 func (UnimplementedPetServiceServer) DeletePet(context.Context, *DeletePetRequest) (*EmptyResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeletePet not implemented")
 }
+func (UnimplementedPetServiceServer) RestorePet(context.Context, *RestorePetRequest) (*PetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestorePet not implemented")
+}
 func (UnimplementedPetServiceServer) ListPets(context.Context, *ListPetsRequest) (*ListPetsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListPets not implemented")
 }
 func (UnimplementedPetServiceServer) UpdatePetAdoptionStatus(context.Context, *UpdatePetAdoptionStatusRequest) (*PetResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdatePetAdoptionStatus not implemented")
 }
+func (UnimplementedPetServiceServer) GetImageUploadURL(context.Context, *GetImageUploadURLRequest) (*ImageUploadTarget, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetImageUploadURL not implemented")
+}
+func (UnimplementedPetServiceServer) AddImageURLs(context.Context, *AddImageURLsRequest) (*PetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddImageURLs not implemented")
+}
+func (UnimplementedPetServiceServer) ListRecentlyAdopted(context.Context, *ListRecentlyAdoptedRequest) (*ListRecentlyAdoptedResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRecentlyAdopted not implemented")
+}
+func (UnimplementedPetServiceServer) GetPetFacets(context.Context, *GetPetFacetsRequest) (*PetFacetsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPetFacets not implemented")
+}
+func (UnimplementedPetServiceServer) SuggestBreeds(context.Context, *SuggestBreedsRequest) (*SuggestBreedsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SuggestBreeds not implemented")
+}
+func (UnimplementedPetServiceServer) AddPetTags(context.Context, *PetTagsRequest) (*PetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddPetTags not implemented")
+}
+func (UnimplementedPetServiceServer) RemovePetTags(context.Context, *PetTagsRequest) (*PetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemovePetTags not implemented")
+}
+func (UnimplementedPetServiceServer) AdminSetPetStatus(context.Context, *AdminSetPetStatusRequest) (*PetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdminSetPetStatus not implemented")
+}
+func (UnimplementedPetServiceServer) TransferPetListing(context.Context, *TransferPetListingRequest) (*PetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TransferPetListing not implemented")
+}
 func (UnimplementedPetServiceServer) mustEmbedUnimplementedPetServiceServer() {}
 func (UnimplementedPetServiceServer) testEmbeddedByValue()                    {}
 
@@ -202,6 +404,42 @@ func _PetService_GetPet_Handler(srv interface{}, ctx context.Context, dec func(i
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PetService_CheckPetAvailability_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckPetAvailabilityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PetServiceServer).CheckPetAvailability(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PetService_CheckPetAvailability_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PetServiceServer).CheckPetAvailability(ctx, req.(*CheckPetAvailabilityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PetService_BatchGetPets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetPetsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PetServiceServer).BatchGetPets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PetService_BatchGetPets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PetServiceServer).BatchGetPets(ctx, req.(*BatchGetPetsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _PetService_UpdatePet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(UpdatePetRequest)
 	if err := dec(in); err != nil {
@@ -238,6 +476,24 @@ func _PetService_DeletePet_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PetService_RestorePet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestorePetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PetServiceServer).RestorePet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PetService_RestorePet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PetServiceServer).RestorePet(ctx, req.(*RestorePetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _PetService_ListPets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListPetsRequest)
 	if err := dec(in); err != nil {
@@ -274,6 +530,168 @@ func _PetService_UpdatePetAdoptionStatus_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PetService_GetImageUploadURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetImageUploadURLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PetServiceServer).GetImageUploadURL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PetService_GetImageUploadURL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PetServiceServer).GetImageUploadURL(ctx, req.(*GetImageUploadURLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PetService_AddImageURLs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddImageURLsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PetServiceServer).AddImageURLs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PetService_AddImageURLs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PetServiceServer).AddImageURLs(ctx, req.(*AddImageURLsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PetService_ListRecentlyAdopted_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRecentlyAdoptedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PetServiceServer).ListRecentlyAdopted(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PetService_ListRecentlyAdopted_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PetServiceServer).ListRecentlyAdopted(ctx, req.(*ListRecentlyAdoptedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PetService_GetPetFacets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPetFacetsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PetServiceServer).GetPetFacets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PetService_GetPetFacets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PetServiceServer).GetPetFacets(ctx, req.(*GetPetFacetsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PetService_SuggestBreeds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SuggestBreedsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PetServiceServer).SuggestBreeds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PetService_SuggestBreeds_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PetServiceServer).SuggestBreeds(ctx, req.(*SuggestBreedsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PetService_AddPetTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PetTagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PetServiceServer).AddPetTags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PetService_AddPetTags_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PetServiceServer).AddPetTags(ctx, req.(*PetTagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PetService_RemovePetTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PetTagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PetServiceServer).RemovePetTags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PetService_RemovePetTags_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PetServiceServer).RemovePetTags(ctx, req.(*PetTagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PetService_AdminSetPetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminSetPetStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PetServiceServer).AdminSetPetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PetService_AdminSetPetStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PetServiceServer).AdminSetPetStatus(ctx, req.(*AdminSetPetStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PetService_TransferPetListing_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransferPetListingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PetServiceServer).TransferPetListing(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PetService_TransferPetListing_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PetServiceServer).TransferPetListing(ctx, req.(*TransferPetListingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // PetService_ServiceDesc is the grpc.ServiceDesc for PetService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -289,6 +707,14 @@ var PetService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetPet",
 			Handler:    _PetService_GetPet_Handler,
 		},
+		{
+			MethodName: "CheckPetAvailability",
+			Handler:    _PetService_CheckPetAvailability_Handler,
+		},
+		{
+			MethodName: "BatchGetPets",
+			Handler:    _PetService_BatchGetPets_Handler,
+		},
 		{
 			MethodName: "UpdatePet",
 			Handler:    _PetService_UpdatePet_Handler,
@@ -297,6 +723,10 @@ var PetService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeletePet",
 			Handler:    _PetService_DeletePet_Handler,
 		},
+		{
+			MethodName: "RestorePet",
+			Handler:    _PetService_RestorePet_Handler,
+		},
 		{
 			MethodName: "ListPets",
 			Handler:    _PetService_ListPets_Handler,
@@ -305,6 +735,42 @@ var PetService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdatePetAdoptionStatus",
 			Handler:    _PetService_UpdatePetAdoptionStatus_Handler,
 		},
+		{
+			MethodName: "GetImageUploadURL",
+			Handler:    _PetService_GetImageUploadURL_Handler,
+		},
+		{
+			MethodName: "AddImageURLs",
+			Handler:    _PetService_AddImageURLs_Handler,
+		},
+		{
+			MethodName: "ListRecentlyAdopted",
+			Handler:    _PetService_ListRecentlyAdopted_Handler,
+		},
+		{
+			MethodName: "GetPetFacets",
+			Handler:    _PetService_GetPetFacets_Handler,
+		},
+		{
+			MethodName: "SuggestBreeds",
+			Handler:    _PetService_SuggestBreeds_Handler,
+		},
+		{
+			MethodName: "AddPetTags",
+			Handler:    _PetService_AddPetTags_Handler,
+		},
+		{
+			MethodName: "RemovePetTags",
+			Handler:    _PetService_RemovePetTags_Handler,
+		},
+		{
+			MethodName: "AdminSetPetStatus",
+			Handler:    _PetService_AdminSetPetStatus_Handler,
+		},
+		{
+			MethodName: "TransferPetListing",
+			Handler:    _PetService_TransferPetListing_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "pet.proto",
@@ -0,0 +1,68 @@
+// Package eventbus names the NATS subjects the services publish and consume. Publishers and
+// consumers both build their subjects through a Namespace, so an environment prefix configured
+// with NATS_SUBJECT_PREFIX is applied the same way on both sides.
+package eventbus
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Subjects of the events exchanged between the services, before any environment prefix.
+const (
+	SubjectApplicationCreated         = "adoption.application.created"
+	SubjectApplicationStatusUpdated   = "adoption.application.status.updated"
+	SubjectApplicantFlagged           = "adoption.admin.applicant.flagged"
+	SubjectApplicationPendingReminder = "adoption.application.pending.reminder"
+	SubjectPetUnavailable             = "pet.unavailable"
+)
+
+// Stream names, before any environment prefix, for when the events move to JetStream.
+const (
+	StreamAdoptions = "ADOPTIONS"
+	StreamPets      = "PETS"
+)
+
+// Namespace scopes subjects and stream names to one environment, so several environments can
+// share a NATS cluster without receiving each other's events. The zero value adds no prefix.
+type Namespace struct {
+	prefix string
+}
+
+// NewNamespace returns the namespace for prefix, e.g. "staging" turns "pet.unavailable" into
+// "staging.pet.unavailable". Surrounding whitespace and dots are ignored. The prefix must be one
+// or more dot-separated subject tokens without wildcards.
+func NewNamespace(prefix string) (Namespace, error) {
+	prefix = strings.Trim(strings.TrimSpace(prefix), ".")
+	if prefix == "" {
+		return Namespace{}, nil
+	}
+	for _, token := range strings.Split(prefix, ".") {
+		if token == "" || strings.ContainsAny(token, " \t\r\n*>") {
+			return Namespace{}, fmt.Errorf("invalid NATS subject prefix %q", prefix)
+		}
+	}
+	return Namespace{prefix: prefix}, nil
+}
+
+// Prefix returns the normalized prefix, empty if there is none.
+func (n Namespace) Prefix() string {
+	return n.prefix
+}
+
+// Subject returns subject within the namespace.
+func (n Namespace) Subject(subject string) string {
+	if n.prefix == "" {
+		return subject
+	}
+	return n.prefix + "." + subject
+}
+
+// Stream returns the stream name within the namespace, e.g. "STAGING_ADOPTIONS". Stream names
+// cannot contain dots, so the prefix's dots become underscores.
+func (n Namespace) Stream(name string) string {
+	if n.prefix == "" {
+		return name
+	}
+	return strings.ToUpper(strings.ReplaceAll(n.prefix, ".", "_")) + "_" + name
+}
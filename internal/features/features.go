@@ -0,0 +1,74 @@
+// Package features decides whether a feature applies to a given user. A feature is either on for
+// everyone or "soft-launched": off globally but on for an allowlist of users, so risky features
+// such as auto-approval and digests can be rolled out gradually.
+package features
+
+import "strings"
+
+// Features that can be soft-launched.
+const (
+	AutoApproval = "auto_approval" // Applications from trusted users are approved on creation
+	Digest       = "digest"        // Notifications are batched into one summary email
+)
+
+// Flags holds the global switch and allowlist of each feature. Configure it at startup; after
+// that it is safe for concurrent use. A nil *Flags has every feature off.
+type Flags struct {
+	flags map[string]flag
+}
+
+type flag struct {
+	enabled   bool
+	allowlist map[string]bool
+}
+
+// NewFlags returns Flags with every feature off.
+func NewFlags() *Flags {
+	return &Flags{flags: make(map[string]flag)}
+}
+
+// Set configures feature: enabled turns it on for everyone, otherwise it is on only for the
+// users in allowlist. Empty user IDs are ignored.
+func (f *Flags) Set(feature string, enabled bool, allowlist []string) {
+	allowed := make(map[string]bool, len(allowlist))
+	for _, userID := range allowlist {
+		if userID = strings.TrimSpace(userID); userID != "" {
+			allowed[userID] = true
+		}
+	}
+	f.flags[feature] = flag{enabled: enabled, allowlist: allowed}
+}
+
+// IsFeatureEnabledForUser reports whether feature applies to userID: always if the feature is
+// on globally, otherwise only if the user is on its allowlist.
+func (f *Flags) IsFeatureEnabledForUser(feature, userID string) bool {
+	if f == nil {
+		return false
+	}
+	fl, ok := f.flags[feature]
+	if !ok {
+		return false
+	}
+	return fl.enabled || (userID != "" && fl.allowlist[userID])
+}
+
+// IsFeatureEnabledForAnyone reports whether feature applies to at least one user, i.e. whether
+// whatever backs it has to be set up.
+func (f *Flags) IsFeatureEnabledForAnyone(feature string) bool {
+	if f == nil {
+		return false
+	}
+	fl := f.flags[feature]
+	return fl.enabled || len(fl.allowlist) > 0
+}
+
+// ParseUserIDs splits a comma-separated list of user IDs, as used by the *_ALLOWLIST settings.
+func ParseUserIDs(raw string) []string {
+	var userIDs []string
+	for _, userID := range strings.Split(raw, ",") {
+		if userID = strings.TrimSpace(userID); userID != "" {
+			userIDs = append(userIDs, userID)
+		}
+	}
+	return userIDs
+}
@@ -0,0 +1,29 @@
+// Package settings reports the effective configuration of a service in its startup log. Each
+// service lists its feature flags and key tunables; this package formats them the same way for
+// all of them.
+package settings
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Setting is one effective feature flag or tunable, as reported in the startup log.
+type Setting struct {
+	Name  string
+	Value string
+}
+
+// Summary formats settings as a single "name=value ..." line, so the startup log shows every
+// resolved flag together. Values containing spaces are quoted.
+func Summary(settings []Setting) string {
+	parts := make([]string, len(settings))
+	for i, s := range settings {
+		value := s.Value
+		if value == "" || strings.ContainsAny(value, " \t\"=") {
+			value = strconv.Quote(value)
+		}
+		parts[i] = s.Name + "=" + value
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,55 @@
+package testutil
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoURIEnv names the environment variable pointing MongoDatabase at a MongoDB server, e.g.
+// one started with "docker run -p 27017:27017 mongo". There is no embeddable MongoDB for Go, so
+// tests that need a real one are skipped when it is not set.
+const MongoURIEnv = "TEST_MONGO_URI"
+
+// MongoDatabase returns a database with a unique name on the server at TEST_MONGO_URI, dropped
+// when the test ends. The test is skipped if TEST_MONGO_URI is not set.
+func MongoDatabase(t testing.TB) *mongo.Database {
+	t.Helper()
+	uri := os.Getenv(MongoURIEnv)
+	if uri == "" {
+		t.Skipf("testutil: %s not set, skipping test that needs MongoDB", MongoURIEnv)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatalf("testutil: could not connect to MongoDB at %s: %v", uri, err)
+	}
+	if err := client.Ping(ctx, nil); err != nil {
+		client.Disconnect(context.Background())
+		t.Fatalf("testutil: MongoDB at %s is not reachable: %v", uri, err)
+	}
+
+	// Database names cannot contain some of the characters subtest names use.
+	name := strings.NewReplacer("/", "_", " ", "_", ".", "_", "$", "_").Replace(t.Name())
+	if len(name) > 40 {
+		name = name[:40]
+	}
+	db := client.Database(fmt.Sprintf("test_%s_%d", name, time.Now().UnixNano()))
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := db.Drop(ctx); err != nil {
+			t.Logf("testutil: could not drop %s: %v", db.Name(), err)
+		}
+		client.Disconnect(ctx)
+	})
+	return db
+}
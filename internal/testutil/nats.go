@@ -0,0 +1,49 @@
+// Package testutil starts in-process stand-ins for the services' external dependencies, so
+// tests can exercise real clients without hand-written mocks: an embedded NATS server, a fake
+// SMTP server that records what it receives, and a scratch MongoDB database. Everything started
+// is shut down when the test ends.
+package testutil
+
+import (
+	"testing"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+)
+
+// StartNATS runs an embedded NATS server on a free loopback port and returns its client URL.
+func StartNATS(t testing.TB) string {
+	t.Helper()
+	srv, err := natsserver.NewServer(&natsserver.Options{
+		Host:   "127.0.0.1",
+		Port:   natsserver.RANDOM_PORT,
+		NoLog:  true,
+		NoSigs: true,
+	})
+	if err != nil {
+		t.Fatalf("testutil: could not create NATS server: %v", err)
+	}
+	go srv.Start()
+	if !srv.ReadyForConnections(5 * time.Second) {
+		srv.Shutdown()
+		t.Fatalf("testutil: NATS server did not start")
+	}
+	t.Cleanup(func() {
+		srv.Shutdown()
+		srv.WaitForShutdown()
+	})
+	return srv.ClientURL()
+}
+
+// ConnectNATS starts an embedded NATS server with StartNATS and returns a connection to it.
+func ConnectNATS(t testing.TB) (*nats.Conn, string) {
+	t.Helper()
+	url := StartNATS(t)
+	nc, err := nats.Connect(url)
+	if err != nil {
+		t.Fatalf("testutil: could not connect to NATS at %s: %v", url, err)
+	}
+	t.Cleanup(nc.Close)
+	return nc, url
+}
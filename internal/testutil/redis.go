@@ -0,0 +1,51 @@
+package testutil
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisAddrEnv names the environment variable pointing RedisAddr at a Redis server, e.g. one
+// started with "docker run -p 6379:6379 redis". Tests that need a real one are skipped when it is
+// not set.
+const RedisAddrEnv = "TEST_REDIS_ADDR"
+
+// RedisAddr returns the address of the Redis server at TEST_REDIS_ADDR and a key prefix unique to
+// the test, whose keys are deleted when the test ends. The test is skipped if TEST_REDIS_ADDR is
+// not set.
+func RedisAddr(t testing.TB) (string, string) {
+	t.Helper()
+	addr := os.Getenv(RedisAddrEnv)
+	if addr == "" {
+		t.Skipf("testutil: %s not set, skipping test that needs Redis", RedisAddrEnv)
+	}
+
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		t.Fatalf("testutil: Redis at %s is not reachable: %v", addr, err)
+	}
+
+	prefix := fmt.Sprintf("test:%s:%d:", strings.ReplaceAll(t.Name(), " ", "_"), time.Now().UnixNano())
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		keys, err := client.Keys(ctx, prefix+"*").Result()
+		if err == nil && len(keys) > 0 {
+			err = client.Del(ctx, keys...).Err()
+		}
+		if err != nil {
+			t.Logf("testutil: could not delete Redis keys under %s: %v", prefix, err)
+		}
+		client.Close()
+	})
+	return addr, prefix
+}
@@ -0,0 +1,165 @@
+package testutil
+
+import (
+	"io"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Email is one message received by the fake SMTP server.
+type Email struct {
+	From string
+	To   []string
+	Data string // Headers and body, as sent after DATA
+}
+
+// SMTPServer is a fake SMTP server that accepts every message, and any AUTH PLAIN credentials,
+// and records what it receives. It speaks just enough SMTP for net/smtp.SendMail.
+type SMTPServer struct {
+	Host string // Loopback address the server listens on, without the port
+	Port int
+
+	listener net.Listener
+	mu       sync.Mutex
+	emails   []Email
+	received chan struct{} // Signalled after every message
+}
+
+// StartSMTP runs a fake SMTP server on a free loopback port.
+func StartSMTP(t testing.TB) *SMTPServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("testutil: could not listen for SMTP: %v", err)
+	}
+	addr := listener.Addr().(*net.TCPAddr)
+	s := &SMTPServer{Host: "127.0.0.1", Port: addr.Port, listener: listener, received: make(chan struct{}, 1)}
+	go s.serve()
+	t.Cleanup(func() { listener.Close() })
+	return s
+}
+
+// Emails returns the messages received so far.
+func (s *SMTPServer) Emails() []Email {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Email(nil), s.emails...)
+}
+
+// WaitForEmails waits until at least n messages were received and returns them, or fails the
+// test after timeout.
+func (s *SMTPServer) WaitForEmails(t testing.TB, n int, timeout time.Duration) []Email {
+	t.Helper()
+	deadline := time.After(timeout)
+	for {
+		if emails := s.Emails(); len(emails) >= n {
+			return emails
+		}
+		select {
+		case <-s.received:
+		case <-deadline:
+			t.Fatalf("testutil: got %d email(s) within %s, want %d", len(s.Emails()), timeout, n)
+		}
+	}
+}
+
+func (s *SMTPServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return // Listener closed
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *SMTPServer) handle(conn net.Conn) {
+	defer conn.Close()
+	text := textproto.NewConn(conn)
+	reply := func(code int, lines ...string) bool {
+		for i, line := range lines {
+			sep := " "
+			if i < len(lines)-1 {
+				sep = "-"
+			}
+			if err := text.PrintfLine("%s%s%s", strconv.Itoa(code), sep, line); err != nil {
+				return false
+			}
+		}
+		return true
+	}
+
+	if !reply(220, "localhost fake SMTP ready") {
+		return
+	}
+	var current Email
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		ok := true
+		switch strings.ToUpper(verb) {
+		case "EHLO":
+			ok = reply(250, "localhost", "AUTH PLAIN")
+		case "HELO", "NOOP":
+			ok = reply(250, "OK")
+		case "AUTH":
+			ok = reply(235, "Authentication successful")
+		case "MAIL":
+			current = Email{From: smtpPath(arg)}
+			ok = reply(250, "OK")
+		case "RCPT":
+			current.To = append(current.To, smtpPath(arg))
+			ok = reply(250, "OK")
+		case "DATA":
+			if !reply(354, "End data with <CR><LF>.<CR><LF>") {
+				return
+			}
+			data, err := io.ReadAll(text.DotReader())
+			if err != nil {
+				return
+			}
+			current.Data = string(data)
+			s.record(current)
+			current = Email{}
+			ok = reply(250, "OK: queued")
+		case "RSET":
+			current = Email{}
+			ok = reply(250, "OK")
+		case "QUIT":
+			reply(221, "Bye")
+			return
+		default:
+			ok = reply(502, "Command not implemented")
+		}
+		if !ok {
+			return
+		}
+	}
+}
+
+func (s *SMTPServer) record(email Email) {
+	s.mu.Lock()
+	s.emails = append(s.emails, email)
+	s.mu.Unlock()
+	select {
+	case s.received <- struct{}{}:
+	default:
+	}
+}
+
+// smtpPath extracts the address from a "FROM:<a@b>" or "TO:<a@b>" argument.
+func smtpPath(arg string) string {
+	if _, path, found := strings.Cut(arg, ":"); found {
+		arg = path
+	}
+	arg, _, _ = strings.Cut(strings.TrimSpace(arg), " ") // Drop ESMTP parameters such as BODY=8BITMIME
+	return strings.Trim(arg, "<>")
+}
@@ -0,0 +1,76 @@
+// Package textnorm cleans up free text entered by users, such as pet descriptions and
+// application notes, before it is stored. It is shared by the services of this module.
+package textnorm
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Normalize trims s, removes control characters, collapses runs of spaces and tabs into one
+// space and at most one blank line between paragraphs, and caps the result at maxRunes
+// characters (no cap if maxRunes <= 0). Line breaks are kept, so descriptions can still
+// have paragraphs. Invalid UTF-8 is dropped.
+func Normalize(s string, maxRunes int) string {
+	lines := strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	var b strings.Builder
+	b.Grow(len(s))
+	blankLines := 0
+	for _, line := range lines {
+		line = collapseLine(line)
+		if line == "" {
+			blankLines++
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteByte('\n')
+			if blankLines > 0 {
+				b.WriteByte('\n')
+			}
+		}
+		blankLines = 0
+		b.WriteString(line)
+	}
+	return truncate(b.String(), maxRunes)
+}
+
+// collapseLine drops control characters and invalid UTF-8 from one line, turns every run of
+// whitespace into a single space and trims the ends.
+func collapseLine(line string) string {
+	var b strings.Builder
+	b.Grow(len(line))
+	pendingSpace := false
+	for _, r := range line {
+		switch {
+		case r == utf8.RuneError:
+			continue
+		case unicode.IsSpace(r):
+			pendingSpace = true
+			continue
+		case unicode.IsControl(r) || unicode.Is(unicode.Cf, r):
+			continue
+		}
+		if pendingSpace && b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		pendingSpace = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// truncate cuts s to at most maxRunes characters, dropping whitespace left at the cut.
+func truncate(s string, maxRunes int) string {
+	if maxRunes <= 0 || utf8.RuneCountInString(s) <= maxRunes {
+		return s
+	}
+	n := 0
+	for i := range s {
+		if n == maxRunes {
+			return strings.TrimRightFunc(s[:i], unicode.IsSpace)
+		}
+		n++
+	}
+	return s
+}
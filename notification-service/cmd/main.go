@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
 	// "sync" // Removed as it's not directly used in this main package
@@ -10,10 +12,15 @@ import (
 	"time"
 
 	// Adjust these import paths to match your project's module path and structure
+	"github.com/redis/go-redis/v9"
+	"github.com/zhandarbeks/petstore-final-project/internal/settings"
 	"github.com/zhandarbeks/petstore-final-project/notification-service/internal/client"
 	"github.com/zhandarbeks/petstore-final-project/notification-service/internal/config"
 	"github.com/zhandarbeks/petstore-final-project/notification-service/internal/consumer"
+	"github.com/zhandarbeks/petstore-final-project/notification-service/internal/delivery"
+	"github.com/zhandarbeks/petstore-final-project/notification-service/internal/digest"
 	"github.com/zhandarbeks/petstore-final-project/notification-service/internal/email"
+	"github.com/zhandarbeks/petstore-final-project/notification-service/internal/metrics"
 	"github.com/zhandarbeks/petstore-final-project/notification-service/internal/service"
 )
 
@@ -25,11 +32,13 @@ func main() {
 	}
 
 	log.Println("Notification Service | Configuration loaded.")
+	log.Printf("Notification Service | Effective settings: %s", settings.Summary(cfg.Settings()))
 	log.Printf("Notification Service | NATS URL: %s", cfg.NatsURL)
 	log.Printf("Notification Service | SMTP Server: %s:%d", cfg.SMTPServer, cfg.SMTPPort)
 	log.Printf("Notification Service | Sender Email: %s", cfg.SMTPSenderEmail)
 	log.Printf("Notification Service | User Service gRPC URL: %s", cfg.UserServiceGRPCURL)
 	log.Printf("Notification Service | Pet Service gRPC URL: %s", cfg.PetServiceGRPCURL)
+	log.Printf("Notification Service | Adoption Service gRPC URL: %s", cfg.AdoptionServiceGRPCURL)
 
 	// Create a main context that can be used to signal shutdown
 	mainCtx, cancelMainCtx := context.WithCancel(context.Background())
@@ -67,22 +76,97 @@ func main() {
 		}
 	}()
 
+	// 3b. Initialize Adoption Service gRPC Client (applicants of adopted pets)
+	adoptionClientInitCtx, adoptionClientCancel := context.WithTimeout(mainCtx, initTimeout)
+	defer adoptionClientCancel()
+	adoptionServiceClient, err := client.NewAdoptionServiceGRPCClient(adoptionClientInitCtx, cfg.AdoptionServiceGRPCURL)
+	if err != nil {
+		log.Fatalf("Notification Service | FATAL: Failed to initialize Adoption Service gRPC client: %v", err)
+	}
+	log.Println("Notification Service | Adoption Service gRPC client initialized.")
+	defer func() {
+		log.Println("Notification Service | Closing Adoption Service gRPC client connection...")
+		if err := adoptionServiceClient.Close(); err != nil {
+			log.Printf("Notification Service | Error closing Adoption Service gRPC client: %v", err)
+		}
+	}()
+
 	// 4. Initialize Email Sender
 	emailSender, err := email.NewSMTPEmailSender(cfg.SMTPServer, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPSenderEmail)
 	if err != nil {
 		log.Fatalf("Notification Service | FATAL: Failed to initialize SMTP Email Sender: %v", err)
 	}
 	log.Println("Notification Service | SMTP Email Sender initialized.")
+	// Bound concurrent SMTP sends; the rest wait for a free slot
+	emailSender = email.NewConcurrencyLimitedSender(emailSender, cfg.MaxConcurrentEmails)
+
+	// 4b. Track delivery status callbacks and skip suppressed recipients when sending
+	deliveryTracker := delivery.NewTracker(cfg.SuppressBouncedRecipients)
+	emailSender = email.NewSuppressingSender(emailSender, deliveryTracker.IsSuppressed)
+
+	// 4c. Connect to Redis, used by the per-recipient rate limit and by digest mode
+	var rdb *redis.Client
+	digestEnabled := cfg.DigestMode || len(cfg.DigestAllowlist) > 0
+	if cfg.EmailRateLimitPerRecipient > 0 || digestEnabled {
+		rdb = redis.NewClient(&redis.Options{Addr: cfg.RedisAddr, Password: cfg.RedisPassword, DB: cfg.RedisDB})
+		redisPingCtx, redisPingCancel := context.WithTimeout(mainCtx, initTimeout)
+		err := rdb.Ping(redisPingCtx).Err()
+		redisPingCancel()
+		if err != nil {
+			if digestEnabled {
+				log.Fatalf("Notification Service | FATAL: Redis unavailable at %s, required for digest mode: %v", cfg.RedisAddr, err)
+			}
+			log.Printf("Notification Service | Warning: Redis unavailable at %s, per-recipient rate limit disabled: %v", cfg.RedisAddr, err)
+			rdb.Close()
+			rdb = nil
+		} else {
+			defer rdb.Close()
+		}
+	}
+
+	// 4d. Limit emails per recipient, counted in Redis so the limit holds across instances
+	if cfg.EmailRateLimitPerRecipient > 0 && rdb != nil {
+		emailSender = email.NewRateLimitedSender(emailSender, email.NewRedisWindowCounter(rdb, "notify:rate:"), cfg.EmailRateLimitPerRecipient, cfg.EmailRateLimitWindow)
+		log.Printf("Notification Service | Per-recipient rate limit: %d emails per %s", cfg.EmailRateLimitPerRecipient, cfg.EmailRateLimitWindow)
+	}
 
 	// 5. Initialize Notification Service (which implements consumer.EventHandler)
 	notificationSvc := service.NewNotificationService(emailSender, userServiceClient, petServiceClient)
+	notificationSvc.SetAdoptionServiceClient(adoptionServiceClient)
+	notificationSvc.SetMaxDescriptionLength(cfg.EmailDescriptionMaxLength)
 	log.Println("Notification Service | Core notification service logic initialized.")
+	if cfg.DigestMode {
+		notificationSvc.EnableDigest(digest.NewRedisStore(rdb, "notify:digest:"))
+		log.Printf("Notification Service | Digest mode enabled: window %s, flush every %s", cfg.DigestWindow, cfg.DigestFlushInterval)
+	} else if digestEnabled {
+		notificationSvc.SoftLaunchDigest(digest.NewRedisStore(rdb, "notify:digest:"), cfg.DigestAllowlist)
+		log.Printf("Notification Service | Digest mode enabled for %d allowlisted user(s): window %s, flush every %s", len(cfg.DigestAllowlist), cfg.DigestWindow, cfg.DigestFlushInterval)
+	}
+	if digestEnabled {
+		go notificationSvc.RunDigestFlusher(mainCtx, cfg.DigestWindow, cfg.DigestFlushInterval)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/webhooks/email-delivery", delivery.WebhookHandler(deliveryTracker, cfg.DeliveryWebhookToken))
+	mux.Handle("/admin/email-previews", service.PreviewHandler(notificationSvc, cfg.AdminAPIToken))
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	httpServer := &http.Server{Addr: cfg.HTTPPort, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		log.Printf("Notification Service | HTTP server (delivery webhooks, email previews, metrics) listening on %s", cfg.HTTPPort)
+		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("Notification Service | HTTP server error: %v", err)
+		}
+	}()
 
 	// 6. Initialize NATS Consumer
-	natsConsumer, err := consumer.NewNATSConsumer(cfg.NatsURL, notificationSvc)
+	natsConsumer, err := consumer.NewNATSConsumer(cfg.NatsURL, notificationSvc, cfg.NatsSubjects, cfg.NatsMaxSubjects, cfg.NatsDrainTimeout, cfg.NatsNamespace)
 	if err != nil {
 		log.Fatalf("Notification Service | FATAL: Failed to initialize NATS consumer: %v", err)
 	}
+	natsConsumer.SetOrderingWait(cfg.EventOrderingWait)
+	mux.Handle("/metrics", metrics.Handler(natsConsumer.EventLatency()))
 	log.Println("Notification Service | NATS consumer initialized.")
 
 	// 7. Start NATS Subscribers
@@ -102,6 +186,12 @@ func main() {
 	// Cancel the main context to signal other parts of the application if they use it.
 	cancelMainCtx()
 
+	httpShutdownCtx, httpShutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	if err := httpServer.Shutdown(httpShutdownCtx); err != nil {
+		log.Printf("Notification Service | Error shutting down HTTP server: %v", err)
+	}
+	httpShutdownCancel()
+
 	// Close NATS consumer (which will unsubscribe and drain connections)
 	natsConsumer.Close() // This method should handle waiting for message handlers to finish.
 
@@ -0,0 +1,92 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	pbAdoption "github.com/zhandarbeks/petstore-final-project/genprotos/adoption"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure" // For connecting without TLS (dev environment)
+)
+
+// AdoptionServiceClient defines the interface for interacting with the Adoption gRPC service.
+type AdoptionServiceClient interface {
+	// ListPetApplications returns every application for the pet, oldest first.
+	ListPetApplications(ctx context.Context, petID string) ([]*pbAdoption.AdoptionApplication, error)
+	Close() error
+}
+
+// petApplicationsPageSize is the page size used to walk a pet's applications; the service caps it at 100.
+const petApplicationsPageSize = 100
+
+// adoptionServiceGRPCClient is the gRPC implementation of AdoptionServiceClient.
+type adoptionServiceGRPCClient struct {
+	conn   *grpc.ClientConn
+	client pbAdoption.AdoptionServiceClient
+}
+
+// NewAdoptionServiceGRPCClient creates a new gRPC client for the Adoption Service.
+// It takes the target URL of the Adoption Service (e.g., "adoption-service:50053").
+func NewAdoptionServiceGRPCClient(ctx context.Context, targetURL string) (AdoptionServiceClient, error) {
+	if targetURL == "" {
+		return nil, fmt.Errorf("adoption service target URL cannot be empty")
+	}
+
+	log.Printf("Notification Service | Attempting to connect to Adoption Service gRPC at %s", targetURL)
+
+	conn, err := grpc.DialContext(
+		ctx,
+		targetURL,
+		grpc.WithTransportCredentials(insecure.NewCredentials()), // No TLS for now
+		grpc.WithBlock(), // Block until connection is up or context times out
+	)
+	if err != nil {
+		log.Printf("Notification Service | Failed to connect to Adoption Service gRPC at %s: %v", targetURL, err)
+		return nil, fmt.Errorf("did not connect to adoption service: %w", err)
+	}
+	log.Printf("Notification Service | Successfully connected to Adoption Service gRPC at %s", targetURL)
+
+	return &adoptionServiceGRPCClient{
+		conn:   conn,
+		client: pbAdoption.NewAdoptionServiceClient(conn),
+	}, nil
+}
+
+// ListPetApplications fetches all of a pet's applications from the Adoption Service, page by page.
+func (c *adoptionServiceGRPCClient) ListPetApplications(ctx context.Context, petID string) ([]*pbAdoption.AdoptionApplication, error) {
+	if petID == "" {
+		return nil, fmt.Errorf("pet ID cannot be empty")
+	}
+	log.Printf("Notification Service | Calling Adoption Service ListPetAdoptionApplications for PetID: %s", petID)
+
+	callCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var applications []*pbAdoption.AdoptionApplication
+	limit := int32(petApplicationsPageSize)
+	for page := int32(1); ; page++ {
+		res, err := c.client.ListPetAdoptionApplications(callCtx, &pbAdoption.ListPetAdoptionApplicationsRequest{PetId: petID, Page: &page, Limit: &limit})
+		if err != nil {
+			log.Printf("Notification Service | Error calling Adoption Service ListPetAdoptionApplications for PetID %s: %v", petID, err)
+			return nil, fmt.Errorf("adoption service ListPetAdoptionApplications call failed: %w", err)
+		}
+		applications = append(applications, res.GetApplications()...)
+		if len(res.GetApplications()) == 0 || int32(len(applications)) >= res.GetTotalCount() {
+			break
+		}
+	}
+
+	log.Printf("Notification Service | Fetched %d applications for PetID: %s", len(applications), petID)
+	return applications, nil
+}
+
+// Close closes the gRPC client connection to the Adoption Service.
+func (c *adoptionServiceGRPCClient) Close() error {
+	if c.conn != nil {
+		log.Println("Notification Service | Closing Adoption Service gRPC client connection...")
+		return c.conn.Close()
+	}
+	return nil
+}
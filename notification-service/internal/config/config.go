@@ -4,8 +4,13 @@ import (
 	"log"
 	"os"
 	"strconv" // For SMTP port
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv" // For loading .env files (optional)
+	"github.com/zhandarbeks/petstore-final-project/internal/eventbus"
+	"github.com/zhandarbeks/petstore-final-project/internal/features"
+	"github.com/zhandarbeks/petstore-final-project/internal/settings"
 )
 
 // Config holds all configuration for the notification-service
@@ -18,10 +23,60 @@ type Config struct {
 	SMTPSenderEmail     string // The "From" email address for notifications
 	UserServiceGRPCURL  string // gRPC URL for the User Service (e.g., "user-service:50051")
 	PetServiceGRPCURL   string // gRPC URL for the Pet Service (e.g., "pet-service:50052")
+	AdoptionServiceGRPCURL string // gRPC URL for the Adoption Service, used to find a pet's applicants (e.g., "adoption-service:50053")
+	NatsSubjects        []string // Subjects to consume; empty means the consumer's defaults
+	NatsMaxSubjects     int      // Upper bound on the number of configured subjects
+	NatsDrainTimeout    time.Duration // How long shutdown waits for running handlers before cancelling them
+	EventOrderingWait   time.Duration // How long a status update for a new application waits for its created event (0 = no waiting)
+	NatsNamespace       eventbus.Namespace // Environment prefix for NATS subjects (NATS_SUBJECT_PREFIX), shared with the publishers
+	HTTPPort                  string // Port for the HTTP server receiving delivery webhooks (e.g., ":8081")
+	DeliveryWebhookToken      string // Shared token providers must send in X-Webhook-Token; empty disables the webhook
+	SuppressBouncedRecipients bool   // Stop emailing addresses that bounced or complained
+	AdminAPIToken             string // Token required in X-Admin-Token for email previews; empty disables them
+	MaxConcurrentEmails       int    // Max emails sent at once; further sends wait for a free slot (0 = unlimited)
+	EmailDescriptionMaxLength int    // Characters of a pet description included in emails before an ellipsis (0 = all)
+
+	// Per-recipient email rate limit, counted in Redis
+	RedisAddr                  string        // Redis server address for the rate limit counters
+	RedisPassword              string        // Redis password (if any)
+	RedisDB                    int           // Redis database number for the rate limit counters
+	EmailRateLimitPerRecipient int           // Max emails per recipient within EmailRateLimitWindow (0 = unlimited)
+	EmailRateLimitWindow       time.Duration // Window for the per-recipient rate limit
+
+	// Digest mode: batch a user's events into one summary email, queued in Redis
+	DigestMode          bool          // Queue events and send digests instead of one email per event
+	DigestAllowlist     []string      // Users digest mode is soft-launched to while DigestMode is off
+	DigestWindow        time.Duration // How long a user's first queued event waits for more before the digest is sent
+	DigestFlushInterval time.Duration // How often the flusher looks for digests whose window has passed
 	// Optional: If this service also exposes its own gRPC server (e.g., for health checks)
 	// ServerPort string
 }
 
+// Settings returns the feature flags and key tunables in effect. Secrets and connection strings are left out.
+func (c *Config) Settings() []settings.Setting {
+	return []settings.Setting{
+		{Name: "smtp_server", Value: c.SMTPServer + ":" + strconv.Itoa(c.SMTPPort)},
+		{Name: "sender_email", Value: c.SMTPSenderEmail},
+		{Name: "nats_subjects", Value: strings.Join(c.NatsSubjects, ",")},
+		{Name: "nats_max_subjects", Value: strconv.Itoa(c.NatsMaxSubjects)},
+		{Name: "nats_drain_timeout", Value: c.NatsDrainTimeout.String()},
+		{Name: "event_ordering_wait", Value: c.EventOrderingWait.String()},
+		{Name: "nats_subject_prefix", Value: c.NatsNamespace.Prefix()},
+		{Name: "delivery_webhook_enabled", Value: strconv.FormatBool(c.DeliveryWebhookToken != "")},
+		{Name: "suppress_bounced_recipients", Value: strconv.FormatBool(c.SuppressBouncedRecipients)},
+		{Name: "email_preview_enabled", Value: strconv.FormatBool(c.AdminAPIToken != "")},
+		{Name: "max_concurrent_emails", Value: strconv.Itoa(c.MaxConcurrentEmails)},
+		{Name: "email_description_max_length", Value: strconv.Itoa(c.EmailDescriptionMaxLength)},
+		{Name: "email_rate_limit_per_recipient", Value: strconv.Itoa(c.EmailRateLimitPerRecipient)},
+		{Name: "email_rate_limit_window", Value: c.EmailRateLimitWindow.String()},
+		{Name: "digest_mode", Value: strconv.FormatBool(c.DigestMode)},
+		{Name: "digest_allowlist", Value: strings.Join(c.DigestAllowlist, ",")},
+		{Name: "digest_window", Value: c.DigestWindow.String()},
+		{Name: "digest_flush_interval", Value: c.DigestFlushInterval.String()},
+		{Name: "redis_db", Value: strconv.Itoa(c.RedisDB)},
+	}
+}
+
 // Load loads configuration. It first attempts to load from a .env file (if present),
 // then falls back to actual environment variables, and finally to defaults.
 func Load() (*Config, error) {
@@ -40,9 +95,118 @@ func Load() (*Config, error) {
 		SMTPSenderEmail:     getEnv("SENDER_EMAIL", "noreply@petstore.example"),
 		UserServiceGRPCURL:  getEnv("USER_SERVICE_GRPC_URL", "localhost:50051"), // Default for local, Docker will override
 		PetServiceGRPCURL:   getEnv("PET_SERVICE_GRPC_URL", "localhost:50052"),   // Default for local, Docker will override
+		AdoptionServiceGRPCURL: getEnv("ADOPTION_SERVICE_GRPC_URL", "localhost:50053"), // Default for local, Docker will override
+		HTTPPort:             getEnv("NOTIFICATION_HTTP_PORT", ":8081"),
+		DeliveryWebhookToken: getEnv("DELIVERY_WEBHOOK_TOKEN", ""),
+		AdminAPIToken:        getEnv("ADMIN_API_TOKEN", ""),
+		RedisAddr:            getEnv("REDIS_ADDR_NOTIFICATIONS", "localhost:6379"),
+		RedisPassword:        getEnv("REDIS_PASSWORD_NOTIFICATIONS", ""),
 		// ServerPort:       getEnv("NOTIFICATION_SERVICE_PORT", ":50054"), // If it has its own gRPC server
 	}
 
+	for _, subject := range strings.Split(getEnv("NATS_SUBJECTS", "adoption.application.created,adoption.application.status.updated,adoption.application.pending.reminder,pet.unavailable"), ",") {
+		if subject = strings.TrimSpace(subject); subject != "" {
+			cfg.NatsSubjects = append(cfg.NatsSubjects, subject)
+		}
+	}
+
+	maxSubjectsStr := getEnv("NATS_MAX_SUBJECTS", "16")
+	maxSubjectsVal, err := strconv.Atoi(maxSubjectsStr)
+	if err != nil || maxSubjectsVal <= 0 {
+		log.Printf("Notification Service | Warning: Invalid NATS_MAX_SUBJECTS value: '%s'. Using default 16. Error: %v", maxSubjectsStr, err)
+		maxSubjectsVal = 16
+	}
+	cfg.NatsMaxSubjects = maxSubjectsVal
+
+	drainTimeoutStr := getEnv("NATS_DRAIN_TIMEOUT", "10s")
+	drainTimeoutVal, err := time.ParseDuration(drainTimeoutStr)
+	if err != nil || drainTimeoutVal <= 0 {
+		log.Printf("Notification Service | Warning: Invalid NATS_DRAIN_TIMEOUT value: '%s'. Using default 10s. Error: %v", drainTimeoutStr, err)
+		drainTimeoutVal = 10 * time.Second
+	}
+	cfg.NatsDrainTimeout = drainTimeoutVal
+
+	orderingWaitStr := getEnv("EVENT_ORDERING_WAIT", "5s")
+	orderingWaitVal, err := time.ParseDuration(orderingWaitStr)
+	if err != nil || orderingWaitVal < 0 {
+		log.Printf("Notification Service | Warning: Invalid EVENT_ORDERING_WAIT value: '%s'. Using default 5s. Error: %v", orderingWaitStr, err)
+		orderingWaitVal = 5 * time.Second
+	}
+	cfg.EventOrderingWait = orderingWaitVal
+
+	suppressStr := getEnv("SUPPRESS_BOUNCED_RECIPIENTS", "true")
+	suppressVal, err := strconv.ParseBool(suppressStr)
+	if err != nil {
+		log.Printf("Notification Service | Warning: Invalid SUPPRESS_BOUNCED_RECIPIENTS value: '%s'. Using default true. Error: %v", suppressStr, err)
+		suppressVal = true
+	}
+	cfg.SuppressBouncedRecipients = suppressVal
+
+	maxConcurrentEmailsStr := getEnv("MAX_CONCURRENT_EMAILS", "5")
+	maxConcurrentEmailsVal, err := strconv.Atoi(maxConcurrentEmailsStr)
+	if err != nil || maxConcurrentEmailsVal < 0 {
+		log.Printf("Notification Service | Warning: Invalid MAX_CONCURRENT_EMAILS value: '%s'. Using default 5. Error: %v", maxConcurrentEmailsStr, err)
+		maxConcurrentEmailsVal = 5
+	}
+	cfg.MaxConcurrentEmails = maxConcurrentEmailsVal
+
+	descriptionMaxStr := getEnv("EMAIL_DESCRIPTION_MAX_LENGTH", "300")
+	descriptionMaxVal, err := strconv.Atoi(descriptionMaxStr)
+	if err != nil || descriptionMaxVal < 0 {
+		log.Printf("Notification Service | Warning: Invalid EMAIL_DESCRIPTION_MAX_LENGTH value: '%s'. Using default 300. Error: %v", descriptionMaxStr, err)
+		descriptionMaxVal = 300
+	}
+	cfg.EmailDescriptionMaxLength = descriptionMaxVal
+
+	redisDBStr := getEnv("REDIS_DB_NOTIFICATIONS", "3") // DB 3, next to users (0), pets (1) and adoptions (2)
+	redisDBVal, err := strconv.Atoi(redisDBStr)
+	if err != nil {
+		log.Printf("Notification Service | Warning: Invalid REDIS_DB_NOTIFICATIONS value: '%s'. Using default 3. Error: %v", redisDBStr, err)
+		redisDBVal = 3
+	}
+	cfg.RedisDB = redisDBVal
+
+	rateLimitStr := getEnv("EMAIL_RATE_LIMIT_PER_RECIPIENT", "3")
+	rateLimitVal, err := strconv.Atoi(rateLimitStr)
+	if err != nil || rateLimitVal < 0 {
+		log.Printf("Notification Service | Warning: Invalid EMAIL_RATE_LIMIT_PER_RECIPIENT value: '%s'. Using default 3. Error: %v", rateLimitStr, err)
+		rateLimitVal = 3
+	}
+	cfg.EmailRateLimitPerRecipient = rateLimitVal
+
+	rateWindowStr := getEnv("EMAIL_RATE_LIMIT_WINDOW", "10m")
+	rateWindowVal, err := time.ParseDuration(rateWindowStr)
+	if err != nil || rateWindowVal <= 0 {
+		log.Printf("Notification Service | Warning: Invalid EMAIL_RATE_LIMIT_WINDOW value: '%s'. Using default 10m. Error: %v", rateWindowStr, err)
+		rateWindowVal = 10 * time.Minute
+	}
+	cfg.EmailRateLimitWindow = rateWindowVal
+
+	digestModeStr := getEnv("DIGEST_MODE", "false")
+	digestModeVal, err := strconv.ParseBool(digestModeStr)
+	if err != nil {
+		log.Printf("Notification Service | Warning: Invalid DIGEST_MODE value: '%s'. Using default false. Error: %v", digestModeStr, err)
+		digestModeVal = false
+	}
+	cfg.DigestMode = digestModeVal
+	cfg.DigestAllowlist = features.ParseUserIDs(getEnv("DIGEST_ALLOWLIST", ""))
+
+	digestWindowStr := getEnv("DIGEST_WINDOW", "1h")
+	digestWindowVal, err := time.ParseDuration(digestWindowStr)
+	if err != nil || digestWindowVal <= 0 {
+		log.Printf("Notification Service | Warning: Invalid DIGEST_WINDOW value: '%s'. Using default 1h. Error: %v", digestWindowStr, err)
+		digestWindowVal = time.Hour
+	}
+	cfg.DigestWindow = digestWindowVal
+
+	digestFlushStr := getEnv("DIGEST_FLUSH_INTERVAL", "1m")
+	digestFlushVal, err := time.ParseDuration(digestFlushStr)
+	if err != nil || digestFlushVal <= 0 {
+		log.Printf("Notification Service | Warning: Invalid DIGEST_FLUSH_INTERVAL value: '%s'. Using default 1m. Error: %v", digestFlushStr, err)
+		digestFlushVal = time.Minute
+	}
+	cfg.DigestFlushInterval = digestFlushVal
+
 	smtpPortStr := getEnv("SMTP_PORT", "587") // Common port for TLS
 	smtpPortVal, err := strconv.Atoi(smtpPortStr)
 	if err != nil {
@@ -56,6 +220,12 @@ func Load() (*Config, error) {
 	if cfg.NatsURL == "" {
 		log.Fatal("Notification Service | FATAL: NATS_URL environment variable is required.")
 	}
+	// A bad prefix would make this environment miss its events or read another's, so don't start.
+	natsNamespace, err := eventbus.NewNamespace(getEnv("NATS_SUBJECT_PREFIX", ""))
+	if err != nil {
+		log.Fatalf("Notification Service | FATAL: NATS_SUBJECT_PREFIX is invalid: %v", err)
+	}
+	cfg.NatsNamespace = natsNamespace
 	if cfg.SMTPServer == "smtp.example.com" || cfg.SMTPServer == "" {
 		log.Println("Notification Service | WARNING: SMTP_HOST is using a placeholder or is not set. Email sending will likely fail.")
 	}
@@ -71,6 +241,9 @@ func Load() (*Config, error) {
 	if cfg.PetServiceGRPCURL == "" {
 		log.Fatal("Notification Service | FATAL: PET_SERVICE_GRPC_URL environment variable is required.")
 	}
+	if cfg.AdoptionServiceGRPCURL == "" {
+		log.Fatal("Notification Service | FATAL: ADOPTION_SERVICE_GRPC_URL environment variable is required.")
+	}
 
 
 	return cfg, nil
@@ -3,11 +3,14 @@ package consumer
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"sync" // For managing goroutines during shutdown
 	"time"
 
 	"github.com/nats-io/nats.go"
+	"github.com/zhandarbeks/petstore-final-project/internal/eventbus"
+	"github.com/zhandarbeks/petstore-final-project/notification-service/internal/metrics"
 	// You'll need to define these event structs based on what adoption-service publishes
 	// For example:
 	// "github.com/zhandarbeks/petstore-final-project/notification-service/internal/service"
@@ -22,6 +25,7 @@ type AdoptionApplicationCreatedEvent struct {
 	PetID          string    `json:"pet_id"`
 	Status         string    `json:"status"` // Consider using domain.ApplicationStatus type if shared
 	AppliedAt      time.Time `json:"applied_at"`
+	PublishedAt    time.Time `json:"published_at"` // When adoption-service published the event
 }
 
 // AdoptionApplicationStatusUpdatedEvent represents the data structure for this event.
@@ -31,8 +35,56 @@ type AdoptionApplicationStatusUpdatedEvent struct {
 	UserID         string    `json:"user_id"`
 	PetID          string    `json:"pet_id"`
 	NewStatus      string    `json:"new_status"` // Consider using domain.ApplicationStatus type
+	AppliedAt      time.Time `json:"applied_at"` // When the application was created; used to keep its events in order
 	UpdatedAt      time.Time `json:"updated_at"`
 	ReviewNotes    string    `json:"review_notes"`
+	PublishedAt    time.Time `json:"published_at"` // When adoption-service published the event
+}
+
+// AdoptionApplicationPendingReminderEvent is published by the adoption-service for an application
+// that has been waiting for review for too long.
+type AdoptionApplicationPendingReminderEvent struct {
+	EventType     string    `json:"event_type"`
+	ApplicationID string    `json:"application_id"`
+	UserID        string    `json:"user_id"`
+	PetID         string    `json:"pet_id"`
+	Status        string    `json:"status"`
+	AppliedAt     time.Time `json:"applied_at"`
+	PendingSince  time.Time `json:"pending_since"` // Last update of the application
+	PublishedAt   time.Time `json:"published_at"`  // When adoption-service published the event
+}
+
+// PetUnavailableEvent is published by the pet-service when a pet is adopted and can no longer be applied for.
+type PetUnavailableEvent struct {
+	EventType       string    `json:"event_type"`
+	PetID           string    `json:"pet_id"`
+	PetName         string    `json:"pet_name"`
+	ListedByUserID  string    `json:"listed_by_user_id"`
+	AdoptedByUserID string    `json:"adopted_by_user_id"`
+	Status          string    `json:"status"`
+	UnavailableAt   time.Time `json:"unavailable_at"`
+	PublishedAt     time.Time `json:"published_at"` // When pet-service published the event
+}
+
+// Subjects published by the adoption-service and pet-service that this consumer knows how to handle.
+// They are configured and routed without the environment prefix, which is added when subscribing.
+const (
+	SubjectApplicationCreated       = eventbus.SubjectApplicationCreated
+	SubjectApplicationStatusUpdated = eventbus.SubjectApplicationStatusUpdated
+	SubjectApplicationPendingReminder = eventbus.SubjectApplicationPendingReminder
+	SubjectPetUnavailable           = eventbus.SubjectPetUnavailable
+)
+
+// DefaultSubjects is the subject list used when none is configured.
+var DefaultSubjects = []string{SubjectApplicationCreated, SubjectApplicationStatusUpdated, SubjectApplicationPendingReminder, SubjectPetUnavailable}
+
+// DefaultMaxSubjects caps how many subjects a consumer subscribes to when no limit is configured.
+const DefaultMaxSubjects = 16
+
+// Subscription pairs a NATS subject with the handler for its messages.
+type Subscription struct {
+	Subject string
+	Handler nats.MsgHandler
 }
 
 // EventHandler defines the interface for processing received NATS events.
@@ -40,6 +92,8 @@ type AdoptionApplicationStatusUpdatedEvent struct {
 type EventHandler interface {
 	HandleAdoptionApplicationCreated(ctx context.Context, event AdoptionApplicationCreatedEvent) error
 	HandleAdoptionApplicationStatusUpdated(ctx context.Context, event AdoptionApplicationStatusUpdatedEvent) error
+	HandleApplicationPendingReminder(ctx context.Context, event AdoptionApplicationPendingReminderEvent) error
+	HandlePetUnavailable(ctx context.Context, event PetUnavailableEvent) error
 }
 
 // NATSConsumer handles NATS subscriptions and message processing.
@@ -48,12 +102,39 @@ type NATSConsumer struct {
 	js           nats.JetStreamContext // For JetStream, if used
 	eventHandler EventHandler
 	subscriptions []*nats.Subscription
+	subjects     []string // Subjects to subscribe to, validated against Routes
+	namespace    eventbus.Namespace // Environment prefix added to subjects when subscribing
+	maxSubjects  int      // Upper bound on len(subjects)
 	shutdownWg   sync.WaitGroup // WaitGroup for graceful shutdown of message handlers
 	stopChan     chan struct{}    // Channel to signal goroutines to stop
+
+	// Message handlers run with contexts derived from ctx. Close cancels it once drainTimeout
+	// has passed, so handlers still running are told to stop instead of being abandoned.
+	ctx          context.Context
+	cancel       context.CancelFunc
+	drainTimeout time.Duration
+
+	eventLatency *metrics.Histogram // Seconds from event publication until it was processed
+	sequencer    *applicationSequencer // Keeps status updates from overtaking the created event of their application
 }
 
-// NewNATSConsumer creates a new NATS consumer.
-func NewNATSConsumer(natsURL string, handler EventHandler) (*NATSConsumer, error) {
+// DefaultDrainTimeout is how long Close waits for running handlers when no timeout is configured.
+const DefaultDrainTimeout = 10 * time.Second
+
+// drainCancelGrace is how long Close waits, after cancelling, for handlers to observe the cancellation.
+const drainCancelGrace = time.Second
+
+// handlerTimeout bounds the processing of a single message.
+const handlerTimeout = 30 * time.Second
+
+// EventLatencyMetric is the name of the end-to-end adoption event latency histogram.
+const EventLatencyMetric = "notification_adoption_event_latency_seconds"
+
+// NewNATSConsumer creates a new NATS consumer for the given subjects (DefaultSubjects if empty).
+// maxSubjects limits the subject list; 0 means DefaultMaxSubjects. drainTimeout bounds how long
+// Close waits for running handlers before cancelling them; 0 means DefaultDrainTimeout.
+// Subjects are subscribed to within namespace, matching the publishers of the same environment.
+func NewNATSConsumer(natsURL string, handler EventHandler, subjects []string, maxSubjects int, drainTimeout time.Duration, namespace eventbus.Namespace) (*NATSConsumer, error) {
 	if handler == nil {
 		log.Fatal("Notification Service | FATAL: EventHandler cannot be nil for NATSConsumer")
 	}
@@ -74,41 +155,140 @@ func NewNATSConsumer(natsURL string, handler EventHandler) (*NATSConsumer, error
 	// }
 	// log.Println("Notification Service | JetStream context obtained.")
 
+	return NewNATSConsumerFromConn(nc, handler, subjects, maxSubjects, drainTimeout, namespace), nil
+}
+
+// NewNATSConsumerFromConn creates a NATS consumer on an existing connection. nc may be nil when
+// messages are fed to the Routes handlers directly, as in tests.
+func NewNATSConsumerFromConn(nc *nats.Conn, handler EventHandler, subjects []string, maxSubjects int, drainTimeout time.Duration, namespace eventbus.Namespace) *NATSConsumer {
+	if drainTimeout <= 0 {
+		drainTimeout = DefaultDrainTimeout
+	}
+	ctx, cancel := context.WithCancel(context.Background())
 	return &NATSConsumer{
 		nc:           nc,
 		// js:           js,
 		eventHandler: handler,
+		subjects:     subjects,
+		namespace:    namespace,
+		maxSubjects:  maxSubjects,
 		stopChan:     make(chan struct{}),
-	}, nil
+		ctx:          ctx,
+		cancel:       cancel,
+		drainTimeout: drainTimeout,
+		eventLatency: metrics.NewHistogram(EventLatencyMetric, "Delay between an adoption event being published and the notification service processing it.", metrics.LatencyBuckets),
+		sequencer:    newApplicationSequencer(DefaultOrderingWait),
+	}
+}
+
+// SetOrderingWait sets how long a status update for a just-created application waits for the
+// application's created event, so applicants do not hear "approved" before "received". 0 handles
+// every event as soon as it arrives. Call it before StartSubscribers.
+func (c *NATSConsumer) SetOrderingWait(wait time.Duration) {
+	c.sequencer = newApplicationSequencer(wait)
+}
+
+// EventLatency returns the histogram of delays between event publication and processing.
+func (c *NATSConsumer) EventLatency() *metrics.Histogram {
+	return c.eventLatency
+}
+
+// observeLatency records how long ago an event was published. Events from publishers that do
+// not stamp published_at fall back to the event's own timestamp; events with neither are skipped.
+func (c *NATSConsumer) observeLatency(publishedAt, fallback time.Time) {
+	if publishedAt.IsZero() {
+		publishedAt = fallback
+	}
+	if publishedAt.IsZero() {
+		return
+	}
+	c.eventLatency.Observe(time.Since(publishedAt).Seconds())
+}
+
+// handlerContext returns the context for processing one message, cancelled by Close after the drain timeout.
+func (c *NATSConsumer) handlerContext() (context.Context, context.CancelFunc) {
+	parent := c.ctx
+	if parent == nil {
+		parent = context.Background()
+	}
+	return context.WithTimeout(parent, handlerTimeout)
+}
+
+// Routes returns the message handler for every subject this consumer understands.
+// New event types are added here.
+func (c *NATSConsumer) Routes() map[string]nats.MsgHandler {
+	return map[string]nats.MsgHandler{
+		SubjectApplicationCreated:       c.handleCreatedMessage,
+		SubjectApplicationStatusUpdated: c.handleStatusUpdatedMessage,
+		SubjectApplicationPendingReminder: c.handlePendingReminderMessage,
+		SubjectPetUnavailable:           c.handlePetUnavailableMessage,
+	}
+}
+
+// PlanSubscriptions turns a configured subject list into the subscriptions to make, in order.
+// It rejects unknown and duplicate subjects and lists longer than maxSubjects.
+func PlanSubscriptions(subjects []string, routes map[string]nats.MsgHandler, maxSubjects int) ([]Subscription, error) {
+	if len(subjects) == 0 {
+		subjects = DefaultSubjects
+	}
+	if maxSubjects <= 0 {
+		maxSubjects = DefaultMaxSubjects
+	}
+	if len(subjects) > maxSubjects {
+		return nil, fmt.Errorf("%d subjects configured, at most %d allowed", len(subjects), maxSubjects)
+	}
+
+	subs := make([]Subscription, 0, len(subjects))
+	seen := make(map[string]bool, len(subjects))
+	for _, subject := range subjects {
+		handler, ok := routes[subject]
+		if !ok {
+			return nil, fmt.Errorf("no handler for subject '%s'", subject)
+		}
+		if seen[subject] {
+			return nil, fmt.Errorf("subject '%s' is configured more than once", subject)
+		}
+		seen[subject] = true
+		subs = append(subs, Subscription{Subject: subject, Handler: handler})
+	}
+	return subs, nil
+}
+
+// Subscriptions returns the subscriptions StartSubscribers makes: the configured subjects with
+// the environment prefix added, paired with their handlers.
+func (c *NATSConsumer) Subscriptions() ([]Subscription, error) {
+	planned, err := PlanSubscriptions(c.subjects, c.Routes(), c.maxSubjects)
+	if err != nil {
+		return nil, err
+	}
+	for i := range planned {
+		planned[i].Subject = c.namespace.Subject(planned[i].Subject)
+	}
+	return planned, nil
 }
 
 // StartSubscribers begins listening to configured NATS subjects.
 func (c *NATSConsumer) StartSubscribers() error {
 	log.Println("Notification Service | Starting NATS subscribers...")
 
-	// Subscribe to AdoptionApplicationCreated events
-	// For core NATS:
-	subCreated, err := c.nc.Subscribe("adoption.application.created", c.handleCreatedMessage)
-	// For JetStream (durable subscriber):
-	// subCreated, err := c.js.Subscribe("adoption.application.created", c.handleCreatedMessage, nats.Durable("notification-service-created"), nats.AckNone())
+	planned, err := c.Subscriptions()
 	if err != nil {
-		log.Printf("Notification Service | Error subscribing to 'adoption.application.created': %v", err)
+		log.Printf("Notification Service | Invalid NATS subject configuration: %v", err)
 		return err
 	}
-	c.subscriptions = append(c.subscriptions, subCreated)
-	log.Println("Notification Service | Subscribed to 'adoption.application.created'")
 
-	// Subscribe to AdoptionApplicationStatusUpdated events
-	// For core NATS:
-	subStatusUpdated, err := c.nc.Subscribe("adoption.application.status.updated", c.handleStatusUpdatedMessage)
-	// For JetStream (durable subscriber):
-	// subStatusUpdated, err := c.js.Subscribe("adoption.application.status.updated", c.handleStatusUpdatedMessage, nats.Durable("notification-service-status"), nats.AckNone())
-	if err != nil {
-		log.Printf("Notification Service | Error subscribing to 'adoption.application.status.updated': %v", err)
-		return err
+	for _, s := range planned {
+		// For core NATS:
+		sub, err := c.nc.Subscribe(s.Subject, s.Handler)
+		// For JetStream (durable subscriber):
+		// sub, err := c.js.Subscribe(s.Subject, s.Handler, nats.Durable("notification-service-"+s.Subject), nats.AckNone())
+		if err != nil {
+			log.Printf("Notification Service | Error subscribing to '%s': %v", s.Subject, err)
+			return err
+		}
+		c.subscriptions = append(c.subscriptions, sub)
+		log.Printf("Notification Service | Subscribed to '%s'", s.Subject)
 	}
-	c.subscriptions = append(c.subscriptions, subStatusUpdated)
-	log.Println("Notification Service | Subscribed to 'adoption.application.status.updated'")
 
 	// Keep the main goroutine alive or manage via application lifecycle
 	// For a simple worker, this might run indefinitely until Close() is called.
@@ -135,17 +315,19 @@ func (c *NATSConsumer) handleCreatedMessage(msg *nats.Msg) {
 			return
 		}
 
-		// Process the event using the injected handler
-		// Using a new context for each message processing, or pass one from a higher level.
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second) // Example timeout for processing
-		defer cancel()
+		// Process the event using the injected handler; status updates held for it follow.
+		c.sequencer.created(event.ApplicationID, func() {
+			ctx, cancel := c.handlerContext()
+			defer cancel()
 
-		if err := c.eventHandler.HandleAdoptionApplicationCreated(ctx, event); err != nil {
-			log.Printf("Notification Service | Error handling AdoptionApplicationCreatedEvent for AppID %s: %v", event.ApplicationID, err)
-			// Implement retry logic or dead-letter queue if necessary
-		} else {
-			log.Printf("Notification Service | Successfully processed AdoptionApplicationCreatedEvent for AppID %s", event.ApplicationID)
-		}
+			if err := c.eventHandler.HandleAdoptionApplicationCreated(ctx, event); err != nil {
+				log.Printf("Notification Service | Error handling AdoptionApplicationCreatedEvent for AppID %s: %v", event.ApplicationID, err)
+				// Implement retry logic or dead-letter queue if necessary
+			} else {
+				log.Printf("Notification Service | Successfully processed AdoptionApplicationCreatedEvent for AppID %s", event.ApplicationID)
+			}
+			c.observeLatency(event.PublishedAt, event.AppliedAt)
+		})
 	}
 }
 
@@ -165,14 +347,78 @@ func (c *NATSConsumer) handleStatusUpdatedMessage(msg *nats.Msg) {
 			return
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		// The update may be held until the application's created event has been handled, so it
+		// counts as running until then.
+		c.shutdownWg.Add(1)
+		c.sequencer.update(event.ApplicationID, event.AppliedAt, func() {
+			defer c.shutdownWg.Done()
+			ctx, cancel := c.handlerContext()
+			defer cancel()
+
+			if err := c.eventHandler.HandleAdoptionApplicationStatusUpdated(ctx, event); err != nil {
+				log.Printf("Notification Service | Error handling AdoptionApplicationStatusUpdatedEvent for AppID %s: %v", event.ApplicationID, err)
+			} else {
+				log.Printf("Notification Service | Successfully processed AdoptionApplicationStatusUpdatedEvent for AppID %s", event.ApplicationID)
+			}
+			c.observeLatency(event.PublishedAt, event.UpdatedAt)
+		})
+	}
+}
+
+func (c *NATSConsumer) handlePendingReminderMessage(msg *nats.Msg) {
+	c.shutdownWg.Add(1)
+	defer c.shutdownWg.Done()
+
+	select {
+	case <-c.stopChan:
+		log.Printf("Notification Service | Shutting down handlePendingReminderMessage goroutine for subject: %s", msg.Subject)
+		return
+	default:
+		log.Printf("Notification Service | Received message on subject '%s'", msg.Subject)
+		var event AdoptionApplicationPendingReminderEvent
+		if err := json.Unmarshal(msg.Data, &event); err != nil {
+			log.Printf("Notification Service | Error unmarshalling AdoptionApplicationPendingReminderEvent: %v. Data: %s", err, string(msg.Data))
+			return
+		}
+
+		ctx, cancel := c.handlerContext()
 		defer cancel()
 
-		if err := c.eventHandler.HandleAdoptionApplicationStatusUpdated(ctx, event); err != nil {
-			log.Printf("Notification Service | Error handling AdoptionApplicationStatusUpdatedEvent for AppID %s: %v", event.ApplicationID, err)
+		if err := c.eventHandler.HandleApplicationPendingReminder(ctx, event); err != nil {
+			log.Printf("Notification Service | Error handling AdoptionApplicationPendingReminderEvent for AppID %s: %v", event.ApplicationID, err)
 		} else {
-			log.Printf("Notification Service | Successfully processed AdoptionApplicationStatusUpdatedEvent for AppID %s", event.ApplicationID)
+			log.Printf("Notification Service | Successfully processed AdoptionApplicationPendingReminderEvent for AppID %s", event.ApplicationID)
 		}
+		// Only published_at: pending_since is when the application was last touched, not the event.
+		c.observeLatency(event.PublishedAt, time.Time{})
+	}
+}
+
+func (c *NATSConsumer) handlePetUnavailableMessage(msg *nats.Msg) {
+	c.shutdownWg.Add(1)
+	defer c.shutdownWg.Done()
+
+	select {
+	case <-c.stopChan:
+		log.Printf("Notification Service | Shutting down handlePetUnavailableMessage goroutine for subject: %s", msg.Subject)
+		return
+	default:
+		log.Printf("Notification Service | Received message on subject '%s'", msg.Subject)
+		var event PetUnavailableEvent
+		if err := json.Unmarshal(msg.Data, &event); err != nil {
+			log.Printf("Notification Service | Error unmarshalling PetUnavailableEvent: %v. Data: %s", err, string(msg.Data))
+			return
+		}
+
+		ctx, cancel := c.handlerContext()
+		defer cancel()
+
+		if err := c.eventHandler.HandlePetUnavailable(ctx, event); err != nil {
+			log.Printf("Notification Service | Error handling PetUnavailableEvent for PetID %s: %v", event.PetID, err)
+		} else {
+			log.Printf("Notification Service | Successfully processed PetUnavailableEvent for PetID %s", event.PetID)
+		}
+		c.observeLatency(event.PublishedAt, event.UnavailableAt)
 	}
 }
 
@@ -211,8 +457,20 @@ func (c *NATSConsumer) Close() {
 	select {
 	case <-done:
 		log.Println("Notification Service | All message handlers have completed.")
-	case <-time.After(10 * time.Second): // Timeout for waiting
-		log.Println("Notification Service | Timeout waiting for message handlers to complete.")
+	case <-time.After(c.drainTimeout):
+		log.Printf("Notification Service | Timeout after %s waiting for message handlers, cancelling the remaining ones.", c.drainTimeout)
+		if c.cancel != nil {
+			c.cancel()
+		}
+		select {
+		case <-done:
+			log.Println("Notification Service | Remaining message handlers stopped after cancellation.")
+		case <-time.After(drainCancelGrace):
+			log.Println("Notification Service | Some message handlers did not stop after cancellation.")
+		}
+	}
+	if c.cancel != nil {
+		c.cancel()
 	}
 
 	log.Println("Notification Service | NATS consumer shut down.")
@@ -0,0 +1,142 @@
+package consumer
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// DefaultOrderingWait is how long a status update for a new application waits for the
+// application's created event when no wait is configured.
+const DefaultOrderingWait = 5 * time.Second
+
+// recentApplicationWindow bounds which applications can still have their created event in
+// flight. Updates for applications created longer ago are handled right away.
+const recentApplicationWindow = time.Minute
+
+// applicationSequencer keeps the events of one application in order. The created and
+// status-updated subjects are delivered on separate subscriptions, so an "approved" update can
+// overtake the "received" event of an application that was approved right after it was created.
+// Such an update is held until the created event has been handled, or until wait passes, and
+// events held for the same application are then handled one at a time in arrival order.
+type applicationSequencer struct {
+	wait time.Duration // 0 disables holding
+
+	mu        sync.Mutex
+	apps      map[string]*applicationEvents // Keyed by application ID
+	lastPrune time.Time
+}
+
+// applicationEvents is the ordering state of one application.
+type applicationEvents struct {
+	createdDone bool        // The created event has been handled
+	doneAt      time.Time   // When it was, for pruning
+	busy        bool        // Events of the application are being handled; new ones must queue
+	held        []func()    // Events waiting their turn, in arrival order
+	timer       *time.Timer // Releases held events if the created event does not arrive in time
+}
+
+func newApplicationSequencer(wait time.Duration) *applicationSequencer {
+	return &applicationSequencer{wait: wait, apps: make(map[string]*applicationEvents)}
+}
+
+// created handles the created event of an application, then any events held for it.
+func (s *applicationSequencer) created(applicationID string, handle func()) {
+	s.mu.Lock()
+	app := s.apps[applicationID]
+	if app == nil {
+		app = &applicationEvents{}
+		s.apps[applicationID] = app
+	}
+	if app.timer != nil {
+		app.timer.Stop()
+		app.timer = nil
+	}
+	app.busy = true
+	s.mu.Unlock()
+
+	handle()
+
+	s.mu.Lock()
+	app.createdDone = true
+	app.doneAt = time.Now()
+	s.pruneLocked(app.doneAt)
+	s.mu.Unlock()
+	s.drain(app)
+}
+
+// update handles an event that must not overtake the application's created event. appliedAt is
+// when the application was created; a zero value, e.g. from an older publisher, is treated as old.
+func (s *applicationSequencer) update(applicationID string, appliedAt time.Time, handle func()) {
+	s.mu.Lock()
+	app := s.apps[applicationID]
+	switch {
+	case app != nil && app.busy:
+		app.held = append(app.held, handle)
+		s.mu.Unlock()
+		return
+	case app != nil && app.createdDone,
+		s.wait <= 0 || appliedAt.IsZero() || time.Since(appliedAt) > recentApplicationWindow:
+		s.mu.Unlock()
+		handle()
+		return
+	}
+
+	// The created event of a new application has not been handled yet: wait for it.
+	if app == nil {
+		app = &applicationEvents{}
+		s.apps[applicationID] = app
+	}
+	app.held = append(app.held, handle)
+	if app.timer == nil {
+		app.timer = time.AfterFunc(s.wait, func() { s.release(applicationID) })
+	}
+	s.mu.Unlock()
+}
+
+// release handles the events held for an application whose created event did not arrive in time.
+func (s *applicationSequencer) release(applicationID string) {
+	s.mu.Lock()
+	app := s.apps[applicationID]
+	if app == nil || app.busy || app.createdDone || len(app.held) == 0 {
+		s.mu.Unlock()
+		return // The created event arrived after all
+	}
+	app.timer = nil
+	app.busy = true
+	log.Printf("Notification Service | Created event for AppID %s not received within %s; handling %d held event(s) anyway.", applicationID, s.wait, len(app.held))
+	// Forget the application, so a created event that shows up later is handled normally.
+	delete(s.apps, applicationID)
+	s.mu.Unlock()
+	s.drain(app)
+}
+
+// drain handles app's held events one at a time, including ones queued meanwhile.
+func (s *applicationSequencer) drain(app *applicationEvents) {
+	for {
+		s.mu.Lock()
+		if len(app.held) == 0 {
+			app.busy = false
+			s.mu.Unlock()
+			return
+		}
+		next := app.held[0]
+		app.held = app.held[1:]
+		s.mu.Unlock()
+		next()
+	}
+}
+
+// pruneLocked forgets applications whose created event was handled long enough ago that no
+// update for them will be held anymore. It runs at most once per window. s.mu must be held.
+func (s *applicationSequencer) pruneLocked(now time.Time) {
+	if now.Sub(s.lastPrune) < recentApplicationWindow {
+		return
+	}
+	s.lastPrune = now
+	for id, app := range s.apps {
+		if app.createdDone && !app.busy && now.Sub(app.doneAt) > recentApplicationWindow {
+			delete(s.apps, id)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package delivery
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// Status is the delivery outcome reported by the email provider.
+type Status string
+
+const (
+	StatusDelivered  Status = "delivered"
+	StatusBounced    Status = "bounced"
+	StatusComplained Status = "complained" // Recipient marked the email as spam
+)
+
+// ParseStatus converts a provider value into a Status.
+func ParseStatus(value string) (Status, bool) {
+	switch Status(strings.ToLower(strings.TrimSpace(value))) {
+	case StatusDelivered:
+		return StatusDelivered, true
+	case StatusBounced, "bounce":
+		return StatusBounced, true
+	case StatusComplained, "complaint", "spam":
+		return StatusComplained, true
+	default:
+		return "", false
+	}
+}
+
+// Event is one delivery status callback for a recipient.
+type Event struct {
+	Recipient  string    `json:"recipient"`
+	Status     Status    `json:"status"`
+	Reason     string    `json:"reason,omitempty"`
+	MessageID  string    `json:"message_id,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// maxHistoryPerRecipient bounds the memory used per address; older events are dropped.
+const maxHistoryPerRecipient = 20
+
+// Tracker keeps the delivery history per recipient and the suppression list built from it.
+// It is in memory, so history and suppressions are lost on restart. It is safe for concurrent use.
+type Tracker struct {
+	mu              sync.RWMutex
+	history         map[string][]Event
+	suppressed      map[string]Status
+	suppressBounced bool // Suppress future sends to addresses that bounced or complained
+}
+
+// NewTracker creates an empty Tracker. With suppressBounced, bounced and complaining
+// recipients are added to the suppression list.
+func NewTracker(suppressBounced bool) *Tracker {
+	return &Tracker{
+		history:         make(map[string][]Event),
+		suppressed:      make(map[string]Status),
+		suppressBounced: suppressBounced,
+	}
+}
+
+func normalizeAddress(address string) string {
+	return strings.ToLower(strings.TrimSpace(address))
+}
+
+// Record stores a delivery event and updates the suppression list.
+func (t *Tracker) Record(event Event) {
+	recipient := normalizeAddress(event.Recipient)
+	if recipient == "" {
+		return
+	}
+	event.Recipient = recipient
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now().UTC()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	history := append(t.history[recipient], event)
+	if len(history) > maxHistoryPerRecipient {
+		history = history[len(history)-maxHistoryPerRecipient:]
+	}
+	t.history[recipient] = history
+
+	if t.suppressBounced && (event.Status == StatusBounced || event.Status == StatusComplained) {
+		t.suppressed[recipient] = event.Status
+	}
+}
+
+// History returns the recorded events for a recipient, oldest first.
+func (t *Tracker) History(recipient string) []Event {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	history := t.history[normalizeAddress(recipient)]
+	return append([]Event(nil), history...)
+}
+
+// IsSuppressed reports whether emails to the recipient should be skipped.
+func (t *Tracker) IsSuppressed(recipient string) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	_, ok := t.suppressed[normalizeAddress(recipient)]
+	return ok
+}
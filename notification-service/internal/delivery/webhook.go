@@ -0,0 +1,60 @@
+package delivery
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+)
+
+// WebhookPayload is the body accepted by the delivery status webhook. Providers with a different
+// format are expected to be mapped onto it (e.g. by a small relay or the provider's templating).
+type WebhookPayload struct {
+	Recipient  string    `json:"recipient"`
+	Status     string    `json:"status"` // "delivered", "bounced" or "complained"
+	Reason     string    `json:"reason,omitempty"`
+	MessageID  string    `json:"message_id,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"` // Optional; defaults to the time received
+}
+
+// WebhookHandler returns an HTTP handler that records delivery status callbacks in the tracker.
+// Callers must send the shared token in the X-Webhook-Token header; an empty token disables the endpoint.
+func WebhookHandler(tracker *Tracker, token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if token == "" {
+			http.Error(w, "delivery webhook is disabled", http.StatusForbidden)
+			return
+		}
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("X-Webhook-Token")), []byte(token)) != 1 {
+			http.Error(w, "invalid or missing webhook token", http.StatusUnauthorized)
+			return
+		}
+
+		var payload WebhookPayload
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 64<<10)).Decode(&payload); err != nil {
+			http.Error(w, "invalid payload: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		status, ok := ParseStatus(payload.Status)
+		if !ok || payload.Recipient == "" {
+			http.Error(w, "recipient and a status of delivered, bounced or complained are required", http.StatusBadRequest)
+			return
+		}
+
+		tracker.Record(Event{
+			Recipient:  payload.Recipient,
+			Status:     status,
+			Reason:     payload.Reason,
+			MessageID:  payload.MessageID,
+			OccurredAt: payload.OccurredAt,
+		})
+		log.Printf("Notification Service | Delivery status '%s' recorded for %s (message %s)", status, payload.Recipient, payload.MessageID)
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
@@ -0,0 +1,102 @@
+// Package digest persists notifications that are batched into a single summary email per user.
+package digest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Item is one event waiting to be included in a user's digest.
+type Item struct {
+	EventType     string    `json:"event_type"` // service.EmailApplicationCreated or service.EmailApplicationStatusUpdated
+	ApplicationID string    `json:"application_id"`
+	PetID         string    `json:"pet_id"`
+	PetName       string    `json:"pet_name"`
+	Status        string    `json:"status"`
+	ReviewNotes   string    `json:"review_notes,omitempty"`
+	QueuedAt      time.Time `json:"queued_at"`
+}
+
+// Store holds pending digest items per user until they are flushed.
+type Store interface {
+	// Add queues an item for the user. The user's digest window starts with their first pending item.
+	Add(ctx context.Context, userID string, item Item) error
+	// DueUsers returns the users whose first pending item was queued at or before cutoff.
+	DueUsers(ctx context.Context, cutoff time.Time) ([]string, error)
+	// Take removes and returns all pending items of a user, oldest first.
+	Take(ctx context.Context, userID string) ([]Item, error)
+}
+
+// redisStore keeps each user's items in a list and the users with pending items in a sorted
+// set scored by the time of their first item, so pending digests survive restarts.
+type redisStore struct {
+	client *redis.Client
+	prefix string // e.g., "notify:digest:"
+}
+
+// NewRedisStore creates a Store backed by Redis.
+func NewRedisStore(client *redis.Client, keyPrefix string) Store {
+	if keyPrefix == "" {
+		keyPrefix = "notify:digest:"
+	}
+	return &redisStore{client: client, prefix: keyPrefix}
+}
+
+func (s *redisStore) usersKey() string {
+	return s.prefix + "users"
+}
+
+func (s *redisStore) itemsKey(userID string) string {
+	return fmt.Sprintf("%sitems:%s", s.prefix, userID)
+}
+
+func (s *redisStore) Add(ctx context.Context, userID string, item Item) error {
+	if item.QueuedAt.IsZero() {
+		item.QueuedAt = time.Now().UTC()
+	}
+	data, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.RPush(ctx, s.itemsKey(userID), data)
+		pipe.ZAddNX(ctx, s.usersKey(), redis.Z{Score: float64(item.QueuedAt.Unix()), Member: userID})
+		return nil
+	})
+	return err
+}
+
+func (s *redisStore) DueUsers(ctx context.Context, cutoff time.Time) ([]string, error) {
+	return s.client.ZRangeByScore(ctx, s.usersKey(), &redis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatInt(cutoff.Unix(), 10),
+	}).Result()
+}
+
+func (s *redisStore) Take(ctx context.Context, userID string) ([]Item, error) {
+	var values *redis.StringSliceCmd
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		values = pipe.LRange(ctx, s.itemsKey(userID), 0, -1)
+		pipe.Del(ctx, s.itemsKey(userID))
+		pipe.ZRem(ctx, s.usersKey(), userID)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]Item, 0, len(values.Val()))
+	for _, value := range values.Val() {
+		var item Item
+		if err := json.Unmarshal([]byte(value), &item); err != nil {
+			return items, fmt.Errorf("decoding digest item for user %s: %w", userID, err)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
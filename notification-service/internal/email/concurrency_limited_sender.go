@@ -0,0 +1,25 @@
+package email
+
+// concurrencyLimitedSender bounds the number of emails being sent at once. Sends beyond the
+// limit wait for a free slot, so a burst of events queues up instead of opening many SMTP
+// connections at the same time.
+type concurrencyLimitedSender struct {
+	next  EmailSender
+	slots chan struct{}
+}
+
+// NewConcurrencyLimitedSender wraps next so that at most maxInFlight emails are sent concurrently.
+// A maxInFlight of 0 or less disables the limit and returns next unchanged.
+func NewConcurrencyLimitedSender(next EmailSender, maxInFlight int) EmailSender {
+	if maxInFlight <= 0 {
+		return next
+	}
+	return &concurrencyLimitedSender{next: next, slots: make(chan struct{}, maxInFlight)}
+}
+
+// SendEmail waits for a free slot and then sends the email through the wrapped sender.
+func (s *concurrencyLimitedSender) SendEmail(to []string, subject, body string, isHTML bool) error {
+	s.slots <- struct{}{}
+	defer func() { <-s.slots }()
+	return s.next.SendEmail(to, subject, body, isHTML)
+}
@@ -0,0 +1,89 @@
+package email
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// WindowCounter counts sends per recipient in fixed time windows.
+type WindowCounter interface {
+	// Incr adds one to the counter for key and returns the new count within the current window.
+	Incr(ctx context.Context, key string, window time.Duration) (int64, error)
+}
+
+// redisWindowCounter keeps the counters in Redis so the limit holds across service instances.
+type redisWindowCounter struct {
+	client *redis.Client
+	prefix string // e.g., "notify:rate:"
+}
+
+// NewRedisWindowCounter creates a WindowCounter backed by Redis keys that expire with the window.
+func NewRedisWindowCounter(client *redis.Client, keyPrefix string) WindowCounter {
+	if keyPrefix == "" {
+		keyPrefix = "notify:rate:"
+	}
+	return &redisWindowCounter{client: client, prefix: keyPrefix}
+}
+
+// Incr increments the key and starts its expiry on the first send of the window.
+func (c *redisWindowCounter) Incr(ctx context.Context, key string, window time.Duration) (int64, error) {
+	var incr *redis.IntCmd
+	_, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.Incr(ctx, c.prefix+key)
+		pipe.ExpireNX(ctx, c.prefix+key, window)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+// rateLimitCheckTimeout bounds the counter lookup, since SendEmail has no context of its own.
+const rateLimitCheckTimeout = 2 * time.Second
+
+// rateLimitedSender drops emails to recipients that already received limit emails in the
+// current window, so a burst of status updates does not flood a single inbox.
+type rateLimitedSender struct {
+	next    EmailSender
+	counter WindowCounter
+	limit   int
+	window  time.Duration
+}
+
+// NewRateLimitedSender wraps next so that each recipient gets at most limit emails per window.
+// A limit of 0 or less disables rate limiting and returns next unchanged.
+func NewRateLimitedSender(next EmailSender, counter WindowCounter, limit int, window time.Duration) EmailSender {
+	if limit <= 0 || counter == nil {
+		return next
+	}
+	return &rateLimitedSender{next: next, counter: counter, limit: limit, window: window}
+}
+
+// SendEmail sends to the recipients still under their limit. Excess emails are dropped
+// without an error; the event counts as handled. If the counter is unavailable the email
+// is sent anyway, so a Redis outage does not stop notifications.
+func (s *rateLimitedSender) SendEmail(to []string, subject, body string, isHTML bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), rateLimitCheckTimeout)
+	defer cancel()
+
+	allowed := make([]string, 0, len(to))
+	for _, recipient := range to {
+		count, err := s.counter.Incr(ctx, strings.ToLower(strings.TrimSpace(recipient)), s.window)
+		if err != nil {
+			log.Printf("Notification Service | Warning: Rate limit check failed for %s, sending anyway: %v", recipient, err)
+		} else if count > int64(s.limit) {
+			log.Printf("Notification Service | Rate limit reached for %s (%d per %s), dropping email. Subject: %s", recipient, s.limit, s.window, subject)
+			continue
+		}
+		allowed = append(allowed, recipient)
+	}
+	if len(allowed) == 0 {
+		return nil
+	}
+	return s.next.SendEmail(allowed, subject, body, isHTML)
+}
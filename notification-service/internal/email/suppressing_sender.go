@@ -0,0 +1,34 @@
+package email
+
+import (
+	"log"
+)
+
+// suppressingSender drops recipients on a suppression list before handing the email to the
+// wrapped sender, so addresses that bounced or complained are not mailed again.
+type suppressingSender struct {
+	next         EmailSender
+	isSuppressed func(recipient string) bool
+}
+
+// NewSuppressingSender wraps next so that recipients for which isSuppressed returns true are skipped.
+func NewSuppressingSender(next EmailSender, isSuppressed func(recipient string) bool) EmailSender {
+	return &suppressingSender{next: next, isSuppressed: isSuppressed}
+}
+
+// SendEmail sends to the recipients that are not suppressed. If every recipient is suppressed,
+// nothing is sent and no error is returned; the event counts as handled.
+func (s *suppressingSender) SendEmail(to []string, subject, body string, isHTML bool) error {
+	allowed := make([]string, 0, len(to))
+	for _, recipient := range to {
+		if s.isSuppressed(recipient) {
+			log.Printf("Notification Service | Skipping suppressed recipient %s. Subject: %s", recipient, subject)
+			continue
+		}
+		allowed = append(allowed, recipient)
+	}
+	if len(allowed) == 0 {
+		return nil
+	}
+	return s.next.SendEmail(allowed, subject, body, isHTML)
+}
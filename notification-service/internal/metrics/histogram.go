@@ -0,0 +1,118 @@
+// Package metrics keeps in-process metrics and serves them in the Prometheus text format.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// LatencyBuckets are the default upper bounds, in seconds, for latency histograms.
+var LatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// Histogram counts observations into cumulative buckets, like a Prometheus histogram.
+// A nil *Histogram ignores observations.
+type Histogram struct {
+	name    string
+	help    string
+	buckets []float64 // Sorted upper bounds; +Inf is implicit
+
+	mu     sync.Mutex
+	counts []uint64 // counts[i] is the number of observations <= buckets[i]
+	sum    float64
+	count  uint64
+}
+
+// NewHistogram creates a histogram with the given bucket upper bounds (LatencyBuckets if empty).
+func NewHistogram(name, help string, buckets []float64) *Histogram {
+	if len(buckets) == 0 {
+		buckets = LatencyBuckets
+	}
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+	return &Histogram{name: name, help: help, buckets: sorted, counts: make([]uint64, len(sorted))}
+}
+
+// Observe records one value.
+func (h *Histogram) Observe(v float64) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i, bound := range h.buckets {
+		if v <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += v
+	h.count++
+}
+
+// Count returns the number of observations.
+func (h *Histogram) Count() uint64 {
+	if h == nil {
+		return 0
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.count
+}
+
+// Sum returns the sum of all observed values.
+func (h *Histogram) Sum() float64 {
+	if h == nil {
+		return 0
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.sum
+}
+
+// WriteTo writes the histogram in the Prometheus text exposition format.
+func (h *Histogram) WriteTo(w io.Writer) (int64, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var written int64
+	write := func(format string, args ...interface{}) error {
+		n, err := fmt.Fprintf(w, format, args...)
+		written += int64(n)
+		return err
+	}
+	if err := write("# HELP %s %s\n# TYPE %s histogram\n", h.name, h.help, h.name); err != nil {
+		return written, err
+	}
+	for i, bound := range h.buckets {
+		if err := write("%s_bucket{le=\"%s\"} %d\n", h.name, strconv.FormatFloat(bound, 'g', -1, 64), h.counts[i]); err != nil {
+			return written, err
+		}
+	}
+	if err := write("%s_bucket{le=\"+Inf\"} %d\n%s_sum %s\n%s_count %d\n",
+		h.name, h.count, h.name, strconv.FormatFloat(h.sum, 'g', -1, 64), h.name, h.count); err != nil {
+		return written, err
+	}
+	return written, nil
+}
+
+// Handler serves the given histograms in the Prometheus text format. Nil histograms are skipped.
+func Handler(histograms ...*Histogram) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		for _, h := range histograms {
+			if h == nil {
+				continue
+			}
+			if _, err := h.WriteTo(w); err != nil {
+				return
+			}
+		}
+	})
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/zhandarbeks/petstore-final-project/internal/features"
+	"github.com/zhandarbeks/petstore-final-project/notification-service/internal/digest"
+)
+
+// EnableDigest switches the service to digest mode: events are queued in store and sent as
+// one summary email per user by FlushDigests instead of one email per event.
+func (s *NotificationService) EnableDigest(store digest.Store) {
+	s.enableDigest(store, true, nil)
+}
+
+// SoftLaunchDigest is EnableDigest for the users in userIDs only; everyone else keeps getting
+// one email per event.
+func (s *NotificationService) SoftLaunchDigest(store digest.Store, userIDs []string) {
+	s.enableDigest(store, false, userIDs)
+}
+
+func (s *NotificationService) enableDigest(store digest.Store, forEveryone bool, userIDs []string) {
+	s.digestStore = store
+	s.features = features.NewFlags()
+	s.features.Set(features.Digest, forEveryone, userIDs)
+}
+
+// usesDigest reports whether events for userID are queued for the digest.
+func (s *NotificationService) usesDigest(userID string) bool {
+	return s.digestStore != nil && s.features.IsFeatureEnabledForUser(features.Digest, userID)
+}
+
+// queueDigestItem stores an event for the user's next digest.
+func (s *NotificationService) queueDigestItem(ctx context.Context, userID string, item digest.Item) error {
+	item.QueuedAt = time.Now().UTC()
+	if err := s.digestStore.Add(ctx, userID, item); err != nil {
+		log.Printf("Notification Service | Error queueing digest item for UserID %s, AppID %s: %v", userID, item.ApplicationID, err)
+		return fmt.Errorf("failed to queue digest item: %w", err)
+	}
+	log.Printf("Notification Service | Queued '%s' for the digest of UserID %s (AppID %s).", item.EventType, userID, item.ApplicationID)
+	return nil
+}
+
+// FlushDigests sends one digest email to every user whose first pending item was queued at or
+// before cutoff, and returns the number of digests sent. If a digest cannot be sent, its items
+// are queued again for the next flush.
+func (s *NotificationService) FlushDigests(ctx context.Context, cutoff time.Time) (int, error) {
+	if s.digestStore == nil {
+		return 0, nil
+	}
+	userIDs, err := s.digestStore.DueUsers(ctx, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list due digests: %w", err)
+	}
+
+	sent := 0
+	for _, userID := range userIDs {
+		items, err := s.digestStore.Take(ctx, userID)
+		if err != nil {
+			log.Printf("Notification Service | Error taking digest items for UserID %s: %v", userID, err)
+			continue
+		}
+		if len(items) == 0 {
+			continue
+		}
+		if err := s.sendDigest(ctx, userID, items); err != nil {
+			log.Printf("Notification Service | Error sending digest to UserID %s, requeueing %d items: %v", userID, len(items), err)
+			for _, item := range items {
+				if err := s.digestStore.Add(ctx, userID, item); err != nil {
+					log.Printf("Notification Service | Error requeueing digest item for UserID %s, AppID %s: %v", userID, item.ApplicationID, err)
+				}
+			}
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+func (s *NotificationService) sendDigest(ctx context.Context, userID string, items []digest.Item) error {
+	userDetails, err := s.userServiceClient.GetUserDetails(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch user details for digest: %w", err)
+	}
+	if userDetails == nil || userDetails.GetEmail() == "" {
+		return fmt.Errorf("user email not found for UserID %s", userID)
+	}
+
+	subject, body := digestEmail(userDetails, items)
+	if err := s.emailSender.SendEmail([]string{userDetails.GetEmail()}, subject, body, true); err != nil {
+		return fmt.Errorf("failed to send digest email: %w", err)
+	}
+	log.Printf("Notification Service | Digest with %d updates sent to %s.", len(items), userDetails.GetEmail())
+	return nil
+}
+
+// RunDigestFlusher calls FlushDigests every interval for digests whose window has passed,
+// until ctx is cancelled.
+func (s *NotificationService) RunDigestFlusher(ctx context.Context, window, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.FlushDigests(ctx, time.Now().Add(-window)); err != nil {
+				log.Printf("Notification Service | Digest flush failed: %v", err)
+			}
+		}
+	}
+}
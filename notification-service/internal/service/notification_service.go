@@ -2,12 +2,17 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"time"
 
 	// Adjust import paths to match your project's module path and structure
+	pbAdoption "github.com/zhandarbeks/petstore-final-project/genprotos/adoption"
 	"github.com/zhandarbeks/petstore-final-project/notification-service/internal/client"    // For UserServiceClient, PetServiceClient
 	"github.com/zhandarbeks/petstore-final-project/notification-service/internal/consumer"  // For event structs
+	"github.com/zhandarbeks/petstore-final-project/internal/features"
+	"github.com/zhandarbeks/petstore-final-project/notification-service/internal/digest"
 	"github.com/zhandarbeks/petstore-final-project/notification-service/internal/email"     // For EmailSender
 )
 
@@ -17,14 +22,19 @@ type NotificationService struct {
 	emailSender       email.EmailSender
 	userServiceClient client.UserServiceClient
 	petServiceClient  client.PetServiceClient
+	digestStore       digest.Store // When set, events are queued for the digest instead of emailed one by one
+	features          *features.Flags // Which users digest mode applies to
+	adoptionServiceClient client.AdoptionServiceClient // Finds a pet's applicants for pet.unavailable events
+	maxDescriptionLength  int                          // Cap on pet descriptions embedded in emails, in characters (0 = no limit)
 }
 
 // NewNotificationService creates a new NotificationService.
+// The result implements consumer.EventHandler and also renders email previews.
 func NewNotificationService(
 	sender email.EmailSender,
 	userClient client.UserServiceClient,
 	petClient client.PetServiceClient,
-) consumer.EventHandler { // Return the interface type
+) *NotificationService {
 	if sender == nil || userClient == nil || petClient == nil {
 		log.Fatal("Notification Service | FATAL: EmailSender, UserServiceClient, and PetServiceClient cannot be nil")
 	}
@@ -32,9 +42,22 @@ func NewNotificationService(
 		emailSender:       sender,
 		userServiceClient: userClient,
 		petServiceClient:  petClient,
+		maxDescriptionLength: DefaultMaxDescriptionLength,
 	}
 }
 
+// SetMaxDescriptionLength sets how many characters of a pet's description emails include before
+// cutting it with an ellipsis. 0 or less includes descriptions in full.
+func (s *NotificationService) SetMaxDescriptionLength(maxRunes int) {
+	s.maxDescriptionLength = maxRunes
+}
+
+// SetAdoptionServiceClient sets the client used to find a pet's applicants. Without it,
+// pet.unavailable events cannot be handled.
+func (s *NotificationService) SetAdoptionServiceClient(adoptionClient client.AdoptionServiceClient) {
+	s.adoptionServiceClient = adoptionClient
+}
+
 // HandleAdoptionApplicationCreated processes an event when a new adoption application is created.
 func (s *NotificationService) HandleAdoptionApplicationCreated(ctx context.Context, event consumer.AdoptionApplicationCreatedEvent) error {
 	log.Printf("Notification Service | Handling AdoptionApplicationCreated event for AppID: %s, UserID: %s, PetID: %s",
@@ -66,17 +89,19 @@ func (s *NotificationService) HandleAdoptionApplicationCreated(ctx context.Conte
 		return fmt.Errorf("pet details not found or name is empty for PetID %s", event.PetID)
 	}
 
+	if s.usesDigest(event.UserID) {
+		return s.queueDigestItem(ctx, event.UserID, digest.Item{
+			EventType:     EmailApplicationCreated,
+			ApplicationID: event.ApplicationID,
+			PetID:         event.PetID,
+			PetName:       petDetails.GetName(),
+			Status:        event.Status,
+		})
+	}
+
 	// 3. Construct and Send Email
 	recipientEmail := userDetails.GetEmail()
-	subject := fmt.Sprintf("Adoption Application Received for %s (ID: %s)", petDetails.GetName(), event.ApplicationID)
-	body := fmt.Sprintf(`
-		<h1>Adoption Application Received!</h1>
-		<p>Dear %s,</p>
-		<p>Thank you for submitting your adoption application (ID: %s) for <strong>%s</strong> (Pet ID: %s).</p>
-		<p>Your application is currently in status: <strong>%s</strong>.</p>
-		<p>We will review your application and get back to you soon.</p>
-		<p>Thank you,<br/>The PetStore Team</p>
-	`, userDetails.GetFullName(), event.ApplicationID, petDetails.GetName(), event.PetID, event.Status)
+	subject, body := applicationCreatedEmail(userDetails, petDetails, event.ApplicationID, event.Status, s.maxDescriptionLength)
 
 	err = s.emailSender.SendEmail([]string{recipientEmail}, subject, body, true) // true for HTML email
 	if err != nil {
@@ -115,35 +140,155 @@ func (s *NotificationService) HandleAdoptionApplicationStatusUpdated(ctx context
 		return fmt.Errorf("pet details not found or name is empty for PetID %s", event.PetID)
 	}
 
+	if s.usesDigest(event.UserID) {
+		return s.queueDigestItem(ctx, event.UserID, digest.Item{
+			EventType:     EmailApplicationStatusUpdated,
+			ApplicationID: event.ApplicationID,
+			PetID:         event.PetID,
+			PetName:       petDetails.GetName(),
+			Status:        event.NewStatus,
+			ReviewNotes:   event.ReviewNotes,
+		})
+	}
+
 	// 3. Construct and Send Email
 	recipientEmail := userDetails.GetEmail()
-	subject := fmt.Sprintf("Update on Your Adoption Application for %s (ID: %s)", petDetails.GetName(), event.ApplicationID)
-	body := fmt.Sprintf(`
-		<h1>Adoption Application Status Update!</h1>
-		<p>Dear %s,</p>
-		<p>There's an update on your adoption application (ID: %s) for <strong>%s</strong> (Pet ID: %s).</p>
-		<p>Your application status is now: <strong>%s</strong>.</p>
-	`, userDetails.GetFullName(), event.ApplicationID, petDetails.GetName(), event.PetID, event.NewStatus)
+	subject, body := applicationStatusUpdatedEmail(userDetails, petDetails, event.ApplicationID, event.NewStatus, event.ReviewNotes)
 
-	if event.ReviewNotes != "" {
-		body += fmt.Sprintf("<p>Reviewer's Notes: %s</p>", event.ReviewNotes)
+	err = s.emailSender.SendEmail([]string{recipientEmail}, subject, body, true) // true for HTML email
+	if err != nil {
+		log.Printf("Notification Service | Error sending 'Status Updated' email to %s for AppID %s: %v", recipientEmail, event.ApplicationID, err)
+		return fmt.Errorf("failed to send status update email: %w", err)
 	}
 
-	if event.NewStatus == "APPROVED" { // Assuming "APPROVED" is the string representation from domain/consumer event
-		body += "<p>Congratulations! Your application has been approved. We will contact you shortly with the next steps.</p>"
-	} else if event.NewStatus == "REJECTED" {
-		body += "<p>We regret to inform you that your application was not approved at this time. Thank you for your interest.</p>"
+	log.Printf("Notification Service | 'Status Updated' email sent successfully to %s for AppID %s.", recipientEmail, event.ApplicationID)
+	return nil
+}
+
+// HandleApplicationPendingReminder tells the applicant that their application is still waiting
+// for review, so a slow review does not look like a lost application.
+func (s *NotificationService) HandleApplicationPendingReminder(ctx context.Context, event consumer.AdoptionApplicationPendingReminderEvent) error {
+	log.Printf("Notification Service | Handling AdoptionApplicationPendingReminder event for AppID: %s, PendingSince: %s",
+		event.ApplicationID, event.PendingSince.Format(time.RFC3339))
+
+	userDetails, err := s.userServiceClient.GetUserDetails(ctx, event.UserID)
+	if err != nil {
+		log.Printf("Notification Service | Error fetching user details for UserID %s: %v", event.UserID, err)
+		return fmt.Errorf("failed to fetch user details for pending reminder: %w", err)
+	}
+	if userDetails == nil || userDetails.GetEmail() == "" {
+		log.Printf("Notification Service | User details or email not found for UserID %s", event.UserID)
+		return fmt.Errorf("user email not found for UserID %s", event.UserID)
 	}
 
-	body += "<p>Thank you,<br/>The PetStore Team</p>"
+	petDetails, err := s.petServiceClient.GetPetDetails(ctx, event.PetID)
+	if err != nil {
+		log.Printf("Notification Service | Error fetching pet details for PetID %s: %v", event.PetID, err)
+		return fmt.Errorf("failed to fetch pet details for pending reminder: %w", err)
+	}
+	if petDetails == nil || petDetails.GetName() == "" {
+		log.Printf("Notification Service | Pet details or name not found for PetID %s", event.PetID)
+		return fmt.Errorf("pet details not found or name is empty for PetID %s", event.PetID)
+	}
 
-	err = s.emailSender.SendEmail([]string{recipientEmail}, subject, body, true) // true for HTML email
+	if s.usesDigest(event.UserID) {
+		return s.queueDigestItem(ctx, event.UserID, digest.Item{
+			EventType:     EmailApplicationPendingReminder,
+			ApplicationID: event.ApplicationID,
+			PetID:         event.PetID,
+			PetName:       petDetails.GetName(),
+			Status:        event.Status,
+		})
+	}
+
+	recipientEmail := userDetails.GetEmail()
+	subject, body := applicationPendingReminderEmail(userDetails, petDetails, event.ApplicationID, daysSince(event.PendingSince))
+	if err := s.emailSender.SendEmail([]string{recipientEmail}, subject, body, true); err != nil {
+		log.Printf("Notification Service | Error sending 'Pending Reminder' email to %s for AppID %s: %v", recipientEmail, event.ApplicationID, err)
+		return fmt.Errorf("failed to send pending reminder email: %w", err)
+	}
+
+	log.Printf("Notification Service | 'Pending Reminder' email sent successfully to %s for AppID %s.", recipientEmail, event.ApplicationID)
+	return nil
+}
+
+// daysSince returns the number of whole days since t, at least 1.
+func daysSince(t time.Time) int {
+	if days := int(time.Since(t).Hours() / 24); days > 1 {
+		return days
+	}
+	return 1
+}
+
+// HandlePetUnavailable emails everyone who applied for a pet that was just adopted, except the adopter
+// and applicants whose application was approved or who cancelled it. Each user is emailed once, even
+// with several applications. These emails are sent right away, also in digest mode.
+func (s *NotificationService) HandlePetUnavailable(ctx context.Context, event consumer.PetUnavailableEvent) error {
+	log.Printf("Notification Service | Handling PetUnavailable event for PetID: %s, AdoptedBy: %s", event.PetID, event.AdoptedByUserID)
+
+	if s.adoptionServiceClient == nil {
+		return errors.New("no adoption service client configured to look up applicants")
+	}
+	applications, err := s.adoptionServiceClient.ListPetApplications(ctx, event.PetID)
 	if err != nil {
-		log.Printf("Notification Service | Error sending 'Status Updated' email to %s for AppID %s: %v", recipientEmail, event.ApplicationID, err)
-		return fmt.Errorf("failed to send status update email: %w", err)
+		log.Printf("Notification Service | Error listing applications for PetID %s: %v", event.PetID, err)
+		return fmt.Errorf("failed to list applications for unavailable pet: %w", err)
 	}
 
-	log.Printf("Notification Service | 'Status Updated' email sent successfully to %s for AppID %s.", recipientEmail, event.ApplicationID)
+	var recipients []string
+	notified := make(map[string]bool)
+	for _, app := range applications {
+		userID := app.GetUserId()
+		if userID == "" || userID == event.AdoptedByUserID || notified[userID] {
+			continue
+		}
+		switch app.GetStatus() {
+		case pbAdoption.ApplicationStatus_APPROVED, pbAdoption.ApplicationStatus_CANCELLED_BY_USER:
+			continue
+		}
+		notified[userID] = true
+		recipients = append(recipients, userID)
+	}
+	if len(recipients) == 0 {
+		log.Printf("Notification Service | No applicants to notify for unavailable PetID %s", event.PetID)
+		return nil
+	}
+
+	petName := event.PetName
+	if petName == "" {
+		petDetails, err := s.petServiceClient.GetPetDetails(ctx, event.PetID)
+		if err != nil {
+			log.Printf("Notification Service | Error fetching pet details for PetID %s: %v", event.PetID, err)
+			return fmt.Errorf("failed to fetch pet details for unavailable pet: %w", err)
+		}
+		petName = petDetails.GetName()
+	}
+
+	// Keep going when one applicant fails, so the others are still told.
+	var errs []error
+	for _, userID := range recipients {
+		userDetails, err := s.userServiceClient.GetUserDetails(ctx, userID)
+		if err != nil {
+			log.Printf("Notification Service | Error fetching user details for UserID %s: %v", userID, err)
+			errs = append(errs, fmt.Errorf("user %s: %w", userID, err))
+			continue
+		}
+		if userDetails.GetEmail() == "" {
+			log.Printf("Notification Service | User details or email not found for UserID %s", userID)
+			errs = append(errs, fmt.Errorf("user email not found for UserID %s", userID))
+			continue
+		}
+		subject, body := petUnavailableEmail(userDetails, event.PetID, petName)
+		if err := s.emailSender.SendEmail([]string{userDetails.GetEmail()}, subject, body, true); err != nil {
+			log.Printf("Notification Service | Error sending 'Pet Unavailable' email to %s for PetID %s: %v", userDetails.GetEmail(), event.PetID, err)
+			errs = append(errs, fmt.Errorf("user %s: %w", userID, err))
+		}
+	}
+
+	log.Printf("Notification Service | 'Pet Unavailable' emails sent to %d of %d applicants for PetID %s.", len(recipients)-len(errs), len(recipients), event.PetID)
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to notify %d applicants: %w", len(errs), errors.Join(errs...))
+	}
 	return nil
 }
 
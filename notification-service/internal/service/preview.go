@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+
+	pbPet "github.com/zhandarbeks/petstore-final-project/genprotos/pet"
+	pbUser "github.com/zhandarbeks/petstore-final-project/genprotos/user"
+)
+
+// previewApplicationID stands in for a real application in rendered previews.
+const previewApplicationID = "PREVIEW"
+
+// ErrUnknownEmailType is returned by Preview for an email type that has no template.
+var ErrUnknownEmailType = errors.New("unknown email type")
+
+// EmailPreview is a rendered email that was not sent.
+type EmailPreview struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"` // HTML
+}
+
+// Preview renders the email of the given type for a real user and pet without sending it.
+// status is used for status update emails (default "APPROVED").
+func (s *NotificationService) Preview(ctx context.Context, emailType, userID, petID, status string) (*EmailPreview, error) {
+	if emailType != EmailApplicationCreated && emailType != EmailApplicationStatusUpdated {
+		return nil, ErrUnknownEmailType
+	}
+
+	// Look up the user and pet at the same time.
+	var (
+		wg              sync.WaitGroup
+		user            *pbUser.User
+		pet             *pbPet.Pet
+		userErr, petErr error
+	)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		user, userErr = s.userServiceClient.GetUserDetails(ctx, userID)
+	}()
+	go func() {
+		defer wg.Done()
+		pet, petErr = s.petServiceClient.GetPetDetails(ctx, petID)
+	}()
+	wg.Wait()
+	if userErr != nil {
+		return nil, fmt.Errorf("failed to fetch user details for preview: %w", userErr)
+	}
+	if petErr != nil {
+		return nil, fmt.Errorf("failed to fetch pet details for preview: %w", petErr)
+	}
+
+	var subject, body string
+	if emailType == EmailApplicationCreated {
+		if status == "" {
+			status = "PENDING_REVIEW"
+		}
+		subject, body = applicationCreatedEmail(user, pet, previewApplicationID, status, s.maxDescriptionLength)
+	} else {
+		if status == "" {
+			status = "APPROVED"
+		}
+		subject, body = applicationStatusUpdatedEmail(user, pet, previewApplicationID, status, "")
+	}
+	return &EmailPreview{To: user.GetEmail(), Subject: subject, Body: body}, nil
+}
+
+// PreviewHandler serves GET /admin/email-previews?type=...&userId=...&petId=...[&status=...] and returns the
+// rendered EmailPreview as JSON. It requires the X-Admin-Token header; an empty token disables it.
+func PreviewHandler(svc *NotificationService, adminToken string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if adminToken == "" {
+			http.Error(w, "email previews are disabled", http.StatusForbidden)
+			return
+		}
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("X-Admin-Token")), []byte(adminToken)) != 1 {
+			http.Error(w, "invalid or missing admin token", http.StatusUnauthorized)
+			return
+		}
+
+		q := r.URL.Query()
+		if q.Get("userId") == "" || q.Get("petId") == "" {
+			http.Error(w, "userId and petId are required", http.StatusBadRequest)
+			return
+		}
+		preview, err := svc.Preview(r.Context(), q.Get("type"), q.Get("userId"), q.Get("petId"), q.Get("status"))
+		if err != nil {
+			if errors.Is(err, ErrUnknownEmailType) {
+				http.Error(w, fmt.Sprintf("type must be '%s' or '%s'", EmailApplicationCreated, EmailApplicationStatusUpdated), http.StatusBadRequest)
+				return
+			}
+			log.Printf("Notification Service | Error rendering email preview: %v", err)
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(preview); err != nil {
+			log.Printf("Notification Service | Error writing email preview: %v", err)
+		}
+	})
+}
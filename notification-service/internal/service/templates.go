@@ -0,0 +1,237 @@
+package service
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	pbPet "github.com/zhandarbeks/petstore-final-project/genprotos/pet"
+	pbUser "github.com/zhandarbeks/petstore-final-project/genprotos/user"
+	"github.com/zhandarbeks/petstore-final-project/notification-service/internal/digest"
+)
+
+// Email types that can be rendered, used by the preview endpoint.
+const (
+	EmailApplicationCreated         = "application_created"
+	EmailApplicationStatusUpdated   = "application_status_updated"
+	EmailApplicationPendingReminder = "application_pending_reminder"
+)
+
+// emailText holds the translatable parts of the application emails. Format verbs follow the
+// arguments used by applicationCreatedEmail and applicationStatusUpdatedEmail.
+type emailText struct {
+	createdSubject     string // pet name, application ID
+	createdBody        string // full name, application ID, pet name, pet ID, status
+	aboutPet           string // pet name, (truncated) pet description
+	updatedSubject     string // pet name, application ID
+	updatedBody        string // full name, application ID, pet name, pet ID, new status
+	reviewNotes        string // review notes
+	unavailableSubject string // pet name
+	unavailableBody    string // full name, pet name, pet ID
+	reminderSubject    string // pet name, application ID
+	reminderBody       string // full name, application ID, pet name, pet ID, days pending
+	approved           string
+	rejected           string
+	signature          string
+}
+
+// defaultLocale is used for users without a locale or with one that has no templates.
+const defaultLocale = "en"
+
+// emailTexts maps a user's locale to the email templates in that language.
+var emailTexts = map[string]emailText{
+	"en": {
+		createdSubject: "Adoption Application Received for %s (ID: %s)",
+		createdBody: `
+		<h1>Adoption Application Received!</h1>
+		<p>Dear %s,</p>
+		<p>Thank you for submitting your adoption application (ID: %s) for <strong>%s</strong> (Pet ID: %s).</p>
+		<p>Your application is currently in status: <strong>%s</strong>.</p>
+		<p>We will review your application and get back to you soon.</p>
+	`,
+		updatedSubject: "Update on Your Adoption Application for %s (ID: %s)",
+		updatedBody: `
+		<h1>Adoption Application Status Update!</h1>
+		<p>Dear %s,</p>
+		<p>There's an update on your adoption application (ID: %s) for <strong>%s</strong> (Pet ID: %s).</p>
+		<p>Your application status is now: <strong>%s</strong>.</p>
+	`,
+		unavailableSubject: "%s Has Found a Home",
+		unavailableBody: `
+		<h1>This Pet Has Been Adopted</h1>
+		<p>Dear %s,</p>
+		<p>You applied to adopt <strong>%s</strong> (Pet ID: %s), who has now been adopted by another family.</p>
+		<p>We are sorry it did not work out this time. Many other pets are still looking for a home.</p>
+	`,
+		reminderSubject: "Your Adoption Application for %s Is Still Under Review (ID: %s)",
+		reminderBody: `
+		<h1>Your Application Is Still Under Review</h1>
+		<p>Dear %s,</p>
+		<p>Your adoption application (ID: %s) for <strong>%s</strong> (Pet ID: %s) has been waiting for review for %d days.</p>
+		<p>We have reminded our team, and we will get back to you as soon as it has been reviewed.</p>
+	`,
+		aboutPet:    "<p>About %s: %s</p>",
+		reviewNotes: "<p>Reviewer's Notes: %s</p>",
+		approved:    "<p>Congratulations! Your application has been approved. We will contact you shortly with the next steps.</p>",
+		rejected:    "<p>We regret to inform you that your application was not approved at this time. Thank you for your interest.</p>",
+		signature:   "<p>Thank you,<br/>The PetStore Team</p>",
+	},
+	"ru": {
+		createdSubject: "Заявка на усыновление питомца %s получена (ID: %s)",
+		createdBody: `
+		<h1>Заявка на усыновление получена!</h1>
+		<p>Здравствуйте, %s!</p>
+		<p>Спасибо за вашу заявку на усыновление (ID: %s) питомца <strong>%s</strong> (ID питомца: %s).</p>
+		<p>Текущий статус заявки: <strong>%s</strong>.</p>
+		<p>Мы рассмотрим заявку и скоро свяжемся с вами.</p>
+	`,
+		updatedSubject: "Новости по вашей заявке на усыновление питомца %s (ID: %s)",
+		updatedBody: `
+		<h1>Статус заявки на усыновление изменён!</h1>
+		<p>Здравствуйте, %s!</p>
+		<p>По вашей заявке на усыновление (ID: %s) питомца <strong>%s</strong> (ID питомца: %s) есть новости.</p>
+		<p>Новый статус заявки: <strong>%s</strong>.</p>
+	`,
+		unavailableSubject: "%s обрёл новый дом",
+		unavailableBody: `
+		<h1>Этого питомца уже усыновили</h1>
+		<p>Здравствуйте, %s!</p>
+		<p>Вы подавали заявку на усыновление питомца <strong>%s</strong> (ID питомца: %s), но его усыновила другая семья.</p>
+		<p>Нам жаль, что в этот раз не получилось. Многие другие питомцы всё ещё ищут дом.</p>
+	`,
+		reminderSubject: "Ваша заявка на усыновление питомца %s всё ещё рассматривается (ID: %s)",
+		reminderBody: `
+		<h1>Ваша заявка всё ещё на рассмотрении</h1>
+		<p>Здравствуйте, %s!</p>
+		<p>Ваша заявка на усыновление (ID: %s) питомца <strong>%s</strong> (ID питомца: %s) ожидает рассмотрения уже %d дн.</p>
+		<p>Мы напомнили о ней нашей команде и свяжемся с вами, как только заявка будет рассмотрена.</p>
+	`,
+		aboutPet:    "<p>О питомце %s: %s</p>",
+		reviewNotes: "<p>Комментарий к заявке: %s</p>",
+		approved:    "<p>Поздравляем! Ваша заявка одобрена. Мы скоро свяжемся с вами, чтобы обсудить дальнейшие шаги.</p>",
+		rejected:    "<p>К сожалению, сейчас ваша заявка не одобрена. Спасибо за интерес к нашим питомцам.</p>",
+		signature:   "<p>Спасибо,<br/>Команда PetStore</p>",
+	},
+}
+
+// textFor returns the templates for a user's locale, matching on the language ("ru-RU" uses "ru")
+// and falling back to English.
+func textFor(locale string) emailText {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if i := strings.IndexAny(locale, "-_"); i >= 0 {
+		locale = locale[:i]
+	}
+	if text, ok := emailTexts[locale]; ok {
+		return text
+	}
+	return emailTexts[defaultLocale]
+}
+
+// DefaultMaxDescriptionLength caps, in characters, the pet descriptions embedded in emails when
+// no limit is configured.
+const DefaultMaxDescriptionLength = 300
+
+// ellipsis marks a description cut short for an email.
+const ellipsis = "…"
+
+// truncateText cuts s to at most maxRunes characters, ellipsis included, preferring to cut at a
+// word boundary. It is only for text embedded in emails; the API keeps returning the full text.
+// maxRunes <= 0 means no limit.
+func truncateText(s string, maxRunes int) string {
+	s = strings.TrimSpace(s)
+	if maxRunes <= 0 || utf8.RuneCountInString(s) <= maxRunes {
+		return s
+	}
+	cut := []rune(s)[:maxRunes-1] // Leave room for the ellipsis
+	// Drop the partial word, unless that would leave too little of the text.
+	for i := len(cut) - 1; i >= len(cut)/2; i-- {
+		if unicode.IsSpace(cut[i]) {
+			cut = cut[:i]
+			break
+		}
+	}
+	return strings.TrimRightFunc(string(cut), func(r rune) bool { return unicode.IsSpace(r) || unicode.IsPunct(r) }) + ellipsis
+}
+
+// applicationCreatedEmail renders the email sent when an adoption application is submitted,
+// in the user's locale. The pet's description is cut to maxDescription characters.
+func applicationCreatedEmail(user *pbUser.User, pet *pbPet.Pet, applicationID, status string, maxDescription int) (subject, body string) {
+	text := textFor(user.GetLocale())
+	subject = fmt.Sprintf(text.createdSubject, pet.GetName(), applicationID)
+	body = fmt.Sprintf(text.createdBody, user.GetFullName(), applicationID, pet.GetName(), pet.GetId(), status)
+	if description := truncateText(pet.GetDescription(), maxDescription); description != "" {
+		body += fmt.Sprintf(text.aboutPet, pet.GetName(), html.EscapeString(description))
+	}
+	body += text.signature
+	return subject, body
+}
+
+// applicationStatusUpdatedEmail renders the email sent when an application's status changes,
+// in the user's locale.
+func applicationStatusUpdatedEmail(user *pbUser.User, pet *pbPet.Pet, applicationID, newStatus, reviewNotes string) (subject, body string) {
+	text := textFor(user.GetLocale())
+	subject = fmt.Sprintf(text.updatedSubject, pet.GetName(), applicationID)
+	body = fmt.Sprintf(text.updatedBody, user.GetFullName(), applicationID, pet.GetName(), pet.GetId(), newStatus)
+
+	if reviewNotes != "" {
+		body += fmt.Sprintf(text.reviewNotes, reviewNotes)
+	}
+
+	if newStatus == "APPROVED" { // Assuming "APPROVED" is the string representation from domain/consumer event
+		body += text.approved
+	} else if newStatus == "REJECTED" {
+		body += text.rejected
+	}
+
+	body += text.signature
+	return subject, body
+}
+
+// applicationPendingReminderEmail renders the email telling an applicant that their application
+// is still waiting for review, in the user's locale.
+func applicationPendingReminderEmail(user *pbUser.User, pet *pbPet.Pet, applicationID string, daysPending int) (subject, body string) {
+	text := textFor(user.GetLocale())
+	subject = fmt.Sprintf(text.reminderSubject, pet.GetName(), applicationID)
+	body = fmt.Sprintf(text.reminderBody, user.GetFullName(), applicationID, pet.GetName(), pet.GetId(), daysPending)
+	body += text.signature
+	return subject, body
+}
+
+// petUnavailableEmail renders the email sent to an applicant when the pet was adopted by someone else,
+// in the user's locale.
+func petUnavailableEmail(user *pbUser.User, petID, petName string) (subject, body string) {
+	text := textFor(user.GetLocale())
+	subject = fmt.Sprintf(text.unavailableSubject, petName)
+	body = fmt.Sprintf(text.unavailableBody, user.GetFullName(), petName, petID)
+	body += text.signature
+	return subject, body
+}
+
+// digestEmail renders one summary email for several queued events, oldest first.
+func digestEmail(user *pbUser.User, items []digest.Item) (subject, body string) {
+	subject = fmt.Sprintf("Your adoption application updates (%d)", len(items))
+	body = fmt.Sprintf(`
+		<h1>Your Adoption Application Updates</h1>
+		<p>Dear %s,</p>
+		<p>Here is what happened with your adoption applications:</p>
+		<ul>
+	`, user.GetFullName())
+	for _, item := range items {
+		switch item.EventType {
+		case EmailApplicationCreated:
+			body += fmt.Sprintf("<li>Application %s for <strong>%s</strong> received, status: <strong>%s</strong>.</li>", item.ApplicationID, item.PetName, item.Status)
+		case EmailApplicationPendingReminder:
+			body += fmt.Sprintf("<li>Application %s for <strong>%s</strong> is still under review.</li>", item.ApplicationID, item.PetName)
+		default:
+			body += fmt.Sprintf("<li>Application %s for <strong>%s</strong> is now <strong>%s</strong>.", item.ApplicationID, item.PetName, item.Status)
+			if item.ReviewNotes != "" {
+				body += fmt.Sprintf(" Reviewer's Notes: %s", item.ReviewNotes)
+			}
+			body += "</li>"
+		}
+	}
+	body += "</ul><p>Thank you,<br/>The PetStore Team</p>"
+	return subject, body
+}
@@ -2,16 +2,31 @@ package main_test // Or a package name like 'notificationservicetest'
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	// Adjust these import paths to match your project's module path and structure
+	"github.com/zhandarbeks/petstore-final-project/internal/eventbus"
+	"github.com/zhandarbeks/petstore-final-project/internal/testutil"
 	"github.com/zhandarbeks/petstore-final-project/notification-service/internal/client"
 	"github.com/zhandarbeks/petstore-final-project/notification-service/internal/consumer"
+	"github.com/zhandarbeks/petstore-final-project/notification-service/internal/delivery"
+	"github.com/zhandarbeks/petstore-final-project/notification-service/internal/digest"
 	"github.com/zhandarbeks/petstore-final-project/notification-service/internal/email"
+	"github.com/zhandarbeks/petstore-final-project/notification-service/internal/metrics"
 	"github.com/zhandarbeks/petstore-final-project/notification-service/internal/service"
 
+	"github.com/nats-io/nats.go"
+
+	pbAdoption "github.com/zhandarbeks/petstore-final-project/genprotos/adoption" // For applications mock
 	pbPet "github.com/zhandarbeks/petstore-final-project/genprotos/pet"  // For Pet details mock
 	pbUser "github.com/zhandarbeks/petstore-final-project/genprotos/user" // For User details mock
 
@@ -46,6 +61,13 @@ func (m *MockEmailSender) SendEmail(to []string, subject, body string, isHTML bo
 	return nil // Default to success
 }
 
+// emailSenderFunc adapts a function to email.EmailSender.
+type emailSenderFunc func(to []string, subject, body string, isHTML bool) error
+
+func (f emailSenderFunc) SendEmail(to []string, subject, body string, isHTML bool) error {
+	return f(to, subject, body, isHTML)
+}
+
 // MockUserServiceClient is a mock for UserServiceClient
 type MockUserServiceClient struct {
 	GetUserDetailsFunc func(ctx context.Context, userID string) (*pbUser.User, error)
@@ -88,6 +110,21 @@ func (m *MockPetServiceClient) Close() error {
 	return nil
 }
 
+// MockAdoptionServiceClient is a mock for AdoptionServiceClient
+type MockAdoptionServiceClient struct {
+	ListPetApplicationsFunc func(ctx context.Context, petID string) ([]*pbAdoption.AdoptionApplication, error)
+}
+
+var _ client.AdoptionServiceClient = (*MockAdoptionServiceClient)(nil)
+
+func (m *MockAdoptionServiceClient) ListPetApplications(ctx context.Context, petID string) ([]*pbAdoption.AdoptionApplication, error) {
+	if m.ListPetApplicationsFunc != nil {
+		return m.ListPetApplicationsFunc(ctx, petID)
+	}
+	return nil, errors.New("ListPetApplicationsFunc not implemented")
+}
+func (m *MockAdoptionServiceClient) Close() error { return nil }
+
 // --- Test Functions ---
 
 func TestNotificationService_HandleAdoptionApplicationCreated_Success(t *testing.T) {
@@ -137,6 +174,45 @@ func TestNotificationService_HandleAdoptionApplicationCreated_Success(t *testing
 	// Add more assertions for subject and body content if needed
 }
 
+func TestNotificationService_HandleAdoptionApplicationCreated_TruncatesDescription(t *testing.T) {
+	description := "Buddy is a friendly golden retriever who loves long walks, playing fetch in the park and " +
+		"meeting new people. He is house-trained, good with children and gets along with cats."
+	pet := &pbPet.Pet{Id: "pet456", Name: "Buddy", Description: description}
+	mockEmailer := &MockEmailSender{}
+	mockUserClient := &MockUserServiceClient{GetUserDetailsFunc: func(ctx context.Context, userID string) (*pbUser.User, error) {
+		return &pbUser.User{Id: userID, Email: "testuser@example.com", FullName: "Test User"}, nil
+	}}
+	mockPetClient := &MockPetServiceClient{GetPetDetailsFunc: func(ctx context.Context, petID string) (*pbPet.Pet, error) {
+		return pet, nil
+	}}
+	notificationSvc := service.NewNotificationService(mockEmailer, mockUserClient, mockPetClient)
+	notificationSvc.SetMaxDescriptionLength(40)
+
+	event := consumer.AdoptionApplicationCreatedEvent{ApplicationID: "app789", UserID: "user123", PetID: "pet456", Status: "PENDING_REVIEW"}
+	if err := notificationSvc.HandleAdoptionApplicationCreated(context.Background(), event); err != nil {
+		t.Fatalf("HandleAdoptionApplicationCreated() error = %v", err)
+	}
+
+	if !strings.Contains(mockEmailer.LastBody, "Buddy is a friendly golden retriever…") {
+		t.Errorf("email body does not contain the truncated description:\n%s", mockEmailer.LastBody)
+	}
+	if strings.Contains(mockEmailer.LastBody, "long walks") {
+		t.Errorf("email body contains the description past the limit:\n%s", mockEmailer.LastBody)
+	}
+	if pet.GetDescription() != description {
+		t.Errorf("pet description = %q, want it left intact", pet.GetDescription())
+	}
+
+	// Without a limit the whole description is emailed.
+	notificationSvc.SetMaxDescriptionLength(0)
+	if err := notificationSvc.HandleAdoptionApplicationCreated(context.Background(), event); err != nil {
+		t.Fatalf("HandleAdoptionApplicationCreated() error = %v", err)
+	}
+	if !strings.Contains(mockEmailer.LastBody, description) {
+		t.Errorf("email body without a limit does not contain the full description:\n%s", mockEmailer.LastBody)
+	}
+}
+
 func TestNotificationService_HandleAdoptionApplicationStatusUpdated_Approved(t *testing.T) {
 	mockEmailer := &MockEmailSender{}
 	mockUserClient := &MockUserServiceClient{}
@@ -172,6 +248,83 @@ func TestNotificationService_HandleAdoptionApplicationStatusUpdated_Approved(t *
 	// Add more specific assertions for email content based on "APPROVED" status
 }
 
+func TestNotificationService_HandleApplicationPendingReminder(t *testing.T) {
+	mockEmailer := &MockEmailSender{}
+	mockUserClient := &MockUserServiceClient{
+		GetUserDetailsFunc: func(ctx context.Context, userID string) (*pbUser.User, error) {
+			return &pbUser.User{Id: "user123", Email: "testuser@example.com", FullName: "Test User"}, nil
+		},
+	}
+	mockPetClient := &MockPetServiceClient{
+		GetPetDetailsFunc: func(ctx context.Context, petID string) (*pbPet.Pet, error) {
+			return &pbPet.Pet{Id: "pet456", Name: "Buddy"}, nil
+		},
+	}
+	notificationSvc := service.NewNotificationService(mockEmailer, mockUserClient, mockPetClient)
+
+	event := consumer.AdoptionApplicationPendingReminderEvent{
+		EventType:     "AdoptionApplicationPendingReminder",
+		ApplicationID: "app789",
+		UserID:        "user123",
+		PetID:         "pet456",
+		Status:        "PENDING_REVIEW",
+		PendingSince:  time.Now().Add(-4*24*time.Hour - time.Hour),
+	}
+	if err := notificationSvc.HandleApplicationPendingReminder(context.Background(), event); err != nil {
+		t.Fatalf("HandleApplicationPendingReminder() error = %v", err)
+	}
+	if len(mockEmailer.LastTo) != 1 || mockEmailer.LastTo[0] != "testuser@example.com" {
+		t.Errorf("Email sent to %v, want [testuser@example.com]", mockEmailer.LastTo)
+	}
+	if !strings.Contains(mockEmailer.LastSubject, "Still Under Review") || !strings.Contains(mockEmailer.LastBody, "for 4 days") {
+		t.Errorf("reminder email = %q / %q, want the still-under-review text with 4 days pending", mockEmailer.LastSubject, mockEmailer.LastBody)
+	}
+}
+
+func TestNotificationService_HandlePetUnavailable_EmailsUnapprovedApplicants(t *testing.T) {
+	mockAdoptionClient := &MockAdoptionServiceClient{
+		ListPetApplicationsFunc: func(ctx context.Context, petID string) ([]*pbAdoption.AdoptionApplication, error) {
+			if petID != "pet456" {
+				return nil, errors.New("unexpected pet")
+			}
+			return []*pbAdoption.AdoptionApplication{
+				{Id: "app1", UserId: "adopter", Status: pbAdoption.ApplicationStatus_APPROVED},
+				{Id: "app2", UserId: "pending", Status: pbAdoption.ApplicationStatus_PENDING_REVIEW},
+				{Id: "app3", UserId: "rejected", Status: pbAdoption.ApplicationStatus_REJECTED},
+				{Id: "app4", UserId: "cancelled", Status: pbAdoption.ApplicationStatus_CANCELLED_BY_USER},
+				{Id: "app5", UserId: "pending", Status: pbAdoption.ApplicationStatus_PENDING_REVIEW}, // Applied twice
+				{Id: "app6", UserId: "approved-elsewhere", Status: pbAdoption.ApplicationStatus_APPROVED},
+			}, nil
+		},
+	}
+	mockUserClient := &MockUserServiceClient{
+		GetUserDetailsFunc: func(ctx context.Context, userID string) (*pbUser.User, error) {
+			return &pbUser.User{Id: userID, Email: userID + "@example.com", FullName: "User " + userID}, nil
+		},
+	}
+	var sentTo []string
+	sender := emailSenderFunc(func(to []string, subject, body string, isHTML bool) error {
+		sentTo = append(sentTo, to...)
+		if !strings.Contains(subject, "Buddy") {
+			t.Errorf("subject %q does not name the pet", subject)
+		}
+		return nil
+	})
+
+	notificationSvc := service.NewNotificationService(sender, mockUserClient, &MockPetServiceClient{})
+	notificationSvc.SetAdoptionServiceClient(mockAdoptionClient)
+
+	event := consumer.PetUnavailableEvent{PetID: "pet456", PetName: "Buddy", AdoptedByUserID: "adopter", Status: "ADOPTED"}
+	if err := notificationSvc.HandlePetUnavailable(context.Background(), event); err != nil {
+		t.Fatalf("HandlePetUnavailable() error = %v", err)
+	}
+
+	want := []string{"pending@example.com", "rejected@example.com"}
+	if !reflect.DeepEqual(sentTo, want) {
+		t.Errorf("HandlePetUnavailable() emailed %v, want %v", sentTo, want)
+	}
+}
+
 func TestNotificationService_HandleAdoptionApplicationCreated_UserFetchFail(t *testing.T) {
 	mockEmailer := &MockEmailSender{}
 	mockUserClient := &MockUserServiceClient{}
@@ -205,6 +358,605 @@ func TestNotificationService_HandleAdoptionApplicationCreated_UserFetchFail(t *t
 }
 
 
+func TestPlanSubscriptions_ConfiguredSubjects(t *testing.T) {
+	routes := (&consumer.NATSConsumer{}).Routes()
+
+	subs, err := consumer.PlanSubscriptions([]string{consumer.SubjectApplicationStatusUpdated, consumer.SubjectApplicationCreated}, routes, 5)
+	if err != nil {
+		t.Fatalf("PlanSubscriptions() error = %v", err)
+	}
+	if len(subs) != 2 || subs[0].Subject != consumer.SubjectApplicationStatusUpdated || subs[1].Subject != consumer.SubjectApplicationCreated {
+		t.Fatalf("PlanSubscriptions() = %+v, want the configured subjects in order", subs)
+	}
+	for _, s := range subs {
+		if s.Handler == nil {
+			t.Errorf("PlanSubscriptions() subject %q has no handler", s.Subject)
+		}
+	}
+
+	// Only the configured subjects are subscribed.
+	subs, err = consumer.PlanSubscriptions([]string{consumer.SubjectApplicationCreated}, routes, 5)
+	if err != nil || len(subs) != 1 || subs[0].Subject != consumer.SubjectApplicationCreated {
+		t.Errorf("PlanSubscriptions() = %+v, %v, want only %q", subs, err, consumer.SubjectApplicationCreated)
+	}
+
+	// An empty list falls back to the defaults.
+	subs, err = consumer.PlanSubscriptions(nil, routes, 0)
+	if err != nil || len(subs) != len(consumer.DefaultSubjects) {
+		t.Errorf("PlanSubscriptions(nil) = %+v, %v, want the default subjects", subs, err)
+	}
+
+	invalid := []struct {
+		name        string
+		subjects    []string
+		maxSubjects int
+	}{
+		{"unknown subject", []string{"adoption.application.deleted"}, 5},
+		{"duplicate subject", []string{consumer.SubjectApplicationCreated, consumer.SubjectApplicationCreated}, 5},
+		{"over the limit", []string{consumer.SubjectApplicationCreated, consumer.SubjectApplicationStatusUpdated}, 1},
+	}
+	for _, tt := range invalid {
+		if _, err := consumer.PlanSubscriptions(tt.subjects, routes, tt.maxSubjects); err == nil {
+			t.Errorf("PlanSubscriptions() with %s: expected an error", tt.name)
+		}
+	}
+}
+
+// blockingEventHandler blocks in HandleAdoptionApplicationCreated until its context is cancelled.
+type blockingEventHandler struct {
+	started   chan struct{}
+	cancelled chan error
+}
+
+func (h *blockingEventHandler) HandleAdoptionApplicationCreated(ctx context.Context, event consumer.AdoptionApplicationCreatedEvent) error {
+	close(h.started)
+	<-ctx.Done()
+	h.cancelled <- ctx.Err()
+	return ctx.Err()
+}
+
+func (h *blockingEventHandler) HandleAdoptionApplicationStatusUpdated(ctx context.Context, event consumer.AdoptionApplicationStatusUpdatedEvent) error {
+	return nil
+}
+
+func (h *blockingEventHandler) HandleApplicationPendingReminder(ctx context.Context, event consumer.AdoptionApplicationPendingReminderEvent) error {
+	return nil
+}
+
+func (h *blockingEventHandler) HandlePetUnavailable(ctx context.Context, event consumer.PetUnavailableEvent) error {
+	return nil
+}
+
+func TestNATSConsumer_Subscriptions_MatchPublisherSubjects(t *testing.T) {
+	namespace, err := eventbus.NewNamespace(" staging.eu. ")
+	if err != nil {
+		t.Fatalf("NewNamespace() error = %v", err)
+	}
+	natsConsumer := consumer.NewNATSConsumerFromConn(nil, &blockingEventHandler{}, nil, 0, 0, namespace)
+
+	subs, err := natsConsumer.Subscriptions()
+	if err != nil {
+		t.Fatalf("Subscriptions() error = %v", err)
+	}
+	got := make([]string, len(subs))
+	for i, sub := range subs {
+		got[i] = sub.Subject
+	}
+	// The adoption-service and pet-service publishers send to namespace.Subject(eventbus.Subject...).
+	want := []string{
+		namespace.Subject(eventbus.SubjectApplicationCreated),
+		namespace.Subject(eventbus.SubjectApplicationStatusUpdated),
+		namespace.Subject(eventbus.SubjectApplicationPendingReminder),
+		namespace.Subject(eventbus.SubjectPetUnavailable),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("subscribed subjects = %v, want %v", got, want)
+	}
+	if want[0] != "staging.eu.adoption.application.created" {
+		t.Errorf("prefixed subject = %q, want %q", want[0], "staging.eu.adoption.application.created")
+	}
+	if stream := namespace.Stream(eventbus.StreamAdoptions); stream != "STAGING_EU_ADOPTIONS" {
+		t.Errorf("prefixed stream = %q, want %q", stream, "STAGING_EU_ADOPTIONS")
+	}
+
+	unprefixed, _ := consumer.NewNATSConsumerFromConn(nil, &blockingEventHandler{}, nil, 0, 0, eventbus.Namespace{}).Subscriptions()
+	if len(unprefixed) == 0 || unprefixed[0].Subject != eventbus.SubjectApplicationCreated {
+		t.Errorf("subscriptions without prefix = %v, want the plain subjects", unprefixed)
+	}
+	for _, prefix := range []string{"staging.*", "prod..eu", "my env"} {
+		if _, err := eventbus.NewNamespace(prefix); err == nil {
+			t.Errorf("NewNamespace(%q) error = nil, want an error", prefix)
+		}
+	}
+}
+
+func TestNATSConsumer_Close_CancelsHandlersAfterDrainTimeout(t *testing.T) {
+	const drainTimeout = 100 * time.Millisecond
+	h := &blockingEventHandler{started: make(chan struct{}), cancelled: make(chan error, 1)}
+	natsConsumer := consumer.NewNATSConsumerFromConn(nil, h, nil, 0, drainTimeout, eventbus.Namespace{})
+
+	data, _ := json.Marshal(consumer.AdoptionApplicationCreatedEvent{ApplicationID: "app789", UserID: "user123", PetID: "pet456"})
+	go natsConsumer.Routes()[consumer.SubjectApplicationCreated](&nats.Msg{Subject: consumer.SubjectApplicationCreated, Data: data})
+	<-h.started
+
+	start := time.Now()
+	natsConsumer.Close()
+	elapsed := time.Since(start)
+
+	select {
+	case err := <-h.cancelled:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("handler context error = %v, want context.Canceled", err)
+		}
+	default:
+		t.Fatal("handler was not cancelled by Close")
+	}
+	if elapsed < drainTimeout || elapsed > drainTimeout+time.Second {
+		t.Errorf("Close() took %s, want about the %s drain timeout", elapsed, drainTimeout)
+	}
+}
+
+// recordingEventHandler records the events it handles, in order.
+type recordingEventHandler struct {
+	mu      sync.Mutex
+	handled []string
+}
+
+func (h *recordingEventHandler) record(entry string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.handled = append(h.handled, entry)
+}
+
+func (h *recordingEventHandler) Handled() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]string(nil), h.handled...)
+}
+
+func (h *recordingEventHandler) HandleAdoptionApplicationCreated(ctx context.Context, event consumer.AdoptionApplicationCreatedEvent) error {
+	h.record("created " + event.ApplicationID)
+	return nil
+}
+
+func (h *recordingEventHandler) HandleAdoptionApplicationStatusUpdated(ctx context.Context, event consumer.AdoptionApplicationStatusUpdatedEvent) error {
+	h.record(event.NewStatus + " " + event.ApplicationID)
+	return nil
+}
+
+func (h *recordingEventHandler) HandleApplicationPendingReminder(ctx context.Context, event consumer.AdoptionApplicationPendingReminderEvent) error {
+	return nil
+}
+
+func (h *recordingEventHandler) HandlePetUnavailable(ctx context.Context, event consumer.PetUnavailableEvent) error {
+	return nil
+}
+
+func TestNATSConsumer_HandlesApplicationEventsInOrder(t *testing.T) {
+	h := &recordingEventHandler{}
+	natsConsumer := consumer.NewNATSConsumerFromConn(nil, h, nil, 0, 0, eventbus.Namespace{})
+	natsConsumer.SetOrderingWait(200 * time.Millisecond)
+	routes := natsConsumer.Routes()
+	deliver := func(subject string, event interface{}) {
+		data, _ := json.Marshal(event)
+		routes[subject](&nats.Msg{Subject: subject, Data: data})
+	}
+	now := time.Now().UTC()
+
+	// The approval of a new application overtakes its created event...
+	deliver(consumer.SubjectApplicationStatusUpdated, consumer.AdoptionApplicationStatusUpdatedEvent{ApplicationID: "app1", NewStatus: "APPROVED", AppliedAt: now, UpdatedAt: now})
+	if got := h.Handled(); len(got) != 0 {
+		t.Fatalf("handled before the created event: %v, want nothing", got)
+	}
+	// ...and is handled right after it.
+	deliver(consumer.SubjectApplicationCreated, consumer.AdoptionApplicationCreatedEvent{ApplicationID: "app1", Status: "PENDING_REVIEW", AppliedAt: now})
+	if got, want := h.Handled(), []string{"created app1", "APPROVED app1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("handled = %v, want %v", got, want)
+	}
+
+	// Later updates of that application, and updates of old applications, are not held.
+	deliver(consumer.SubjectApplicationStatusUpdated, consumer.AdoptionApplicationStatusUpdatedEvent{ApplicationID: "app1", NewStatus: "CANCELLED_BY_USER", AppliedAt: now})
+	deliver(consumer.SubjectApplicationStatusUpdated, consumer.AdoptionApplicationStatusUpdatedEvent{ApplicationID: "old", NewStatus: "REJECTED", AppliedAt: now.Add(-24 * time.Hour)})
+	if got, want := h.Handled(), []string{"created app1", "APPROVED app1", "CANCELLED_BY_USER app1", "REJECTED old"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("handled = %v, want %v", got, want)
+	}
+
+	// If the created event never arrives, the held update is handled after the wait.
+	deliver(consumer.SubjectApplicationStatusUpdated, consumer.AdoptionApplicationStatusUpdatedEvent{ApplicationID: "app2", NewStatus: "APPROVED", AppliedAt: time.Now().UTC()})
+	deadline := time.Now().Add(2 * time.Second)
+	for len(h.Handled()) < 5 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := h.Handled(); len(got) != 5 || got[4] != "APPROVED app2" {
+		t.Errorf("handled = %v, want APPROVED app2 released after the wait", got)
+	}
+}
+
+func TestNATSConsumer_RecordsEventLatency(t *testing.T) {
+	mockUserClient := &MockUserServiceClient{GetUserDetailsFunc: func(ctx context.Context, userID string) (*pbUser.User, error) {
+		return &pbUser.User{Id: userID, Email: "test@example.com", FullName: "Test User"}, nil
+	}}
+	mockPetClient := &MockPetServiceClient{GetPetDetailsFunc: func(ctx context.Context, petID string) (*pbPet.Pet, error) {
+		return &pbPet.Pet{Id: petID, Name: "Buddy"}, nil
+	}}
+	notificationSvc := service.NewNotificationService(&MockEmailSender{}, mockUserClient, mockPetClient)
+	natsConsumer := consumer.NewNATSConsumerFromConn(nil, notificationSvc, nil, 0, 0, eventbus.Namespace{})
+
+	// The event was published two seconds ago.
+	publishedAt := time.Now().Add(-2 * time.Second)
+	data, _ := json.Marshal(consumer.AdoptionApplicationCreatedEvent{ApplicationID: "app789", UserID: "user123", PetID: "pet456", Status: "PENDING", AppliedAt: publishedAt, PublishedAt: publishedAt})
+	natsConsumer.Routes()[consumer.SubjectApplicationCreated](&nats.Msg{Subject: consumer.SubjectApplicationCreated, Data: data})
+
+	latency := natsConsumer.EventLatency()
+	if latency.Count() != 1 {
+		t.Fatalf("latency observations = %d, want 1", latency.Count())
+	}
+	if sum := latency.Sum(); sum < 2 || sum > 10 {
+		t.Errorf("observed latency = %.3fs, want about 2s", sum)
+	}
+
+	rec := httptest.NewRecorder()
+	metrics.Handler(latency).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if !strings.Contains(rec.Body.String(), consumer.EventLatencyMetric+"_count 1") {
+		t.Errorf("/metrics body missing the latency count:\n%s", rec.Body.String())
+	}
+}
+
+func TestEndToEnd_ApplicationCreatedEventIsEmailed(t *testing.T) {
+	consumerConn, natsURL := testutil.ConnectNATS(t)
+	smtpServer := testutil.StartSMTP(t)
+
+	sender, err := email.NewSMTPEmailSender(smtpServer.Host, smtpServer.Port, "notifier", "secret", "noreply@petstore.test")
+	if err != nil {
+		t.Fatalf("NewSMTPEmailSender() error = %v", err)
+	}
+	mockUserClient := &MockUserServiceClient{GetUserDetailsFunc: func(ctx context.Context, userID string) (*pbUser.User, error) {
+		return &pbUser.User{Id: userID, Email: "applicant@example.com", FullName: "Ann Applicant"}, nil
+	}}
+	mockPetClient := &MockPetServiceClient{GetPetDetailsFunc: func(ctx context.Context, petID string) (*pbPet.Pet, error) {
+		return &pbPet.Pet{Id: petID, Name: "Buddy"}, nil
+	}}
+	notificationSvc := service.NewNotificationService(sender, mockUserClient, mockPetClient)
+	natsConsumer := consumer.NewNATSConsumerFromConn(consumerConn, notificationSvc, nil, 0, 0, eventbus.Namespace{})
+	if err := natsConsumer.StartSubscribers(); err != nil {
+		t.Fatalf("StartSubscribers() error = %v", err)
+	}
+	defer natsConsumer.Close()
+	if err := consumerConn.Flush(); err != nil { // Make sure the subscriptions are in place before publishing
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	// Publish the event the way adoption-service does after creating an application.
+	publisherConn, err := nats.Connect(natsURL)
+	if err != nil {
+		t.Fatalf("could not connect publisher: %v", err)
+	}
+	defer publisherConn.Close()
+	now := time.Now().UTC()
+	data, _ := json.Marshal(consumer.AdoptionApplicationCreatedEvent{
+		EventType: "AdoptionApplicationCreated", ApplicationID: "app1", UserID: "user1", PetID: "pet1",
+		Status: "PENDING_REVIEW", AppliedAt: now, PublishedAt: now,
+	})
+	if err := publisherConn.Publish(eventbus.SubjectApplicationCreated, data); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	emails := smtpServer.WaitForEmails(t, 1, 5*time.Second)
+	if got := emails[0].To; len(got) != 1 || got[0] != "applicant@example.com" {
+		t.Errorf("email recipients = %v, want [applicant@example.com]", got)
+	}
+	if !strings.Contains(emails[0].Data, "Buddy") {
+		t.Errorf("email does not mention the pet:\n%s", emails[0].Data)
+	}
+}
+
+func TestSuppressingSender_SkipsBouncedRecipient(t *testing.T) {
+	tracker := delivery.NewTracker(true)
+	webhook := delivery.WebhookHandler(tracker, "hook-token")
+
+	// The provider reports a bounce for one address.
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/email-delivery", strings.NewReader(`{"recipient":"Bounced@Example.com","status":"bounced","reason":"mailbox does not exist"}`))
+	req.Header.Set("X-Webhook-Token", "hook-token")
+	rec := httptest.NewRecorder()
+	webhook.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("webhook status = %d, want %d. Body: %s", rec.Code, http.StatusNoContent, rec.Body.String())
+	}
+	if history := tracker.History("bounced@example.com"); len(history) != 1 || history[0].Status != delivery.StatusBounced {
+		t.Errorf("History() = %+v, want one bounced event", history)
+	}
+
+	mockEmailer := &MockEmailSender{}
+	sender := email.NewSuppressingSender(mockEmailer, tracker.IsSuppressed)
+
+	if err := sender.SendEmail([]string{"bounced@example.com"}, "Hello", "body", true); err != nil {
+		t.Fatalf("SendEmail() error = %v", err)
+	}
+	if mockEmailer.SendEmailCalled {
+		t.Error("SendEmail() should skip a suppressed recipient")
+	}
+
+	if err := sender.SendEmail([]string{"bounced@example.com", "ok@example.com"}, "Hello", "body", true); err != nil {
+		t.Fatalf("SendEmail() error = %v", err)
+	}
+	if len(mockEmailer.LastTo) != 1 || mockEmailer.LastTo[0] != "ok@example.com" {
+		t.Errorf("SendEmail() sent to %v, want only ok@example.com", mockEmailer.LastTo)
+	}
+
+	// Callbacks without the shared token are rejected.
+	req = httptest.NewRequest(http.MethodPost, "/webhooks/email-delivery", strings.NewReader(`{"recipient":"ok@example.com","status":"complained"}`))
+	rec = httptest.NewRecorder()
+	webhook.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized || tracker.IsSuppressed("ok@example.com") {
+		t.Errorf("webhook without token: status = %d, suppressed = %v; want 401 and not suppressed", rec.Code, tracker.IsSuppressed("ok@example.com"))
+	}
+}
+
+// memoryWindowCounter is an in-memory email.WindowCounter; the window never rolls over within a test.
+type memoryWindowCounter struct {
+	counts map[string]int64
+}
+
+func (c *memoryWindowCounter) Incr(ctx context.Context, key string, window time.Duration) (int64, error) {
+	c.counts[key]++
+	return c.counts[key], nil
+}
+
+func TestRateLimitedSender_CapsRapidEventsPerRecipient(t *testing.T) {
+	const limit = 2
+	sends := 0
+	mockEmailer := &MockEmailSender{SendEmailFunc: func(to []string, subject, body string, isHTML bool) error {
+		sends++
+		return nil
+	}}
+	mockUserClient := &MockUserServiceClient{GetUserDetailsFunc: func(ctx context.Context, userID string) (*pbUser.User, error) {
+		return &pbUser.User{Id: userID, Email: "Busy@Example.com", FullName: "Busy User"}, nil
+	}}
+	mockPetClient := &MockPetServiceClient{GetPetDetailsFunc: func(ctx context.Context, petID string) (*pbPet.Pet, error) {
+		return &pbPet.Pet{Id: petID, Name: "Buddy"}, nil
+	}}
+	counter := &memoryWindowCounter{counts: make(map[string]int64)}
+	sender := email.NewRateLimitedSender(mockEmailer, counter, limit, 10*time.Minute)
+	notificationSvc := service.NewNotificationService(sender, mockUserClient, mockPetClient)
+
+	for i, status := range []string{"PENDING_REVIEW", "APPROVED", "REJECTED", "APPROVED", "CANCELLED_BY_USER"} {
+		event := consumer.AdoptionApplicationStatusUpdatedEvent{ApplicationID: "app789", UserID: "user123", PetID: "pet456", NewStatus: status}
+		if err := notificationSvc.HandleAdoptionApplicationStatusUpdated(context.Background(), event); err != nil {
+			t.Fatalf("event %d: HandleAdoptionApplicationStatusUpdated() error = %v", i, err)
+		}
+	}
+	if sends != limit {
+		t.Errorf("emails sent = %d, want %d", sends, limit)
+	}
+
+	// Other recipients have their own budget.
+	if err := sender.SendEmail([]string{"other@example.com"}, "Hello", "body", true); err != nil {
+		t.Fatalf("SendEmail() error = %v", err)
+	}
+	if sends != limit+1 {
+		t.Errorf("emails sent after other recipient = %d, want %d", sends, limit+1)
+	}
+}
+
+// memoryDigestStore is an in-memory digest.Store.
+type memoryDigestStore struct {
+	items   map[string][]digest.Item
+	firstAt map[string]time.Time
+}
+
+var _ digest.Store = (*memoryDigestStore)(nil)
+
+func newMemoryDigestStore() *memoryDigestStore {
+	return &memoryDigestStore{items: make(map[string][]digest.Item), firstAt: make(map[string]time.Time)}
+}
+
+func (s *memoryDigestStore) Add(ctx context.Context, userID string, item digest.Item) error {
+	if _, ok := s.firstAt[userID]; !ok {
+		s.firstAt[userID] = item.QueuedAt
+	}
+	s.items[userID] = append(s.items[userID], item)
+	return nil
+}
+
+func (s *memoryDigestStore) DueUsers(ctx context.Context, cutoff time.Time) ([]string, error) {
+	var due []string
+	for userID, first := range s.firstAt {
+		if !first.After(cutoff) {
+			due = append(due, userID)
+		}
+	}
+	return due, nil
+}
+
+func (s *memoryDigestStore) Take(ctx context.Context, userID string) ([]digest.Item, error) {
+	items := s.items[userID]
+	delete(s.items, userID)
+	delete(s.firstAt, userID)
+	return items, nil
+}
+
+func TestConcurrencyLimitedSender_BoundsInFlightSends(t *testing.T) {
+	const limit = 3
+	var inFlight, maxInFlight, sent atomic.Int32
+	// MockEmailSender records its calls without locking, so the concurrent sends use a plain func.
+	slowSender := emailSenderFunc(func(to []string, subject, body string, isHTML bool) error {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			seen := maxInFlight.Load()
+			if current <= seen || maxInFlight.CompareAndSwap(seen, current) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		sent.Add(1)
+		return nil
+	})
+	sender := email.NewConcurrencyLimitedSender(slowSender, limit)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := sender.SendEmail([]string{"user@example.com"}, "Update", "body", true); err != nil {
+				t.Errorf("SendEmail() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := maxInFlight.Load(); got > limit {
+		t.Errorf("max concurrent sends = %d, want at most %d", got, limit)
+	}
+	if got := sent.Load(); got != 20 {
+		t.Errorf("sent %d emails, want 20 (queued sends must not be dropped)", got)
+	}
+}
+
+func TestNotificationService_DigestMode_BatchesEventsInWindow(t *testing.T) {
+	sends := 0
+	mockEmailer := &MockEmailSender{SendEmailFunc: func(to []string, subject, body string, isHTML bool) error {
+		sends++
+		return nil
+	}}
+	mockUserClient := &MockUserServiceClient{GetUserDetailsFunc: func(ctx context.Context, userID string) (*pbUser.User, error) {
+		return &pbUser.User{Id: userID, Email: "testuser@example.com", FullName: "Test User"}, nil
+	}}
+	mockPetClient := &MockPetServiceClient{GetPetDetailsFunc: func(ctx context.Context, petID string) (*pbPet.Pet, error) {
+		return &pbPet.Pet{Id: petID, Name: map[string]string{"pet456": "Buddy", "pet789": "Misty"}[petID]}, nil
+	}}
+	notificationSvc := service.NewNotificationService(mockEmailer, mockUserClient, mockPetClient)
+	notificationSvc.EnableDigest(newMemoryDigestStore())
+
+	start := time.Now()
+	created := consumer.AdoptionApplicationCreatedEvent{ApplicationID: "app1", UserID: "user123", PetID: "pet456", Status: "PENDING_REVIEW"}
+	if err := notificationSvc.HandleAdoptionApplicationCreated(context.Background(), created); err != nil {
+		t.Fatalf("HandleAdoptionApplicationCreated() error = %v", err)
+	}
+	updated := consumer.AdoptionApplicationStatusUpdatedEvent{ApplicationID: "app2", UserID: "user123", PetID: "pet789", NewStatus: "APPROVED"}
+	if err := notificationSvc.HandleAdoptionApplicationStatusUpdated(context.Background(), updated); err != nil {
+		t.Fatalf("HandleAdoptionApplicationStatusUpdated() error = %v", err)
+	}
+	if sends != 0 {
+		t.Fatalf("emails sent before flush = %d, want 0", sends)
+	}
+
+	// Window not over yet: the first event is newer than the cutoff.
+	if n, err := notificationSvc.FlushDigests(context.Background(), start.Add(-time.Minute)); err != nil || n != 0 {
+		t.Fatalf("FlushDigests() inside window = (%d, %v), want (0, nil)", n, err)
+	}
+
+	n, err := notificationSvc.FlushDigests(context.Background(), time.Now())
+	if err != nil || n != 1 {
+		t.Fatalf("FlushDigests() after window = (%d, %v), want (1, nil)", n, err)
+	}
+	if sends != 1 {
+		t.Errorf("emails sent = %d, want 1 digest", sends)
+	}
+	if len(mockEmailer.LastTo) != 1 || mockEmailer.LastTo[0] != "testuser@example.com" {
+		t.Errorf("digest sent to %v, want testuser@example.com", mockEmailer.LastTo)
+	}
+	for _, want := range []string{"app1", "Buddy", "PENDING_REVIEW", "app2", "Misty", "APPROVED"} {
+		if !strings.Contains(mockEmailer.LastBody, want) {
+			t.Errorf("digest body missing %q:\n%s", want, mockEmailer.LastBody)
+		}
+	}
+
+	// Everything was flushed.
+	if n, _ := notificationSvc.FlushDigests(context.Background(), time.Now()); n != 0 {
+		t.Errorf("second FlushDigests() sent %d digests, want 0", n)
+	}
+}
+
+func TestNotificationService_HandleAdoptionApplicationStatusUpdated_UsesUserLocale(t *testing.T) {
+	locales := map[string]string{"user-ru": "ru-RU", "user-en": "", "user-xx": "xx"}
+	mockEmailer := &MockEmailSender{}
+	mockUserClient := &MockUserServiceClient{
+		GetUserDetailsFunc: func(ctx context.Context, userID string) (*pbUser.User, error) {
+			return &pbUser.User{Id: userID, Email: userID + "@example.com", FullName: "Test User", Locale: locales[userID]}, nil
+		},
+	}
+	mockPetClient := &MockPetServiceClient{
+		GetPetDetailsFunc: func(ctx context.Context, petID string) (*pbPet.Pet, error) {
+			return &pbPet.Pet{Id: petID, Name: "Buddy"}, nil
+		},
+	}
+	notificationSvc := service.NewNotificationService(mockEmailer, mockUserClient, mockPetClient)
+
+	tests := []struct {
+		userID      string
+		wantSubject string
+		wantBody    string
+	}{
+		{"user-ru", "Новости по вашей заявке", "Поздравляем!"},
+		{"user-en", "Update on Your Adoption Application", "Congratulations!"},
+		{"user-xx", "Update on Your Adoption Application", "Congratulations!"}, // unsupported locale falls back to English
+	}
+	for _, tt := range tests {
+		event := consumer.AdoptionApplicationStatusUpdatedEvent{
+			EventType:     "AdoptionApplicationStatusUpdated",
+			ApplicationID: "app789",
+			UserID:        tt.userID,
+			PetID:         "pet456",
+			NewStatus:     "APPROVED",
+			UpdatedAt:     time.Now(),
+		}
+		if err := notificationSvc.HandleAdoptionApplicationStatusUpdated(context.Background(), event); err != nil {
+			t.Fatalf("HandleAdoptionApplicationStatusUpdated(%s) error = %v", tt.userID, err)
+		}
+		if !strings.Contains(mockEmailer.LastSubject, tt.wantSubject) || !strings.Contains(mockEmailer.LastBody, tt.wantBody) {
+			t.Errorf("%s: got subject %q and body %q, want them to contain %q and %q", tt.userID, mockEmailer.LastSubject, mockEmailer.LastBody, tt.wantSubject, tt.wantBody)
+		}
+	}
+}
+
+func TestNotificationService_PreviewHandler_RendersUserAndPet(t *testing.T) {
+	mockEmailer := &MockEmailSender{}
+	mockUserClient := &MockUserServiceClient{
+		GetUserDetailsFunc: func(ctx context.Context, userID string) (*pbUser.User, error) {
+			return &pbUser.User{Id: userID, Email: "jane@example.com", FullName: "Jane Doe"}, nil
+		},
+	}
+	mockPetClient := &MockPetServiceClient{
+		GetPetDetailsFunc: func(ctx context.Context, petID string) (*pbPet.Pet, error) {
+			return &pbPet.Pet{Id: petID, Name: "Whiskers"}, nil
+		},
+	}
+	notificationSvc := service.NewNotificationService(mockEmailer, mockUserClient, mockPetClient)
+	previews := service.PreviewHandler(notificationSvc, "admin-token")
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/email-previews?type=application_status_updated&userId=user123&petId=pet456", nil)
+	req.Header.Set("X-Admin-Token", "admin-token")
+	rec := httptest.NewRecorder()
+	previews.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("preview status = %d, want %d. Body: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	var preview service.EmailPreview
+	if err := json.Unmarshal(rec.Body.Bytes(), &preview); err != nil {
+		t.Fatalf("failed to decode preview: %v", err)
+	}
+	if !strings.Contains(preview.Body, "Jane Doe") || !strings.Contains(preview.Body, "Whiskers") {
+		t.Errorf("preview body should contain the user and pet names, got: %s", preview.Body)
+	}
+	if preview.To != "jane@example.com" || !strings.Contains(preview.Subject, "Whiskers") {
+		t.Errorf("preview To = %q, Subject = %q; want jane@example.com and a subject naming Whiskers", preview.To, preview.Subject)
+	}
+	if mockEmailer.SendEmailCalled {
+		t.Error("preview should not send an email")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/admin/email-previews?type=unknown&userId=user123&petId=pet456", nil)
+	req.Header.Set("X-Admin-Token", "admin-token")
+	rec = httptest.NewRecorder()
+	previews.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("preview of unknown type status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
 // TODO: Add more test cases:
 // - HandleAdoptionApplicationStatusUpdated for REJECTED status
 // - HandleAdoptionApplicationCreated when PetServiceClient fails
@@ -2,15 +2,34 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log"
+	"net/http"
+	"os"
 	"time"
 
 	// Adjust these import paths to match your project's module path and structure
+	"github.com/zhandarbeks/petstore-final-project/internal/gatewayauth"
+	"github.com/zhandarbeks/petstore-final-project/internal/settings"
+	"github.com/zhandarbeks/petstore-final-project/pet-service/internal/client"
 	"github.com/zhandarbeks/petstore-final-project/pet-service/internal/config"
 	"github.com/zhandarbeks/petstore-final-project/pet-service/internal/handler"
+	"github.com/zhandarbeks/petstore-final-project/pet-service/internal/logging"
+	"github.com/zhandarbeks/petstore-final-project/pet-service/internal/metrics"
+	"github.com/zhandarbeks/petstore-final-project/pet-service/internal/publisher"
 	"github.com/zhandarbeks/petstore-final-project/pet-service/internal/repository"
 	"github.com/zhandarbeks/petstore-final-project/pet-service/internal/server" // Using the server package we defined
+	"github.com/zhandarbeks/petstore-final-project/pet-service/internal/storage"
 	"github.com/zhandarbeks/petstore-final-project/pet-service/internal/usecase"
+
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc"
+)
+
+// MongoDB database and collection used by the service and by the migrate command.
+const (
+	mongoDBName         = "petstore_pets"
+	mongoCollectionName = "pets"
 )
 
 func main() {
@@ -21,10 +40,22 @@ func main() {
 	}
 
 	log.Println("Pet Service | Configuration loaded.")
+	if level, err := logging.ParseLevel(cfg.LogLevel); err == nil {
+		logging.SetLevel(level)
+	}
+	log.Printf("Pet Service | Effective settings: %s", settings.Summary(cfg.Settings()))
 	log.Printf("Pet Service | Server Port: %s", cfg.ServerPort)
 	log.Printf("Pet Service | MongoDB URI: %s", cfg.MongoURI) // Be cautious logging full URIs with credentials in production
 	log.Printf("Pet Service | Redis Address: %s, DB: %d", cfg.RedisAddr, cfg.RedisDB)
 
+	// "migrate" ensures indexes (and optionally seeds data) and exits instead of serving.
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(cfg, os.Args[2:]); err != nil {
+			log.Fatalf("Pet Service | FATAL: Migration failed: %v", err)
+		}
+		return
+	}
+
 	// Create a main context that can be used to signal shutdown
 	mainCtx, cancelMainCtx := context.WithCancel(context.Background())
 	defer cancelMainCtx() // Ensure that the main context is canceled when main exits
@@ -36,7 +67,7 @@ func main() {
 	mongoInitCtx, mongoCancel := context.WithTimeout(mainCtx, initTimeout)
 	defer mongoCancel()
 	// Using "petstore_pets" as DB name and "pets" as collection name, adjust if needed or move to config
-	petMongoRepo, err := repository.NewMongoDBPetRepository(mongoInitCtx, cfg.MongoURI, "petstore_pets", "pets")
+	petMongoRepo, err := repository.NewMongoDBPetRepository(mongoInitCtx, cfg.MongoURI, mongoDBName, mongoCollectionName, repository.IndexOptions{EnsureIndexes: cfg.EnsureIndexes})
 	if err != nil {
 		log.Fatalf("Pet Service | FATAL: Failed to initialize MongoDB repository: %v", err)
 	}
@@ -77,24 +108,108 @@ func main() {
 		}()
 	}
 
+	// 3b. Initialize Image Storage (presigned uploads)
+	var imageStorage storage.ImageStorage
+	if cfg.ImageStorageBackend == "s3" {
+		imageStorage, err = storage.NewS3ImageStorage(storage.S3Config{
+			Bucket:          cfg.ImageStorageBucket,
+			Region:          cfg.ImageStorageRegion,
+			Endpoint:        cfg.ImageStorageEndpoint,
+			AccessKeyID:     cfg.ImageStorageAccessKeyID,
+			SecretAccessKey: cfg.ImageStorageSecretKey,
+			PublicBaseURL:   cfg.ImageStoragePublicURL,
+			Expiry:          cfg.ImageUploadURLExpiry,
+			MaxUploadBytes:  cfg.ImageUploadMaxBytes,
+		})
+		if err != nil {
+			log.Fatalf("Pet Service | FATAL: Failed to initialize S3 image storage: %v", err)
+		}
+	} else {
+		imageStorage = storage.NewFakeImageStorage(cfg.ImageStoragePublicURL)
+	}
+	log.Printf("Pet Service | Image storage initialized (backend: %s).", cfg.ImageStorageBackend)
+
+	// 3c. Initialize User Service client (used to verify users, e.g. for listing transfers)
+	userServiceClient, err := client.NewUserServiceGRPCClient(mainCtx, cfg.UserServiceGRPCURL)
+	if err != nil {
+		log.Fatalf("Pet Service | FATAL: Failed to create User Service client: %v", err)
+	}
+	defer func() {
+		if err := userServiceClient.Close(); err != nil {
+			log.Printf("Pet Service | Error closing User Service client: %v", err)
+		}
+	}()
+
+	// 3d. Initialize NATS Publisher (pet.unavailable events for the notification-service)
+	natsPublisher, err := publisher.NewNATSPetPublisher(cfg.NatsURL, cfg.NatsNamespace)
+	if err != nil {
+		log.Fatalf("Pet Service | FATAL: Failed to initialize NATS publisher: %v", err)
+	}
+	defer natsPublisher.Close()
+
 	// 4. Initialize Pet Usecase
-	petUsecase := usecase.NewPetUsecase(petMongoRepo, petRedisCache)
+	petUsecase := usecase.NewPetUsecase(petMongoRepo, petRedisCache, imageStorage, userServiceClient, natsPublisher, usecase.PetUsecaseConfig{
+		FacetsCacheTTL:       cfg.FacetsCacheTTL,
+		ListCacheTTL:         cfg.ListCacheTTL,
+		SeedCacheOnCreate:    cfg.SeedCacheOnCreate,
+		MaxDescriptionLength: cfg.MaxDescriptionLength,
+		MaxImagesPerPet:      cfg.MaxImagesPerPet,
+	})
 	log.Println("Pet Service | Usecase layer initialized.")
 
 	// 5. Initialize Pet gRPC Handler
-	petGRPCHandler := handler.NewPetHandler(petUsecase)
+	petGRPCHandler := handler.NewPetHandler(petUsecase, cfg.PlaceholderImageURL)
 	log.Println("Pet Service | gRPC handler initialized.")
 
 	// 6. Initialize and Start Pet gRPC Server
 	// This uses the server.NewGRPCServer from pet-service/internal/server/grpc_server.go
-	grpcServer, err := server.NewGRPCServer(cfg.ServerPort, petGRPCHandler)
+	// Per-user request limits, counted in Redis so they hold across instances
+	var rateLimitInterceptors []grpc.UnaryServerInterceptor
+	if cfg.UserRateLimitReads > 0 || cfg.UserRateLimitWrites > 0 {
+		rateLimitRedis := redis.NewClient(&redis.Options{Addr: cfg.RedisAddr, Password: cfg.RedisPassword, DB: cfg.RedisDB})
+		defer rateLimitRedis.Close()
+		rateLimitInterceptors = append(rateLimitInterceptors, server.NewUserRateLimitInterceptor(
+			server.NewRedisRateCounter(rateLimitRedis, "pet:ratelimit:"),
+			server.UserRateLimits{ReadsPerWindow: cfg.UserRateLimitReads, WritesPerWindow: cfg.UserRateLimitWrites, Window: cfg.UserRateLimitWindow},
+		))
+		log.Printf("Pet Service | Per-user rate limits: %d reads, %d writes per %s", cfg.UserRateLimitReads, cfg.UserRateLimitWrites, cfg.UserRateLimitWindow)
+	}
+
+	// Only the gateway may name the caller; checked before the rate limits count requests per user.
+	if cfg.GatewayAuthToken == "" {
+		log.Println("Pet Service | WARNING: GATEWAY_AUTH_TOKEN is not set. Caller identity metadata is trusted from any client, so keep the gRPC port private.")
+	}
+	interceptors := append([]grpc.UnaryServerInterceptor{gatewayauth.UnaryServerInterceptor(cfg.GatewayAuthToken)}, rateLimitInterceptors...)
+	grpcServer, err := server.NewGRPCServer(cfg.ServerPort, petGRPCHandler, cfg.MaxInFlightRequests, interceptors...)
 	if err != nil {
 		log.Fatalf("Pet Service | FATAL: Failed to create gRPC server: %v", err)
 	}
 
+	// Publish the cache hit/miss counters for scraping
+	var metricsServer *http.Server
+	if cfg.MetricsHTTPPort != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Handler(metrics.CacheHits, metrics.CacheMisses))
+		metricsServer = &http.Server{Addr: cfg.MetricsHTTPPort, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+		go func() {
+			log.Printf("Pet Service | Metrics HTTP server listening on %s", cfg.MetricsHTTPPort)
+			if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Printf("Pet Service | Metrics HTTP server error: %v", err)
+			}
+		}()
+	}
+
 	log.Println("Pet Service | Starting up...")
 	// RunWithGracefulShutdown will block until a shutdown signal is received.
 	grpcServer.RunWithGracefulShutdown()
 
+	if metricsServer != nil {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := metricsServer.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Pet Service | Error shutting down metrics HTTP server: %v", err)
+		}
+		cancel()
+	}
+
 	log.Println("Pet Service | Shut down gracefully.")
 }
\ No newline at end of file
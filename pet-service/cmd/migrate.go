@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	"github.com/zhandarbeks/petstore-final-project/pet-service/internal/config"
+	"github.com/zhandarbeks/petstore-final-project/pet-service/internal/migrate"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// runMigrate implements "pet-service migrate [-seed]": it ensures the pets indexes and,
+// with -seed, inserts the sample pets. Run it before starting instances with ENSURE_INDEXES=false.
+func runMigrate(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	seed := fs.Bool("seed", false, "insert sample pets that don't exist yet")
+	timeout := fs.Duration("timeout", 60*time.Second, "overall migration timeout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(cfg.MongoURI))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Disconnect(context.Background()); err != nil {
+			log.Printf("Pet Service | Error disconnecting MongoDB after migration: %v", err)
+		}
+	}()
+	if err := client.Ping(ctx, nil); err != nil {
+		return err
+	}
+
+	if err := migrate.Run(ctx, client.Database(mongoDBName), mongoCollectionName, migrate.Options{Seed: *seed}); err != nil {
+		return err
+	}
+	log.Println("Pet Service | Migration complete.")
+	return nil
+}
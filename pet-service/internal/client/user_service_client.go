@@ -0,0 +1,80 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	pbUser "github.com/zhandarbeks/petstore-final-project/genprotos/user" // Adjust import path to your generated user protos
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure" // For connecting without TLS (dev environment)
+	"google.golang.org/grpc/status"
+)
+
+// UserServiceClient defines the calls the pet-service makes to the User gRPC service.
+// This helps in mocking the client for testing purposes.
+type UserServiceClient interface {
+	UserExists(ctx context.Context, userID string) (bool, error)
+	Close() error
+}
+
+// userServiceGRPCClient is the gRPC implementation of UserServiceClient.
+type userServiceGRPCClient struct {
+	conn   *grpc.ClientConn
+	client pbUser.UserServiceClient
+}
+
+// NewUserServiceGRPCClient creates a new gRPC client for the User Service.
+// The connection is established lazily, so the pet-service can start before the user-service is up.
+func NewUserServiceGRPCClient(ctx context.Context, targetURL string) (UserServiceClient, error) {
+	if targetURL == "" {
+		return nil, fmt.Errorf("user service target URL cannot be empty")
+	}
+
+	log.Printf("Pet Service | Setting up User Service gRPC client for %s", targetURL)
+	conn, err := grpc.DialContext(
+		ctx,
+		targetURL,
+		grpc.WithTransportCredentials(insecure.NewCredentials()), // No TLS for now
+	)
+	if err != nil {
+		log.Printf("Pet Service | Failed to set up User Service gRPC client for %s: %v", targetURL, err)
+		return nil, fmt.Errorf("did not connect to user service: %w", err)
+	}
+
+	return &userServiceGRPCClient{
+		conn:   conn,
+		client: pbUser.NewUserServiceClient(conn),
+	}, nil
+}
+
+// UserExists reports whether the User Service knows the given user ID.
+func (c *userServiceGRPCClient) UserExists(ctx context.Context, userID string) (bool, error) {
+	if userID == "" {
+		return false, nil
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := c.client.GetUser(callCtx, &pbUser.GetUserRequest{UserId: userID})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return false, nil
+		}
+		log.Printf("Pet Service | Error calling User Service GetUser for UserID %s: %v", userID, err)
+		return false, fmt.Errorf("user service GetUser call failed: %w", err)
+	}
+	return true, nil
+}
+
+// Close closes the gRPC client connection to the User Service.
+func (c *userServiceGRPCClient) Close() error {
+	if c.conn != nil {
+		log.Println("Pet Service | Closing User Service gRPC client connection...")
+		return c.conn.Close()
+	}
+	return nil
+}
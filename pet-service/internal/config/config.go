@@ -4,9 +4,12 @@ import (
 	"log"
 	"os"
 	"strconv"
-	// "time" // Not immediately needed for basic pet service config, can be added if token/expiry logic comes here.
+	"time"
 
 	"github.com/joho/godotenv" // For loading .env files (optional)
+	"github.com/zhandarbeks/petstore-final-project/internal/eventbus"
+	"github.com/zhandarbeks/petstore-final-project/internal/settings"
+	"github.com/zhandarbeks/petstore-final-project/pet-service/internal/logging"
 )
 
 // Config holds all configuration for the pet-service
@@ -16,9 +19,62 @@ type Config struct {
 	RedisAddr     string // Redis server address for pet caching (e.g., "localhost:6379")
 	RedisPassword string // Redis password (if any)
 	RedisDB       int    // Redis database number for pet caching
+	MaxInFlightRequests int // Max concurrent gRPC requests before rejecting with ResourceExhausted (0 = unlimited)
+	EnsureIndexes       bool // Create MongoDB indexes on startup; disable when migrations manage them
+	FacetsCacheTTL      time.Duration // How long the pet facets aggregation is cached in Redis (0 = no caching)
+	ListCacheTTL        time.Duration // How long a page of ListPets results is cached in Redis (0 = no caching)
+	SeedCacheOnCreate   bool          // Cache newly created pets so an immediate read sees them
+	MaxDescriptionLength int          // Max characters of a pet description after normalization (0 = unlimited)
+	MaxImagesPerPet     int           // Max image URLs per pet, enforced when adding or replacing them (0 = unlimited)
+	UserServiceGRPCURL  string        // User service address, used to verify users (e.g. new owners of transferred listings)
+	NatsURL             string        // NATS server URL for pet events (e.g., "nats://localhost:4222")
+	NatsNamespace       eventbus.Namespace // Environment prefix for NATS subjects (NATS_SUBJECT_PREFIX), shared with the consumers
+	MetricsHTTPPort     string        // Port of the HTTP server publishing /metrics (e.g., ":9090"); empty disables it
+	UserRateLimitReads  int           // Read requests one user may make per UserRateLimitWindow (0 = unlimited)
+	UserRateLimitWrites int           // Write requests one user may make per UserRateLimitWindow (0 = unlimited)
+	UserRateLimitWindow time.Duration // Window of the per-user rate limits
+	GatewayAuthToken    string        // Token the API gateway attaches to its calls; caller identity metadata without it is rejected. Empty trusts every caller
+	LogLevel            string        // "debug", "info" or "warn"; debug adds per-request detail such as cache misses
+
+	// Image upload storage settings
+	ImageStorageBackend      string        // "s3" for an S3-compatible bucket, "fake" for local development
+	ImageStorageBucket       string        // Bucket name
+	ImageStorageRegion       string        // Bucket region (use "auto" for GCS interop)
+	ImageStorageEndpoint     string        // Optional custom endpoint (MinIO, GCS interop); empty means AWS S3
+	ImageStorageAccessKeyID  string        // Access key used to sign upload policies
+	ImageStorageSecretKey    string        // Secret key used to sign upload policies
+	ImageStoragePublicURL    string        // Base URL images are served from (bucket URL or CDN)
+	ImageUploadURLExpiry     time.Duration // How long a presigned upload target stays valid
+	ImageUploadMaxBytes      int64         // Maximum accepted upload size
+	PlaceholderImageURL      string        // Thumbnail returned for pets without images (empty = none)
 	// Add other pet-service specific configurations here if needed
 }
 
+// Settings returns the feature flags and key tunables in effect. Secrets and connection strings are left out.
+func (c *Config) Settings() []settings.Setting {
+	return []settings.Setting{
+		{Name: "image_storage_backend", Value: c.ImageStorageBackend},
+		{Name: "image_upload_url_expiry", Value: c.ImageUploadURLExpiry.String()},
+		{Name: "image_upload_max_bytes", Value: strconv.FormatInt(c.ImageUploadMaxBytes, 10)},
+		{Name: "placeholder_image_url", Value: c.PlaceholderImageURL},
+		{Name: "facets_cache_ttl", Value: c.FacetsCacheTTL.String()},
+		{Name: "list_cache_ttl", Value: c.ListCacheTTL.String()},
+		{Name: "seed_cache_on_create", Value: strconv.FormatBool(c.SeedCacheOnCreate)},
+		{Name: "nats_subject_prefix", Value: c.NatsNamespace.Prefix()},
+		{Name: "max_description_length", Value: strconv.Itoa(c.MaxDescriptionLength)},
+		{Name: "max_images_per_pet", Value: strconv.Itoa(c.MaxImagesPerPet)},
+		{Name: "ensure_indexes", Value: strconv.FormatBool(c.EnsureIndexes)},
+		{Name: "max_in_flight_requests", Value: strconv.Itoa(c.MaxInFlightRequests)},
+		{Name: "redis_db", Value: strconv.Itoa(c.RedisDB)},
+		{Name: "metrics_http_port", Value: c.MetricsHTTPPort},
+		{Name: "user_rate_limit_reads", Value: strconv.Itoa(c.UserRateLimitReads)},
+		{Name: "user_rate_limit_writes", Value: strconv.Itoa(c.UserRateLimitWrites)},
+		{Name: "user_rate_limit_window", Value: c.UserRateLimitWindow.String()},
+		{Name: "gateway_auth_enabled", Value: strconv.FormatBool(c.GatewayAuthToken != "")},
+		{Name: "log_level", Value: c.LogLevel},
+	}
+}
+
 // Load loads configuration. It first attempts to load from a .env file (if present),
 // then falls back to actual environment variables, and finally to defaults.
 func Load() (*Config, error) {
@@ -37,6 +93,19 @@ func Load() (*Config, error) {
 		MongoURI:      getEnv("MONGO_URI_PETS", "mongodb://localhost:27017/petdb_dev"), // Default for local, Docker will override
 		RedisAddr:     getEnv("REDIS_ADDR_PETS", "localhost:6379"),                   // Default for local, Docker will override
 		RedisPassword: getEnv("REDIS_PASSWORD_PETS", ""),                             // Default to no password
+		UserServiceGRPCURL: getEnv("USER_SERVICE_GRPC_URL", "localhost:50051"),
+		NatsURL:            getEnv("NATS_URL", "nats://localhost:4222"),
+		MetricsHTTPPort:    getEnv("METRICS_HTTP_PORT", ":9090"),
+		GatewayAuthToken:   getEnv("GATEWAY_AUTH_TOKEN", ""),
+
+		ImageStorageBackend:     getEnv("IMAGE_STORAGE_BACKEND", "fake"),
+		ImageStorageBucket:      getEnv("IMAGE_STORAGE_BUCKET", "petstore-pet-images"),
+		ImageStorageRegion:      getEnv("IMAGE_STORAGE_REGION", "us-east-1"),
+		ImageStorageEndpoint:    getEnv("IMAGE_STORAGE_ENDPOINT", ""),
+		ImageStorageAccessKeyID: getEnv("IMAGE_STORAGE_ACCESS_KEY_ID", ""),
+		ImageStorageSecretKey:   getEnv("IMAGE_STORAGE_SECRET_ACCESS_KEY", ""),
+		ImageStoragePublicURL:   getEnv("IMAGE_STORAGE_PUBLIC_URL", "http://localhost:9000/petstore-pet-images"),
+		PlaceholderImageURL:     getEnv("PET_PLACEHOLDER_IMAGE_URL", ""),
 	}
 
 	redisDBStr := getEnv("REDIS_DB_PETS", "1") // Using DB 1 for pets to separate from user cache (DB 0)
@@ -48,6 +117,110 @@ func Load() (*Config, error) {
 		cfg.RedisDB = redisDBVal
 	}
 
+	uploadExpiryStr := getEnv("IMAGE_UPLOAD_URL_EXPIRY_MINUTES", "15")
+	uploadExpiryMinutes, err := strconv.Atoi(uploadExpiryStr)
+	if err != nil || uploadExpiryMinutes <= 0 {
+		log.Printf("Pet Service | Warning: Invalid IMAGE_UPLOAD_URL_EXPIRY_MINUTES value: '%s'. Using default 15 minutes. Error: %v", uploadExpiryStr, err)
+		uploadExpiryMinutes = 15
+	}
+	cfg.ImageUploadURLExpiry = time.Duration(uploadExpiryMinutes) * time.Minute
+
+	uploadMaxBytesStr := getEnv("IMAGE_UPLOAD_MAX_BYTES", "5242880") // 5 MiB
+	uploadMaxBytes, err := strconv.ParseInt(uploadMaxBytesStr, 10, 64)
+	if err != nil || uploadMaxBytes <= 0 {
+		log.Printf("Pet Service | Warning: Invalid IMAGE_UPLOAD_MAX_BYTES value: '%s'. Using default 5242880. Error: %v", uploadMaxBytesStr, err)
+		uploadMaxBytes = 5242880
+	}
+	cfg.ImageUploadMaxBytes = uploadMaxBytes
+
+	maxInFlightStr := getEnv("MAX_IN_FLIGHT_REQUESTS_PETS", "100")
+	maxInFlightVal, err := strconv.Atoi(maxInFlightStr)
+	if err != nil || maxInFlightVal < 0 {
+		log.Printf("Pet Service | Warning: Invalid MAX_IN_FLIGHT_REQUESTS_PETS value: '%s'. Using default 100. Error: %v", maxInFlightStr, err)
+		cfg.MaxInFlightRequests = 100
+	} else {
+		cfg.MaxInFlightRequests = maxInFlightVal
+	}
+
+	facetsTTLStr := getEnv("PET_FACETS_CACHE_TTL_SECONDS", "300")
+	facetsTTLSeconds, err := strconv.Atoi(facetsTTLStr)
+	if err != nil || facetsTTLSeconds < 0 {
+		log.Printf("Pet Service | Warning: Invalid PET_FACETS_CACHE_TTL_SECONDS value: '%s'. Using default 300. Error: %v", facetsTTLStr, err)
+		facetsTTLSeconds = 300
+	}
+	cfg.FacetsCacheTTL = time.Duration(facetsTTLSeconds) * time.Second
+
+	listTTLStr := getEnv("PET_LIST_CACHE_TTL_SECONDS", "60")
+	listTTLSeconds, err := strconv.Atoi(listTTLStr)
+	if err != nil || listTTLSeconds < 0 {
+		log.Printf("Pet Service | Warning: Invalid PET_LIST_CACHE_TTL_SECONDS value: '%s'. Using default 60. Error: %v", listTTLStr, err)
+		listTTLSeconds = 60
+	}
+	cfg.ListCacheTTL = time.Duration(listTTLSeconds) * time.Second
+
+	ensureIndexesStr := getEnv("ENSURE_INDEXES", "true")
+	ensureIndexesVal, err := strconv.ParseBool(ensureIndexesStr)
+	if err != nil {
+		log.Printf("Pet Service | Warning: Invalid ENSURE_INDEXES value: '%s'. Using default true. Error: %v", ensureIndexesStr, err)
+		ensureIndexesVal = true
+	}
+	cfg.EnsureIndexes = ensureIndexesVal
+
+	seedCacheStr := getEnv("SEED_CACHE_ON_CREATE", "true")
+	seedCacheVal, err := strconv.ParseBool(seedCacheStr)
+	if err != nil {
+		log.Printf("Pet Service | Warning: Invalid SEED_CACHE_ON_CREATE value: '%s'. Using default true. Error: %v", seedCacheStr, err)
+		seedCacheVal = true
+	}
+	cfg.SeedCacheOnCreate = seedCacheVal
+
+	maxDescriptionLengthStr := getEnv("MAX_DESCRIPTION_LENGTH", "2000")
+	maxDescriptionLengthVal, err := strconv.Atoi(maxDescriptionLengthStr)
+	if err != nil || maxDescriptionLengthVal < 0 {
+		log.Printf("Pet Service | Warning: Invalid MAX_DESCRIPTION_LENGTH value: '%s'. Using default 2000. Error: %v", maxDescriptionLengthStr, err)
+		maxDescriptionLengthVal = 2000
+	}
+	cfg.MaxDescriptionLength = maxDescriptionLengthVal
+
+	maxImagesPerPetStr := getEnv("MAX_IMAGES_PER_PET", "20")
+	maxImagesPerPetVal, err := strconv.Atoi(maxImagesPerPetStr)
+	if err != nil || maxImagesPerPetVal < 0 {
+		log.Printf("Pet Service | Warning: Invalid MAX_IMAGES_PER_PET value: '%s'. Using default 20. Error: %v", maxImagesPerPetStr, err)
+		maxImagesPerPetVal = 20
+	}
+	cfg.MaxImagesPerPet = maxImagesPerPetVal
+
+	rateLimitReadsStr := getEnv("USER_RATE_LIMIT_READS", "600")
+	rateLimitReadsVal, err := strconv.Atoi(rateLimitReadsStr)
+	if err != nil || rateLimitReadsVal < 0 {
+		log.Printf("Pet Service | Warning: Invalid USER_RATE_LIMIT_READS value: '%s'. Using default 600. Error: %v", rateLimitReadsStr, err)
+		rateLimitReadsVal = 600
+	}
+	cfg.UserRateLimitReads = rateLimitReadsVal
+
+	rateLimitWritesStr := getEnv("USER_RATE_LIMIT_WRITES", "60")
+	rateLimitWritesVal, err := strconv.Atoi(rateLimitWritesStr)
+	if err != nil || rateLimitWritesVal < 0 {
+		log.Printf("Pet Service | Warning: Invalid USER_RATE_LIMIT_WRITES value: '%s'. Using default 60. Error: %v", rateLimitWritesStr, err)
+		rateLimitWritesVal = 60
+	}
+	cfg.UserRateLimitWrites = rateLimitWritesVal
+
+	rateLimitWindowStr := getEnv("USER_RATE_LIMIT_WINDOW", "1m")
+	rateLimitWindowVal, err := time.ParseDuration(rateLimitWindowStr)
+	if err != nil || rateLimitWindowVal <= 0 {
+		log.Printf("Pet Service | Warning: Invalid USER_RATE_LIMIT_WINDOW value: '%s'. Using default 1m. Error: %v", rateLimitWindowStr, err)
+		rateLimitWindowVal = time.Minute
+	}
+	cfg.UserRateLimitWindow = rateLimitWindowVal
+
+	logLevelStr := getEnv("LOG_LEVEL", "info")
+	logLevelVal, err := logging.ParseLevel(logLevelStr)
+	if err != nil {
+		log.Printf("Pet Service | Warning: Invalid LOG_LEVEL value: '%s'. Using default info. Error: %v", logLevelStr, err)
+	}
+	cfg.LogLevel = logLevelVal.String()
+
 	// Critical validations
 	if cfg.MongoURI == "" {
 		log.Fatal("Pet Service | FATAL: MONGO_URI_PETS environment variable is required and was not found or set.")
@@ -55,6 +228,15 @@ func Load() (*Config, error) {
 	if cfg.ServerPort == "" {
 		log.Fatal("Pet Service | FATAL: PET_SERVICE_PORT environment variable is required and was not found or set.")
 	}
+	if cfg.ImageStorageBackend != "s3" && cfg.ImageStorageBackend != "fake" {
+		log.Fatalf("Pet Service | FATAL: IMAGE_STORAGE_BACKEND must be 's3' or 'fake', got '%s'.", cfg.ImageStorageBackend)
+	}
+	// A bad prefix would make this environment miss its events or read another's, so don't start.
+	natsNamespace, err := eventbus.NewNamespace(getEnv("NATS_SUBJECT_PREFIX", ""))
+	if err != nil {
+		log.Fatalf("Pet Service | FATAL: NATS_SUBJECT_PREFIX is invalid: %v", err)
+	}
+	cfg.NatsNamespace = natsNamespace
 
 	return cfg, nil
 }
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"strings"
 	"time"
 	// "errors" // Uncomment if you add validation methods that return errors
 )
@@ -28,11 +29,70 @@ type Pet struct {
 	ListedByUserID   string         `bson:"listed_by_user_id,omitempty" json:"listed_by_user_id,omitempty"` // ID of the user who listed the pet
 	AdoptedByUserID  string         `bson:"adopted_by_user_id,omitempty" json:"adopted_by_user_id,omitempty"` // ID of the user who adopted the pet
 	ImageURLs        []string       `bson:"image_urls,omitempty" json:"image_urls,omitempty"`                 // List of URLs for pet images
+	Tags             []string       `bson:"tags,omitempty" json:"tags,omitempty"`                             // Normalized labels, see NormalizeTags
+	StatusHistory    []StatusChange `bson:"status_history,omitempty" json:"status_history,omitempty"`         // Audited status changes, oldest first
+	ListingHistory   []ListingTransfer `bson:"listing_history,omitempty" json:"listing_history,omitempty"`    // Owner changes of the listing, oldest first
+	Version          int            `bson:"version" json:"version"`                                           // Incremented on every update, used for optimistic locking
 	CreatedAt        time.Time      `bson:"created_at" json:"created_at"`
 	UpdatedAt        time.Time      `bson:"updated_at" json:"updated_at"`
+	Deleted          bool           `bson:"deleted,omitempty" json:"deleted,omitempty"`                       // Soft-deleted: hidden from reads but kept for its history
+	DeletedAt        *time.Time     `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
 	// Additional fields like 'vaccination_status', 'gender', 'size', 'location' could be added.
 }
 
+// StatusChange is an entry in a pet's status history.
+type StatusChange struct {
+	FromStatus AdoptionStatus `bson:"from_status" json:"from_status"`
+	ToStatus   AdoptionStatus `bson:"to_status" json:"to_status"`
+	Reason     string         `bson:"reason,omitempty" json:"reason,omitempty"`
+	ChangedBy  string         `bson:"changed_by,omitempty" json:"changed_by,omitempty"`
+	Forced     bool           `bson:"forced,omitempty" json:"forced,omitempty"` // Admin override that skipped the usual rules
+	ChangedAt  time.Time      `bson:"changed_at" json:"changed_at"`
+}
+
+// ListingTransfer is an entry in a pet's listing history, recorded when the listing moves to another user.
+type ListingTransfer struct {
+	FromUserID    string    `bson:"from_user_id" json:"from_user_id"`
+	ToUserID      string    `bson:"to_user_id" json:"to_user_id"`
+	TransferredBy string    `bson:"transferred_by" json:"transferred_by"` // Current owner or admin who made the change
+	TransferredAt time.Time `bson:"transferred_at" json:"transferred_at"`
+}
+
+// MaxStatusReasonLength is the longest reason, in characters, accepted for a status change.
+const MaxStatusReasonLength = 500
+
+// MaxTagLength is the longest tag accepted after normalization.
+const MaxTagLength = 40
+
+// NormalizeTags lowercases and trims tags, collapses inner whitespace, and drops empty,
+// overlong and duplicate entries, so "Good  with Kids" and "good with kids" are the same tag.
+func NormalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	out := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		t := strings.Join(strings.Fields(strings.ToLower(tag)), " ")
+		if t == "" || len(t) > MaxTagLength || seen[t] {
+			continue
+		}
+		seen[t] = true
+		out = append(out, t)
+	}
+	return out
+}
+
+// FacetCount is a distinct field value together with the number of pets that have it.
+type FacetCount struct {
+	Value string `bson:"_id" json:"value"`
+	Count int64  `bson:"count" json:"count"`
+}
+
+// PetFacets summarizes the distinct values of the filterable pet fields, most common first.
+type PetFacets struct {
+	Species          []FacetCount `bson:"species" json:"species"`
+	Breeds           []FacetCount `bson:"breeds" json:"breeds"`
+	AdoptionStatuses []FacetCount `bson:"adoption_statuses" json:"adoption_statuses"`
+}
+
 // PrepareForCreate sets the CreatedAt and UpdatedAt timestamps for a new pet.
 // It also defaults AdoptionStatus to AVAILABLE if not set.
 func (p *Pet) PrepareForCreate() {
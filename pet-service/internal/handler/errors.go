@@ -0,0 +1,23 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// InternalError converts an unexpected usecase error into a gRPC status error.
+// Requests the client cancelled or that ran out of time are reported as Canceled or
+// DeadlineExceeded instead of Internal, so caller-side aborts are not counted as server failures.
+func InternalError(ctx context.Context, err error, message string) error {
+	switch {
+	case errors.Is(err, context.Canceled) || errors.Is(ctx.Err(), context.Canceled):
+		return status.Errorf(codes.Canceled, "%s: request was cancelled", message)
+	case errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return status.Errorf(codes.DeadlineExceeded, "%s: deadline exceeded", message)
+	default:
+		return status.Errorf(codes.Internal, "%s: %v", message, err)
+	}
+}
@@ -4,13 +4,17 @@ import (
 	"context"
 	"errors"
 	"log"
+	"slices"
+	"strings"
 	"time" // Import time for RFC3339 formatting
 
 	"github.com/zhandarbeks/petstore-final-project/pet-service/internal/domain"   // Adjust import path
+	"github.com/zhandarbeks/petstore-final-project/pet-service/internal/repository"
 	"github.com/zhandarbeks/petstore-final-project/pet-service/internal/usecase" // Adjust import path
 	pb "github.com/zhandarbeks/petstore-final-project/genprotos/pet"            // Adjust import path to your generated protos
 
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	// "google.golang.org/protobuf/types/known/timestamppb" // No longer needed here if formatting to string directly
 )
@@ -19,14 +23,57 @@ import (
 type PetHandler struct {
 	pb.UnimplementedPetServiceServer // Embed for forward compatibility
 	usecase                        usecase.PetUsecase
+	placeholderImageURL            string // Returned as thumbnail_url for pets without images; empty disables it
 }
 
 // NewPetHandler creates a new PetHandler.
-func NewPetHandler(uc usecase.PetUsecase) *PetHandler {
+func NewPetHandler(uc usecase.PetUsecase, placeholderImageURL string) *PetHandler {
 	if uc == nil {
 		log.Fatal("PetUsecase cannot be nil in NewPetHandler")
 	}
-	return &PetHandler{usecase: uc}
+	return &PetHandler{usecase: uc, placeholderImageURL: placeholderImageURL}
+}
+
+// userRolesMetadataKey is the incoming gRPC metadata key carrying the caller's roles (comma-separated).
+const userRolesMetadataKey = "x-user-roles"
+
+// userIDMetadataKey is the incoming gRPC metadata key carrying the authenticated caller's user ID.
+// The gateway sets it and the roles; the server's gatewayauth interceptor rejects them from anyone else.
+const userIDMetadataKey = "x-user-id"
+
+// roleAdmin is required for administrative overrides.
+const roleAdmin = "admin"
+
+// roleShelter may, like roleAdmin, create pets with a non-default initial status. The user-service
+// grants it to shelter accounts and the gateway forwards it from the token's role claim.
+const roleShelter = "shelter"
+
+// callerUserID returns the authenticated caller's user ID from incoming gRPC metadata, or "".
+func callerUserID(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if ids := md.Get(userIDMetadataKey); len(ids) > 0 {
+		return strings.TrimSpace(ids[0])
+	}
+	return ""
+}
+
+// callerHasRole reports whether the incoming gRPC metadata grants role.
+func callerHasRole(ctx context.Context, role string) bool {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return false
+	}
+	for _, v := range md.Get(userRolesMetadataKey) {
+		for _, r := range strings.Split(v, ",") {
+			if strings.TrimSpace(r) == role {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 // --- Helper Functions for Type Conversion ---
@@ -57,6 +104,50 @@ func domainAdoptionStatusToPb(domainStatus domain.AdoptionStatus) pb.AdoptionSta
 	}
 }
 
+func domainListingHistoryToPb(history []domain.ListingTransfer) []*pb.ListingTransfer {
+	if len(history) == 0 {
+		return nil
+	}
+	out := make([]*pb.ListingTransfer, len(history))
+	for i, t := range history {
+		out[i] = &pb.ListingTransfer{
+			FromUserId:    t.FromUserID,
+			ToUserId:      t.ToUserID,
+			TransferredBy: t.TransferredBy,
+			TransferredAt: t.TransferredAt.Format(time.RFC3339),
+		}
+	}
+	return out
+}
+
+func domainStatusHistoryToPb(history []domain.StatusChange) []*pb.PetStatusChange {
+	if len(history) == 0 {
+		return nil
+	}
+	out := make([]*pb.PetStatusChange, len(history))
+	for i, c := range history {
+		out[i] = &pb.PetStatusChange{
+			FromStatus: domainAdoptionStatusToPb(c.FromStatus),
+			ToStatus:   domainAdoptionStatusToPb(c.ToStatus),
+			Reason:     c.Reason,
+			ChangedBy:  c.ChangedBy,
+			Forced:     c.Forced,
+			ChangedAt:  c.ChangedAt.Format(time.RFC3339),
+		}
+	}
+	return out
+}
+
+// petToPb converts a pet for a response, falling back to the placeholder image for the thumbnail.
+// The placeholder is presentation only and never stored with the pet.
+func (h *PetHandler) petToPb(dp *domain.Pet) *pb.Pet {
+	p := domainPetToPbPet(dp)
+	if p != nil && p.ThumbnailUrl == "" {
+		p.ThumbnailUrl = h.placeholderImageURL
+	}
+	return p
+}
+
 func domainPetToPbPet(dp *domain.Pet) *pb.Pet {
 	if dp == nil {
 		return nil
@@ -68,6 +159,14 @@ func domainPetToPbPet(dp *domain.Pet) *pb.Pet {
 	if !dp.UpdatedAt.IsZero() {
 		updatedAtStr = dp.UpdatedAt.Format(time.RFC3339) // Corrected: Format to RFC3339 string
 	}
+	var thumbnailURL string
+	if len(dp.ImageURLs) > 0 {
+		thumbnailURL = dp.ImageURLs[0]
+	}
+	var deletedAtStr string
+	if dp.DeletedAt != nil {
+		deletedAtStr = dp.DeletedAt.Format(time.RFC3339)
+	}
 
 	return &pb.Pet{
 		Id:                dp.ID,
@@ -80,6 +179,12 @@ func domainPetToPbPet(dp *domain.Pet) *pb.Pet {
 		ListedByUserId:    dp.ListedByUserID,
 		AdoptedByUserId:   dp.AdoptedByUserID,
 		ImageUrls:         dp.ImageURLs,
+		Tags:              dp.Tags,
+		StatusHistory:     domainStatusHistoryToPb(dp.StatusHistory),
+		ThumbnailUrl:      thumbnailURL,
+		ListingHistory:    domainListingHistoryToPb(dp.ListingHistory),
+		Deleted:           dp.Deleted,
+		DeletedAt:         deletedAtStr,
 		CreatedAt:         createdAtStr, // Now a standard ISO string
 		UpdatedAt:         updatedAtStr, // Now a standard ISO string
 	}
@@ -104,16 +209,29 @@ func (h *PetHandler) CreatePet(ctx context.Context, req *pb.CreatePetRequest) (*
 		Description:    req.GetDescription(),
 		ListedByUserID: req.GetListedByUserId(),
 		ImageURLs:      req.GetImageUrls(),
+		Tags:           req.GetTags(),
+	}
+	if req.AdoptionStatus != nil {
+		reqData.AdoptionStatus = pbAdoptionStatusToDomain(req.GetAdoptionStatus())
+		reqData.CallerCanSetStatus = callerHasRole(ctx, roleAdmin) || callerHasRole(ctx, roleShelter)
 	}
 
 	createdPet, err := h.usecase.CreatePet(ctx, reqData)
 	if err != nil {
 		log.Printf("Pet Service | Error during CreatePet usecase call for name %s: %v", req.GetName(), err)
-		return nil, status.Errorf(codes.Internal, "Failed to create pet: %v", err)
+		switch {
+		case errors.Is(err, usecase.ErrInvalidInitialStatus):
+			return nil, status.Errorf(codes.InvalidArgument, err.Error())
+		case errors.Is(err, usecase.ErrInitialStatusForbidden):
+			return nil, status.Errorf(codes.PermissionDenied, "Admin or shelter role is required to set a pet's initial status")
+		case errors.Is(err, usecase.ErrInvalidImageURL):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, InternalError(ctx, err, "Failed to create pet")
 	}
 
 	log.Printf("Pet Service | Pet created successfully via gRPC: %s (ID: %s)", createdPet.Name, createdPet.ID)
-	return &pb.PetResponse{Pet: domainPetToPbPet(createdPet)}, nil
+	return &pb.PetResponse{Pet: h.petToPb(createdPet)}, nil
 }
 
 func (h *PetHandler) GetPet(ctx context.Context, req *pb.GetPetRequest) (*pb.PetResponse, error) {
@@ -129,11 +247,50 @@ func (h *PetHandler) GetPet(ctx context.Context, req *pb.GetPetRequest) (*pb.Pet
 		if err.Error() == "pet not found" {
 			return nil, status.Errorf(codes.NotFound, "Pet not found")
 		}
-		return nil, status.Errorf(codes.Internal, "Failed to get pet: %v", err)
+		return nil, InternalError(ctx, err, "Failed to get pet")
 	}
 
 	log.Printf("Pet Service | Pet retrieved successfully via gRPC: %s (ID: %s)", pet.Name, pet.ID)
-	return &pb.PetResponse{Pet: domainPetToPbPet(pet)}, nil
+	return &pb.PetResponse{Pet: h.petToPb(pet)}, nil
+}
+
+func (h *PetHandler) CheckPetAvailability(ctx context.Context, req *pb.CheckPetAvailabilityRequest) (*pb.CheckPetAvailabilityResponse, error) {
+	if req.GetPetId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Pet ID is required")
+	}
+
+	pet, err := h.usecase.GetPetByID(ctx, req.GetPetId())
+	if err != nil {
+		log.Printf("Pet Service | Error during GetPetByID usecase call for availability of ID %s: %v", req.GetPetId(), err)
+		if err.Error() == "pet not found" {
+			return nil, status.Errorf(codes.NotFound, "Pet not found")
+		}
+		return nil, InternalError(ctx, err, "Failed to check pet availability")
+	}
+
+	return &pb.CheckPetAvailabilityResponse{
+		AdoptionStatus: domainAdoptionStatusToPb(pet.AdoptionStatus),
+		Available:      pet.AdoptionStatus == domain.StatusAvailable,
+	}, nil
+}
+
+func (h *PetHandler) BatchGetPets(ctx context.Context, req *pb.BatchGetPetsRequest) (*pb.BatchGetPetsResponse, error) {
+	log.Printf("Pet Service | gRPC BatchGetPets request received for %d IDs", len(req.GetIds()))
+
+	domainPets, err := h.usecase.BatchGetPets(ctx, req.GetIds())
+	if err != nil {
+		if errors.Is(err, usecase.ErrTooManyPetIDs) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		log.Printf("Pet Service | Error during BatchGetPets usecase call: %v", err)
+		return nil, InternalError(ctx, err, "Failed to get pets")
+	}
+
+	pbPets := make([]*pb.Pet, len(domainPets))
+	for i, dp := range domainPets {
+		pbPets[i] = h.petToPb(dp)
+	}
+	return &pb.BatchGetPetsResponse{Pets: pbPets}, nil
 }
 
 func (h *PetHandler) UpdatePet(ctx context.Context, req *pb.UpdatePetRequest) (*pb.PetResponse, error) {
@@ -156,35 +313,43 @@ func (h *PetHandler) UpdatePet(ctx context.Context, req *pb.UpdatePetRequest) (*
 		reqData.Species = &species
 	}
 	
-	breed := req.GetBreed() 
-	reqData.Breed = &breed  
+	if req.Breed != nil { // Presence matters: an explicit empty string clears the breed
+		breed := req.GetBreed()
+		reqData.Breed = &breed
+	}
 
-	if req.GetAge() != 0 { 
+	if req.Age != nil { // Presence matters: 0 is a valid age, e.g. for a puppy
 		age := req.GetAge()
 		reqData.Age = &age
 	}
-	if req.GetDescription() != "" {
+	if req.Description != nil { // Presence matters: an explicit empty string clears the description
 		desc := req.GetDescription()
 		reqData.Description = &desc
 	}
 	
-	if reqData.Name == nil && reqData.Species == nil && reqData.Breed == nil && reqData.Age == nil && reqData.Description == nil && req.ImageUrls == nil {
-		 log.Println("Pet Service | UpdatePet: No fields provided for update")
-		 return nil, status.Errorf(codes.InvalidArgument, "At least one field must be provided for update")
-	}
-
-
-	updatedPet, err := h.usecase.UpdatePet(ctx, req.GetPetId(), reqData)
+	updatedPet, err := h.usecase.UpdatePet(ctx, req.GetPetId(), reqData, callerUserID(ctx))
 	if err != nil {
 		log.Printf("Pet Service | Error during UpdatePet usecase call for ID %s: %v", req.GetPetId(), err)
+		if errors.Is(err, usecase.ErrNotPetOwner) {
+			return nil, status.Error(codes.PermissionDenied, "Only the user who listed this pet can update it")
+		}
+		if errors.Is(err, usecase.ErrNoFieldsToUpdate) {
+			return nil, status.Error(codes.InvalidArgument, "At least one field must be provided for update")
+		}
+		if errors.Is(err, usecase.ErrTooManyImages) || errors.Is(err, usecase.ErrInvalidImageURL) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		if errors.Is(err, repository.ErrConcurrentModification) {
+			return nil, status.Errorf(codes.Aborted, "Concurrent modification: the pet was updated by another request, reload and try again")
+		}
 		if err.Error() == "pet not found for update" || err.Error() == "pet not found" {
 			return nil, status.Errorf(codes.NotFound, "Pet not found for update")
 		}
-		return nil, status.Errorf(codes.Internal, "Failed to update pet: %v", err)
+		return nil, InternalError(ctx, err, "Failed to update pet")
 	}
 
 	log.Printf("Pet Service | Pet updated successfully via gRPC: %s (ID: %s)", updatedPet.Name, updatedPet.ID)
-	return &pb.PetResponse{Pet: domainPetToPbPet(updatedPet)}, nil
+	return &pb.PetResponse{Pet: h.petToPb(updatedPet)}, nil
 }
 
 func (h *PetHandler) DeletePet(ctx context.Context, req *pb.DeletePetRequest) (*pb.EmptyResponse, error) {
@@ -194,48 +359,133 @@ func (h *PetHandler) DeletePet(ctx context.Context, req *pb.DeletePetRequest) (*
 		return nil, status.Errorf(codes.InvalidArgument, "Pet ID is required for deletion")
 	}
 
-	err := h.usecase.DeletePet(ctx, req.GetPetId())
+	err := h.usecase.DeletePet(ctx, req.GetPetId(), callerUserID(ctx))
 	if err != nil {
 		log.Printf("Pet Service | Error during DeletePet usecase call for ID %s: %v", req.GetPetId(), err)
+		if errors.Is(err, usecase.ErrNotPetOwner) {
+			return nil, status.Error(codes.PermissionDenied, "Only the user who listed this pet can delete it")
+		}
 		if err.Error() == "pet not found for deletion" || err.Error() == "pet not found" {
 			return nil, status.Errorf(codes.NotFound, "Pet not found for deletion")
 		}
-		return nil, status.Errorf(codes.Internal, "Failed to delete pet: %v", err)
+		return nil, InternalError(ctx, err, "Failed to delete pet")
 	}
 
 	log.Printf("Pet Service | Pet deleted successfully via gRPC: ID %s", req.GetPetId())
 	return &pb.EmptyResponse{}, nil
 }
 
+// RestorePet undoes DeletePet. The caller must have listed the pet or be an admin.
+func (h *PetHandler) RestorePet(ctx context.Context, req *pb.RestorePetRequest) (*pb.PetResponse, error) {
+	callerID := callerUserID(ctx)
+	log.Printf("Pet Service | gRPC RestorePet request received for ID: %s, Caller: %s", req.GetPetId(), callerID)
+
+	if req.GetPetId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Pet ID is required for restore")
+	}
+
+	restoredPet, err := h.usecase.RestorePet(ctx, req.GetPetId(), callerID, callerHasRole(ctx, roleAdmin))
+	if err != nil {
+		log.Printf("Pet Service | Error during RestorePet usecase call for ID %s: %v", req.GetPetId(), err)
+		switch {
+		case errors.Is(err, usecase.ErrNotPetOwner):
+			return nil, status.Error(codes.PermissionDenied, "Only the user who listed this pet or an admin can restore it")
+		case errors.Is(err, usecase.ErrPetNotDeleted):
+			return nil, status.Error(codes.FailedPrecondition, "Pet is not deleted")
+		case err.Error() == "pet not found":
+			return nil, status.Errorf(codes.NotFound, "Pet not found")
+		}
+		return nil, InternalError(ctx, err, "Failed to restore pet")
+	}
+
+	log.Printf("Pet Service | Pet restored successfully via gRPC: ID %s", req.GetPetId())
+	return &pb.PetResponse{Pet: h.petToPb(restoredPet)}, nil
+}
+
+// listedSpecies merges the species_filter and species_filters of a ListPets request, without
+// empty or repeated values.
+func listedSpecies(req *pb.ListPetsRequest) []string {
+	var species []string
+	for _, s := range append([]string{req.GetSpeciesFilter()}, req.GetSpeciesFilters()...) {
+		if s != "" && !slices.Contains(species, s) {
+			species = append(species, s)
+		}
+	}
+	return species
+}
+
 func (h *PetHandler) ListPets(ctx context.Context, req *pb.ListPetsRequest) (*pb.ListPetsResponse, error) {
-	log.Printf("Pet Service | gRPC ListPets request received. Page: %d, Limit: %d, SpeciesFilter: %s, StatusFilter: %s",
-		req.GetPage(), req.GetLimit(), req.GetSpeciesFilter(), req.GetStatusFilter().String())
+	log.Printf("Pet Service | gRPC ListPets request received. Page: %d, Limit: %d, SpeciesFilter: %v, StatusFilter: %s",
+		req.GetPage(), req.GetLimit(), listedSpecies(req), req.GetStatusFilter().String())
 
 	page := int(req.GetPage())
 	limit := int(req.GetLimit())
-	if page == 0 { page = 1 } 
+	if page == 0 && req.Cursor == nil { page = 1 } // A cursor replaces pages; an empty cursor starts from the first pet
 	if limit == 0 { limit = 10 } 
 
 	filters := make(map[string]interface{})
-	if req.GetSpeciesFilter() != "" {
-		filters["species"] = req.GetSpeciesFilter()
+	if species := listedSpecies(req); len(species) > 0 {
+		filters[repository.FilterSpecies] = species
 	}
 	if req.GetStatusFilter() != pb.AdoptionStatus_ADOPTION_STATUS_UNSPECIFIED {
 		filters["adoption_status"] = pbAdoptionStatusToDomain(req.GetStatusFilter())
 	}
+	if req.GetListedByUserIdFilter() != "" {
+		filters["listed_by_user_id"] = req.GetListedByUserIdFilter()
+	}
+	if len(req.GetTagsFilter()) > 0 {
+		filters[repository.FilterTags] = req.GetTagsFilter()
+		filters[repository.FilterTagsMatchAll] = req.GetMatchAllTags()
+	}
+	if req.GetSort() != "" {
+		filters[repository.FilterSort] = req.GetSort()
+	}
+	if req.GetSearchQuery() != "" {
+		filters[repository.FilterSearch] = req.GetSearchQuery()
+	}
+	if req.MinAge != nil {
+		filters[repository.FilterMinAge] = req.GetMinAge()
+	}
+	if req.MaxAge != nil {
+		filters[repository.FilterMaxAge] = req.GetMaxAge()
+	}
+	if req.Cursor != nil {
+		filters[repository.FilterAfter] = req.GetCursor()
+	}
+	if req.CreatedBefore != nil {
+		createdBefore, err := time.Parse(time.RFC3339, req.GetCreatedBefore())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "created_before must be an RFC 3339 timestamp")
+		}
+		filters[repository.FilterCreatedBefore] = createdBefore
+	}
+	if req.GetIncludeDeleted() {
+		if !callerHasRole(ctx, roleAdmin) {
+			return nil, status.Error(codes.PermissionDenied, "Admin role is required to list deleted pets")
+		}
+		filters[repository.FilterIncludeDeleted] = true
+	}
 
 	domainPets, totalCount, err := h.usecase.ListPets(ctx, page, limit, filters)
 	if err != nil {
 		log.Printf("Pet Service | Error during ListPets usecase call: %v", err)
-		if err.Error() == "invalid adoption_status filter value" {
+		if err.Error() == "invalid adoption_status filter value" || err.Error() == "invalid sort value" ||
+			errors.Is(err, usecase.ErrInvalidCursor) || errors.Is(err, usecase.ErrCursorWithPage) ||
+			errors.Is(err, usecase.ErrInvalidAgeRange) {
 			return nil, status.Errorf(codes.InvalidArgument, err.Error())
 		}
-		return nil, status.Errorf(codes.Internal, "Failed to list pets: %v", err)
+		return nil, InternalError(ctx, err, "Failed to list pets")
 	}
 
 	pbPets := make([]*pb.Pet, len(domainPets))
 	for i, dp := range domainPets {
-		pbPets[i] = domainPetToPbPet(dp)
+		pbPets[i] = h.petToPb(dp)
+	}
+
+	// A full cursor page may be followed by more pets.
+	var nextCursor string
+	if req.Cursor != nil && len(domainPets) == limit && limit > 0 {
+		nextCursor = repository.EncodePageCursor(domainPets[len(domainPets)-1])
 	}
 
 	log.Printf("Pet Service | Listed %d pets, total available: %d", len(pbPets), totalCount)
@@ -244,6 +494,7 @@ func (h *PetHandler) ListPets(ctx context.Context, req *pb.ListPetsRequest) (*pb
 		TotalCount: int32(totalCount), 
 		Page:       int32(page),
 		Limit:      int32(limit),
+		NextCursor: nextCursor,
 	}, nil
 }
 
@@ -265,9 +516,12 @@ func (h *PetHandler) UpdatePetAdoptionStatus(ctx context.Context, req *pb.Update
 		adopterIDPtr = &adopterID
 	}
 
-	updatedPet, err := h.usecase.UpdatePetAdoptionStatus(ctx, req.GetPetId(), domainStatus, adopterIDPtr)
+	updatedPet, err := h.usecase.UpdatePetAdoptionStatus(ctx, req.GetPetId(), domainStatus, adopterIDPtr, req.GetReason(), callerUserID(ctx))
 	if err != nil {
 		log.Printf("Pet Service | Error during UpdatePetAdoptionStatus usecase call for ID %s: %v", req.GetPetId(), err)
+		if errors.Is(err, repository.ErrConcurrentModification) {
+			return nil, status.Errorf(codes.Aborted, "Concurrent modification: the pet's status was changed by another request, reload and try again")
+		}
 		if errors.Is(err, errors.New("pet not found for status update")) || errors.Is(err, errors.New("pet not found")) {
 			return nil, status.Errorf(codes.NotFound, "Pet not found for status update")
 		}
@@ -277,9 +531,215 @@ func (h *PetHandler) UpdatePetAdoptionStatus(ctx context.Context, req *pb.Update
 		if errors.Is(err, errors.New("adopter user ID is required when setting status to ADOPTED")) {
 			return nil, status.Errorf(codes.InvalidArgument, err.Error())
 		}
-		return nil, status.Errorf(codes.Internal, "Failed to update pet adoption status: %v", err)
+		if errors.Is(err, usecase.ErrStatusReasonTooLong) {
+			return nil, status.Errorf(codes.InvalidArgument, err.Error())
+		}
+		return nil, InternalError(ctx, err, "Failed to update pet adoption status")
 	}
 
 	log.Printf("Pet Service | Pet adoption status updated successfully via gRPC for ID: %s", updatedPet.ID)
-	return &pb.PetResponse{Pet: domainPetToPbPet(updatedPet)}, nil
-}
\ No newline at end of file
+	return &pb.PetResponse{Pet: h.petToPb(updatedPet)}, nil
+}
+func (h *PetHandler) GetImageUploadURL(ctx context.Context, req *pb.GetImageUploadURLRequest) (*pb.ImageUploadTarget, error) {
+	log.Printf("Pet Service | gRPC GetImageUploadURL request received for pet ID: %s, ContentType: %s", req.GetPetId(), req.GetContentType())
+
+	if req.GetPetId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Pet ID is required")
+	}
+	if req.GetContentType() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Content type is required")
+	}
+
+	target, err := h.usecase.GetImageUploadTarget(ctx, req.GetPetId(), req.GetFilename(), req.GetContentType(), callerUserID(ctx))
+	if err != nil {
+		log.Printf("Pet Service | Error during GetImageUploadTarget usecase call for pet ID %s: %v", req.GetPetId(), err)
+		if errors.Is(err, usecase.ErrNotPetOwner) {
+			return nil, status.Error(codes.PermissionDenied, "Only the user who listed this pet can upload its images")
+		}
+		switch err.Error() {
+		case "pet not found":
+			return nil, status.Errorf(codes.NotFound, "Pet not found")
+		case "unsupported image content type":
+			return nil, status.Errorf(codes.InvalidArgument, err.Error())
+		case "image storage is not configured":
+			return nil, status.Errorf(codes.Unimplemented, err.Error())
+		}
+		return nil, InternalError(ctx, err, "Failed to create image upload target")
+	}
+
+	return &pb.ImageUploadTarget{
+		Url:       target.URL,
+		Fields:    target.Fields,
+		PublicUrl: target.PublicURL,
+		ExpiresAt: target.ExpiresAt.Format(time.RFC3339),
+	}, nil
+}
+
+func (h *PetHandler) AddImageURLs(ctx context.Context, req *pb.AddImageURLsRequest) (*pb.PetResponse, error) {
+	log.Printf("Pet Service | gRPC AddImageURLs request received for pet ID: %s (%d URLs)", req.GetPetId(), len(req.GetImageUrls()))
+
+	if req.GetPetId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Pet ID is required")
+	}
+	if len(req.GetImageUrls()) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "At least one image URL is required")
+	}
+
+	updatedPet, err := h.usecase.AddImageURLs(ctx, req.GetPetId(), req.GetImageUrls(), callerUserID(ctx))
+	if err != nil {
+		log.Printf("Pet Service | Error during AddImageURLs usecase call for pet ID %s: %v", req.GetPetId(), err)
+		switch err.Error() {
+		case "pet not found":
+			return nil, status.Errorf(codes.NotFound, "Pet not found")
+		case "invalid image URL", "at least one image URL is required":
+			return nil, status.Errorf(codes.InvalidArgument, err.Error())
+		case "image storage is not configured":
+			return nil, status.Errorf(codes.Unimplemented, err.Error())
+		}
+		if errors.Is(err, usecase.ErrNotPetOwner) {
+			return nil, status.Error(codes.PermissionDenied, "Only the user who listed this pet can add its images")
+		}
+		if errors.Is(err, usecase.ErrTooManyImages) {
+			return nil, status.Errorf(codes.InvalidArgument, err.Error())
+		}
+		return nil, InternalError(ctx, err, "Failed to add image URLs")
+	}
+
+	log.Printf("Pet Service | Image URLs added successfully via gRPC for pet ID: %s", updatedPet.ID)
+	return &pb.PetResponse{Pet: h.petToPb(updatedPet)}, nil
+}
+
+func (h *PetHandler) ListRecentlyAdopted(ctx context.Context, req *pb.ListRecentlyAdoptedRequest) (*pb.ListRecentlyAdoptedResponse, error) {
+	log.Printf("Pet Service | gRPC ListRecentlyAdopted request received. Limit: %d", req.GetLimit())
+
+	domainPets, err := h.usecase.ListRecentlyAdopted(ctx, int(req.GetLimit()))
+	if err != nil {
+		log.Printf("Pet Service | Error during ListRecentlyAdopted usecase call: %v", err)
+		return nil, InternalError(ctx, err, "Failed to list recently adopted pets")
+	}
+
+	pbPets := make([]*pb.Pet, len(domainPets))
+	for i, dp := range domainPets {
+		pbPets[i] = h.petToPb(dp)
+	}
+	return &pb.ListRecentlyAdoptedResponse{Pets: pbPets}, nil
+}
+
+func (h *PetHandler) SuggestBreeds(ctx context.Context, req *pb.SuggestBreedsRequest) (*pb.SuggestBreedsResponse, error) {
+	log.Printf("Pet Service | gRPC SuggestBreeds request received. Species: %s, Prefix: %s", req.GetSpecies(), req.GetPrefix())
+
+	breeds, err := h.usecase.SuggestBreeds(ctx, req.GetSpecies(), req.GetPrefix(), int(req.GetLimit()))
+	if err != nil {
+		if errors.Is(err, usecase.ErrSpeciesRequired) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		log.Printf("Pet Service | Error during SuggestBreeds usecase call: %v", err)
+		return nil, InternalError(ctx, err, "Failed to suggest breeds")
+	}
+	return &pb.SuggestBreedsResponse{Breeds: breeds}, nil
+}
+
+func domainFacetCountsToPb(counts []domain.FacetCount) []*pb.FacetCount {
+	out := make([]*pb.FacetCount, len(counts))
+	for i, fc := range counts {
+		out[i] = &pb.FacetCount{Value: fc.Value, Count: fc.Count}
+	}
+	return out
+}
+
+func (h *PetHandler) GetPetFacets(ctx context.Context, req *pb.GetPetFacetsRequest) (*pb.PetFacetsResponse, error) {
+	log.Println("Pet Service | gRPC GetPetFacets request received.")
+
+	facets, err := h.usecase.GetPetFacets(ctx)
+	if err != nil {
+		log.Printf("Pet Service | Error during GetPetFacets usecase call: %v", err)
+		return nil, InternalError(ctx, err, "Failed to get pet facets")
+	}
+	return &pb.PetFacetsResponse{
+		Species:          domainFacetCountsToPb(facets.Species),
+		Breeds:           domainFacetCountsToPb(facets.Breeds),
+		AdoptionStatuses: domainFacetCountsToPb(facets.AdoptionStatuses),
+	}, nil
+}
+
+func (h *PetHandler) AddPetTags(ctx context.Context, req *pb.PetTagsRequest) (*pb.PetResponse, error) {
+	log.Printf("Pet Service | gRPC AddPetTags request received for pet ID: %s (%d tags)", req.GetPetId(), len(req.GetTags()))
+	return h.updatePetTags(ctx, req, h.usecase.AddPetTags, "Failed to add pet tags")
+}
+
+func (h *PetHandler) RemovePetTags(ctx context.Context, req *pb.PetTagsRequest) (*pb.PetResponse, error) {
+	log.Printf("Pet Service | gRPC RemovePetTags request received for pet ID: %s (%d tags)", req.GetPetId(), len(req.GetTags()))
+	return h.updatePetTags(ctx, req, h.usecase.RemovePetTags, "Failed to remove pet tags")
+}
+
+func (h *PetHandler) updatePetTags(ctx context.Context, req *pb.PetTagsRequest, apply func(context.Context, string, []string, string) (*domain.Pet, error), failureMsg string) (*pb.PetResponse, error) {
+	if req.GetPetId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Pet ID is required")
+	}
+
+	updatedPet, err := apply(ctx, req.GetPetId(), req.GetTags(), callerUserID(ctx))
+	if err != nil {
+		log.Printf("Pet Service | Error updating tags for pet ID %s: %v", req.GetPetId(), err)
+		if errors.Is(err, usecase.ErrNotPetOwner) {
+			return nil, status.Error(codes.PermissionDenied, "Only the user who listed this pet can change its tags")
+		}
+		switch err.Error() {
+		case "pet not found":
+			return nil, status.Errorf(codes.NotFound, "Pet not found")
+		case "at least one valid tag is required":
+			return nil, status.Errorf(codes.InvalidArgument, err.Error())
+		}
+		return nil, InternalError(ctx, err, failureMsg)
+	}
+	return &pb.PetResponse{Pet: h.petToPb(updatedPet)}, nil
+}
+
+func (h *PetHandler) AdminSetPetStatus(ctx context.Context, req *pb.AdminSetPetStatusRequest) (*pb.PetResponse, error) {
+	log.Printf("Pet Service | gRPC AdminSetPetStatus request for ID: %s, NewStatus: %s, ChangedBy: %s", req.GetPetId(), req.GetNewStatus().String(), req.GetChangedBy())
+
+	if !callerHasRole(ctx, roleAdmin) {
+		return nil, status.Errorf(codes.PermissionDenied, "Admin role is required to override a pet's status")
+	}
+	if req.GetPetId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Pet ID is required")
+	}
+
+	updatedPet, err := h.usecase.AdminSetPetStatus(ctx, req.GetPetId(), pbAdoptionStatusToDomain(req.GetNewStatus()), req.GetReason(), req.GetChangedBy())
+	if err != nil {
+		log.Printf("Pet Service | Error during AdminSetPetStatus usecase call for ID %s: %v", req.GetPetId(), err)
+		switch err.Error() {
+		case "pet not found", "pet not found for status update":
+			return nil, status.Errorf(codes.NotFound, "Pet not found")
+		case "invalid new adoption status", "a reason is required for an admin status override":
+			return nil, status.Errorf(codes.InvalidArgument, err.Error())
+		}
+		return nil, InternalError(ctx, err, "Failed to override pet status")
+	}
+	return &pb.PetResponse{Pet: h.petToPb(updatedPet)}, nil
+}
+
+func (h *PetHandler) TransferPetListing(ctx context.Context, req *pb.TransferPetListingRequest) (*pb.PetResponse, error) {
+	callerID := callerUserID(ctx)
+	log.Printf("Pet Service | gRPC TransferPetListing request for ID: %s, NewOwner: %s, Caller: %s", req.GetPetId(), req.GetNewOwnerUserId(), callerID)
+
+	if req.GetPetId() == "" || req.GetNewOwnerUserId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Pet ID and new owner user ID are required")
+	}
+
+	updatedPet, err := h.usecase.TransferPetListing(ctx, req.GetPetId(), req.GetNewOwnerUserId(), callerID, callerHasRole(ctx, roleAdmin))
+	if err != nil {
+		log.Printf("Pet Service | Error during TransferPetListing usecase call for ID %s: %v", req.GetPetId(), err)
+		switch {
+		case errors.Is(err, usecase.ErrListingTransferForbidden):
+			return nil, status.Errorf(codes.PermissionDenied, "Only the current owner or an admin can transfer this listing")
+		case errors.Is(err, usecase.ErrNewOwnerNotFound):
+			return nil, status.Errorf(codes.NotFound, "New owner user not found")
+		case errors.Is(err, repository.ErrConcurrentModification):
+			return nil, status.Errorf(codes.Aborted, "Concurrent modification: the pet was updated by another request, reload and try again")
+		case err.Error() == "pet not found":
+			return nil, status.Errorf(codes.NotFound, "Pet not found")
+		}
+		return nil, InternalError(ctx, err, "Failed to transfer pet listing")
+	}
+	return &pb.PetResponse{Pet: h.petToPb(updatedPet)}, nil
+}
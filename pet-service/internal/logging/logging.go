@@ -0,0 +1,80 @@
+// Package logging adds levels on top of the standard logger, so chatty per-request messages
+// can be turned on for debugging without flooding the logs in production.
+package logging
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync/atomic"
+)
+
+// Level is the minimum severity that is logged.
+type Level int32
+
+const (
+	LevelDebug Level = iota // Per-request detail, e.g. cache hits and misses
+	LevelInfo               // Normal operation; the default
+	LevelWarn               // Recoverable problems, e.g. a failing cache
+)
+
+var current atomic.Int32
+
+func init() {
+	current.Store(int32(LevelInfo))
+}
+
+// ParseLevel parses "debug", "info" or "warn" (case-insensitive).
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return LevelDebug, nil
+	case "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	}
+	return LevelInfo, fmt.Errorf("unknown log level %q", s)
+}
+
+// String returns the name accepted by ParseLevel.
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelWarn:
+		return "warn"
+	}
+	return "info"
+}
+
+// SetLevel sets the minimum level that is logged.
+func SetLevel(l Level) {
+	current.Store(int32(l))
+}
+
+// Enabled reports whether messages at level l are logged.
+func Enabled(l Level) bool {
+	return l >= Level(current.Load())
+}
+
+// Debugf logs a debug message with the service prefix.
+func Debugf(format string, args ...interface{}) {
+	if Enabled(LevelDebug) {
+		log.Printf("Pet Service | "+format, args...)
+	}
+}
+
+// Infof logs an informational message with the service prefix.
+func Infof(format string, args ...interface{}) {
+	if Enabled(LevelInfo) {
+		log.Printf("Pet Service | "+format, args...)
+	}
+}
+
+// Warnf logs a warning with the service prefix.
+func Warnf(format string, args ...interface{}) {
+	if Enabled(LevelWarn) {
+		log.Printf("Pet Service | Warning: "+format, args...)
+	}
+}
@@ -0,0 +1,107 @@
+// Package metrics keeps in-process counters and serves them in the Prometheus text format.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// Cache names used as the "cache" label of the cache counters.
+const (
+	CachePet    = "pet"
+	CacheFacets = "facets"
+	CacheList   = "list"
+)
+
+var (
+	// CacheHits counts cache-aside lookups answered from Redis, by cache.
+	CacheHits = NewCounterVec("pet_service_cache_hits_total", "Cache lookups answered from the cache.", "cache")
+	// CacheMisses counts cache-aside lookups that fell through to MongoDB, by cache.
+	// Lookups that failed because Redis was unavailable count as misses.
+	CacheMisses = NewCounterVec("pet_service_cache_misses_total", "Cache lookups that fell through to the database.", "cache")
+)
+
+// CounterVec is a set of monotonic counters sharing a name, told apart by the value of one label,
+// like a Prometheus counter vector. A nil *CounterVec ignores increments.
+type CounterVec struct {
+	name  string
+	help  string
+	label string
+
+	mu     sync.Mutex
+	values map[string]uint64 // Keyed by label value
+}
+
+// NewCounterVec creates a counter vector whose series are labelled with label.
+func NewCounterVec(name, help, label string) *CounterVec {
+	return &CounterVec{name: name, help: help, label: label, values: make(map[string]uint64)}
+}
+
+// Inc adds one to the counter with the given label value.
+func (v *CounterVec) Inc(labelValue string) {
+	if v == nil {
+		return
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.values[labelValue]++
+}
+
+// Value returns the current count for the given label value.
+func (v *CounterVec) Value(labelValue string) uint64 {
+	if v == nil {
+		return 0
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.values[labelValue]
+}
+
+// WriteTo writes the counters in the Prometheus text exposition format, ordered by label value.
+func (v *CounterVec) WriteTo(w io.Writer) (int64, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	var written int64
+	write := func(format string, args ...interface{}) error {
+		n, err := fmt.Fprintf(w, format, args...)
+		written += int64(n)
+		return err
+	}
+	if err := write("# HELP %s %s\n# TYPE %s counter\n", v.name, v.help, v.name); err != nil {
+		return written, err
+	}
+	labelValues := make([]string, 0, len(v.values))
+	for labelValue := range v.values {
+		labelValues = append(labelValues, labelValue)
+	}
+	sort.Strings(labelValues)
+	for _, labelValue := range labelValues {
+		if err := write("%s{%s=%q} %d\n", v.name, v.label, labelValue, v.values[labelValue]); err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// Handler serves the given counter vectors in the Prometheus text format. Nil vectors are skipped.
+func Handler(vecs ...*CounterVec) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		for _, v := range vecs {
+			if v == nil {
+				continue
+			}
+			if _, err := v.WriteTo(w); err != nil {
+				return
+			}
+		}
+	})
+}
@@ -0,0 +1,73 @@
+// Package migrate prepares the pets database ahead of deployment: it ensures the collection's
+// indexes and can seed sample pets, so running instances can start with ENSURE_INDEXES=false.
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/zhandarbeks/petstore-final-project/pet-service/internal/domain"
+	"github.com/zhandarbeks/petstore-final-project/pet-service/internal/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Options selects the optional migration steps.
+type Options struct {
+	Seed bool // Insert the sample pets in SeedPets if they don't exist yet
+}
+
+// SeedPets are sample listings for local development. They have fixed IDs so seeding is idempotent.
+var SeedPets = []domain.Pet{
+	{ID: "seed-pet-1", Name: "Biscuit", Species: "Dog", Breed: "Beagle", Age: 3, Description: "Friendly and food-motivated.", Tags: []string{"good with kids"}},
+	{ID: "seed-pet-2", Name: "Misty", Species: "Cat", Breed: "Siamese", Age: 5, Description: "Calm indoor cat.", Tags: []string{"indoor"}},
+	{ID: "seed-pet-3", Name: "Pepper", Species: "Rabbit", Breed: "Mini Lop", Age: 1, Description: "Curious and gentle."},
+}
+
+// Run ensures the indexes of the pets collection and, if requested, seeds sample pets.
+func Run(ctx context.Context, db *mongo.Database, collectionName string, opts Options) error {
+	collection := db.Collection(collectionName)
+
+	if err := repository.EnsureIndexes(ctx, collection.Indexes()); err != nil {
+		return fmt.Errorf("ensure indexes on %s: %w", collectionName, err)
+	}
+	log.Printf("Pet Service | Migrate: indexes ensured for collection %s", collectionName)
+
+	if !opts.Seed {
+		return nil
+	}
+	inserted, err := seedPets(ctx, collection)
+	if err != nil {
+		return fmt.Errorf("seed %s: %w", collectionName, err)
+	}
+	log.Printf("Pet Service | Migrate: seeded %d of %d sample pets into %s", inserted, len(SeedPets), collectionName)
+	return nil
+}
+
+// seedPets upserts SeedPets with $setOnInsert, leaving pets that already exist untouched.
+func seedPets(ctx context.Context, collection *mongo.Collection) (int, error) {
+	inserted := 0
+	for _, pet := range SeedPets {
+		pet := pet
+		now := time.Now().UTC()
+		pet.AdoptionStatus = domain.StatusAvailable
+		pet.Tags = domain.NormalizeTags(pet.Tags)
+		pet.CreatedAt = now
+		pet.UpdatedAt = now
+
+		res, err := collection.UpdateOne(ctx,
+			bson.M{"_id": pet.ID},
+			bson.M{"$setOnInsert": pet},
+			options.Update().SetUpsert(true))
+		if err != nil {
+			return inserted, err
+		}
+		if res.UpsertedCount > 0 {
+			inserted++
+		}
+	}
+	return inserted, nil
+}
@@ -0,0 +1,75 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/zhandarbeks/petstore-final-project/internal/eventbus"
+	"github.com/zhandarbeks/petstore-final-project/pet-service/internal/domain"
+)
+
+// PetEventPublisher defines the interface for publishing pet-related events.
+type PetEventPublisher interface {
+	PublishPetUnavailable(ctx context.Context, pet *domain.Pet) error
+	Close()
+}
+
+// natsPetPublisher is the NATS implementation of PetEventPublisher.
+type natsPetPublisher struct {
+	nc        *nats.Conn
+	namespace eventbus.Namespace // Environment prefix applied to every subject
+}
+
+// NewNATSPetPublisher creates a new NATS publisher for pet events, published on the subjects of
+// the given namespace.
+func NewNATSPetPublisher(natsURL string, namespace eventbus.Namespace) (PetEventPublisher, error) {
+	nc, err := nats.Connect(natsURL, nats.Timeout(5*time.Second), nats.RetryOnFailedConnect(true), nats.MaxReconnects(3))
+	if err != nil {
+		log.Printf("Pet Service | Error connecting to NATS at %s: %v", natsURL, err)
+		return nil, err
+	}
+	log.Printf("Pet Service | Successfully connected to NATS at %s", natsURL)
+	return &natsPetPublisher{nc: nc, namespace: namespace}, nil
+}
+
+// PublishPetUnavailable publishes an event when a pet has been adopted, so users who applied for it can be told.
+func (p *natsPetPublisher) PublishPetUnavailable(ctx context.Context, pet *domain.Pet) error {
+	subject := p.namespace.Subject(eventbus.SubjectPetUnavailable)
+	eventData := map[string]interface{}{
+		"event_type":         "PetUnavailable",
+		"pet_id":             pet.ID,
+		"pet_name":           pet.Name,
+		"listed_by_user_id":  pet.ListedByUserID,
+		"adopted_by_user_id": pet.AdoptedByUserID,
+		"status":             pet.AdoptionStatus,
+		"unavailable_at":     pet.UpdatedAt,
+		"published_at":       time.Now().UTC(), // Lets consumers measure end-to-end latency
+	}
+
+	payload, err := json.Marshal(eventData)
+	if err != nil {
+		log.Printf("Pet Service | Error marshalling PetUnavailable event for pet ID %s: %v", pet.ID, err)
+		return err
+	}
+
+	if err = p.nc.Publish(subject, payload); err != nil {
+		log.Printf("Pet Service | Error publishing PetUnavailable event to subject '%s' for pet ID %s: %v", subject, pet.ID, err)
+		return err
+	}
+
+	log.Printf("Pet Service | Published event to '%s' for pet ID: %s", subject, pet.ID)
+	return nil
+}
+
+// Close drains and closes the NATS connection.
+func (p *natsPetPublisher) Close() {
+	if p.nc != nil {
+		log.Println("Pet Service | Draining and closing NATS connection...")
+		p.nc.Drain()
+		p.nc.Close()
+		log.Println("Pet Service | NATS connection closed.")
+	}
+}
@@ -2,28 +2,77 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/zhandarbeks/petstore-final-project/pet-service/internal/domain" // Adjust import path
 )
 
+// ErrConcurrentModification is returned by versioned updates when the pet was changed by someone
+// else since it was read.
+var ErrConcurrentModification = errors.New("concurrent modification")
+
 // PetRepository defines the interface for database operations related to pets.
 type PetRepository interface {
 	CreatePet(ctx context.Context, pet *domain.Pet) (*domain.Pet, error)
-	GetPetByID(ctx context.Context, id string) (*domain.Pet, error)
-	UpdatePet(ctx context.Context, pet *domain.Pet) (*domain.Pet, error)
-	DeletePet(ctx context.Context, id string) error
+	GetPetByID(ctx context.Context, id string) (*domain.Pet, error)                 // Soft-deleted pets are not found
+	GetPetByIDIncludingDeleted(ctx context.Context, id string) (*domain.Pet, error) // Also finds soft-deleted pets
+	GetPetsByIDs(ctx context.Context, ids []string) ([]*domain.Pet, error) // The pets that exist and are not deleted, in no particular order
+	UpdatePet(ctx context.Context, pet *domain.Pet) (*domain.Pet, error) // Only succeeds if pet.Version is current; increments it
+	DeletePet(ctx context.Context, id string) error // Soft delete: marks the pet deleted and keeps the document
+	RestorePet(ctx context.Context, id string) (*domain.Pet, error) // Undoes DeletePet; "pet not found" unless the pet is deleted
 	ListPets(ctx context.Context, page, limit int, filters map[string]interface{}) ([]*domain.Pet, int64, error) // For listing with filters & pagination
-	UpdatePetAdoptionStatus(ctx context.Context, id string, newStatus domain.AdoptionStatus, adopterUserID *string) (*domain.Pet, error)
+	// UpdatePetAdoptionStatus sets the status to change.ToStatus if the pet is still at expectedVersion, and appends change to the status history.
+	UpdatePetAdoptionStatus(ctx context.Context, id string, expectedVersion int, change domain.StatusChange, adopterUserID *string) (*domain.Pet, error)
+	AddPetImageURLs(ctx context.Context, id string, imageURLs []string) (*domain.Pet, error) // Appends URLs without duplicating existing ones
+	ListRecentlyAdopted(ctx context.Context, limit int) ([]*domain.Pet, error)               // ADOPTED pets, most recently updated first
+	GetPetFacets(ctx context.Context) (*domain.PetFacets, error)                             // Distinct species/breed/status values with counts
+	// SuggestBreeds returns up to limit distinct breeds of pets of species starting with prefix, both
+	// matched case-insensitively, in alphabetical order.
+	SuggestBreeds(ctx context.Context, species, prefix string, limit int) ([]string, error)
+	AddPetTags(ctx context.Context, id string, tags []string) (*domain.Pet, error)            // Adds tags that are not already present
+	RemovePetTags(ctx context.Context, id string, tags []string) (*domain.Pet, error)
+	// ForcePetAdoptionStatus sets the status without any business checks and appends change to the status history.
+	ForcePetAdoptionStatus(ctx context.Context, id string, change domain.StatusChange) (*domain.Pet, error)
+	// TransferPetListing moves the listing to transfer.ToUserID if the pet is still at expectedVersion, and appends transfer to the listing history.
+	TransferPetListing(ctx context.Context, id string, expectedVersion int, transfer domain.ListingTransfer) (*domain.Pet, error)
 }
 
+// ListPets filter keys with special handling; any other key is matched by equality.
+const (
+	FilterSpecies        = "species"         // string or []string: pets of the species, or of any of them
+	FilterTags           = "tags"            // []string: pets carrying the given tags
+	FilterTagsMatchAll   = "tags_match_all"  // bool: require every tag in FilterTags (default: any of them)
+	FilterSort           = "sort"            // string: SortNewest or SortOldest; not a filter, sets the result order
+	FilterAfter          = "after"           // *PageCursor: cursor pagination instead of pages; nil starts at the first pet
+	FilterCreatedBefore  = "created_before"  // time.Time: pets created strictly before this time
+	FilterSearch         = "search"          // string: full-text search over name and description
+	FilterMinAge         = "min_age"         // int32: pets at least this old
+	FilterMaxAge         = "max_age"         // int32: pets at most this old
+	FilterIncludeDeleted = "include_deleted" // bool: also match soft-deleted pets, which are left out by default
+)
+
+// Result orders accepted for FilterSort.
+const (
+	SortNewest = "newest" // created_at descending
+	SortOldest = "oldest" // created_at ascending
+)
+
+// ErrCacheMiss is returned (wrapped, e.g. "pet not found in cache") by the PetCache getters when
+// the entry is not cached. It is an expected outcome, unlike the other errors they return.
+var ErrCacheMiss = errors.New("not found in cache")
+
 // PetCache defines the interface for caching operations related to pets.
 type PetCache interface {
 	GetPet(ctx context.Context, id string) (*domain.Pet, error)
 	SetPet(ctx context.Context, id string, pet *domain.Pet, expiration time.Duration) error
 	DeletePet(ctx context.Context, id string) error
-	// Consider methods for caching lists of pets if that's a frequent operation
-	// SetListedPets(ctx context.Context, cacheKey string, pets []*domain.Pet, expiration time.Duration) error
-	// GetListedPets(ctx context.Context, cacheKey string) ([]*domain.Pet, error)
-	// DeleteListedPets(ctx context.Context, cacheKey string) error
+	GetPetFacets(ctx context.Context) (*domain.PetFacets, error)
+	SetPetFacets(ctx context.Context, facets *domain.PetFacets, expiration time.Duration) error
+	DeletePetFacets(ctx context.Context) error
+	// GetListedPets and SetListedPets cache one page of ListPets results, keyed by its page,
+	// limit and filters. DeleteListedPets drops all cached pages.
+	GetListedPets(ctx context.Context, page, limit int, filters map[string]interface{}) ([]*domain.Pet, int64, error)
+	SetListedPets(ctx context.Context, page, limit int, filters map[string]interface{}, pets []*domain.Pet, totalCount int64, expiration time.Duration) error
+	DeleteListedPets(ctx context.Context) error
 }
\ No newline at end of file
@@ -4,6 +4,9 @@ import (
 	"context"
 	"errors"
 	"log"
+	"regexp"
+	"sort"
+	"strings"
 	"time" // Added import for time
 
 	"github.com/zhandarbeks/petstore-final-project/pet-service/internal/domain" // Adjust import path
@@ -19,8 +22,41 @@ type mongoPetRepository struct {
 	collection *mongo.Collection
 }
 
+// IndexCreator creates indexes on the pets collection. mongo.IndexView satisfies it;
+// tests substitute their own to observe index creation.
+type IndexCreator interface {
+	CreateMany(ctx context.Context, models []mongo.IndexModel, opts ...*options.CreateIndexesOptions) ([]string, error)
+}
+
+// IndexOptions controls index creation when the repository is constructed.
+type IndexOptions struct {
+	EnsureIndexes bool         // Create indexes on startup; disable when migrations manage them or on read-only replicas
+	Creator       IndexCreator // Nil uses the collection's index view
+}
+
+// EnsureIndexes creates the pets collection's indexes. It is shared by repository startup and
+// the migrate command so both use the same definitions; creating an existing index is a no-op.
+func EnsureIndexes(ctx context.Context, creator IndexCreator) error {
+	_, err := creator.CreateMany(ctx, petIndexModels())
+	return err
+}
+
+// petIndexModels lists the indexes for the pets collection.
+func petIndexModels() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{Keys: bson.D{{Key: "species", Value: 1}}},
+		{Keys: bson.D{{Key: "adoption_status", Value: 1}}},
+		{Keys: bson.D{{Key: "age", Value: 1}}},
+		{Keys: bson.D{{Key: "adoption_status", Value: 1}, {Key: "updated_at", Value: -1}}}, // For the recently-adopted showcase
+		{Keys: bson.D{{Key: "tags", Value: 1}}}, // Multikey index for tag filtering
+		{Keys: bson.D{{Key: "adoption_status", Value: 1}, {Key: "created_at", Value: -1}}}, // For browsing available pets newest-first
+		{Keys: bson.D{{Key: "name", Value: "text"}, {Key: "description", Value: "text"}}}, // For FilterSearch; a collection can have only one text index
+		// Add more indexes based on common query patterns
+	}
+}
+
 // NewMongoDBPetRepository creates a new instance of mongoPetRepository.
-func NewMongoDBPetRepository(ctx context.Context, uri, dbName, collectionName string) (PetRepository, error) {
+func NewMongoDBPetRepository(ctx context.Context, uri, dbName, collectionName string, indexOpts IndexOptions) (PetRepository, error) {
 	clientOptions := options.Client().ApplyURI(uri)
 	client, err := mongo.Connect(ctx, clientOptions)
 	if err != nil {
@@ -37,28 +73,82 @@ func NewMongoDBPetRepository(ctx context.Context, uri, dbName, collectionName st
 	}
 	log.Println("Pet Service | Successfully connected to MongoDB!")
 
+	return NewMongoDBPetRepositoryFromClient(ctx, client, dbName, collectionName, indexOpts), nil
+}
+
+// NewMongoDBPetRepositoryFromClient builds the repository on an already connected client and
+// creates the collection's indexes if indexOpts.EnsureIndexes is set. Index failures are logged, not returned.
+func NewMongoDBPetRepositoryFromClient(ctx context.Context, client *mongo.Client, dbName, collectionName string, indexOpts IndexOptions) PetRepository {
 	db := client.Database(dbName)
 	collection := db.Collection(collectionName)
 
-	// Example indexes for pets collection
-	indexModels := []mongo.IndexModel{
-		{Keys: bson.D{{Key: "species", Value: 1}}},
-		{Keys: bson.D{{Key: "adoption_status", Value: 1}}},
-		{Keys: bson.D{{Key: "age", Value: 1}}},
-		// Add more indexes based on common query patterns
-	}
-	_, err = collection.Indexes().CreateMany(ctx, indexModels)
-	if err != nil {
-		log.Printf("Pet Service | Warning: Could not create indexes for collection %s: %v", collectionName, err)
+	if !indexOpts.EnsureIndexes {
+		log.Printf("Pet Service | Skipping index creation for collection %s (ENSURE_INDEXES=false)", collectionName)
 	} else {
-		log.Printf("Pet Service | Indexes ensured for collection %s", collectionName)
+		creator := indexOpts.Creator
+		if creator == nil {
+			creator = collection.Indexes()
+		}
+		if err := EnsureIndexes(ctx, creator); err != nil {
+			log.Printf("Pet Service | Warning: Could not create indexes for collection %s: %v", collectionName, err)
+		} else {
+			log.Printf("Pet Service | Indexes ensured for collection %s", collectionName)
+		}
 	}
 
 	return &mongoPetRepository{
 		client:     client,
 		db:         db,
 		collection: collection,
-	}, nil
+	}
+}
+
+// NewMongoDBPetRepositoryFromCollection wraps an already connected collection, without pinging
+// or creating indexes. Useful when the client is shared or provided by a test deployment.
+func NewMongoDBPetRepositoryFromCollection(collection *mongo.Collection) PetRepository {
+	return &mongoPetRepository{
+		client:     collection.Database().Client(),
+		db:         collection.Database(),
+		collection: collection,
+	}
+}
+
+// notDeleted matches pets that were not soft-deleted. Pets stored before soft deletes have no
+// deleted field.
+func notDeleted() bson.M {
+	return bson.M{"$ne": true}
+}
+
+// activePetFilter matches the pet with the given ID unless it was soft-deleted.
+func activePetFilter(id string) bson.M {
+	return bson.M{"_id": id, "deleted": notDeleted()}
+}
+
+// versionFilter matches the pet with the given ID and version, unless it was soft-deleted. Pets
+// stored before versioning have no version field and count as version 0.
+func versionFilter(id string, version int) bson.M {
+	filter := activePetFilter(id)
+	if version == 0 {
+		filter["version"] = bson.M{"$in": bson.A{0, nil}}
+	} else {
+		filter["version"] = version
+	}
+	return filter
+}
+
+// versionMismatchError is called after a versioned update matched nothing and tells a missing pet
+// (notFound) apart from a stale version (ErrConcurrentModification).
+func (r *mongoPetRepository) versionMismatchError(ctx context.Context, id string, version int, notFound string) error {
+	count, err := r.collection.CountDocuments(ctx, activePetFilter(id))
+	if err != nil {
+		log.Printf("Pet Service | Error checking pet '%s' after failed update: %v", id, err)
+		return err
+	}
+	if count == 0 {
+		return errors.New(notFound)
+	}
+	log.Printf("Pet Service | Rejected update of pet '%s': version %d is stale", id, version)
+	return ErrConcurrentModification
 }
 
 func (r *mongoPetRepository) Close(ctx context.Context) error {
@@ -84,9 +174,17 @@ func (r *mongoPetRepository) CreatePet(ctx context.Context, pet *domain.Pet) (*d
 }
 
 func (r *mongoPetRepository) GetPetByID(ctx context.Context, id string) (*domain.Pet, error) {
+	return r.findPet(ctx, activePetFilter(id), id)
+}
+
+func (r *mongoPetRepository) GetPetByIDIncludingDeleted(ctx context.Context, id string) (*domain.Pet, error) {
+	return r.findPet(ctx, bson.M{"_id": id}, id)
+}
+
+func (r *mongoPetRepository) findPet(ctx context.Context, filter bson.M, id string) (*domain.Pet, error) {
 	var pet domain.Pet
 	// Assuming _id in MongoDB is stored as the string hex itself, as per domain.Pet.ID
-	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&pet)
+	err := r.collection.FindOne(ctx, filter).Decode(&pet)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return nil, errors.New("pet not found")
@@ -97,6 +195,25 @@ func (r *mongoPetRepository) GetPetByID(ctx context.Context, id string) (*domain
 	return &pet, nil
 }
 
+func (r *mongoPetRepository) GetPetsByIDs(ctx context.Context, ids []string) ([]*domain.Pet, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}, "deleted": notDeleted()})
+	if err != nil {
+		log.Printf("Pet Service | Error getting %d pets by ID from MongoDB: %v", len(ids), err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var pets []*domain.Pet
+	if err = cursor.All(ctx, &pets); err != nil {
+		log.Printf("Pet Service | Error decoding pets fetched by ID from MongoDB: %v", err)
+		return nil, err
+	}
+	return pets, nil
+}
+
 func (r *mongoPetRepository) UpdatePet(ctx context.Context, pet *domain.Pet) (*domain.Pet, error) {
 	if pet.ID == "" {
 		return nil, errors.New("pet ID cannot be empty for update")
@@ -118,17 +235,19 @@ func (r *mongoPetRepository) UpdatePet(ctx context.Context, pet *domain.Pet) (*d
 	}
 	// If you want partial updates for ImageURLs (e.g., add/remove), that would require different logic.
 
-	update := bson.M{"$set": updateFields}
+	update := bson.M{"$set": updateFields, "$inc": bson.M{"version": 1}}
 
-	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": pet.ID}, update)
+	// Only update the version we read, so a concurrent update is not silently overwritten.
+	result, err := r.collection.UpdateOne(ctx, versionFilter(pet.ID, pet.Version), update)
 	if err != nil {
 		log.Printf("Pet Service | Error updating pet '%s' in MongoDB: %v", pet.ID, err)
 		return nil, err
 	}
 
 	if result.MatchedCount == 0 {
-		return nil, errors.New("pet not found for update")
+		return nil, r.versionMismatchError(ctx, pet.ID, pet.Version, "pet not found for update")
 	}
+	pet.Version++
 	return pet, nil // Return the updated pet object
 }
 
@@ -136,17 +255,44 @@ func (r *mongoPetRepository) DeletePet(ctx context.Context, id string) error {
 	if id == "" {
 		return errors.New("pet ID cannot be empty for delete")
 	}
-	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	// Soft delete: the document stays, with its status and listing history, for the adoption
+	// applications that still reference it.
+	now := time.Now().UTC()
+	update := bson.M{
+		"$set": bson.M{"deleted": true, "deleted_at": now, "updated_at": now},
+		"$inc": bson.M{"version": 1},
+	}
+	result, err := r.collection.UpdateOne(ctx, activePetFilter(id), update)
 	if err != nil {
 		log.Printf("Pet Service | Error deleting pet '%s' from MongoDB: %v", id, err)
 		return err
 	}
-	if result.DeletedCount == 0 {
+	if result.MatchedCount == 0 {
 		return errors.New("pet not found for deletion")
 	}
 	return nil
 }
 
+func (r *mongoPetRepository) RestorePet(ctx context.Context, id string) (*domain.Pet, error) {
+	if id == "" {
+		return nil, errors.New("pet ID cannot be empty for restore")
+	}
+	update := bson.M{
+		"$unset": bson.M{"deleted": "", "deleted_at": ""},
+		"$set":   bson.M{"updated_at": time.Now().UTC()},
+		"$inc":   bson.M{"version": 1},
+	}
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id, "deleted": true}, update)
+	if err != nil {
+		log.Printf("Pet Service | Error restoring pet '%s' in MongoDB: %v", id, err)
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, errors.New("pet not found")
+	}
+	return r.GetPetByID(ctx, id)
+}
+
 func (r *mongoPetRepository) ListPets(ctx context.Context, page, limit int, filters map[string]interface{}) ([]*domain.Pet, int64, error) {
 	if page < 1 {
 		page = 1
@@ -155,23 +301,32 @@ func (r *mongoPetRepository) ListPets(ctx context.Context, page, limit int, filt
 		limit = 10 // Default limit
 	}
 	skip := (page - 1) * limit
+	after, cursorPaging := filters[FilterAfter].(*PageCursor)
+	if cursorPaging {
+		skip = 0
+	}
 
 	findOptions := options.Find()
 	findOptions.SetSkip(int64(skip))
 	findOptions.SetLimit(int64(limit))
-	// findOptions.SetSort(bson.D{{"created_at", -1}}) // Example sort by creation date descending
-
-	// Build query from filters
-	query := bson.M{}
-	if filters != nil {
-		for key, value := range filters {
-			// Basic equality filter. For more complex filters (ranges, regex), add more logic.
-			// Ensure filter keys match BSON field names.
-			query[key] = value
+	switch filters[FilterSort] {
+	case SortNewest:
+		findOptions.SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}})
+	case SortOldest:
+		findOptions.SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+	default:
+		if cursorPaging {
+			findOptions.SetSort(bson.D{{Key: "_id", Value: 1}})
 		}
 	}
 
-	cursor, err := r.collection.Find(ctx, query, findOptions)
+	query := ListPetsQuery(filters)
+	findQuery := query // The total count ignores the cursor position
+	if after != nil {
+		findQuery = bson.M{"$and": bson.A{query, afterCursorQuery(after, filters[FilterSort])}}
+	}
+
+	cursor, err := r.collection.Find(ctx, findQuery, findOptions)
 	if err != nil {
 		log.Printf("Pet Service | Error listing pets from MongoDB: %v", err)
 		return nil, 0, err
@@ -193,7 +348,79 @@ func (r *mongoPetRepository) ListPets(ctx context.Context, page, limit int, filt
 	return pets, totalCount, nil
 }
 
-func (r *mongoPetRepository) UpdatePetAdoptionStatus(ctx context.Context, id string, newStatus domain.AdoptionStatus, adopterUserID *string) (*domain.Pet, error) {
+// ListPetsQuery builds the MongoDB filter used by ListPets.
+// Tags are matched with $in (any) or $all (every tag, when FilterTagsMatchAll is true),
+// FilterCreatedBefore with $lt on created_at, FilterMinAge and FilterMaxAge with $gte and $lte
+// on age, FilterSearch with $text, which uses the text index on name and description, and
+// several FilterSpecies with $in; all other filters are plain equality matches on the BSON field
+// of the same name.
+func ListPetsQuery(filters map[string]interface{}) bson.M {
+	query := bson.M{}
+	for key, value := range filters {
+		switch key {
+		case FilterTagsMatchAll:
+			// Applied together with FilterTags below.
+		case FilterSort, FilterAfter:
+			// Set the result order and position in ListPets, not part of the filter.
+		case FilterIncludeDeleted:
+			// Applied below.
+		case FilterTags:
+			tags, ok := value.([]string)
+			if !ok || len(tags) == 0 {
+				continue
+			}
+			operator := "$in"
+			if matchAll, _ := filters[FilterTagsMatchAll].(bool); matchAll {
+				operator = "$all"
+			}
+			query["tags"] = bson.M{operator: tags}
+		case FilterCreatedBefore:
+			if before, ok := value.(time.Time); ok && !before.IsZero() {
+				query["created_at"] = bson.M{"$lt": before}
+			}
+		case FilterSpecies:
+			switch species := value.(type) {
+			case string:
+				query["species"] = species
+			case []string:
+				if len(species) == 1 {
+					query["species"] = species[0]
+				} else if len(species) > 1 {
+					query["species"] = bson.M{"$in": species}
+				}
+			}
+		case FilterSearch:
+			if search, ok := value.(string); ok && strings.TrimSpace(search) != "" {
+				query["$text"] = bson.M{"$search": search}
+			}
+		case FilterMinAge, FilterMaxAge:
+			age, ok := value.(int32)
+			if !ok {
+				continue
+			}
+			ageCond, _ := query["age"].(bson.M)
+			if ageCond == nil {
+				ageCond = bson.M{}
+				query["age"] = ageCond
+			}
+			if key == FilterMinAge {
+				ageCond["$gte"] = age
+			} else {
+				ageCond["$lte"] = age
+			}
+		default:
+			// Basic equality filter. Ensure filter keys match BSON field names.
+			query[key] = value
+		}
+	}
+	if includeDeleted, _ := filters[FilterIncludeDeleted].(bool); !includeDeleted {
+		query["deleted"] = notDeleted()
+	}
+	return query
+}
+
+func (r *mongoPetRepository) UpdatePetAdoptionStatus(ctx context.Context, id string, expectedVersion int, change domain.StatusChange, adopterUserID *string) (*domain.Pet, error) {
+	newStatus := change.ToStatus
 	if id == "" {
 		return nil, errors.New("pet ID cannot be empty for status update")
 	}
@@ -203,7 +430,7 @@ func (r *mongoPetRepository) UpdatePetAdoptionStatus(ctx context.Context, id str
 
 	updateFields := bson.M{
 		"adoption_status": newStatus,
-		"updated_at":      time.Now().UTC(), // time.Now() is used here
+		"updated_at":      change.ChangedAt,
 	}
 	if adopterUserID != nil && *adopterUserID != "" && newStatus == domain.StatusAdopted {
 		updateFields["adopted_by_user_id"] = *adopterUserID
@@ -213,17 +440,227 @@ func (r *mongoPetRepository) UpdatePetAdoptionStatus(ctx context.Context, id str
 	}
 
 
-	update := bson.M{"$set": updateFields}
-	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	update := bson.M{
+		"$set":  updateFields,
+		"$push": bson.M{"status_history": change},
+		"$inc":  bson.M{"version": 1},
+	}
+	result, err := r.collection.UpdateOne(ctx, versionFilter(id, expectedVersion), update)
 	if err != nil {
 		log.Printf("Pet Service | Error updating pet adoption status for ID '%s': %v", id, err)
 		return nil, err
 	}
 
 	if result.MatchedCount == 0 {
-		return nil, errors.New("pet not found for status update")
+		return nil, r.versionMismatchError(ctx, id, expectedVersion, "pet not found for status update")
 	}
 
 	// Fetch and return the updated pet
 	return r.GetPetByID(ctx, id)
-}
\ No newline at end of file
+}
+
+func (r *mongoPetRepository) ForcePetAdoptionStatus(ctx context.Context, id string, change domain.StatusChange) (*domain.Pet, error) {
+	if id == "" {
+		return nil, errors.New("pet ID cannot be empty for status update")
+	}
+
+	updateFields := bson.M{
+		"adoption_status": change.ToStatus,
+		"updated_at":      change.ChangedAt,
+	}
+	if change.ToStatus != domain.StatusAdopted {
+		updateFields["adopted_by_user_id"] = ""
+	}
+	update := bson.M{
+		"$set":  updateFields,
+		"$push": bson.M{"status_history": change},
+		"$inc":  bson.M{"version": 1}, // Still bump the version so in-flight updates notice the override
+	}
+	result, err := r.collection.UpdateOne(ctx, activePetFilter(id), update)
+	if err != nil {
+		log.Printf("Pet Service | Error forcing pet adoption status for ID '%s': %v", id, err)
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, errors.New("pet not found for status update")
+	}
+	return r.GetPetByID(ctx, id)
+}
+
+func (r *mongoPetRepository) TransferPetListing(ctx context.Context, id string, expectedVersion int, transfer domain.ListingTransfer) (*domain.Pet, error) {
+	if id == "" {
+		return nil, errors.New("pet ID cannot be empty for listing transfer")
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"listed_by_user_id": transfer.ToUserID,
+			"updated_at":        transfer.TransferredAt,
+		},
+		"$push": bson.M{"listing_history": transfer},
+		"$inc":  bson.M{"version": 1},
+	}
+	result, err := r.collection.UpdateOne(ctx, versionFilter(id, expectedVersion), update)
+	if err != nil {
+		log.Printf("Pet Service | Error transferring listing of pet '%s': %v", id, err)
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, r.versionMismatchError(ctx, id, expectedVersion, "pet not found")
+	}
+	return r.GetPetByID(ctx, id)
+}
+
+func (r *mongoPetRepository) AddPetImageURLs(ctx context.Context, id string, imageURLs []string) (*domain.Pet, error) {
+	if id == "" {
+		return nil, errors.New("pet ID cannot be empty for adding images")
+	}
+
+	// $addToSet with $each appends only the URLs that are not already present.
+	update := bson.M{
+		"$addToSet": bson.M{"image_urls": bson.M{"$each": imageURLs}},
+		"$set":      bson.M{"updated_at": time.Now().UTC()},
+	}
+	result, err := r.collection.UpdateOne(ctx, activePetFilter(id), update)
+	if err != nil {
+		log.Printf("Pet Service | Error adding image URLs to pet '%s': %v", id, err)
+		return nil, err
+	}
+
+	if result.MatchedCount == 0 {
+		return nil, errors.New("pet not found")
+	}
+
+	return r.GetPetByID(ctx, id)
+}
+
+func (r *mongoPetRepository) AddPetTags(ctx context.Context, id string, tags []string) (*domain.Pet, error) {
+	return r.updatePetTags(ctx, id, bson.M{"$addToSet": bson.M{"tags": bson.M{"$each": tags}}})
+}
+
+func (r *mongoPetRepository) RemovePetTags(ctx context.Context, id string, tags []string) (*domain.Pet, error) {
+	return r.updatePetTags(ctx, id, bson.M{"$pull": bson.M{"tags": bson.M{"$in": tags}}})
+}
+
+func (r *mongoPetRepository) updatePetTags(ctx context.Context, id string, update bson.M) (*domain.Pet, error) {
+	if id == "" {
+		return nil, errors.New("pet ID cannot be empty for updating tags")
+	}
+	update["$set"] = bson.M{"updated_at": time.Now().UTC()}
+
+	result, err := r.collection.UpdateOne(ctx, activePetFilter(id), update)
+	if err != nil {
+		log.Printf("Pet Service | Error updating tags of pet '%s': %v", id, err)
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, errors.New("pet not found")
+	}
+	return r.GetPetByID(ctx, id)
+}
+
+// RecentlyAdoptedQuery builds the filter and find options used by ListRecentlyAdopted:
+// only ADOPTED pets, newest updated_at first, capped at limit.
+func RecentlyAdoptedQuery(limit int) (bson.M, *options.FindOptions) {
+	filter := bson.M{"adoption_status": domain.StatusAdopted, "deleted": notDeleted()}
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "updated_at", Value: -1}}).
+		SetLimit(int64(limit))
+	return filter, findOptions
+}
+
+func (r *mongoPetRepository) ListRecentlyAdopted(ctx context.Context, limit int) ([]*domain.Pet, error) {
+	if limit < 1 {
+		limit = 10
+	}
+	filter, findOptions := RecentlyAdoptedQuery(limit)
+
+	cursor, err := r.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		log.Printf("Pet Service | Error listing recently adopted pets from MongoDB: %v", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var pets []*domain.Pet
+	if err = cursor.All(ctx, &pets); err != nil {
+		log.Printf("Pet Service | Error decoding recently adopted pets from MongoDB: %v", err)
+		return nil, err
+	}
+	return pets, nil
+}
+
+// facetGroupStages counts pets per distinct value of field, most common first.
+func facetGroupStages(field string) bson.A {
+	return bson.A{
+		bson.M{"$match": bson.M{field: bson.M{"$nin": bson.A{nil, ""}}, "deleted": notDeleted()}},
+		bson.M{"$group": bson.M{"_id": "$" + field, "count": bson.M{"$sum": 1}}},
+		bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+	}
+}
+
+// PetFacetsPipeline builds the aggregation used by GetPetFacets: a single $facet stage
+// computing the species, breed and adoption status counts in one pass over the collection.
+func PetFacetsPipeline() mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$facet", Value: bson.M{
+			"species":           facetGroupStages("species"),
+			"breeds":            facetGroupStages("breed"),
+			"adoption_statuses": facetGroupStages("adoption_status"),
+		}}},
+	}
+}
+
+func (r *mongoPetRepository) GetPetFacets(ctx context.Context) (*domain.PetFacets, error) {
+	cursor, err := r.collection.Aggregate(ctx, PetFacetsPipeline())
+	if err != nil {
+		log.Printf("Pet Service | Error aggregating pet facets in MongoDB: %v", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	facets := &domain.PetFacets{}
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(facets); err != nil {
+			log.Printf("Pet Service | Error decoding pet facets from MongoDB: %v", err)
+			return nil, err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		log.Printf("Pet Service | Error reading pet facets cursor: %v", err)
+		return nil, err
+	}
+	return facets, nil
+}
+
+// SuggestBreeds asks MongoDB for the distinct breeds of the species and filters them by prefix here,
+// folding breeds that differ only in case (e.g. "Beagle" and "beagle") into the first one seen.
+func (r *mongoPetRepository) SuggestBreeds(ctx context.Context, species, prefix string, limit int) ([]string, error) {
+	filter := bson.M{"species": primitive.Regex{Pattern: "^" + regexp.QuoteMeta(species) + "$", Options: "i"}, "deleted": notDeleted()}
+	values, err := r.collection.Distinct(ctx, "breed", filter)
+	if err != nil {
+		log.Printf("Pet Service | Error listing breeds of species %s from MongoDB: %v", species, err)
+		return nil, err
+	}
+
+	lowerPrefix := strings.ToLower(prefix)
+	seen := make(map[string]bool, len(values))
+	breeds := make([]string, 0, len(values))
+	for _, value := range values {
+		breed, ok := value.(string)
+		if !ok || breed == "" {
+			continue
+		}
+		key := strings.ToLower(breed)
+		if seen[key] || !strings.HasPrefix(key, lowerPrefix) {
+			continue
+		}
+		seen[key] = true
+		breeds = append(breeds, breed)
+	}
+	sort.Slice(breeds, func(i, j int) bool { return strings.ToLower(breeds[i]) < strings.ToLower(breeds[j]) })
+	if limit > 0 && len(breeds) > limit {
+		breeds = breeds[:limit]
+	}
+	return breeds, nil
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/zhandarbeks/petstore-final-project/pet-service/internal/domain" // Adjust import path
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrMalformedCursor is returned by DecodePageCursor for a cursor it did not produce.
+var ErrMalformedCursor = errors.New("malformed page cursor")
+
+// PageCursor marks the last pet of a cursor page; the next page starts after it in the
+// listing's sort order.
+type PageCursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        string    `json:"id"`
+}
+
+// EncodePageCursor returns the opaque cursor for the page following pet.
+func EncodePageCursor(pet *domain.Pet) string {
+	data, _ := json.Marshal(PageCursor{CreatedAt: pet.CreatedAt.UTC(), ID: pet.ID})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodePageCursor parses a cursor produced by EncodePageCursor.
+func DecodePageCursor(cursor string) (*PageCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrMalformedCursor
+	}
+	var pc PageCursor
+	if err := json.Unmarshal(data, &pc); err != nil {
+		return nil, ErrMalformedCursor
+	}
+	if _, err := primitive.ObjectIDFromHex(pc.ID); err != nil {
+		return nil, ErrMalformedCursor
+	}
+	return &pc, nil
+}
+
+// afterCursorQuery matches the pets that come after pc in the given sort order. Without a
+// sort, cursor pages are ordered by _id.
+func afterCursorQuery(pc *PageCursor, sort interface{}) bson.M {
+	switch sort {
+	case SortNewest:
+		return bson.M{"$or": bson.A{
+			bson.M{"created_at": bson.M{"$lt": pc.CreatedAt}},
+			bson.M{"created_at": pc.CreatedAt, "_id": bson.M{"$lt": pc.ID}},
+		}}
+	case SortOldest:
+		return bson.M{"$or": bson.A{
+			bson.M{"created_at": bson.M{"$gt": pc.CreatedAt}},
+			bson.M{"created_at": pc.CreatedAt, "_id": bson.M{"$gt": pc.ID}},
+		}}
+	default:
+		return bson.M{"_id": bson.M{"$gt": pc.ID}}
+	}
+}
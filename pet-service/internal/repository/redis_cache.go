@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -58,7 +60,7 @@ func (c *redisPetCache) GetPet(ctx context.Context, id string) (*domain.Pet, err
 	val, err := c.client.Get(ctx, key).Result()
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
-			return nil, errors.New("pet not found in cache")
+			return nil, fmt.Errorf("pet %w", ErrCacheMiss)
 		}
 		log.Printf("Pet Service | Error getting pet from Redis cache (key: %s): %v", key, err)
 		return nil, err
@@ -99,58 +101,134 @@ func (c *redisPetCache) DeletePet(ctx context.Context, id string) error {
 	return nil
 }
 
-// Example for caching lists (implement if needed)
-/*
-func (c *redisPetCache) listCacheKey(filters map[string]interface{}, page, limit int) string {
-	// Create a stable key based on filters, page, and limit
-	// This can be complex; consider hashing or a canonical string representation.
-	// For simplicity, a basic example:
-	var filterParts []string
-	for k, v := range filters {
-		filterParts = append(filterParts, fmt.Sprintf("%s=%v", k, v))
+func (c *redisPetCache) facetsKey() string {
+	return c.prefix + "facets"
+}
+
+func (c *redisPetCache) GetPetFacets(ctx context.Context) (*domain.PetFacets, error) {
+	key := c.facetsKey()
+	val, err := c.client.Get(ctx, key).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, fmt.Errorf("facets %w", ErrCacheMiss)
+		}
+		log.Printf("Pet Service | Error getting pet facets from Redis cache (key: %s): %v", key, err)
+		return nil, err
+	}
+
+	var facets domain.PetFacets
+	if err := json.Unmarshal([]byte(val), &facets); err != nil {
+		log.Printf("Pet Service | Error unmarshalling pet facets from Redis (key: %s): %v", key, err)
+		return nil, err
 	}
-	sort.Strings(filterParts) // Ensure order for consistency
-	return fmt.Sprintf("%slist:%s:page%d:limit%d", c.prefix, strings.Join(filterParts, "&"), page, limit)
+	return &facets, nil
 }
 
-func (c *redisPetCache) SetListedPets(ctx context.Context, cacheKey string, pets []*domain.Pet, expiration time.Duration) error {
-	data, err := json.Marshal(pets)
+func (c *redisPetCache) SetPetFacets(ctx context.Context, facets *domain.PetFacets, expiration time.Duration) error {
+	key := c.facetsKey()
+	data, err := json.Marshal(facets)
 	if err != nil {
-		log.Printf("Pet Service | Error marshalling listed pets for Redis cache (key: %s): %v", cacheKey, err)
+		log.Printf("Pet Service | Error marshalling pet facets for Redis cache (key: %s): %v", key, err)
+		return err
+	}
+	if err := c.client.Set(ctx, key, data, expiration).Err(); err != nil {
+		log.Printf("Pet Service | Error setting pet facets in Redis cache (key: %s): %v", key, err)
 		return err
 	}
-	err = c.client.Set(ctx, cacheKey, data, expiration).Err()
+	return nil
+}
+
+func (c *redisPetCache) DeletePetFacets(ctx context.Context) error {
+	key := c.facetsKey()
+	if err := c.client.Del(ctx, key).Err(); err != nil {
+		log.Printf("Pet Service | Error deleting pet facets from Redis cache (key: %s): %v", key, err)
+		return err
+	}
+	return nil
+}
+
+// listCacheKey returns the key of one page of a pet listing. The filters, page and limit are
+// marshalled to JSON, which orders map keys, so equal queries get the same key however the filter
+// map was built; the JSON is hashed to keep keys short.
+func (c *redisPetCache) listCacheKey(page, limit int, filters map[string]interface{}) (string, error) {
+	data, err := json.Marshal(struct {
+		Filters map[string]interface{} `json:"f"`
+		Page    int                    `json:"p"`
+		Limit   int                    `json:"l"`
+	}{filters, page, limit})
 	if err != nil {
-		log.Printf("Pet Service | Error setting listed pets in Redis cache (key: %s): %v", cacheKey, err)
+		return "", err
 	}
-	return err
+	sum := sha256.Sum256(data)
+	return c.prefix + "list:" + hex.EncodeToString(sum[:]), nil
 }
 
-func (c *redisPetCache) GetListedPets(ctx context.Context, cacheKey string) ([]*domain.Pet, error) {
-	val, err := c.client.Get(ctx, cacheKey).Result()
+// listedPets is how a page of a pet listing is stored in the cache.
+type listedPets struct {
+	Pets       []*domain.Pet `json:"pets"`
+	TotalCount int64         `json:"total_count"`
+}
+
+func (c *redisPetCache) GetListedPets(ctx context.Context, page, limit int, filters map[string]interface{}) ([]*domain.Pet, int64, error) {
+	key, err := c.listCacheKey(page, limit, filters)
+	if err != nil {
+		log.Printf("Pet Service | Error building listed pets cache key: %v", err)
+		return nil, 0, err
+	}
+	val, err := c.client.Get(ctx, key).Result()
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
-			return nil, errors.New("listed pets not found in cache")
+			return nil, 0, fmt.Errorf("listed pets %w", ErrCacheMiss)
 		}
-		log.Printf("Pet Service | Error getting listed pets from Redis cache (key: %s): %v", cacheKey, err)
-		return nil, err
+		log.Printf("Pet Service | Error getting listed pets from Redis cache (key: %s): %v", key, err)
+		return nil, 0, err
 	}
-	var pets []*domain.Pet
-	err = json.Unmarshal([]byte(val), &pets)
-	if err != nil {
-		log.Printf("Pet Service | Error unmarshalling listed pets from Redis (key: %s): %v", cacheKey, err)
-		return nil, err
+
+	var listed listedPets
+	if err := json.Unmarshal([]byte(val), &listed); err != nil {
+		log.Printf("Pet Service | Error unmarshalling listed pets from Redis (key: %s): %v", key, err)
+		return nil, 0, err
 	}
-	return pets, nil
+	return listed.Pets, listed.TotalCount, nil
 }
 
-func (c *redisPetCache) DeleteListedPets(ctx context.Context, cacheKey string) error {
-	// More robust: delete all keys matching a pattern if filters change, e.g., using SCAN and DEL.
-	// For simplicity, just deleting a specific key.
-	err := c.client.Del(ctx, cacheKey).Err()
+func (c *redisPetCache) SetListedPets(ctx context.Context, page, limit int, filters map[string]interface{}, pets []*domain.Pet, totalCount int64, expiration time.Duration) error {
+	key, err := c.listCacheKey(page, limit, filters)
 	if err != nil {
-		log.Printf("Pet Service | Error deleting listed pets from Redis cache (key: %s): %v", cacheKey, err)
+		log.Printf("Pet Service | Error building listed pets cache key: %v", err)
+		return err
+	}
+	data, err := json.Marshal(listedPets{Pets: pets, TotalCount: totalCount})
+	if err != nil {
+		log.Printf("Pet Service | Error marshalling listed pets for Redis cache (key: %s): %v", key, err)
+		return err
+	}
+	if err := c.client.Set(ctx, key, data, expiration).Err(); err != nil {
+		log.Printf("Pet Service | Error setting listed pets in Redis cache (key: %s): %v", key, err)
+		return err
+	}
+	return nil
+}
+
+// DeleteListedPets drops every cached listing page. The keys are found with SCAN rather than
+// KEYS, so Redis is not blocked while a large keyspace is walked.
+func (c *redisPetCache) DeleteListedPets(ctx context.Context) error {
+	pattern := c.prefix + "list:*"
+	iter := c.client.Scan(ctx, 0, pattern, 100).Iterator()
+	var keys []string
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
 	}
-	return err
+	if err := iter.Err(); err != nil {
+		log.Printf("Pet Service | Error scanning listed pets in Redis cache (pattern: %s): %v", pattern, err)
+		return err
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	if err := c.client.Del(ctx, keys...).Err(); err != nil {
+		log.Printf("Pet Service | Error deleting listed pets from Redis cache (pattern: %s): %v", pattern, err)
+		return err
+	}
+	return nil
 }
-*/
\ No newline at end of file
@@ -10,6 +10,7 @@ import (
 
 	pb "github.com/zhandarbeks/petstore-final-project/genprotos/pet" // Adjust import path to your generated pet protos
 	"google.golang.org/grpc"
+	_ "google.golang.org/grpc/encoding/gzip" // Registers gzip so clients may send compressed requests
 	"google.golang.org/grpc/health"
 	"google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection" // For Evans CLI, grpcurl, etc.
@@ -19,12 +20,15 @@ import (
 type GRPCServer struct {
 	server   *grpc.Server
 	listener net.Listener
+	inFlight *InFlightTracker
 	Port     string
 }
 
 // NewGRPCServer creates and configures a new gRPC server instance for the Pet Service.
-// It takes the port string (e.g., ":50052") and the PetServiceServer implementation.
-func NewGRPCServer(port string, petService pb.PetServiceServer) (*GRPCServer, error) {
+// It takes the port string (e.g., ":50052") and the PetServiceServer implementation,
+// plus the maximum number of concurrent in-flight requests (0 disables the limit). Any extra
+// interceptors run after the concurrency limit, in order.
+func NewGRPCServer(port string, petService pb.PetServiceServer, maxInFlight int, interceptors ...grpc.UnaryServerInterceptor) (*GRPCServer, error) {
 	if port == "" {
 		return nil, fmt.Errorf("port cannot be empty for Pet Service gRPC server")
 	}
@@ -39,9 +43,12 @@ func NewGRPCServer(port string, petService pb.PetServiceServer) (*GRPCServer, er
 	}
 
 	// Create a new gRPC server
+	inFlight := &InFlightTracker{}
 	s := grpc.NewServer(
-	// Add interceptors here if needed in the future
-	// grpc.UnaryInterceptor(yourUnaryInterceptor),
+		grpc.ChainUnaryInterceptor(append([]grpc.UnaryServerInterceptor{
+			NewConcurrencyLimitInterceptor(maxInFlight, inFlight), // Rejects requests beyond maxInFlight with ResourceExhausted
+		}, interceptors...)...),
+		// grpc.StreamInterceptor(yourStreamInterceptor),
 	)
 
 	// Register your pet service implementation with the gRPC server.
@@ -63,6 +70,7 @@ func NewGRPCServer(port string, petService pb.PetServiceServer) (*GRPCServer, er
 	return &GRPCServer{
 		server:   s,
 		listener: lis,
+		inFlight: inFlight,
 		Port:     port,
 	}, nil
 }
@@ -78,11 +86,17 @@ func (gs *GRPCServer) Start() error {
 	return nil
 }
 
+// Addr returns the address the server listens on, useful when it was started on port 0.
+func (gs *GRPCServer) Addr() string {
+	return gs.listener.Addr().String()
+}
+
 // Stop gracefully shuts down the gRPC server for the Pet Service.
 func (gs *GRPCServer) Stop() {
-	log.Println("Pet Service | Attempting to gracefully stop gRPC server...")
+	inFlight := gs.inFlight.StartDraining()
+	log.Printf("Pet Service | Attempting to gracefully stop gRPC server... in_flight=%d", inFlight)
 	gs.server.GracefulStop()
-	log.Println("Pet Service | gRPC server stopped.")
+	log.Printf("Pet Service | gRPC server stopped. in_flight_at_shutdown=%d drained=%d", inFlight, gs.inFlight.Drained())
 }
 
 // RunWithGracefulShutdown starts the Pet Service server and handles OS signals for graceful shutdown.
@@ -0,0 +1,105 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// userIDMetadataKey carries the authenticated caller's user ID, set by the API gateway.
+const userIDMetadataKey = "x-user-id"
+
+// rateLimitCheckTimeout bounds the counter lookup, so a slow Redis does not stall every request.
+const rateLimitCheckTimeout = 500 * time.Millisecond
+
+// RateCounter counts requests per key in fixed time windows.
+type RateCounter interface {
+	// Incr adds one to the counter for key and returns the new count within the current window.
+	Incr(ctx context.Context, key string, window time.Duration) (int64, error)
+}
+
+// redisRateCounter keeps the counters in Redis so the limit holds across service instances.
+type redisRateCounter struct {
+	client *redis.Client
+	prefix string // e.g., "pet:ratelimit:"
+}
+
+// NewRedisRateCounter creates a RateCounter backed by Redis keys that expire with the window.
+func NewRedisRateCounter(client *redis.Client, keyPrefix string) RateCounter {
+	if keyPrefix == "" {
+		keyPrefix = "pet:ratelimit:"
+	}
+	return &redisRateCounter{client: client, prefix: keyPrefix}
+}
+
+// Incr increments the key and starts its expiry on the first request of the window.
+func (c *redisRateCounter) Incr(ctx context.Context, key string, window time.Duration) (int64, error) {
+	var incr *redis.IntCmd
+	_, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.Incr(ctx, c.prefix+key)
+		pipe.ExpireNX(ctx, c.prefix+key, window)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+// UserRateLimits caps the requests one authenticated user may make per window. Reads are methods
+// whose name starts with Get or List; everything else counts as a write. A limit of 0 disables
+// the limit for that class.
+type UserRateLimits struct {
+	ReadsPerWindow  int
+	WritesPerWindow int
+	Window          time.Duration
+}
+
+// isReadMethod reports whether fullMethod (e.g. "/pet.PetService/GetPet") only reads.
+func isReadMethod(fullMethod string) bool {
+	name := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	return strings.HasPrefix(name, "Get") || strings.HasPrefix(name, "List")
+}
+
+// NewUserRateLimitInterceptor returns a unary interceptor that rejects requests with
+// codes.ResourceExhausted once the caller (x-user-id metadata) exceeded their limit for the
+// method's class in the current window. Requests without a user ID are not limited here; the
+// gateway limits them by IP. If the counter is unavailable the request is let through, so a
+// Redis outage does not take the service down.
+func NewUserRateLimitInterceptor(counter RateCounter, limits UserRateLimits) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		class, limit := "write", limits.WritesPerWindow
+		if isReadMethod(info.FullMethod) {
+			class, limit = "read", limits.ReadsPerWindow
+		}
+		if limit <= 0 || counter == nil {
+			return handler(ctx, req)
+		}
+		md, _ := metadata.FromIncomingContext(ctx)
+		userIDs := md.Get(userIDMetadataKey)
+		if len(userIDs) == 0 || userIDs[0] == "" {
+			return handler(ctx, req)
+		}
+
+		checkCtx, cancel := context.WithTimeout(ctx, rateLimitCheckTimeout)
+		count, err := counter.Incr(checkCtx, fmt.Sprintf("%s:%s", class, userIDs[0]), limits.Window)
+		cancel()
+		if err != nil {
+			log.Printf("Pet Service | Warning: Rate limit check failed for user %s, allowing %s: %v", userIDs[0], info.FullMethod, err)
+			return handler(ctx, req)
+		}
+		if count > int64(limit) {
+			log.Printf("Pet Service | Rejecting %s: user %s exceeded %d %ss per %s", info.FullMethod, userIDs[0], limit, class, limits.Window)
+			return nil, status.Errorf(codes.ResourceExhausted, "rate limit exceeded: at most %d %s requests per %s", limit, class, limits.Window)
+		}
+		return handler(ctx, req)
+	}
+}
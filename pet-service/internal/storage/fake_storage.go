@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// fakeImageStorage produces unsigned upload targets under a base URL.
+// It is meant for local development and tests where no real bucket is available.
+type fakeImageStorage struct {
+	baseURL string
+	expiry  time.Duration
+}
+
+// NewFakeImageStorage creates an ImageStorage that does not talk to any backend.
+func NewFakeImageStorage(baseURL string) ImageStorage {
+	if baseURL == "" {
+		baseURL = "http://localhost:9000/pet-images"
+	}
+	return &fakeImageStorage{baseURL: strings.TrimRight(baseURL, "/"), expiry: 15 * time.Minute}
+}
+
+func (f *fakeImageStorage) PresignUpload(ctx context.Context, key, contentType string) (*UploadTarget, error) {
+	if key == "" {
+		return nil, errors.New("object key is required")
+	}
+	return &UploadTarget{
+		URL: f.baseURL,
+		Fields: map[string]string{
+			"key":          key,
+			"Content-Type": contentType,
+		},
+		PublicURL: f.PublicURL(key),
+		ExpiresAt: time.Now().UTC().Add(f.expiry),
+	}, nil
+}
+
+func (f *fakeImageStorage) PublicURL(key string) string {
+	return fmt.Sprintf("%s/%s", f.baseURL, key)
+}
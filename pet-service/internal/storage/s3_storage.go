@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// S3Config holds the settings for an S3-compatible bucket.
+// GCS buckets can be used as well through their S3-interoperable XML API with HMAC keys
+// (Endpoint "https://storage.googleapis.com", Region "auto").
+type S3Config struct {
+	Bucket          string
+	Region          string
+	Endpoint        string // Optional custom endpoint (MinIO, GCS interop). Empty means AWS S3.
+	AccessKeyID     string
+	SecretAccessKey string
+	PublicBaseURL   string // Optional CDN/public base URL. Empty means the bucket URL.
+	Expiry          time.Duration
+	MaxUploadBytes  int64
+}
+
+type s3ImageStorage struct {
+	cfg S3Config
+	now func() time.Time
+}
+
+// NewS3ImageStorage creates an ImageStorage that issues SigV4 presigned POST policies.
+func NewS3ImageStorage(cfg S3Config) (ImageStorage, error) {
+	if cfg.Bucket == "" || cfg.Region == "" {
+		return nil, errors.New("s3 bucket and region are required")
+	}
+	if cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return nil, errors.New("s3 access key ID and secret access key are required")
+	}
+	if cfg.Expiry <= 0 {
+		cfg.Expiry = 15 * time.Minute
+	}
+	if cfg.MaxUploadBytes <= 0 {
+		cfg.MaxUploadBytes = 5 << 20 // 5 MiB
+	}
+	return &s3ImageStorage{cfg: cfg, now: time.Now}, nil
+}
+
+// bucketURL returns the URL clients POST uploads to.
+func (s *s3ImageStorage) bucketURL() string {
+	if s.cfg.Endpoint != "" {
+		// Path-style addressing for custom endpoints.
+		return fmt.Sprintf("%s/%s", strings.TrimRight(s.cfg.Endpoint, "/"), s.cfg.Bucket)
+	}
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com", s.cfg.Bucket, s.cfg.Region)
+}
+
+func (s *s3ImageStorage) PresignUpload(ctx context.Context, key, contentType string) (*UploadTarget, error) {
+	if key == "" {
+		return nil, errors.New("object key is required")
+	}
+
+	now := s.now().UTC()
+	expiresAt := now.Add(s.cfg.Expiry)
+	amzDate := now.Format("20060102T150405Z")
+	shortDate := now.Format("20060102")
+	credential := fmt.Sprintf("%s/%s/%s/s3/aws4_request", s.cfg.AccessKeyID, shortDate, s.cfg.Region)
+
+	// The policy pins the upload to exactly this key and content type, and caps its size.
+	policy := map[string]interface{}{
+		"expiration": expiresAt.Format("2006-01-02T15:04:05.000Z"),
+		"conditions": []interface{}{
+			map[string]string{"bucket": s.cfg.Bucket},
+			map[string]string{"key": key},
+			map[string]string{"Content-Type": contentType},
+			[]interface{}{"content-length-range", 1, s.cfg.MaxUploadBytes},
+			map[string]string{"x-amz-algorithm": "AWS4-HMAC-SHA256"},
+			map[string]string{"x-amz-credential": credential},
+			map[string]string{"x-amz-date": amzDate},
+		},
+	}
+	policyJSON, err := json.Marshal(policy)
+	if err != nil {
+		return nil, fmt.Errorf("could not encode upload policy: %w", err)
+	}
+	encodedPolicy := base64.StdEncoding.EncodeToString(policyJSON)
+
+	// SigV4 signing key derivation: HMAC chain over date, region, service and terminator.
+	signingKey := hmacSHA256([]byte("AWS4"+s.cfg.SecretAccessKey), shortDate)
+	signingKey = hmacSHA256(signingKey, s.cfg.Region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, encodedPolicy))
+
+	return &UploadTarget{
+		URL: s.bucketURL(),
+		Fields: map[string]string{
+			"key":              key,
+			"Content-Type":     contentType,
+			"policy":           encodedPolicy,
+			"x-amz-algorithm":  "AWS4-HMAC-SHA256",
+			"x-amz-credential": credential,
+			"x-amz-date":       amzDate,
+			"x-amz-signature":  signature,
+		},
+		PublicURL: s.PublicURL(key),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// PublicURL serves objects from PublicBaseURL, or from the bucket itself when it is not set.
+func (s *s3ImageStorage) PublicURL(key string) string {
+	publicBase := s.cfg.PublicBaseURL
+	if publicBase == "" {
+		publicBase = s.bucketURL()
+	}
+	return fmt.Sprintf("%s/%s", strings.TrimRight(publicBase, "/"), key)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// UploadTarget describes where and how a client should upload an image directly to object storage.
+// The client sends a multipart/form-data POST to URL containing every entry of Fields plus the file
+// (as the last form field, named "file"). Once the upload succeeds, PublicURL is the address the
+// image will be served from and should be registered on the pet via AddImageURLs.
+type UploadTarget struct {
+	URL       string
+	Fields    map[string]string
+	PublicURL string
+	ExpiresAt time.Time
+}
+
+// ImageStorage abstracts the object storage backend used for pet images.
+type ImageStorage interface {
+	// PresignUpload returns a presigned upload target for the given object key.
+	PresignUpload(ctx context.Context, key, contentType string) (*UploadTarget, error)
+	// PublicURL returns the address the object with the given key is served from.
+	PublicURL(key string) string
+}
+
+// PetImageKey builds a pet-scoped object key for a new image upload.
+// Keys look like "pets/<petID>/<random-id><ext>" so that every upload lives under its pet's prefix.
+func PetImageKey(petID, filename string) string {
+	ext := strings.ToLower(path.Ext(filename))
+	return fmt.Sprintf("%s%s%s", PetImageKeyPrefix(petID), primitive.NewObjectID().Hex(), ext)
+}
+
+// PetImageKeyPrefix returns the prefix every image key of the pet starts with.
+func PetImageKeyPrefix(petID string) string {
+	return fmt.Sprintf("pets/%s/", petID)
+}
+
+// IsAllowedImageContentType reports whether the content type is one we accept for pet images.
+func IsAllowedImageContentType(contentType string) bool {
+	switch contentType {
+	case "image/jpeg", "image/png", "image/gif", "image/webp":
+		return true
+	default:
+		return false
+	}
+}
@@ -2,8 +2,10 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	"github.com/zhandarbeks/petstore-final-project/pet-service/internal/domain" // Adjust import path
+	"github.com/zhandarbeks/petstore-final-project/pet-service/internal/storage"
 )
 
 // CreatePetRequestData holds the data needed to create a new pet.
@@ -15,7 +17,12 @@ type CreatePetRequestData struct {
 	Age            int32
 	Description    string
 	ListedByUserID string // ID of the user listing the pet
-	ImageURLs      []string
+	ImageURLs      []string // Must be empty: images are uploaded once the pet exists (ErrInvalidImageURL)
+	Tags           []string
+
+	// Optional initial status; empty means AVAILABLE. Other values require CallerCanSetStatus.
+	AdoptionStatus     domain.AdoptionStatus
+	CallerCanSetStatus bool // Caller has the admin or shelter role
 }
 
 // UpdatePetRequestData holds the data for updating an existing pet.
@@ -26,16 +33,45 @@ type UpdatePetRequestData struct {
 	Breed          *string
 	Age            *int32
 	Description    *string
-	ImageURLs      []string // For ImageURLs, decide if it's a full replacement or partial update
+	ImageURLs      []string // Replaces the images; each must be one of them already or an upload for the pet (ErrInvalidImageURL)
 	// AdoptionStatus is handled by a separate method for clarity and control
 }
 
+// PetUsecaseConfig holds tunable settings for the pet usecase.
+type PetUsecaseConfig struct {
+	// FacetsCacheTTL is how long the facets aggregation is served from cache. 0 disables caching.
+	FacetsCacheTTL time.Duration
+	// ListCacheTTL is how long a page of ListPets results is served from cache. Writes drop all
+	// cached pages, the TTL bounds how stale a page can get if that fails. 0 disables caching.
+	ListCacheTTL time.Duration
+	// SeedCacheOnCreate caches a newly created pet right away, so a read that follows the create
+	// is served the new pet instead of depending on a possibly lagging database read.
+	SeedCacheOnCreate bool
+	// MaxDescriptionLength caps a pet's description, in characters, after it has been normalized
+	// (see textnorm.Normalize). 0 means no limit.
+	MaxDescriptionLength int
+	// MaxImagesPerPet caps how many image URLs a pet can have, whether they are added incrementally
+	// or replaced as a whole. 0 means no limit.
+	MaxImagesPerPet int
+}
+
 // PetUsecase defines the interface for pet-related business logic.
 type PetUsecase interface {
 	CreatePet(ctx context.Context, reqData CreatePetRequestData) (*domain.Pet, error)
 	GetPetByID(ctx context.Context, id string) (*domain.Pet, error)
-	UpdatePet(ctx context.Context, id string, reqData UpdatePetRequestData) (*domain.Pet, error)
-	DeletePet(ctx context.Context, id string) error
+	BatchGetPets(ctx context.Context, ids []string) ([]*domain.Pet, error) // Pets that do not exist are left out
+	UpdatePet(ctx context.Context, id string, reqData UpdatePetRequestData, callerUserID string) (*domain.Pet, error) // Only the user who listed the pet; ErrNotPetOwner otherwise
+	DeletePet(ctx context.Context, id string, callerUserID string) error                                              // Only the user who listed the pet; ErrNotPetOwner otherwise
+	RestorePet(ctx context.Context, id string, callerUserID string, callerIsAdmin bool) (*domain.Pet, error)          // Only the user who listed the pet or an admin; ErrNotPetOwner otherwise
 	ListPets(ctx context.Context, page, limit int, filters map[string]interface{}) ([]*domain.Pet, int64, error)
-	UpdatePetAdoptionStatus(ctx context.Context, id string, newStatus domain.AdoptionStatus, adopterUserID *string) (*domain.Pet, error)
+	UpdatePetAdoptionStatus(ctx context.Context, id string, newStatus domain.AdoptionStatus, adopterUserID *string, reason, changedBy string) (*domain.Pet, error)
+	GetImageUploadTarget(ctx context.Context, petID, filename, contentType, callerUserID string) (*storage.UploadTarget, error) // Only the user who listed the pet; ErrNotPetOwner otherwise
+	AddImageURLs(ctx context.Context, petID string, imageURLs []string, callerUserID string) (*domain.Pet, error)             // Only the user who listed the pet, and only URLs of its uploads
+	ListRecentlyAdopted(ctx context.Context, limit int) ([]*domain.Pet, error)
+	GetPetFacets(ctx context.Context) (*domain.PetFacets, error)
+	SuggestBreeds(ctx context.Context, species, prefix string, limit int) ([]string, error)
+	AddPetTags(ctx context.Context, petID string, tags []string, callerUserID string) (*domain.Pet, error)    // Only the user who listed the pet; ErrNotPetOwner otherwise
+	RemovePetTags(ctx context.Context, petID string, tags []string, callerUserID string) (*domain.Pet, error) // Only the user who listed the pet; ErrNotPetOwner otherwise
+	AdminSetPetStatus(ctx context.Context, petID string, newStatus domain.AdoptionStatus, reason, changedBy string) (*domain.Pet, error)
+	TransferPetListing(ctx context.Context, petID, newOwnerID, callerID string, callerIsAdmin bool) (*domain.Pet, error)
 }
\ No newline at end of file
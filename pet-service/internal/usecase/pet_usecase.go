@@ -5,24 +5,86 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net/url"
+	"slices"
+	"strings"
 	"time"
-
-	"github.com/zhandarbeks/petstore-final-project/pet-service/internal/domain" // Adjust import path
+	"unicode/utf8"
+
+	"github.com/zhandarbeks/petstore-final-project/internal/textnorm"
+	"github.com/zhandarbeks/petstore-final-project/pet-service/internal/client"
+	"github.com/zhandarbeks/petstore-final-project/pet-service/internal/domain"
+	"github.com/zhandarbeks/petstore-final-project/pet-service/internal/logging"
+	"github.com/zhandarbeks/petstore-final-project/pet-service/internal/metrics" // Adjust import path
+	"github.com/zhandarbeks/petstore-final-project/pet-service/internal/publisher"
 	"github.com/zhandarbeks/petstore-final-project/pet-service/internal/repository"
+	"github.com/zhandarbeks/petstore-final-project/pet-service/internal/storage"
 	// "go.mongodb.org/mongo-driver/bson/primitive" // If generating IDs here, but repo handles it
 )
 
 type petUsecase struct {
-	petRepo  repository.PetRepository
-	petCache repository.PetCache
-	// userServiceClient some_interface.UserServiceClient // If needed to validate ListedByUserID against user service
+	petRepo      repository.PetRepository
+	petCache     repository.PetCache
+	imageStorage storage.ImageStorage // Object storage used for presigned image uploads
+	userClient   client.UserServiceClient // Used to check that users exist, e.g. the new owner of a transferred listing
+	publisher    publisher.PetEventPublisher // Announces pets becoming unavailable; nil disables events
+	cfg          PetUsecaseConfig
 }
 
-// NewPetUsecase creates a new instance of petUsecase.
-func NewPetUsecase(repo repository.PetRepository, cache repository.PetCache) PetUsecase {
+var (
+	// ErrListingTransferForbidden is returned when someone other than the current owner or an admin
+	// tries to transfer a listing.
+	ErrListingTransferForbidden = errors.New("only the current owner or an admin can transfer this listing")
+	// ErrNotPetOwner is returned when someone other than the user who listed a pet updates or
+	// deletes it. Pets without an owner cannot be changed this way.
+	ErrNotPetOwner = errors.New("forbidden: not pet owner")
+	// ErrNewOwnerNotFound is returned when the user a listing is transferred to does not exist.
+	ErrNewOwnerNotFound = errors.New("new owner user not found")
+	// ErrPetNotDeleted is returned when restoring a pet that was not deleted.
+	ErrPetNotDeleted = errors.New("pet is not deleted")
+	// ErrNoFieldsToUpdate is returned when an update request does not set any field.
+	ErrNoFieldsToUpdate = errors.New("at least one field must be provided for update")
+	// ErrInvalidInitialStatus is returned when a pet is created with a status other than AVAILABLE or PENDING_ADOPTION.
+	ErrInvalidInitialStatus = errors.New("initial adoption status must be AVAILABLE or PENDING_ADOPTION")
+	// ErrInitialStatusForbidden is returned when a caller without the admin or shelter role creates
+	// a pet with a non-default status.
+	ErrInitialStatusForbidden = errors.New("only admins and shelters can set a pet's initial status")
+	// ErrInvalidCursor is returned when a pet list cursor is malformed.
+	ErrInvalidCursor = errors.New("invalid pagination cursor")
+	// ErrCursorWithPage is returned when a pet list request sets both a cursor and a page.
+	ErrCursorWithPage = errors.New("invalid page: cursor and page cannot be combined")
+	// ErrInvalidAgeRange is returned when a pet list filters by a negative age, or by a minimum
+	// age above the maximum.
+	ErrInvalidAgeRange = errors.New("invalid age range: ages must not be negative and min_age must not exceed max_age")
+	// ErrTooManyPetIDs is returned when more than MaxBatchGetPets pets are requested at once.
+	ErrTooManyPetIDs = fmt.Errorf("at most %d pets can be requested at once", MaxBatchGetPets)
+	// ErrSpeciesRequired is returned when breed suggestions are requested without a species.
+	ErrSpeciesRequired = errors.New("species is required")
+	// ErrTooManyImages is returned when adding or setting image URLs would take a pet past
+	// PetUsecaseConfig.MaxImagesPerPet.
+	ErrTooManyImages = errors.New("too many images for this pet")
+	// ErrInvalidImageURL is returned for image URLs that are not the public URL of an image uploaded
+	// for the pet through GetImageUploadTarget. New pets have no uploads yet, so they take none.
+	ErrInvalidImageURL = errors.New("invalid image URL")
+	// ErrStatusReasonTooLong is returned when a status change reason exceeds domain.MaxStatusReasonLength.
+	ErrStatusReasonTooLong = fmt.Errorf("status change reason must be at most %d characters", domain.MaxStatusReasonLength)
+)
+
+// petCacheTTL is how long a single pet stays in the cache.
+const petCacheTTL = 1 * time.Hour
+
+// MaxBatchGetPets is the most pets BatchGetPets returns in one call.
+const MaxBatchGetPets = 100
+
+// NewPetUsecase creates a new instance of petUsecase. eventPublisher may be nil when no events are published.
+func NewPetUsecase(repo repository.PetRepository, cache repository.PetCache, imageStorage storage.ImageStorage, userClient client.UserServiceClient, eventPublisher publisher.PetEventPublisher, cfg PetUsecaseConfig) PetUsecase {
 	return &petUsecase{
-		petRepo:  repo,
-		petCache: cache,
+		petRepo:      repo,
+		petCache:     cache,
+		imageStorage: imageStorage,
+		userClient:   userClient,
+		publisher:    eventPublisher,
+		cfg:          cfg,
 	}
 }
 
@@ -34,6 +96,19 @@ func (uc *petUsecase) CreatePet(ctx context.Context, reqData CreatePetRequestDat
 	if reqData.Age < 0 {
 		return nil, errors.New("pet age cannot be negative")
 	}
+	switch reqData.AdoptionStatus {
+	case "", domain.StatusUnspecified, domain.StatusAvailable:
+		reqData.AdoptionStatus = domain.StatusAvailable
+	case domain.StatusPendingAdoption:
+		if !reqData.CallerCanSetStatus {
+			return nil, ErrInitialStatusForbidden
+		}
+	default:
+		return nil, ErrInvalidInitialStatus
+	}
+	if len(reqData.ImageURLs) > 0 {
+		return nil, fmt.Errorf("%w: upload images once the pet is created", ErrInvalidImageURL)
+	}
 	// Optional: Validate ListedByUserID if it's mandatory or by calling user service
 
 	newPet := &domain.Pet{
@@ -41,10 +116,10 @@ func (uc *petUsecase) CreatePet(ctx context.Context, reqData CreatePetRequestDat
 		Species:        reqData.Species,
 		Breed:          reqData.Breed,
 		Age:            reqData.Age,
-		Description:    reqData.Description,
+		Description:    textnorm.Normalize(reqData.Description, uc.cfg.MaxDescriptionLength),
 		ListedByUserID: reqData.ListedByUserID,
-		ImageURLs:      reqData.ImageURLs,
-		// AdoptionStatus will be defaulted by PrepareForCreate in the domain or repo
+		Tags:           domain.NormalizeTags(reqData.Tags),
+		AdoptionStatus: reqData.AdoptionStatus,
 	}
 	// newPet.PrepareForCreate() // This is called by the repository in our current setup
 
@@ -54,6 +129,14 @@ func (uc *petUsecase) CreatePet(ctx context.Context, reqData CreatePetRequestDat
 		return nil, fmt.Errorf("could not create pet: %w", err)
 	}
 
+	uc.invalidateFacets(ctx)
+	uc.invalidateListedPets(ctx)
+	if uc.cfg.SeedCacheOnCreate {
+		if cacheErr := uc.petCache.SetPet(ctx, createdPet.ID, createdPet, petCacheTTL); cacheErr != nil {
+			log.Printf("Pet Service | Warning: Failed to seed cache with new pet %s: %v", createdPet.ID, cacheErr)
+		}
+	}
+
 	log.Printf("Pet Service | Pet created successfully: %s (ID: %s)", createdPet.Name, createdPet.ID)
 	return createdPet, nil
 }
@@ -66,15 +149,18 @@ func (uc *petUsecase) GetPetByID(ctx context.Context, id string) (*domain.Pet, e
 	// 1. Try cache
 	cachedPet, err := uc.petCache.GetPet(ctx, id)
 	if err == nil && cachedPet != nil {
-		log.Printf("Pet Service | Pet %s found in cache", id)
+		logging.Debugf("Pet %s found in cache", id)
+		metrics.CacheHits.Inc(metrics.CachePet)
 		return cachedPet, nil
 	}
-	if err != nil && err.Error() != "pet not found in cache" {
-		log.Printf("Pet Service | Error fetching pet %s from cache: %v", id, err)
-	}
+	metrics.CacheMisses.Inc(metrics.CachePet)
 
 	// 2. Not in cache or cache error, get from repository
-	log.Printf("Pet Service | Pet %s not in cache or cache error, fetching from repository", id)
+	if err != nil && !errors.Is(err, repository.ErrCacheMiss) {
+		logging.Warnf("Error fetching pet %s from cache, falling back to repository: %v", id, err)
+	} else {
+		logging.Debugf("Pet %s not in cache, fetching from repository", id)
+	}
 	pet, err := uc.petRepo.GetPetByID(ctx, id)
 	if err != nil {
 		log.Printf("Pet Service | Error fetching pet %s from repository: %v", id, err)
@@ -82,7 +168,7 @@ func (uc *petUsecase) GetPetByID(ctx context.Context, id string) (*domain.Pet, e
 	}
 
 	// 3. Set in cache
-	cacheErr := uc.petCache.SetPet(ctx, id, pet, 1*time.Hour) // Example 1-hour cache
+	cacheErr := uc.petCache.SetPet(ctx, id, pet, petCacheTTL)
 	if cacheErr != nil {
 		log.Printf("Pet Service | Warning: Failed to set pet %s in cache: %v", id, cacheErr)
 	}
@@ -90,10 +176,72 @@ func (uc *petUsecase) GetPetByID(ctx context.Context, id string) (*domain.Pet, e
 	return pet, nil
 }
 
-func (uc *petUsecase) UpdatePet(ctx context.Context, id string, reqData UpdatePetRequestData) (*domain.Pet, error) {
+// BatchGetPets returns the pets with the given IDs, in the order of ids and without duplicates.
+// Each pet is looked up in the cache first; the misses are read from the repository in a single
+// query and cached. IDs of pets that do not exist are left out.
+func (uc *petUsecase) BatchGetPets(ctx context.Context, ids []string) ([]*domain.Pet, error) {
+	ids = slices.DeleteFunc(slices.Clone(ids), func(id string) bool { return id == "" })
+	unique := slices.Clone(ids)
+	slices.Sort(unique)
+	unique = slices.Compact(unique)
+	if len(unique) > MaxBatchGetPets {
+		return nil, ErrTooManyPetIDs
+	}
+
+	// 1. Try cache
+	found := make(map[string]*domain.Pet, len(unique))
+	var misses []string
+	for _, id := range unique {
+		cachedPet, err := uc.petCache.GetPet(ctx, id)
+		if err == nil && cachedPet != nil {
+			metrics.CacheHits.Inc(metrics.CachePet)
+			found[id] = cachedPet
+			continue
+		}
+		metrics.CacheMisses.Inc(metrics.CachePet)
+		if err != nil && !errors.Is(err, repository.ErrCacheMiss) {
+			logging.Warnf("Error fetching pet %s from cache, falling back to repository: %v", id, err)
+		}
+		misses = append(misses, id)
+	}
+
+	// 2. Fetch the misses in one query
+	if len(misses) > 0 {
+		pets, err := uc.petRepo.GetPetsByIDs(ctx, misses)
+		if err != nil {
+			log.Printf("Pet Service | Error fetching %d pets from repository: %v", len(misses), err)
+			return nil, fmt.Errorf("could not get pets: %w", err)
+		}
+		// 3. Set in cache
+		for _, pet := range pets {
+			found[pet.ID] = pet
+			if cacheErr := uc.petCache.SetPet(ctx, pet.ID, pet, petCacheTTL); cacheErr != nil {
+				log.Printf("Pet Service | Warning: Failed to set pet %s in cache: %v", pet.ID, cacheErr)
+			}
+		}
+	}
+
+	pets := make([]*domain.Pet, 0, len(found))
+	for _, id := range ids {
+		if pet, ok := found[id]; ok {
+			pets = append(pets, pet)
+			delete(found, id)
+		}
+	}
+	return pets, nil
+}
+
+// UpdatePet applies reqData to the pet. Only the user who listed the pet may update it.
+func (uc *petUsecase) UpdatePet(ctx context.Context, id string, reqData UpdatePetRequestData, callerUserID string) (*domain.Pet, error) {
 	if id == "" {
 		return nil, errors.New("pet ID is required for update")
 	}
+	if reqData.Name == nil && reqData.Species == nil && reqData.Breed == nil && reqData.Age == nil && reqData.Description == nil && reqData.ImageURLs == nil {
+		return nil, ErrNoFieldsToUpdate
+	}
+	if err := uc.checkImageCount(0, countNewImageURLs(nil, reqData.ImageURLs)); err != nil {
+		return nil, err
+	}
 
 	// Fetch existing pet
 	pet, err := uc.petRepo.GetPetByID(ctx, id)
@@ -101,6 +249,16 @@ func (uc *petUsecase) UpdatePet(ctx context.Context, id string, reqData UpdatePe
 		log.Printf("Pet Service | Error fetching pet %s for update: %v", id, err)
 		return nil, err // Could be "pet not found"
 	}
+	if err := checkPetOwner(pet, callerUserID); err != nil {
+		return nil, err
+	}
+	// The replacement may keep, reorder or drop the pet's images, but only add its own uploads.
+	for _, raw := range reqData.ImageURLs {
+		if !slices.Contains(pet.ImageURLs, raw) && (uc.imageStorage == nil || !uc.isPetImageURL(id, raw)) {
+			log.Printf("Pet Service | Rejected image URL %q for pet %s update: not served from the pet's storage prefix", raw, id)
+			return nil, ErrInvalidImageURL
+		}
+	}
 
 	// Apply updates from reqData
 	updated := false
@@ -120,9 +278,11 @@ func (uc *petUsecase) UpdatePet(ctx context.Context, id string, reqData UpdatePe
 		pet.Age = *reqData.Age
 		updated = true
 	}
-	if reqData.Description != nil && *reqData.Description != pet.Description {
-		pet.Description = *reqData.Description
-		updated = true
+	if reqData.Description != nil {
+		if description := textnorm.Normalize(*reqData.Description, uc.cfg.MaxDescriptionLength); description != pet.Description {
+			pet.Description = description
+			updated = true
+		}
 	}
 	if reqData.ImageURLs != nil { // Assuming full replacement of ImageURLs
 		pet.ImageURLs = reqData.ImageURLs
@@ -147,23 +307,30 @@ func (uc *petUsecase) UpdatePet(ctx context.Context, id string, reqData UpdatePe
 	if cacheErr != nil {
 		log.Printf("Pet Service | Warning: Failed to delete pet %s from cache after update: %v", id, cacheErr)
 	}
+	uc.invalidateFacets(ctx) // Species or breed may have changed
+	uc.invalidateListedPets(ctx)
 
 	log.Printf("Pet Service | Pet updated successfully: ID %s", id)
 	return updatedPet, nil
 }
 
-func (uc *petUsecase) DeletePet(ctx context.Context, id string) error {
+// DeletePet soft-deletes the pet: it is no longer found or listed, but its document and history
+// are kept so it can be restored. Only the user who listed the pet may delete it.
+func (uc *petUsecase) DeletePet(ctx context.Context, id string, callerUserID string) error {
 	if id == "" {
 		return errors.New("pet ID is required for deletion")
 	}
 
-	// Optional: Check if pet exists before trying to delete, to return a more specific "not found"
-	// _, err := uc.petRepo.GetPetByID(ctx, id)
-	// if err != nil {
-	// 	return err // Could be "pet not found"
-	// }
+	pet, err := uc.petRepo.GetPetByID(ctx, id)
+	if err != nil {
+		log.Printf("Pet Service | Error fetching pet %s for deletion: %v", id, err)
+		return err // Could be "pet not found"
+	}
+	if err := checkPetOwner(pet, callerUserID); err != nil {
+		return err
+	}
 
-	err := uc.petRepo.DeletePet(ctx, id)
+	err = uc.petRepo.DeletePet(ctx, id)
 	if err != nil {
 		log.Printf("Pet Service | Error deleting pet %s from repository: %v", id, err)
 		return fmt.Errorf("could not delete pet: %w", err)
@@ -174,16 +341,61 @@ func (uc *petUsecase) DeletePet(ctx context.Context, id string) error {
 	if cacheErr != nil {
 		log.Printf("Pet Service | Warning: Failed to delete pet %s from cache after DB deletion: %v", id, cacheErr)
 	}
+	uc.invalidateFacets(ctx)
+	uc.invalidateListedPets(ctx)
 
 	log.Printf("Pet Service | Pet deleted successfully: ID %s", id)
 	return nil
 }
 
-func (uc *petUsecase) ListPets(ctx context.Context, page, limit int, filters map[string]interface{}) ([]*domain.Pet, int64, error) {
-	// TODO: Implement caching for list operations if beneficial.
-	// This can be complex due to varying filters and pagination.
-	// For now, directly fetch from repository.
+// RestorePet undoes DeletePet. Only the user who listed the pet, or an admin, may restore it.
+func (uc *petUsecase) RestorePet(ctx context.Context, id string, callerUserID string, callerIsAdmin bool) (*domain.Pet, error) {
+	if id == "" {
+		return nil, errors.New("pet ID is required for restore")
+	}
+
+	pet, err := uc.petRepo.GetPetByIDIncludingDeleted(ctx, id)
+	if err != nil {
+		log.Printf("Pet Service | Error fetching pet %s for restore: %v", id, err)
+		return nil, err // Could be "pet not found"
+	}
+	if !pet.Deleted {
+		return nil, ErrPetNotDeleted
+	}
+	if !callerIsAdmin {
+		if err := checkPetOwner(pet, callerUserID); err != nil {
+			return nil, err
+		}
+	}
+
+	restoredPet, err := uc.petRepo.RestorePet(ctx, id)
+	if err != nil {
+		log.Printf("Pet Service | Error restoring pet %s in repository: %v", id, err)
+		return nil, fmt.Errorf("could not restore pet: %w", err)
+	}
+
+	// Invalidate cache
+	cacheErr := uc.petCache.DeletePet(ctx, id)
+	if cacheErr != nil {
+		log.Printf("Pet Service | Warning: Failed to delete pet %s from cache after restore: %v", id, cacheErr)
+	}
+	uc.invalidateFacets(ctx)
+	uc.invalidateListedPets(ctx)
+
+	log.Printf("Pet Service | Pet restored successfully: ID %s by %s", id, callerUserID)
+	return restoredPet, nil
+}
+
+// checkPetOwner returns ErrNotPetOwner unless callerUserID listed the pet.
+func checkPetOwner(pet *domain.Pet, callerUserID string) error {
+	if callerUserID == "" || pet.ListedByUserID == "" || callerUserID != pet.ListedByUserID {
+		log.Printf("Pet Service | User %q is not the owner of pet %s (owner %q)", callerUserID, pet.ID, pet.ListedByUserID)
+		return ErrNotPetOwner
+	}
+	return nil
+}
 
+func (uc *petUsecase) ListPets(ctx context.Context, page, limit int, filters map[string]interface{}) ([]*domain.Pet, int64, error) {
 	// Sanitize/validate filters if necessary
 	// For example, if filtering by domain.AdoptionStatus, ensure the value is valid
 	if statusStr, ok := filters["adoption_status"].(string); ok {
@@ -194,17 +406,84 @@ func (uc *petUsecase) ListPets(ctx context.Context, page, limit int, filters map
 			delete(filters, "adoption_status")
 		}
 	}
+	if tags, ok := filters[repository.FilterTags].([]string); ok {
+		if normalized := domain.NormalizeTags(tags); len(normalized) > 0 {
+			filters[repository.FilterTags] = normalized
+		} else {
+			delete(filters, repository.FilterTags)
+			delete(filters, repository.FilterTagsMatchAll)
+		}
+	}
+	minAge, hasMinAge := filters[repository.FilterMinAge].(int32)
+	maxAge, hasMaxAge := filters[repository.FilterMaxAge].(int32)
+	if (hasMinAge && minAge < 0) || (hasMaxAge && maxAge < 0) || (hasMinAge && hasMaxAge && minAge > maxAge) {
+		return nil, 0, ErrInvalidAgeRange
+	}
+	if search, ok := filters[repository.FilterSearch].(string); ok {
+		if search = strings.TrimSpace(search); search != "" {
+			filters[repository.FilterSearch] = search
+		} else {
+			delete(filters, repository.FilterSearch)
+		}
+	}
+	if sort, ok := filters[repository.FilterSort].(string); ok {
+		switch sort {
+		case repository.SortNewest, repository.SortOldest:
+		case "":
+			delete(filters, repository.FilterSort)
+		default:
+			return nil, 0, errors.New("invalid sort value")
+		}
+	}
+	// The handler passes the client's cursor string; the repository expects it decoded.
+	if rawCursor, ok := filters[repository.FilterAfter]; ok {
+		if page > 0 {
+			return nil, 0, ErrCursorWithPage
+		}
+		cursorStr, _ := rawCursor.(string)
+		var after *repository.PageCursor
+		if cursorStr != "" {
+			decoded, err := repository.DecodePageCursor(cursorStr)
+			if err != nil {
+				return nil, 0, ErrInvalidCursor
+			}
+			after = decoded
+		}
+		filters[repository.FilterAfter] = after
+	}
 
+	// 1. Try cache
+	if uc.cfg.ListCacheTTL > 0 {
+		cached, cachedCount, err := uc.petCache.GetListedPets(ctx, page, limit, filters)
+		if err == nil {
+			metrics.CacheHits.Inc(metrics.CacheList)
+			return cached, cachedCount, nil
+		}
+		metrics.CacheMisses.Inc(metrics.CacheList)
+		if !errors.Is(err, repository.ErrCacheMiss) {
+			logging.Warnf("Error fetching listed pets from cache, falling back to repository: %v", err)
+		}
+	}
 
+	// 2. Not in cache (expired or invalidated), get from repository
 	pets, totalCount, err := uc.petRepo.ListPets(ctx, page, limit, filters)
 	if err != nil {
 		log.Printf("Pet Service | Error listing pets from repository: %v", err)
 		return nil, 0, fmt.Errorf("could not list pets: %w", err)
 	}
+
+	// 3. Set in cache
+	if uc.cfg.ListCacheTTL > 0 {
+		if cacheErr := uc.petCache.SetListedPets(ctx, page, limit, filters, pets, totalCount, uc.cfg.ListCacheTTL); cacheErr != nil {
+			log.Printf("Pet Service | Warning: Failed to set listed pets in cache: %v", cacheErr)
+		}
+	}
 	return pets, totalCount, nil
 }
 
-func (uc *petUsecase) UpdatePetAdoptionStatus(ctx context.Context, id string, newStatus domain.AdoptionStatus, adopterUserID *string) (*domain.Pet, error) {
+// UpdatePetAdoptionStatus moves a pet to newStatus. The change is recorded in the pet's status
+// history together with the optional reason and the user who made it.
+func (uc *petUsecase) UpdatePetAdoptionStatus(ctx context.Context, id string, newStatus domain.AdoptionStatus, adopterUserID *string, reason, changedBy string) (*domain.Pet, error) {
 	if id == "" {
 		return nil, errors.New("pet ID is required for status update")
 	}
@@ -214,14 +493,25 @@ func (uc *petUsecase) UpdatePetAdoptionStatus(ctx context.Context, id string, ne
 	if newStatus == domain.StatusAdopted && (adopterUserID == nil || *adopterUserID == "") {
 		return nil, errors.New("adopter user ID is required when setting status to ADOPTED")
 	}
+	reason = strings.TrimSpace(reason)
+	if utf8.RuneCountInString(reason) > domain.MaxStatusReasonLength {
+		return nil, ErrStatusReasonTooLong
+	}
 
-	// Optional: Fetch pet first to ensure it exists or to perform other checks
-	// _, err := uc.petRepo.GetPetByID(ctx, id)
-	// if err != nil {
-	// 	return nil, err // Could be "pet not found"
-	// }
+	// Fetch the pet first so the update only applies to the version we saw
+	pet, err := uc.petRepo.GetPetByID(ctx, id)
+	if err != nil {
+		return nil, err // Could be "pet not found"
+	}
 
-	updatedPet, err := uc.petRepo.UpdatePetAdoptionStatus(ctx, id, newStatus, adopterUserID)
+	change := domain.StatusChange{
+		FromStatus: pet.AdoptionStatus,
+		ToStatus:   newStatus,
+		Reason:     reason,
+		ChangedBy:  changedBy,
+		ChangedAt:  time.Now().UTC(),
+	}
+	updatedPet, err := uc.petRepo.UpdatePetAdoptionStatus(ctx, id, pet.Version, change, adopterUserID)
 	if err != nil {
 		log.Printf("Pet Service | Error updating pet adoption status for ID %s: %v", id, err)
 		return nil, fmt.Errorf("could not update pet adoption status: %w", err)
@@ -232,7 +522,388 @@ func (uc *petUsecase) UpdatePetAdoptionStatus(ctx context.Context, id string, ne
 	if cacheErr != nil {
 		log.Printf("Pet Service | Warning: Failed to delete pet %s from cache after status update: %v", id, cacheErr)
 	}
+	uc.invalidateFacets(ctx) // Status counts changed
+	uc.invalidateListedPets(ctx)
+	uc.publishIfUnavailable(ctx, pet.AdoptionStatus, updatedPet)
 
 	log.Printf("Pet Service | Pet adoption status updated successfully for ID: %s to %s", id, newStatus)
 	return updatedPet, nil
-}
\ No newline at end of file
+}
+
+// publishIfUnavailable publishes a pet.unavailable event when the pet just became ADOPTED. A failed
+// publish is only logged: the status change has been saved and must not be reported as failed.
+func (uc *petUsecase) publishIfUnavailable(ctx context.Context, previousStatus domain.AdoptionStatus, pet *domain.Pet) {
+	if uc.publisher == nil || pet == nil || previousStatus == domain.StatusAdopted || pet.AdoptionStatus != domain.StatusAdopted {
+		return
+	}
+	if err := uc.publisher.PublishPetUnavailable(ctx, pet); err != nil {
+		log.Printf("Pet Service | Warning: Failed to publish pet.unavailable event for pet %s: %v", pet.ID, err)
+	}
+}
+
+// AdminSetPetStatus moves a pet to any status for data corrections. Unlike UpdatePetAdoptionStatus it
+// skips the transition rules (e.g. ADOPTED without an adopter), so a reason is mandatory and the change
+// is recorded in the pet's status history and the service log.
+func (uc *petUsecase) AdminSetPetStatus(ctx context.Context, petID string, newStatus domain.AdoptionStatus, reason, changedBy string) (*domain.Pet, error) {
+	if petID == "" {
+		return nil, errors.New("pet ID is required for status update")
+	}
+	if newStatus == domain.StatusUnspecified || !domain.IsValidAdoptionStatus(newStatus) {
+		return nil, errors.New("invalid new adoption status")
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, errors.New("a reason is required for an admin status override")
+	}
+
+	pet, err := uc.petRepo.GetPetByID(ctx, petID)
+	if err != nil {
+		log.Printf("Pet Service | Error fetching pet %s for admin status override: %v", petID, err)
+		return nil, err // Could be "pet not found"
+	}
+
+	change := domain.StatusChange{
+		FromStatus: pet.AdoptionStatus,
+		ToStatus:   newStatus,
+		Reason:     reason,
+		ChangedBy:  changedBy,
+		Forced:     true,
+		ChangedAt:  time.Now().UTC(),
+	}
+	updatedPet, err := uc.petRepo.ForcePetAdoptionStatus(ctx, petID, change)
+	if err != nil {
+		log.Printf("Pet Service | Error forcing adoption status for pet %s: %v", petID, err)
+		return nil, fmt.Errorf("could not force pet adoption status: %w", err)
+	}
+
+	// Invalidate cache for this pet
+	cacheErr := uc.petCache.DeletePet(ctx, petID)
+	if cacheErr != nil {
+		log.Printf("Pet Service | Warning: Failed to delete pet %s from cache after admin status override: %v", petID, cacheErr)
+	}
+	uc.invalidateFacets(ctx)
+	uc.invalidateListedPets(ctx)
+	uc.publishIfUnavailable(ctx, change.FromStatus, updatedPet)
+
+	log.Printf("Pet Service | ADMIN OVERRIDE: pet %s status %s -> %s by %q. Reason: %s", petID, change.FromStatus, newStatus, changedBy, reason)
+	return updatedPet, nil
+}
+
+// TransferPetListing reassigns a pet's listing to newOwnerID. Only the current owner or an admin may do
+// this, and the new owner must exist in the user-service. The change is recorded in the listing history.
+func (uc *petUsecase) TransferPetListing(ctx context.Context, petID, newOwnerID, callerID string, callerIsAdmin bool) (*domain.Pet, error) {
+	if petID == "" {
+		return nil, errors.New("pet ID is required for listing transfer")
+	}
+	if newOwnerID == "" {
+		return nil, errors.New("new owner user ID is required")
+	}
+
+	pet, err := uc.petRepo.GetPetByID(ctx, petID)
+	if err != nil {
+		log.Printf("Pet Service | Error fetching pet %s for listing transfer: %v", petID, err)
+		return nil, err // Could be "pet not found"
+	}
+	if !callerIsAdmin && (callerID == "" || callerID != pet.ListedByUserID) {
+		log.Printf("Pet Service | User %q is not allowed to transfer the listing of pet %s (owner %s)", callerID, petID, pet.ListedByUserID)
+		return nil, ErrListingTransferForbidden
+	}
+	if newOwnerID == pet.ListedByUserID {
+		return pet, nil // Already listed by this user
+	}
+
+	if uc.userClient == nil {
+		return nil, errors.New("user service client is not configured")
+	}
+	exists, err := uc.userClient.UserExists(ctx, newOwnerID)
+	if err != nil {
+		return nil, fmt.Errorf("could not verify new owner: %w", err)
+	}
+	if !exists {
+		return nil, ErrNewOwnerNotFound
+	}
+
+	transfer := domain.ListingTransfer{
+		FromUserID:    pet.ListedByUserID,
+		ToUserID:      newOwnerID,
+		TransferredBy: callerID,
+		TransferredAt: time.Now().UTC(),
+	}
+	updatedPet, err := uc.petRepo.TransferPetListing(ctx, petID, pet.Version, transfer)
+	if err != nil {
+		log.Printf("Pet Service | Error transferring listing of pet %s: %v", petID, err)
+		return nil, fmt.Errorf("could not transfer pet listing: %w", err)
+	}
+
+	// Invalidate cache for this pet
+	cacheErr := uc.petCache.DeletePet(ctx, petID)
+	if cacheErr != nil {
+		log.Printf("Pet Service | Warning: Failed to delete pet %s from cache after listing transfer: %v", petID, cacheErr)
+	}
+	uc.invalidateListedPets(ctx)
+
+	log.Printf("Pet Service | Listing of pet %s transferred from %s to %s by %s", petID, transfer.FromUserID, newOwnerID, callerID)
+	return updatedPet, nil
+}
+
+func (uc *petUsecase) GetImageUploadTarget(ctx context.Context, petID, filename, contentType, callerUserID string) (*storage.UploadTarget, error) {
+	if petID == "" {
+		return nil, errors.New("pet ID is required")
+	}
+	if !storage.IsAllowedImageContentType(contentType) {
+		return nil, errors.New("unsupported image content type")
+	}
+	if uc.imageStorage == nil {
+		return nil, errors.New("image storage is not configured")
+	}
+
+	// Make sure the pet exists and belongs to the caller so uploads can't be issued for arbitrary prefixes.
+	pet, err := uc.petRepo.GetPetByID(ctx, petID)
+	if err != nil {
+		log.Printf("Pet Service | Error fetching pet %s for image upload: %v", petID, err)
+		return nil, err // Could be "pet not found"
+	}
+	if err := checkPetOwner(pet, callerUserID); err != nil {
+		return nil, err
+	}
+
+	key := storage.PetImageKey(petID, filename)
+	target, err := uc.imageStorage.PresignUpload(ctx, key, contentType)
+	if err != nil {
+		log.Printf("Pet Service | Error presigning image upload for pet %s: %v", petID, err)
+		return nil, fmt.Errorf("could not create image upload target: %w", err)
+	}
+
+	log.Printf("Pet Service | Image upload target issued for pet %s (key: %s)", petID, key)
+	return target, nil
+}
+
+func (uc *petUsecase) AddImageURLs(ctx context.Context, petID string, imageURLs []string, callerUserID string) (*domain.Pet, error) {
+	if petID == "" {
+		return nil, errors.New("pet ID is required")
+	}
+	if len(imageURLs) == 0 {
+		return nil, errors.New("at least one image URL is required")
+	}
+	if uc.imageStorage == nil {
+		return nil, errors.New("image storage is not configured")
+	}
+	for _, raw := range imageURLs {
+		if !uc.isPetImageURL(petID, raw) {
+			log.Printf("Pet Service | Rejected image URL %q for pet %s: not served from the pet's storage prefix", raw, petID)
+			return nil, ErrInvalidImageURL
+		}
+	}
+
+	pet, err := uc.petRepo.GetPetByID(ctx, petID)
+	if err != nil {
+		log.Printf("Pet Service | Error fetching pet %s to add images: %v", petID, err)
+		return nil, err // Could be "pet not found"
+	}
+	if err := checkPetOwner(pet, callerUserID); err != nil {
+		return nil, err
+	}
+	// $addToSet cannot enforce a cap, so count what the addition would leave the pet with first.
+	if err := uc.checkImageCount(len(pet.ImageURLs), countNewImageURLs(pet.ImageURLs, imageURLs)); err != nil {
+		return nil, err
+	}
+
+	updatedPet, err := uc.petRepo.AddPetImageURLs(ctx, petID, imageURLs)
+	if err != nil {
+		log.Printf("Pet Service | Error adding image URLs to pet %s: %v", petID, err)
+		if err.Error() == "pet not found" {
+			return nil, err
+		}
+		return nil, fmt.Errorf("could not add image URLs: %w", err)
+	}
+
+	// Invalidate cache
+	cacheErr := uc.petCache.DeletePet(ctx, petID)
+	if cacheErr != nil {
+		log.Printf("Pet Service | Warning: Failed to delete pet %s from cache after adding images: %v", petID, cacheErr)
+	}
+	uc.invalidateListedPets(ctx)
+
+	log.Printf("Pet Service | Added %d image URL(s) to pet %s", len(imageURLs), petID)
+	return updatedPet, nil
+}
+
+// isPetImageURL reports whether raw is the public URL of an object under the pet's key prefix,
+// i.e. an image uploaded through GetImageUploadTarget rather than an arbitrary link.
+func (uc *petUsecase) isPetImageURL(petID, raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.RawQuery != "" || u.Fragment != "" {
+		return false
+	}
+	if strings.Contains(u.Path, "..") {
+		return false
+	}
+	name, ok := strings.CutPrefix(raw, uc.imageStorage.PublicURL(storage.PetImageKeyPrefix(petID)))
+	return ok && name != "" && !strings.Contains(name, "/")
+}
+
+// checkImageCount returns ErrTooManyImages if a pet with current images would have more than
+// MaxImagesPerPet once the new ones are added.
+func (uc *petUsecase) checkImageCount(current, added int) error {
+	if limit := uc.cfg.MaxImagesPerPet; limit > 0 && current+added > limit {
+		return fmt.Errorf("%w: a pet can have at most %d images", ErrTooManyImages, limit)
+	}
+	return nil
+}
+
+// countNewImageURLs returns how many distinct URLs of added are not in existing yet, i.e. how
+// many images adding them would add.
+func countNewImageURLs(existing, added []string) int {
+	seen := make(map[string]bool, len(existing)+len(added))
+	for _, u := range existing {
+		seen[u] = true
+	}
+	count := 0
+	for _, u := range added {
+		if !seen[u] {
+			seen[u] = true
+			count++
+		}
+	}
+	return count
+}
+
+func (uc *petUsecase) AddPetTags(ctx context.Context, petID string, tags []string, callerUserID string) (*domain.Pet, error) {
+	return uc.updatePetTags(ctx, petID, tags, callerUserID, uc.petRepo.AddPetTags, "add")
+}
+
+func (uc *petUsecase) RemovePetTags(ctx context.Context, petID string, tags []string, callerUserID string) (*domain.Pet, error) {
+	return uc.updatePetTags(ctx, petID, tags, callerUserID, uc.petRepo.RemovePetTags, "remove")
+}
+
+func (uc *petUsecase) updatePetTags(ctx context.Context, petID string, tags []string, callerUserID string, apply func(context.Context, string, []string) (*domain.Pet, error), action string) (*domain.Pet, error) {
+	if petID == "" {
+		return nil, errors.New("pet ID is required")
+	}
+	normalized := domain.NormalizeTags(tags)
+	if len(normalized) == 0 {
+		return nil, errors.New("at least one valid tag is required")
+	}
+
+	pet, err := uc.petRepo.GetPetByID(ctx, petID)
+	if err != nil {
+		log.Printf("Pet Service | Error fetching pet %s to %s tags: %v", petID, action, err)
+		return nil, err // Could be "pet not found"
+	}
+	if err := checkPetOwner(pet, callerUserID); err != nil {
+		return nil, err
+	}
+
+	updatedPet, err := apply(ctx, petID, normalized)
+	if err != nil {
+		log.Printf("Pet Service | Error trying to %s tags on pet %s: %v", action, petID, err)
+		if err.Error() == "pet not found" {
+			return nil, err
+		}
+		return nil, fmt.Errorf("could not %s pet tags: %w", action, err)
+	}
+
+	// Invalidate cache
+	cacheErr := uc.petCache.DeletePet(ctx, petID)
+	if cacheErr != nil {
+		log.Printf("Pet Service | Warning: Failed to delete pet %s from cache after tag update: %v", petID, cacheErr)
+	}
+	uc.invalidateListedPets(ctx)
+	return updatedPet, nil
+}
+
+// maxRecentlyAdoptedLimit caps the showcase size so the public endpoint stays cheap.
+const maxRecentlyAdoptedLimit = 50
+
+func (uc *petUsecase) ListRecentlyAdopted(ctx context.Context, limit int) ([]*domain.Pet, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > maxRecentlyAdoptedLimit {
+		limit = maxRecentlyAdoptedLimit
+	}
+
+	pets, err := uc.petRepo.ListRecentlyAdopted(ctx, limit)
+	if err != nil {
+		log.Printf("Pet Service | Error listing recently adopted pets from repository: %v", err)
+		return nil, fmt.Errorf("could not list recently adopted pets: %w", err)
+	}
+	return pets, nil
+}
+
+// maxBreedSuggestions caps the breed suggestions returned for one autocomplete request.
+const maxBreedSuggestions = 50
+
+func (uc *petUsecase) SuggestBreeds(ctx context.Context, species, prefix string, limit int) ([]string, error) {
+	species = strings.TrimSpace(species)
+	if species == "" {
+		return nil, ErrSpeciesRequired
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > maxBreedSuggestions {
+		limit = maxBreedSuggestions
+	}
+
+	breeds, err := uc.petRepo.SuggestBreeds(ctx, species, strings.TrimSpace(prefix), limit)
+	if err != nil {
+		log.Printf("Pet Service | Error suggesting breeds for species %s from repository: %v", species, err)
+		return nil, fmt.Errorf("could not suggest breeds: %w", err)
+	}
+	return breeds, nil
+}
+
+func (uc *petUsecase) GetPetFacets(ctx context.Context) (*domain.PetFacets, error) {
+	// 1. Try cache
+	if uc.cfg.FacetsCacheTTL > 0 {
+		cached, err := uc.petCache.GetPetFacets(ctx)
+		if err == nil && cached != nil {
+			metrics.CacheHits.Inc(metrics.CacheFacets)
+			return cached, nil
+		}
+		metrics.CacheMisses.Inc(metrics.CacheFacets)
+		if err != nil && !errors.Is(err, repository.ErrCacheMiss) {
+			log.Printf("Pet Service | Error fetching pet facets from cache: %v", err)
+		}
+	}
+
+	// 2. Not in cache (expired or invalidated), run the aggregation
+	facets, err := uc.petRepo.GetPetFacets(ctx)
+	if err != nil {
+		log.Printf("Pet Service | Error computing pet facets in repository: %v", err)
+		return nil, fmt.Errorf("could not get pet facets: %w", err)
+	}
+
+	// 3. Set in cache
+	if uc.cfg.FacetsCacheTTL > 0 {
+		if cacheErr := uc.petCache.SetPetFacets(ctx, facets, uc.cfg.FacetsCacheTTL); cacheErr != nil {
+			log.Printf("Pet Service | Warning: Failed to set pet facets in cache: %v", cacheErr)
+		}
+	}
+	return facets, nil
+}
+
+// invalidateFacets drops the cached facets after a write that changes the counts,
+// so the next GetPetFacets call recomputes them.
+func (uc *petUsecase) invalidateFacets(ctx context.Context) {
+	if uc.cfg.FacetsCacheTTL <= 0 {
+		return
+	}
+	if cacheErr := uc.petCache.DeletePetFacets(ctx); cacheErr != nil {
+		log.Printf("Pet Service | Warning: Failed to invalidate pet facets cache: %v", cacheErr)
+	}
+}
+
+// invalidateListedPets drops every cached ListPets page after a write to any pet. Working out
+// which pages the pet appears on is not worth it: a write can move a pet into or out of any
+// filtered listing and shifts the pages after it. A page cached by a listing that read the
+// database before the write is only served until ListCacheTTL expires.
+func (uc *petUsecase) invalidateListedPets(ctx context.Context) {
+	if uc.cfg.ListCacheTTL <= 0 {
+		return
+	}
+	if cacheErr := uc.petCache.DeleteListedPets(ctx); cacheErr != nil {
+		log.Printf("Pet Service | Warning: Failed to invalidate listed pets cache: %v", cacheErr)
+	}
+}
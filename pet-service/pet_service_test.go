@@ -1,16 +1,41 @@
 package main_test // Or use a package name like 'petservicetest'
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	// Adjust these import paths to match your project's module path and structure
+	pb "github.com/zhandarbeks/petstore-final-project/genprotos/pet"
+	"github.com/zhandarbeks/petstore-final-project/internal/gatewayauth"
+	"github.com/zhandarbeks/petstore-final-project/internal/testutil"
+	"github.com/zhandarbeks/petstore-final-project/pet-service/internal/client"
 	"github.com/zhandarbeks/petstore-final-project/pet-service/internal/domain"
+	"github.com/zhandarbeks/petstore-final-project/pet-service/internal/handler"
+	"github.com/zhandarbeks/petstore-final-project/pet-service/internal/metrics"
+	"github.com/zhandarbeks/petstore-final-project/pet-service/internal/migrate"
 	"github.com/zhandarbeks/petstore-final-project/pet-service/internal/repository" // For mock repository
+	"github.com/zhandarbeks/petstore-final-project/pet-service/internal/server"
+	"github.com/zhandarbeks/petstore-final-project/pet-service/internal/storage"
 	"github.com/zhandarbeks/petstore-final-project/pet-service/internal/usecase"
 
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
 	// Optional: for assertions, e.g., "github.com/stretchr/testify/assert"
 	// Optional: for mocking, e.g., "github.com/stretchr/testify/mock"
 )
@@ -22,10 +47,21 @@ import (
 type MockPetRepository struct {
 	CreatePetFunc               func(ctx context.Context, pet *domain.Pet) (*domain.Pet, error)
 	GetPetByIDFunc              func(ctx context.Context, id string) (*domain.Pet, error)
+	GetPetsByIDsFunc            func(ctx context.Context, ids []string) ([]*domain.Pet, error)
+	GetPetByIDIncludingDeletedFunc func(ctx context.Context, id string) (*domain.Pet, error)
+	RestorePetFunc              func(ctx context.Context, id string) (*domain.Pet, error)
 	UpdatePetFunc               func(ctx context.Context, pet *domain.Pet) (*domain.Pet, error)
 	DeletePetFunc               func(ctx context.Context, id string) error
 	ListPetsFunc                func(ctx context.Context, page, limit int, filters map[string]interface{}) ([]*domain.Pet, int64, error)
-	UpdatePetAdoptionStatusFunc func(ctx context.Context, id string, newStatus domain.AdoptionStatus, adopterUserID *string) (*domain.Pet, error)
+	UpdatePetAdoptionStatusFunc func(ctx context.Context, id string, expectedVersion int, change domain.StatusChange, adopterUserID *string) (*domain.Pet, error)
+	AddPetImageURLsFunc         func(ctx context.Context, id string, imageURLs []string) (*domain.Pet, error)
+	ListRecentlyAdoptedFunc     func(ctx context.Context, limit int) ([]*domain.Pet, error)
+	GetPetFacetsFunc            func(ctx context.Context) (*domain.PetFacets, error)
+	AddPetTagsFunc              func(ctx context.Context, id string, tags []string) (*domain.Pet, error)
+	RemovePetTagsFunc           func(ctx context.Context, id string, tags []string) (*domain.Pet, error)
+	ForcePetAdoptionStatusFunc  func(ctx context.Context, id string, change domain.StatusChange) (*domain.Pet, error)
+	TransferPetListingFunc      func(ctx context.Context, id string, expectedVersion int, transfer domain.ListingTransfer) (*domain.Pet, error)
+	SuggestBreedsFunc           func(ctx context.Context, species, prefix string, limit int) ([]string, error)
 }
 
 // Ensure MockPetRepository implements repository.PetRepository
@@ -45,6 +81,27 @@ func (m *MockPetRepository) GetPetByID(ctx context.Context, id string) (*domain.
 	return nil, errors.New("GetPetByIDFunc not implemented in mock")
 }
 
+func (m *MockPetRepository) GetPetsByIDs(ctx context.Context, ids []string) ([]*domain.Pet, error) {
+	if m.GetPetsByIDsFunc != nil {
+		return m.GetPetsByIDsFunc(ctx, ids)
+	}
+	return nil, errors.New("GetPetsByIDsFunc not implemented in mock")
+}
+
+func (m *MockPetRepository) GetPetByIDIncludingDeleted(ctx context.Context, id string) (*domain.Pet, error) {
+	if m.GetPetByIDIncludingDeletedFunc != nil {
+		return m.GetPetByIDIncludingDeletedFunc(ctx, id)
+	}
+	return nil, errors.New("GetPetByIDIncludingDeletedFunc not implemented in mock")
+}
+
+func (m *MockPetRepository) RestorePet(ctx context.Context, id string) (*domain.Pet, error) {
+	if m.RestorePetFunc != nil {
+		return m.RestorePetFunc(ctx, id)
+	}
+	return nil, errors.New("RestorePetFunc not implemented in mock")
+}
+
 func (m *MockPetRepository) UpdatePet(ctx context.Context, pet *domain.Pet) (*domain.Pet, error) {
 	if m.UpdatePetFunc != nil {
 		return m.UpdatePetFunc(ctx, pet)
@@ -66,18 +123,94 @@ func (m *MockPetRepository) ListPets(ctx context.Context, page, limit int, filte
 	return nil, 0, errors.New("ListPetsFunc not implemented in mock")
 }
 
-func (m *MockPetRepository) UpdatePetAdoptionStatus(ctx context.Context, id string, newStatus domain.AdoptionStatus, adopterUserID *string) (*domain.Pet, error) {
+func (m *MockPetRepository) UpdatePetAdoptionStatus(ctx context.Context, id string, expectedVersion int, change domain.StatusChange, adopterUserID *string) (*domain.Pet, error) {
 	if m.UpdatePetAdoptionStatusFunc != nil {
-		return m.UpdatePetAdoptionStatusFunc(ctx, id, newStatus, adopterUserID)
+		return m.UpdatePetAdoptionStatusFunc(ctx, id, expectedVersion, change, adopterUserID)
 	}
 	return nil, errors.New("UpdatePetAdoptionStatusFunc not implemented in mock")
 }
 
+func (m *MockPetRepository) AddPetImageURLs(ctx context.Context, id string, imageURLs []string) (*domain.Pet, error) {
+	if m.AddPetImageURLsFunc != nil {
+		return m.AddPetImageURLsFunc(ctx, id, imageURLs)
+	}
+	return nil, errors.New("AddPetImageURLsFunc not implemented in mock")
+}
+
+func (m *MockPetRepository) ListRecentlyAdopted(ctx context.Context, limit int) ([]*domain.Pet, error) {
+	if m.ListRecentlyAdoptedFunc != nil {
+		return m.ListRecentlyAdoptedFunc(ctx, limit)
+	}
+	return nil, errors.New("ListRecentlyAdoptedFunc not implemented in mock")
+}
+
+func (m *MockPetRepository) SuggestBreeds(ctx context.Context, species, prefix string, limit int) ([]string, error) {
+	if m.SuggestBreedsFunc != nil {
+		return m.SuggestBreedsFunc(ctx, species, prefix, limit)
+	}
+	return nil, errors.New("SuggestBreedsFunc not implemented in mock")
+}
+
+func (m *MockPetRepository) GetPetFacets(ctx context.Context) (*domain.PetFacets, error) {
+	if m.GetPetFacetsFunc != nil {
+		return m.GetPetFacetsFunc(ctx)
+	}
+	return nil, errors.New("GetPetFacetsFunc not implemented in mock")
+}
+
+func (m *MockPetRepository) AddPetTags(ctx context.Context, id string, tags []string) (*domain.Pet, error) {
+	if m.AddPetTagsFunc != nil {
+		return m.AddPetTagsFunc(ctx, id, tags)
+	}
+	return nil, errors.New("AddPetTagsFunc not implemented in mock")
+}
+
+func (m *MockPetRepository) RemovePetTags(ctx context.Context, id string, tags []string) (*domain.Pet, error) {
+	if m.RemovePetTagsFunc != nil {
+		return m.RemovePetTagsFunc(ctx, id, tags)
+	}
+	return nil, errors.New("RemovePetTagsFunc not implemented in mock")
+}
+func (m *MockPetRepository) ForcePetAdoptionStatus(ctx context.Context, id string, change domain.StatusChange) (*domain.Pet, error) {
+	if m.ForcePetAdoptionStatusFunc != nil {
+		return m.ForcePetAdoptionStatusFunc(ctx, id, change)
+	}
+	return nil, errors.New("ForcePetAdoptionStatusFunc not implemented in mock")
+}
+func (m *MockPetRepository) TransferPetListing(ctx context.Context, id string, expectedVersion int, transfer domain.ListingTransfer) (*domain.Pet, error) {
+	if m.TransferPetListingFunc != nil {
+		return m.TransferPetListingFunc(ctx, id, expectedVersion, transfer)
+	}
+	return nil, errors.New("TransferPetListingFunc not implemented in mock")
+}
+
+// MockUserServiceClient is a mock implementation of client.UserServiceClient.
+type MockUserServiceClient struct {
+	UserExistsFunc func(ctx context.Context, userID string) (bool, error)
+}
+
+// Ensure MockUserServiceClient implements client.UserServiceClient
+var _ client.UserServiceClient = (*MockUserServiceClient)(nil)
+
+func (m *MockUserServiceClient) UserExists(ctx context.Context, userID string) (bool, error) {
+	if m.UserExistsFunc != nil {
+		return m.UserExistsFunc(ctx, userID)
+	}
+	return false, errors.New("UserExistsFunc not implemented in mock")
+}
+func (m *MockUserServiceClient) Close() error { return nil }
+
 // MockPetCache is a mock implementation of the PetCache interface.
 type MockPetCache struct {
 	GetPetFunc    func(ctx context.Context, id string) (*domain.Pet, error)
 	SetPetFunc    func(ctx context.Context, id string, pet *domain.Pet, expiration time.Duration) error
 	DeletePetFunc func(ctx context.Context, id string) error
+	GetPetFacetsFunc    func(ctx context.Context) (*domain.PetFacets, error)
+	SetPetFacetsFunc    func(ctx context.Context, facets *domain.PetFacets, expiration time.Duration) error
+	DeletePetFacetsFunc func(ctx context.Context) error
+	GetListedPetsFunc    func(ctx context.Context, page, limit int, filters map[string]interface{}) ([]*domain.Pet, int64, error)
+	SetListedPetsFunc    func(ctx context.Context, page, limit int, filters map[string]interface{}, pets []*domain.Pet, totalCount int64, expiration time.Duration) error
+	DeleteListedPetsFunc func(ctx context.Context) error
 }
 
 // Ensure MockPetCache implements repository.PetCache
@@ -104,6 +237,48 @@ func (m *MockPetCache) DeletePet(ctx context.Context, id string) error {
 	return errors.New("DeletePetFunc not implemented in mock cache")
 }
 
+func (m *MockPetCache) GetPetFacets(ctx context.Context) (*domain.PetFacets, error) {
+	if m.GetPetFacetsFunc != nil {
+		return m.GetPetFacetsFunc(ctx)
+	}
+	return nil, errors.New("GetPetFacetsFunc not implemented in mock cache")
+}
+
+func (m *MockPetCache) SetPetFacets(ctx context.Context, facets *domain.PetFacets, expiration time.Duration) error {
+	if m.SetPetFacetsFunc != nil {
+		return m.SetPetFacetsFunc(ctx, facets, expiration)
+	}
+	return errors.New("SetPetFacetsFunc not implemented in mock cache")
+}
+
+func (m *MockPetCache) DeletePetFacets(ctx context.Context) error {
+	if m.DeletePetFacetsFunc != nil {
+		return m.DeletePetFacetsFunc(ctx)
+	}
+	return errors.New("DeletePetFacetsFunc not implemented in mock cache")
+}
+
+func (m *MockPetCache) GetListedPets(ctx context.Context, page, limit int, filters map[string]interface{}) ([]*domain.Pet, int64, error) {
+	if m.GetListedPetsFunc != nil {
+		return m.GetListedPetsFunc(ctx, page, limit, filters)
+	}
+	return nil, 0, errors.New("GetListedPetsFunc not implemented in mock cache")
+}
+
+func (m *MockPetCache) SetListedPets(ctx context.Context, page, limit int, filters map[string]interface{}, pets []*domain.Pet, totalCount int64, expiration time.Duration) error {
+	if m.SetListedPetsFunc != nil {
+		return m.SetListedPetsFunc(ctx, page, limit, filters, pets, totalCount, expiration)
+	}
+	return errors.New("SetListedPetsFunc not implemented in mock cache")
+}
+
+func (m *MockPetCache) DeleteListedPets(ctx context.Context) error {
+	if m.DeleteListedPetsFunc != nil {
+		return m.DeleteListedPetsFunc(ctx)
+	}
+	return errors.New("DeleteListedPetsFunc not implemented in mock cache")
+}
+
 // --- Test Functions ---
 
 func TestPetUsecase_CreatePet_Success(t *testing.T) {
@@ -119,7 +294,6 @@ func TestPetUsecase_CreatePet_Success(t *testing.T) {
 		Age:            2,
 		Description:    "Friendly and playful",
 		ListedByUserID: "user123",
-		ImageURLs:      []string{"http://example.com/buddy.jpg"},
 	}
 
 	// Define behavior for CreatePet (simulate successful creation)
@@ -131,7 +305,7 @@ func TestPetUsecase_CreatePet_Success(t *testing.T) {
 	}
 
 	// 2. Initialize Usecase with Mocks
-	uc := usecase.NewPetUsecase(mockRepo, mockCache)
+	uc := usecase.NewPetUsecase(mockRepo, mockCache, nil, nil, nil, usecase.PetUsecaseConfig{})
 
 	// 3. Call the Method to Test
 	ctx := context.Background()
@@ -166,10 +340,129 @@ func TestPetUsecase_CreatePet_Success(t *testing.T) {
 	// }
 }
 
+func TestPetUsecase_CreatePet_SeedsCache(t *testing.T) {
+	repoReads := 0
+	mockRepo := &MockPetRepository{
+		CreatePetFunc: func(ctx context.Context, pet *domain.Pet) (*domain.Pet, error) {
+			pet.ID = "pet1"
+			return pet, nil
+		},
+		GetPetByIDFunc: func(ctx context.Context, id string) (*domain.Pet, error) {
+			repoReads++
+			return nil, errors.New("pet not found")
+		},
+	}
+	cached := map[string]*domain.Pet{}
+	mockCache := &MockPetCache{
+		GetPetFunc: func(ctx context.Context, id string) (*domain.Pet, error) {
+			if pet, ok := cached[id]; ok {
+				return pet, nil
+			}
+			return nil, repository.ErrCacheMiss
+		},
+		SetPetFunc: func(ctx context.Context, id string, pet *domain.Pet, expiration time.Duration) error {
+			cached[id] = pet
+			return nil
+		},
+	}
+	uc := usecase.NewPetUsecase(mockRepo, mockCache, nil, nil, nil, usecase.PetUsecaseConfig{SeedCacheOnCreate: true})
+	ctx := context.Background()
+
+	if _, err := uc.CreatePet(ctx, usecase.CreatePetRequestData{Name: "Rex", Species: "Dog"}); err != nil {
+		t.Fatalf("CreatePet() error = %v", err)
+	}
+	if _, ok := cached["pet1"]; !ok {
+		t.Fatalf("CreatePet() did not seed the cache with the new pet")
+	}
+	pet, err := uc.GetPetByID(ctx, "pet1")
+	if err != nil {
+		t.Fatalf("GetPetByID() error = %v", err)
+	}
+	if pet.Name != "Rex" {
+		t.Errorf("GetPetByID() name = %q, want Rex", pet.Name)
+	}
+	if repoReads != 0 {
+		t.Errorf("repository GetPetByID called %d times, want 0 (read served from cache)", repoReads)
+	}
+}
+
+func TestPetUsecase_CreatePet_NormalizesDescription(t *testing.T) {
+	var stored *domain.Pet
+	mockRepo := &MockPetRepository{
+		CreatePetFunc: func(ctx context.Context, pet *domain.Pet) (*domain.Pet, error) {
+			stored = pet
+			return pet, nil
+		},
+	}
+	uc := usecase.NewPetUsecase(mockRepo, &MockPetCache{}, nil, nil, nil, usecase.PetUsecaseConfig{MaxDescriptionLength: 12})
+
+	tests := []struct {
+		name        string
+		description string
+		want        string
+	}{
+		{"collapses whitespace", "  Good \t  old   dog \n", "Good old dog"},
+		{"keeps one blank line between paragraphs", "Calm\r\n\r\n\n\nKind", "Calm\n\nKind"},
+		{"strips control characters", "Good\x00 bo\x1by\u200b", "Good boy"},
+		{"caps length in characters", "Очень ласковый кот", "Очень ласков"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := uc.CreatePet(context.Background(), usecase.CreatePetRequestData{Name: "Rex", Species: "Dog", Description: tt.description}); err != nil {
+				t.Fatalf("CreatePet() error = %v", err)
+			}
+			if stored.Description != tt.want {
+				t.Errorf("stored description = %q, want %q", stored.Description, tt.want)
+			}
+		})
+	}
+}
+
+func TestPetUsecase_CreatePet_InitialStatus(t *testing.T) {
+	mockRepo := &MockPetRepository{
+		CreatePetFunc: func(ctx context.Context, pet *domain.Pet) (*domain.Pet, error) {
+			pet.ID = "pet123"
+			pet.PrepareForCreate()
+			return pet, nil
+		},
+	}
+	uc := usecase.NewPetUsecase(mockRepo, &MockPetCache{}, nil, nil, nil, usecase.PetUsecaseConfig{})
+
+	tests := []struct {
+		name         string
+		status       domain.AdoptionStatus
+		canSetStatus bool
+		wantStatus   domain.AdoptionStatus
+		wantErr      error
+	}{
+		{"default", "", false, domain.StatusAvailable, nil},
+		{"explicit available without role", domain.StatusAvailable, false, domain.StatusAvailable, nil},
+		{"pending with shelter or admin role", domain.StatusPendingAdoption, true, domain.StatusPendingAdoption, nil},
+		{"pending without role", domain.StatusPendingAdoption, false, "", usecase.ErrInitialStatusForbidden},
+		{"adopted", domain.StatusAdopted, true, "", usecase.ErrInvalidInitialStatus},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pet, err := uc.CreatePet(context.Background(), usecase.CreatePetRequestData{
+				Name:               "Buddy",
+				Species:            "Dog",
+				AdoptionStatus:     tt.status,
+				CallerCanSetStatus: tt.canSetStatus,
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreatePet() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && pet.AdoptionStatus != tt.wantStatus {
+				t.Errorf("CreatePet() status = %s, want %s", pet.AdoptionStatus, tt.wantStatus)
+			}
+		})
+	}
+}
+
 func TestPetUsecase_CreatePet_MissingName(t *testing.T) {
 	mockRepo := &MockPetRepository{}
 	mockCache := &MockPetCache{}
-	uc := usecase.NewPetUsecase(mockRepo, mockCache)
+	uc := usecase.NewPetUsecase(mockRepo, mockCache, nil, nil, nil, usecase.PetUsecaseConfig{})
 
 	createReq := usecase.CreatePetRequestData{
 		// Name is missing
@@ -191,19 +484,1954 @@ func TestPetUsecase_CreatePet_MissingName(t *testing.T) {
 	// assert.EqualError(t, err, "pet name and species are required")
 }
 
-// TODO: Add more unit tests for other PetUsecase methods:
-// - GetPetByID_Success_FromCache
-// - GetPetByID_Success_FromDB_CacheMiss
-// - GetPetByID_NotFound
-// - UpdatePet_Success
-// - UpdatePet_NotFound
-// - DeletePet_Success
-// - DeletePet_NotFound
-// - ListPets_Success_NoFilters
-// - ListPets_Success_WithFilters
-// - ListPets_Empty
-// - UpdatePetAdoptionStatus_Success_ToAdopted
-// - UpdatePetAdoptionStatus_Success_ToAvailable
-// - UpdatePetAdoptionStatus_PetNotFound
-// - UpdatePetAdoptionStatus_InvalidStatus
-// - UpdatePetAdoptionStatus_MissingAdopterID
\ No newline at end of file
+func TestPetUsecase_GetImageUploadTarget_PetScoped(t *testing.T) {
+	mockRepo := &MockPetRepository{
+		GetPetByIDFunc: func(ctx context.Context, id string) (*domain.Pet, error) {
+			return &domain.Pet{ID: id, Name: "Buddy", ListedByUserID: "owner1"}, nil
+		},
+	}
+	mockCache := &MockPetCache{}
+	uc := usecase.NewPetUsecase(mockRepo, mockCache, storage.NewFakeImageStorage("http://images.test/bucket"), nil, nil, usecase.PetUsecaseConfig{})
+
+	target, err := uc.GetImageUploadTarget(context.Background(), "pet42", "Buddy.JPG", "image/jpeg", "owner1")
+	if err != nil {
+		t.Fatalf("GetImageUploadTarget() error = %v, want nil", err)
+	}
+	if target.URL == "" {
+		t.Errorf("GetImageUploadTarget() URL is empty, want upload URL")
+	}
+	key := target.Fields["key"]
+	if !strings.HasPrefix(key, "pets/pet42/") || !strings.HasSuffix(key, ".jpg") {
+		t.Errorf("GetImageUploadTarget() key = %q, want pets/pet42/<id>.jpg", key)
+	}
+	if target.PublicURL != "http://images.test/bucket/"+key {
+		t.Errorf("GetImageUploadTarget() PublicURL = %q, want it to point at key %q", target.PublicURL, key)
+	}
+
+	if _, err := uc.GetImageUploadTarget(context.Background(), "pet42", "Buddy.JPG", "image/jpeg", "someone-else"); !errors.Is(err, usecase.ErrNotPetOwner) {
+		t.Errorf("GetImageUploadTarget() by another user error = %v, want ErrNotPetOwner", err)
+	}
+}
+
+func TestPetUsecase_GetImageUploadTarget_PetNotFound(t *testing.T) {
+	mockRepo := &MockPetRepository{
+		GetPetByIDFunc: func(ctx context.Context, id string) (*domain.Pet, error) {
+			return nil, errors.New("pet not found")
+		},
+	}
+	uc := usecase.NewPetUsecase(mockRepo, &MockPetCache{}, storage.NewFakeImageStorage(""), nil, nil, usecase.PetUsecaseConfig{})
+
+	_, err := uc.GetImageUploadTarget(context.Background(), "missing", "a.png", "image/png", "owner1")
+	if err == nil || err.Error() != "pet not found" {
+		t.Errorf("GetImageUploadTarget() error = %v, want 'pet not found'", err)
+	}
+}
+
+func TestS3ImageStorage_PresignUpload_SignedPolicy(t *testing.T) {
+	s3, err := storage.NewS3ImageStorage(storage.S3Config{
+		Bucket:          "pet-images",
+		Region:          "eu-central-1",
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "secret",
+	})
+	if err != nil {
+		t.Fatalf("NewS3ImageStorage() error = %v", err)
+	}
+
+	key := storage.PetImageKey("pet42", "photo.png")
+	target, err := s3.PresignUpload(context.Background(), key, "image/png")
+	if err != nil {
+		t.Fatalf("PresignUpload() error = %v", err)
+	}
+	if target.URL != "https://pet-images.s3.eu-central-1.amazonaws.com" {
+		t.Errorf("PresignUpload() URL = %q", target.URL)
+	}
+	for _, field := range []string{"policy", "x-amz-signature", "x-amz-credential", "x-amz-date"} {
+		if target.Fields[field] == "" {
+			t.Errorf("PresignUpload() missing form field %q", field)
+		}
+	}
+	if target.Fields["key"] != key || !strings.HasSuffix(target.PublicURL, "/"+key) {
+		t.Errorf("PresignUpload() target not scoped to key %q: %+v", key, target)
+	}
+}
+
+func TestConcurrencyLimitInterceptor_RejectsBeyondLimit(t *testing.T) {
+	const limit = 2
+	interceptor := server.NewConcurrencyLimitInterceptor(limit, nil)
+	info := &grpc.UnaryServerInfo{FullMethod: "/pet.PetService/GetPet"}
+
+	entered := make(chan struct{}, limit)
+	release := make(chan struct{})
+	blockingHandler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		entered <- struct{}{}
+		<-release
+		return "ok", nil
+	}
+
+	// Fill every slot with a request that blocks until released.
+	var wg sync.WaitGroup
+	errs := make(chan error, limit)
+	for i := 0; i < limit; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := interceptor(context.Background(), nil, info, blockingHandler)
+			errs <- err
+		}()
+	}
+	for i := 0; i < limit; i++ {
+		<-entered
+	}
+
+	// The next request must be rejected immediately.
+	_, err := interceptor(context.Background(), nil, info, blockingHandler)
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("request beyond limit: got code %v, want %v", status.Code(err), codes.ResourceExhausted)
+	}
+
+	close(release)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("in-limit request failed: %v", err)
+		}
+	}
+
+	// Once the slots are freed, requests proceed again.
+	resp, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	})
+	if err != nil || resp != "ok" {
+		t.Errorf("request after release: got (%v, %v), want (ok, nil)", resp, err)
+	}
+}
+
+// blockingPetServer holds GetPet open until release is closed.
+type blockingPetServer struct {
+	pb.UnimplementedPetServiceServer
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingPetServer) GetPet(ctx context.Context, req *pb.GetPetRequest) (*pb.PetResponse, error) {
+	close(s.entered)
+	<-s.release
+	return &pb.PetResponse{Pet: &pb.Pet{Id: req.GetPetId()}}, nil
+}
+
+// lockedBuffer is a bytes.Buffer that is safe to write from the server goroutines while the test reads it.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestGRPCServer_Stop_LogsDrainedRequests(t *testing.T) {
+	var logBuf lockedBuffer
+	originalLogOutput := log.Writer()
+	log.SetOutput(&logBuf)
+	defer log.SetOutput(originalLogOutput)
+
+	petServer := &blockingPetServer{entered: make(chan struct{}), release: make(chan struct{})}
+	gs, err := server.NewGRPCServer("127.0.0.1:0", petServer, 10)
+	if err != nil {
+		t.Fatalf("NewGRPCServer() error = %v", err)
+	}
+	go gs.Start()
+
+	conn, err := grpc.NewClient(gs.Addr(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("grpc.NewClient() error = %v", err)
+	}
+	defer conn.Close()
+
+	callErr := make(chan error, 1)
+	go func() {
+		_, err := pb.NewPetServiceClient(conn).GetPet(context.Background(), &pb.GetPetRequest{PetId: "pet1"})
+		callErr <- err
+	}()
+	<-petServer.entered
+
+	stopped := make(chan struct{})
+	go func() {
+		gs.Stop()
+		close(stopped)
+	}()
+
+	// Let the request finish only once shutdown has begun, so it counts as drained.
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(logBuf.String(), "in_flight=1") {
+		if time.Now().After(deadline) {
+			t.Fatalf("shutdown did not report the in-flight request; log:\n%s", logBuf.String())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	close(petServer.release)
+
+	if err := <-callErr; err != nil {
+		t.Errorf("in-flight GetPet failed during shutdown: %v", err)
+	}
+	<-stopped
+	if !strings.Contains(logBuf.String(), "in_flight_at_shutdown=1 drained=1") {
+		t.Errorf("shutdown log missing drained count; log:\n%s", logBuf.String())
+	}
+}
+
+// callerRecordingPetServer answers GetPet with the caller the pet-service handlers would see.
+type callerRecordingPetServer struct {
+	pb.UnimplementedPetServiceServer
+}
+
+func (s *callerRecordingPetServer) GetPet(ctx context.Context, req *pb.GetPetRequest) (*pb.PetResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	return &pb.PetResponse{Pet: &pb.Pet{Id: req.GetPetId(), ListedByUserId: strings.Join(md.Get("x-user-id"), ",")}}, nil
+}
+
+func TestGRPCServer_GatewayAuth_RejectsForgedCallerIdentity(t *testing.T) {
+	gs, err := server.NewGRPCServer("127.0.0.1:0", &callerRecordingPetServer{}, 10, gatewayauth.UnaryServerInterceptor("gateway-secret"))
+	if err != nil {
+		t.Fatalf("NewGRPCServer() error = %v", err)
+	}
+	go gs.Start()
+	defer gs.Stop()
+
+	getPet := func(ctx context.Context, token string) (*pb.PetResponse, error) {
+		conn, err := grpc.NewClient(gs.Addr(), grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithUnaryInterceptor(gatewayauth.UnaryClientInterceptor(token)))
+		if err != nil {
+			t.Fatalf("grpc.NewClient() error = %v", err)
+		}
+		defer conn.Close()
+		return pb.NewPetServiceClient(conn).GetPet(ctx, &pb.GetPetRequest{PetId: "pet1"})
+	}
+	asAdmin := metadata.AppendToOutgoingContext(context.Background(), "x-user-id", "admin-1", "x-user-roles", "admin")
+
+	if _, err := getPet(asAdmin, ""); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("GetPet() claiming a caller without the gateway token code = %v, want Unauthenticated", status.Code(err))
+	}
+	if _, err := getPet(asAdmin, "guessed"); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("GetPet() claiming a caller with a wrong token code = %v, want Unauthenticated", status.Code(err))
+	}
+	resp, err := getPet(asAdmin, "gateway-secret")
+	if err != nil || resp.GetPet().GetListedByUserId() != "admin-1" {
+		t.Errorf("GetPet() from the gateway = (%v, %v), want the forwarded caller admin-1", resp, err)
+	}
+	// Calls that claim no caller, e.g. from other services, need no token.
+	if _, err := getPet(context.Background(), ""); err != nil {
+		t.Errorf("GetPet() without caller metadata error = %v, want nil", err)
+	}
+}
+
+func TestRecentlyAdoptedQuery_FiltersAdoptedAndSortsNewestFirst(t *testing.T) {
+	filter, findOptions := repository.RecentlyAdoptedQuery(5)
+
+	if filter["adoption_status"] != domain.StatusAdopted || filter["deleted"] == nil || len(filter) != 2 {
+		t.Errorf("RecentlyAdoptedQuery() filter = %v, want only adoption_status=ADOPTED and not deleted", filter)
+	}
+	sort, ok := findOptions.Sort.(bson.D)
+	if !ok || len(sort) != 1 || sort[0].Key != "updated_at" || sort[0].Value != -1 {
+		t.Errorf("RecentlyAdoptedQuery() sort = %v, want updated_at descending", findOptions.Sort)
+	}
+	if findOptions.Limit == nil || *findOptions.Limit != 5 {
+		t.Errorf("RecentlyAdoptedQuery() limit = %v, want 5", findOptions.Limit)
+	}
+}
+
+func TestMongoPetRepository_ListRecentlyAdopted_NewestAdoptedFirst(t *testing.T) {
+	db := testutil.MongoDatabase(t)
+	ctx := context.Background()
+	repo := repository.NewMongoDBPetRepositoryFromClient(ctx, db.Client(), db.Name(), "pets", repository.IndexOptions{EnsureIndexes: true})
+
+	// Inserted directly: CreatePet would stamp every pet with the current time.
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	deletedAt := now.Add(-time.Minute)
+	pets := []interface{}{
+		&domain.Pet{ID: "adopted-old", Name: "Rex", Species: "Dog", AdoptionStatus: domain.StatusAdopted, CreatedAt: now.Add(-72 * time.Hour), UpdatedAt: now.Add(-3 * time.Hour)},
+		&domain.Pet{ID: "adopted-new", Name: "Goldie", Species: "Dog", AdoptionStatus: domain.StatusAdopted, CreatedAt: now.Add(-72 * time.Hour), UpdatedAt: now.Add(-1 * time.Hour)},
+		&domain.Pet{ID: "adopted-mid", Name: "Whiskers", Species: "Cat", AdoptionStatus: domain.StatusAdopted, CreatedAt: now.Add(-72 * time.Hour), UpdatedAt: now.Add(-2 * time.Hour)},
+		&domain.Pet{ID: "available", Name: "Tweety", Species: "Bird", AdoptionStatus: domain.StatusAvailable, CreatedAt: now.Add(-72 * time.Hour), UpdatedAt: now},
+		&domain.Pet{ID: "adopted-deleted", Name: "Spot", Species: "Dog", AdoptionStatus: domain.StatusAdopted, CreatedAt: now.Add(-72 * time.Hour), UpdatedAt: now, Deleted: true, DeletedAt: &deletedAt},
+	}
+	if _, err := db.Collection("pets").InsertMany(ctx, pets); err != nil {
+		t.Fatalf("InsertMany() error = %v", err)
+	}
+
+	ids := func(limit int) string {
+		t.Helper()
+		adopted, err := repo.ListRecentlyAdopted(ctx, limit)
+		if err != nil {
+			t.Fatalf("ListRecentlyAdopted(%d) error = %v", limit, err)
+		}
+		got := make([]string, len(adopted))
+		for i, pet := range adopted {
+			got[i] = pet.ID
+		}
+		return strings.Join(got, ",")
+	}
+
+	if got := ids(10); got != "adopted-new,adopted-mid,adopted-old" {
+		t.Errorf("ListRecentlyAdopted(10) = %s, want adopted-new,adopted-mid,adopted-old", got)
+	}
+	if got := ids(2); got != "adopted-new,adopted-mid" {
+		t.Errorf("ListRecentlyAdopted(2) = %s, want adopted-new,adopted-mid", got)
+	}
+}
+
+func TestPetUsecase_ListRecentlyAdopted_ClampsLimit(t *testing.T) {
+	var gotLimit int
+	now := time.Now()
+	mockRepo := &MockPetRepository{
+		ListRecentlyAdoptedFunc: func(ctx context.Context, limit int) ([]*domain.Pet, error) {
+			gotLimit = limit
+			return []*domain.Pet{
+				{ID: "newer", AdoptionStatus: domain.StatusAdopted, UpdatedAt: now},
+				{ID: "older", AdoptionStatus: domain.StatusAdopted, UpdatedAt: now.Add(-time.Hour)},
+			}, nil
+		},
+	}
+	uc := usecase.NewPetUsecase(mockRepo, &MockPetCache{}, nil, nil, nil, usecase.PetUsecaseConfig{})
+
+	pets, err := uc.ListRecentlyAdopted(context.Background(), 1000)
+	if err != nil {
+		t.Fatalf("ListRecentlyAdopted() error = %v", err)
+	}
+	if gotLimit != 50 {
+		t.Errorf("ListRecentlyAdopted() passed limit %d to repository, want 50", gotLimit)
+	}
+	if len(pets) != 2 || pets[0].ID != "newer" {
+		t.Errorf("ListRecentlyAdopted() = %v, want repository order preserved", pets)
+	}
+}
+
+// newFacetsCacheMock returns a MockPetCache that keeps facets in memory, ignoring expiry.
+func newFacetsCacheMock() *MockPetCache {
+	var cached *domain.PetFacets
+	return &MockPetCache{
+		GetPetFacetsFunc: func(ctx context.Context) (*domain.PetFacets, error) {
+			if cached == nil {
+				return nil, repository.ErrCacheMiss
+			}
+			return cached, nil
+		},
+		SetPetFacetsFunc: func(ctx context.Context, facets *domain.PetFacets, expiration time.Duration) error {
+			cached = facets
+			return nil
+		},
+		DeletePetFacetsFunc: func(ctx context.Context) error {
+			cached = nil
+			return nil
+		},
+	}
+}
+
+func TestPetUsecase_GetPetFacets_SecondCallHitsCache(t *testing.T) {
+	repoCalls := 0
+	mockRepo := &MockPetRepository{
+		GetPetFacetsFunc: func(ctx context.Context) (*domain.PetFacets, error) {
+			repoCalls++
+			return &domain.PetFacets{Species: []domain.FacetCount{{Value: "Dog", Count: 3}}}, nil
+		},
+	}
+	uc := usecase.NewPetUsecase(mockRepo, newFacetsCacheMock(), nil, nil, nil, usecase.PetUsecaseConfig{FacetsCacheTTL: 5 * time.Minute})
+
+	for i := 0; i < 2; i++ {
+		facets, err := uc.GetPetFacets(context.Background())
+		if err != nil {
+			t.Fatalf("GetPetFacets() call %d error = %v", i+1, err)
+		}
+		if len(facets.Species) != 1 || facets.Species[0].Value != "Dog" {
+			t.Errorf("GetPetFacets() call %d species = %v, want Dog", i+1, facets.Species)
+		}
+	}
+	if repoCalls != 1 {
+		t.Errorf("repository GetPetFacets called %d times, want 1 (second call served from cache)", repoCalls)
+	}
+}
+
+func TestPetUsecase_GetPetFacets_RefreshedAfterCreate(t *testing.T) {
+	repoCalls := 0
+	mockRepo := &MockPetRepository{
+		GetPetFacetsFunc: func(ctx context.Context) (*domain.PetFacets, error) {
+			repoCalls++
+			return &domain.PetFacets{}, nil
+		},
+		CreatePetFunc: func(ctx context.Context, pet *domain.Pet) (*domain.Pet, error) {
+			pet.ID = "pet1"
+			return pet, nil
+		},
+	}
+	uc := usecase.NewPetUsecase(mockRepo, newFacetsCacheMock(), nil, nil, nil, usecase.PetUsecaseConfig{FacetsCacheTTL: 5 * time.Minute})
+	ctx := context.Background()
+
+	if _, err := uc.GetPetFacets(ctx); err != nil {
+		t.Fatalf("GetPetFacets() error = %v", err)
+	}
+	if _, err := uc.CreatePet(ctx, usecase.CreatePetRequestData{Name: "Rex", Species: "Dog"}); err != nil {
+		t.Fatalf("CreatePet() error = %v", err)
+	}
+	if _, err := uc.GetPetFacets(ctx); err != nil {
+		t.Fatalf("GetPetFacets() error = %v", err)
+	}
+	if repoCalls != 2 {
+		t.Errorf("repository GetPetFacets called %d times, want 2 (cache invalidated by CreatePet)", repoCalls)
+	}
+}
+
+// newListCacheMock returns a MockPetCache that keeps listed pets in memory, keyed like the Redis
+// cache by page, limit and filters (fmt prints maps in key order).
+func newListCacheMock() *MockPetCache {
+	type page struct {
+		pets  []*domain.Pet
+		total int64
+	}
+	cached := map[string]page{}
+	key := func(p, limit int, filters map[string]interface{}) string {
+		return fmt.Sprint(p, limit, filters)
+	}
+	return &MockPetCache{
+		GetListedPetsFunc: func(ctx context.Context, p, limit int, filters map[string]interface{}) ([]*domain.Pet, int64, error) {
+			if hit, ok := cached[key(p, limit, filters)]; ok {
+				return hit.pets, hit.total, nil
+			}
+			return nil, 0, fmt.Errorf("listed pets %w", repository.ErrCacheMiss)
+		},
+		SetListedPetsFunc: func(ctx context.Context, p, limit int, filters map[string]interface{}, pets []*domain.Pet, totalCount int64, expiration time.Duration) error {
+			cached[key(p, limit, filters)] = page{pets: pets, total: totalCount}
+			return nil
+		},
+		DeleteListedPetsFunc: func(ctx context.Context) error {
+			clear(cached)
+			return nil
+		},
+		DeletePetFunc: func(ctx context.Context, id string) error { return nil },
+	}
+}
+
+func TestPetUsecase_ListPets_CachedPerQuery(t *testing.T) {
+	repoCalls := 0
+	mockRepo := &MockPetRepository{
+		ListPetsFunc: func(ctx context.Context, page, limit int, filters map[string]interface{}) ([]*domain.Pet, int64, error) {
+			repoCalls++
+			return []*domain.Pet{{ID: "pet1", Name: "Rex"}}, 7, nil
+		},
+	}
+	uc := usecase.NewPetUsecase(mockRepo, newListCacheMock(), nil, nil, nil, usecase.PetUsecaseConfig{ListCacheTTL: time.Minute})
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		pets, total, err := uc.ListPets(ctx, 1, 10, map[string]interface{}{repository.FilterSpecies: "Dog"})
+		if err != nil {
+			t.Fatalf("ListPets() call %d error = %v", i+1, err)
+		}
+		if len(pets) != 1 || pets[0].ID != "pet1" || total != 7 {
+			t.Errorf("ListPets() call %d = %v, %d, want [pet1], 7", i+1, pets, total)
+		}
+	}
+	if repoCalls != 1 {
+		t.Errorf("repository ListPets called %d times, want 1 (second call served from cache)", repoCalls)
+	}
+
+	if _, _, err := uc.ListPets(ctx, 2, 10, map[string]interface{}{repository.FilterSpecies: "Dog"}); err != nil {
+		t.Fatalf("ListPets() page 2 error = %v", err)
+	}
+	if _, _, err := uc.ListPets(ctx, 1, 10, map[string]interface{}{repository.FilterSpecies: "Cat"}); err != nil {
+		t.Fatalf("ListPets() other species error = %v", err)
+	}
+	if repoCalls != 3 {
+		t.Errorf("repository ListPets called %d times, want 3 (other page and filters are cache misses)", repoCalls)
+	}
+}
+
+func TestPetUsecase_ListPets_CacheInvalidatedByDelete(t *testing.T) {
+	repoCalls := 0
+	mockRepo := &MockPetRepository{
+		ListPetsFunc: func(ctx context.Context, page, limit int, filters map[string]interface{}) ([]*domain.Pet, int64, error) {
+			repoCalls++
+			return []*domain.Pet{}, 0, nil
+		},
+		GetPetByIDFunc: func(ctx context.Context, id string) (*domain.Pet, error) {
+			return &domain.Pet{ID: id, ListedByUserID: "owner"}, nil
+		},
+		DeletePetFunc: func(ctx context.Context, id string) error { return nil },
+	}
+	uc := usecase.NewPetUsecase(mockRepo, newListCacheMock(), nil, nil, nil, usecase.PetUsecaseConfig{ListCacheTTL: time.Minute})
+	ctx := context.Background()
+
+	if _, _, err := uc.ListPets(ctx, 1, 10, map[string]interface{}{}); err != nil {
+		t.Fatalf("ListPets() error = %v", err)
+	}
+	if err := uc.DeletePet(ctx, "pet1", "owner"); err != nil {
+		t.Fatalf("DeletePet() error = %v", err)
+	}
+	if _, _, err := uc.ListPets(ctx, 1, 10, map[string]interface{}{}); err != nil {
+		t.Fatalf("ListPets() error = %v", err)
+	}
+	if repoCalls != 2 {
+		t.Errorf("repository ListPets called %d times, want 2 (cache invalidated by DeletePet)", repoCalls)
+	}
+}
+
+func TestRedisPetCache_DeleteListedPets(t *testing.T) {
+	addr, prefix := testutil.RedisAddr(t)
+	ctx := context.Background()
+	cache, err := repository.NewRedisPetCache(ctx, addr, "", 0, prefix)
+	if err != nil {
+		t.Fatalf("NewRedisPetCache() error = %v", err)
+	}
+	pets := []*domain.Pet{{ID: "pet1", Name: "Rex"}}
+
+	if err := cache.SetListedPets(ctx, 1, 10, map[string]interface{}{"species": "Dog", "breed": "Collie"}, pets, 4, time.Minute); err != nil {
+		t.Fatalf("SetListedPets() error = %v", err)
+	}
+	got, total, err := cache.GetListedPets(ctx, 1, 10, map[string]interface{}{"breed": "Collie", "species": "Dog"})
+	if err != nil || len(got) != 1 || got[0].Name != "Rex" || total != 4 {
+		t.Fatalf("GetListedPets() = %v, %d, %v, want [Rex], 4", got, total, err)
+	}
+	if _, _, err := cache.GetListedPets(ctx, 2, 10, map[string]interface{}{"breed": "Collie", "species": "Dog"}); !errors.Is(err, repository.ErrCacheMiss) {
+		t.Errorf("GetListedPets() for another page error = %v, want ErrCacheMiss", err)
+	}
+
+	if err := cache.DeleteListedPets(ctx); err != nil {
+		t.Fatalf("DeleteListedPets() error = %v", err)
+	}
+	if _, _, err := cache.GetListedPets(ctx, 1, 10, map[string]interface{}{"breed": "Collie", "species": "Dog"}); !errors.Is(err, repository.ErrCacheMiss) {
+		t.Errorf("GetListedPets() after DeleteListedPets error = %v, want ErrCacheMiss", err)
+	}
+}
+
+func TestPetUsecase_BatchGetPets_FetchesCacheMissesInOneQuery(t *testing.T) {
+	cached := map[string]*domain.Pet{"pet1": {ID: "pet1", Name: "Cached"}}
+	mockCache := &MockPetCache{
+		GetPetFunc: func(ctx context.Context, id string) (*domain.Pet, error) {
+			if pet, ok := cached[id]; ok {
+				return pet, nil
+			}
+			return nil, fmt.Errorf("pet %w", repository.ErrCacheMiss)
+		},
+		SetPetFunc: func(ctx context.Context, id string, pet *domain.Pet, expiration time.Duration) error {
+			cached[id] = pet
+			return nil
+		},
+	}
+	var queries [][]string
+	mockRepo := &MockPetRepository{
+		GetPetsByIDsFunc: func(ctx context.Context, ids []string) ([]*domain.Pet, error) {
+			queries = append(queries, ids)
+			return []*domain.Pet{{ID: "pet3", Name: "Stored 3"}, {ID: "pet2", Name: "Stored 2"}}, nil
+		},
+	}
+	uc := usecase.NewPetUsecase(mockRepo, mockCache, nil, nil, nil, usecase.PetUsecaseConfig{})
+
+	pets, err := uc.BatchGetPets(context.Background(), []string{"pet2", "pet1", "missing", "pet3", "pet2"})
+	if err != nil {
+		t.Fatalf("BatchGetPets() error = %v", err)
+	}
+	var gotIDs []string
+	for _, pet := range pets {
+		gotIDs = append(gotIDs, pet.ID)
+	}
+	if !slices.Equal(gotIDs, []string{"pet2", "pet1", "pet3"}) {
+		t.Errorf("BatchGetPets() IDs = %v, want [pet2 pet1 pet3] (request order, missing left out)", gotIDs)
+	}
+	if pets[1].Name != "Cached" {
+		t.Errorf("BatchGetPets() pet1 = %q, want the cached pet", pets[1].Name)
+	}
+	if len(queries) != 1 || !slices.Equal(queries[0], []string{"missing", "pet2", "pet3"}) {
+		t.Errorf("repository GetPetsByIDs calls = %v, want one call for [missing pet2 pet3]", queries)
+	}
+	if cached["pet2"] == nil || cached["pet3"] == nil {
+		t.Error("BatchGetPets() did not cache the pets read from the repository")
+	}
+
+	if _, err := uc.BatchGetPets(context.Background(), []string{"pet1", "pet2", "pet3"}); err != nil {
+		t.Fatalf("BatchGetPets() second call error = %v", err)
+	}
+	if len(queries) != 1 {
+		t.Errorf("repository GetPetsByIDs called %d times, want 1 (second call served from cache)", len(queries))
+	}
+}
+
+func TestPetHandler_BatchGetPets_TooManyIDs(t *testing.T) {
+	uc := usecase.NewPetUsecase(&MockPetRepository{}, &MockPetCache{}, nil, nil, nil, usecase.PetUsecaseConfig{})
+	h := handler.NewPetHandler(uc, "")
+
+	ids := make([]string, usecase.MaxBatchGetPets+1)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("pet%d", i)
+	}
+	_, err := h.BatchGetPets(context.Background(), &pb.BatchGetPetsRequest{Ids: ids})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("BatchGetPets() with %d IDs error = %v, want InvalidArgument", len(ids), err)
+	}
+}
+
+func TestPetHandler_CheckPetAvailability(t *testing.T) {
+	pets := map[string]*domain.Pet{
+		"available": {ID: "available", AdoptionStatus: domain.StatusAvailable},
+		"pending":   {ID: "pending", AdoptionStatus: domain.StatusPendingAdoption},
+		"adopted":   {ID: "adopted", AdoptionStatus: domain.StatusAdopted, AdoptedByUserID: "adopter1"},
+	}
+	mockRepo := &MockPetRepository{
+		GetPetByIDFunc: func(ctx context.Context, id string) (*domain.Pet, error) {
+			if pet, ok := pets[id]; ok {
+				return pet, nil
+			}
+			return nil, errors.New("pet not found")
+		},
+	}
+	mockCache := &MockPetCache{
+		GetPetFunc: func(ctx context.Context, id string) (*domain.Pet, error) {
+			return nil, fmt.Errorf("pet %w", repository.ErrCacheMiss)
+		},
+	}
+	h := handler.NewPetHandler(usecase.NewPetUsecase(mockRepo, mockCache, nil, nil, nil, usecase.PetUsecaseConfig{}), "")
+
+	tests := []struct {
+		petID         string
+		wantStatus    pb.AdoptionStatus
+		wantAvailable bool
+	}{
+		{petID: "available", wantStatus: pb.AdoptionStatus_AVAILABLE, wantAvailable: true},
+		{petID: "pending", wantStatus: pb.AdoptionStatus_PENDING_ADOPTION},
+		{petID: "adopted", wantStatus: pb.AdoptionStatus_ADOPTED},
+	}
+	for _, tt := range tests {
+		res, err := h.CheckPetAvailability(context.Background(), &pb.CheckPetAvailabilityRequest{PetId: tt.petID})
+		if err != nil {
+			t.Fatalf("CheckPetAvailability(%s) error = %v", tt.petID, err)
+		}
+		if res.GetAdoptionStatus() != tt.wantStatus || res.GetAvailable() != tt.wantAvailable {
+			t.Errorf("CheckPetAvailability(%s) = %v/%v, want %v/%v", tt.petID, res.GetAdoptionStatus(), res.GetAvailable(), tt.wantStatus, tt.wantAvailable)
+		}
+	}
+
+	if _, err := h.CheckPetAvailability(context.Background(), &pb.CheckPetAvailabilityRequest{PetId: "missing"}); status.Code(err) != codes.NotFound {
+		t.Errorf("CheckPetAvailability(missing) error = %v, want NotFound", err)
+	}
+}
+
+func TestPetUsecase_GetPetByID_CountsCacheHitsAndMisses(t *testing.T) {
+	cached := map[string]*domain.Pet{}
+	mockCache := &MockPetCache{
+		GetPetFunc: func(ctx context.Context, id string) (*domain.Pet, error) {
+			if pet, ok := cached[id]; ok {
+				return pet, nil
+			}
+			return nil, repository.ErrCacheMiss
+		},
+		SetPetFunc: func(ctx context.Context, id string, pet *domain.Pet, expiration time.Duration) error {
+			cached[id] = pet
+			return nil
+		},
+	}
+	mockRepo := &MockPetRepository{
+		GetPetByIDFunc: func(ctx context.Context, id string) (*domain.Pet, error) {
+			return &domain.Pet{ID: id, Name: "Buddy"}, nil
+		},
+	}
+	uc := usecase.NewPetUsecase(mockRepo, mockCache, nil, nil, nil, usecase.PetUsecaseConfig{})
+	hitsBefore, missesBefore := metrics.CacheHits.Value(metrics.CachePet), metrics.CacheMisses.Value(metrics.CachePet)
+
+	if _, err := uc.GetPetByID(context.Background(), "pet1"); err != nil {
+		t.Fatalf("GetPetByID() first call error = %v", err)
+	}
+	if got := metrics.CacheMisses.Value(metrics.CachePet) - missesBefore; got != 1 {
+		t.Errorf("cache misses after first call = %d, want 1", got)
+	}
+	if got := metrics.CacheHits.Value(metrics.CachePet) - hitsBefore; got != 0 {
+		t.Errorf("cache hits after first call = %d, want 0", got)
+	}
+
+	if _, err := uc.GetPetByID(context.Background(), "pet1"); err != nil {
+		t.Fatalf("GetPetByID() second call error = %v", err)
+	}
+	if got := metrics.CacheHits.Value(metrics.CachePet) - hitsBefore; got != 1 {
+		t.Errorf("cache hits after second call = %d, want 1", got)
+	}
+	if got := metrics.CacheMisses.Value(metrics.CachePet) - missesBefore; got != 1 {
+		t.Errorf("cache misses after second call = %d, want still 1", got)
+	}
+
+	var out bytes.Buffer
+	if _, err := metrics.CacheHits.WriteTo(&out); err != nil {
+		t.Fatalf("WriteTo() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "# TYPE pet_service_cache_hits_total counter") || !strings.Contains(out.String(), `pet_service_cache_hits_total{cache="pet"}`) {
+		t.Errorf("WriteTo() = %q, want the pet cache hit counter", out.String())
+	}
+}
+
+func TestPetUsecase_GetPetByID_CacheMissLogging(t *testing.T) {
+	var logBuf bytes.Buffer
+	originalLogOutput := log.Writer()
+	log.SetOutput(&logBuf)
+	defer log.SetOutput(originalLogOutput)
+
+	cacheErr := repository.ErrCacheMiss
+	mockCache := &MockPetCache{
+		GetPetFunc: func(ctx context.Context, id string) (*domain.Pet, error) { return nil, cacheErr },
+		SetPetFunc: func(ctx context.Context, id string, pet *domain.Pet, expiration time.Duration) error { return nil },
+	}
+	mockRepo := &MockPetRepository{
+		GetPetByIDFunc: func(ctx context.Context, id string) (*domain.Pet, error) {
+			return &domain.Pet{ID: id, Name: "Buddy"}, nil
+		},
+	}
+	uc := usecase.NewPetUsecase(mockRepo, mockCache, nil, nil, nil, usecase.PetUsecaseConfig{})
+
+	if _, err := uc.GetPetByID(context.Background(), "pet1"); err != nil {
+		t.Fatalf("GetPetByID() on a cache miss error = %v", err)
+	}
+	if logBuf.Len() != 0 {
+		t.Errorf("GetPetByID() on a cache miss logged %q at the default level, want nothing", logBuf.String())
+	}
+
+	cacheErr = errors.New("redis: connection refused")
+	if _, err := uc.GetPetByID(context.Background(), "pet1"); err != nil {
+		t.Fatalf("GetPetByID() on a cache error = %v", err)
+	}
+	if !strings.Contains(logBuf.String(), "Warning:") || !strings.Contains(logBuf.String(), "connection refused") {
+		t.Errorf("GetPetByID() on a cache error logged %q, want a warning with the error", logBuf.String())
+	}
+}
+
+func TestPetFacetsPipeline_SingleFacetStage(t *testing.T) {
+	pipeline := repository.PetFacetsPipeline()
+	if len(pipeline) != 1 || pipeline[0][0].Key != "$facet" {
+		t.Fatalf("PetFacetsPipeline() = %v, want a single $facet stage", pipeline)
+	}
+	facets, ok := pipeline[0][0].Value.(bson.M)
+	if !ok {
+		t.Fatalf("$facet value has type %T, want bson.M", pipeline[0][0].Value)
+	}
+	for _, name := range []string{"species", "breeds", "adoption_statuses"} {
+		if _, ok := facets[name]; !ok {
+			t.Errorf("$facet is missing the %q sub-pipeline", name)
+		}
+	}
+}
+
+func TestInternalError_MapsContextErrors(t *testing.T) {
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	expiredCtx, cancelExpired := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancelExpired()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  error
+		want codes.Code
+	}{
+		{"cancelled error", context.Background(), context.Canceled, codes.Canceled},
+		{"wrapped cancelled error", context.Background(), fmt.Errorf("could not query: %w", context.Canceled), codes.Canceled},
+		{"cancelled context", cancelledCtx, errors.New("connection reset"), codes.Canceled},
+		{"deadline error", context.Background(), context.DeadlineExceeded, codes.DeadlineExceeded},
+		{"expired context", expiredCtx, errors.New("server selection timeout"), codes.DeadlineExceeded},
+		{"other error", context.Background(), errors.New("boom"), codes.Internal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := handler.InternalError(tt.ctx, tt.err, "Failed")
+			if got := status.Code(err); got != tt.want {
+				t.Errorf("InternalError() code = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPetHandler_ListPets_CursorPagination(t *testing.T) {
+	var gotPage int
+	var gotAfter interface{}
+	mockRepo := &MockPetRepository{
+		ListPetsFunc: func(ctx context.Context, page, limit int, filters map[string]interface{}) ([]*domain.Pet, int64, error) {
+			gotPage, gotAfter = page, filters[repository.FilterAfter]
+			return []*domain.Pet{
+				{ID: "64b7f0c2a1b2c3d4e5f60001", Name: "Buddy", CreatedAt: time.Now()},
+				{ID: "64b7f0c2a1b2c3d4e5f60002", Name: "Max", CreatedAt: time.Now()},
+			}, 5, nil
+		},
+	}
+	h := handler.NewPetHandler(usecase.NewPetUsecase(mockRepo, &MockPetCache{}, nil, nil, nil, usecase.PetUsecaseConfig{}), "")
+	limit := int32(2)
+
+	// An empty cursor starts cursor pagination; a full page returns the cursor of the next one.
+	resp, err := h.ListPets(context.Background(), &pb.ListPetsRequest{Limit: &limit, Cursor: new(string)})
+	if err != nil {
+		t.Fatalf("ListPets() error = %v", err)
+	}
+	if gotPage != 0 || gotAfter != (*repository.PageCursor)(nil) {
+		t.Errorf("ListPets() passed page %d, after %v; want page 0 and a nil cursor", gotPage, gotAfter)
+	}
+	next := resp.GetNextCursor()
+	if next == "" {
+		t.Fatal("ListPets() next_cursor is empty for a full page")
+	}
+
+	if _, err := h.ListPets(context.Background(), &pb.ListPetsRequest{Limit: &limit, Cursor: &next}); err != nil {
+		t.Fatalf("ListPets() with next_cursor error = %v", err)
+	}
+	if after, ok := gotAfter.(*repository.PageCursor); !ok || after == nil || after.ID != "64b7f0c2a1b2c3d4e5f60002" {
+		t.Errorf("ListPets() with next_cursor passed after = %v, want the last pet of the previous page", gotAfter)
+	}
+
+	page := int32(2)
+	malformed := "not-a-cursor"
+	invalid := []struct {
+		name string
+		req  *pb.ListPetsRequest
+	}{
+		{"cursor and page", &pb.ListPetsRequest{Page: &page, Limit: &limit, Cursor: &next}},
+		{"malformed cursor", &pb.ListPetsRequest{Limit: &limit, Cursor: &malformed}},
+	}
+	for _, tt := range invalid {
+		if _, err := h.ListPets(context.Background(), tt.req); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("ListPets() with %s code = %v, want %v", tt.name, status.Code(err), codes.InvalidArgument)
+		}
+	}
+}
+
+func TestPetHandler_ListPets_SpeciesFilters(t *testing.T) {
+	var gotQuery bson.M
+	mockRepo := &MockPetRepository{
+		ListPetsFunc: func(ctx context.Context, page, limit int, filters map[string]interface{}) ([]*domain.Pet, int64, error) {
+			gotQuery = repository.ListPetsQuery(filters)
+			return nil, 0, nil
+		},
+	}
+	h := handler.NewPetHandler(usecase.NewPetUsecase(mockRepo, &MockPetCache{}, nil, nil, nil, usecase.PetUsecaseConfig{}), "")
+	dog := "Dog"
+
+	tests := []struct {
+		name string
+		req  *pb.ListPetsRequest
+		want interface{} // Condition on species; nil for none
+	}{
+		{"none", &pb.ListPetsRequest{}, nil},
+		{"species_filter only", &pb.ListPetsRequest{SpeciesFilter: &dog}, "Dog"},
+		{"one species_filters entry", &pb.ListPetsRequest{SpeciesFilters: []string{"Cat"}}, "Cat"},
+		{"several", &pb.ListPetsRequest{SpeciesFilters: []string{"Dog", "Cat", ""}}, bson.M{"$in": []string{"Dog", "Cat"}}},
+		{"both fields, deduplicated", &pb.ListPetsRequest{SpeciesFilter: &dog, SpeciesFilters: []string{"Cat", "Dog"}}, bson.M{"$in": []string{"Dog", "Cat"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := h.ListPets(context.Background(), tt.req); err != nil {
+				t.Fatalf("ListPets() error = %v", err)
+			}
+			if got, ok := gotQuery["species"]; fmt.Sprint(got) != fmt.Sprint(tt.want) || ok != (tt.want != nil) {
+				t.Errorf("species condition = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListPetsQuery_TagsMatchAny(t *testing.T) {
+	query := repository.ListPetsQuery(map[string]interface{}{
+		"species":              "Dog",
+		repository.FilterTags: []string{"house-trained", "good with kids"},
+	})
+
+	if query["species"] != "Dog" {
+		t.Errorf("ListPetsQuery() species = %v, want Dog", query["species"])
+	}
+	tagCond, ok := query["tags"].(bson.M)
+	if !ok {
+		t.Fatalf("ListPetsQuery() tags = %v, want a bson.M condition", query["tags"])
+	}
+	if got, ok := tagCond["$in"].([]string); !ok || len(got) != 2 {
+		t.Errorf("ListPetsQuery() tags condition = %v, want $in with both tags", tagCond)
+	}
+	if _, leaked := query[repository.FilterTagsMatchAll]; leaked {
+		t.Errorf("ListPetsQuery() should not use %q as a field", repository.FilterTagsMatchAll)
+	}
+}
+
+func TestListPetsQuery_TagsMatchAll(t *testing.T) {
+	query := repository.ListPetsQuery(map[string]interface{}{
+		repository.FilterTags:         []string{"house-trained", "good with kids"},
+		repository.FilterTagsMatchAll: true,
+	})
+
+	tagCond, ok := query["tags"].(bson.M)
+	if !ok {
+		t.Fatalf("ListPetsQuery() tags = %v, want a bson.M condition", query["tags"])
+	}
+	if _, ok := tagCond["$all"]; !ok {
+		t.Errorf("ListPetsQuery() tags condition = %v, want $all", tagCond)
+	}
+	if _, ok := tagCond["$in"]; ok {
+		t.Errorf("ListPetsQuery() tags condition = %v, should not use $in when matching all", tagCond)
+	}
+	if len(query) != 2 {
+		t.Errorf("ListPetsQuery() = %v, want only the tags and not-deleted conditions", query)
+	}
+}
+
+func TestListPetsQuery_CreatedBefore(t *testing.T) {
+	before := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	query := repository.ListPetsQuery(map[string]interface{}{
+		"adoption_status":              domain.StatusAvailable,
+		repository.FilterCreatedBefore: before,
+	})
+
+	createdCond, ok := query["created_at"].(bson.M)
+	if !ok {
+		t.Fatalf("ListPetsQuery() created_at = %v, want a bson.M condition", query["created_at"])
+	}
+	if got, _ := createdCond["$lt"].(time.Time); !got.Equal(before) {
+		t.Errorf("ListPetsQuery() created_at condition = %v, want $lt %v", createdCond, before)
+	}
+	if _, leaked := query[repository.FilterCreatedBefore]; leaked {
+		t.Errorf("ListPetsQuery() should not use %q as a field", repository.FilterCreatedBefore)
+	}
+}
+
+func TestListPetsQuery_SearchComposesWithFilters(t *testing.T) {
+	query := repository.ListPetsQuery(map[string]interface{}{
+		"species":               "Dog",
+		"adoption_status":       domain.StatusAvailable,
+		repository.FilterSearch: "golden retriever",
+	})
+
+	textCond, ok := query["$text"].(bson.M)
+	if !ok || textCond["$search"] != "golden retriever" {
+		t.Errorf("ListPetsQuery() $text = %v, want $search for the query", query["$text"])
+	}
+	if query["species"] != "Dog" || query["adoption_status"] != domain.StatusAvailable {
+		t.Errorf("ListPetsQuery() = %v, want the species and status filters next to $text", query)
+	}
+	if _, leaked := query[repository.FilterSearch]; leaked {
+		t.Errorf("ListPetsQuery() should not use %q as a field", repository.FilterSearch)
+	}
+
+	if blank := repository.ListPetsQuery(map[string]interface{}{repository.FilterSearch: "   "}); len(blank) != 1 {
+		t.Errorf("ListPetsQuery() with a blank search = %v, want only the not-deleted condition", blank)
+	}
+}
+
+func TestListPetsQuery_AgeRange(t *testing.T) {
+	query := repository.ListPetsQuery(map[string]interface{}{
+		"species":               "Dog",
+		"adoption_status":       domain.StatusAvailable,
+		repository.FilterMinAge: int32(1),
+		repository.FilterMaxAge: int32(2),
+	})
+	ageCond, ok := query["age"].(bson.M)
+	if !ok {
+		t.Fatalf("ListPetsQuery() age = %v, want a bson.M condition", query["age"])
+	}
+	if ageCond["$gte"] != int32(1) || ageCond["$lte"] != int32(2) || len(ageCond) != 2 {
+		t.Errorf("ListPetsQuery() age condition = %v, want $gte 1 and $lte 2", ageCond)
+	}
+	if query["species"] != "Dog" || query["adoption_status"] != domain.StatusAvailable || len(query) != 4 {
+		t.Errorf("ListPetsQuery() = %v, want the age range next to the species and status filters", query)
+	}
+
+	seniors := repository.ListPetsQuery(map[string]interface{}{repository.FilterMinAge: int32(8)})
+	if ageCond, _ := seniors["age"].(bson.M); len(ageCond) != 1 || ageCond["$gte"] != int32(8) {
+		t.Errorf("ListPetsQuery() with only min_age = %v, want only $gte 8", seniors)
+	}
+	for _, key := range []string{repository.FilterMinAge, repository.FilterMaxAge} {
+		if _, leaked := seniors[key]; leaked {
+			t.Errorf("ListPetsQuery() should not use %q as a field", key)
+		}
+	}
+}
+
+func TestPetUsecase_ListPets_ValidatesAgeRange(t *testing.T) {
+	called := false
+	mockRepo := &MockPetRepository{
+		ListPetsFunc: func(ctx context.Context, page, limit int, filters map[string]interface{}) ([]*domain.Pet, int64, error) {
+			called = true
+			return nil, 0, nil
+		},
+	}
+	uc := usecase.NewPetUsecase(mockRepo, &MockPetCache{}, nil, nil, nil, usecase.PetUsecaseConfig{})
+
+	tests := []struct {
+		name    string
+		filters map[string]interface{}
+		wantErr bool
+	}{
+		{"min and max", map[string]interface{}{repository.FilterMinAge: int32(1), repository.FilterMaxAge: int32(2)}, false},
+		{"equal bounds", map[string]interface{}{repository.FilterMinAge: int32(3), repository.FilterMaxAge: int32(3)}, false},
+		{"min above max", map[string]interface{}{repository.FilterMinAge: int32(8), repository.FilterMaxAge: int32(2)}, true},
+		{"negative min", map[string]interface{}{repository.FilterMinAge: int32(-1)}, true},
+		{"negative max", map[string]interface{}{repository.FilterMaxAge: int32(-1)}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called = false
+			_, _, err := uc.ListPets(context.Background(), 1, 10, tt.filters)
+			if tt.wantErr {
+				if !errors.Is(err, usecase.ErrInvalidAgeRange) || called {
+					t.Errorf("ListPets() error = %v, repository called = %v, want ErrInvalidAgeRange without a query", err, called)
+				}
+			} else if err != nil || !called {
+				t.Errorf("ListPets() error = %v, repository called = %v, want the query to run", err, called)
+			}
+		})
+	}
+}
+
+func TestMongoPetRepository_ListPets_Search(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("search filter sent with the other filters", func(mt *mtest.T) {
+		repo := repository.NewMongoDBPetRepositoryFromCollection(mt.Coll)
+		ns := mt.DB.Name() + "." + mt.Coll.Name()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{{Key: "_id", Value: "pet1"}, {Key: "name", Value: "Goldie"}}),
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{{Key: "n", Value: int32(1)}}),
+		)
+
+		pets, total, err := repo.ListPets(context.Background(), 1, 10, map[string]interface{}{
+			"species":               "Dog",
+			repository.FilterSearch: "golden",
+		})
+		if err != nil {
+			t.Fatalf("ListPets() error = %v", err)
+		}
+		if len(pets) != 1 || total != 1 {
+			t.Errorf("ListPets() = %d pets, total %d, want 1, 1", len(pets), total)
+		}
+
+		find := mt.GetStartedEvent().Command
+		if search := find.Lookup("filter", "$text", "$search").StringValue(); search != "golden" {
+			t.Errorf("find filter $text.$search = %q, want golden", search)
+		}
+		if species := find.Lookup("filter", "species").StringValue(); species != "Dog" {
+			t.Errorf("find filter species = %q, want Dog", species)
+		}
+		count := mt.GetStartedEvent().Command
+		if _, err := count.LookupErr("pipeline"); err != nil {
+			t.Fatalf("second command = %v, want the count aggregation", count)
+		}
+		if !strings.Contains(count.String(), `"$search": "golden"`) {
+			t.Errorf("count pipeline = %v, want it to apply the search too", count.Lookup("pipeline"))
+		}
+	})
+}
+
+func TestMongoPetRepository_ListPets_SearchMatchesNameAndDescription(t *testing.T) {
+	db := testutil.MongoDatabase(t)
+	ctx := context.Background()
+	repo := repository.NewMongoDBPetRepositoryFromClient(ctx, db.Client(), db.Name(), "pets", repository.IndexOptions{EnsureIndexes: true})
+
+	now := time.Now().UTC()
+	for _, pet := range []*domain.Pet{
+		{ID: "pet1", Name: "Goldie", Species: "Dog", Description: "A calm retriever", AdoptionStatus: domain.StatusAvailable, CreatedAt: now},
+		{ID: "pet2", Name: "Rex", Species: "Dog", Description: "Friendly RETRIEVER mix", AdoptionStatus: domain.StatusAdopted, CreatedAt: now},
+		{ID: "pet3", Name: "Whiskers", Species: "Cat", Description: "Loves to retrieve toys", AdoptionStatus: domain.StatusAvailable, CreatedAt: now},
+		{ID: "pet4", Name: "Retriever", Species: "Cat", Description: "Named after a dog", AdoptionStatus: domain.StatusAvailable, CreatedAt: now},
+	} {
+		if _, err := repo.CreatePet(ctx, pet); err != nil {
+			t.Fatalf("CreatePet(%s) error = %v", pet.ID, err)
+		}
+	}
+
+	ids := func(filters map[string]interface{}) string {
+		t.Helper()
+		pets, total, err := repo.ListPets(ctx, 1, 10, filters)
+		if err != nil {
+			t.Fatalf("ListPets(%v) error = %v", filters, err)
+		}
+		got := make([]string, len(pets))
+		for i, pet := range pets {
+			got[i] = pet.ID
+		}
+		slices.Sort(got)
+		if int(total) != len(got) {
+			t.Errorf("ListPets(%v) total = %d, want %d", filters, total, len(got))
+		}
+		return strings.Join(got, ",")
+	}
+
+	// Case-insensitive, in name or description; "retrieve" stems to the same word.
+	if got := ids(map[string]interface{}{repository.FilterSearch: "retriever"}); got != "pet1,pet2,pet3,pet4" {
+		t.Errorf("search retriever = %s, want pet1,pet2,pet3,pet4", got)
+	}
+	if got := ids(map[string]interface{}{repository.FilterSearch: "retriever", "species": "Dog", "adoption_status": domain.StatusAvailable}); got != "pet1" {
+		t.Errorf("search retriever among available dogs = %s, want pet1", got)
+	}
+	if got := ids(map[string]interface{}{repository.FilterSearch: "goldie"}); got != "pet1" {
+		t.Errorf("search goldie = %s, want pet1", got)
+	}
+	if got := ids(map[string]interface{}{repository.FilterSearch: "parrot"}); got != "" {
+		t.Errorf("search parrot = %s, want no pets", got)
+	}
+}
+
+func TestPetUsecase_AddPetTags_Normalizes(t *testing.T) {
+	var gotTags []string
+	mockRepo := &MockPetRepository{
+		GetPetByIDFunc: func(ctx context.Context, id string) (*domain.Pet, error) {
+			return &domain.Pet{ID: id, ListedByUserID: "owner1"}, nil
+		},
+		AddPetTagsFunc: func(ctx context.Context, id string, tags []string) (*domain.Pet, error) {
+			gotTags = tags
+			return &domain.Pet{ID: id, Tags: tags}, nil
+		},
+	}
+	mockCache := &MockPetCache{
+		DeletePetFunc: func(ctx context.Context, id string) error { return nil },
+	}
+	uc := usecase.NewPetUsecase(mockRepo, mockCache, nil, nil, nil, usecase.PetUsecaseConfig{})
+
+	_, err := uc.AddPetTags(context.Background(), "pet1", []string{"  Good  with Kids ", "good with kids", "", "House-Trained"}, "owner1")
+	if err != nil {
+		t.Fatalf("AddPetTags() error = %v", err)
+	}
+	if len(gotTags) != 2 || gotTags[0] != "good with kids" || gotTags[1] != "house-trained" {
+		t.Errorf("AddPetTags() sent tags %q to repository, want normalized and deduplicated", gotTags)
+	}
+
+	if _, err := uc.AddPetTags(context.Background(), "pet1", []string{"  "}, "owner1"); err == nil || err.Error() != "at least one valid tag is required" {
+		t.Errorf("AddPetTags() with blank tags error = %v, want validation error", err)
+	}
+
+	gotTags = nil
+	if _, err := uc.RemovePetTags(context.Background(), "pet1", []string{"house-trained"}, "someone-else"); !errors.Is(err, usecase.ErrNotPetOwner) {
+		t.Errorf("RemovePetTags() by another user error = %v, want ErrNotPetOwner", err)
+	}
+	if _, err := uc.AddPetTags(context.Background(), "pet1", []string{"calm"}, "someone-else"); !errors.Is(err, usecase.ErrNotPetOwner) || gotTags != nil {
+		t.Errorf("AddPetTags() by another user error = %v (sent %q), want ErrNotPetOwner and no update", err, gotTags)
+	}
+}
+
+func TestMongoPetRepository_ListPets_TagFilters(t *testing.T) {
+	db := testutil.MongoDatabase(t)
+	ctx := context.Background()
+	repo := repository.NewMongoDBPetRepositoryFromClient(ctx, db.Client(), db.Name(), "pets", repository.IndexOptions{EnsureIndexes: true})
+
+	now := time.Now().UTC()
+	for _, pet := range []*domain.Pet{
+		{ID: "pet1", Name: "Goldie", Species: "Dog", Tags: []string{"good with kids", "house-trained"}, AdoptionStatus: domain.StatusAvailable, CreatedAt: now},
+		{ID: "pet2", Name: "Rex", Species: "Dog", Tags: []string{"good with kids"}, AdoptionStatus: domain.StatusAvailable, CreatedAt: now},
+		{ID: "pet3", Name: "Whiskers", Species: "Cat", Tags: []string{"house-trained", "calm"}, AdoptionStatus: domain.StatusAvailable, CreatedAt: now},
+		{ID: "pet4", Name: "Tweety", Species: "Bird", AdoptionStatus: domain.StatusAvailable, CreatedAt: now},
+	} {
+		if _, err := repo.CreatePet(ctx, pet); err != nil {
+			t.Fatalf("CreatePet(%s) error = %v", pet.ID, err)
+		}
+	}
+
+	ids := func(filters map[string]interface{}) string {
+		t.Helper()
+		pets, total, err := repo.ListPets(ctx, 1, 10, filters)
+		if err != nil {
+			t.Fatalf("ListPets(%v) error = %v", filters, err)
+		}
+		got := make([]string, len(pets))
+		for i, pet := range pets {
+			got[i] = pet.ID
+		}
+		slices.Sort(got)
+		if int(total) != len(got) {
+			t.Errorf("ListPets(%v) total = %d, want %d", filters, total, len(got))
+		}
+		return strings.Join(got, ",")
+	}
+
+	tags := []string{"good with kids", "house-trained"}
+	if got := ids(map[string]interface{}{repository.FilterTags: tags}); got != "pet1,pet2,pet3" {
+		t.Errorf("any of %q = %s, want pet1,pet2,pet3", tags, got)
+	}
+	if got := ids(map[string]interface{}{repository.FilterTags: tags, repository.FilterTagsMatchAll: true}); got != "pet1" {
+		t.Errorf("all of %q = %s, want pet1", tags, got)
+	}
+	if got := ids(map[string]interface{}{repository.FilterTags: tags, repository.FilterTagsMatchAll: true, "species": "Cat"}); got != "" {
+		t.Errorf("all of %q among cats = %s, want no pets", tags, got)
+	}
+	if got := ids(map[string]interface{}{repository.FilterTags: []string{"calm"}}); got != "pet3" {
+		t.Errorf("any of calm = %s, want pet3", got)
+	}
+}
+
+func TestPetUsecase_AddImageURLs_EnforcesMaxImages(t *testing.T) {
+	pet := &domain.Pet{ID: "pet1", ListedByUserID: "owner1", ImageURLs: []string{"https://img.example.com/pets/pet1/1.jpg", "https://img.example.com/pets/pet1/2.jpg"}}
+	var addCalls int
+	mockRepo := &MockPetRepository{
+		GetPetByIDFunc: func(ctx context.Context, id string) (*domain.Pet, error) {
+			return &domain.Pet{ID: pet.ID, ListedByUserID: pet.ListedByUserID, ImageURLs: append([]string(nil), pet.ImageURLs...)}, nil
+		},
+		AddPetImageURLsFunc: func(ctx context.Context, id string, imageURLs []string) (*domain.Pet, error) {
+			addCalls++
+			for _, u := range imageURLs {
+				if !slices.Contains(pet.ImageURLs, u) {
+					pet.ImageURLs = append(pet.ImageURLs, u)
+				}
+			}
+			return pet, nil
+		},
+	}
+	mockCache := &MockPetCache{
+		DeletePetFunc: func(ctx context.Context, id string) error { return nil },
+	}
+	uc := usecase.NewPetUsecase(mockRepo, mockCache, storage.NewFakeImageStorage("https://img.example.com"), nil, nil, usecase.PetUsecaseConfig{MaxImagesPerPet: 4})
+
+	// Up to the cap; a URL the pet already has does not count.
+	updated, err := uc.AddImageURLs(context.Background(), "pet1", []string{"https://img.example.com/pets/pet1/2.jpg", "https://img.example.com/pets/pet1/3.jpg", "https://img.example.com/pets/pet1/4.jpg"}, "owner1")
+	if err != nil {
+		t.Fatalf("AddImageURLs() up to the cap error = %v", err)
+	}
+	if len(updated.ImageURLs) != 4 {
+		t.Errorf("ImageURLs = %v, want 4 images", updated.ImageURLs)
+	}
+
+	// Beyond the cap nothing is added.
+	if _, err := uc.AddImageURLs(context.Background(), "pet1", []string{"https://img.example.com/pets/pet1/5.jpg"}, "owner1"); !errors.Is(err, usecase.ErrTooManyImages) {
+		t.Errorf("AddImageURLs() beyond the cap error = %v, want ErrTooManyImages", err)
+	}
+	if addCalls != 1 {
+		t.Errorf("repository AddPetImageURLs called %d times, want 1", addCalls)
+	}
+	if len(pet.ImageURLs) != 4 {
+		t.Errorf("ImageURLs after rejected add = %v, want still 4 images", pet.ImageURLs)
+	}
+
+	// Replacing the whole gallery is capped too.
+	tooMany := []string{"https://a.example.com/1", "https://a.example.com/2", "https://a.example.com/3", "https://a.example.com/4", "https://a.example.com/5"}
+	if _, err := uc.UpdatePet(context.Background(), "pet1", usecase.UpdatePetRequestData{ImageURLs: tooMany}, "owner1"); !errors.Is(err, usecase.ErrTooManyImages) {
+		t.Errorf("UpdatePet() with %d images error = %v, want ErrTooManyImages", len(tooMany), err)
+	}
+}
+
+func TestPetUsecase_AddImageURLs_RequiresOwnerAndUploadedImages(t *testing.T) {
+	var addCalls int
+	mockRepo := &MockPetRepository{
+		GetPetByIDFunc: func(ctx context.Context, id string) (*domain.Pet, error) {
+			return &domain.Pet{ID: id, ListedByUserID: "owner1"}, nil
+		},
+		AddPetImageURLsFunc: func(ctx context.Context, id string, imageURLs []string) (*domain.Pet, error) {
+			addCalls++
+			return &domain.Pet{ID: id, ListedByUserID: "owner1", ImageURLs: imageURLs}, nil
+		},
+	}
+	mockCache := &MockPetCache{
+		DeletePetFunc: func(ctx context.Context, id string) error { return nil },
+	}
+	uc := usecase.NewPetUsecase(mockRepo, mockCache, storage.NewFakeImageStorage("https://cdn.example.com/pet-images/"), nil, nil, usecase.PetUsecaseConfig{})
+
+	if _, err := uc.AddImageURLs(context.Background(), "pet1", []string{"https://cdn.example.com/pet-images/pets/pet1/a.jpg"}, "owner1"); err != nil {
+		t.Fatalf("AddImageURLs() of an uploaded image error = %v, want nil", err)
+	}
+
+	if _, err := uc.AddImageURLs(context.Background(), "pet1", []string{"https://cdn.example.com/pet-images/pets/pet1/a.jpg"}, "someone-else"); !errors.Is(err, usecase.ErrNotPetOwner) {
+		t.Errorf("AddImageURLs() by another user error = %v, want ErrNotPetOwner", err)
+	}
+
+	for _, raw := range []string{
+		"https://evil.example.com/pets/pet1/a.jpg",              // other host
+		"https://cdn.example.com/pet-images/pets/pet2/a.jpg",    // another pet's prefix
+		"https://cdn.example.com/pet-images/pets/pet1/",         // no object
+		"https://cdn.example.com/pet-images/pets/pet1/x/a.jpg",  // nested key
+		"https://cdn.example.com/pet-images/pets/pet1/../a.jpg", // escapes the prefix
+		"https://cdn.example.com/pet-images/pets/pet1/a.jpg?x=1",
+	} {
+		if _, err := uc.AddImageURLs(context.Background(), "pet1", []string{raw}, "owner1"); err == nil || err.Error() != "invalid image URL" {
+			t.Errorf("AddImageURLs(%q) error = %v, want 'invalid image URL'", raw, err)
+		}
+	}
+	if addCalls != 1 {
+		t.Errorf("repository AddPetImageURLs called %d times, want 1", addCalls)
+	}
+}
+
+func TestPetUsecase_ImageURLsOnCreateAndUpdate_MustBeUploads(t *testing.T) {
+	var updated *domain.Pet
+	mockRepo := &MockPetRepository{
+		CreatePetFunc: func(ctx context.Context, pet *domain.Pet) (*domain.Pet, error) {
+			t.Errorf("CreatePet() stored a pet with image URLs %v", pet.ImageURLs)
+			return pet, nil
+		},
+		GetPetByIDFunc: func(ctx context.Context, id string) (*domain.Pet, error) {
+			return &domain.Pet{ID: id, ListedByUserID: "owner1", ImageURLs: []string{"https://legacy.example.com/buddy.jpg"}}, nil
+		},
+		UpdatePetFunc: func(ctx context.Context, pet *domain.Pet) (*domain.Pet, error) {
+			updated = pet
+			return pet, nil
+		},
+	}
+	mockCache := &MockPetCache{
+		DeletePetFunc: func(ctx context.Context, id string) error { return nil },
+	}
+	uc := usecase.NewPetUsecase(mockRepo, mockCache, storage.NewFakeImageStorage("https://cdn.example.com/pet-images"), nil, nil, usecase.PetUsecaseConfig{})
+
+	_, err := uc.CreatePet(context.Background(), usecase.CreatePetRequestData{Name: "Buddy", Species: "Dog", ListedByUserID: "owner1", ImageURLs: []string{"https://cdn.example.com/pet-images/pets/pet1/a.jpg"}})
+	if !errors.Is(err, usecase.ErrInvalidImageURL) {
+		t.Errorf("CreatePet() with image URLs error = %v, want ErrInvalidImageURL", err)
+	}
+
+	_, err = uc.UpdatePet(context.Background(), "pet1", usecase.UpdatePetRequestData{ImageURLs: []string{"https://evil.example.com/a.jpg"}}, "owner1")
+	if !errors.Is(err, usecase.ErrInvalidImageURL) {
+		t.Errorf("UpdatePet() with a foreign image URL error = %v, want ErrInvalidImageURL", err)
+	}
+	if updated != nil {
+		t.Errorf("UpdatePet() stored image URLs %v after rejecting them", updated.ImageURLs)
+	}
+
+	// Keeping an existing image and adding an upload of the pet is fine.
+	gallery := []string{"https://cdn.example.com/pet-images/pets/pet1/a.jpg", "https://legacy.example.com/buddy.jpg"}
+	if _, err := uc.UpdatePet(context.Background(), "pet1", usecase.UpdatePetRequestData{ImageURLs: gallery}, "owner1"); err != nil {
+		t.Fatalf("UpdatePet() with the pet's own images error = %v", err)
+	}
+	if updated == nil || !slices.Equal(updated.ImageURLs, gallery) {
+		t.Errorf("UpdatePet() stored pet %+v, want image URLs %v", updated, gallery)
+	}
+}
+
+func TestPetUsecase_UpdatePetAdoptionStatus_RecordsReason(t *testing.T) {
+	var gotChange domain.StatusChange
+	mockRepo := &MockPetRepository{
+		GetPetByIDFunc: func(ctx context.Context, id string) (*domain.Pet, error) {
+			return &domain.Pet{ID: id, AdoptionStatus: domain.StatusAvailable, Version: 3}, nil
+		},
+		UpdatePetAdoptionStatusFunc: func(ctx context.Context, id string, expectedVersion int, change domain.StatusChange, adopterUserID *string) (*domain.Pet, error) {
+			if expectedVersion != 3 {
+				t.Errorf("expectedVersion = %d, want 3", expectedVersion)
+			}
+			gotChange = change
+			return &domain.Pet{ID: id, AdoptionStatus: change.ToStatus, StatusHistory: []domain.StatusChange{change}, Version: 4}, nil
+		},
+	}
+	mockCache := &MockPetCache{
+		DeletePetFunc: func(ctx context.Context, id string) error { return nil },
+	}
+	uc := usecase.NewPetUsecase(mockRepo, mockCache, nil, nil, nil, usecase.PetUsecaseConfig{})
+
+	updated, err := uc.UpdatePetAdoptionStatus(context.Background(), "pet1", domain.StatusPendingAdoption, nil, "  reserved pending home check ", "lister-1")
+	if err != nil {
+		t.Fatalf("UpdatePetAdoptionStatus() error = %v", err)
+	}
+	if gotChange.FromStatus != domain.StatusAvailable || gotChange.ToStatus != domain.StatusPendingAdoption {
+		t.Errorf("recorded change = %s -> %s, want %s -> %s", gotChange.FromStatus, gotChange.ToStatus, domain.StatusAvailable, domain.StatusPendingAdoption)
+	}
+	if gotChange.Reason != "reserved pending home check" {
+		t.Errorf("recorded reason = %q, want %q", gotChange.Reason, "reserved pending home check")
+	}
+	if gotChange.ChangedBy != "lister-1" || gotChange.Forced || gotChange.ChangedAt.IsZero() {
+		t.Errorf("recorded change = %+v, want changed by lister-1, not forced, with a time", gotChange)
+	}
+	if len(updated.StatusHistory) != 1 || updated.StatusHistory[0].Reason != gotChange.Reason {
+		t.Errorf("StatusHistory = %+v, want the recorded change", updated.StatusHistory)
+	}
+
+	// Overlong reasons are rejected before anything is written.
+	mockRepo.UpdatePetAdoptionStatusFunc = func(ctx context.Context, id string, expectedVersion int, change domain.StatusChange, adopterUserID *string) (*domain.Pet, error) {
+		t.Fatal("UpdatePetAdoptionStatus should not reach the repository with an overlong reason")
+		return nil, nil
+	}
+	longReason := strings.Repeat("x", domain.MaxStatusReasonLength+1)
+	if _, err := uc.UpdatePetAdoptionStatus(context.Background(), "pet1", domain.StatusPendingAdoption, nil, longReason, "lister-1"); !errors.Is(err, usecase.ErrStatusReasonTooLong) {
+		t.Errorf("UpdatePetAdoptionStatus() with an overlong reason error = %v, want ErrStatusReasonTooLong", err)
+	}
+}
+
+func TestPetUsecase_AdminSetPetStatus_BypassesAdopterGuard(t *testing.T) {
+	var gotChange domain.StatusChange
+	mockRepo := &MockPetRepository{
+		GetPetByIDFunc: func(ctx context.Context, id string) (*domain.Pet, error) {
+			return &domain.Pet{ID: id, AdoptionStatus: domain.StatusPendingAdoption}, nil
+		},
+		UpdatePetAdoptionStatusFunc: func(ctx context.Context, id string, expectedVersion int, change domain.StatusChange, adopterUserID *string) (*domain.Pet, error) {
+			t.Fatal("UpdatePetAdoptionStatus should not reach the repository without an adopter")
+			return nil, nil
+		},
+		ForcePetAdoptionStatusFunc: func(ctx context.Context, id string, change domain.StatusChange) (*domain.Pet, error) {
+			gotChange = change
+			return &domain.Pet{ID: id, AdoptionStatus: change.ToStatus, StatusHistory: []domain.StatusChange{change}}, nil
+		},
+	}
+	mockCache := &MockPetCache{
+		DeletePetFunc: func(ctx context.Context, id string) error { return nil },
+	}
+	uc := usecase.NewPetUsecase(mockRepo, mockCache, nil, nil, nil, usecase.PetUsecaseConfig{})
+
+	// The normal path rejects ADOPTED without an adopter.
+	if _, err := uc.UpdatePetAdoptionStatus(context.Background(), "pet1", domain.StatusAdopted, nil, "", ""); err == nil {
+		t.Fatal("UpdatePetAdoptionStatus() expected an error for ADOPTED without adopter")
+	}
+
+	var logBuf bytes.Buffer
+	originalLogOutput := log.Writer()
+	log.SetOutput(&logBuf)
+
+	reason := "Adopted offline at the shelter, paperwork on file"
+	pet, err := uc.AdminSetPetStatus(context.Background(), "pet1", domain.StatusAdopted, reason, "admin1")
+	log.SetOutput(originalLogOutput)
+	if err != nil {
+		t.Fatalf("AdminSetPetStatus() error = %v", err)
+	}
+	if pet.AdoptionStatus != domain.StatusAdopted {
+		t.Errorf("AdminSetPetStatus() status = %v, want %v", pet.AdoptionStatus, domain.StatusAdopted)
+	}
+	if !gotChange.Forced || gotChange.Reason != reason || gotChange.ChangedBy != "admin1" || gotChange.FromStatus != domain.StatusPendingAdoption {
+		t.Errorf("AdminSetPetStatus() recorded change = %+v, want forced change with reason and actor", gotChange)
+	}
+	if !strings.Contains(logBuf.String(), reason) {
+		t.Errorf("AdminSetPetStatus() log = %q, want it to contain the reason", logBuf.String())
+	}
+
+	if _, err := uc.AdminSetPetStatus(context.Background(), "pet1", domain.StatusAdopted, "   ", "admin1"); err == nil {
+		t.Error("AdminSetPetStatus() without reason expected an error")
+	}
+}
+
+func TestMongoPetRepository_OptimisticLocking(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	// A versioned update that matched nothing, followed by the existence check finding the pet.
+	staleVersionResponses := func(mt *mtest.T) []bson.D {
+		return []bson.D{
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}, bson.E{Key: "nModified", Value: 0}),
+			mtest.CreateCursorResponse(0, mt.DB.Name()+"."+mt.Coll.Name(), mtest.FirstBatch, bson.D{{Key: "n", Value: int32(1)}}),
+		}
+	}
+
+	mt.Run("UpdatePet stale version", func(mt *mtest.T) {
+		repo := repository.NewMongoDBPetRepositoryFromCollection(mt.Coll)
+		mt.AddMockResponses(staleVersionResponses(mt)...)
+
+		_, err := repo.UpdatePet(context.Background(), &domain.Pet{ID: "pet1", Name: "Rex", Version: 1})
+		if !errors.Is(err, repository.ErrConcurrentModification) {
+			t.Errorf("UpdatePet() error = %v, want ErrConcurrentModification", err)
+		}
+	})
+
+	mt.Run("UpdatePet increments version", func(mt *mtest.T) {
+		repo := repository.NewMongoDBPetRepositoryFromCollection(mt.Coll)
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+
+		updated, err := repo.UpdatePet(context.Background(), &domain.Pet{ID: "pet1", Name: "Rex", Version: 4})
+		if err != nil {
+			t.Fatalf("UpdatePet() error = %v", err)
+		}
+		if updated.Version != 5 {
+			t.Errorf("UpdatePet() version = %d, want 5", updated.Version)
+		}
+	})
+
+	mt.Run("UpdatePetAdoptionStatus stale version", func(mt *mtest.T) {
+		repo := repository.NewMongoDBPetRepositoryFromCollection(mt.Coll)
+		mt.AddMockResponses(staleVersionResponses(mt)...)
+
+		_, err := repo.UpdatePetAdoptionStatus(context.Background(), "pet1", 2, domain.StatusChange{ToStatus: domain.StatusPendingAdoption, ChangedAt: time.Now().UTC()}, nil)
+		if !errors.Is(err, repository.ErrConcurrentModification) {
+			t.Errorf("UpdatePetAdoptionStatus() error = %v, want ErrConcurrentModification", err)
+		}
+	})
+
+	mt.Run("UpdatePetAdoptionStatus missing pet", func(mt *mtest.T) {
+		repo := repository.NewMongoDBPetRepositoryFromCollection(mt.Coll)
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}, bson.E{Key: "nModified", Value: 0}),
+			mtest.CreateCursorResponse(0, mt.DB.Name()+"."+mt.Coll.Name(), mtest.FirstBatch),
+		)
+
+		_, err := repo.UpdatePetAdoptionStatus(context.Background(), "pet1", 2, domain.StatusChange{ToStatus: domain.StatusPendingAdoption, ChangedAt: time.Now().UTC()}, nil)
+		if err == nil || err.Error() != "pet not found for status update" {
+			t.Errorf("UpdatePetAdoptionStatus() error = %v, want pet not found", err)
+		}
+	})
+}
+
+func TestMongoPetRepository_SuggestBreeds(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("prefix matched case-insensitively within the species", func(mt *mtest.T) {
+		repo := repository.NewMongoDBPetRepositoryFromCollection(mt.Coll)
+		// The distinct breeds MongoDB holds for the seeded dogs.
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "values", Value: bson.A{
+			"Labrador", "beagle", "Bernese Mountain Dog", "Beagle", "Boxer", "",
+		}}))
+
+		breeds, err := repo.SuggestBreeds(context.Background(), "DOG", "BE", 10)
+		if err != nil {
+			t.Fatalf("SuggestBreeds() error = %v", err)
+		}
+		if want := []string{"beagle", "Bernese Mountain Dog"}; fmt.Sprint(breeds) != fmt.Sprint(want) {
+			t.Errorf("SuggestBreeds() = %v, want %v", breeds, want)
+		}
+
+		cmd := mt.GetStartedEvent().Command
+		if key := cmd.Lookup("key").StringValue(); key != "breed" {
+			t.Errorf("distinct key = %q, want breed", key)
+		}
+		pattern, options := cmd.Lookup("query", "species").Regex()
+		if pattern != "^DOG$" || options != "i" {
+			t.Errorf("distinct species filter = /%s/%s, want /^DOG$/i", pattern, options)
+		}
+	})
+
+	mt.Run("limit", func(mt *mtest.T) {
+		repo := repository.NewMongoDBPetRepositoryFromCollection(mt.Coll)
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "values", Value: bson.A{"Siamese", "Persian", "Maine Coon"}}))
+
+		breeds, err := repo.SuggestBreeds(context.Background(), "cat", "", 2)
+		if err != nil {
+			t.Fatalf("SuggestBreeds() error = %v", err)
+		}
+		if want := []string{"Maine Coon", "Persian"}; fmt.Sprint(breeds) != fmt.Sprint(want) {
+			t.Errorf("SuggestBreeds() = %v, want %v", breeds, want)
+		}
+	})
+}
+
+// recordingIndexCreator counts CreateMany calls instead of talking to MongoDB.
+type recordingIndexCreator struct {
+	calls int
+}
+
+func (c *recordingIndexCreator) CreateMany(ctx context.Context, models []mongo.IndexModel, opts ...*options.CreateIndexesOptions) ([]string, error) {
+	c.calls++
+	return nil, nil
+}
+
+func TestMongoPetRepository_EnsureIndexes(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("disabled", func(mt *mtest.T) {
+		creator := &recordingIndexCreator{}
+		repository.NewMongoDBPetRepositoryFromClient(context.Background(), mt.Client, "petstore_pets", "pets",
+			repository.IndexOptions{EnsureIndexes: false, Creator: creator})
+		if creator.calls != 0 {
+			t.Errorf("CreateMany called %d times with EnsureIndexes off, want 0", creator.calls)
+		}
+	})
+
+	mt.Run("enabled", func(mt *mtest.T) {
+		creator := &recordingIndexCreator{}
+		repository.NewMongoDBPetRepositoryFromClient(context.Background(), mt.Client, "petstore_pets", "pets",
+			repository.IndexOptions{EnsureIndexes: true, Creator: creator})
+		if creator.calls != 1 {
+			t.Errorf("CreateMany called %d times with EnsureIndexes on, want 1", creator.calls)
+		}
+	})
+}
+
+func TestMigrate_Run_EnsuresIndexes(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("indexes only", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		if err := migrate.Run(context.Background(), mt.DB, mt.Coll.Name(), migrate.Options{}); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+
+		started := mt.GetStartedEvent()
+		if started == nil || started.CommandName != "createIndexes" {
+			t.Fatalf("first command = %v, want createIndexes", started)
+		}
+		created := make(map[string]bool)
+		indexes, _ := started.Command.Lookup("indexes").Array().Values()
+		for _, idx := range indexes {
+			created[idx.Document().Lookup("name").StringValue()] = true
+		}
+		for _, want := range []string{"species_1", "adoption_status_1", "age_1", "adoption_status_1_updated_at_-1", "tags_1"} {
+			if !created[want] {
+				t.Errorf("index %q not created; got %v", want, created)
+			}
+		}
+		if next := mt.GetStartedEvent(); next != nil {
+			t.Errorf("unexpected command %q without Seed", next.CommandName)
+		}
+	})
+
+	mt.Run("with seed", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+		for range migrate.SeedPets {
+			mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 0}))
+		}
+
+		if err := migrate.Run(context.Background(), mt.DB, mt.Coll.Name(), migrate.Options{Seed: true}); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		mt.GetStartedEvent() // createIndexes
+		for i := range migrate.SeedPets {
+			if started := mt.GetStartedEvent(); started == nil || started.CommandName != "update" {
+				t.Fatalf("seed command %d = %v, want update", i, started)
+			}
+		}
+	})
+}
+
+func TestPetHandler_GetPet_PlaceholderThumbnail(t *testing.T) {
+	const placeholder = "https://cdn.example.com/pet-placeholder.png"
+	pets := map[string]*domain.Pet{
+		"no-images":   {ID: "no-images", Name: "Rex"},
+		"with-images": {ID: "with-images", Name: "Mia", ImageURLs: []string{"https://cdn.example.com/mia-1.jpg", "https://cdn.example.com/mia-2.jpg"}},
+	}
+	mockRepo := &MockPetRepository{
+		GetPetByIDFunc: func(ctx context.Context, id string) (*domain.Pet, error) {
+			return pets[id], nil
+		},
+	}
+	mockCache := &MockPetCache{
+		GetPetFunc: func(ctx context.Context, id string) (*domain.Pet, error) { return nil, repository.ErrCacheMiss },
+		SetPetFunc: func(ctx context.Context, id string, pet *domain.Pet, expiration time.Duration) error { return nil },
+	}
+	h := handler.NewPetHandler(usecase.NewPetUsecase(mockRepo, mockCache, nil, nil, nil, usecase.PetUsecaseConfig{}), placeholder)
+
+	resp, err := h.GetPet(context.Background(), &pb.GetPetRequest{PetId: "no-images"})
+	if err != nil {
+		t.Fatalf("GetPet() error = %v", err)
+	}
+	if resp.GetPet().GetThumbnailUrl() != placeholder {
+		t.Errorf("GetPet() thumbnail_url = %q, want placeholder %q", resp.GetPet().GetThumbnailUrl(), placeholder)
+	}
+	if len(resp.GetPet().GetImageUrls()) != 0 {
+		t.Errorf("GetPet() image_urls = %v, want none", resp.GetPet().GetImageUrls())
+	}
+	if len(pets["no-images"].ImageURLs) != 0 {
+		t.Error("GetPet() should not add the placeholder to the stored pet")
+	}
+
+	resp, err = h.GetPet(context.Background(), &pb.GetPetRequest{PetId: "with-images"})
+	if err != nil {
+		t.Fatalf("GetPet() error = %v", err)
+	}
+	if resp.GetPet().GetThumbnailUrl() != "https://cdn.example.com/mia-1.jpg" {
+		t.Errorf("GetPet() thumbnail_url = %q, want the first image", resp.GetPet().GetThumbnailUrl())
+	}
+	if len(resp.GetPet().GetImageUrls()) != 2 {
+		t.Errorf("GetPet() image_urls = %v, want the pet's own images", resp.GetPet().GetImageUrls())
+	}
+}
+
+func TestPetUsecase_UpdatePet_NoFieldsToUpdate(t *testing.T) {
+	mockRepo := &MockPetRepository{
+		GetPetByIDFunc: func(ctx context.Context, id string) (*domain.Pet, error) {
+			return &domain.Pet{ID: id, Name: "Rex", Breed: "Beagle", ListedByUserID: "owner1"}, nil
+		},
+		UpdatePetFunc: func(ctx context.Context, pet *domain.Pet) (*domain.Pet, error) {
+			return pet, nil
+		},
+	}
+	uc := usecase.NewPetUsecase(mockRepo, &MockPetCache{}, nil, nil, nil, usecase.PetUsecaseConfig{})
+
+	if _, err := uc.UpdatePet(context.Background(), "pet1", usecase.UpdatePetRequestData{}, "owner1"); !errors.Is(err, usecase.ErrNoFieldsToUpdate) {
+		t.Errorf("UpdatePet() with no fields error = %v, want ErrNoFieldsToUpdate", err)
+	}
+
+	h := handler.NewPetHandler(uc, "")
+	_, err := h.UpdatePet(context.Background(), &pb.UpdatePetRequest{PetId: "pet1"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("UpdatePet() with an empty request code = %v, want %v", status.Code(err), codes.InvalidArgument)
+	}
+
+	// An explicitly empty breed is a field to update: it clears the breed.
+	mockCache := &MockPetCache{
+		DeletePetFunc: func(ctx context.Context, id string) error { return nil },
+	}
+	h = handler.NewPetHandler(usecase.NewPetUsecase(mockRepo, mockCache, nil, nil, nil, usecase.PetUsecaseConfig{}), "")
+	emptyBreed := ""
+	ownerCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-user-id", "owner1"))
+	resp, err := h.UpdatePet(ownerCtx, &pb.UpdatePetRequest{PetId: "pet1", Breed: &emptyBreed})
+	if err != nil {
+		t.Fatalf("UpdatePet() clearing breed error = %v", err)
+	}
+	if resp.GetPet().GetBreed() != "" {
+		t.Errorf("UpdatePet() breed = %q, want cleared", resp.GetPet().GetBreed())
+	}
+}
+
+func TestPetUsecase_UpdateAndDeletePet_RequireOwner(t *testing.T) {
+	deleted := 0
+	mockRepo := &MockPetRepository{
+		GetPetByIDFunc: func(ctx context.Context, id string) (*domain.Pet, error) {
+			if id == "orphan" {
+				return &domain.Pet{ID: id, Name: "Rex"}, nil // Listed before owners were recorded
+			}
+			return &domain.Pet{ID: id, Name: "Rex", ListedByUserID: "owner1"}, nil
+		},
+		UpdatePetFunc: func(ctx context.Context, pet *domain.Pet) (*domain.Pet, error) {
+			return pet, nil
+		},
+		DeletePetFunc: func(ctx context.Context, id string) error {
+			deleted++
+			return nil
+		},
+	}
+	mockCache := &MockPetCache{
+		DeletePetFunc: func(ctx context.Context, id string) error { return nil },
+	}
+	uc := usecase.NewPetUsecase(mockRepo, mockCache, nil, nil, nil, usecase.PetUsecaseConfig{})
+	name := "Max"
+	update := usecase.UpdatePetRequestData{Name: &name}
+
+	if _, err := uc.UpdatePet(context.Background(), "pet1", update, "owner1"); err != nil {
+		t.Errorf("UpdatePet() by owner error = %v", err)
+	}
+	if err := uc.DeletePet(context.Background(), "pet1", "owner1"); err != nil {
+		t.Errorf("DeletePet() by owner error = %v", err)
+	}
+	for _, tc := range []struct{ name, petID, callerID string }{
+		{"non-owner", "pet1", "intruder"},
+		{"missing caller", "pet1", ""},
+		{"pet without owner", "orphan", "owner1"},
+	} {
+		if _, err := uc.UpdatePet(context.Background(), tc.petID, update, tc.callerID); !errors.Is(err, usecase.ErrNotPetOwner) {
+			t.Errorf("UpdatePet() by %s error = %v, want ErrNotPetOwner", tc.name, err)
+		}
+		if err := uc.DeletePet(context.Background(), tc.petID, tc.callerID); !errors.Is(err, usecase.ErrNotPetOwner) {
+			t.Errorf("DeletePet() by %s error = %v, want ErrNotPetOwner", tc.name, err)
+		}
+	}
+	if deleted != 1 {
+		t.Errorf("repository DeletePet called %d times, want only for the owner", deleted)
+	}
+
+	// The handler takes the caller from the x-user-id metadata set by the gateway.
+	h := handler.NewPetHandler(uc, "")
+	intruderCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-user-id", "intruder"))
+	if _, err := h.UpdatePet(intruderCtx, &pb.UpdatePetRequest{PetId: "pet1", Name: &name}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("UpdatePet() by non-owner code = %v, want %v", status.Code(err), codes.PermissionDenied)
+	}
+	if _, err := h.DeletePet(context.Background(), &pb.DeletePetRequest{PetId: "pet1"}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("DeletePet() without caller code = %v, want %v", status.Code(err), codes.PermissionDenied)
+	}
+}
+
+// newTransferTestUsecase returns a usecase over a pet "pet1" listed by "owner1" and a user
+// directory that only knows "owner1" and "owner2". transfers collects the recorded transfers.
+func newTransferTestUsecase(transfers *[]domain.ListingTransfer) usecase.PetUsecase {
+	mockRepo := &MockPetRepository{
+		GetPetByIDFunc: func(ctx context.Context, id string) (*domain.Pet, error) {
+			return &domain.Pet{ID: id, ListedByUserID: "owner1", Version: 3}, nil
+		},
+		TransferPetListingFunc: func(ctx context.Context, id string, expectedVersion int, transfer domain.ListingTransfer) (*domain.Pet, error) {
+			if expectedVersion != 3 {
+				return nil, repository.ErrConcurrentModification
+			}
+			*transfers = append(*transfers, transfer)
+			return &domain.Pet{ID: id, ListedByUserID: transfer.ToUserID, ListingHistory: *transfers, Version: 4}, nil
+		},
+	}
+	mockCache := &MockPetCache{
+		DeletePetFunc: func(ctx context.Context, id string) error { return nil },
+	}
+	users := &MockUserServiceClient{
+		UserExistsFunc: func(ctx context.Context, userID string) (bool, error) {
+			return userID == "owner1" || userID == "owner2", nil
+		},
+	}
+	return usecase.NewPetUsecase(mockRepo, mockCache, nil, users, nil, usecase.PetUsecaseConfig{})
+}
+
+func TestPetUsecase_TransferPetListing_Success(t *testing.T) {
+	var transfers []domain.ListingTransfer
+	uc := newTransferTestUsecase(&transfers)
+
+	pet, err := uc.TransferPetListing(context.Background(), "pet1", "owner2", "owner1", false)
+	if err != nil {
+		t.Fatalf("TransferPetListing() error = %v", err)
+	}
+	if pet.ListedByUserID != "owner2" {
+		t.Errorf("TransferPetListing() listed_by_user_id = %q, want owner2", pet.ListedByUserID)
+	}
+	if len(transfers) != 1 || transfers[0].FromUserID != "owner1" || transfers[0].ToUserID != "owner2" || transfers[0].TransferredBy != "owner1" {
+		t.Errorf("TransferPetListing() recorded %+v, want one transfer owner1 -> owner2 by owner1", transfers)
+	}
+
+	// Admins may transfer listings they don't own.
+	if _, err := uc.TransferPetListing(context.Background(), "pet1", "owner2", "admin1", true); err != nil {
+		t.Errorf("TransferPetListing() by admin error = %v", err)
+	}
+}
+
+func TestPetUsecase_TransferPetListing_NewOwnerNotFound(t *testing.T) {
+	var transfers []domain.ListingTransfer
+	uc := newTransferTestUsecase(&transfers)
+
+	_, err := uc.TransferPetListing(context.Background(), "pet1", "ghost", "owner1", false)
+	if !errors.Is(err, usecase.ErrNewOwnerNotFound) {
+		t.Errorf("TransferPetListing() error = %v, want ErrNewOwnerNotFound", err)
+	}
+	if len(transfers) != 0 {
+		t.Errorf("TransferPetListing() recorded %+v, want no transfer", transfers)
+	}
+}
+
+func TestPetUsecase_TransferPetListing_Unauthorized(t *testing.T) {
+	var transfers []domain.ListingTransfer
+	uc := newTransferTestUsecase(&transfers)
+
+	_, err := uc.TransferPetListing(context.Background(), "pet1", "owner2", "someone-else", false)
+	if !errors.Is(err, usecase.ErrListingTransferForbidden) {
+		t.Errorf("TransferPetListing() error = %v, want ErrListingTransferForbidden", err)
+	}
+	if len(transfers) != 0 {
+		t.Errorf("TransferPetListing() recorded %+v, want no transfer", transfers)
+	}
+}
+
+// TODO: Add more unit tests for other PetUsecase methods:
+// - GetPetByID_Success_FromCache
+// - GetPetByID_Success_FromDB_CacheMiss
+// - GetPetByID_NotFound
+// - UpdatePet_Success
+// - UpdatePet_NotFound
+// - DeletePet_Success
+// - DeletePet_NotFound
+// - ListPets_Success_NoFilters
+// - ListPets_Success_WithFilters
+// - ListPets_Empty
+// - UpdatePetAdoptionStatus_Success_ToAdopted
+// - UpdatePetAdoptionStatus_Success_ToAvailable
+// - UpdatePetAdoptionStatus_PetNotFound
+// - UpdatePetAdoptionStatus_InvalidStatus
+// - UpdatePetAdoptionStatus_MissingAdopterID
+func TestListPetsQuery_ExcludesDeletedUnlessIncluded(t *testing.T) {
+	query := repository.ListPetsQuery(map[string]interface{}{"species": "Dog"})
+	if cond, ok := query["deleted"].(bson.M); !ok || cond["$ne"] != true {
+		t.Errorf("ListPetsQuery() deleted = %v, want $ne true", query["deleted"])
+	}
+
+	withDeleted := repository.ListPetsQuery(map[string]interface{}{"species": "Dog", repository.FilterIncludeDeleted: true})
+	if _, ok := withDeleted["deleted"]; ok || len(withDeleted) != 1 {
+		t.Errorf("ListPetsQuery() with %s = %v, want only the species filter", repository.FilterIncludeDeleted, withDeleted)
+	}
+}
+
+// newSoftDeleteRepoMock returns a MockPetRepository holding pet in memory and soft-deleting it
+// the way the MongoDB repository does.
+func newSoftDeleteRepoMock(pet *domain.Pet) *MockPetRepository {
+	return &MockPetRepository{
+		GetPetByIDFunc: func(ctx context.Context, id string) (*domain.Pet, error) {
+			if id != pet.ID || pet.Deleted {
+				return nil, errors.New("pet not found")
+			}
+			return pet, nil
+		},
+		GetPetByIDIncludingDeletedFunc: func(ctx context.Context, id string) (*domain.Pet, error) {
+			if id != pet.ID {
+				return nil, errors.New("pet not found")
+			}
+			return pet, nil
+		},
+		DeletePetFunc: func(ctx context.Context, id string) error {
+			deletedAt := time.Now().UTC()
+			pet.Deleted, pet.DeletedAt = true, &deletedAt
+			return nil
+		},
+		RestorePetFunc: func(ctx context.Context, id string) (*domain.Pet, error) {
+			pet.Deleted, pet.DeletedAt = false, nil
+			return pet, nil
+		},
+	}
+}
+
+func TestPetHandler_DeletedPetNotFoundUntilRestored(t *testing.T) {
+	mockRepo := newSoftDeleteRepoMock(&domain.Pet{ID: "pet1", Name: "Rex", ListedByUserID: "owner1"})
+	mockCache := &MockPetCache{
+		GetPetFunc: func(ctx context.Context, id string) (*domain.Pet, error) {
+			return nil, fmt.Errorf("pet %w", repository.ErrCacheMiss)
+		},
+		SetPetFunc:    func(ctx context.Context, id string, pet *domain.Pet, expiration time.Duration) error { return nil },
+		DeletePetFunc: func(ctx context.Context, id string) error { return nil },
+	}
+	h := handler.NewPetHandler(usecase.NewPetUsecase(mockRepo, mockCache, nil, nil, nil, usecase.PetUsecaseConfig{}), "")
+	ownerCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-user-id", "owner1"))
+	intruderCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-user-id", "intruder"))
+
+	if _, err := h.DeletePet(ownerCtx, &pb.DeletePetRequest{PetId: "pet1"}); err != nil {
+		t.Fatalf("DeletePet() error = %v", err)
+	}
+	if _, err := h.GetPet(ownerCtx, &pb.GetPetRequest{PetId: "pet1"}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetPet() after delete error = %v, want NotFound", err)
+	}
+	if _, err := h.RestorePet(intruderCtx, &pb.RestorePetRequest{PetId: "pet1"}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("RestorePet() by another user error = %v, want PermissionDenied", err)
+	}
+
+	resp, err := h.RestorePet(ownerCtx, &pb.RestorePetRequest{PetId: "pet1"})
+	if err != nil {
+		t.Fatalf("RestorePet() error = %v", err)
+	}
+	if resp.GetPet().GetDeleted() {
+		t.Error("RestorePet() returned a pet still marked deleted")
+	}
+	if _, err := h.GetPet(ownerCtx, &pb.GetPetRequest{PetId: "pet1"}); err != nil {
+		t.Errorf("GetPet() after restore error = %v", err)
+	}
+	if _, err := h.RestorePet(ownerCtx, &pb.RestorePetRequest{PetId: "pet1"}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("RestorePet() of a pet that is not deleted error = %v, want FailedPrecondition", err)
+	}
+}
+
+func TestPetHandler_ListPets_IncludeDeletedRequiresAdmin(t *testing.T) {
+	var gotFilters map[string]interface{}
+	mockRepo := &MockPetRepository{
+		ListPetsFunc: func(ctx context.Context, page, limit int, filters map[string]interface{}) ([]*domain.Pet, int64, error) {
+			gotFilters = filters
+			return nil, 0, nil
+		},
+	}
+	h := handler.NewPetHandler(usecase.NewPetUsecase(mockRepo, &MockPetCache{}, nil, nil, nil, usecase.PetUsecaseConfig{}), "")
+	includeDeleted := true
+	req := &pb.ListPetsRequest{IncludeDeleted: &includeDeleted}
+
+	userCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-user-id", "user1"))
+	if _, err := h.ListPets(userCtx, req); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("ListPets() with include_deleted as a user error = %v, want PermissionDenied", err)
+	}
+
+	adminCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-user-id", "admin1", "x-user-roles", "admin"))
+	if _, err := h.ListPets(adminCtx, req); err != nil {
+		t.Fatalf("ListPets() with include_deleted as an admin error = %v", err)
+	}
+	if gotFilters[repository.FilterIncludeDeleted] != true {
+		t.Errorf("ListPets() filters = %v, want %s", gotFilters, repository.FilterIncludeDeleted)
+	}
+}
+
+func TestMongoPetRepository_SoftDeleteAndRestore(t *testing.T) {
+	db := testutil.MongoDatabase(t)
+	ctx := context.Background()
+	repo := repository.NewMongoDBPetRepositoryFromClient(ctx, db.Client(), db.Name(), "pets", repository.IndexOptions{EnsureIndexes: true})
+
+	if _, err := repo.CreatePet(ctx, &domain.Pet{ID: "pet1", Name: "Rex", Species: "Dog", AdoptionStatus: domain.StatusAvailable}); err != nil {
+		t.Fatalf("CreatePet() error = %v", err)
+	}
+	if err := repo.DeletePet(ctx, "pet1"); err != nil {
+		t.Fatalf("DeletePet() error = %v", err)
+	}
+
+	if _, err := repo.GetPetByID(ctx, "pet1"); err == nil || err.Error() != "pet not found" {
+		t.Errorf("GetPetByID() after delete error = %v, want pet not found", err)
+	}
+	if _, total, err := repo.ListPets(ctx, 1, 10, map[string]interface{}{}); err != nil || total != 0 {
+		t.Errorf("ListPets() after delete total = %d, %v, want 0", total, err)
+	}
+	deleted, err := repo.GetPetByIDIncludingDeleted(ctx, "pet1")
+	if err != nil || !deleted.Deleted || deleted.DeletedAt == nil {
+		t.Fatalf("GetPetByIDIncludingDeleted() = %+v, %v, want the pet marked deleted", deleted, err)
+	}
+	if err := repo.DeletePet(ctx, "pet1"); err == nil {
+		t.Error("DeletePet() of a deleted pet succeeded, want pet not found for deletion")
+	}
+
+	restored, err := repo.RestorePet(ctx, "pet1")
+	if err != nil {
+		t.Fatalf("RestorePet() error = %v", err)
+	}
+	if restored.Deleted || restored.DeletedAt != nil {
+		t.Errorf("RestorePet() = %+v, want the deleted marks cleared", restored)
+	}
+	if _, total, err := repo.ListPets(ctx, 1, 10, map[string]interface{}{}); err != nil || total != 1 {
+		t.Errorf("ListPets() after restore total = %d, %v, want 1", total, err)
+	}
+}
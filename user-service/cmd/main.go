@@ -2,16 +2,28 @@ package main
 
 import (
 	"context"
+	"crypto/rsa"
+	"errors"
 	"log"
+	"net/http"
+	"os"
 	"time"
 
+	"github.com/zhandarbeks/petstore-final-project/internal/settings"
 	"github.com/zhandarbeks/petstore-final-project/user-service/internal/config"
 	"github.com/zhandarbeks/petstore-final-project/user-service/internal/handler"
+	"github.com/zhandarbeks/petstore-final-project/user-service/internal/metrics"
 	"github.com/zhandarbeks/petstore-final-project/user-service/internal/repository"
 	"github.com/zhandarbeks/petstore-final-project/user-service/internal/server"
 	"github.com/zhandarbeks/petstore-final-project/user-service/internal/usecase"
 )
 
+// MongoDB database and collection used by the service and by the migrate command.
+const (
+	mongoDBName         = "petstore_users"
+	mongoCollectionName = "users"
+)
+
 func main() {
 	cfg, err := config.Load()
 	if err != nil {
@@ -19,11 +31,20 @@ func main() {
 	}
 
 	log.Println("User Service | Configuration loaded.")
+	log.Printf("User Service | Effective settings: %s", settings.Summary(cfg.Settings()))
 	log.Printf("User Service | Server Port: %s", cfg.ServerPort)
 	log.Printf("User Service | MongoDB URI: %s", cfg.MongoURI)
 	log.Printf("User Service | Redis Address: %s, DB: %d", cfg.RedisAddr, cfg.RedisDB)
 	log.Printf("User Service | Token Expiry: %v", cfg.TokenExpiry)
 
+	// "migrate" ensures indexes (and optionally seeds data) and exits instead of serving.
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(cfg, os.Args[2:]); err != nil {
+			log.Fatalf("User Service | FATAL: Migration failed: %v", err)
+		}
+		return
+	}
+
 	mainCtx, cancelMainCtx := context.WithCancel(context.Background())
 	defer cancelMainCtx()
 
@@ -31,7 +52,7 @@ func main() {
 
 	mongoInitCtx, mongoCancel := context.WithTimeout(mainCtx, initTimeout)
 	defer mongoCancel()
-	userMongoRepo, err := repository.NewMongoDBUserRepository(mongoInitCtx, cfg.MongoURI, "petstore_users", "users") // DB name "petstore_users", collection "users"
+	userMongoRepo, err := repository.NewMongoDBUserRepository(mongoInitCtx, cfg.MongoURI, mongoDBName, mongoCollectionName, repository.IndexOptions{EnsureIndexes: cfg.EnsureIndexes})
 	if err != nil {
 		log.Fatalf("FATAL: Failed to initialize MongoDB repository: %v", err)
 	}
@@ -70,19 +91,99 @@ func main() {
 		}()
 	}
 
-	userUsecase := usecase.NewUserUsecase(userMongoRepo, userRedisCache, cfg.JWTSecretKey, cfg.TokenExpiry)
+	signingKey := usecase.HMACSigningKey(cfg.JWTSecretKey)
+	var previousKeys []*rsa.PublicKey
+	if cfg.JWTSigningAlgorithm == "RS256" {
+		signingKey, err = usecase.LoadRSASigningKey(cfg.JWTPrivateKeyFile)
+		if err != nil {
+			log.Fatalf("FATAL: Failed to load JWT_PRIVATE_KEY_FILE: %v", err)
+		}
+		previousKeys, err = usecase.LoadRSAPublicKeys(cfg.JWTPreviousPublicKeyFiles...)
+		if err != nil {
+			log.Fatalf("FATAL: Failed to load JWT_PREVIOUS_PUBLIC_KEY_FILES: %v", err)
+		}
+	}
+	userUsecase := usecase.NewUserUsecaseWithSigningKey(userMongoRepo, userRedisCache, signingKey, cfg.TokenExpiry)
+	userUsecase.SetBcryptCost(cfg.BcryptCost)
+	// Validate tokens (on logout) exactly like the gateway does, so both accept the same ones.
+	userUsecase.SetPreviousVerificationKeys(previousKeys...)
+	userUsecase.SetTokenLeeway(cfg.JWTLeeway)
+	if cfg.RefreshTokenExpiry > 0 {
+		tokenStore, err := repository.NewRedisTokenStore(redisInitCtx, cfg.RedisAddr, cfg.RedisPassword, cfg.RedisDB, "refresh:")
+		if err != nil {
+			log.Fatalf("FATAL: Failed to initialize Redis refresh token store: %v", err)
+		}
+		if c, ok := tokenStore.(interface{ Close() error }); ok {
+			defer c.Close()
+		}
+		userUsecase.SetRefreshTokenStore(tokenStore, cfg.RefreshTokenExpiry)
+	}
+	// The gateway reads the same "revoked:" keys to reject logged-out tokens.
+	tokenBlocklist, err := repository.NewRedisTokenBlocklist(redisInitCtx, cfg.RedisAddr, cfg.RedisPassword, cfg.RedisDB, "revoked:")
+	if err != nil {
+		log.Fatalf("FATAL: Failed to initialize Redis token blocklist: %v", err)
+	}
+	if c, ok := tokenBlocklist.(interface{ Close() error }); ok {
+		defer c.Close()
+	}
+	userUsecase.SetTokenBlocklist(tokenBlocklist)
+	log.Printf("User Service | Signing access tokens with %s.", signingKey.Method.Alg())
 	log.Println("User Service | Usecase layer initialized.")
 
 	userGRPCHandler := handler.NewUserHandler(userUsecase)
 	log.Println("User Service | gRPC handler initialized.")
 
-	grpcServer, err := server.NewGRPCServer(cfg.ServerPort, userGRPCHandler)
+	grpcServer, err := server.NewGRPCServer(cfg.ServerPort, userGRPCHandler, cfg.MaxInFlightRequests)
 	if err != nil {
 		log.Fatalf("FATAL: Failed to create gRPC server: %v", err)
 	}
 
+	// Publish the RS256 public key so the gateway and third parties can validate tokens
+	var jwksServer *http.Server
+	if cfg.JWTSigningAlgorithm == "RS256" && cfg.JWKSHTTPPort != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/jwks.json", server.NewJWKSHandler(signingKey, previousKeys...))
+		jwksServer = &http.Server{Addr: cfg.JWKSHTTPPort, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+		go func() {
+			log.Printf("User Service | JWKS HTTP server listening on %s", cfg.JWKSHTTPPort)
+			if err := jwksServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Printf("User Service | JWKS HTTP server error: %v", err)
+			}
+		}()
+	}
+
+	// Publish the cache hit/miss counters for scraping
+	var metricsServer *http.Server
+	if cfg.MetricsHTTPPort != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Handler(metrics.CacheHits, metrics.CacheMisses))
+		metricsServer = &http.Server{Addr: cfg.MetricsHTTPPort, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+		go func() {
+			log.Printf("User Service | Metrics HTTP server listening on %s", cfg.MetricsHTTPPort)
+			if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Printf("User Service | Metrics HTTP server error: %v", err)
+			}
+		}()
+	}
+
 	log.Println("User Service | Starting up...")
 	grpcServer.RunWithGracefulShutdown()
 
+	if jwksServer != nil {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := jwksServer.Shutdown(shutdownCtx); err != nil {
+			log.Printf("User Service | Error shutting down JWKS HTTP server: %v", err)
+		}
+		cancel()
+	}
+
+	if metricsServer != nil {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := metricsServer.Shutdown(shutdownCtx); err != nil {
+			log.Printf("User Service | Error shutting down metrics HTTP server: %v", err)
+		}
+		cancel()
+	}
+
 	log.Println("User Service | Shut down gracefully.")
 }
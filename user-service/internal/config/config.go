@@ -4,9 +4,12 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv" // For loading .env files (optional)
+	"golang.org/x/crypto/bcrypt"
+	"github.com/zhandarbeks/petstore-final-project/internal/settings"
 )
 
 // Config holds all configuration for the user-service
@@ -17,7 +20,34 @@ type Config struct {
 	RedisPassword string        // Redis password (if any, leave empty if none)
 	RedisDB       int           // Redis database number
 	JWTSecretKey  string        // Secret key for signing JWT tokens
+	JWTSigningAlgorithm string  // "HS256" (JWTSecretKey) or "RS256" (JWTPrivateKeyFile)
+	JWTPrivateKeyFile   string  // PEM file with the RSA private key used for RS256
+	JWTPreviousPublicKeyFiles []string // PEM files with the public keys of rotated-out RS256 keys, still published in the JWKS
+	JWKSHTTPPort        string  // Port of the HTTP server publishing /jwks.json for RS256 keys (e.g., ":8083"); empty disables it
+	MetricsHTTPPort     string  // Port of the HTTP server publishing /metrics (e.g., ":9090"); empty disables it
 	TokenExpiry   time.Duration // Duration for token expiry
+	JWTLeeway     time.Duration // Clock skew tolerated on exp/nbf when validating tokens; should match the gateway's JWT_LEEWAY
+	RefreshTokenExpiry time.Duration // How long refresh tokens are valid; 0 disables refresh tokens
+	MaxInFlightRequests int     // Max concurrent gRPC requests before rejecting with ResourceExhausted (0 = unlimited)
+	EnsureIndexes       bool // Create MongoDB indexes on startup; disable when migrations manage them
+	BcryptCost          int  // bcrypt cost for hashing new passwords (clamped to bcrypt.MinCost..bcrypt.MaxCost)
+}
+
+// Settings returns the feature flags and key tunables in effect. Secrets and connection strings are left out.
+func (c *Config) Settings() []settings.Setting {
+	return []settings.Setting{
+		{Name: "token_expiry", Value: c.TokenExpiry.String()},
+		{Name: "refresh_token_expiry", Value: c.RefreshTokenExpiry.String()},
+		{Name: "jwt_leeway", Value: c.JWTLeeway.String()},
+		{Name: "jwt_signing_algorithm", Value: c.JWTSigningAlgorithm},
+		{Name: "jwks_http_port", Value: c.JWKSHTTPPort},
+		{Name: "metrics_http_port", Value: c.MetricsHTTPPort},
+		{Name: "jwt_previous_keys", Value: strconv.Itoa(len(c.JWTPreviousPublicKeyFiles))},
+		{Name: "ensure_indexes", Value: strconv.FormatBool(c.EnsureIndexes)},
+		{Name: "bcrypt_cost", Value: strconv.Itoa(c.BcryptCost)},
+		{Name: "max_in_flight_requests", Value: strconv.Itoa(c.MaxInFlightRequests)},
+		{Name: "redis_db", Value: strconv.Itoa(c.RedisDB)},
+	}
 }
 
 // Load loads configuration. It first attempts to load from a .env file (if present),
@@ -44,6 +74,16 @@ func Load() (*Config, error) {
 		RedisAddr:     getEnv("REDIS_ADDR", "localhost:6379"),                 // Default for local, Docker will override
 		RedisPassword: getEnv("REDIS_PASSWORD", ""),                           // Default to no password
 		JWTSecretKey:  getEnv("JWT_SECRET_KEY", "your-very-secret-and-long-key-!@#$%^&*()_dev"), // !! CHANGE THIS !!
+		JWTSigningAlgorithm: getEnv("JWT_SIGNING_ALGORITHM", "HS256"),
+		JWTPrivateKeyFile:   getEnv("JWT_PRIVATE_KEY_FILE", ""),
+		JWKSHTTPPort:        getEnv("JWKS_HTTP_PORT", ":8083"),
+		MetricsHTTPPort:     getEnv("METRICS_HTTP_PORT", ":9090"),
+	}
+
+	for _, file := range strings.Split(getEnv("JWT_PREVIOUS_PUBLIC_KEY_FILES", ""), ",") {
+		if file = strings.TrimSpace(file); file != "" {
+			cfg.JWTPreviousPublicKeyFiles = append(cfg.JWTPreviousPublicKeyFiles, file)
+		}
 	}
 
 	redisDBStr := getEnv("REDIS_DB", "0")
@@ -64,6 +104,54 @@ func Load() (*Config, error) {
 		cfg.TokenExpiry = time.Duration(tokenExpiryMinutes) * time.Minute
 	}
 
+	jwtLeewayStr := getEnv("JWT_LEEWAY", "30s")
+	jwtLeeway, err := time.ParseDuration(jwtLeewayStr)
+	if err != nil || jwtLeeway < 0 {
+		log.Printf("Warning: Invalid JWT_LEEWAY value: '%s'. Using default 30s.", jwtLeewayStr)
+		jwtLeeway = 30 * time.Second
+	}
+	cfg.JWTLeeway = jwtLeeway
+
+	refreshTokenExpiryStr := getEnv("REFRESH_TOKEN_EXPIRY_HOURS", "720") // Default to 30 days
+	refreshTokenExpiryHours, err := strconv.Atoi(refreshTokenExpiryStr)
+	if err != nil || refreshTokenExpiryHours < 0 {
+		log.Printf("Warning: Invalid REFRESH_TOKEN_EXPIRY_HOURS value: '%s'. Using default 720 hours. Error: %v", refreshTokenExpiryStr, err)
+		refreshTokenExpiryHours = 720
+	}
+	cfg.RefreshTokenExpiry = time.Duration(refreshTokenExpiryHours) * time.Hour
+
+	maxInFlightStr := getEnv("MAX_IN_FLIGHT_REQUESTS", "100")
+	maxInFlightVal, err := strconv.Atoi(maxInFlightStr)
+	if err != nil || maxInFlightVal < 0 {
+		log.Printf("Warning: Invalid MAX_IN_FLIGHT_REQUESTS value: '%s'. Using default 100. Error: %v", maxInFlightStr, err)
+		cfg.MaxInFlightRequests = 100
+	} else {
+		cfg.MaxInFlightRequests = maxInFlightVal
+	}
+
+	ensureIndexesStr := getEnv("ENSURE_INDEXES", "true")
+	ensureIndexesVal, err := strconv.ParseBool(ensureIndexesStr)
+	if err != nil {
+		log.Printf("Warning: Invalid ENSURE_INDEXES value: '%s'. Using default true. Error: %v", ensureIndexesStr, err)
+		ensureIndexesVal = true
+	}
+	cfg.EnsureIndexes = ensureIndexesVal
+
+	bcryptCostStr := getEnv("BCRYPT_COST", strconv.Itoa(bcrypt.DefaultCost))
+	bcryptCostVal, err := strconv.Atoi(bcryptCostStr)
+	if err != nil {
+		log.Printf("Warning: Invalid BCRYPT_COST value: '%s'. Using default %d. Error: %v", bcryptCostStr, bcrypt.DefaultCost, err)
+		bcryptCostVal = bcrypt.DefaultCost
+	} else if bcryptCostVal < bcrypt.MinCost || bcryptCostVal > bcrypt.MaxCost {
+		clamped := bcrypt.MinCost
+		if bcryptCostVal > bcrypt.MaxCost {
+			clamped = bcrypt.MaxCost
+		}
+		log.Printf("Warning: BCRYPT_COST %d is outside %d-%d. Using %d.", bcryptCostVal, bcrypt.MinCost, bcrypt.MaxCost, clamped)
+		bcryptCostVal = clamped
+	}
+	cfg.BcryptCost = bcryptCostVal
+
 	// Critical validations
 	if cfg.MongoURI == "" {
 		log.Fatal("FATAL: MONGO_URI environment variable is required and was not found or set.")
@@ -71,7 +159,14 @@ func Load() (*Config, error) {
 	if cfg.ServerPort == "" {
 		log.Fatal("FATAL: USER_SERVICE_PORT environment variable is required and was not found or set.")
 	}
-	if cfg.JWTSecretKey == "your-very-secret-and-long-key-!@#$%^&*()_dev" || len(cfg.JWTSecretKey) < 32 {
+	if cfg.JWTSigningAlgorithm != "HS256" && cfg.JWTSigningAlgorithm != "RS256" {
+		log.Printf("Warning: Invalid JWT_SIGNING_ALGORITHM value: '%s'. Using default HS256.", cfg.JWTSigningAlgorithm)
+		cfg.JWTSigningAlgorithm = "HS256"
+	}
+	if cfg.JWTSigningAlgorithm == "RS256" && cfg.JWTPrivateKeyFile == "" {
+		log.Fatal("FATAL: JWT_PRIVATE_KEY_FILE is required when JWT_SIGNING_ALGORITHM is RS256.")
+	}
+	if cfg.JWTSigningAlgorithm == "HS256" && (cfg.JWTSecretKey == "your-very-secret-and-long-key-!@#$%^&*()_dev" || len(cfg.JWTSecretKey) < 32) {
 		log.Println("WARNING: JWT_SECRET_KEY is using a default or is too short. Please set a strong, unique key via environment variable for production.")
 	}
 
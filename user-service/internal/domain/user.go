@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"strings"
 	"time"
 
 	"golang.org/x/crypto/bcrypt" // For password hashing
@@ -14,20 +15,83 @@ type User struct {
 	Email          string    `bson:"email" json:"email"`
 	HashedPassword string    `bson:"hashed_password" json:"-"` // Avoid exposing this in JSON responses directly
 	FullName       string    `bson:"full_name" json:"full_name"`
+	Role           string    `bson:"role,omitempty" json:"role,omitempty"` // RoleUser, RoleTrusted, RoleShelter or RoleAdmin; empty (users created before roles) means RoleUser
+	Locale         string    `bson:"locale,omitempty" json:"locale,omitempty"` // Language for emails; empty means DefaultLocale
 	CreatedAt      time.Time `bson:"created_at" json:"created_at"`
 	UpdatedAt      time.Time `bson:"updated_at" json:"updated_at"`
+	FavoritePetIDs []string  `bson:"favorite_pet_ids,omitempty" json:"favorite_pet_ids,omitempty"` // Pets the user has favorited, in the order they were added
+	Version        int       `bson:"version" json:"version"`                                       // Incremented on every profile update, used for optimistic locking
 	// DeletedAt    *time.Time `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"` // For soft deletes, optional
 }
 
+// Roles a user can have. Every new user is a RoleUser; trusted users, shelters and admins are
+// promoted in the database. The role goes into the access token's "rol" claim, which the gateway
+// forwards to the services.
+const (
+	RoleUser    = "user"
+	RoleTrusted = "trusted" // Pre-vetted adopter whose applications the adoption-service may auto-approve
+	RoleShelter = "shelter" // Shelter account that may list pets as PENDING_ADOPTION in the pet-service
+	RoleAdmin   = "admin"
+)
+
+// EffectiveRole returns the user's role, treating users stored before roles existed as RoleUser.
+func (u *User) EffectiveRole() string {
+	if u.Role == "" {
+		return RoleUser
+	}
+	return u.Role
+}
+
+// DefaultLocale is the email language for users who have not chosen one.
+const DefaultLocale = "en"
+
+// SupportedLocales lists the languages the notification emails are available in.
+var SupportedLocales = []string{"en", "ru"}
+
+// NormalizeLocale lowercases a locale and reduces it to its language, so "ru-RU" becomes "ru".
+// It reports false if the language is not supported. An empty locale is valid and means DefaultLocale.
+func NormalizeLocale(locale string) (string, bool) {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if i := strings.IndexAny(locale, "-_"); i >= 0 {
+		locale = locale[:i]
+	}
+	if locale == "" {
+		return "", true
+	}
+	for _, supported := range SupportedLocales {
+		if locale == supported {
+			return locale, true
+		}
+	}
+	return "", false
+}
+
 // HashPassword generates a bcrypt hash of the password.
 // It's a good practice to use a moderate cost for bcrypt.
 func HashPassword(password string) (string, error) {
 	// bcrypt.DefaultCost is 10, which is generally a good balance.
 	// You can increase the cost for higher security, but it will be slower.
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	return HashPasswordWithCost(password, bcrypt.DefaultCost)
+}
+
+// HashPasswordWithCost generates a bcrypt hash of the password with the given cost,
+// clamped to the range bcrypt accepts.
+func HashPasswordWithCost(password string, cost int) (string, error) {
+	bytes, err := bcrypt.GenerateFromPassword([]byte(password), ClampBcryptCost(cost))
 	return string(bytes), err
 }
 
+// ClampBcryptCost returns cost limited to bcrypt.MinCost..bcrypt.MaxCost.
+func ClampBcryptCost(cost int) int {
+	if cost < bcrypt.MinCost {
+		return bcrypt.MinCost
+	}
+	if cost > bcrypt.MaxCost {
+		return bcrypt.MaxCost
+	}
+	return cost
+}
+
 // CheckPasswordHash compares a plain-text password with a stored bcrypt hash.
 // Returns true if the password matches the hash, false otherwise.
 func CheckPasswordHash(password, hash string) bool {
@@ -40,9 +104,28 @@ func CheckPasswordHash(password, hash string) bool {
 func (u *User) PrepareForCreate() {
 	u.CreatedAt = time.Now().UTC()
 	u.UpdatedAt = time.Now().UTC()
+	if u.Role == "" {
+		u.Role = RoleUser
+	}
 	// Any other default setting or validation before creation
 }
 
+// AnonymizedEmailDomain is the email domain of anonymized users. ".invalid" never resolves, so
+// nothing is ever delivered to their placeholder addresses.
+const AnonymizedEmailDomain = "users.invalid"
+
+// AnonymizedIdentity returns the placeholder username and email that replace those of an
+// anonymized user. They are derived from the user's ID, so they stay unique.
+func AnonymizedIdentity(id string) (username, email string) {
+	return "deleted-" + id, "deleted-" + id + "@" + AnonymizedEmailDomain
+}
+
+// IsAnonymized reports whether the user's personal data was replaced by AnonymizedIdentity.
+func (u *User) IsAnonymized() bool {
+	_, email := AnonymizedIdentity(u.ID)
+	return u.Email == email
+}
+
 // BeforeUpdate (concept)
 func (u *User) PrepareForUpdate() {
 	u.UpdatedAt = time.Now().UTC()
@@ -3,14 +3,16 @@ package handler
 import (
 	"context"
 	"errors"
-	"fmt" // Added import for fmt
 	"log"
+	"strings"
 	"time" // Added import for time
 
 	"github.com/zhandarbeks/petstore-final-project/user-service/internal/domain"   // Adjust import path
+	"github.com/zhandarbeks/petstore-final-project/user-service/internal/repository"
 	"github.com/zhandarbeks/petstore-final-project/user-service/internal/usecase" // Adjust import path
 	pb "github.com/zhandarbeks/petstore-final-project/genprotos/user"            // Adjust import path to your generated protos
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	// "google.golang.org/protobuf/types/known/timestamppb" // If using timestamppb in your domain/proto
@@ -40,6 +42,7 @@ func domainUserToPbUser(du *domain.User) *pb.User {
 		Username:  du.Username,
 		Email:     du.Email,
 		FullName:  du.FullName,
+		Locale:    du.Locale,
 		CreatedAt: du.CreatedAt.Format(time.RFC3339), // Or use timestamppb.New(du.CreatedAt)
 		UpdatedAt: du.UpdatedAt.Format(time.RFC3339), // Or use timestamppb.New(du.UpdatedAt)
 	}
@@ -59,31 +62,45 @@ func (h *UserHandler) RegisterUser(ctx context.Context, req *pb.RegisterUserRequ
 	if err != nil {
 		log.Printf("Error during RegisterUser usecase call for email %s: %v", req.GetEmail(), err)
 		// Map domain-specific errors to gRPC status codes
-		if errors.Is(err, errors.New("user with this email already exists")) { // Example of specific error check
-			return nil, status.Errorf(codes.AlreadyExists, err.Error())
+		var dupErr *repository.DuplicateUserError
+		if errors.As(err, &dupErr) {
+			return nil, duplicateUserStatus(dupErr)
 		}
 		if errors.Is(err, errors.New("username, email, password, and full name are required")) {
 			return nil, status.Errorf(codes.InvalidArgument, err.Error())
 		}
-		// Check for the specific error from usecase regarding token generation failure
-		// The error message from usecase is "user registered, but token generation failed: <original_token_error>"
-		// We need to check if the original_token_error is part of the error string.
-		// A more robust way would be to define custom error types.
-		// For now, we'll assume the usecase returns the user even if token generation fails,
-		// and the error indicates this specific scenario.
-		if createdUser != nil && err != nil && (err.Error() == fmt.Sprintf("user registered, but token generation failed: %v", errors.Unwrap(err)) || errors.Is(err, errors.New("user registered, but token generation failed"))) {
-			log.Printf("RegisterUser: User %s created, but token generation failed: %v", createdUser.Email, err)
-			// Return the user as the gRPC response for RegisterUser only contains User.
-			// The client should be aware that login is needed to get a token.
-			return &pb.UserResponse{User: domainUserToPbUser(createdUser)}, nil
+		var tokenErr *usecase.TokenGenerationError
+		if errors.As(err, &tokenErr) {
+			log.Printf("RegisterUser: User %s created, but token generation failed: %v", tokenErr.User.Email, tokenErr.Err)
+			// The account exists; the client gets it back and is told to log in for a token.
+			return &pb.UserResponse{User: domainUserToPbUser(tokenErr.User), LoginRequired: true}, nil
 		}
-		return nil, status.Errorf(codes.Internal, "Failed to register user: %v", err)
+		return nil, InternalError(ctx, err, "Failed to register user")
 	}
 
 	log.Printf("User registered successfully via gRPC: %s", createdUser.Email)
 	return &pb.UserResponse{User: domainUserToPbUser(createdUser)}, nil
 }
 
+// duplicateUserStatus maps a duplicate-user error to AlreadyExists. When the conflicting field is
+// known it is attached as a BadRequest field violation, so clients can point at the right input.
+func duplicateUserStatus(dupErr *repository.DuplicateUserError) error {
+	st := status.New(codes.AlreadyExists, dupErr.Error())
+	if dupErr.Field == "" {
+		return st.Err()
+	}
+	detailed, err := st.WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{Field: dupErr.Field, Description: dupErr.Error()},
+		},
+	})
+	if err != nil {
+		log.Printf("Error attaching duplicate field detail to status: %v", err)
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
 // LoginUser handles the gRPC request for user login.
 func (h *UserHandler) LoginUser(ctx context.Context, req *pb.LoginUserRequest) (*pb.LoginUserResponse, error) {
 	log.Printf("gRPC LoginUser request received for email: %s", req.GetEmail())
@@ -99,16 +116,61 @@ func (h *UserHandler) LoginUser(ctx context.Context, req *pb.LoginUserRequest) (
 		if err.Error() == "invalid email or password" {
 			return nil, status.Errorf(codes.Unauthenticated, err.Error())
 		}
-		return nil, status.Errorf(codes.Internal, "Login failed: %v", err)
+		return nil, InternalError(ctx, err, "Login failed")
+	}
+
+	refreshToken, err := h.usecase.IssueRefreshToken(ctx, user.ID)
+	if err != nil {
+		// The access token is valid; the client just has to log in again once it expires.
+		log.Printf("Warning: Could not issue refresh token for user %s: %v", user.ID, err)
+		refreshToken = ""
 	}
 
 	log.Printf("User logged in successfully via gRPC: %s", user.Email)
 	return &pb.LoginUserResponse{
-		User:        domainUserToPbUser(user),
-		AccessToken: token,
+		User:         domainUserToPbUser(user),
+		AccessToken:  token,
+		RefreshToken: refreshToken,
 	}, nil
 }
 
+// RefreshToken handles the gRPC request to exchange a refresh token for a new access token.
+func (h *UserHandler) RefreshToken(ctx context.Context, req *pb.RefreshTokenRequest) (*pb.RefreshTokenResponse, error) {
+	if req.GetRefreshToken() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Refresh token is required")
+	}
+
+	accessToken, refreshToken, err := h.usecase.RefreshToken(ctx, req.GetRefreshToken())
+	if err != nil {
+		log.Printf("Error during RefreshToken usecase call: %v", err)
+		if errors.Is(err, usecase.ErrInvalidRefreshToken) {
+			return nil, status.Errorf(codes.Unauthenticated, err.Error())
+		}
+		return nil, InternalError(ctx, err, "Token refresh failed")
+	}
+	return &pb.RefreshTokenResponse{AccessToken: accessToken, RefreshToken: refreshToken}, nil
+}
+
+// LogoutUser handles the gRPC request to revoke an access token, and optionally a refresh token.
+func (h *UserHandler) LogoutUser(ctx context.Context, req *pb.LogoutRequest) (*pb.EmptyResponse, error) {
+	if req.GetAccessToken() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Access token is required")
+	}
+
+	err := h.usecase.LogoutUser(ctx, req.GetAccessToken(), req.GetRefreshToken())
+	if err != nil {
+		log.Printf("Error during LogoutUser usecase call: %v", err)
+		switch {
+		case errors.Is(err, usecase.ErrInvalidAccessToken):
+			return nil, status.Errorf(codes.Unauthenticated, err.Error())
+		case errors.Is(err, usecase.ErrLogoutUnavailable):
+			return nil, status.Errorf(codes.Unimplemented, err.Error())
+		}
+		return nil, InternalError(ctx, err, "Logout failed")
+	}
+	return &pb.EmptyResponse{}, nil
+}
+
 // GetUser handles the gRPC request to retrieve a user by ID.
 func (h *UserHandler) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.UserResponse, error) {
 	log.Printf("gRPC GetUser request received for ID: %s", req.GetUserId())
@@ -127,7 +189,7 @@ func (h *UserHandler) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.
 		if err.Error() == "invalid user ID format" {
 			return nil, status.Errorf(codes.InvalidArgument, "Invalid user ID format")
 		}
-		return nil, status.Errorf(codes.Internal, "Failed to get user: %v", err)
+		return nil, InternalError(ctx, err, "Failed to get user")
 	}
 
 	log.Printf("User retrieved successfully via gRPC: %s", user.Email)
@@ -159,22 +221,26 @@ func (h *UserHandler) UpdateUserProfile(ctx context.Context, req *pb.UpdateUserP
 		fullNamePtr = &val
 	}
 
-	if usernamePtr == nil && fullNamePtr == nil {
-		log.Println("UpdateUserProfile: No fields provided for update")
-		return nil, status.Errorf(codes.InvalidArgument, "At least one field (username or full name) must be provided for update")
-	}
-
-
-	updatedUser, err := h.usecase.UpdateUserProfile(ctx, req.GetUserId(), usernamePtr, fullNamePtr)
+	// Locale is optional in the proto, so an explicitly empty value resets it to the default.
+	updatedUser, err := h.usecase.UpdateUserProfile(ctx, req.GetUserId(), usernamePtr, fullNamePtr, req.Locale)
 	if err != nil {
 		log.Printf("Error during UpdateUserProfile usecase call for ID %s: %v", req.GetUserId(), err)
+		if errors.Is(err, usecase.ErrNoFieldsToUpdate) {
+			return nil, status.Error(codes.InvalidArgument, "At least one field (username, full name or locale) must be provided for update")
+		}
+		if errors.Is(err, usecase.ErrUnsupportedLocale) {
+			return nil, status.Errorf(codes.InvalidArgument, "Unsupported locale %q, supported: %s", req.GetLocale(), strings.Join(domain.SupportedLocales, ", "))
+		}
+		if errors.Is(err, repository.ErrConcurrentModification) {
+			return nil, status.Errorf(codes.Aborted, "Concurrent modification: the profile was updated by another request, reload and try again")
+		}
 		if err.Error() == "user not found for update" { // Match error from usecase
 			return nil, status.Errorf(codes.NotFound, "User not found for update")
 		}
 		if err.Error() == "invalid user ID format for update" { // Match error from usecase
 			return nil, status.Errorf(codes.InvalidArgument, "Invalid user ID format")
 		}
-		return nil, status.Errorf(codes.Internal, "Failed to update user profile: %v", err)
+		return nil, InternalError(ctx, err, "Failed to update user profile")
 	}
 
 	log.Printf("User profile updated successfully via gRPC for ID: %s", updatedUser.ID)
@@ -199,9 +265,79 @@ func (h *UserHandler) DeleteUser(ctx context.Context, req *pb.DeleteUserRequest)
 		if err.Error() == "invalid user ID format for delete" { // Match error from usecase
 			return nil, status.Errorf(codes.InvalidArgument, "Invalid user ID format")
 		}
-		return nil, status.Errorf(codes.Internal, "Failed to delete user: %v", err)
+		return nil, InternalError(ctx, err, "Failed to delete user")
 	}
 
 	log.Printf("User deleted successfully via gRPC: ID %s", req.GetUserId())
 	return &pb.EmptyResponse{}, nil
 }
+
+// AnonymizeUser handles the gRPC request to anonymize a user's personal data.
+func (h *UserHandler) AnonymizeUser(ctx context.Context, req *pb.AnonymizeUserRequest) (*pb.UserResponse, error) {
+	log.Printf("gRPC AnonymizeUser request received for ID: %s", req.GetUserId())
+
+	if req.GetUserId() == "" {
+		log.Println("AnonymizeUser: User ID is required")
+		return nil, status.Errorf(codes.InvalidArgument, "User ID is required")
+	}
+
+	user, err := h.usecase.AnonymizeUser(ctx, req.GetUserId())
+	if err != nil {
+		log.Printf("Error during AnonymizeUser usecase call for ID %s: %v", req.GetUserId(), err)
+		if err.Error() == "user not found" { // Match error from repository
+			return nil, status.Errorf(codes.NotFound, "User not found")
+		}
+		return nil, InternalError(ctx, err, "Failed to anonymize user")
+	}
+
+	log.Printf("User anonymized successfully via gRPC: ID %s", user.ID)
+	return &pb.UserResponse{User: domainUserToPbUser(user)}, nil
+}
+
+// favoritesErrorToStatus maps favorites usecase errors to gRPC status errors.
+func favoritesErrorToStatus(ctx context.Context, err error) error {
+	switch err.Error() {
+	case "user not found":
+		return status.Errorf(codes.NotFound, "User not found")
+	case "user ID and pet ID are required", "user ID is required":
+		return status.Errorf(codes.InvalidArgument, err.Error())
+	default:
+		return InternalError(ctx, err, "Failed to update favorites")
+	}
+}
+
+// AddFavoritePet handles the gRPC request to add a pet to a user's favorites.
+func (h *UserHandler) AddFavoritePet(ctx context.Context, req *pb.FavoritePetRequest) (*pb.FavoritePetsResponse, error) {
+	log.Printf("gRPC AddFavoritePet request received for user %s, pet %s", req.GetUserId(), req.GetPetId())
+
+	favorites, err := h.usecase.AddFavoritePet(ctx, req.GetUserId(), req.GetPetId())
+	if err != nil {
+		log.Printf("Error during AddFavoritePet usecase call for user %s: %v", req.GetUserId(), err)
+		return nil, favoritesErrorToStatus(ctx, err)
+	}
+	return &pb.FavoritePetsResponse{PetIds: favorites}, nil
+}
+
+// RemoveFavoritePet handles the gRPC request to remove a pet from a user's favorites.
+func (h *UserHandler) RemoveFavoritePet(ctx context.Context, req *pb.FavoritePetRequest) (*pb.FavoritePetsResponse, error) {
+	log.Printf("gRPC RemoveFavoritePet request received for user %s, pet %s", req.GetUserId(), req.GetPetId())
+
+	favorites, err := h.usecase.RemoveFavoritePet(ctx, req.GetUserId(), req.GetPetId())
+	if err != nil {
+		log.Printf("Error during RemoveFavoritePet usecase call for user %s: %v", req.GetUserId(), err)
+		return nil, favoritesErrorToStatus(ctx, err)
+	}
+	return &pb.FavoritePetsResponse{PetIds: favorites}, nil
+}
+
+// ListFavoritePets handles the gRPC request to list a user's favorite pet IDs.
+func (h *UserHandler) ListFavoritePets(ctx context.Context, req *pb.ListFavoritePetsRequest) (*pb.FavoritePetsResponse, error) {
+	log.Printf("gRPC ListFavoritePets request received for user %s", req.GetUserId())
+
+	favorites, err := h.usecase.ListFavoritePets(ctx, req.GetUserId())
+	if err != nil {
+		log.Printf("Error during ListFavoritePets usecase call for user %s: %v", req.GetUserId(), err)
+		return nil, favoritesErrorToStatus(ctx, err)
+	}
+	return &pb.FavoritePetsResponse{PetIds: favorites}, nil
+}
@@ -0,0 +1,21 @@
+// Package migrate prepares the users database ahead of deployment by ensuring the collection's
+// indexes, so running instances can start with ENSURE_INDEXES=false.
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/zhandarbeks/petstore-final-project/user-service/internal/repository"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Run ensures the indexes of the users collection. There is no reference data to seed.
+func Run(ctx context.Context, db *mongo.Database, collectionName string) error {
+	if err := repository.EnsureIndexes(ctx, db.Collection(collectionName).Indexes()); err != nil {
+		return fmt.Errorf("ensure indexes on %s: %w", collectionName, err)
+	}
+	log.Printf("Migrate: indexes ensured for collection %s", collectionName)
+	return nil
+}
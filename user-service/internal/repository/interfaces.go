@@ -2,18 +2,59 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/zhandarbeks/petstore-final-project/user-service/internal/domain" // Adjust import path as per your module
 )
 
+// ErrConcurrentModification is returned by UpdateUser when the user was changed by someone else
+// since it was read (its version no longer matches).
+var ErrConcurrentModification = errors.New("concurrent modification")
+
+// ErrUserAlreadyExists is matched (with errors.Is) by the *DuplicateUserError that CreateUser
+// returns when the email or username is already taken.
+var ErrUserAlreadyExists = errors.New("user already exists")
+
+// DuplicateUserError reports which unique field a new user conflicted on. Field is
+// DuplicateFieldEmail or DuplicateFieldUsername, or empty if the violated index is unknown.
+type DuplicateUserError struct {
+	Field string
+}
+
+// Fields whose unique indexes CreateUser can report as violated.
+const (
+	DuplicateFieldEmail    = "email"
+	DuplicateFieldUsername = "username"
+)
+
+func (e *DuplicateUserError) Error() string {
+	if e.Field == "" {
+		return "user with this email or username already exists"
+	}
+	return fmt.Sprintf("user with this %s already exists", e.Field)
+}
+
+// Is makes errors.Is(err, ErrUserAlreadyExists) match.
+func (e *DuplicateUserError) Is(target error) bool {
+	return target == ErrUserAlreadyExists
+}
+
+// ErrCacheMiss is returned by UserCache.GetUser when the user is not cached. It is an expected
+// outcome, not a failure: callers fall through to the repository.
+var ErrCacheMiss = errors.New("user not found in cache")
+
 // UserRepository defines the interface for database operations related to users.
 type UserRepository interface {
 	CreateUser(ctx context.Context, user *domain.User) (*domain.User, error)
 	GetUserByID(ctx context.Context, id string) (*domain.User, error)
 	GetUserByEmail(ctx context.Context, email string) (*domain.User, error)
-	UpdateUser(ctx context.Context, user *domain.User) (*domain.User, error)
+	UpdateUser(ctx context.Context, user *domain.User) (*domain.User, error) // Only succeeds if user.Version is current; increments it
 	DeleteUser(ctx context.Context, id string) error
+	AddFavoritePet(ctx context.Context, userID, petID string) ([]string, error)    // Returns the updated favorite pet IDs
+	RemoveFavoritePet(ctx context.Context, userID, petID string) ([]string, error) // Returns the updated favorite pet IDs
+	AnonymizeUser(ctx context.Context, id string) (*domain.User, error)            // Replaces personal data with placeholders and clears the password
 	// ListUsers(ctx context.Context, page, limit int) ([]*domain.User, int64, error) // Example for listing users
 }
 
@@ -26,6 +67,26 @@ type UserCache interface {
 	DeleteUser(ctx context.Context, id string) error
 }
 
+// ErrRefreshTokenNotFound is returned by TokenStore.ConsumeRefreshToken when the token is unknown,
+// expired, revoked or was already used.
+var ErrRefreshTokenNotFound = errors.New("refresh token not found")
+
+// TokenStore keeps refresh tokens, each under an opaque ID, until they expire. A token can be
+// consumed only once, which is what makes rotation safe.
+type TokenStore interface {
+	SaveRefreshToken(ctx context.Context, tokenID, userID string, expiration time.Duration) error
+	GetRefreshTokenUser(ctx context.Context, tokenID string) (string, error) // Returns the token's user ID and keeps the token
+	ConsumeRefreshToken(ctx context.Context, tokenID string) (string, error) // Removes the token and returns its user ID
+	RevokeUserRefreshTokens(ctx context.Context, userID string) error
+}
+
+// TokenBlocklist keeps the IDs ("jti" claims) of access tokens revoked before they expire, e.g.
+// on logout. An entry only has to outlive its token, so it expires with it.
+type TokenBlocklist interface {
+	BlockToken(ctx context.Context, tokenID string, expiration time.Duration) error
+	IsTokenBlocked(ctx context.Context, tokenID string) (bool, error)
+}
+
 // You might also define an interface that combines both direct DB access and caching logic,
 // or use a decorator pattern where the caching repository wraps the database repository.
 // For instance:
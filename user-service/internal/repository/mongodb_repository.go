@@ -4,7 +4,8 @@ import (
 	"context"
 	"errors"
 	"log"
-	// "time" // No longer needed here
+	"strings"
+	"time"
 
 	"github.com/zhandarbeks/petstore-final-project/user-service/internal/domain" // Adjust import path
 	"go.mongodb.org/mongo-driver/bson"
@@ -20,8 +21,38 @@ type mongoUserRepository struct {
 	collection *mongo.Collection
 }
 
+// IndexCreator creates indexes on the users collection. mongo.IndexView satisfies it;
+// tests substitute their own to observe index creation.
+type IndexCreator interface {
+	CreateMany(ctx context.Context, models []mongo.IndexModel, opts ...*options.CreateIndexesOptions) ([]string, error)
+}
+
+// IndexOptions controls index creation when the repository is constructed.
+type IndexOptions struct {
+	EnsureIndexes bool         // Create indexes on startup; disable when migrations manage them or on read-only replicas
+	Creator       IndexCreator // Nil uses the collection's index view
+}
+
+// EnsureIndexes creates the users collection's indexes. It is shared by repository startup and
+// the migrate command so both use the same definitions; creating an existing index is a no-op.
+func EnsureIndexes(ctx context.Context, creator IndexCreator) error {
+	_, err := creator.CreateMany(ctx, userIndexModels())
+	return err
+}
+
+// userIndexModels lists the indexes for the users collection.
+func userIndexModels() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{Keys: bson.D{{Key: "email", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "username", Value: 1}}, Options: options.Index().SetUnique(true)},
+		// Note: If _id is stored as a string, MongoDB automatically indexes it.
+		// If you were storing it as ObjectID and wanted to ensure the string version was also indexed for other queries,
+		// you might add an index on a separate string ID field, but that's not our case here if _id itself is the string.
+	}
+}
+
 // NewMongoDBUserRepository creates a new instance of mongoUserRepository.
-func NewMongoDBUserRepository(ctx context.Context, uri, dbName, collectionName string) (UserRepository, error) {
+func NewMongoDBUserRepository(ctx context.Context, uri, dbName, collectionName string, indexOpts IndexOptions) (UserRepository, error) {
 	clientOptions := options.Client().ApplyURI(uri)
 	client, err := mongo.Connect(ctx, clientOptions)
 	if err != nil {
@@ -38,28 +69,53 @@ func NewMongoDBUserRepository(ctx context.Context, uri, dbName, collectionName s
 	}
 	log.Println("Successfully connected to MongoDB!")
 
+	return NewMongoDBUserRepositoryFromClient(ctx, client, dbName, collectionName, indexOpts), nil
+}
+
+// NewMongoDBUserRepositoryFromClient builds the repository on an already connected client and
+// creates the collection's indexes if indexOpts.EnsureIndexes is set. Index failures are logged, not returned.
+func NewMongoDBUserRepositoryFromClient(ctx context.Context, client *mongo.Client, dbName, collectionName string, indexOpts IndexOptions) UserRepository {
 	db := client.Database(dbName)
 	collection := db.Collection(collectionName)
 
-	indexModels := []mongo.IndexModel{
-		{Keys: bson.D{{Key: "email", Value: 1}}, Options: options.Index().SetUnique(true)},
-		{Keys: bson.D{{Key: "username", Value: 1}}, Options: options.Index().SetUnique(true)},
-		// Note: If _id is stored as a string, MongoDB automatically indexes it.
-		// If you were storing it as ObjectID and wanted to ensure the string version was also indexed for other queries,
-		// you might add an index on a separate string ID field, but that's not our case here if _id itself is the string.
-	}
-	_, err = collection.Indexes().CreateMany(ctx, indexModels)
-	if err != nil {
-		log.Printf("Warning: Could not create indexes for collection %s: %v", collectionName, err)
+	if !indexOpts.EnsureIndexes {
+		log.Printf("Skipping index creation for collection %s (ENSURE_INDEXES=false)", collectionName)
 	} else {
-		log.Printf("Indexes ensured for collection %s", collectionName)
+		creator := indexOpts.Creator
+		if creator == nil {
+			creator = collection.Indexes()
+		}
+		if err := EnsureIndexes(ctx, creator); err != nil {
+			log.Printf("Warning: Could not create indexes for collection %s: %v", collectionName, err)
+		} else {
+			log.Printf("Indexes ensured for collection %s", collectionName)
+		}
 	}
 
 	return &mongoUserRepository{
 		client:     client,
 		db:         db,
 		collection: collection,
-	}, nil
+	}
+}
+
+// NewMongoDBUserRepositoryFromCollection wraps an already connected collection, without pinging
+// or creating indexes. Useful when the client is shared or provided by a test deployment.
+func NewMongoDBUserRepositoryFromCollection(collection *mongo.Collection) UserRepository {
+	return &mongoUserRepository{
+		client:     collection.Database().Client(),
+		db:         collection.Database(),
+		collection: collection,
+	}
+}
+
+// versionFilter matches the user with the given ID and version. Users stored before versioning
+// have no version field and count as version 0.
+func versionFilter(id string, version int) bson.M {
+	if version == 0 {
+		return bson.M{"_id": id, "version": bson.M{"$in": bson.A{0, nil}}}
+	}
+	return bson.M{"_id": id, "version": version}
 }
 
 func (r *mongoUserRepository) Close(ctx context.Context) error {
@@ -87,7 +143,7 @@ func (r *mongoUserRepository) CreateUser(ctx context.Context, user *domain.User)
 	_, err := r.collection.InsertOne(ctx, user) // user.ID (string) will be used for _id
 	if err != nil {
 		if mongo.IsDuplicateKeyError(err) {
-			return nil, errors.New("user with this email or username already exists")
+			return nil, &DuplicateUserError{Field: duplicateKeyField(err)}
 		}
 		log.Printf("Error creating user in MongoDB: %v", err)
 		return nil, err
@@ -95,6 +151,40 @@ func (r *mongoUserRepository) CreateUser(ctx context.Context, user *domain.User)
 	return user, nil
 }
 
+// duplicateKeyField returns the user field whose unique index a duplicate-key error violated,
+// or "" if it cannot tell. The server reports the index's key pattern; older servers only name
+// the index in the message (e.g. "index: email_1 dup key: ...").
+func duplicateKeyField(err error) string {
+	var writeErr mongo.WriteException
+	if !errors.As(err, &writeErr) {
+		return ""
+	}
+	for _, we := range writeErr.WriteErrors {
+		if keyPattern, ok := we.Raw.Lookup("keyPattern").DocumentOK(); ok {
+			if elements, elemErr := keyPattern.Elements(); elemErr == nil && len(elements) > 0 {
+				if field := knownDuplicateField(elements[0].Key()); field != "" {
+					return field
+				}
+			}
+		}
+		for _, field := range []string{DuplicateFieldEmail, DuplicateFieldUsername} {
+			if strings.Contains(we.Message, "index: "+field+"_1 ") {
+				return field
+			}
+		}
+	}
+	return ""
+}
+
+// knownDuplicateField returns key if it is a field with a unique index, "" otherwise.
+func knownDuplicateField(key string) string {
+	switch key {
+	case DuplicateFieldEmail, DuplicateFieldUsername:
+		return key
+	}
+	return ""
+}
+
 // GetUserByID retrieves a user by their ID (which is a string).
 func (r *mongoUserRepository) GetUserByID(ctx context.Context, id string) (*domain.User, error) {
 	// Since domain.User.ID is string and likely stored as string for _id,
@@ -137,11 +227,14 @@ func (r *mongoUserRepository) UpdateUser(ctx context.Context, user *domain.User)
 		"$set": bson.M{
 			"username":   user.Username,
 			"full_name":  user.FullName,
+			"locale":     user.Locale,
 			"updated_at": user.UpdatedAt,
 		},
+		"$inc": bson.M{"version": 1},
 	}
 
-	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": user.ID}, update) // Query with string user.ID
+	// Only update the version we read, so a concurrent update is not silently overwritten.
+	result, err := r.collection.UpdateOne(ctx, versionFilter(user.ID, user.Version), update)
 	if err != nil {
 		if mongo.IsDuplicateKeyError(err) {
 			return nil, errors.New("cannot update user, username or email may conflict")
@@ -151,8 +244,19 @@ func (r *mongoUserRepository) UpdateUser(ctx context.Context, user *domain.User)
 	}
 
 	if result.MatchedCount == 0 {
-		return nil, errors.New("user not found for update")
+		// Either the user is gone or its version moved on; tell them apart for the caller.
+		count, countErr := r.collection.CountDocuments(ctx, bson.M{"_id": user.ID})
+		if countErr != nil {
+			log.Printf("Error checking user '%s' after failed update: %v", user.ID, countErr)
+			return nil, countErr
+		}
+		if count == 0 {
+			return nil, errors.New("user not found for update")
+		}
+		log.Printf("Rejected update of user '%s': version %d is stale", user.ID, user.Version)
+		return nil, ErrConcurrentModification
 	}
+	user.Version++
 	// If ModifiedCount is 0 but MatchedCount is 1, it means the data was the same.
 	// Return the user object that was passed in (it has the updated timestamp).
 	return user, nil
@@ -174,4 +278,68 @@ func (r *mongoUserRepository) DeleteUser(ctx context.Context, id string) error {
 		return errors.New("user not found for deletion")
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+// AnonymizeUser replaces the user's personal data with placeholders, clears the password hash
+// so the account can no longer log in, and drops the favorites. The document itself is kept, so
+// records referring to the user ID stay consistent.
+func (r *mongoUserRepository) AnonymizeUser(ctx context.Context, id string) (*domain.User, error) {
+	if id == "" {
+		return nil, errors.New("user ID cannot be empty for anonymize")
+	}
+	username, email := domain.AnonymizedIdentity(id)
+	update := bson.M{
+		"$set": bson.M{
+			"username":        username,
+			"email":           email,
+			"hashed_password": "",
+			"full_name":       "",
+			"updated_at":      time.Now().UTC(),
+		},
+		"$unset": bson.M{"locale": "", "favorite_pet_ids": ""},
+		"$inc":   bson.M{"version": 1},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var user domain.User
+	err := r.collection.FindOneAndUpdate(ctx, bson.M{"_id": id}, update, opts).Decode(&user)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errors.New("user not found")
+		}
+		log.Printf("Error anonymizing user '%s' in MongoDB: %v", id, err)
+		return nil, err
+	}
+	return &user, nil
+}
+
+// AddFavoritePet adds a pet to the user's favorites. Adding a pet twice is a no-op.
+func (r *mongoUserRepository) AddFavoritePet(ctx context.Context, userID, petID string) ([]string, error) {
+	return r.updateFavorites(ctx, userID, bson.M{"$addToSet": bson.M{"favorite_pet_ids": petID}})
+}
+
+// RemoveFavoritePet removes a pet from the user's favorites. Removing a pet that is not a favorite is a no-op.
+func (r *mongoUserRepository) RemoveFavoritePet(ctx context.Context, userID, petID string) ([]string, error) {
+	return r.updateFavorites(ctx, userID, bson.M{"$pull": bson.M{"favorite_pet_ids": petID}})
+}
+
+// updateFavorites applies a favorites update and returns the resulting list.
+func (r *mongoUserRepository) updateFavorites(ctx context.Context, userID string, update bson.M) ([]string, error) {
+	if userID == "" {
+		return nil, errors.New("user ID cannot be empty")
+	}
+	opts := options.FindOneAndUpdate().
+		SetReturnDocument(options.After).
+		SetProjection(bson.M{"favorite_pet_ids": 1})
+
+	var user domain.User
+	err := r.collection.FindOneAndUpdate(ctx, bson.M{"_id": userID}, update, opts).Decode(&user)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errors.New("user not found")
+		}
+		log.Printf("Error updating favorites for user '%s' in MongoDB: %v", userID, err)
+		return nil, err
+	}
+	return user.FavoritePetIDs, nil
+}
@@ -62,7 +62,7 @@ func (c *redisUserCache) GetUser(ctx context.Context, id string) (*domain.User,
 	val, err := c.client.Get(ctx, key).Result()
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
-			return nil, errors.New("user not found in cache") // Or return a specific cache miss error
+			return nil, ErrCacheMiss
 		}
 		log.Printf("Error getting user from Redis cache (key: %s): %v", key, err)
 		return nil, err
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisTokenBlocklist is the Redis implementation of TokenBlocklist. Each revoked token is a key
+// that expires when the token does. The gateway reads the same keys to reject revoked tokens.
+type redisTokenBlocklist struct {
+	client *redis.Client
+	prefix string // e.g., "revoked:" to namespace keys
+}
+
+// NewRedisTokenBlocklist creates a new instance of redisTokenBlocklist.
+func NewRedisTokenBlocklist(ctx context.Context, addr, password string, db int, keyPrefix string) (TokenBlocklist, error) {
+	rdb := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: password,
+		DB:       db,
+	})
+	if _, err := rdb.Ping(ctx).Result(); err != nil {
+		log.Printf("Error connecting to Redis for the token blocklist: %v", err)
+		return nil, err
+	}
+
+	if keyPrefix == "" {
+		keyPrefix = "revoked:" // Default prefix
+	}
+	return &redisTokenBlocklist{client: rdb, prefix: keyPrefix}, nil
+}
+
+// Close closes the Redis client connection.
+func (b *redisTokenBlocklist) Close() error {
+	if b.client != nil {
+		return b.client.Close()
+	}
+	return nil
+}
+
+// BlockToken records tokenID as revoked for expiration. A token that has already expired needs no entry.
+func (b *redisTokenBlocklist) BlockToken(ctx context.Context, tokenID string, expiration time.Duration) error {
+	if expiration <= 0 {
+		return nil
+	}
+	if err := b.client.Set(ctx, b.prefix+tokenID, 1, expiration).Err(); err != nil {
+		log.Printf("Error blocking token %s in Redis: %v", tokenID, err)
+		return err
+	}
+	return nil
+}
+
+// IsTokenBlocked reports whether tokenID was revoked and has not expired yet.
+func (b *redisTokenBlocklist) IsTokenBlocked(ctx context.Context, tokenID string) (bool, error) {
+	n, err := b.client.Exists(ctx, b.prefix+tokenID).Result()
+	if err != nil {
+		log.Printf("Error checking token %s in the Redis blocklist: %v", tokenID, err)
+		return false, err
+	}
+	return n > 0, nil
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisTokenStore is the Redis implementation of TokenStore. Each token is a key holding its
+// user ID, and every user has a set of their token IDs so they can all be revoked at once.
+type redisTokenStore struct {
+	client *redis.Client
+	prefix string // e.g., "refresh:" to namespace keys
+}
+
+// NewRedisTokenStore creates a new instance of redisTokenStore.
+func NewRedisTokenStore(ctx context.Context, addr, password string, db int, keyPrefix string) (TokenStore, error) {
+	rdb := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: password,
+		DB:       db,
+	})
+	if _, err := rdb.Ping(ctx).Result(); err != nil {
+		log.Printf("Error connecting to Redis for refresh tokens: %v", err)
+		return nil, err
+	}
+
+	if keyPrefix == "" {
+		keyPrefix = "refresh:" // Default prefix
+	}
+	return &redisTokenStore{client: rdb, prefix: keyPrefix}, nil
+}
+
+// Close closes the Redis client connection.
+func (s *redisTokenStore) Close() error {
+	if s.client != nil {
+		return s.client.Close()
+	}
+	return nil
+}
+
+func (s *redisTokenStore) tokenKey(tokenID string) string {
+	return s.prefix + "token:" + tokenID
+}
+
+func (s *redisTokenStore) userKey(userID string) string {
+	return s.prefix + "user:" + userID
+}
+
+// SaveRefreshToken stores a token for userID. The user's token set lives as long as their newest token.
+func (s *redisTokenStore) SaveRefreshToken(ctx context.Context, tokenID, userID string, expiration time.Duration) error {
+	pipe := s.client.TxPipeline()
+	pipe.Set(ctx, s.tokenKey(tokenID), userID, expiration)
+	pipe.SAdd(ctx, s.userKey(userID), tokenID)
+	pipe.Expire(ctx, s.userKey(userID), expiration)
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("Error saving refresh token for user %s in Redis: %v", userID, err)
+		return err
+	}
+	return nil
+}
+
+// GetRefreshTokenUser returns the user ID a token was issued to, without using it up.
+func (s *redisTokenStore) GetRefreshTokenUser(ctx context.Context, tokenID string) (string, error) {
+	userID, err := s.client.Get(ctx, s.tokenKey(tokenID)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", ErrRefreshTokenNotFound
+		}
+		log.Printf("Error reading refresh token from Redis: %v", err)
+		return "", err
+	}
+	return userID, nil
+}
+
+// ConsumeRefreshToken atomically reads and deletes a token, so two requests racing with the same
+// token cannot both succeed.
+func (s *redisTokenStore) ConsumeRefreshToken(ctx context.Context, tokenID string) (string, error) {
+	userID, err := s.client.GetDel(ctx, s.tokenKey(tokenID)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", ErrRefreshTokenNotFound
+		}
+		log.Printf("Error consuming refresh token from Redis: %v", err)
+		return "", err
+	}
+	if err := s.client.SRem(ctx, s.userKey(userID), tokenID).Err(); err != nil {
+		// The token itself is gone; a stale set member is only cleaned up on revocation or expiry.
+		log.Printf("Warning: Failed to remove refresh token from the set of user %s: %v", userID, err)
+	}
+	return userID, nil
+}
+
+// RevokeUserRefreshTokens deletes every refresh token of the user.
+func (s *redisTokenStore) RevokeUserRefreshTokens(ctx context.Context, userID string) error {
+	tokenIDs, err := s.client.SMembers(ctx, s.userKey(userID)).Result()
+	if err != nil {
+		log.Printf("Error listing refresh tokens of user %s in Redis: %v", userID, err)
+		return err
+	}
+	keys := []string{s.userKey(userID)}
+	for _, tokenID := range tokenIDs {
+		keys = append(keys, s.tokenKey(tokenID))
+	}
+	if err := s.client.Del(ctx, keys...).Err(); err != nil {
+		log.Printf("Error revoking refresh tokens of user %s in Redis: %v", userID, err)
+		return err
+	}
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 
 	pb "github.com/zhandarbeks/petstore-final-project/genprotos/user" // Adjust import path to your generated protos
 	"google.golang.org/grpc"
+	_ "google.golang.org/grpc/encoding/gzip" // Registers gzip so clients may send compressed requests
 	"google.golang.org/grpc/health"
 	"google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection" // For Evans CLI, grpcurl, etc.
@@ -19,12 +20,14 @@ import (
 type GRPCServer struct {
 	server   *grpc.Server
 	listener net.Listener
+	inFlight *InFlightTracker
 	Port     string
 }
 
 // NewGRPCServer creates and configures a new gRPC server instance.
-// It takes the port string (e.g., ":50051") and the UserServiceServer implementation.
-func NewGRPCServer(port string, userService pb.UserServiceServer) (*GRPCServer, error) {
+// It takes the port string (e.g., ":50051") and the UserServiceServer implementation,
+// plus the maximum number of concurrent in-flight requests (0 disables the limit).
+func NewGRPCServer(port string, userService pb.UserServiceServer, maxInFlight int) (*GRPCServer, error) {
 	if port == "" {
 		return nil, fmt.Errorf("port cannot be empty")
 	}
@@ -39,9 +42,13 @@ func NewGRPCServer(port string, userService pb.UserServiceServer) (*GRPCServer,
 	}
 
 	// Create a new gRPC server with options (e.g., interceptors if needed later)
+	inFlight := &InFlightTracker{}
 	s := grpc.NewServer(
-	// grpc.UnaryInterceptor(yourUnaryInterceptor), // Example for adding interceptors
-	// grpc.StreamInterceptor(yourStreamInterceptor),
+		grpc.ChainUnaryInterceptor(
+			NewLoggingInterceptor(), // Logs request payloads with passwords redacted
+			NewConcurrencyLimitInterceptor(maxInFlight, inFlight), // Rejects requests beyond maxInFlight with ResourceExhausted
+		),
+		// grpc.StreamInterceptor(yourStreamInterceptor),
 	)
 
 	// Register your user service implementation with the gRPC server.
@@ -65,6 +72,7 @@ func NewGRPCServer(port string, userService pb.UserServiceServer) (*GRPCServer,
 	return &GRPCServer{
 		server:   s,
 		listener: lis,
+		inFlight: inFlight,
 		Port:     port,
 	}, nil
 }
@@ -82,11 +90,17 @@ func (gs *GRPCServer) Start() error {
 	return nil // Should not be reached if Serve() blocks indefinitely
 }
 
+// Addr returns the address the server listens on, useful when it was started on port 0.
+func (gs *GRPCServer) Addr() string {
+	return gs.listener.Addr().String()
+}
+
 // Stop gracefully shuts down the gRPC server.
 func (gs *GRPCServer) Stop() {
-	log.Println("Attempting to gracefully stop gRPC server...")
+	inFlight := gs.inFlight.StartDraining()
+	log.Printf("Attempting to gracefully stop gRPC server... in_flight=%d", inFlight)
 	gs.server.GracefulStop()
-	log.Println("gRPC server stopped.")
+	log.Printf("gRPC server stopped. in_flight_at_shutdown=%d drained=%d", inFlight, gs.inFlight.Drained())
 }
 
 // RunWithGracefulShutdown starts the server and handles OS signals for graceful shutdown.
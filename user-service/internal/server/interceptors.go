@@ -0,0 +1,139 @@
+package server
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// InFlightTracker counts the requests admitted by the concurrency-limit interceptor that are still
+// being handled. Once StartDraining is called it also counts how many of them completed, so
+// graceful shutdown can report what it drained.
+type InFlightTracker struct {
+	inFlight atomic.Int64
+	draining atomic.Bool
+	drained  atomic.Int64
+}
+
+// InFlight returns the number of requests currently being handled.
+func (t *InFlightTracker) InFlight() int64 { return t.inFlight.Load() }
+
+// StartDraining marks the start of shutdown and returns the number of requests in flight at that moment.
+func (t *InFlightTracker) StartDraining() int64 {
+	t.draining.Store(true)
+	return t.inFlight.Load()
+}
+
+// Drained returns the number of requests that completed after StartDraining.
+func (t *InFlightTracker) Drained() int64 { return t.drained.Load() }
+
+func (t *InFlightTracker) begin() {
+	if t != nil {
+		t.inFlight.Add(1)
+	}
+}
+
+func (t *InFlightTracker) end() {
+	if t == nil {
+		return
+	}
+	t.inFlight.Add(-1)
+	if t.draining.Load() {
+		t.drained.Add(1)
+	}
+}
+
+// NewConcurrencyLimitInterceptor returns a unary interceptor that allows at most maxInFlight
+// requests to be processed concurrently. Requests arriving while the limit is reached are
+// rejected immediately with codes.ResourceExhausted instead of being queued.
+// A maxInFlight of 0 or less disables the limit. Admitted requests are counted in tracker, which may be nil.
+func NewConcurrencyLimitInterceptor(maxInFlight int, tracker *InFlightTracker) grpc.UnaryServerInterceptor {
+	if maxInFlight <= 0 {
+		return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			tracker.begin()
+			defer tracker.end()
+			return handler(ctx, req)
+		}
+	}
+
+	sem := make(chan struct{}, maxInFlight)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		select {
+		case sem <- struct{}{}:
+			defer func() { <-sem }()
+			tracker.begin()
+			defer tracker.end()
+			return handler(ctx, req)
+		default:
+			log.Printf("Rejecting %s: max in-flight requests (%d) reached", info.FullMethod, maxInFlight)
+			return nil, status.Errorf(codes.ResourceExhausted, "server is busy, too many concurrent requests")
+		}
+	}
+}
+
+// redactedValue replaces the value of sensitive fields in logged payloads.
+const redactedValue = "[REDACTED]"
+
+// sensitiveFields are the proto field names whose values are never logged, at any nesting level.
+var sensitiveFields = map[protoreflect.Name]bool{
+	"password":      true,
+	"refresh_token": true,
+	"access_token":  true,
+}
+
+// RedactedPayload renders a request message as JSON for logging, with the values of
+// sensitiveFields replaced by "[REDACTED]". The message itself is not modified.
+func RedactedPayload(req interface{}) string {
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return "<non-proto payload>"
+	}
+	clone := proto.Clone(msg)
+	redactMessage(clone.ProtoReflect())
+	data, err := protojson.Marshal(clone)
+	if err != nil {
+		return "<unprintable payload>"
+	}
+	return string(data)
+}
+
+func redactMessage(m protoreflect.Message) {
+	var redact []protoreflect.FieldDescriptor // Set after Range, which must not see the message change
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case sensitiveFields[fd.Name()] && fd.Kind() == protoreflect.StringKind && !fd.IsList():
+			redact = append(redact, fd)
+		case fd.Kind() == protoreflect.MessageKind && fd.IsList():
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				redactMessage(list.Get(i).Message())
+			}
+		case fd.Kind() == protoreflect.MessageKind && !fd.IsMap():
+			redactMessage(v.Message())
+		}
+		return true
+	})
+	for _, fd := range redact {
+		m.Set(fd, protoreflect.ValueOfString(redactedValue))
+	}
+}
+
+// NewLoggingInterceptor returns a unary interceptor that logs each request with its payload,
+// redacted by RedactedPayload, followed by the resulting status code and duration.
+func NewLoggingInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		log.Printf("gRPC request %s payload=%s", info.FullMethod, RedactedPayload(req))
+		resp, err := handler(ctx, req)
+		log.Printf("gRPC request %s finished code=%s duration=%s", info.FullMethod, status.Code(err), time.Since(start))
+		return resp, err
+	}
+}
@@ -0,0 +1,66 @@
+package server
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"log"
+	"math/big"
+	"net/http"
+
+	"github.com/zhandarbeks/petstore-final-project/user-service/internal/usecase"
+)
+
+// JWK is an RSA public key in JSON Web Key format (RFC 7517).
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	Modulus   string `json:"n"`
+	Exponent  string `json:"e"`
+}
+
+// JWKSet is the body of the /jwks.json endpoint.
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// PublicJWK returns the public half of an RS256 signing key as a JWK.
+func PublicJWK(publicKey *rsa.PublicKey, keyID string) JWK {
+	return JWK{
+		KeyType:   "RSA",
+		Use:       "sig",
+		Algorithm: "RS256",
+		KeyID:     keyID,
+		Modulus:   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+		Exponent:  base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+	}
+}
+
+// NewJWKSHandler serves the public key of the current signing key, so token validators need no
+// shared secret, followed by the previous keys that tokens issued before a rotation were signed
+// with. HMAC keys are never published.
+func NewJWKSHandler(current usecase.SigningKey, previous ...*rsa.PublicKey) http.Handler {
+	set := JWKSet{Keys: []JWK{}}
+	if privateKey, ok := current.Key.(*rsa.PrivateKey); ok {
+		set.Keys = append(set.Keys, PublicJWK(&privateKey.PublicKey, current.KeyID))
+	}
+	for _, publicKey := range previous {
+		set.Keys = append(set.Keys, PublicJWK(publicKey, usecase.RSAKeyID(publicKey)))
+	}
+	body, err := json.Marshal(set)
+	if err != nil {
+		log.Fatalf("FATAL: Could not encode JWKS: %v", err)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		w.Write(body)
+	})
+}
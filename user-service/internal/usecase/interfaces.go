@@ -2,8 +2,11 @@ package usecase
 
 import (
 	"context"
+	"crypto/rsa"
+	"time"
 
 	"github.com/zhandarbeks/petstore-final-project/user-service/internal/domain" // Adjust import path as per your module
+	"github.com/zhandarbeks/petstore-final-project/user-service/internal/repository"
 	// You might need to define request/response structs specific to usecases if they differ significantly from domain/gRPC,
 	// but often gRPC request/response types (or domain types) can be used directly or with minimal mapping.
 )
@@ -13,6 +16,19 @@ type UserUsecase interface {
 	RegisterUser(ctx context.Context, username, email, password, fullName string) (*domain.User, string, error) // Returns User, AccessToken, Error
 	LoginUser(ctx context.Context, email, password string) (*domain.User, string, error)    // Returns User, AccessToken, Error
 	GetUserByID(ctx context.Context, id string) (*domain.User, error)
-	UpdateUserProfile(ctx context.Context, id string, username, fullName *string) (*domain.User, error) // Pointers allow partial updates
+	UpdateUserProfile(ctx context.Context, id string, username, fullName, locale *string) (*domain.User, error) // Pointers allow partial updates
 	DeleteUser(ctx context.Context, id string) error
+	AnonymizeUser(ctx context.Context, id string) (*domain.User, error) // Replaces personal data with placeholders; the account can no longer log in
+	AddFavoritePet(ctx context.Context, userID, petID string) ([]string, error)
+	RemoveFavoritePet(ctx context.Context, userID, petID string) ([]string, error)
+	ListFavoritePets(ctx context.Context, userID string) ([]string, error)
+	SetBcryptCost(cost int) // Cost for hashing new passwords; defaults to bcrypt.DefaultCost
+	SetRefreshTokenStore(store repository.TokenStore, expiry time.Duration) // Enables refresh tokens; without a store none are issued
+	IssueRefreshToken(ctx context.Context, userID string) (string, error)     // Returns "" when refresh tokens are disabled
+	RefreshToken(ctx context.Context, refreshToken string) (string, string, error) // Returns a new access token and the rotated refresh token
+	SetTokenBlocklist(blocklist repository.TokenBlocklist) // Enables logout; without a blocklist LogoutUser fails
+	SetPreviousVerificationKeys(publicKeys ...*rsa.PublicKey) // Rotated-out RS256 keys ValidateAccessToken still accepts
+	SetTokenLeeway(leeway time.Duration)                      // Clock skew ValidateAccessToken tolerates on exp/nbf
+	ValidateAccessToken(ctx context.Context, accessToken string) (*AccessTokenClaims, error) // Rejects revoked tokens with ErrInvalidAccessToken
+	LogoutUser(ctx context.Context, accessToken, refreshToken string) error // Revokes the access token, and refreshToken if not empty
 }
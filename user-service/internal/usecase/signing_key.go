@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// SigningKey is the key access tokens are signed with.
+type SigningKey struct {
+	Method jwt.SigningMethod
+	Key    interface{} // []byte for HS256, *rsa.PrivateKey for RS256
+	KeyID  string      // Sent as the token's "kid" header so validators can pick the key from a JWKS; empty for HS256
+}
+
+// HMACSigningKey signs tokens with HS256 and a secret shared with every validator.
+func HMACSigningKey(secret string) SigningKey {
+	return SigningKey{Method: jwt.SigningMethodHS256, Key: []byte(secret)}
+}
+
+// RSASigningKey signs tokens with RS256, so validators only need the public key.
+func RSASigningKey(privateKey *rsa.PrivateKey) SigningKey {
+	return SigningKey{Method: jwt.SigningMethodRS256, Key: privateKey, KeyID: RSAKeyID(&privateKey.PublicKey)}
+}
+
+// verificationKey returns the key that validates tokens signed with k.
+func (k SigningKey) verificationKey() interface{} {
+	if privateKey, ok := k.Key.(*rsa.PrivateKey); ok {
+		return &privateKey.PublicKey
+	}
+	return k.Key
+}
+
+// RSAKeyID returns the RFC 7638 thumbprint of publicKey, used as its key ID.
+func RSAKeyID(publicKey *rsa.PublicKey) string {
+	// Required members in lexicographic order, without whitespace.
+	thumbprintInput := fmt.Sprintf(`{"e":"%s","kty":"RSA","n":"%s"}`,
+		base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+		base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()))
+	sum := sha256.Sum256([]byte(thumbprintInput))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// LoadRSASigningKey reads a PEM-encoded RSA private key (PKCS#1 or PKCS#8) and returns an RS256 signing key.
+func LoadRSASigningKey(privateKeyFile string) (SigningKey, error) {
+	data, err := os.ReadFile(privateKeyFile)
+	if err != nil {
+		return SigningKey{}, fmt.Errorf("reading JWT private key: %w", err)
+	}
+	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(data)
+	if err != nil {
+		return SigningKey{}, fmt.Errorf("parsing JWT private key: %w", err)
+	}
+	return RSASigningKey(privateKey), nil
+}
+
+// LoadRSAPublicKeys reads PEM-encoded RSA public keys, such as those of keys rotated out but
+// still validating unexpired tokens.
+func LoadRSAPublicKeys(publicKeyFiles ...string) ([]*rsa.PublicKey, error) {
+	publicKeys := make([]*rsa.PublicKey, 0, len(publicKeyFiles))
+	for _, file := range publicKeyFiles {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("reading JWT public key: %w", err)
+		}
+		publicKey, err := jwt.ParseRSAPublicKeyFromPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("parsing JWT public key %s: %w", file, err)
+		}
+		publicKeys = append(publicKeys, publicKey)
+	}
+	return publicKeys, nil
+}
@@ -2,6 +2,11 @@ package usecase
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"log"
@@ -9,7 +14,9 @@ import (
 
 	"github.com/golang-jwt/jwt/v5"                                                  // For JWT generation
 	"github.com/zhandarbeks/petstore-final-project/user-service/internal/domain" // Adjust import path
+	"github.com/zhandarbeks/petstore-final-project/user-service/internal/metrics"
 	"github.com/zhandarbeks/petstore-final-project/user-service/internal/repository"
+	"golang.org/x/crypto/bcrypt"
 	// "go.mongodb.org/mongo-driver/bson/primitive" // If generating IDs here, but usually repo handles it
 )
 
@@ -17,11 +24,65 @@ import (
 type userUsecase struct {
 	userRepo     repository.UserRepository
 	userCache    repository.UserCache // For caching user data
-	jwtSecretKey []byte               // Secret key for signing JWTs
+	signingKey   SigningKey           // Key and algorithm for signing JWTs
 	tokenExpiry  time.Duration        // How long tokens are valid
+	bcryptCost   int                  // Cost used to hash new passwords
+	tokenStore   repository.TokenStore // Refresh tokens; nil disables them
+	refreshTokenExpiry time.Duration  // How long refresh tokens are valid
+	tokenBlocklist repository.TokenBlocklist // Access tokens revoked by logging out; nil disables logout
+	previousKeys map[string]*rsa.PublicKey // Rotated-out RS256 keys by key ID, still accepted by ValidateAccessToken
+	tokenLeeway  time.Duration             // Clock skew tolerated on exp/nbf by ValidateAccessToken, as in the gateway
 }
 
-// NewUserUsecase creates a new instance of userUsecase.
+// ErrNoFieldsToUpdate is returned when an update request does not set any field.
+var ErrNoFieldsToUpdate = errors.New("at least one field must be provided for update")
+
+// ErrUnsupportedLocale is returned when a profile update sets a locale with no email templates.
+var ErrUnsupportedLocale = errors.New("unsupported locale")
+
+// ErrInvalidRefreshToken is returned by RefreshToken for a refresh token that is unknown, expired,
+// revoked or was already exchanged.
+var ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+
+// ErrInvalidAccessToken is returned for an access token that is malformed, not signed by this
+// service, expired or revoked.
+var ErrInvalidAccessToken = errors.New("invalid, expired or revoked access token")
+
+// ErrLogoutUnavailable is returned by LogoutUser when no token blocklist is configured.
+var ErrLogoutUnavailable = errors.New("logout is not available")
+
+// AccessTokenClaims are the claims of a valid access token.
+type AccessTokenClaims struct {
+	UserID    string
+	TokenID   string // "jti" claim; empty in tokens issued before tokens could be revoked
+	ExpiresAt time.Time
+}
+
+// ErrTokenGenerationFailed is matched (with errors.Is) by the *TokenGenerationError that
+// RegisterUser returns when the user was created but no access token could be signed.
+var ErrTokenGenerationFailed = errors.New("user registered, but token generation failed")
+
+// TokenGenerationError reports that registration created User but signing its token failed.
+// The account exists, so callers should treat registration as done and have the user log in.
+type TokenGenerationError struct {
+	User *domain.User
+	Err  error // The signing error
+}
+
+func (e *TokenGenerationError) Error() string {
+	return fmt.Sprintf("%v: %v", ErrTokenGenerationFailed, e.Err)
+}
+
+// Is makes errors.Is(err, ErrTokenGenerationFailed) match.
+func (e *TokenGenerationError) Is(target error) bool {
+	return target == ErrTokenGenerationFailed
+}
+
+func (e *TokenGenerationError) Unwrap() error {
+	return e.Err
+}
+
+// NewUserUsecase creates a new instance of userUsecase that signs tokens with HS256.
 func NewUserUsecase(
 	repo repository.UserRepository,
 	cache repository.UserCache,
@@ -31,18 +92,242 @@ func NewUserUsecase(
 	if jwtSecret == "" {
 		log.Fatal("FATAL: JWT secret key cannot be empty for UserUsecase")
 	}
+	return NewUserUsecaseWithSigningKey(repo, cache, HMACSigningKey(jwtSecret), tokenExpiry)
+}
+
+// NewUserUsecaseWithSigningKey creates a new instance of userUsecase that signs tokens with signingKey.
+func NewUserUsecaseWithSigningKey(
+	repo repository.UserRepository,
+	cache repository.UserCache,
+	signingKey SigningKey,
+	tokenExpiry time.Duration,
+) UserUsecase {
+	if signingKey.Method == nil || signingKey.Key == nil {
+		log.Fatal("FATAL: JWT signing key cannot be empty for UserUsecase")
+	}
 	return &userUsecase{
-		userRepo:     repo,
-		userCache:    cache,
-		jwtSecretKey: []byte(jwtSecret),
-		tokenExpiry:  tokenExpiry,
+		userRepo:    repo,
+		userCache:   cache,
+		signingKey:  signingKey,
+		tokenExpiry: tokenExpiry,
+		bcryptCost:  bcrypt.DefaultCost,
+	}
+}
+
+// SetBcryptCost sets the bcrypt cost used to hash passwords of new users, clamped to the
+// range bcrypt accepts. Existing hashes keep the cost they were created with.
+func (uc *userUsecase) SetBcryptCost(cost int) {
+	uc.bcryptCost = domain.ClampBcryptCost(cost)
+}
+
+// SetRefreshTokenStore enables refresh tokens, kept in store for expiry. Without a store none are issued.
+func (uc *userUsecase) SetRefreshTokenStore(store repository.TokenStore, expiry time.Duration) {
+	uc.tokenStore = store
+	uc.refreshTokenExpiry = expiry
+}
+
+// SetTokenBlocklist enables logout: revoked access tokens are recorded in blocklist until they expire.
+func (uc *userUsecase) SetTokenBlocklist(blocklist repository.TokenBlocklist) {
+	uc.tokenBlocklist = blocklist
+}
+
+// SetPreviousVerificationKeys makes ValidateAccessToken also accept tokens signed with the
+// rotated-out RS256 keys publicKeys, picked by their "kid" header like the gateway does.
+func (uc *userUsecase) SetPreviousVerificationKeys(publicKeys ...*rsa.PublicKey) {
+	uc.previousKeys = make(map[string]*rsa.PublicKey, len(publicKeys))
+	for _, publicKey := range publicKeys {
+		uc.previousKeys[RSAKeyID(publicKey)] = publicKey
+	}
+}
+
+// SetTokenLeeway makes ValidateAccessToken accept tokens up to leeway past their "exp", or
+// before their "nbf", so it agrees with the gateway on which tokens are still valid.
+func (uc *userUsecase) SetTokenLeeway(leeway time.Duration) {
+	uc.tokenLeeway = leeway
+}
+
+// verificationKey picks the key that validates token: the current signing key, or the previous
+// key named by its "kid" header. Tokens without "kid" are only checked against the current key.
+func (uc *userUsecase) verificationKey(token *jwt.Token) (interface{}, error) {
+	keyID, _ := token.Header["kid"].(string)
+	if keyID == "" || keyID == uc.signingKey.KeyID {
+		return uc.signingKey.verificationKey(), nil
+	}
+	if publicKey, ok := uc.previousKeys[keyID]; ok {
+		return publicKey, nil
 	}
+	return nil, fmt.Errorf("unknown signing key %q", keyID)
+}
+
+// refreshTokenID is the ID a refresh token is stored under. Only a hash is kept, so the tokens
+// cannot be read back from the store.
+func refreshTokenID(refreshToken string) string {
+	sum := sha256.Sum256([]byte(refreshToken))
+	return hex.EncodeToString(sum[:])
+}
+
+// IssueRefreshToken creates a new opaque refresh token for the user. It returns "" when refresh
+// tokens are disabled.
+func (uc *userUsecase) IssueRefreshToken(ctx context.Context, userID string) (string, error) {
+	if uc.tokenStore == nil {
+		return "", nil
+	}
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("could not generate refresh token: %w", err)
+	}
+	refreshToken := base64.RawURLEncoding.EncodeToString(raw)
+	if err := uc.tokenStore.SaveRefreshToken(ctx, refreshTokenID(refreshToken), userID, uc.refreshTokenExpiry); err != nil {
+		return "", fmt.Errorf("could not store refresh token: %w", err)
+	}
+	return refreshToken, nil
+}
+
+// RefreshToken exchanges a refresh token for a new access token and a new refresh token. The
+// refresh token passed in is used up, so presenting it again fails with ErrInvalidRefreshToken.
+// It is only used up once the user was found and the access token signed, so a transient error
+// before that leaves it valid for a retry.
+func (uc *userUsecase) RefreshToken(ctx context.Context, refreshToken string) (string, string, error) {
+	if uc.tokenStore == nil || refreshToken == "" {
+		return "", "", ErrInvalidRefreshToken
+	}
+	tokenID := refreshTokenID(refreshToken)
+	userID, err := uc.tokenStore.GetRefreshTokenUser(ctx, tokenID)
+	if err != nil {
+		if errors.Is(err, repository.ErrRefreshTokenNotFound) {
+			return "", "", ErrInvalidRefreshToken
+		}
+		return "", "", fmt.Errorf("could not check refresh token: %w", err)
+	}
+
+	user, err := uc.GetUserByID(ctx, userID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return "", "", ErrInvalidRefreshToken // Deleted after the token was issued
+		}
+		return "", "", err
+	}
+	if user.IsAnonymized() {
+		return "", "", ErrInvalidRefreshToken // Anonymized after the token was issued
+	}
+	accessToken, err := uc.generateJWT(user)
+	if err != nil {
+		return "", "", err
+	}
+
+	// Consuming is atomic, so of two requests racing with the same token only one succeeds.
+	if _, err := uc.tokenStore.ConsumeRefreshToken(ctx, tokenID); err != nil {
+		if errors.Is(err, repository.ErrRefreshTokenNotFound) {
+			return "", "", ErrInvalidRefreshToken
+		}
+		return "", "", fmt.Errorf("could not use refresh token: %w", err)
+	}
+	newRefreshToken, err := uc.IssueRefreshToken(ctx, user.ID)
+	if err != nil {
+		return "", "", err
+	}
+
+	log.Printf("Refresh token rotated for user ID %s", user.ID)
+	return accessToken, newRefreshToken, nil
+}
+
+// revokeRefreshTokens revokes the user's refresh tokens after the account was deleted or
+// anonymized. A failure is only logged: RefreshToken refuses deleted and anonymized users, so
+// tokens left behind cannot be exchanged anyway.
+func (uc *userUsecase) revokeRefreshTokens(ctx context.Context, id string) {
+	if uc.tokenStore == nil {
+		return
+	}
+	if err := uc.tokenStore.RevokeUserRefreshTokens(ctx, id); err != nil {
+		log.Printf("Warning: Failed to revoke refresh tokens of user %s: %v", id, err)
+	}
+}
+
+// ValidateAccessToken checks an access token issued by this service and returns its claims. It
+// accepts the same tokens as the gateway: signed with the current or a previous key, within the
+// leeway. Tokens revoked with LogoutUser are rejected with ErrInvalidAccessToken like invalid ones.
+func (uc *userUsecase) ValidateAccessToken(ctx context.Context, accessToken string) (*AccessTokenClaims, error) {
+	token, err := jwt.Parse(accessToken, uc.verificationKey,
+		jwt.WithValidMethods([]string{uc.signingKey.Method.Alg()}),
+		jwt.WithIssuer("petstore-user-service"),
+		jwt.WithAudience("petstore-clients"),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(uc.tokenLeeway),
+	)
+	if err != nil {
+		return nil, ErrInvalidAccessToken
+	}
+	mapClaims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, ErrInvalidAccessToken
+	}
+	userID, _ := mapClaims["sub"].(string)
+	tokenID, _ := mapClaims["jti"].(string)
+	expiresAt, err := mapClaims.GetExpirationTime()
+	if userID == "" || err != nil || expiresAt == nil {
+		return nil, ErrInvalidAccessToken
+	}
+
+	if tokenID != "" && uc.tokenBlocklist != nil {
+		blocked, err := uc.tokenBlocklist.IsTokenBlocked(ctx, tokenID)
+		if err != nil {
+			return nil, fmt.Errorf("could not check token blocklist: %w", err)
+		}
+		if blocked {
+			return nil, ErrInvalidAccessToken
+		}
+	}
+	return &AccessTokenClaims{UserID: userID, TokenID: tokenID, ExpiresAt: expiresAt.Time}, nil
+}
+
+// LogoutUser revokes an access token for the rest of its lifetime, and refreshToken if it is not
+// empty. Tokens issued before they carried an ID cannot be revoked and stay valid until they expire.
+func (uc *userUsecase) LogoutUser(ctx context.Context, accessToken, refreshToken string) error {
+	if uc.tokenBlocklist == nil {
+		return ErrLogoutUnavailable
+	}
+	claims, err := uc.ValidateAccessToken(ctx, accessToken)
+	if err != nil {
+		return err
+	}
+
+	if claims.TokenID == "" {
+		log.Printf("Warning: Access token of user ID %s has no ID and cannot be revoked", claims.UserID)
+	} else if err := uc.tokenBlocklist.BlockToken(ctx, claims.TokenID, time.Until(claims.ExpiresAt)+uc.tokenLeeway); err != nil {
+		return fmt.Errorf("could not revoke access token: %w", err)
+	}
+
+	if refreshToken != "" && uc.tokenStore != nil {
+		// Not found means the refresh token was already used or has expired: nothing to revoke.
+		if _, err := uc.tokenStore.ConsumeRefreshToken(ctx, refreshTokenID(refreshToken)); err != nil && !errors.Is(err, repository.ErrRefreshTokenNotFound) {
+			log.Printf("Warning: Failed to revoke refresh token of user ID %s on logout: %v", claims.UserID, err)
+		}
+	}
+
+	log.Printf("User ID %s logged out", claims.UserID)
+	return nil
+}
+
+// newAccessTokenID returns a random ID for the "jti" claim, under which the token can be revoked.
+func newAccessTokenID() (string, error) {
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(raw), nil
 }
 
 // generateJWT generates a new JWT access token for a given user.
 func (uc *userUsecase) generateJWT(user *domain.User) (string, error) {
+	tokenID, err := newAccessTokenID()
+	if err != nil {
+		log.Printf("Error generating JWT ID for user %s: %v", user.ID, err)
+		return "", fmt.Errorf("could not generate token: %w", err)
+	}
+
 	// Create the claims
 	claims := jwt.MapClaims{
+		"jti": tokenID, // Token ID, recorded in the blocklist on logout
 		"sub": user.ID, // Subject (user ID)
 		"eml": user.Email,
 		"unm": user.Username,                               // Username
@@ -51,13 +336,17 @@ func (uc *userUsecase) generateJWT(user *domain.User) (string, error) {
 		"iss": "petstore-user-service",                     // Issuer
 		"aud": "petstore-clients",                          // Audience
 		"fnm": user.FullName,                               // Full name
+		"rol": user.EffectiveRole(),                        // Role, checked by the gateway for staff-only endpoints
 	}
 
 	// Create token
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token := jwt.NewWithClaims(uc.signingKey.Method, claims)
+	if uc.signingKey.KeyID != "" {
+		token.Header["kid"] = uc.signingKey.KeyID
+	}
 
-	// Sign token with secret
-	tokenString, err := token.SignedString(uc.jwtSecretKey)
+	// Sign token with the configured key
+	tokenString, err := token.SignedString(uc.signingKey.Key)
 	if err != nil {
 		log.Printf("Error signing JWT token for user %s: %v", user.ID, err)
 		return "", fmt.Errorf("could not generate token: %w", err)
@@ -81,11 +370,11 @@ func (uc *userUsecase) RegisterUser(ctx context.Context, username, email, passwo
 		return nil, "", fmt.Errorf("could not verify user existence: %w", err)
 	}
 	if existingUser != nil {
-		return nil, "", errors.New("user with this email already exists")
+		return nil, "", &repository.DuplicateUserError{Field: repository.DuplicateFieldEmail}
 	}
 
 	// Hash the password
-	hashedPassword, err := domain.HashPassword(password)
+	hashedPassword, err := domain.HashPasswordWithCost(password, uc.bcryptCost)
 	if err != nil {
 		log.Printf("Error hashing password for user '%s': %v", email, err)
 		return nil, "", fmt.Errorf("could not process password: %w", err)
@@ -97,6 +386,7 @@ func (uc *userUsecase) RegisterUser(ctx context.Context, username, email, passwo
 		Email:          email,
 		HashedPassword: hashedPassword,
 		FullName:       fullName,
+		Role:           domain.RoleUser, // Admins are promoted in the database, never at sign-up
 	}
 	// PrepareForCreate sets CreatedAt and UpdatedAt, called by repo.CreateUser
 
@@ -109,12 +399,10 @@ func (uc *userUsecase) RegisterUser(ctx context.Context, username, email, passwo
 	// Generate JWT token for the new user
 	tokenString, err := uc.generateJWT(createdUser)
 	if err != nil {
-		// Log error but proceed with user creation if token generation fails.
-		// Alternatively, you could make this a critical failure.
+		// The user is already stored, so this is not a failed registration: return the user with
+		// an error telling the caller that the client has to log in to get a token.
 		log.Printf("Warning: User %s registered but failed to generate JWT: %v", createdUser.ID, err)
-		// Depending on requirements, you might return the user without a token or error out.
-		// For now, we'll return the user and an empty token with the error.
-		return createdUser, "", fmt.Errorf("user registered, but token generation failed: %w", err)
+		return createdUser, "", &TokenGenerationError{User: createdUser, Err: err}
 	}
 
 	log.Printf("User registered successfully: %s (ID: %s)", createdUser.Email, createdUser.ID)
@@ -161,9 +449,11 @@ func (uc *userUsecase) GetUserByID(ctx context.Context, id string) (*domain.User
 	cachedUser, err := uc.userCache.GetUser(ctx, id)
 	if err == nil && cachedUser != nil {
 		log.Printf("User %s found in cache", id)
+		metrics.CacheHits.Inc(metrics.CacheUser)
 		return cachedUser, nil
 	}
-	if err != nil && err.Error() != "user not found in cache" { // Log actual cache errors
+	metrics.CacheMisses.Inc(metrics.CacheUser)
+	if err != nil && !errors.Is(err, repository.ErrCacheMiss) { // Log actual cache errors
 		log.Printf("Error fetching user %s from cache: %v", id, err)
 	}
 
@@ -187,12 +477,19 @@ func (uc *userUsecase) GetUserByID(ctx context.Context, id string) (*domain.User
 
 // UpdateUserProfile handles updating a user's profile information.
 // It uses pointers for username and fullName to allow partial updates (only update if provided).
-func (uc *userUsecase) UpdateUserProfile(ctx context.Context, id string, username, fullName *string) (*domain.User, error) {
+func (uc *userUsecase) UpdateUserProfile(ctx context.Context, id string, username, fullName, locale *string) (*domain.User, error) {
 	if id == "" {
 		return nil, errors.New("user ID is required for update")
 	}
-	if username == nil && fullName == nil {
-		return nil, errors.New("at least one field (username or full name) must be provided for update")
+	if username == nil && fullName == nil && locale == nil {
+		return nil, ErrNoFieldsToUpdate
+	}
+	var normalizedLocale string
+	if locale != nil {
+		var ok bool
+		if normalizedLocale, ok = domain.NormalizeLocale(*locale); !ok {
+			return nil, ErrUnsupportedLocale
+		}
 	}
 
 	// Fetch the existing user
@@ -217,6 +514,10 @@ func (uc *userUsecase) UpdateUserProfile(ctx context.Context, id string, usernam
 		user.FullName = *fullName
 		updated = true
 	}
+	if locale != nil && normalizedLocale != user.Locale { // An empty locale resets to the default
+		user.Locale = normalizedLocale
+		updated = true
+	}
 
 	if !updated {
 		log.Printf("No changes detected for user %s profile update.", id)
@@ -231,12 +532,8 @@ func (uc *userUsecase) UpdateUserProfile(ctx context.Context, id string, usernam
 		return nil, fmt.Errorf("could not update user profile: %w", err)
 	}
 
-	// Invalidate/update cache after successful DB update
-	cacheErr := uc.userCache.DeleteUser(ctx, id) // Simple invalidation
-	// Or: uc.userCache.SetUser(ctx, id, updatedUser, 1*time.Hour) // Update with new data
-	if cacheErr != nil {
-		log.Printf("Warning: Failed to invalidate/update cache for user %s after profile update: %v", id, cacheErr)
-	}
+	// Invalidate cache after successful DB update; the next read repopulates it
+	uc.invalidateUserCache(ctx, id)
 
 	log.Printf("User profile updated successfully for ID: %s", id)
 	return updatedUser, nil
@@ -255,11 +552,73 @@ func (uc *userUsecase) DeleteUser(ctx context.Context, id string) error {
 	}
 
 	// Invalidate cache after successful DB deletion
-	cacheErr := uc.userCache.DeleteUser(ctx, id)
-	if cacheErr != nil {
-		log.Printf("Warning: Failed to delete user %s from cache after DB deletion: %v", id, cacheErr)
-	}
+	uc.invalidateUserCache(ctx, id)
+	uc.revokeRefreshTokens(ctx, id)
 
 	log.Printf("User deleted successfully: ID %s", id)
 	return nil
 }
+
+// AnonymizeUser withdraws a user's personal data: it is replaced with placeholders and the
+// password is cleared, so the account can no longer log in. Unlike DeleteUser the record stays,
+// so applications and other data referring to the user ID remain consistent.
+func (uc *userUsecase) AnonymizeUser(ctx context.Context, id string) (*domain.User, error) {
+	if id == "" {
+		return nil, errors.New("user ID is required for anonymization")
+	}
+	user, err := uc.userRepo.AnonymizeUser(ctx, id)
+	if err != nil {
+		log.Printf("Error anonymizing user %s in repository: %v", id, err)
+		return nil, err
+	}
+	uc.invalidateUserCache(ctx, id)
+	uc.revokeRefreshTokens(ctx, id)
+
+	log.Printf("User anonymized successfully: ID %s", id)
+	return user, nil
+}
+
+// AddFavoritePet adds a pet to the user's favorites and returns the updated list.
+// The pet itself is not looked up here; the gateway resolves (and tolerates missing) pets when reading favorites.
+func (uc *userUsecase) AddFavoritePet(ctx context.Context, userID, petID string) ([]string, error) {
+	if userID == "" || petID == "" {
+		return nil, errors.New("user ID and pet ID are required")
+	}
+	favorites, err := uc.userRepo.AddFavoritePet(ctx, userID, petID)
+	if err != nil {
+		log.Printf("Error adding pet %s to favorites of user %s: %v", petID, userID, err)
+		return nil, err
+	}
+	uc.invalidateUserCache(ctx, userID)
+	return favorites, nil
+}
+
+// RemoveFavoritePet removes a pet from the user's favorites and returns the updated list.
+func (uc *userUsecase) RemoveFavoritePet(ctx context.Context, userID, petID string) ([]string, error) {
+	if userID == "" || petID == "" {
+		return nil, errors.New("user ID and pet ID are required")
+	}
+	favorites, err := uc.userRepo.RemoveFavoritePet(ctx, userID, petID)
+	if err != nil {
+		log.Printf("Error removing pet %s from favorites of user %s: %v", petID, userID, err)
+		return nil, err
+	}
+	uc.invalidateUserCache(ctx, userID)
+	return favorites, nil
+}
+
+// ListFavoritePets returns the IDs of the pets the user has favorited.
+func (uc *userUsecase) ListFavoritePets(ctx context.Context, userID string) ([]string, error) {
+	user, err := uc.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return user.FavoritePetIDs, nil
+}
+
+// invalidateUserCache drops the cached copy of a user after a write.
+func (uc *userUsecase) invalidateUserCache(ctx context.Context, id string) {
+	if cacheErr := uc.userCache.DeleteUser(ctx, id); cacheErr != nil {
+		log.Printf("Warning: Failed to invalidate cache for user %s: %v", id, cacheErr)
+	}
+}
@@ -1,16 +1,39 @@
 package main_test // Or use the package name of your user-service cmd, e.g., main_test or userservicetest
 
 import (
+	"bytes"
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
 	"testing"
 	"time"
 
 	// Adjust these import paths to match your project's module path and structure
+	pb "github.com/zhandarbeks/petstore-final-project/genprotos/user"
+	"github.com/zhandarbeks/petstore-final-project/internal/testutil"
 	"github.com/zhandarbeks/petstore-final-project/user-service/internal/domain"
+	"github.com/zhandarbeks/petstore-final-project/user-service/internal/handler"
 	"github.com/zhandarbeks/petstore-final-project/user-service/internal/repository" // For mock repository
+	"github.com/zhandarbeks/petstore-final-project/user-service/internal/server"
 	"github.com/zhandarbeks/petstore-final-project/user-service/internal/usecase"
 
+	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
 	// A popular library for assertions (optional, but very helpful)
 	// "github.com/stretchr/testify/assert"
 	// "github.com/stretchr/testify/mock"
@@ -28,6 +51,9 @@ type MockUserRepository struct {
 	GetUserByEmailFunc  func(ctx context.Context, email string) (*domain.User, error)
 	UpdateUserFunc      func(ctx context.Context, user *domain.User) (*domain.User, error)
 	DeleteUserFunc      func(ctx context.Context, id string) error
+	AddFavoritePetFunc    func(ctx context.Context, userID, petID string) ([]string, error)
+	RemoveFavoritePetFunc func(ctx context.Context, userID, petID string) ([]string, error)
+	AnonymizeUserFunc     func(ctx context.Context, id string) (*domain.User, error)
 }
 
 // Explicitly state that MockUserRepository implements repository.UserRepository
@@ -71,6 +97,90 @@ func (m *MockUserRepository) DeleteUser(ctx context.Context, id string) error {
 	return errors.New("DeleteUserFunc not implemented in mock")
 }
 
+func (m *MockUserRepository) AddFavoritePet(ctx context.Context, userID, petID string) ([]string, error) {
+	if m.AddFavoritePetFunc != nil {
+		return m.AddFavoritePetFunc(ctx, userID, petID)
+	}
+	return nil, errors.New("AddFavoritePetFunc not implemented in mock")
+}
+
+func (m *MockUserRepository) RemoveFavoritePet(ctx context.Context, userID, petID string) ([]string, error) {
+	if m.RemoveFavoritePetFunc != nil {
+		return m.RemoveFavoritePetFunc(ctx, userID, petID)
+	}
+	return nil, errors.New("RemoveFavoritePetFunc not implemented in mock")
+}
+
+func (m *MockUserRepository) AnonymizeUser(ctx context.Context, id string) (*domain.User, error) {
+	if m.AnonymizeUserFunc != nil {
+		return m.AnonymizeUserFunc(ctx, id)
+	}
+	return nil, errors.New("AnonymizeUserFunc not implemented in mock")
+}
+
+// MockTokenStore is an in-memory implementation of the TokenStore interface.
+type MockTokenStore struct {
+	tokens map[string]string // Token ID -> user ID
+}
+
+var _ repository.TokenStore = (*MockTokenStore)(nil)
+
+func (m *MockTokenStore) SaveRefreshToken(ctx context.Context, tokenID, userID string, expiration time.Duration) error {
+	if m.tokens == nil {
+		m.tokens = make(map[string]string)
+	}
+	m.tokens[tokenID] = userID
+	return nil
+}
+
+func (m *MockTokenStore) GetRefreshTokenUser(ctx context.Context, tokenID string) (string, error) {
+	userID, ok := m.tokens[tokenID]
+	if !ok {
+		return "", repository.ErrRefreshTokenNotFound
+	}
+	return userID, nil
+}
+
+func (m *MockTokenStore) ConsumeRefreshToken(ctx context.Context, tokenID string) (string, error) {
+	userID, ok := m.tokens[tokenID]
+	if !ok {
+		return "", repository.ErrRefreshTokenNotFound
+	}
+	delete(m.tokens, tokenID)
+	return userID, nil
+}
+
+func (m *MockTokenStore) RevokeUserRefreshTokens(ctx context.Context, userID string) error {
+	for tokenID, owner := range m.tokens {
+		if owner == userID {
+			delete(m.tokens, tokenID)
+		}
+	}
+	return nil
+}
+
+// MockTokenBlocklist is an in-memory implementation of the TokenBlocklist interface. Entries
+// expire according to now, which tests can move forward.
+type MockTokenBlocklist struct {
+	now      func() time.Time
+	expiries map[string]time.Time // Token ID -> when its entry expires
+}
+
+var _ repository.TokenBlocklist = (*MockTokenBlocklist)(nil)
+
+func (m *MockTokenBlocklist) BlockToken(ctx context.Context, tokenID string, expiration time.Duration) error {
+	if m.expiries == nil {
+		m.expiries = make(map[string]time.Time)
+	}
+	m.expiries[tokenID] = m.now().Add(expiration)
+	return nil
+}
+
+func (m *MockTokenBlocklist) IsTokenBlocked(ctx context.Context, tokenID string) (bool, error) {
+	expiresAt, ok := m.expiries[tokenID]
+	return ok && m.now().Before(expiresAt), nil
+}
+
 // MockUserCache is a mock implementation of the UserCache interface.
 type MockUserCache struct {
 	GetUserFunc    func(ctx context.Context, id string) (*domain.User, error)
@@ -131,6 +241,7 @@ func TestUserUsecase_RegisterUser_Success(t *testing.T) {
 	jwtSecret := "test-secret-key-for-user-service-tests"
 	tokenExpiry := 15 * time.Minute
 	uc := usecase.NewUserUsecase(mockRepo, mockCache, jwtSecret, tokenExpiry)
+	uc.SetBcryptCost(bcrypt.MinCost) // Keep hashing fast in tests
 
 	// 3. Define Test Inputs
 	ctx := context.Background()
@@ -176,6 +287,192 @@ func TestUserUsecase_RegisterUser_Success(t *testing.T) {
 	}
 }
 
+func TestUserUsecase_TokensCarryRoleClaim(t *testing.T) {
+	const secret = "test-secret"
+	hashed, err := domain.HashPasswordWithCost("password123", bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("could not hash password: %v", err)
+	}
+	var stored *domain.User
+	mockRepo := &MockUserRepository{
+		GetUserByEmailFunc: func(ctx context.Context, email string) (*domain.User, error) {
+			if stored == nil {
+				return nil, errors.New("user not found with this email")
+			}
+			return stored, nil
+		},
+		CreateUserFunc: func(ctx context.Context, user *domain.User) (*domain.User, error) {
+			user.ID = "new-user"
+			return user, nil
+		},
+	}
+	uc := usecase.NewUserUsecase(mockRepo, &MockUserCache{}, secret, 15*time.Minute)
+	uc.SetBcryptCost(bcrypt.MinCost)
+	roleClaim := func(tokenString string) string {
+		t.Helper()
+		claims := jwt.MapClaims{}
+		if _, err := jwt.ParseWithClaims(tokenString, claims, func(*jwt.Token) (interface{}, error) { return []byte(secret), nil }); err != nil {
+			t.Fatalf("could not parse token: %v", err)
+		}
+		role, _ := claims["rol"].(string)
+		return role
+	}
+
+	created, token, err := uc.RegisterUser(context.Background(), "jane", "jane@example.com", "password123", "Jane Doe")
+	if err != nil {
+		t.Fatalf("RegisterUser() error = %v", err)
+	}
+	if created.Role != domain.RoleUser || roleClaim(token) != domain.RoleUser {
+		t.Errorf("RegisterUser() role = %q, claim %q, want %q for new users", created.Role, roleClaim(token), domain.RoleUser)
+	}
+
+	for _, tc := range []struct{ storedRole, wantClaim string }{
+		{domain.RoleAdmin, domain.RoleAdmin},
+		{domain.RoleShelter, domain.RoleShelter},
+		{"", domain.RoleUser}, // Stored before roles existed
+	} {
+		stored = &domain.User{ID: "user1", Email: "jane@example.com", HashedPassword: hashed, Role: tc.storedRole}
+		_, token, err := uc.LoginUser(context.Background(), "jane@example.com", "password123")
+		if err != nil {
+			t.Fatalf("LoginUser() error = %v", err)
+		}
+		if got := roleClaim(token); got != tc.wantClaim {
+			t.Errorf("LoginUser() of user with role %q claim = %q, want %q", tc.storedRole, got, tc.wantClaim)
+		}
+	}
+}
+
+func TestUserUsecase_RegisterUser_SigningAlgorithms(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey() error = %v", err)
+	}
+	tests := []struct {
+		name      string
+		key       usecase.SigningKey
+		verifyKey interface{}
+	}{
+		{"HS256", usecase.HMACSigningKey("test-secret-key-for-user-service-tests"), []byte("test-secret-key-for-user-service-tests")},
+		{"RS256", usecase.RSASigningKey(privateKey), &privateKey.PublicKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &MockUserRepository{
+				GetUserByEmailFunc: func(ctx context.Context, email string) (*domain.User, error) {
+					return nil, errors.New("user not found with this email")
+				},
+				CreateUserFunc: func(ctx context.Context, user *domain.User) (*domain.User, error) {
+					user.ID = "user123"
+					return user, nil
+				},
+			}
+			uc := usecase.NewUserUsecaseWithSigningKey(mockRepo, &MockUserCache{}, tt.key, 15*time.Minute)
+			uc.SetBcryptCost(bcrypt.MinCost) // Keep hashing fast in tests
+
+			_, tokenString, err := uc.RegisterUser(context.Background(), "jane", "jane@example.com", "password123", "Jane Doe")
+			if err != nil {
+				t.Fatalf("RegisterUser() error = %v", err)
+			}
+			token, err := jwt.Parse(tokenString, func(*jwt.Token) (interface{}, error) { return tt.verifyKey, nil },
+				jwt.WithValidMethods([]string{tt.name}))
+			if err != nil {
+				t.Fatalf("token does not validate with the %s verification key: %v", tt.name, err)
+			}
+			if sub, _ := token.Claims.GetSubject(); sub != "user123" {
+				t.Errorf("token subject = %q, want %q", sub, "user123")
+			}
+		})
+	}
+}
+
+func TestJWKSHandler_PublishesCurrentAndPreviousKeys(t *testing.T) {
+	currentKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey() error = %v", err)
+	}
+	previousKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey() error = %v", err)
+	}
+	signingKey := usecase.RSASigningKey(currentKey)
+
+	rec := httptest.NewRecorder()
+	server.NewJWKSHandler(signingKey, &previousKey.PublicKey).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/jwks.json", nil))
+	var set server.JWKSet
+	if err := json.Unmarshal(rec.Body.Bytes(), &set); err != nil {
+		t.Fatalf("JWKS body is not JSON: %v. Body: %s", err, rec.Body.String())
+	}
+	if len(set.Keys) != 2 || set.Keys[0].KeyID != signingKey.KeyID || set.Keys[1].KeyID != usecase.RSAKeyID(&previousKey.PublicKey) {
+		t.Fatalf("JWKS keys = %+v, want the current key then the previous one", set.Keys)
+	}
+
+	// Tokens name the current key, so validators can find it in the JWKS.
+	mockRepo := &MockUserRepository{
+		GetUserByEmailFunc: func(ctx context.Context, email string) (*domain.User, error) {
+			return nil, errors.New("user not found with this email")
+		},
+		CreateUserFunc: func(ctx context.Context, user *domain.User) (*domain.User, error) {
+			user.ID = "user123"
+			return user, nil
+		},
+	}
+	uc := usecase.NewUserUsecaseWithSigningKey(mockRepo, &MockUserCache{}, signingKey, 15*time.Minute)
+	uc.SetBcryptCost(bcrypt.MinCost) // Keep hashing fast in tests
+	_, tokenString, err := uc.RegisterUser(context.Background(), "jane", "jane@example.com", "password123", "Jane Doe")
+	if err != nil {
+		t.Fatalf("RegisterUser() error = %v", err)
+	}
+	token, _, err := jwt.NewParser().ParseUnverified(tokenString, jwt.MapClaims{})
+	if err != nil {
+		t.Fatalf("ParseUnverified() error = %v", err)
+	}
+	if kid := token.Header["kid"]; kid != set.Keys[0].KeyID {
+		t.Errorf("token kid = %v, want %q", kid, set.Keys[0].KeyID)
+	}
+}
+
+func TestUserUsecase_RegisterUser_UsesConfiguredBcryptCost(t *testing.T) {
+	tests := []struct {
+		name     string
+		cost     int
+		wantCost int
+	}{
+		{"configured", bcrypt.MinCost + 1, bcrypt.MinCost + 1},
+		{"below minimum", 1, bcrypt.MinCost},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var storedHash string
+			mockRepo := &MockUserRepository{
+				GetUserByEmailFunc: func(ctx context.Context, email string) (*domain.User, error) {
+					return nil, errors.New("user not found with this email")
+				},
+				CreateUserFunc: func(ctx context.Context, user *domain.User) (*domain.User, error) {
+					storedHash = user.HashedPassword
+					user.ID = "user-1"
+					return user, nil
+				},
+			}
+			uc := usecase.NewUserUsecase(mockRepo, &MockUserCache{}, "test-secret", 15*time.Minute)
+			uc.SetBcryptCost(tt.cost)
+
+			if _, _, err := uc.RegisterUser(context.Background(), "jane", "jane@example.com", "password123", "Jane Doe"); err != nil {
+				t.Fatalf("RegisterUser() error = %v", err)
+			}
+			cost, err := bcrypt.Cost([]byte(storedHash))
+			if err != nil {
+				t.Fatalf("bcrypt.Cost() error = %v", err)
+			}
+			if cost != tt.wantCost {
+				t.Errorf("stored hash cost = %d, want %d", cost, tt.wantCost)
+			}
+			if !domain.CheckPasswordHash("password123", storedHash) {
+				t.Error("stored hash does not match the password")
+			}
+		})
+	}
+}
+
 func TestUserUsecase_RegisterUser_EmailExists(t *testing.T) {
 	mockRepo := &MockUserRepository{}
 	mockCache := &MockUserCache{}
@@ -199,6 +496,638 @@ func TestUserUsecase_RegisterUser_EmailExists(t *testing.T) {
 	}
 }
 
+func TestUserUsecase_RegisterUser_TokenGenerationFails(t *testing.T) {
+	mockRepo := &MockUserRepository{
+		GetUserByEmailFunc: func(ctx context.Context, email string) (*domain.User, error) {
+			return nil, errors.New("user not found with this email")
+		},
+		CreateUserFunc: func(ctx context.Context, user *domain.User) (*domain.User, error) {
+			user.ID = "newUserID"
+			return user, nil
+		},
+	}
+	// An RS256 method with an HMAC secret cannot sign, so token generation fails after the user is stored.
+	brokenKey := usecase.SigningKey{Method: jwt.SigningMethodRS256, Key: []byte("not-an-rsa-key")}
+	uc := usecase.NewUserUsecaseWithSigningKey(mockRepo, &MockUserCache{}, brokenKey, 15*time.Minute)
+	uc.SetBcryptCost(bcrypt.MinCost) // Keep hashing fast in tests
+
+	createdUser, token, err := uc.RegisterUser(context.Background(), "jane", "jane@example.com", "password123", "Jane Doe")
+	if !errors.Is(err, usecase.ErrTokenGenerationFailed) {
+		t.Fatalf("RegisterUser() error = %v, want %v", err, usecase.ErrTokenGenerationFailed)
+	}
+	var tokenErr *usecase.TokenGenerationError
+	if !errors.As(err, &tokenErr) {
+		t.Fatalf("RegisterUser() error type = %T, want *usecase.TokenGenerationError", err)
+	}
+	if tokenErr.User == nil || tokenErr.User.ID != "newUserID" {
+		t.Errorf("TokenGenerationError.User = %+v, want the created user", tokenErr.User)
+	}
+	if createdUser != tokenErr.User || token != "" {
+		t.Errorf("RegisterUser() = (%v, %q), want the created user and no token", createdUser, token)
+	}
+
+	// The handler reports the registration as successful and asks the client to log in.
+	resp, err := handler.NewUserHandler(uc).RegisterUser(context.Background(), &pb.RegisterUserRequest{
+		Username: "jane", Email: "jane@example.com", Password: "password123", FullName: "Jane Doe",
+	})
+	if err != nil {
+		t.Fatalf("handler RegisterUser() error = %v", err)
+	}
+	if !resp.GetLoginRequired() || resp.GetUser().GetId() != "newUserID" {
+		t.Errorf("handler RegisterUser() = %v, want the new user with login_required", resp)
+	}
+}
+
+func TestUserUsecase_AddFavoritePet_InvalidatesCache(t *testing.T) {
+	mockRepo := &MockUserRepository{
+		AddFavoritePetFunc: func(ctx context.Context, userID, petID string) ([]string, error) {
+			return []string{"pet1", petID}, nil
+		},
+	}
+	cacheInvalidated := false
+	mockCache := &MockUserCache{
+		DeleteUserFunc: func(ctx context.Context, id string) error {
+			cacheInvalidated = id == "user1"
+			return nil
+		},
+	}
+	uc := usecase.NewUserUsecase(mockRepo, mockCache, "test-secret", 15*time.Minute)
+
+	favorites, err := uc.AddFavoritePet(context.Background(), "user1", "pet2")
+	if err != nil {
+		t.Fatalf("AddFavoritePet() error = %v", err)
+	}
+	if len(favorites) != 2 || favorites[1] != "pet2" {
+		t.Errorf("AddFavoritePet() = %v, want [pet1 pet2]", favorites)
+	}
+	if !cacheInvalidated {
+		t.Error("AddFavoritePet() did not invalidate the cached user")
+	}
+}
+
+func TestUserUsecase_GetUserByID_CacheHit(t *testing.T) {
+	mockRepo := &MockUserRepository{
+		GetUserByIDFunc: func(ctx context.Context, id string) (*domain.User, error) {
+			t.Error("GetUserByID() should not hit the repository when the user is cached")
+			return nil, errors.New("unexpected repository call")
+		},
+	}
+	mockCache := &MockUserCache{
+		GetUserFunc: func(ctx context.Context, id string) (*domain.User, error) {
+			return &domain.User{ID: id, Username: "cached"}, nil
+		},
+	}
+	uc := usecase.NewUserUsecase(mockRepo, mockCache, "test-secret", 15*time.Minute)
+
+	user, err := uc.GetUserByID(context.Background(), "user1")
+	if err != nil {
+		t.Fatalf("GetUserByID() error = %v", err)
+	}
+	if user.Username != "cached" {
+		t.Errorf("GetUserByID() Username = %s, want cached", user.Username)
+	}
+}
+
+func TestUserUsecase_GetUserByID_CacheMissThenSet(t *testing.T) {
+	mockRepo := &MockUserRepository{
+		GetUserByIDFunc: func(ctx context.Context, id string) (*domain.User, error) {
+			return &domain.User{ID: id, Username: "fromdb"}, nil
+		},
+	}
+	var cachedUser *domain.User
+	mockCache := &MockUserCache{
+		GetUserFunc: func(ctx context.Context, id string) (*domain.User, error) {
+			return nil, repository.ErrCacheMiss
+		},
+		SetUserFunc: func(ctx context.Context, id string, user *domain.User, expiration time.Duration) error {
+			cachedUser = user
+			return nil
+		},
+	}
+	uc := usecase.NewUserUsecase(mockRepo, mockCache, "test-secret", 15*time.Minute)
+
+	user, err := uc.GetUserByID(context.Background(), "user1")
+	if err != nil {
+		t.Fatalf("GetUserByID() error = %v", err)
+	}
+	if user.Username != "fromdb" {
+		t.Errorf("GetUserByID() Username = %s, want fromdb", user.Username)
+	}
+	if cachedUser == nil || cachedUser.ID != "user1" {
+		t.Errorf("GetUserByID() cached %+v, want user1 from the repository", cachedUser)
+	}
+
+	// A missing user is not cached.
+	cachedUser = nil
+	mockRepo.GetUserByIDFunc = func(ctx context.Context, id string) (*domain.User, error) {
+		return nil, errors.New("user not found")
+	}
+	if _, err := uc.GetUserByID(context.Background(), "missing"); err == nil || err.Error() != "user not found" {
+		t.Errorf("GetUserByID() error = %v, want user not found", err)
+	}
+	if cachedUser != nil {
+		t.Error("GetUserByID() should not cache a missing user")
+	}
+}
+
+func TestUserUsecase_UpdateUserProfile_InvalidatesCache(t *testing.T) {
+	mockRepo := &MockUserRepository{
+		GetUserByIDFunc: func(ctx context.Context, id string) (*domain.User, error) {
+			return &domain.User{ID: id, Username: "old", FullName: "Old Name"}, nil
+		},
+		UpdateUserFunc: func(ctx context.Context, user *domain.User) (*domain.User, error) {
+			return user, nil
+		},
+	}
+	invalidated := ""
+	mockCache := &MockUserCache{
+		DeleteUserFunc: func(ctx context.Context, id string) error {
+			invalidated = id
+			return nil
+		},
+	}
+	uc := usecase.NewUserUsecase(mockRepo, mockCache, "test-secret", 15*time.Minute)
+
+	newName := "New Name"
+	user, err := uc.UpdateUserProfile(context.Background(), "user1", nil, &newName, nil)
+	if err != nil {
+		t.Fatalf("UpdateUserProfile() error = %v", err)
+	}
+	if user.FullName != newName {
+		t.Errorf("UpdateUserProfile() FullName = %s, want %s", user.FullName, newName)
+	}
+	if invalidated != "user1" {
+		t.Errorf("UpdateUserProfile() invalidated cache for %q, want user1", invalidated)
+	}
+}
+
+func TestUserUsecase_DeleteUser_InvalidatesCache(t *testing.T) {
+	mockRepo := &MockUserRepository{
+		DeleteUserFunc: func(ctx context.Context, id string) error { return nil },
+	}
+	invalidated := ""
+	mockCache := &MockUserCache{
+		DeleteUserFunc: func(ctx context.Context, id string) error {
+			invalidated = id
+			return errors.New("redis unavailable") // Cache errors are logged, not returned
+		},
+	}
+	uc := usecase.NewUserUsecase(mockRepo, mockCache, "test-secret", 15*time.Minute)
+
+	if err := uc.DeleteUser(context.Background(), "user1"); err != nil {
+		t.Fatalf("DeleteUser() error = %v, want nil even when the cache delete fails", err)
+	}
+	if invalidated != "user1" {
+		t.Errorf("DeleteUser() invalidated cache for %q, want user1", invalidated)
+	}
+
+	// Nothing is invalidated when the repository delete fails.
+	invalidated = ""
+	mockRepo.DeleteUserFunc = func(ctx context.Context, id string) error { return errors.New("user not found for deletion") }
+	if err := uc.DeleteUser(context.Background(), "user2"); err == nil {
+		t.Error("DeleteUser() error = nil, want the repository error")
+	}
+	if invalidated != "" {
+		t.Errorf("DeleteUser() invalidated cache for %q after a failed delete", invalidated)
+	}
+}
+
+func TestUserUsecase_UpdateUserProfile_NoFieldsToUpdate(t *testing.T) {
+	uc := usecase.NewUserUsecase(&MockUserRepository{}, &MockUserCache{}, "test-secret", 15*time.Minute)
+
+	if _, err := uc.UpdateUserProfile(context.Background(), "user1", nil, nil, nil); !errors.Is(err, usecase.ErrNoFieldsToUpdate) {
+		t.Errorf("UpdateUserProfile() with no fields error = %v, want ErrNoFieldsToUpdate", err)
+	}
+
+	h := handler.NewUserHandler(uc)
+	_, err := h.UpdateUserProfile(context.Background(), &pb.UpdateUserProfileRequest{UserId: "user1"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("UpdateUserProfile() with an empty request code = %v, want %v", status.Code(err), codes.InvalidArgument)
+	}
+}
+
+func TestInternalError_MapsContextErrors(t *testing.T) {
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	expiredCtx, cancelExpired := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancelExpired()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  error
+		want codes.Code
+	}{
+		{"cancelled error", context.Background(), context.Canceled, codes.Canceled},
+		{"wrapped cancelled error", context.Background(), fmt.Errorf("could not query: %w", context.Canceled), codes.Canceled},
+		{"cancelled context", cancelledCtx, errors.New("connection reset"), codes.Canceled},
+		{"deadline error", context.Background(), context.DeadlineExceeded, codes.DeadlineExceeded},
+		{"expired context", expiredCtx, errors.New("server selection timeout"), codes.DeadlineExceeded},
+		{"other error", context.Background(), errors.New("boom"), codes.Internal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := handler.InternalError(tt.ctx, tt.err, "Failed")
+			if got := status.Code(err); got != tt.want {
+				t.Errorf("InternalError() code = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoggingInterceptor_RedactsPasswords(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	mockRepo := &MockUserRepository{
+		GetUserByEmailFunc: func(ctx context.Context, email string) (*domain.User, error) {
+			return nil, errors.New("user not found with this email")
+		},
+		CreateUserFunc: func(ctx context.Context, user *domain.User) (*domain.User, error) {
+			user.ID = "user123"
+			return user, nil
+		},
+	}
+	uc := usecase.NewUserUsecase(mockRepo, &MockUserCache{}, "test-secret-key-for-user-service-tests", 15*time.Minute)
+	uc.SetBcryptCost(bcrypt.MinCost) // Keep hashing fast in tests
+	h := handler.NewUserHandler(uc)
+	interceptor := server.NewLoggingInterceptor()
+
+	const secret = "hunter2-very-secret"
+	calls := []struct {
+		method string
+		req    interface{}
+		call   grpc.UnaryHandler
+	}{
+		{
+			"/user.UserService/RegisterUser",
+			&pb.RegisterUserRequest{Username: "jane", Email: "jane@example.com", Password: secret, FullName: "Jane Doe"},
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				return h.RegisterUser(ctx, req.(*pb.RegisterUserRequest))
+			},
+		},
+		{
+			"/user.UserService/LoginUser",
+			&pb.LoginUserRequest{Email: "jane@example.com", Password: secret},
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				return h.LoginUser(ctx, req.(*pb.LoginUserRequest))
+			},
+		},
+	}
+	for _, c := range calls {
+		_, _ = interceptor(context.Background(), c.req, &grpc.UnaryServerInfo{FullMethod: c.method}, c.call)
+	}
+
+	output := logs.String()
+	if strings.Contains(output, secret) {
+		t.Errorf("log output contains the raw password:\n%s", output)
+	}
+	if strings.Count(output, "[REDACTED]") != len(calls) || !strings.Contains(output, "jane@example.com") {
+		t.Errorf("log output should contain both payloads with the password redacted, got:\n%s", output)
+	}
+	if got := calls[1].req.(*pb.LoginUserRequest).GetPassword(); got != secret {
+		t.Errorf("redaction modified the request: password = %q", got)
+	}
+}
+
+func TestMongoUserRepository_UpdateUser_StaleVersionRejected(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("stale version", func(mt *mtest.T) {
+		repo := repository.NewMongoDBUserRepositoryFromCollection(mt.Coll)
+		// The versioned update matches nothing, but the user still exists: someone else updated it first.
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}, bson.E{Key: "nModified", Value: 0}),
+			mtest.CreateCursorResponse(0, mt.DB.Name()+"."+mt.Coll.Name(), mtest.FirstBatch, bson.D{{Key: "n", Value: int32(1)}}),
+		)
+
+		_, err := repo.UpdateUser(context.Background(), &domain.User{ID: "user1", Username: "new-name", Version: 1})
+		if !errors.Is(err, repository.ErrConcurrentModification) {
+			t.Errorf("UpdateUser() error = %v, want ErrConcurrentModification", err)
+		}
+	})
+
+	mt.Run("current version", func(mt *mtest.T) {
+		repo := repository.NewMongoDBUserRepositoryFromCollection(mt.Coll)
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+
+		updated, err := repo.UpdateUser(context.Background(), &domain.User{ID: "user1", Username: "new-name", Version: 2})
+		if err != nil {
+			t.Fatalf("UpdateUser() error = %v", err)
+		}
+		if updated.Version != 3 {
+			t.Errorf("UpdateUser() version = %d, want 3", updated.Version)
+		}
+	})
+}
+
+func TestMongoUserRepository_CreateUser_DuplicateField(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	duplicateKeyResponse := func(index string, keyPattern bson.D) bson.D {
+		writeErr := bson.D{
+			{Key: "index", Value: 0},
+			{Key: "code", Value: 11000},
+			{Key: "errmsg", Value: "E11000 duplicate key error collection: test.users index: " + index + " dup key: { }"},
+		}
+		if keyPattern != nil {
+			writeErr = append(writeErr, bson.E{Key: "keyPattern", Value: keyPattern})
+		}
+		return mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}, bson.E{Key: "writeErrors", Value: bson.A{writeErr}})
+	}
+
+	tests := []struct {
+		name      string
+		response  bson.D
+		wantField string
+	}{
+		{"email", duplicateKeyResponse("email_1", bson.D{{Key: "email", Value: 1}}), repository.DuplicateFieldEmail},
+		{"username", duplicateKeyResponse("username_1", bson.D{{Key: "username", Value: 1}}), repository.DuplicateFieldUsername},
+		{"username without key pattern", duplicateKeyResponse("username_1", nil), repository.DuplicateFieldUsername},
+		{"unknown index", duplicateKeyResponse("other_1", nil), ""},
+	}
+	for _, tt := range tests {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			repo := repository.NewMongoDBUserRepositoryFromCollection(mt.Coll)
+			mt.AddMockResponses(tt.response)
+
+			_, err := repo.CreateUser(context.Background(), &domain.User{Username: "jane", Email: "jane@example.com"})
+			var dupErr *repository.DuplicateUserError
+			if !errors.As(err, &dupErr) {
+				t.Fatalf("CreateUser() error = %v, want *repository.DuplicateUserError", err)
+			}
+			if dupErr.Field != tt.wantField {
+				t.Errorf("CreateUser() duplicate field = %q, want %q", dupErr.Field, tt.wantField)
+			}
+			if !errors.Is(err, repository.ErrUserAlreadyExists) {
+				t.Errorf("CreateUser() error = %v, want it to match ErrUserAlreadyExists", err)
+			}
+		})
+	}
+}
+
+func TestUserHandler_RegisterUser_DuplicateUsername(t *testing.T) {
+	mockRepo := &MockUserRepository{
+		GetUserByEmailFunc: func(ctx context.Context, email string) (*domain.User, error) {
+			return nil, errors.New("user not found with this email")
+		},
+		CreateUserFunc: func(ctx context.Context, user *domain.User) (*domain.User, error) {
+			return nil, &repository.DuplicateUserError{Field: repository.DuplicateFieldUsername}
+		},
+	}
+	uc := usecase.NewUserUsecase(mockRepo, &MockUserCache{}, "test-secret", 15*time.Minute)
+	uc.SetBcryptCost(bcrypt.MinCost)
+
+	_, err := handler.NewUserHandler(uc).RegisterUser(context.Background(), &pb.RegisterUserRequest{
+		Username: "jane", Email: "jane@example.com", Password: "password123", FullName: "Jane Doe",
+	})
+	st, _ := status.FromError(err)
+	if st.Code() != codes.AlreadyExists {
+		t.Fatalf("RegisterUser() code = %v, want %v", st.Code(), codes.AlreadyExists)
+	}
+	var field string
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok && len(badRequest.GetFieldViolations()) > 0 {
+			field = badRequest.GetFieldViolations()[0].GetField()
+		}
+	}
+	if field != repository.DuplicateFieldUsername {
+		t.Errorf("RegisterUser() violated field = %q, want %q", field, repository.DuplicateFieldUsername)
+	}
+}
+
+func TestUserHandler_RefreshToken_RotatesAndRejectsReuse(t *testing.T) {
+	hashed, err := domain.HashPasswordWithCost("password123", bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("could not hash password: %v", err)
+	}
+	user := &domain.User{ID: "user1", Email: "jane@example.com", Username: "jane", HashedPassword: hashed}
+	mockRepo := &MockUserRepository{
+		GetUserByEmailFunc: func(ctx context.Context, email string) (*domain.User, error) { return user, nil },
+		GetUserByIDFunc:    func(ctx context.Context, id string) (*domain.User, error) { return user, nil },
+		DeleteUserFunc:     func(ctx context.Context, id string) error { return nil },
+	}
+	mockCache := &MockUserCache{
+		GetUserFunc:    func(ctx context.Context, id string) (*domain.User, error) { return nil, repository.ErrCacheMiss },
+		SetUserFunc:    func(ctx context.Context, id string, u *domain.User, expiration time.Duration) error { return nil },
+		DeleteUserFunc: func(ctx context.Context, id string) error { return nil },
+	}
+	uc := usecase.NewUserUsecase(mockRepo, mockCache, "test-secret", 15*time.Minute)
+	uc.SetRefreshTokenStore(&MockTokenStore{}, time.Hour)
+	h := handler.NewUserHandler(uc)
+	ctx := context.Background()
+
+	login, err := h.LoginUser(ctx, &pb.LoginUserRequest{Email: "jane@example.com", Password: "password123"})
+	if err != nil {
+		t.Fatalf("LoginUser() error = %v", err)
+	}
+	first := login.GetRefreshToken()
+	if first == "" {
+		t.Fatal("LoginUser() refresh_token is empty")
+	}
+
+	refreshed, err := h.RefreshToken(ctx, &pb.RefreshTokenRequest{RefreshToken: first})
+	if err != nil {
+		t.Fatalf("RefreshToken() error = %v", err)
+	}
+	second := refreshed.GetRefreshToken()
+	if refreshed.GetAccessToken() == "" || second == "" || second == first {
+		t.Fatalf("RefreshToken() = %v, want a new access token and a rotated refresh token", refreshed)
+	}
+
+	// The rotated-out token cannot be used again.
+	if _, err := h.RefreshToken(ctx, &pb.RefreshTokenRequest{RefreshToken: first}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("RefreshToken() reusing a rotated token code = %v, want %v", status.Code(err), codes.Unauthenticated)
+	}
+	third, err := h.RefreshToken(ctx, &pb.RefreshTokenRequest{RefreshToken: second})
+	if err != nil {
+		t.Fatalf("RefreshToken() with the rotated token error = %v", err)
+	}
+
+	// Deleting the user revokes their refresh tokens.
+	if err := uc.DeleteUser(ctx, "user1"); err != nil {
+		t.Fatalf("DeleteUser() error = %v", err)
+	}
+	if _, err := h.RefreshToken(ctx, &pb.RefreshTokenRequest{RefreshToken: third.GetRefreshToken()}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("RefreshToken() after DeleteUser code = %v, want %v", status.Code(err), codes.Unauthenticated)
+	}
+}
+
+func TestUserUsecase_RefreshToken_TransientErrorKeepsToken(t *testing.T) {
+	user := &domain.User{ID: "user1", Email: "jane@example.com", Username: "jane"}
+	lookupErr := errors.New("server selection timeout")
+	mockRepo := &MockUserRepository{
+		GetUserByIDFunc: func(ctx context.Context, id string) (*domain.User, error) {
+			if lookupErr != nil {
+				return nil, lookupErr
+			}
+			return user, nil
+		},
+	}
+	mockCache := &MockUserCache{
+		GetUserFunc: func(ctx context.Context, id string) (*domain.User, error) { return nil, repository.ErrCacheMiss },
+		SetUserFunc: func(ctx context.Context, id string, u *domain.User, expiration time.Duration) error { return nil },
+	}
+	uc := usecase.NewUserUsecase(mockRepo, mockCache, "test-secret", 15*time.Minute)
+	uc.SetRefreshTokenStore(&MockTokenStore{}, time.Hour)
+	ctx := context.Background()
+
+	refreshToken, err := uc.IssueRefreshToken(ctx, "user1")
+	if err != nil {
+		t.Fatalf("IssueRefreshToken() error = %v", err)
+	}
+	if _, _, err := uc.RefreshToken(ctx, refreshToken); err == nil || errors.Is(err, usecase.ErrInvalidRefreshToken) {
+		t.Fatalf("RefreshToken() while the user lookup fails error = %v, want the lookup error", err)
+	}
+
+	// The token survived the failed attempt.
+	lookupErr = nil
+	accessToken, rotated, err := uc.RefreshToken(ctx, refreshToken)
+	if err != nil || accessToken == "" || rotated == "" {
+		t.Fatalf("RefreshToken() retry = %q, %q, %v, want new tokens", accessToken, rotated, err)
+	}
+
+	// Anonymized users cannot refresh, even if revoking their tokens failed.
+	user.Username, user.Email = domain.AnonymizedIdentity(user.ID)
+	if _, _, err := uc.RefreshToken(ctx, rotated); !errors.Is(err, usecase.ErrInvalidRefreshToken) {
+		t.Errorf("RefreshToken() of an anonymized user error = %v, want ErrInvalidRefreshToken", err)
+	}
+}
+
+func TestUserHandler_LogoutUser_RevokesTokenUntilItExpires(t *testing.T) {
+	hashed, err := domain.HashPasswordWithCost("password123", bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("could not hash password: %v", err)
+	}
+	user := &domain.User{ID: "user1", Email: "jane@example.com", Username: "jane", HashedPassword: hashed}
+	mockRepo := &MockUserRepository{
+		GetUserByEmailFunc: func(ctx context.Context, email string) (*domain.User, error) { return user, nil },
+	}
+	now := time.Now()
+	blocklist := &MockTokenBlocklist{now: func() time.Time { return now }}
+	uc := usecase.NewUserUsecase(mockRepo, &MockUserCache{}, "test-secret", 15*time.Minute)
+	uc.SetTokenBlocklist(blocklist)
+	uc.SetRefreshTokenStore(&MockTokenStore{}, time.Hour)
+	h := handler.NewUserHandler(uc)
+	ctx := context.Background()
+
+	login, err := h.LoginUser(ctx, &pb.LoginUserRequest{Email: "jane@example.com", Password: "password123"})
+	if err != nil {
+		t.Fatalf("LoginUser() error = %v", err)
+	}
+	claims, err := uc.ValidateAccessToken(ctx, login.GetAccessToken())
+	if err != nil {
+		t.Fatalf("ValidateAccessToken() before logout error = %v", err)
+	}
+	if claims.TokenID == "" {
+		t.Fatal("access token has no jti claim")
+	}
+
+	if _, err := h.LogoutUser(ctx, &pb.LogoutRequest{AccessToken: login.GetAccessToken(), RefreshToken: login.GetRefreshToken()}); err != nil {
+		t.Fatalf("LogoutUser() error = %v", err)
+	}
+	if _, err := uc.ValidateAccessToken(ctx, login.GetAccessToken()); !errors.Is(err, usecase.ErrInvalidAccessToken) {
+		t.Errorf("ValidateAccessToken() after logout error = %v, want ErrInvalidAccessToken", err)
+	}
+	if _, err := h.LogoutUser(ctx, &pb.LogoutRequest{AccessToken: login.GetAccessToken()}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("LogoutUser() with a revoked token code = %v, want %v", status.Code(err), codes.Unauthenticated)
+	}
+	if _, err := h.RefreshToken(ctx, &pb.RefreshTokenRequest{RefreshToken: login.GetRefreshToken()}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("RefreshToken() after logout code = %v, want %v", status.Code(err), codes.Unauthenticated)
+	}
+
+	// The entry lives exactly as long as the token still would have.
+	if got := blocklist.expiries[claims.TokenID].Sub(claims.ExpiresAt); got < -time.Second || got > time.Second {
+		t.Errorf("blocklist entry expires %v after the token, want about the same time", got)
+	}
+	now = claims.ExpiresAt.Add(time.Second)
+	if blocked, _ := blocklist.IsTokenBlocked(ctx, claims.TokenID); blocked {
+		t.Error("blocklist entry is still present after the token expired")
+	}
+}
+
+func TestUserUsecase_LogoutUser_AcceptsTokensTheGatewayAccepts(t *testing.T) {
+	currentKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey() error = %v", err)
+	}
+	previousKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey() error = %v", err)
+	}
+	unknownKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey() error = %v", err)
+	}
+	sign := func(key *rsa.PrivateKey, expiresAt time.Time, tokenID string) string {
+		t.Helper()
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"sub": "user1",
+			"jti": tokenID,
+			"iss": "petstore-user-service",
+			"aud": "petstore-clients",
+			"iat": time.Now().Add(-time.Hour).Unix(),
+			"exp": expiresAt.Unix(),
+		})
+		token.Header["kid"] = usecase.RSAKeyID(&key.PublicKey)
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatalf("could not sign token: %v", err)
+		}
+		return signed
+	}
+
+	uc := usecase.NewUserUsecaseWithSigningKey(&MockUserRepository{}, &MockUserCache{}, usecase.RSASigningKey(currentKey), 15*time.Minute)
+	uc.SetTokenBlocklist(&MockTokenBlocklist{now: time.Now})
+	uc.SetPreviousVerificationKeys(&previousKey.PublicKey)
+	uc.SetTokenLeeway(30 * time.Second)
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		token string
+		want  error
+	}{
+		{"current key", sign(currentKey, time.Now().Add(time.Hour), "token-1"), nil},
+		{"previous key", sign(previousKey, time.Now().Add(time.Hour), "token-2"), nil},
+		{"expired within leeway", sign(currentKey, time.Now().Add(-10*time.Second), "token-3"), nil},
+		{"expired beyond leeway", sign(currentKey, time.Now().Add(-time.Minute), "token-4"), usecase.ErrInvalidAccessToken},
+		{"unknown key", sign(unknownKey, time.Now().Add(time.Hour), "token-5"), usecase.ErrInvalidAccessToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := uc.LogoutUser(ctx, tt.token, ""); !errors.Is(err, tt.want) {
+				t.Errorf("LogoutUser() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedisTokenBlocklist_EntryExpires(t *testing.T) {
+	addr, prefix := testutil.RedisAddr(t)
+	ctx := context.Background()
+	blocklist, err := repository.NewRedisTokenBlocklist(ctx, addr, "", 0, prefix)
+	if err != nil {
+		t.Fatalf("NewRedisTokenBlocklist() error = %v", err)
+	}
+
+	if err := blocklist.BlockToken(ctx, "token-1", 200*time.Millisecond); err != nil {
+		t.Fatalf("BlockToken() error = %v", err)
+	}
+	if blocked, err := blocklist.IsTokenBlocked(ctx, "token-1"); err != nil || !blocked {
+		t.Fatalf("IsTokenBlocked() right after BlockToken = %v, %v, want true", blocked, err)
+	}
+	if blocked, _ := blocklist.IsTokenBlocked(ctx, "token-2"); blocked {
+		t.Error("IsTokenBlocked() for a token that was not blocked = true")
+	}
+
+	time.Sleep(300 * time.Millisecond)
+	if blocked, err := blocklist.IsTokenBlocked(ctx, "token-1"); err != nil || blocked {
+		t.Errorf("IsTokenBlocked() after expiry = %v, %v, want false", blocked, err)
+	}
+}
+
 // TODO: Add more tests for other usecase methods:
 // - LoginUser_Success
 // - LoginUser_UserNotFound